@@ -1,10 +1,8 @@
-// Code generated by ADL CLI v0.55.0. DO NOT EDIT.
-// This file was automatically generated from an ADL (Agent Definition Language) specification.
-// Manual changes to this file may be overwritten during regeneration.
-
 package config
 
 import (
+	"time"
+
 	serverConfig "github.com/inference-gateway/adk/server/config"
 )
 
@@ -14,13 +12,137 @@ type Config struct {
 	A2A serverConfig.Config `env:",prefix=A2A_"`
 
 	// Custom configuration sections
-	Grafana GrafanaConfig `env:",prefix=GRAFANA_"`
+	Alerting    AlertingConfig    `env:",prefix=ALERTING_"`
+	Authz       AuthzConfig       `env:",prefix=AUTHZ_"`
+	Circuit     CircuitConfig     `env:",prefix=CIRCUIT_"`
+	Environment EnvironmentConfig `env:",prefix=ENVIRONMENT_"`
+	Grafana     GrafanaConfig     `env:",prefix=GRAFANA_"`
+	HTTP        HTTPConfig        `env:",prefix=HTTP_"`
+	Inventory   InventoryConfig   `env:",prefix=INVENTORY_"`
+	Prometheus  PrometheusConfig  `env:",prefix=PROMETHEUS_"`
+	Session     SessionConfig     `env:",prefix=SESSION_"`
+	Timeouts    TimeoutsConfig    `env:",prefix=TIMEOUTS_"`
+}
+
+// AlertingConfig represents the alerting configuration
+type AlertingConfig struct {
+	Labels            map[string]string `env:"LABELS"`
+	MaintenanceWindow string            `env:"MAINTENANCE_WINDOW"`
+	RoutingTags       []string          `env:"ROUTING_TAGS,default=team,service,severity"`
+}
+
+// AuthzConfig represents the authz configuration
+type AuthzConfig struct {
+	PolicyFile string `env:"POLICY_FILE"`
+}
+
+// CircuitConfig represents the circuit configuration
+type CircuitConfig struct {
+	Cooldown         time.Duration `env:"COOLDOWN,default=30s"`
+	FailureThreshold int           `env:"FAILURE_THRESHOLD,default=5"`
+}
+
+// EnvironmentConfig represents the environment configuration
+type EnvironmentConfig struct {
+	Labels     map[string]string `env:"LABELS"`
+	ScopeLabel string            `env:"SCOPE_LABEL"`
 }
 
 // GrafanaConfig represents the grafana configuration
 type GrafanaConfig struct {
-	APIKey        string `env:"API_KEY"`
-	DeployEnabled bool   `env:"DEPLOY_ENABLED,default=false"`
-	OrgID         string `env:"ORG_ID"`
-	URL           string `env:"URL"`
+	APIKey                  string                `env:"API_KEY"`
+	CacheTTL                time.Duration         `env:"CACHE_TTL,default=30s"`
+	Cloud                   GrafanaCloudConfig    `env:",prefix=CLOUD_"`
+	Defaults                GrafanaDefaultsConfig `env:",prefix=DEFAULT_"`
+	DeployEnabled           bool                  `env:"DEPLOY_ENABLED,default=false"`
+	GzipRequests            bool                  `env:"GZIP_REQUESTS,default=false"`
+	ImportDir               string                `env:"IMPORT_DIR"`
+	Naming                  GrafanaNamingConfig   `env:",prefix=NAMING_"`
+	OAuth                   GrafanaOAuthConfig    `env:",prefix=OAUTH_"`
+	OrgID                   string                `env:"ORG_ID"`
+	PublicDashboardsEnabled bool                  `env:"PUBLIC_DASHBOARDS_ENABLED,default=false"`
+	SearchMaxResults        int                   `env:"SEARCH_MAX_RESULTS,default=10000"`
+	Tags                    GrafanaTagsConfig     `env:",prefix=TAGS_"`
+	URL                     string                `env:"URL"`
+}
+
+// GrafanaCloudConfig represents the grafana cloud configuration
+type GrafanaCloudConfig struct {
+	APIURL   string        `env:"API_URL,default=https://grafana.com"`
+	Org      string        `env:"ORG"`
+	Token    string        `env:"TOKEN"`
+	TokenTTL time.Duration `env:"TOKEN_TTL,default=1h"`
+}
+
+// GrafanaDefaultsConfig represents the grafana defaults configuration
+type GrafanaDefaultsConfig struct {
+	Language  string   `env:"LANGUAGE,default=en"`
+	Refresh   string   `env:"REFRESH,default=5s"`
+	Tags      []string `env:"TAGS"`
+	TimeFrom  string   `env:"TIME_FROM,default=now-6h"`
+	TimeTo    string   `env:"TIME_TO,default=now"`
+	Timezone  string   `env:"TIMEZONE,default=browser"`
+	WeekStart string   `env:"WEEK_START"`
+}
+
+// GrafanaNamingConfig represents the grafana naming configuration
+type GrafanaNamingConfig struct {
+	ForbiddenWords []string `env:"FORBIDDEN_WORDS"`
+	MaxLength      int      `env:"MAX_LENGTH,default=0"`
+	Prefix         string   `env:"PREFIX"`
+	TitleCase      bool     `env:"TITLE_CASE,default=false"`
+}
+
+// GrafanaTagsConfig represents the grafana tags configuration
+type GrafanaTagsConfig struct {
+	Mode     string   `env:"MODE,default=correct"`
+	Taxonomy []string `env:"TAXONOMY"`
+}
+
+// HTTPConfig represents the http configuration
+type HTTPConfig struct {
+	IdleConnTimeout     time.Duration `env:"IDLE_CONN_TIMEOUT,default=90s"`
+	KeepAlive           time.Duration `env:"KEEP_ALIVE,default=30s"`
+	MaxConnsPerHost     int           `env:"MAX_CONNS_PER_HOST,default=32"`
+	MaxIdleConns        int           `env:"MAX_IDLE_CONNS,default=100"`
+	MaxIdleConnsPerHost int           `env:"MAX_IDLE_CONNS_PER_HOST,default=16"`
+}
+
+// GrafanaOAuthConfig represents the grafana oauth configuration
+type GrafanaOAuthConfig struct {
+	Audience       string   `env:"AUDIENCE"`
+	ClientID       string   `env:"CLIENT_ID"`
+	ClientSecret   string   `env:"CLIENT_SECRET"`
+	GrantType      string   `env:"GRANT_TYPE,default=client_credentials"`
+	Header         string   `env:"HEADER,default=Proxy-Authorization"`
+	PrivateKeyFile string   `env:"PRIVATE_KEY_FILE"`
+	Scopes         []string `env:"SCOPES"`
+	Subject        string   `env:"SUBJECT"`
+	TokenURL       string   `env:"TOKEN_URL"`
+}
+
+// InventoryConfig represents the inventory configuration
+type InventoryConfig struct {
+	Backend string `env:"BACKEND"`
+	DSN     string `env:"DSN"`
+}
+
+// PrometheusConfig represents the prometheus configuration
+type PrometheusConfig struct {
+	DatasourceUID string   `env:"DATASOURCE_UID"`
+	ReplicaLabels []string `env:"REPLICA_LABELS,default=replica,prometheus_replica"`
+	ReplicaURLs   []string `env:"REPLICA_URLS"`
+	ViaGrafana    bool     `env:"VIA_GRAFANA,default=false"`
+}
+
+// SessionConfig represents the session configuration
+type SessionConfig struct {
+	MaxContexts int           `env:"MAX_CONTEXTS,default=1000"`
+	TTL         time.Duration `env:"TTL,default=1h"`
+}
+
+// TimeoutsConfig represents the timeouts configuration
+type TimeoutsConfig struct {
+	Default time.Duration            `env:"DEFAULT,default=60s"`
+	Tools   map[string]time.Duration `env:"TOOLS"`
 }
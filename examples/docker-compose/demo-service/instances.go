@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// maxInstances caps INSTANCES so a typo does not explode the series count
+const maxInstances = 50
+
+// trafficPeriod is the period of the shared traffic wave all instances follow
+const trafficPeriod = 10 * time.Minute
+
+// virtualInstance is one simulated replica of the service. Every instance
+// follows the same traffic wave, so their metrics move together, but each
+// takes its own share of the load and has its own latency and CPU baseline.
+type virtualInstance struct {
+	Instance string
+	Pod      string
+	load     float64
+	latency  float64
+	cpuBase  float64
+}
+
+// instanceCount reads the number of virtual instances from INSTANCES
+func instanceCount() int {
+	count, err := strconv.Atoi(getEnv("INSTANCES", "1"))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return min(count, maxInstances)
+}
+
+// newVirtualInstances creates the instances. Their profiles are seeded by
+// their index so they are stable across restarts.
+func newVirtualInstances(count int) []virtualInstance {
+	instances := make([]virtualInstance, count)
+	for i := range instances {
+		profile := rand.New(rand.NewSource(int64(i) + 1))
+		instances[i] = virtualInstance{
+			Instance: fmt.Sprintf("demo-service-%d:8080", i),
+			Pod:      fmt.Sprintf("demo-service-%05x-%d", profile.Intn(0xfffff), i),
+			load:     0.5 + profile.Float64(),
+			latency:  0.7 + profile.Float64()*0.6,
+			cpuBase:  10 + profile.Float64()*20,
+		}
+	}
+	return instances
+}
+
+// trafficWave is the shared traffic multiplier, between 0.6 and 1.4
+func trafficWave(now time.Time) float64 {
+	phase := float64(now.UnixNano()%int64(trafficPeriod)) / float64(trafficPeriod)
+	return 1 + 0.4*math.Sin(2*math.Pi*phase)
+}
+
+// requests is the number of requests the instance serves in an interval
+func (v virtualInstance) requests(wave float64) int {
+	return int(math.Round(float64(rand.Intn(10)+1) * wave * v.load))
+}
+
+// cpu is the instance's CPU usage percentage, following its load
+func (v virtualInstance) cpu(wave float64) float64 {
+	return min(v.cpuBase+wave*v.load*30+rand.Float64()*10, 100)
+}
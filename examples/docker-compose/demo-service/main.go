@@ -19,7 +19,9 @@ import (
 	metric "go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	resource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	trace "go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -29,7 +31,7 @@ var (
 			Name: "http_requests_total",
 			Help: "Total number of HTTP requests",
 		},
-		[]string{"method", "endpoint", "status"},
+		[]string{"method", "endpoint", "status", "instance", "pod"},
 	)
 
 	httpRequestDuration = promauto.NewHistogramVec(
@@ -38,35 +40,43 @@ var (
 			Help:    "HTTP request duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"method", "endpoint"},
+		[]string{"method", "endpoint", "instance", "pod"},
 	)
 
-	activeConnections = promauto.NewGauge(
+	activeConnections = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "active_connections",
 			Help: "Number of active connections",
 		},
+		[]string{"instance", "pod"},
 	)
 
-	processingQueueSize = promauto.NewGauge(
+	processingQueueSize = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "processing_queue_size",
 			Help: "Current size of the processing queue",
 		},
+		[]string{"instance", "pod"},
 	)
 
-	errorRate = promauto.NewCounter(
+	errorRate = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "errors_total",
 			Help: "Total number of errors",
 		},
+		[]string{"instance", "pod"},
 	)
 
 	// OTEL metrics
 	meter metric.Meter
+
+	// OTEL traces, only recorded when exporting via OTLP
+	tracer trace.Tracer
 )
 
-func initOTEL() (*sdkmetric.MeterProvider, error) {
+// initOTEL sets up the OTEL metrics, exposed on /metrics or, when an OTLP
+// endpoint is configured, pushed to the collector along with traces
+func initOTEL() (*sdkmetric.MeterProvider, *sdktrace.TracerProvider, error) {
 	ctx := context.Background()
 
 	// Create resource with service information
@@ -78,19 +88,27 @@ func initOTEL() (*sdkmetric.MeterProvider, error) {
 		),
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Create Prometheus exporter
-	exporter, err := otelprom.New()
-	if err != nil {
-		return nil, err
+	// Export to the collector via OTLP, or expose on /metrics for Prometheus
+	var reader sdkmetric.Reader
+	var tracerProvider *sdktrace.TracerProvider
+	if otlpEnabled() {
+		if reader, err = newOTLPMetricReader(ctx); err != nil {
+			return nil, nil, err
+		}
+		if tracerProvider, err = initTracing(ctx, res); err != nil {
+			return nil, nil, err
+		}
+	} else if reader, err = otelprom.New(); err != nil {
+		return nil, nil, err
 	}
 
 	// Create meter provider
 	meterProvider := sdkmetric.NewMeterProvider(
 		sdkmetric.WithResource(res),
-		sdkmetric.WithReader(exporter),
+		sdkmetric.WithReader(reader),
 	)
 
 	// Set global meter provider
@@ -99,7 +117,7 @@ func initOTEL() (*sdkmetric.MeterProvider, error) {
 	// Create meter
 	meter = meterProvider.Meter("demo-service")
 
-	return meterProvider, nil
+	return meterProvider, tracerProvider, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -109,7 +127,7 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func simulateMetrics(ctx context.Context) {
+func simulateMetrics(ctx context.Context, instances []virtualInstance) {
 	// Create OTEL metrics
 	cpuUsage, _ := meter.Float64ObservableGauge(
 		"cpu_usage_percent",
@@ -128,16 +146,33 @@ func simulateMetrics(ctx context.Context) {
 		metric.WithUnit("ms"),
 	)
 
+	// Semantic convention HTTP server duration, exported to Prometheus as
+	// http_server_request_duration_seconds
+	serverDuration, _ := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of HTTP server requests"),
+		metric.WithUnit("s"),
+	)
+
 	// Register callbacks for observable metrics
 	_, _ = meter.RegisterCallback(
 		func(ctx context.Context, o metric.Observer) error {
-			// Simulate CPU usage between 10-80%
-			cpu := 10 + rand.Float64()*70
-			o.ObserveFloat64(cpuUsage, cpu)
-
-			// Simulate memory usage between 100MB-800MB
-			memory := 100_000_000 + rand.Float64()*700_000_000
-			o.ObserveFloat64(memoryUsage, memory)
+			wave := trafficWave(time.Now())
+			// The memory leak only affects the first instance
+			leaked := scenarios.leak()
+			for i, instance := range instances {
+				attrs := metric.WithAttributes(attribute.String("pod", instance.Pod))
+
+				// Simulate CPU usage following the instance's load
+				o.ObserveFloat64(cpuUsage, instance.cpu(wave), attrs)
+
+				// Simulate memory usage between 100MB-800MB, plus any leak
+				memory := 100_000_000 + rand.Float64()*700_000_000
+				if i == 0 {
+					memory += leaked
+				}
+				o.ObserveFloat64(memoryUsage, memory, attrs)
+			}
 
 			return nil
 		},
@@ -145,7 +180,7 @@ func simulateMetrics(ctx context.Context) {
 		memoryUsage,
 	)
 
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(metricsInterval())
 	defer ticker.Stop()
 
 	for {
@@ -158,35 +193,49 @@ func simulateMetrics(ctx context.Context) {
 			endpoints := []string{"/api/users", "/api/products", "/api/orders", "/api/health"}
 			statuses := []string{"200", "201", "400", "404", "500"}
 
-			for i := 0; i < rand.Intn(10)+1; i++ {
-				method := methods[rand.Intn(len(methods))]
-				endpoint := endpoints[rand.Intn(len(endpoints))]
-				status := statuses[rand.Intn(len(statuses))]
-
-				// Record traditional Prometheus metrics
-				httpRequestsTotal.WithLabelValues(method, endpoint, status).Inc()
-
-				duration := rand.Float64() * 2
-				httpRequestDuration.WithLabelValues(method, endpoint).Observe(duration)
-
-				// Record OTEL metrics
-				requestLatency.Record(ctx, duration*1000,
-					metric.WithAttributes(
-						attribute.String("method", method),
-						attribute.String("endpoint", endpoint),
-						attribute.String("status", status),
-					),
-				)
-
-				// Occasionally record errors
-				if status == "500" || status == "400" {
-					errorRate.Inc()
+			wave := trafficWave(time.Now())
+			for _, instance := range instances {
+				requests := instance.requests(wave)
+				for i := 0; i < requests; i++ {
+					method := methods[rand.Intn(len(methods))]
+					endpoint := endpoints[rand.Intn(len(endpoints))]
+					status, duration := applyRequestScenarios(statuses[rand.Intn(len(statuses))], rand.Float64()*2*instance.latency)
+
+					// Record traditional Prometheus metrics
+					httpRequestsTotal.WithLabelValues(method, endpoint, status, instance.Instance, instance.Pod).Inc()
+
+					httpRequestDuration.WithLabelValues(method, endpoint, instance.Instance, instance.Pod).Observe(duration)
+
+					// Record OTEL metrics
+					requestLatency.Record(ctx, duration*1000,
+						metric.WithAttributes(
+							attribute.String("method", method),
+							attribute.String("endpoint", endpoint),
+							attribute.String("status", status),
+							attribute.String("pod", instance.Pod),
+						),
+					)
+
+					serverDuration.Record(ctx, duration,
+						metric.WithAttributes(
+							attribute.String("http.request.method", method),
+							attribute.String("http.route", endpoint),
+							attribute.String("http.response.status_code", status),
+							attribute.String("pod", instance.Pod),
+						),
+					)
+					traceRequest(ctx, instance.Pod, method, endpoint, status, time.Duration(duration*float64(time.Second)))
+
+					// Occasionally record errors
+					if status == "500" || status == "400" {
+						errorRate.WithLabelValues(instance.Instance, instance.Pod).Inc()
+					}
 				}
-			}
 
-			// Update gauges
-			activeConnections.Set(float64(rand.Intn(100) + 10))
-			processingQueueSize.Set(float64(rand.Intn(50)))
+				// Update gauges
+				activeConnections.WithLabelValues(instance.Instance, instance.Pod).Set(float64(rand.Intn(100)+10) * wave * instance.load)
+				processingQueueSize.WithLabelValues(instance.Instance, instance.Pod).Set(float64(rand.Intn(50)))
+			}
 		}
 	}
 }
@@ -198,25 +247,29 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 
 func main() {
 	// Initialize OTEL
-	meterProvider, err := initOTEL()
+	meterProvider, tracerProvider, err := initOTEL()
 	if err != nil {
 		log.Fatalf("Failed to initialize OTEL: %v", err)
 	}
 	defer func() {
-		if err := meterProvider.Shutdown(context.Background()); err != nil {
-			log.Printf("Error shutting down meter provider: %v", err)
+		if err := shutdownAll(context.Background(), meterProvider, tracerProvider); err != nil {
+			log.Printf("Error shutting down OTEL providers: %v", err)
 		}
 	}()
 
 	// Start metrics simulation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	go simulateMetrics(ctx)
+	instances := newVirtualInstances(instanceCount())
+	log.Printf("Simulating %d instance(s)", len(instances))
+	go simulateMetrics(ctx, instances)
 
 	// Setup HTTP server
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
-	mux.HandleFunc("/health", healthHandler)
+	mux.Handle("/metrics", targetDown(promhttp.Handler()))
+	mux.Handle("/health", targetDown(http.HandlerFunc(healthHandler)))
+	mux.HandleFunc("GET /scenarios", scenariosHandler)
+	mux.HandleFunc("/scenarios/{name}", scenarioHandler)
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`
 <!DOCTYPE html>
@@ -228,6 +281,7 @@ func main() {
 	<ul>
 		<li><a href="/metrics">Prometheus Metrics</a></li>
 		<li><a href="/health">Health Check</a></li>
+		<li><a href="/scenarios">Failure Scenarios</a></li>
 	</ul>
 	<h2>Available Metrics:</h2>
 	<ul>
@@ -239,7 +293,14 @@ func main() {
 		<li><strong>cpu_usage_percent</strong> - Simulated CPU usage (OTEL)</li>
 		<li><strong>memory_usage_bytes</strong> - Simulated memory usage (OTEL)</li>
 		<li><strong>request_latency_ms</strong> - Request latency histogram (OTEL)</li>
+		<li><strong>http_server_request_duration_seconds</strong> - HTTP server duration histogram (OTEL semantic conventions)</li>
+		<li><strong>demo_scenario_active</strong> - Whether a failure scenario is active, by scenario</li>
 	</ul>
+	<h2>Failure Scenarios:</h2>
+	<p>Start a scenario with <code>POST /scenarios/{name}?duration=5m</code> (the duration is optional) and stop it with <code>DELETE /scenarios/{name}</code>:
+	latency-spike, error-burst, memory-leak, target-down.</p>
+	<p>Set INSTANCES to simulate several replicas; every metric carries the instance and pod labels of the replica it belongs to.</p>
+	<p>With OTEL_EXPORTER_OTLP_ENDPOINT set, the OTEL metrics and request traces are pushed to the collector instead of being served here.</p>
 </body>
 </html>
 		`))
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"time"
+
+	otel "go.opentelemetry.io/otel"
+	attribute "go.opentelemetry.io/otel/attribute"
+	codes "go.opentelemetry.io/otel/codes"
+	otlpmetrichttp "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otlptracehttp "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	resource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	trace "go.opentelemetry.io/otel/trace"
+)
+
+// otlpEnabled reports whether an OTLP endpoint is configured; the exporters
+// read OTEL_EXPORTER_OTLP_ENDPOINT (e.g. http://otel-collector:4318) themselves
+func otlpEnabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// metricsInterval is how often requests are simulated and OTLP metrics exported
+func metricsInterval() time.Duration {
+	interval, err := time.ParseDuration(getEnv("METRICS_INTERVAL", "5s"))
+	if err != nil || interval <= 0 {
+		return 5 * time.Second
+	}
+	return interval
+}
+
+// newOTLPMetricReader pushes the OTEL metrics to the collector
+func newOTLPMetricReader(ctx context.Context) (sdkmetric.Reader, error) {
+	exporter, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(metricsInterval())), nil
+}
+
+// initTracing sends spans to the collector, which forwards them to Tempo
+func initTracing(ctx context.Context, res *resource.Resource) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	tracer = tracerProvider.Tracer("demo-service")
+
+	return tracerProvider, nil
+}
+
+// shutdownAll flushes and stops the providers, collecting their errors
+func shutdownAll(ctx context.Context, providers ...interface{ Shutdown(context.Context) error }) error {
+	var errs []error
+	for _, provider := range providers {
+		if provider != nil {
+			errs = append(errs, provider.Shutdown(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// traceRequest records the spans of a simulated request: the server span and,
+// for API endpoints, the database query it made. Spans are backdated by the
+// simulated duration so Tempo shows realistic latencies.
+func traceRequest(ctx context.Context, pod, method, endpoint, status string, duration time.Duration) {
+	if tracer == nil {
+		return
+	}
+
+	end := time.Now()
+	start := end.Add(-duration)
+	ctx, span := tracer.Start(ctx, method+" "+endpoint,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("http.route", endpoint),
+			attribute.String("http.response.status_code", status),
+			attribute.String("k8s.pod.name", pod),
+		),
+	)
+	if status[0] == '5' {
+		span.SetStatus(codes.Error, "internal server error")
+	}
+
+	if endpoint != "/api/health" {
+		queryStart := start.Add(time.Duration(rand.Int63n(int64(duration)/4 + 1)))
+		_, query := tracer.Start(ctx, "SELECT "+endpoint[len("/api/"):],
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(queryStart),
+			trace.WithAttributes(
+				attribute.String("db.system", "postgresql"),
+				attribute.String("db.operation.name", "SELECT"),
+				attribute.String("peer.service", "postgres"),
+			),
+		)
+		query.End(trace.WithTimestamp(queryStart.Add(duration / 2)))
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	promauto "github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Failure scenarios that can be toggled at runtime to simulate incidents
+const (
+	ScenarioLatencySpike = "latency-spike"
+	ScenarioErrorBurst   = "error-burst"
+	ScenarioMemoryLeak   = "memory-leak"
+	ScenarioTargetDown   = "target-down"
+)
+
+var knownScenarios = map[string]string{
+	ScenarioLatencySpike: "Requests take 5x longer, pushing p95 latency over a few seconds",
+	ScenarioErrorBurst:   "Half of the requests fail with a 500",
+	ScenarioMemoryLeak:   "Memory usage grows on every interval until the scenario is stopped",
+	ScenarioTargetDown:   "/metrics and /health return 503, so Prometheus reports the target down",
+}
+
+var scenarioActive = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "demo_scenario_active",
+		Help: "Whether a failure scenario is active (1) or not (0)",
+	},
+	[]string{"scenario"},
+)
+
+// ScenarioStatus describes a failure scenario and whether it is running
+type ScenarioStatus struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Active      bool       `json:"active"`
+	Until       *time.Time `json:"until,omitempty"`
+}
+
+// scenarioSet tracks the active scenarios; a zero expiry keeps a scenario
+// running until it is stopped
+type scenarioSet struct {
+	mu     sync.Mutex
+	active map[string]time.Time
+	leaked float64
+}
+
+var scenarios = newScenarioSet()
+
+func newScenarioSet() *scenarioSet {
+	for name := range knownScenarios {
+		scenarioActive.WithLabelValues(name).Set(0)
+	}
+	return &scenarioSet{active: map[string]time.Time{}}
+}
+
+// start activates a scenario, for the given duration when it is positive
+func (s *scenarioSet) start(name string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	s.active[name] = until
+	scenarioActive.WithLabelValues(name).Set(1)
+}
+
+// stop deactivates a scenario; stopping the memory leak frees the leaked memory
+func (s *scenarioSet) stop(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked(name)
+}
+
+func (s *scenarioSet) stopLocked(name string) {
+	delete(s.active, name)
+	if name == ScenarioMemoryLeak {
+		s.leaked = 0
+	}
+	scenarioActive.WithLabelValues(name).Set(0)
+}
+
+// isActive reports whether a scenario is running, expiring it once its
+// duration has passed
+func (s *scenarioSet) isActive(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.active[name]
+	if ok && !until.IsZero() && time.Now().After(until) {
+		s.stopLocked(name)
+		return false
+	}
+	return ok
+}
+
+// leak grows the simulated leak by up to 20MB and returns its total size
+func (s *scenarioSet) leak() float64 {
+	if !s.isActive(ScenarioMemoryLeak) {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leaked += 10_000_000 + rand.Float64()*10_000_000
+	return s.leaked
+}
+
+// statuses lists every scenario, sorted by name
+func (s *scenarioSet) statuses() []ScenarioStatus {
+	result := make([]ScenarioStatus, 0, len(knownScenarios))
+	for name, description := range knownScenarios {
+		status := ScenarioStatus{Name: name, Description: description, Active: s.isActive(name)}
+
+		s.mu.Lock()
+		if until := s.active[name]; status.Active && !until.IsZero() {
+			status.Until = &until
+		}
+		s.mu.Unlock()
+
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// applyRequestScenarios adjusts a simulated request for the latency spike
+// and error burst scenarios
+func applyRequestScenarios(status string, duration float64) (string, float64) {
+	if scenarios.isActive(ScenarioLatencySpike) {
+		duration = duration*5 + 1
+	}
+	if scenarios.isActive(ScenarioErrorBurst) && rand.Intn(2) == 0 {
+		status = "500"
+	}
+	return status, duration
+}
+
+// targetDown wraps a handler to fail while the target down scenario is active
+func targetDown(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scenarios.isActive(ScenarioTargetDown) {
+			http.Error(w, "target down scenario active", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// scenariosHandler lists the scenarios
+func scenariosHandler(w http.ResponseWriter, r *http.Request) {
+	writeScenarios(w)
+}
+
+// scenarioHandler starts a scenario with POST, optionally for a
+// ?duration=5m, and stops it with DELETE
+func scenarioHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := knownScenarios[name]; !ok {
+		http.Error(w, fmt.Sprintf("unknown scenario %q", name), http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		var duration time.Duration
+		if value := r.URL.Query().Get("duration"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, fmt.Sprintf("invalid duration %q", value), http.StatusBadRequest)
+				return
+			}
+			duration = parsed
+		}
+		scenarios.start(name, duration)
+	case http.MethodDelete:
+		scenarios.stop(name)
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeScenarios(w)
+}
+
+func writeScenarios(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(scenarios.statuses())
+}
@@ -0,0 +1,204 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	server "github.com/inference-gateway/adk/server"
+	envconfig "github.com/sethvargo/go-envconfig"
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	httpclient "github.com/inference-gateway/grafana-agent/internal/httpclient"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	tools "github.com/inference-gateway/grafana-agent/tools"
+)
+
+// agent holds the services and tools wired the way main.go wires them
+type agent struct {
+	grafanaSvc grafana.Grafana
+	tools      map[string]server.Tool
+}
+
+func newAgent(t *testing.T, s *stack) *agent {
+	t.Helper()
+
+	var cfg config.Config
+	if err := envconfig.ProcessWith(context.Background(), &envconfig.Config{
+		Target: &cfg,
+		Lookuper: envconfig.MapLookuper(map[string]string{
+			"GRAFANA_URL":            s.GrafanaURL,
+			"GRAFANA_API_KEY":        s.GrafanaAPIKey,
+			"GRAFANA_DEPLOY_ENABLED": "true",
+		}),
+	}); err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	logger := zap.NewNop()
+	transport := httpclient.NewTransport(&cfg.HTTP)
+	grafanaSvc, err := grafana.NewGrafanaService(logger, &cfg, transport)
+	if err != nil {
+		t.Fatalf("failed to initialize grafana service: %v", err)
+	}
+	promqlSvc, err := promql.NewPromQLService(logger, &cfg, transport)
+	if err != nil {
+		t.Fatalf("failed to initialize promql service: %v", err)
+	}
+	cloudSvc, err := grafanacloud.NewGrafanaCloudService(logger, &cfg, transport)
+	if err != nil {
+		t.Fatalf("failed to initialize grafanacloud service: %v", err)
+	}
+
+	a := &agent{grafanaSvc: grafanaSvc, tools: map[string]server.Tool{}}
+	for _, tool := range []server.Tool{
+		tools.NewDiscoverMetricsTool(logger, promqlSvc),
+		tools.NewGeneratePromqlQueriesTool(logger, promqlSvc, &cfg.Grafana, &cfg.Environment, &cfg.Alerting),
+		tools.NewCreateDashboardTool(logger, grafanaSvc, cloudSvc, &cfg.Grafana, &cfg.Environment),
+		tools.NewDeployDashboardTool(logger, grafanaSvc, cloudSvc, &cfg.Grafana, &cfg.Alerting),
+		tools.NewVerifyDashboardDataTool(logger, grafanaSvc, promqlSvc, &cfg.Grafana),
+	} {
+		a.tools[tool.GetName()] = tool
+	}
+	return a
+}
+
+// call executes a tool and decodes its JSON response into result
+func (a *agent) call(t *testing.T, name string, args map[string]any, result any) {
+	t.Helper()
+
+	if err := a.tryCall(name, args, result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (a *agent) tryCall(name string, args map[string]any, result any) error {
+	output, err := a.tools[name].Execute(context.Background(), args)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err := json.Unmarshal([]byte(output), result); err != nil {
+		return fmt.Errorf("%s: failed to parse response: %w\n%s", name, err, output)
+	}
+	return nil
+}
+
+// TestDashboardLifecycle runs the flow the agent follows for a typical
+// request against real servers: discover the demo service's metrics,
+// generate queries, create and deploy a dashboard, read it back, verify its
+// panels return data and delete it
+func TestDashboardLifecycle(t *testing.T) {
+	s := startStack(t)
+	a := newAgent(t, s)
+	ctx := context.Background()
+
+	var discovered tools.DiscoverMetricsResponse
+	eventually(t, "demo service metrics not discovered", func() error {
+		if err := a.tryCall("discover_metrics", map[string]any{
+			"prometheus_url": s.PrometheusURL,
+			"name_pattern":   "^http_request",
+		}, &discovered); err != nil {
+			return err
+		}
+		for _, metric := range discovered.Metrics {
+			if metric.Name == "http_requests_total" {
+				return nil
+			}
+		}
+		return fmt.Errorf("http_requests_total not in %d metrics", discovered.TotalMetrics)
+	})
+
+	var generated tools.GeneratePromqlQueriesResponse
+	a.call(t, "generate_promql_queries", map[string]any{
+		"prometheus_url": s.PrometheusURL,
+		"metric_names":   []any{"http_requests_total", "http_request_duration_seconds"},
+	}, &generated)
+	if len(generated.Results) != 2 {
+		t.Fatalf("expected queries for both metrics, got %d results", len(generated.Results))
+	}
+	var panels []any
+	for _, result := range generated.Results {
+		if result.Error != "" || len(result.Suggestions) == 0 {
+			t.Fatalf("no suggestions for %s: %s", result.MetricName, result.Error)
+		}
+		if result.MetricName == "http_requests_total" && result.MetricType != "counter" {
+			t.Errorf("expected http_requests_total to be a counter, got %s", result.MetricType)
+		}
+		suggestion := result.Suggestions[0]
+		expr := suggestion.DashboardQuery
+		if expr == "" {
+			expr = suggestion.Query
+		}
+		panels = append(panels, map[string]any{
+			"title":   result.MetricName,
+			"type":    "timeseries",
+			"targets": []any{map[string]any{"refId": "A", "expr": expr}},
+		})
+	}
+
+	title := fmt.Sprintf("Integration %d", time.Now().UnixNano())
+	var created struct {
+		Dashboard map[string]any `json:"dashboard"`
+	}
+	a.call(t, "create_dashboard", map[string]any{"dashboard_title": title, "panels": panels}, &created)
+	if created.Dashboard["title"] != title {
+		t.Fatalf("unexpected dashboard model %+v", created.Dashboard)
+	}
+
+	var deployed deploy.Result
+	a.call(t, "deploy_dashboard", map[string]any{"dashboard_json": created.Dashboard}, &deployed)
+	uid := deployed.Dashboard.UID
+	if uid == "" || deployed.Dashboard.Version < 1 {
+		t.Fatalf("unexpected deploy result %+v", deployed)
+	}
+	t.Cleanup(func() { _ = a.grafanaSvc.DeleteDashboard(ctx, uid, s.GrafanaURL, s.GrafanaAPIKey) })
+
+	stored, err := a.grafanaSvc.GetDashboard(ctx, uid, s.GrafanaURL, s.GrafanaAPIKey)
+	if err != nil {
+		t.Fatalf("failed to get the deployed dashboard: %v", err)
+	}
+	if stored.Dashboard["title"] != title {
+		t.Errorf("expected the deployed title %q, got %v", title, stored.Dashboard["title"])
+	}
+	storedPanels, _ := stored.Dashboard["panels"].([]any)
+	if len(storedPanels) != len(panels) {
+		t.Fatalf("expected %d panels stored, got %d", len(panels), len(storedPanels))
+	}
+	for i, raw := range storedPanels {
+		want := panels[i].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]
+		targets, _ := raw.(map[string]any)["targets"].([]any)
+		if len(targets) == 0 || targets[0].(map[string]any)["expr"] != want {
+			t.Errorf("panel %d: expected the query %v to round-trip, got %v", i, want, targets)
+		}
+	}
+
+	var verified tools.VerifyDashboardDataResponse
+	eventually(t, "dashboard panels without data", func() error {
+		if err := a.tryCall("verify_dashboard_data", map[string]any{
+			"dashboard_uid":  uid,
+			"prometheus_url": s.PrometheusURL,
+		}, &verified); err != nil {
+			return err
+		}
+		if verified.EmptyPanelCount > 0 {
+			return fmt.Errorf("%d empty panels: %+v", verified.EmptyPanelCount, verified.EmptyPanels)
+		}
+		return nil
+	})
+
+	if err := a.grafanaSvc.DeleteDashboard(ctx, uid, s.GrafanaURL, s.GrafanaAPIKey); err != nil {
+		t.Fatalf("failed to delete the dashboard: %v", err)
+	}
+	if _, err := a.grafanaSvc.GetDashboard(ctx, uid, s.GrafanaURL, s.GrafanaAPIKey); !errors.Is(err, grafana.ErrDashboardNotFound) {
+		t.Errorf("expected the deleted dashboard to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Images the stack runs; override them to pin versions or test a Grafana
+// upgrade before rolling it out
+var (
+	prometheusImage = getEnv("INTEGRATION_PROMETHEUS_IMAGE", "prom/prometheus:latest")
+	grafanaImage    = getEnv("INTEGRATION_GRAFANA_IMAGE", "grafana/grafana:latest")
+)
+
+// demoServiceImage is the tag the demo service is built under
+const demoServiceImage = "grafana-agent-demo-service:integration"
+
+// startupTimeout bounds how long a container may take to become ready
+const startupTimeout = 2 * time.Minute
+
+// prometheusConfig scrapes the demo service every few seconds so the test
+// does not wait long for samples
+const prometheusConfig = `global:
+  scrape_interval: 2s
+scrape_configs:
+  - job_name: demo-service
+    honor_labels: true
+    static_configs:
+      - targets: ['demo-service:8080']
+`
+
+// stack is a Prometheus scraping the demo service and a Grafana, running in
+// containers on their own network
+type stack struct {
+	PrometheusURL  string
+	GrafanaURL     string
+	GrafanaAPIKey  string
+	DemoServiceURL string
+}
+
+// startStack builds the demo service and starts the containers, which are
+// removed when the test finishes
+func startStack(t *testing.T) *stack {
+	t.Helper()
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is required for the integration tests")
+	}
+
+	network := fmt.Sprintf("grafana-agent-it-%d", time.Now().UnixNano())
+	docker(t, "network", "create", network)
+	t.Cleanup(func() { _ = exec.Command("docker", "network", "rm", network).Run() })
+
+	docker(t, "build", "-t", demoServiceImage, filepath.Join("..", "examples", "docker-compose", "demo-service"))
+	demoServiceURL := runContainer(t, network, "demo-service", demoServiceImage, "8080",
+		"-e", "METRICS_INTERVAL=1s", "-e", "INSTANCES=2")
+
+	configFile := filepath.Join(t.TempDir(), "prometheus.yml")
+	if err := os.WriteFile(configFile, []byte(prometheusConfig), 0o644); err != nil {
+		t.Fatalf("failed to write the prometheus config: %v", err)
+	}
+	prometheusURL := runContainer(t, network, "prometheus", prometheusImage, "9090",
+		"-v", configFile+":/etc/prometheus/prometheus.yml:ro")
+
+	grafanaURL := runContainer(t, network, "grafana", grafanaImage, "3000",
+		"-e", "GF_SECURITY_ADMIN_USER=admin", "-e", "GF_SECURITY_ADMIN_PASSWORD=admin")
+
+	waitFor(t, demoServiceURL+"/health")
+	waitFor(t, prometheusURL+"/-/ready")
+	waitFor(t, grafanaURL+"/api/health")
+
+	return &stack{
+		PrometheusURL:  prometheusURL,
+		GrafanaURL:     grafanaURL,
+		GrafanaAPIKey:  createServiceAccountToken(t, grafanaURL),
+		DemoServiceURL: demoServiceURL,
+	}
+}
+
+// docker runs a docker command and returns its trimmed output
+func docker(t *testing.T, args ...string) string {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("docker %s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+// runContainer starts a container reachable as alias on the network and
+// returns the URL of its port published on localhost
+func runContainer(t *testing.T, network, alias, image, port string, args ...string) string {
+	t.Helper()
+
+	runArgs := append([]string{"run", "-d", "--network", network, "--network-alias", alias, "-p", "127.0.0.1::" + port}, args...)
+	id := docker(t, append(runArgs, image)...)
+	t.Cleanup(func() {
+		if t.Failed() {
+			logs, _ := exec.Command("docker", "logs", "--tail", "50", id).CombinedOutput()
+			t.Logf("%s logs:\n%s", alias, logs)
+		}
+		_ = exec.Command("docker", "rm", "-f", "-v", id).Run()
+	})
+
+	// docker port prints one line per address family, e.g. 127.0.0.1:49153
+	address := strings.SplitN(docker(t, "port", id, port), "\n", 2)[0]
+	return "http://" + address
+}
+
+// waitFor polls a URL until it answers 200 OK
+func waitFor(t *testing.T, url string) {
+	t.Helper()
+
+	deadline := time.Now().Add(startupTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatalf("%s not ready after %s", url, startupTimeout)
+}
+
+// eventually retries check until it succeeds or the startup timeout passes
+func eventually(t *testing.T, what string, check func() error) {
+	t.Helper()
+
+	deadline := time.Now().Add(startupTimeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: %v", what, err)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// createServiceAccountToken creates an admin service account token with the
+// admin user's basic auth, the way an operator provisions the agent's key
+func createServiceAccountToken(t *testing.T, grafanaURL string) string {
+	t.Helper()
+
+	var account struct {
+		ID int `json:"id"`
+	}
+	grafanaAdminPost(t, grafanaURL+"/api/serviceaccounts", map[string]any{"name": "grafana-agent", "role": "Admin"}, &account)
+
+	var token struct {
+		Key string `json:"key"`
+	}
+	grafanaAdminPost(t, fmt.Sprintf("%s/api/serviceaccounts/%d/tokens", grafanaURL, account.ID), map[string]any{"name": "integration"}, &token)
+	return token.Key
+}
+
+func grafanaAdminPost(t *testing.T, url string, body, result any) {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.SetBasicAuth("admin", "admin")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST %s: status %d", url, resp.StatusCode)
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			t.Fatalf("POST %s: failed to decode response: %v", url, err)
+		}
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
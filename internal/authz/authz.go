@@ -0,0 +1,147 @@
+// Package authz decides which tools a caller role may run and which Grafana
+// folders it may write to, from a YAML policy such as
+//
+//	default_role: viewer
+//	roles:
+//	  viewer:
+//	    tools: [discover_metrics, generate_promql_queries, create_dashboard]
+//	  dev:
+//	    tools: ["*"]
+//	    folders: [Sandbox]
+//	  sre:
+//	    tools: ["*"]
+//
+// A role without folders may write anywhere; "*" matches every tool or
+// folder, and "General" names the root folder.
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Wildcard matches every tool or folder
+const Wildcard = "*"
+
+// GeneralFolder names the root folder in a policy
+const GeneralFolder = "General"
+
+// ErrForbidden matches every authorization failure
+var ErrForbidden = errors.New("forbidden")
+
+// Policy maps caller roles to the tools and folders they may use
+type Policy struct {
+	// DefaultRole applies to requests that carry no caller role
+	DefaultRole string          `yaml:"default_role"`
+	Roles       map[string]Role `yaml:"roles"`
+}
+
+// Role lists the tools a role may run and the folders it may write to
+type Role struct {
+	Tools []string `yaml:"tools"`
+	// Folders are folder paths ("Platform/Sandbox") or folder UIDs; either
+	// also allows the folder's subfolders. Empty allows every folder.
+	Folders []string `yaml:"folders"`
+}
+
+// LoadPolicy reads a policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("authorization policy %s is invalid: %w", path, err)
+	}
+	if len(policy.Roles) == 0 {
+		return nil, fmt.Errorf("authorization policy %s is invalid: it must define at least one role", path)
+	}
+	if policy.DefaultRole != "" {
+		if _, ok := policy.Roles[policy.DefaultRole]; !ok {
+			return nil, fmt.Errorf("authorization policy %s is invalid: default_role %q is not defined", path, policy.DefaultRole)
+		}
+	}
+	return &policy, nil
+}
+
+// Resolve returns the role a request runs as: the caller's role, or the
+// default role when the request carries none
+func (p *Policy) Resolve(callerRole string) string {
+	if callerRole == "" {
+		return p.DefaultRole
+	}
+	return callerRole
+}
+
+// AuthorizeTool reports whether a role may run a tool
+func (p *Policy) AuthorizeTool(role, tool string) error {
+	definition, ok := p.Roles[role]
+	if !ok {
+		if role == "" {
+			return fmt.Errorf("%w: the request has no caller role and no default_role is configured", ErrForbidden)
+		}
+		return fmt.Errorf("%w: role %q is not defined in the authorization policy", ErrForbidden, role)
+	}
+	if !slices.Contains(definition.Tools, Wildcard) && !slices.Contains(definition.Tools, tool) {
+		return fmt.Errorf("%w: role %q may not use %s", ErrForbidden, role, tool)
+	}
+	return nil
+}
+
+// AuthorizeFolder reports whether a role may write to a folder, given by its
+// slash-separated path, its UIDs or both; neither means the General folder.
+// folderUIDs holds the UID of the folder followed by those of its existing
+// ancestors, so a folder allowed by UID also covers its subfolders.
+func (p *Policy) AuthorizeFolder(role, folderPath string, folderUIDs []string) error {
+	definition := p.Roles[role]
+	if !definition.RestrictsFolders() {
+		return nil
+	}
+	for _, uid := range folderUIDs {
+		if uid != "" && slices.Contains(definition.Folders, uid) {
+			return nil
+		}
+	}
+
+	target := strings.Trim(folderPath, "/ ")
+	switch {
+	case target == "" && len(folderUIDs) > 0:
+		target = folderUIDs[0]
+	case target == "":
+		target = GeneralFolder
+	}
+	for _, allowed := range definition.Folders {
+		if withinFolder(target, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: role %q may not write to folder %q; it may only write to %s", ErrForbidden, role, target, strings.Join(definition.Folders, ", "))
+}
+
+// RestrictsFolders reports whether the role may only write to some folders
+func (r Role) RestrictsFolders() bool {
+	return len(r.Folders) > 0 && !slices.Contains(r.Folders, Wildcard)
+}
+
+// withinFolder reports whether path is folder or one of its subfolders,
+// comparing titles case-insensitively like folder resolution does
+func withinFolder(path, folder string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	allowed := strings.Split(strings.Trim(folder, "/"), "/")
+	if len(allowed) > len(segments) {
+		return false
+	}
+	for i := range allowed {
+		if !strings.EqualFold(strings.TrimSpace(segments[i]), strings.TrimSpace(allowed[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+default_role: viewer
+roles:
+  viewer:
+    tools: [discover_metrics, create_dashboard]
+  dev:
+    tools: ["*"]
+    folders: [Sandbox, team-folder-uid]
+  sre:
+    tools: ["*"]
+`
+
+func loadTestPolicy(t *testing.T, content string) (*Policy, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return LoadPolicy(path)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := loadTestPolicy(t, testPolicy)
+	require.NoError(t, err)
+	require.Equal(t, "viewer", policy.Resolve(""))
+	require.Equal(t, "dev", policy.Resolve("dev"))
+
+	_, err = loadTestPolicy(t, "default_role: admin\nroles:\n  dev:\n    tools: ['*']\n")
+	require.ErrorContains(t, err, `default_role "admin" is not defined`)
+
+	_, err = loadTestPolicy(t, "roles: {}\n")
+	require.ErrorContains(t, err, "at least one role")
+}
+
+func TestAuthorizeTool(t *testing.T) {
+	policy, err := loadTestPolicy(t, testPolicy)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.AuthorizeTool("viewer", "create_dashboard"))
+	require.NoError(t, policy.AuthorizeTool("dev", "deploy_dashboard"))
+
+	err = policy.AuthorizeTool("viewer", "deploy_dashboard")
+	require.True(t, errors.Is(err, ErrForbidden))
+	require.ErrorContains(t, err, `role "viewer" may not use deploy_dashboard`)
+	require.ErrorContains(t, policy.AuthorizeTool("intern", "discover_metrics"), `role "intern" is not defined`)
+}
+
+func TestAuthorizeFolder(t *testing.T) {
+	policy, err := loadTestPolicy(t, testPolicy)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.AuthorizeFolder("dev", "sandbox", nil))
+	require.NoError(t, policy.AuthorizeFolder("dev", "Sandbox/Experiments", nil))
+	require.NoError(t, policy.AuthorizeFolder("dev", "", []string{"team-folder-uid"}))
+	require.NoError(t, policy.AuthorizeFolder("dev", "Team/Payments", []string{"payments-uid", "team-folder-uid"}))
+	require.NoError(t, policy.AuthorizeFolder("dev", "Team/Payments/New", []string{"payments-uid", "team-folder-uid"}))
+	require.NoError(t, policy.AuthorizeFolder("sre", "Production", nil))
+
+	require.ErrorIs(t, policy.AuthorizeFolder("dev", "Production", nil), ErrForbidden)
+	require.ErrorIs(t, policy.AuthorizeFolder("dev", "SandboxOld", nil), ErrForbidden)
+	require.ErrorIs(t, policy.AuthorizeFolder("dev", "", []string{"prod-uid"}), ErrForbidden)
+	require.ErrorIs(t, policy.AuthorizeFolder("dev", "Production/Team", []string{"team-uid", "prod-uid"}), ErrForbidden)
+	require.ErrorContains(t, policy.AuthorizeFolder("dev", "", nil), `folder "General"`)
+}
+
+func TestScope(t *testing.T) {
+	policy, err := loadTestPolicy(t, testPolicy)
+	require.NoError(t, err)
+
+	_, ok := FromContext(context.Background())
+	require.False(t, ok)
+
+	scope, ok := FromContext(NewContext(context.Background(), policy, "dev"))
+	require.True(t, ok)
+	require.Equal(t, "dev", scope.Role())
+	require.True(t, scope.RestrictsFolders())
+	require.NoError(t, scope.AuthorizeFolder("Sandbox/Team", []string{"nested-uid"}))
+	require.ErrorIs(t, scope.AuthorizeFolder("", []string{"prod-uid"}), ErrForbidden)
+
+	scope, _ = FromContext(NewContext(context.Background(), policy, "sre"))
+	require.False(t, scope.RestrictsFolders())
+}
@@ -0,0 +1,40 @@
+package authz
+
+import "context"
+
+// scopeKey is the context key of the caller's Scope
+type scopeKey struct{}
+
+// Scope is the role a tool call runs as, carried in its context so the
+// deployer can check the folder it writes to
+type Scope struct {
+	policy *Policy
+	role   string
+}
+
+// NewContext returns a context carrying the role a tool call runs as
+func NewContext(ctx context.Context, policy *Policy, role string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, Scope{policy: policy, role: role})
+}
+
+// FromContext returns the scope of a tool call; ok is false when no
+// authorization policy is configured
+func FromContext(ctx context.Context) (scope Scope, ok bool) {
+	scope, ok = ctx.Value(scopeKey{}).(Scope)
+	return scope, ok && scope.policy != nil
+}
+
+// Role returns the role the call runs as
+func (s Scope) Role() string {
+	return s.role
+}
+
+// RestrictsFolders reports whether the role may only write to some folders
+func (s Scope) RestrictsFolders() bool {
+	return s.policy.Roles[s.role].RestrictsFolders()
+}
+
+// AuthorizeFolder reports whether the role may write to a folder
+func (s Scope) AuthorizeFolder(folderPath string, folderUIDs []string) error {
+	return s.policy.AuthorizeFolder(s.role, folderPath, folderUIDs)
+}
@@ -0,0 +1,184 @@
+package circuit
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	zap "go.uber.org/zap"
+)
+
+// OpenError is returned for requests short-circuited by an open breaker
+type OpenError struct {
+	Backend  string    `json:"backend"`
+	Since    time.Time `json:"since"`
+	RetryAt  time.Time `json:"retry_at"`
+	Failures int       `json:"failures"`
+	LastErr  string    `json:"last_error,omitempty"`
+}
+
+// Error implements the error interface
+func (e *OpenError) Error() string {
+	msg := fmt.Sprintf("backend %s unavailable since %s (%d consecutive failures), retry after %s",
+		e.Backend, e.Since.UTC().Format(time.RFC3339), e.Failures, e.RetryAt.UTC().Format(time.RFC3339))
+	if e.LastErr != "" {
+		msg += ": " + e.LastErr
+	}
+	return msg
+}
+
+// backendState tracks consecutive failures of a single backend
+type backendState struct {
+	failures  int
+	openSince time.Time
+	retryAt   time.Time
+	probing   bool
+	lastErr   string
+}
+
+// Breaker tracks the health of each backend (scheme and host) reached
+// through its transport. After threshold consecutive failures the backend
+// is considered down: requests fail immediately with an OpenError until the
+// cooldown elapses, after which a single probe request decides whether the
+// breaker closes again.
+type Breaker struct {
+	logger    *zap.Logger
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	backends map[string]*backendState
+}
+
+// NewBreaker creates a breaker for the named client. A threshold of zero
+// or less disables it.
+func NewBreaker(logger *zap.Logger, name string, threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		logger:    logger,
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		backends:  map[string]*backendState{},
+	}
+}
+
+// Transport wraps next so requests pass through the breaker. A nil next
+// uses http.DefaultTransport.
+func (b *Breaker) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if b == nil || b.threshold <= 0 {
+		return next
+	}
+	return &transport{breaker: b, next: next}
+}
+
+// allow reports whether a request to backend may proceed
+func (b *Breaker) allow(backend string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.backends[backend]
+	if !ok || state.openSince.IsZero() {
+		return nil
+	}
+
+	if b.now().Before(state.retryAt) || state.probing {
+		return &OpenError{
+			Backend:  backend,
+			Since:    state.openSince,
+			RetryAt:  state.retryAt,
+			Failures: state.failures,
+			LastErr:  state.lastErr,
+		}
+	}
+
+	state.probing = true
+	return nil
+}
+
+// record updates the backend state with the outcome of a request
+func (b *Breaker) record(backend string, failure error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.backends[backend]
+	if !ok {
+		state = &backendState{}
+		b.backends[backend] = state
+	}
+
+	if failure == nil {
+		if !state.openSince.IsZero() {
+			b.logger.Info("circuit closed, backend recovered",
+				zap.String("client", b.name),
+				zap.String("backend", backend),
+				zap.Duration("downtime", b.now().Sub(state.openSince)))
+		}
+		*state = backendState{}
+		return
+	}
+
+	state.failures++
+	state.lastErr = failure.Error()
+	state.probing = false
+
+	if state.failures < b.threshold {
+		return
+	}
+
+	if state.openSince.IsZero() {
+		state.openSince = b.now()
+		b.logger.Warn("circuit opened, short-circuiting requests",
+			zap.String("client", b.name),
+			zap.String("backend", backend),
+			zap.Int("failures", state.failures),
+			zap.Duration("cooldown", b.cooldown),
+			zap.Error(failure))
+	}
+	state.retryAt = b.now().Add(b.cooldown)
+}
+
+// release clears an in-flight probe whose outcome is unknown
+func (b *Breaker) release(backend string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if state, ok := b.backends[backend]; ok {
+		state.probing = false
+	}
+}
+
+// transport is the RoundTripper installed by Breaker.Transport
+type transport struct {
+	breaker *Breaker
+	next    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backend := req.URL.Scheme + "://" + req.URL.Host
+	if err := t.breaker.allow(backend); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// The caller gave up; this says nothing about the backend. Release
+		// a probe slot without changing the failure count.
+		t.breaker.release(backend)
+	case err != nil:
+		t.breaker.record(backend, err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		t.breaker.record(backend, fmt.Errorf("status %d", resp.StatusCode))
+	default:
+		t.breaker.record(backend, nil)
+	}
+	return resp, err
+}
@@ -0,0 +1,111 @@
+package circuit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+)
+
+func TestBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(zap.NewNop(), "test", 3, time.Minute)
+	breaker.now = func() time.Time { return now }
+	client := &http.Client{Transport: breaker.Transport(nil)}
+
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("Expected request %d to reach the backend, got %v", i, err)
+		}
+	}
+
+	err := get()
+	var openErr *OpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("Expected OpenError after 3 failures, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected the open circuit to short-circuit, backend saw %d calls", calls.Load())
+	}
+	if !openErr.Since.Equal(now) || !openErr.RetryAt.Equal(now.Add(time.Minute)) || openErr.Failures != 3 {
+		t.Errorf("Unexpected OpenError %+v", openErr)
+	}
+	if openErr.LastErr != "status 503" {
+		t.Errorf("Expected last error 'status 503', got %q", openErr.LastErr)
+	}
+
+	// After the cooldown a failing probe re-opens the circuit
+	now = now.Add(time.Minute)
+	if err := get(); err != nil {
+		t.Fatalf("Expected probe to reach the backend, got %v", err)
+	}
+	if err := get(); !errors.As(err, &openErr) {
+		t.Fatalf("Expected failed probe to re-open the circuit, got %v", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	for i := 0; i < 2; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("Expected closed circuit, got %v", err)
+		}
+	}
+	if calls.Load() != 6 {
+		t.Errorf("Expected 6 backend calls, got %d", calls.Load())
+	}
+}
+
+func TestBreaker_IgnoresCallerCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	breaker := NewBreaker(zap.NewNop(), "test", 1, time.Minute)
+	client := &http.Client{Transport: breaker.Transport(nil)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("Expected the cancelled request to fail")
+	}
+
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	if err := breaker.allow(req.URL.Scheme + "://" + req.URL.Host); err != nil {
+		t.Errorf("Expected caller cancellation not to open the circuit, got %v", err)
+	}
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	if got := NewBreaker(zap.NewNop(), "test", 0, time.Minute).Transport(nil); got != http.DefaultTransport {
+		t.Errorf("Expected a zero threshold to return the wrapped transport, got %T", got)
+	}
+}
@@ -0,0 +1,748 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+	naming "github.com/inference-gateway/grafana-agent/internal/naming"
+	schema "github.com/inference-gateway/grafana-agent/internal/schema"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+// DefaultMessage is the version message used when a deployment does not provide one
+const DefaultMessage = "Dashboard deployed via grafana-agent"
+
+// maxFolderDepth bounds the parent walk of a folder; Grafana nests folders
+// at most eight deep
+const maxFolderDepth = 8
+
+// maxProvenanceMetrics caps the metrics listed in a version message
+const maxProvenanceMetrics = 10
+
+// Deployment preconditions shared by every tool that pushes dashboards to Grafana
+var (
+	ErrDeployDisabled           = errors.New("grafana deployment is disabled - set GRAFANA_DEPLOY_ENABLED=true to enable dashboard deployments")
+	ErrMissingURL               = errors.New("grafana_url must be provided either as a parameter or in configuration (GRAFANA_URL)")
+	ErrMissingAPIKey            = errors.New("grafana API key is required - set GRAFANA_API_KEY")
+	ErrFolderConflict           = errors.New("folder and folder_uid are mutually exclusive - provide only one")
+	ErrStackConflict            = errors.New("stack and grafana_url are mutually exclusive - provide only one")
+	ErrMergeWithoutUID          = errors.New("base_version requires dashboard_json to have a uid")
+	ErrPublicDashboardsDisabled = errors.New("public dashboards are disabled - set GRAFANA_PUBLIC_DASHBOARDS_ENABLED=true to allow publishing dashboards")
+)
+
+// Target is a resolved Grafana instance to deploy to
+type Target struct {
+	GrafanaURL string
+	APIKey     string
+	// Stack is the Grafana Cloud stack slug when the target was resolved from a stack name
+	Stack string
+}
+
+// Request describes a single dashboard deployment
+type Request struct {
+	// Dashboard is the dashboard model (the "dashboard" object of the Grafana API payload)
+	Dashboard map[string]any
+	// GrafanaURL overrides the configured Grafana URL when set
+	GrafanaURL string
+	// Stack names a Grafana Cloud stack ("prod") whose URL and a minted
+	// service-account token are used instead of GRAFANA_URL/GRAFANA_API_KEY
+	Stack string
+	// Target, if set, is the Grafana instance the caller already resolved
+	// with ResolveTarget; GrafanaURL and Stack are then ignored
+	Target    *Target
+	FolderUID string
+	// FolderPath is a slash-separated folder chain such as "Platform/Payments";
+	// missing folders are created. A literal slash in a title is written "\/".
+	FolderPath string
+	Message    string
+	Overwrite  bool
+	// BaseVersion is the deployed version the dashboard was derived from.
+	// When the deployed dashboard has been edited since, the agent's changes
+	// are merged three-way into it instead of overwriting it.
+	BaseVersion int
+	// Provenance, if set, is appended to the version message
+	Provenance *Provenance
+	// Maintenance, if set and active, silences the affected alerts while
+	// the dashboard is saved
+	Maintenance *Maintenance
+	// MigrateSchema converts the legacy layout and panels of an imported
+	// dashboard to what the target Grafana renders
+	MigrateSchema bool
+}
+
+// Provenance records why a dashboard version was saved, so Grafana's version
+// history explains each change
+type Provenance struct {
+	// Prompt summarizes the user request that led to the change
+	Prompt string `json:"prompt,omitempty"`
+	// Template is the dashboard template or builder the dashboard was
+	// generated from, e.g. "service_overview"
+	Template string `json:"template,omitempty"`
+	// Metrics are the metric names the dashboard queries
+	Metrics []string `json:"metrics,omitempty"`
+	// RequestID is the A2A task the change was made in
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// DashboardRef identifies a deployed dashboard
+type DashboardRef struct {
+	ID      int    `json:"id"`
+	UID     string `json:"uid"`
+	URL     string `json:"url"`
+	Version int    `json:"version"`
+	Slug    string `json:"slug,omitempty"`
+}
+
+// Result is the response shape shared by the deploying tools
+type Result struct {
+	Status     string       `json:"status"`
+	GrafanaURL string       `json:"grafana_url"`
+	Stack      string       `json:"stack,omitempty"`
+	Dashboard  DashboardRef `json:"dashboard"`
+	FolderUID  string       `json:"folder_uid,omitempty"`
+	FolderPath string       `json:"folder_path,omitempty"`
+	// CreatedFolders lists the folders of FolderPath that had to be created
+	CreatedFolders []string `json:"created_folders,omitempty"`
+	// Merge reports the three-way merge with concurrent edits, if one was needed
+	Merge *MergeReport `json:"merge,omitempty"`
+	// Conflict is set, with status "conflict", when Grafana refused the save
+	Conflict   *ConflictReport `json:"conflict,omitempty"`
+	Provenance *Provenance     `json:"provenance,omitempty"`
+	// Silence reports the silence of a deployment inside a maintenance window
+	Silence *SilenceReport `json:"silence,omitempty"`
+	// Schema reports the schema migrations applied for the target Grafana,
+	// and what it may not render as designed
+	Schema  *schema.Report `json:"schema,omitempty"`
+	Message string         `json:"message"`
+}
+
+// ConflictReport describes a save Grafana rejected because the dashboard
+// changed in the meantime or clashes with another dashboard
+type ConflictReport struct {
+	// Reason is Grafana's status, e.g. "version-mismatch" or "name-exists"
+	Reason string `json:"reason"`
+	Detail string `json:"detail"`
+	// ExpectedVersion is the version the save was based on, if any
+	ExpectedVersion int `json:"expected_version,omitempty"`
+}
+
+// MergeReport describes how the agent's changes were merged into a dashboard
+// edited since BaseVersion
+type MergeReport struct {
+	BaseVersion    int `json:"base_version"`
+	CurrentVersion int `json:"current_version"`
+	// Conflicts are paths both sides changed; the deployed value was kept
+	Conflicts []diff.Conflict `json:"conflicts,omitempty"`
+}
+
+// Deployer deploys dashboards to Grafana with a single validation and
+// folder-resolution path for create_dashboard and deploy_dashboard
+type Deployer struct {
+	logger     *zap.Logger
+	grafanaSvc grafana.Grafana
+	cloudSvc   grafanacloud.GrafanaCloud
+	config     *config.GrafanaConfig
+	inventory  inventory.Store
+	// agentVersion identifies the agent build in version messages
+	agentVersion string
+}
+
+// Options are the settings of a Deployer that outlive a single deployment;
+// main builds them once and the deploying tools pass them on
+type Options struct {
+	// Inventory, when set, records every dashboard the deployer saves and
+	// forgets the ones it deletes
+	Inventory inventory.Store
+	// AgentVersion identifies the agent build in the version messages of
+	// the dashboards the deployer saves, e.g. "grafana-agent 1.4.0"
+	AgentVersion string
+}
+
+// NewDeployer creates a new Deployer. cloudSvc may be nil, in which case
+// deployments to a named stack fail with grafanacloud.ErrNotConfigured.
+func NewDeployer(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, grafanaConfig *config.GrafanaConfig, opts Options) *Deployer {
+	return &Deployer{
+		logger:       logger,
+		grafanaSvc:   grafanaSvc,
+		cloudSvc:     cloudSvc,
+		config:       grafanaConfig,
+		inventory:    opts.Inventory,
+		agentVersion: opts.AgentVersion,
+	}
+}
+
+// ResolveTarget checks that deployments are enabled and resolves the Grafana
+// URL and API key: a named Grafana Cloud stack resolves both through the
+// Cloud API, otherwise the URL argument or GRAFANA_URL is used with
+// GRAFANA_API_KEY
+func (d *Deployer) ResolveTarget(ctx context.Context, grafanaURL, stack string) (*Target, error) {
+	if d.config != nil && !d.config.DeployEnabled {
+		d.logger.Warn("Grafana deployment attempted but GRAFANA_DEPLOY_ENABLED=false")
+		return nil, ErrDeployDisabled
+	}
+
+	if stack = strings.TrimSpace(stack); stack != "" {
+		if strings.TrimSpace(grafanaURL) != "" {
+			return nil, ErrStackConflict
+		}
+		return d.resolveStack(ctx, stack)
+	}
+
+	return d.ResolveReadTarget(grafanaURL)
+}
+
+// ResolveReadTarget resolves the Grafana URL and API key like ResolveTarget
+// for read-only calls, which work even when deployments are disabled
+func (d *Deployer) ResolveReadTarget(grafanaURL string) (*Target, error) {
+	target := &Target{GrafanaURL: strings.TrimSpace(grafanaURL)}
+	if target.GrafanaURL == "" && d.config != nil {
+		target.GrafanaURL = d.config.URL
+	}
+	if target.GrafanaURL == "" {
+		return nil, ErrMissingURL
+	}
+
+	if d.config != nil {
+		target.APIKey = d.config.APIKey
+	}
+	if target.APIKey == "" && !grafana.OAuthReplacesAPIKey(d.config) {
+		return nil, ErrMissingAPIKey
+	}
+
+	return target, nil
+}
+
+// resolveStack resolves a Grafana Cloud stack to its URL and a stack token
+func (d *Deployer) resolveStack(ctx context.Context, stack string) (*Target, error) {
+	if d.cloudSvc == nil {
+		return nil, grafanacloud.ErrNotConfigured
+	}
+
+	credentials, err := d.cloudSvc.StackCredentials(ctx, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Info("Resolved Grafana Cloud stack",
+		zap.String("stack", credentials.Stack.Slug),
+		zap.String("grafana_url", credentials.Stack.URL))
+
+	return &Target{
+		GrafanaURL: credentials.Stack.URL,
+		APIKey:     credentials.Token,
+		Stack:      credentials.Stack.Slug,
+	}, nil
+}
+
+// Deploy resolves the target and folder and pushes the dashboard to Grafana
+func (d *Deployer) Deploy(ctx context.Context, req Request) (*Result, error) {
+	target := req.Target
+	if target == nil {
+		var err error
+		if target, err = d.ResolveTarget(ctx, req.GrafanaURL, req.Stack); err != nil {
+			return nil, err
+		}
+	}
+
+	// Titles must follow the naming convention, except on edits of deployed
+	// dashboards so that dashboards predating it stay editable. A deployer
+	// without configuration has none. The tag taxonomy is enforced where
+	// dashboards are generated and retagged; the tags of dashboards deployed
+	// as given are kept, with a warning for the undeclared ones.
+	if req.BaseVersion == 0 && d.config != nil {
+		title, _ := req.Dashboard["title"].(string)
+		if err := naming.NewRules(&d.config.Naming).Check(title); err != nil {
+			return nil, err
+		}
+		if _, undeclared := taxonomy.New(&d.config.Tags).Correct(dashboardTags(req.Dashboard)); len(undeclared) > 0 {
+			d.logger.Warn("Deploying dashboard with tags outside the tag taxonomy",
+				zap.String("title", title),
+				zap.Strings("tags", undeclared))
+		}
+	}
+
+	message := req.Message
+	if message == "" {
+		message = DefaultMessage
+	}
+	if err := d.authorizeFolder(ctx, target, req); err != nil {
+		return nil, err
+	}
+	folderUID, created, err := d.resolveFolder(ctx, target, req)
+	if err != nil {
+		return nil, err
+	}
+
+	silence, err := d.silenceMaintenance(ctx, target, req)
+	if err != nil {
+		return nil, err
+	}
+	if silence != nil && silence.ID != "" {
+		// The report is shared with the result, so it records the expiry
+		defer d.expireSilence(ctx, target, silence)
+	}
+
+	schemaReport := d.negotiateSchema(ctx, target, req)
+
+	dashboard, overwrite := req.Dashboard, req.Overwrite
+	var (
+		merge           *MergeReport
+		expectedVersion int
+	)
+	if req.BaseVersion > 0 {
+		dashboard, merge, err = d.mergeConcurrentEdits(ctx, target, req)
+		if err != nil {
+			return nil, err
+		}
+		// Save against the version the dashboard (or the merge) is based on;
+		// Grafana rejects the save if the dashboard changes in the meantime
+		overwrite = false
+		expectedVersion = req.BaseVersion
+		if merge != nil {
+			expectedVersion = merge.CurrentVersion
+		}
+	}
+
+	d.logger.Info("Deploying dashboard to Grafana",
+		zap.String("grafana_url", target.GrafanaURL),
+		zap.String("folder_uid", folderUID),
+		zap.Bool("overwrite", overwrite),
+		zap.Int("expected_version", expectedVersion))
+
+	save := grafana.Dashboard{
+		Dashboard: dashboard,
+		FolderUID: folderUID,
+		Message:   d.versionMessage(message, req.Provenance),
+		Overwrite: overwrite,
+	}
+	var resp *grafana.DashboardResponse
+	if expectedVersion > 0 {
+		resp, err = d.grafanaSvc.UpdateDashboard(ctx, save, expectedVersion, target.GrafanaURL, target.APIKey)
+	} else {
+		resp, err = d.grafanaSvc.CreateDashboard(ctx, save, target.GrafanaURL, target.APIKey)
+	}
+	var conflict *grafana.SaveConflictError
+	if errors.As(err, &conflict) {
+		result := d.conflictResult(target, req, folderUID, expectedVersion, conflict)
+		result.Silence = silence
+		result.Schema = schemaReport
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy dashboard to Grafana: %w", err)
+	}
+
+	d.logger.Info("Dashboard deployed successfully",
+		zap.String("grafana_url", target.GrafanaURL),
+		zap.String("dashboard_uid", resp.UID),
+		zap.Int("dashboard_id", resp.ID),
+		zap.String("dashboard_url", resp.URL))
+
+	d.recordInventory(ctx, target, folderUID, dashboard, req.Provenance, resp)
+
+	status := "deployed"
+	if merge != nil {
+		status = "merged"
+	}
+
+	return &Result{
+		Status:     status,
+		GrafanaURL: target.GrafanaURL,
+		Stack:      target.Stack,
+		Dashboard: DashboardRef{
+			ID:      resp.ID,
+			UID:     resp.UID,
+			URL:     resp.URL,
+			Version: resp.Version,
+			Slug:    resp.Slug,
+		},
+		FolderUID:      folderUID,
+		FolderPath:     req.FolderPath,
+		CreatedFolders: created,
+		Merge:          merge,
+		Provenance:     req.Provenance,
+		Silence:        silence,
+		Schema:         schemaReport,
+		Message:        message,
+	}, nil
+}
+
+// Delete deletes a dashboard of the given folder, if the caller's role may
+// write to that folder
+func (d *Deployer) Delete(ctx context.Context, target *Target, uid, folderUID string) error {
+	if err := d.authorizeFolder(ctx, target, Request{FolderUID: folderUID}); err != nil {
+		return err
+	}
+	if err := d.grafanaSvc.DeleteDashboard(ctx, uid, target.GrafanaURL, target.APIKey); err != nil {
+		return err
+	}
+	if d.inventory != nil {
+		if err := d.inventory.Forget(ctx, target.GrafanaURL, d.orgID(ctx, target), uid); err != nil {
+			d.logger.Warn("failed to remove the deleted dashboard from the inventory",
+				zap.String("dashboard_uid", uid),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// recordInventory records a saved dashboard in the inventory. The content
+// hash is taken from the model Grafana stored rather than the one sent, as
+// Grafana assigns panel ids and migrates the schema on save, so drift only
+// shows edits made afterwards. The dashboard is in Grafana by then, so a
+// failure to record it is only logged.
+func (d *Deployer) recordInventory(ctx context.Context, target *Target, folderUID string, dashboard map[string]any, provenance *Provenance, resp *grafana.DashboardResponse) {
+	if d.inventory == nil {
+		return
+	}
+	stored := dashboard
+	if current, err := d.grafanaSvc.GetDashboard(ctx, resp.UID, target.GrafanaURL, target.APIKey); err != nil {
+		d.logger.Warn("failed to fetch the saved dashboard, recording the hash of the model sent",
+			zap.String("dashboard_uid", resp.UID),
+			zap.Error(err))
+	} else if current != nil {
+		stored = current.Dashboard
+	}
+	hash, err := inventory.Hash(stored)
+	if err == nil {
+		title, _ := dashboard["title"].(string)
+		record := inventory.Dashboard{
+			UID:        resp.UID,
+			Title:      title,
+			GrafanaURL: target.GrafanaURL,
+			OrgID:      d.orgID(ctx, target),
+			Stack:      target.Stack,
+			FolderUID:  folderUID,
+			Hash:       hash,
+			Version:    resp.Version,
+		}
+		if provenance != nil {
+			record.Template = provenance.Template
+			record.Prompt = provenance.Prompt
+			record.RequestID = provenance.RequestID
+		}
+		err = d.inventory.Record(ctx, record)
+	}
+	if err != nil {
+		d.logger.Warn("failed to record the deployed dashboard in the inventory",
+			zap.String("dashboard_uid", resp.UID),
+			zap.Error(err))
+	}
+}
+
+// orgID returns the Grafana organization a call runs in: the one selected
+// with org_id, otherwise the token's current organization. It is 0 when
+// Grafana cannot tell.
+func (d *Deployer) orgID(ctx context.Context, target *Target) int {
+	if orgID := grafana.OrgIDFromContext(ctx); orgID > 0 {
+		return orgID
+	}
+	user, err := d.grafanaSvc.GetCurrentUser(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil || user == nil {
+		d.logger.Warn("failed to determine the current Grafana organization", zap.Error(err))
+		return 0
+	}
+	return user.OrgID
+}
+
+// dashboardTags reads the tags of a dashboard model, decoded from JSON or
+// built by the agent
+func dashboardTags(dashboard map[string]any) []string {
+	switch tags := dashboard["tags"].(type) {
+	case []string:
+		return tags
+	case []any:
+		out := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// versionMessage appends the provenance of a change to its version message,
+// one "Key: value" line per field
+func (d *Deployer) versionMessage(message string, provenance *Provenance) string {
+	if provenance == nil {
+		return message
+	}
+
+	lines := []string{message, ""}
+	if provenance.Prompt != "" {
+		lines = append(lines, "Prompt: "+provenance.Prompt)
+	}
+	if provenance.Template != "" {
+		lines = append(lines, "Template: "+provenance.Template)
+	}
+	if metrics := provenance.Metrics; len(metrics) > 0 {
+		line := "Metrics: " + strings.Join(metrics[:min(len(metrics), maxProvenanceMetrics)], ", ")
+		if len(metrics) > maxProvenanceMetrics {
+			line += fmt.Sprintf(" (+%d more)", len(metrics)-maxProvenanceMetrics)
+		}
+		lines = append(lines, line)
+	}
+	if d.agentVersion != "" {
+		lines = append(lines, "Agent: "+d.agentVersion)
+	}
+	if provenance.RequestID != "" {
+		lines = append(lines, "Request: "+provenance.RequestID)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// conflictResult reports a save Grafana rejected with 412 so the caller can
+// relay it to the user instead of failing the deployment
+func (d *Deployer) conflictResult(target *Target, req Request, folderUID string, expectedVersion int, conflict *grafana.SaveConflictError) *Result {
+	uid, _ := req.Dashboard["uid"].(string)
+
+	d.logger.Warn("Grafana rejected the dashboard save",
+		zap.String("grafana_url", target.GrafanaURL),
+		zap.String("dashboard_uid", uid),
+		zap.String("reason", conflict.Status),
+		zap.Int("expected_version", expectedVersion))
+
+	return &Result{
+		Status:     "conflict",
+		GrafanaURL: target.GrafanaURL,
+		Stack:      target.Stack,
+		Dashboard:  DashboardRef{UID: uid, Version: expectedVersion},
+		FolderUID:  folderUID,
+		FolderPath: req.FolderPath,
+		Conflict: &ConflictReport{
+			Reason:          conflict.Status,
+			Detail:          conflict.Message,
+			ExpectedVersion: expectedVersion,
+		},
+		Message: "Dashboard was not saved: " + conflict.Message,
+	}
+}
+
+// mergeConcurrentEdits merges the agent's changes since req.BaseVersion into
+// the currently deployed dashboard when someone else saved it in between.
+// The report is nil when the dashboard is unchanged since BaseVersion (or was
+// deleted) and req.Dashboard can be deployed as is.
+func (d *Deployer) mergeConcurrentEdits(ctx context.Context, target *Target, req Request) (map[string]any, *MergeReport, error) {
+	uid, _ := req.Dashboard["uid"].(string)
+	if uid == "" {
+		return nil, nil, ErrMergeWithoutUID
+	}
+
+	current, err := d.grafanaSvc.GetDashboard(ctx, uid, target.GrafanaURL, target.APIKey)
+	if errors.Is(err, grafana.ErrDashboardNotFound) {
+		return req.Dashboard, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch the deployed dashboard: %w", err)
+	}
+
+	currentVersion := dashboardVersion(current.Dashboard)
+	if currentVersion == req.BaseVersion {
+		return req.Dashboard, nil, nil
+	}
+
+	base, err := d.grafanaSvc.GetDashboardVersion(ctx, uid, req.BaseVersion, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch base version %d: %w", req.BaseVersion, err)
+	}
+
+	merged, conflicts := diff.Merge3(base.Data, req.Dashboard, current.Dashboard)
+	merged["uid"] = uid
+
+	d.logger.Info("Merged concurrent dashboard edits",
+		zap.String("dashboard_uid", uid),
+		zap.Int("base_version", req.BaseVersion),
+		zap.Int("current_version", currentVersion),
+		zap.Int("conflicts", len(conflicts)))
+
+	return merged, &MergeReport{
+		BaseVersion:    req.BaseVersion,
+		CurrentVersion: currentVersion,
+		Conflicts:      conflicts,
+	}, nil
+}
+
+// dashboardVersion reads the version of a dashboard model decoded from JSON
+func dashboardVersion(dashboard map[string]any) int {
+	switch v := dashboard["version"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// resolveFolder resolves the destination folder UID: a folder path is
+// walked (and created where missing), otherwise the explicit folder UID or a
+// folderUid embedded in the dashboard model is used; empty means the General
+// folder
+func (d *Deployer) resolveFolder(ctx context.Context, target *Target, req Request) (string, []string, error) {
+	segments := splitFolderPath(req.FolderPath)
+	if len(segments) == 0 {
+		return resolveFolderUID(req), nil, nil
+	}
+	if strings.TrimSpace(req.FolderUID) != "" {
+		return "", nil, ErrFolderConflict
+	}
+
+	var created []string
+	parentUID := ""
+	for i, title := range segments {
+		folders, err := d.grafanaSvc.ListFolders(ctx, parentUID, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve folder %q: %w", strings.Join(segments[:i+1], "/"), err)
+		}
+
+		uid := ""
+		for _, folder := range folders {
+			if strings.EqualFold(folder.Title, title) {
+				uid = folder.UID
+				break
+			}
+		}
+
+		if uid == "" {
+			folder, err := d.grafanaSvc.CreateFolder(ctx, title, parentUID, target.GrafanaURL, target.APIKey)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to create folder %q: %w", strings.Join(segments[:i+1], "/"), err)
+			}
+			uid = folder.UID
+			created = append(created, strings.Join(segments[:i+1], "/"))
+			d.logger.Info("Created missing folder",
+				zap.String("path", strings.Join(segments[:i+1], "/")),
+				zap.String("uid", uid))
+		}
+
+		parentUID = uid
+	}
+
+	return parentUID, created, nil
+}
+
+// authorizeFolder checks that the caller's role may write to the destination
+// folder before any folder is created. The destination is resolved to both
+// its path and the UIDs of its existing folders, so roles can be scoped by
+// folder path or by the UID of the destination or any of its ancestors.
+func (d *Deployer) authorizeFolder(ctx context.Context, target *Target, req Request) error {
+	scope, ok := authz.FromContext(ctx)
+	if !ok || !scope.RestrictsFolders() {
+		return nil
+	}
+
+	segments := splitFolderPath(req.FolderPath)
+	if len(segments) > 0 {
+		folderUIDs, err := d.existingFolderUIDs(ctx, target, segments)
+		if err != nil {
+			return err
+		}
+		return scope.AuthorizeFolder(strings.Join(segments, "/"), folderUIDs)
+	}
+
+	folderUID := resolveFolderUID(req)
+	if folderUID == "" {
+		return scope.AuthorizeFolder("", nil)
+	}
+	folderPath, folderUIDs, err := d.folderAncestry(ctx, target, folderUID)
+	if err != nil {
+		return err
+	}
+	return scope.AuthorizeFolder(folderPath, folderUIDs)
+}
+
+// folderAncestry returns the slash-separated titles from the top-level folder
+// down to the folder with the given UID, along with the UIDs of that folder
+// and its ancestors, nearest first
+func (d *Deployer) folderAncestry(ctx context.Context, target *Target, uid string) (string, []string, error) {
+	var titles, uids []string
+	for depth := 0; uid != "" && depth < maxFolderDepth; depth++ {
+		folder, err := d.grafanaSvc.GetFolder(ctx, uid, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve folder %s: %w", uid, err)
+		}
+		titles = append([]string{folder.Title}, titles...)
+		uids = append(uids, uid)
+		uid = folder.ParentUID
+	}
+	return strings.Join(titles, "/"), uids, nil
+}
+
+// existingFolderUIDs walks a folder path without creating anything and
+// returns the UIDs of its folders that already exist, nearest first
+func (d *Deployer) existingFolderUIDs(ctx context.Context, target *Target, segments []string) ([]string, error) {
+	var uids []string
+	parentUID := ""
+	for i, title := range segments {
+		folders, err := d.grafanaSvc.ListFolders(ctx, parentUID, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve folder %q: %w", strings.Join(segments[:i+1], "/"), err)
+		}
+
+		uid := ""
+		for _, folder := range folders {
+			if strings.EqualFold(folder.Title, title) {
+				uid = folder.UID
+				break
+			}
+		}
+		if uid == "" {
+			break
+		}
+		uids = append([]string{uid}, uids...)
+		parentUID = uid
+	}
+	return uids, nil
+}
+
+// resolveFolderUID picks the explicit folder UID, falling back to a folderUid
+// embedded in the dashboard model; empty means the General folder
+func resolveFolderUID(req Request) string {
+	if uid := strings.TrimSpace(req.FolderUID); uid != "" {
+		return uid
+	}
+	if uid, ok := req.Dashboard["folderUid"].(string); ok {
+		return strings.TrimSpace(uid)
+	}
+	return ""
+}
+
+// splitFolderPath splits "Platform/Payments" into its folder titles,
+// honouring "\/" as a literal slash and dropping empty segments
+func splitFolderPath(path string) []string {
+	var segments []string
+	var current strings.Builder
+
+	flush := func() {
+		if title := strings.TrimSpace(current.String()); title != "" {
+			segments = append(segments, title)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '/':
+			current.WriteByte('/')
+			i++
+		case path[i] == '/':
+			flush()
+		default:
+			current.WriteByte(path[i])
+		}
+	}
+	flush()
+
+	return segments
+}
@@ -0,0 +1,554 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+	inventoryfakes "github.com/inference-gateway/grafana-agent/internal/inventory/inventoryfakes"
+	naming "github.com/inference-gateway/grafana-agent/internal/naming"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+type stubGrafana struct {
+	grafana.Grafana
+	folders    []grafana.Folder
+	deployed   grafana.Dashboard
+	grafanaURL string
+	apiKey     string
+	err        error
+	alertRules []grafana.AlertRule
+	silences   []grafana.Silence
+	expired    []string
+	user       *grafana.CurrentUser
+	version    string
+}
+
+func (s *stubGrafana) CreateDashboard(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+	s.deployed, s.grafanaURL, s.apiKey = dashboard, grafanaURL, apiKey
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &grafana.DashboardResponse{ID: 7, UID: "abc", URL: "/d/abc/test", Version: 2, Slug: "test"}, nil
+}
+
+// GetDashboard returns the last dashboard saved, stamped like Grafana stores it
+func (s *stubGrafana) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+	stored := maps.Clone(s.deployed.Dashboard)
+	stored["id"], stored["uid"], stored["version"], stored["schemaVersion"] = 7, uid, 2, 39
+	return &grafana.Dashboard{Dashboard: stored, FolderUID: s.deployed.FolderUID}, nil
+}
+
+func (s *stubGrafana) UpdateDashboard(ctx context.Context, dashboard grafana.Dashboard, expectedVersion int, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+	dashboard.Dashboard["version"] = expectedVersion
+	return s.CreateDashboard(ctx, dashboard, grafanaURL, apiKey)
+}
+
+func (s *stubGrafana) ListFolders(ctx context.Context, parentUID, grafanaURL, apiKey string) ([]grafana.Folder, error) {
+	var folders []grafana.Folder
+	for _, folder := range s.folders {
+		if folder.ParentUID == parentUID {
+			folders = append(folders, folder)
+		}
+	}
+	return folders, nil
+}
+
+func (s *stubGrafana) GetFolder(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Folder, error) {
+	for _, folder := range s.folders {
+		if folder.UID == uid {
+			return &folder, nil
+		}
+	}
+	return nil, fmt.Errorf("grafana returned status 404")
+}
+
+func (s *stubGrafana) CreateFolder(ctx context.Context, title, parentUID, grafanaURL, apiKey string) (*grafana.Folder, error) {
+	folder := grafana.Folder{UID: title + "-uid", Title: title, ParentUID: parentUID}
+	s.folders = append(s.folders, folder)
+	return &folder, nil
+}
+
+type stubCloud struct {
+	grafanacloud.GrafanaCloud
+	requested string
+}
+
+func (s *stubCloud) StackCredentials(ctx context.Context, query string) (*grafanacloud.StackCredentials, error) {
+	s.requested = query
+	if query != "prod" {
+		return nil, grafanacloud.ErrStackNotFound
+	}
+	return &grafanacloud.StackCredentials{
+		Stack: grafanacloud.Stack{Slug: "acmeprod", URL: "https://acmeprod.grafana.net"},
+		Token: "stack-token",
+	}, nil
+}
+
+func TestResolveTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *config.GrafanaConfig
+		grafanaURL  string
+		expectedErr error
+		expectedURL string
+	}{
+		{name: "deploy disabled", config: &config.GrafanaConfig{DeployEnabled: false, URL: "http://g", APIKey: "k"}, expectedErr: ErrDeployDisabled},
+		{name: "missing url", config: &config.GrafanaConfig{DeployEnabled: true, APIKey: "k"}, expectedErr: ErrMissingURL},
+		{name: "missing api key", config: &config.GrafanaConfig{DeployEnabled: true, URL: "http://g"}, expectedErr: ErrMissingAPIKey},
+		{name: "configured url", config: &config.GrafanaConfig{DeployEnabled: true, URL: "http://g", APIKey: "k"}, expectedURL: "http://g"},
+		{name: "argument overrides config", config: &config.GrafanaConfig{DeployEnabled: true, URL: "http://g", APIKey: "k"}, grafanaURL: " http://other ", expectedURL: "http://other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := NewDeployer(zap.NewNop(), &stubGrafana{}, nil, tt.config, Options{}).ResolveTarget(context.Background(), tt.grafanaURL, "")
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if target.GrafanaURL != tt.expectedURL || target.APIKey != "k" {
+				t.Errorf("Unexpected target %+v", target)
+			}
+		})
+	}
+}
+
+func TestResolveTargetOAuth(t *testing.T) {
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://g", OAuth: config.GrafanaOAuthConfig{TokenURL: "http://idp/token", Header: "Authorization"}}
+
+	target, err := NewDeployer(zap.NewNop(), &stubGrafana{}, nil, cfg, Options{}).ResolveTarget(context.Background(), "", "")
+	if err != nil {
+		t.Fatalf("Expected OAuth to stand in for the API key, got %v", err)
+	}
+	if target.GrafanaURL != "http://g" || target.APIKey != "" {
+		t.Errorf("Unexpected target %+v", target)
+	}
+
+	cfg.OAuth.Header = "Proxy-Authorization"
+	if _, err := NewDeployer(zap.NewNop(), &stubGrafana{}, nil, cfg, Options{}).ResolveTarget(context.Background(), "", ""); !errors.Is(err, ErrMissingAPIKey) {
+		t.Errorf("Expected ErrMissingAPIKey with a proxy-only token, got %v", err)
+	}
+}
+
+func TestResolveTargetStack(t *testing.T) {
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://g", APIKey: "k"}
+
+	t.Run("stack resolves url and token", func(t *testing.T) {
+		cloud := &stubCloud{}
+		target, err := NewDeployer(zap.NewNop(), &stubGrafana{}, cloud, cfg, Options{}).ResolveTarget(context.Background(), "", " prod ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cloud.requested != "prod" {
+			t.Errorf("Expected trimmed stack name, got %q", cloud.requested)
+		}
+		if target.GrafanaURL != "https://acmeprod.grafana.net" || target.APIKey != "stack-token" || target.Stack != "acmeprod" {
+			t.Errorf("Unexpected target %+v", target)
+		}
+	})
+
+	t.Run("stack and url conflict", func(t *testing.T) {
+		_, err := NewDeployer(zap.NewNop(), &stubGrafana{}, &stubCloud{}, cfg, Options{}).ResolveTarget(context.Background(), "http://other", "prod")
+		if !errors.Is(err, ErrStackConflict) {
+			t.Errorf("Expected ErrStackConflict, got %v", err)
+		}
+	})
+
+	t.Run("cloud not configured", func(t *testing.T) {
+		_, err := NewDeployer(zap.NewNop(), &stubGrafana{}, nil, cfg, Options{}).ResolveTarget(context.Background(), "", "prod")
+		if !errors.Is(err, grafanacloud.ErrNotConfigured) {
+			t.Errorf("Expected ErrNotConfigured, got %v", err)
+		}
+	})
+
+	t.Run("deploy disabled wins", func(t *testing.T) {
+		_, err := NewDeployer(zap.NewNop(), &stubGrafana{}, &stubCloud{}, &config.GrafanaConfig{}, Options{}).ResolveTarget(context.Background(), "", "prod")
+		if !errors.Is(err, ErrDeployDisabled) {
+			t.Errorf("Expected ErrDeployDisabled, got %v", err)
+		}
+	})
+}
+
+func TestDeploy(t *testing.T) {
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana", APIKey: "key"}
+
+	t.Run("deploys with resolved folder and default message", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard: map[string]any{"title": "Test", "folderUid": "embedded"},
+			Overwrite: true,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stub.deployed.FolderUID != "embedded" || stub.deployed.Message != DefaultMessage || !stub.deployed.Overwrite {
+			t.Errorf("Unexpected deployed dashboard %+v", stub.deployed)
+		}
+		if stub.grafanaURL != "http://grafana" || stub.apiKey != "key" {
+			t.Errorf("Unexpected target %s/%s", stub.grafanaURL, stub.apiKey)
+		}
+		if result.Status != "deployed" || result.Dashboard.UID != "abc" || result.Dashboard.Version != 2 || result.FolderUID != "embedded" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+
+	t.Run("resolved target is used as is", func(t *testing.T) {
+		stub, cloud := &stubGrafana{}, &stubCloud{}
+		deployer := NewDeployer(zap.NewNop(), stub, cloud, cfg, Options{})
+		target, err := deployer.ResolveTarget(context.Background(), "", "prod")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		cloud.requested = ""
+		result, err := deployer.Deploy(context.Background(), Request{Dashboard: map[string]any{"title": "Test"}, Target: target})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cloud.requested != "" {
+			t.Errorf("Expected the stack not to be resolved again, got a request for %q", cloud.requested)
+		}
+		if stub.grafanaURL != "https://acmeprod.grafana.net" || stub.apiKey != "stack-token" || result.Stack != "acmeprod" {
+			t.Errorf("Unexpected target %s/%s (%s)", stub.grafanaURL, stub.apiKey, result.Stack)
+		}
+	})
+
+	t.Run("explicit folder wins", func(t *testing.T) {
+		stub := &stubGrafana{}
+		_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard: map[string]any{"folderUid": "embedded"},
+			FolderUID: "explicit",
+			Message:   "custom",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stub.deployed.FolderUID != "explicit" || stub.deployed.Message != "custom" {
+			t.Errorf("Unexpected deployed dashboard %+v", stub.deployed)
+		}
+	})
+
+	t.Run("folder path is created where missing", func(t *testing.T) {
+		stub := &stubGrafana{folders: []grafana.Folder{{UID: "existing", Title: "Platform"}}}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:  map[string]any{"folderUid": "ignored"},
+			FolderPath: "Platform/Payments/ Checkout ",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stub.deployed.FolderUID != "Checkout-uid" || result.FolderUID != "Checkout-uid" {
+			t.Errorf("Expected dashboard in Checkout folder, got %s", stub.deployed.FolderUID)
+		}
+		if len(result.CreatedFolders) != 2 || result.CreatedFolders[0] != "Platform/Payments" || result.CreatedFolders[1] != "Platform/Payments/Checkout" {
+			t.Errorf("Unexpected created folders %v", result.CreatedFolders)
+		}
+		if stub.folders[1].ParentUID != "existing" || stub.folders[2].ParentUID != "Payments-uid" {
+			t.Errorf("Expected nested folder chain, got %+v", stub.folders)
+		}
+	})
+
+	t.Run("folder path and uid conflict", func(t *testing.T) {
+		_, err := NewDeployer(zap.NewNop(), &stubGrafana{}, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:  map[string]any{},
+			FolderPath: "Platform",
+			FolderUID:  "abc",
+		})
+		if !errors.Is(err, ErrFolderConflict) {
+			t.Errorf("Expected ErrFolderConflict, got %v", err)
+		}
+	})
+
+	t.Run("deploys to a cloud stack", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, &stubCloud{}, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard: map[string]any{},
+			Stack:     "prod",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stub.grafanaURL != "https://acmeprod.grafana.net" || stub.apiKey != "stack-token" {
+			t.Errorf("Unexpected target %s/%s", stub.grafanaURL, stub.apiKey)
+		}
+		if result.Stack != "acmeprod" || result.GrafanaURL != "https://acmeprod.grafana.net" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+
+	t.Run("role scoped to folders", func(t *testing.T) {
+		policy := &authz.Policy{Roles: map[string]authz.Role{"dev": {Tools: []string{authz.Wildcard}, Folders: []string{"Sandbox"}}}}
+		ctx := authz.NewContext(context.Background(), policy, "dev")
+		folders := []grafana.Folder{
+			{UID: "sandbox-uid", Title: "Sandbox"},
+			{UID: "team-uid", Title: "Team", ParentUID: "sandbox-uid"},
+			{UID: "prod-uid", Title: "Production"},
+		}
+
+		for _, req := range []Request{
+			{Dashboard: map[string]any{}, FolderPath: "sandbox/Experiments"},
+			{Dashboard: map[string]any{}, FolderUID: "team-uid"},
+		} {
+			stub := &stubGrafana{folders: folders}
+			if _, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(ctx, req); err != nil {
+				t.Errorf("Unexpected error for %+v: %v", req, err)
+			}
+		}
+
+		for _, req := range []Request{
+			{Dashboard: map[string]any{}, FolderPath: "Production/Experiments"},
+			{Dashboard: map[string]any{"folderUid": "prod-uid"}},
+			{Dashboard: map[string]any{}},
+		} {
+			stub := &stubGrafana{folders: folders}
+			_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(ctx, req)
+			if !errors.Is(err, authz.ErrForbidden) {
+				t.Errorf("Expected ErrForbidden for %+v, got %v", req, err)
+			}
+			if len(stub.folders) != len(folders) || stub.deployed.Dashboard != nil {
+				t.Errorf("Expected nothing written for %+v", req)
+			}
+		}
+	})
+
+	t.Run("role scoped to a folder UID", func(t *testing.T) {
+		policy := &authz.Policy{Roles: map[string]authz.Role{"dev": {Tools: []string{authz.Wildcard}, Folders: []string{"platform-uid"}}}}
+		ctx := authz.NewContext(context.Background(), policy, "dev")
+		folders := []grafana.Folder{
+			{UID: "platform-uid", Title: "Platform"},
+			{UID: "team-uid", Title: "Team", ParentUID: "platform-uid"},
+			{UID: "prod-uid", Title: "Production"},
+		}
+
+		for _, req := range []Request{
+			{Dashboard: map[string]any{}, FolderUID: "team-uid"},
+			{Dashboard: map[string]any{}, FolderPath: "Platform/Team/Payments"},
+		} {
+			stub := &stubGrafana{folders: folders}
+			if _, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(ctx, req); err != nil {
+				t.Errorf("Expected subfolders of the allowed folder to be writable for %+v, got %v", req, err)
+			}
+		}
+
+		stub := &stubGrafana{folders: folders}
+		_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(ctx, Request{Dashboard: map[string]any{}, FolderPath: "Production/Platform"})
+		if !errors.Is(err, authz.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden, got %v", err)
+		}
+		if len(stub.folders) != len(folders) {
+			t.Error("Expected no folder to be created")
+		}
+	})
+
+	t.Run("records the dashboard in the inventory", func(t *testing.T) {
+		store := &inventoryfakes.FakeStore{}
+		store.RecordReturns(errors.New("database is locked"))
+
+		dashboard := map[string]any{"title": "Checkout"}
+		stub := &stubGrafana{user: &grafana.CurrentUser{OrgID: 3}}
+		_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{Inventory: store}).Deploy(context.Background(), Request{
+			Dashboard:  dashboard,
+			FolderUID:  "team",
+			Provenance: &Provenance{Prompt: "checkout latency", Template: "service_overview", RequestID: "task-1"},
+		})
+		if err != nil {
+			t.Fatalf("Expected an inventory error not to fail the deployment, got %v", err)
+		}
+		if store.RecordCallCount() != 1 {
+			t.Fatalf("Expected one record, got %d", store.RecordCallCount())
+		}
+		_, record := store.RecordArgsForCall(0)
+		hash, _ := inventory.Hash(map[string]any{"id": 7, "uid": "abc", "version": 2, "schemaVersion": 39, "title": "Checkout"})
+		if record.UID != "abc" || record.Title != "Checkout" || record.GrafanaURL != "http://grafana" || record.OrgID != 3 || record.FolderUID != "team" || record.Version != 2 {
+			t.Errorf("Unexpected record %+v", record)
+		}
+		if record.Template != "service_overview" || record.Prompt != "checkout latency" || record.RequestID != "task-1" || record.Hash != hash {
+			t.Errorf("Unexpected record provenance %+v", record)
+		}
+	})
+
+	t.Run("titles follow the naming convention", func(t *testing.T) {
+		cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana", APIKey: "key",
+			Naming: config.GrafanaNamingConfig{Prefix: "[Platform]", ForbiddenWords: []string{"tmp"}}}
+
+		stub := &stubGrafana{}
+		_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{Dashboard: map[string]any{"title": "Checkout tmp"}})
+		var lintErr *naming.LintError
+		if !errors.As(err, &lintErr) || len(lintErr.Violations) != 2 || lintErr.Suggestion != "[Platform] Checkout" {
+			t.Errorf("Expected a lint error, got %v", err)
+		}
+		if stub.deployed.Dashboard != nil {
+			t.Error("Expected nothing deployed")
+		}
+
+		if _, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{Dashboard: map[string]any{"title": "[Platform] Checkout"}}); err != nil {
+			t.Errorf("Unexpected error for a conforming title: %v", err)
+		}
+	})
+
+	t.Run("deploys to a stack without configuration", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, &stubCloud{}, nil, Options{}).Deploy(context.Background(), Request{
+			Stack:     "prod",
+			Dashboard: map[string]any{"title": "checkout overview", "tags": []any{"legacy"}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Stack != "acmeprod" || stub.deployed.Dashboard["title"] != "checkout overview" {
+			t.Errorf("Unexpected deployment %+v", result)
+		}
+	})
+
+	t.Run("keeps tags outside the tag taxonomy", func(t *testing.T) {
+		cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana", APIKey: "key",
+			Tags: config.GrafanaTagsConfig{Taxonomy: []string{"env", "team"}, Mode: taxonomy.ModeReject}}
+
+		stub := &stubGrafana{}
+		_, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard: map[string]any{"title": "Checkout", "tags": []any{"Team=Payments", "legacy"}},
+		})
+		if err != nil {
+			t.Fatalf("Expected the dashboard deployed as given, got %v", err)
+		}
+		if tags, _ := stub.deployed.Dashboard["tags"].([]any); len(tags) != 2 || tags[1] != "legacy" {
+			t.Errorf("Expected the tags kept, got %v", stub.deployed.Dashboard["tags"])
+		}
+	})
+
+	t.Run("grafana error is wrapped", func(t *testing.T) {
+		_, err := NewDeployer(zap.NewNop(), &stubGrafana{err: errors.New("boom")}, nil, cfg, Options{}).Deploy(context.Background(), Request{Dashboard: map[string]any{}})
+		if err == nil || err.Error() != "failed to deploy dashboard to Grafana: boom" {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+}
+
+func TestSplitFolderPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "", expected: nil},
+		{path: "Platform", expected: []string{"Platform"}},
+		{path: "/Platform//Payments/", expected: []string{"Platform", "Payments"}},
+		{path: `Teams/CI\/CD`, expected: []string{"Teams", "CI/CD"}},
+	}
+
+	for _, tt := range tests {
+		got := splitFolderPath(tt.path)
+		if len(got) != len(tt.expected) {
+			t.Errorf("splitFolderPath(%q) = %v, want %v", tt.path, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("splitFolderPath(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		}
+	}
+}
+
+// mergeStub serves a deployed dashboard and its version history
+type mergeStub struct {
+	stubGrafana
+	current  map[string]any
+	versions map[int]map[string]any
+}
+
+func (s *mergeStub) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+	if s.current == nil {
+		return nil, grafana.ErrDashboardNotFound
+	}
+	return &grafana.Dashboard{Dashboard: s.current}, nil
+}
+
+func (s *mergeStub) GetDashboardVersion(ctx context.Context, uid string, version int, grafanaURL, apiKey string) (*grafana.DashboardVersion, error) {
+	return &grafana.DashboardVersion{Version: version, Data: s.versions[version]}, nil
+}
+
+func TestDeployMergesConcurrentEdits(t *testing.T) {
+	base := map[string]any{"uid": "abc", "version": 2.0, "title": "Checkout", "refresh": "30s"}
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://g", APIKey: "k"}
+
+	t.Run("edited since the base version", func(t *testing.T) {
+		svc := &mergeStub{
+			current:  map[string]any{"uid": "abc", "version": 4.0, "title": "Checkout", "refresh": "1m"},
+			versions: map[int]map[string]any{2: base},
+		}
+		deployer := NewDeployer(zap.NewNop(), svc, nil, cfg, Options{})
+
+		result, err := deployer.Deploy(context.Background(), Request{
+			Dashboard:   map[string]any{"uid": "abc", "title": "Checkout v2", "refresh": "30s"},
+			Overwrite:   true,
+			BaseVersion: 2,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != "merged" || result.Merge == nil || result.Merge.CurrentVersion != 4 || len(result.Merge.Conflicts) != 0 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+
+		deployed := svc.deployed
+		if deployed.Dashboard["title"] != "Checkout v2" || deployed.Dashboard["refresh"] != "1m" {
+			t.Errorf("expected both edits to be kept, got %v", deployed.Dashboard)
+		}
+		if deployed.Overwrite || deployed.Dashboard["version"] != 4 {
+			t.Errorf("merged dashboards must be saved against the current version, got overwrite=%v version=%v", deployed.Overwrite, deployed.Dashboard["version"])
+		}
+	})
+
+	t.Run("unchanged since the base version", func(t *testing.T) {
+		svc := &mergeStub{current: base}
+		deployer := NewDeployer(zap.NewNop(), svc, nil, cfg, Options{})
+
+		dashboard := map[string]any{"uid": "abc", "title": "Checkout v2"}
+		result, err := deployer.Deploy(context.Background(), Request{Dashboard: dashboard, Overwrite: true, BaseVersion: 2})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != "deployed" || result.Merge != nil {
+			t.Errorf("expected a plain deployment, got %+v", result)
+		}
+		if svc.deployed.Overwrite || svc.deployed.Dashboard["version"] != 2 {
+			t.Errorf("expected a save against the base version, got overwrite=%v version=%v", svc.deployed.Overwrite, svc.deployed.Dashboard["version"])
+		}
+	})
+
+	t.Run("changed again before the save", func(t *testing.T) {
+		svc := &mergeStub{current: base}
+		svc.err = &grafana.SaveConflictError{Status: "version-mismatch", Message: "The dashboard has been changed by someone else"}
+		deployer := NewDeployer(zap.NewNop(), svc, nil, cfg, Options{})
+
+		result, err := deployer.Deploy(context.Background(), Request{Dashboard: map[string]any{"uid": "abc", "title": "Checkout v2"}, BaseVersion: 2})
+		if err != nil {
+			t.Fatalf("a conflict should be reported in the result, got %v", err)
+		}
+		if result.Status != "conflict" || result.Conflict == nil || result.Conflict.Reason != "version-mismatch" || result.Conflict.ExpectedVersion != 2 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("requires a uid", func(t *testing.T) {
+		deployer := NewDeployer(zap.NewNop(), &mergeStub{}, nil, cfg, Options{})
+		_, err := deployer.Deploy(context.Background(), Request{Dashboard: map[string]any{"title": "x"}, BaseVersion: 2})
+		if !errors.Is(err, ErrMergeWithoutUID) {
+			t.Errorf("expected ErrMergeWithoutUID, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// dashboardUIDAnnotation links a Grafana-managed alert rule to a dashboard
+const dashboardUIDAnnotation = "__dashboardUid__"
+
+// ErrInvalidMaintenanceWindow is returned for a window that is not
+// "<start>/<end>" or "<start>/<duration>"
+var ErrInvalidMaintenanceWindow = errors.New("maintenance window must be \"<start>/<end>\" or \"<start>/<duration>\" with RFC 3339 times, e.g. 2026-01-10T22:00:00Z/2h")
+
+// Maintenance is a declared maintenance window. A deployment inside the
+// window silences the affected alerts while the dashboard is saved.
+type Maintenance struct {
+	Start time.Time
+	End   time.Time
+	// Labels select the alerts to silence; when empty, the alert rules
+	// linked to the dashboard are silenced
+	Labels map[string]string
+}
+
+// SilenceReport describes the silence created for a deployment inside a
+// maintenance window
+type SilenceReport struct {
+	// ID is empty when there was nothing to silence
+	ID       string                   `json:"id,omitempty"`
+	Matchers []grafana.SilenceMatcher `json:"matchers,omitempty"`
+	EndsAt   time.Time                `json:"ends_at"`
+	// Expired is set once the silence was removed after the deployment;
+	// otherwise it lapses at EndsAt, the end of the maintenance window
+	Expired bool   `json:"expired"`
+	Note    string `json:"note,omitempty"`
+}
+
+// ParseMaintenanceWindow parses a maintenance window given as an RFC 3339
+// start and either an end time or a duration, separated by a slash
+func ParseMaintenanceWindow(window string) (*Maintenance, error) {
+	startText, endText, ok := strings.Cut(strings.TrimSpace(window), "/")
+	if !ok {
+		return nil, ErrInvalidMaintenanceWindow
+	}
+	start, err := time.Parse(time.RFC3339, strings.TrimSpace(startText))
+	if err != nil {
+		return nil, ErrInvalidMaintenanceWindow
+	}
+
+	endText = strings.TrimSpace(endText)
+	end, err := time.Parse(time.RFC3339, endText)
+	if err != nil {
+		duration, durationErr := time.ParseDuration(endText)
+		if durationErr != nil {
+			return nil, ErrInvalidMaintenanceWindow
+		}
+		end = start.Add(duration)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("maintenance window is invalid: it must end after it starts")
+	}
+	return &Maintenance{Start: start, End: end}, nil
+}
+
+// Active reports whether now falls inside the maintenance window
+func (m *Maintenance) Active(now time.Time) bool {
+	return m != nil && !now.Before(m.Start) && now.Before(m.End)
+}
+
+// silenceMaintenance silences the alerts affected by a deployment inside an
+// active maintenance window. The report is nil outside a window.
+func (d *Deployer) silenceMaintenance(ctx context.Context, target *Target, req Request) (*SilenceReport, error) {
+	now := time.Now().UTC()
+	if !req.Maintenance.Active(now) {
+		return nil, nil
+	}
+
+	report := &SilenceReport{EndsAt: req.Maintenance.End.UTC()}
+	matchers, err := d.maintenanceMatchers(ctx, target, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchers) == 0 {
+		report.Note = "no alert rules are linked to the dashboard, so nothing was silenced"
+		return report, nil
+	}
+	report.Matchers = matchers
+
+	title, _ := req.Dashboard["title"].(string)
+	id, err := d.grafanaSvc.CreateSilence(ctx, grafana.Silence{
+		Matchers:  matchers,
+		StartsAt:  now,
+		EndsAt:    report.EndsAt,
+		CreatedBy: "grafana-agent",
+		Comment:   fmt.Sprintf("Maintenance window: deploying dashboard %q", title),
+	}, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to silence alerts for the maintenance window: %w", err)
+	}
+	report.ID = id
+
+	d.logger.Info("Silenced alerts for the maintenance window",
+		zap.String("silence_id", id),
+		zap.Time("ends_at", report.EndsAt))
+	return report, nil
+}
+
+// expireSilence removes a maintenance silence once the deployment is done.
+// It runs even when the request was cancelled; if it fails the silence
+// still lapses at the end of the window.
+func (d *Deployer) expireSilence(ctx context.Context, target *Target, report *SilenceReport) {
+	if err := d.grafanaSvc.DeleteSilence(context.WithoutCancel(ctx), report.ID, target.GrafanaURL, target.APIKey); err != nil {
+		d.logger.Warn("Failed to expire the maintenance silence",
+			zap.String("silence_id", report.ID),
+			zap.Error(err))
+		report.Note = fmt.Sprintf("the silence could not be removed and lapses at the end of the window: %v", err)
+		return
+	}
+	report.Expired = true
+}
+
+// maintenanceMatchers selects the alerts to silence: the explicit labels,
+// otherwise the alert rules linked to the dashboard
+func (d *Deployer) maintenanceMatchers(ctx context.Context, target *Target, req Request) ([]grafana.SilenceMatcher, error) {
+	if labels := req.Maintenance.Labels; len(labels) > 0 {
+		names := make([]string, 0, len(labels))
+		for name := range labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		matchers := make([]grafana.SilenceMatcher, 0, len(names))
+		for _, name := range names {
+			matchers = append(matchers, grafana.SilenceMatcher{Name: name, Value: labels[name], IsEqual: true})
+		}
+		return matchers, nil
+	}
+
+	uid, _ := req.Dashboard["uid"].(string)
+	if uid == "" {
+		return nil, nil
+	}
+	rules, err := d.grafanaSvc.ListAlertRules(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the alert rules linked to the dashboard: %w", err)
+	}
+
+	var ruleUIDs []string
+	for _, rule := range rules {
+		if rule.Annotations[dashboardUIDAnnotation] == uid {
+			ruleUIDs = append(ruleUIDs, regexp.QuoteMeta(rule.UID))
+		}
+	}
+	if len(ruleUIDs) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ruleUIDs)
+	return []grafana.SilenceMatcher{{
+		Name:    grafana.AlertRuleUIDLabel,
+		Value:   strings.Join(ruleUIDs, "|"),
+		IsRegex: true,
+		IsEqual: true,
+	}}, nil
+}
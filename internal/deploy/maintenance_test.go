@@ -0,0 +1,123 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func (s *stubGrafana) ListAlertRules(ctx context.Context, grafanaURL, apiKey string) ([]grafana.AlertRule, error) {
+	return s.alertRules, nil
+}
+
+func (s *stubGrafana) CreateSilence(ctx context.Context, silence grafana.Silence, grafanaURL, apiKey string) (string, error) {
+	s.silences = append(s.silences, silence)
+	return fmt.Sprintf("silence-%d", len(s.silences)), nil
+}
+
+func (s *stubGrafana) DeleteSilence(ctx context.Context, id, grafanaURL, apiKey string) error {
+	s.expired = append(s.expired, id)
+	return nil
+}
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	start := time.Date(2026, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	for _, window := range []string{"2026-01-10T22:00:00Z/2026-01-11T00:00:00Z", "2026-01-10T22:00:00Z/2h"} {
+		maintenance, err := ParseMaintenanceWindow(window)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", window, err)
+		}
+		if !maintenance.Start.Equal(start) || !maintenance.End.Equal(start.Add(2*time.Hour)) {
+			t.Errorf("Unexpected window %+v for %s", maintenance, window)
+		}
+		if !maintenance.Active(start.Add(time.Hour)) || maintenance.Active(start.Add(2*time.Hour)) {
+			t.Errorf("Unexpected activity of %s", window)
+		}
+	}
+
+	for _, window := range []string{"tonight", "2026-01-10T22:00:00Z/later", "2026-01-10T22:00:00Z/-1h"} {
+		if _, err := ParseMaintenanceWindow(window); err == nil {
+			t.Errorf("Expected an error for %s", window)
+		}
+	}
+}
+
+func TestDeployMaintenanceSilence(t *testing.T) {
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana", APIKey: "key"}
+	now := time.Now()
+	active := &Maintenance{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+
+	t.Run("silences the linked alert rules", func(t *testing.T) {
+		stub := &stubGrafana{alertRules: []grafana.AlertRule{
+			{UID: "errors", Annotations: map[string]string{dashboardUIDAnnotation: "abc"}},
+			{UID: "latency", Annotations: map[string]string{dashboardUIDAnnotation: "abc"}},
+			{UID: "other", Annotations: map[string]string{dashboardUIDAnnotation: "xyz"}},
+		}}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:   map[string]any{"uid": "abc", "title": "Checkout"},
+			Maintenance: active,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(stub.silences) != 1 || stub.silences[0].Matchers[0].Value != "errors|latency" || !stub.silences[0].EndsAt.Equal(active.End.UTC()) {
+			t.Errorf("Unexpected silences %+v", stub.silences)
+		}
+		if result.Silence == nil || result.Silence.ID != "silence-1" || !result.Silence.Expired || len(stub.expired) != 1 {
+			t.Errorf("Expected the silence to be expired after the deploy, got %+v", result.Silence)
+		}
+	})
+
+	t.Run("explicit labels", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:   map[string]any{"title": "New"},
+			Maintenance: &Maintenance{Start: active.Start, End: active.End, Labels: map[string]string{"team": "payments", "env": "prod"}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		matchers := stub.silences[0].Matchers
+		if len(matchers) != 2 || matchers[0].Name != "env" || matchers[1].Value != "payments" || matchers[0].IsRegex {
+			t.Errorf("Unexpected matchers %+v", matchers)
+		}
+		if !result.Silence.Expired {
+			t.Errorf("Expected the silence to be expired, got %+v", result.Silence)
+		}
+	})
+
+	t.Run("nothing to silence", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:   map[string]any{"uid": "abc"},
+			Maintenance: active,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(stub.silences) != 0 || result.Silence == nil || result.Silence.Note == "" {
+			t.Errorf("Expected a note without a silence, got %+v", result.Silence)
+		}
+	})
+
+	t.Run("outside the window", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:   map[string]any{"uid": "abc"},
+			Maintenance: &Maintenance{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Labels: map[string]string{"team": "payments"}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(stub.silences) != 0 || result.Silence != nil {
+			t.Errorf("Expected no silence outside the window, got %+v", result.Silence)
+		}
+	})
+}
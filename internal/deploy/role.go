@@ -0,0 +1,33 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// ErrInsufficientRole is returned when the configured API key cannot save
+// dashboards although deployments are enabled
+var ErrInsufficientRole = errors.New("insufficient role")
+
+// CheckDeployRole verifies at startup that GRAFANA_API_KEY may deploy:
+// saving dashboards and folders needs the Editor or Admin role. It checks
+// nothing while deployments are disabled or the key or URL is unset.
+func CheckDeployRole(ctx context.Context, grafanaSvc grafana.Grafana, cfg *config.GrafanaConfig) (*grafana.CurrentUser, error) {
+	if cfg == nil || !cfg.DeployEnabled || cfg.URL == "" || cfg.APIKey == "" {
+		return nil, nil
+	}
+
+	user, err := grafanaSvc.GetCurrentUser(ctx, cfg.URL, cfg.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the role of GRAFANA_API_KEY: %w", err)
+	}
+	if !user.CanEdit() {
+		return user, fmt.Errorf("%w: GRAFANA_API_KEY authenticates as %q with role %s in org %d, but deploying dashboards requires Editor or Admin - grant the service account a higher role or set GRAFANA_DEPLOY_ENABLED=false",
+			ErrInsufficientRole, user.Login, user.Role, user.OrgID)
+	}
+	return user, nil
+}
@@ -0,0 +1,44 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func (s *stubGrafana) GetCurrentUser(ctx context.Context, grafanaURL, apiKey string) (*grafana.CurrentUser, error) {
+	s.grafanaURL, s.apiKey = grafanaURL, apiKey
+	return s.user, s.err
+}
+
+func TestCheckDeployRole(t *testing.T) {
+	enabled := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"}
+
+	viewer := &stubGrafana{user: &grafana.CurrentUser{Login: "sa-agent", OrgID: 1, Role: grafana.RoleViewer}}
+	_, err := CheckDeployRole(context.Background(), viewer, enabled)
+	if !errors.Is(err, ErrInsufficientRole) || !strings.Contains(err.Error(), `"sa-agent" with role Viewer`) {
+		t.Errorf("expected an insufficient role error, got %v", err)
+	}
+	if viewer.apiKey != "test-key" {
+		t.Errorf("expected the configured key to be checked, got %q", viewer.apiKey)
+	}
+
+	editor := &stubGrafana{user: &grafana.CurrentUser{Login: "sa-agent", OrgID: 1, Role: grafana.RoleEditor}}
+	if user, err := CheckDeployRole(context.Background(), editor, enabled); err != nil || user.Role != grafana.RoleEditor {
+		t.Errorf("expected an editor to pass, got %v, %v", user, err)
+	}
+
+	unreachable := &stubGrafana{err: errors.New("connection refused")}
+	if _, err := CheckDeployRole(context.Background(), unreachable, enabled); err == nil || errors.Is(err, ErrInsufficientRole) {
+		t.Errorf("expected a verification error, got %v", err)
+	}
+
+	disabled := &stubGrafana{}
+	if user, err := CheckDeployRole(context.Background(), disabled, &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"}); user != nil || err != nil {
+		t.Errorf("expected no check while deployments are disabled, got %v, %v", user, err)
+	}
+}
@@ -0,0 +1,40 @@
+package deploy
+
+import (
+	"context"
+
+	zap "go.uber.org/zap"
+
+	schema "github.com/inference-gateway/grafana-agent/internal/schema"
+)
+
+// negotiateSchema detects the dashboard schema version the target Grafana
+// supports and, when the request asks for it, migrates the dashboard to it.
+// It returns nil when there is nothing to report: no migration applied and
+// nothing the target may not render.
+func (d *Deployer) negotiateSchema(ctx context.Context, target *Target, req Request) *schema.Report {
+	var grafanaVersion string
+	if health, err := d.grafanaSvc.GetHealth(ctx, target.GrafanaURL, target.APIKey); err != nil {
+		d.logger.Debug("could not detect the grafana version", zap.String("grafana_url", target.GrafanaURL), zap.Error(err))
+	} else {
+		grafanaVersion = health.Version
+	}
+	supported := schema.ForGrafana(grafanaVersion)
+
+	var report *schema.Report
+	if req.MigrateSchema {
+		report = schema.Migrate(req.Dashboard, grafanaVersion, supported)
+	} else {
+		report = schema.Check(req.Dashboard, grafanaVersion, supported)
+	}
+	if len(report.Migrations) == 0 && len(report.Warnings) == 0 {
+		return nil
+	}
+
+	d.logger.Info("dashboard schema negotiated",
+		zap.String("grafana_version", grafanaVersion),
+		zap.Int("supported_version", supported),
+		zap.Strings("migrations", report.Migrations),
+		zap.Strings("warnings", report.Warnings))
+	return report
+}
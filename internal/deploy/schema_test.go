@@ -0,0 +1,76 @@
+package deploy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func (s *stubGrafana) GetHealth(ctx context.Context, grafanaURL, apiKey string) (*grafana.Health, error) {
+	if s.version == "" {
+		return nil, errors.New("grafana returned status 503")
+	}
+	return &grafana.Health{Version: s.version}, nil
+}
+
+func TestDeploySchemaNegotiation(t *testing.T) {
+	cfg := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana", APIKey: "key"}
+	legacy := func() map[string]any {
+		return map[string]any{
+			"title":         "Legacy",
+			"schemaVersion": float64(18),
+			"panels":        []any{map[string]any{"id": float64(1), "type": "graph", "title": "Requests"}},
+		}
+	}
+
+	t.Run("migrates for the target version", func(t *testing.T) {
+		stub := &stubGrafana{version: "11.2.0"}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard:     legacy(),
+			MigrateSchema: true,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Schema == nil || result.Schema.SupportedVersion != 39 || len(result.Schema.Migrations) == 0 {
+			t.Fatalf("Unexpected schema report %+v", result.Schema)
+		}
+		panel := stub.deployed.Dashboard["panels"].([]any)[0].(map[string]any)
+		if panel["type"] != "timeseries" {
+			t.Errorf("Expected the graph panel to be migrated, got %v", panel["type"])
+		}
+	})
+
+	t.Run("only warns without migrating", func(t *testing.T) {
+		stub := &stubGrafana{version: "11.2.0"}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{Dashboard: legacy()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Schema == nil || len(result.Schema.Migrations) != 0 || len(result.Schema.Warnings) != 1 {
+			t.Fatalf("Expected an angular panel warning, got %+v", result.Schema)
+		}
+		panel := stub.deployed.Dashboard["panels"].([]any)[0].(map[string]any)
+		if panel["type"] != "graph" {
+			t.Errorf("Expected the panel untouched, got %v", panel["type"])
+		}
+	})
+
+	t.Run("unknown version deploys without a report", func(t *testing.T) {
+		stub := &stubGrafana{}
+		result, err := NewDeployer(zap.NewNop(), stub, nil, cfg, Options{}).Deploy(context.Background(), Request{
+			Dashboard: map[string]any{"title": "Test", "schemaVersion": float64(36)},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Schema != nil {
+			t.Errorf("Expected no schema report, got %+v", result.Schema)
+		}
+	})
+}
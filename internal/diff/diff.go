@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// volatileFields are dashboard fields Grafana rewrites on every save; they
+// carry no meaning when comparing two versions of a dashboard
+var volatileFields = []string{"id", "version", "iteration"}
+
+// volatilePanelFields are panel fields Grafana stamps when a panel is saved
+var volatilePanelFields = []string{"pluginVersion"}
+
+// Change types
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a single difference between two dashboards, addressed by a path
+// such as "panels[2].targets[0].expr"
+type Change struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Canonicalize returns a normalized deep copy of a dashboard model so that
+// semantically identical dashboards compare equal: volatile fields are
+// stripped, null values dropped and numbers decoded uniformly. Keys are
+// sorted whenever the result is marshalled to JSON.
+func Canonicalize(dashboard map[string]any) map[string]any {
+	if dashboard == nil {
+		return nil
+	}
+
+	// Round-trip through JSON for a deep copy with uniform number types
+	data, err := json.Marshal(dashboard)
+	if err != nil {
+		return nil
+	}
+	var canonical map[string]any
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		return nil
+	}
+
+	for _, field := range volatileFields {
+		delete(canonical, field)
+	}
+	canonicalizePanels(canonical["panels"])
+	return dropNulls(canonical).(map[string]any)
+}
+
+// canonicalizePanels strips volatile fields from panels and collapsed rows
+func canonicalizePanels(raw any) {
+	panels, _ := raw.([]any)
+	for _, p := range panels {
+		panel, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, field := range volatilePanelFields {
+			delete(panel, field)
+		}
+		canonicalizePanels(panel["panels"])
+	}
+}
+
+// dropNulls removes null object values recursively
+func dropNulls(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if item == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = dropNulls(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = dropNulls(item)
+		}
+	}
+	return value
+}
+
+// CanonicalJSON renders the canonical form of a dashboard with sorted keys
+func CanonicalJSON(dashboard map[string]any) ([]byte, error) {
+	return json.Marshal(Canonicalize(dashboard))
+}
+
+// Equal reports whether two dashboards are semantically identical
+func Equal(a, b map[string]any) bool {
+	return reflect.DeepEqual(Canonicalize(a), Canonicalize(b))
+}
+
+// Dashboards lists the changes turning dashboard a into dashboard b after
+// canonicalizing both, in path order
+func Dashboards(a, b map[string]any) []Change {
+	var changes []Change
+	compare("", Canonicalize(a), Canonicalize(b), &changes)
+	return changes
+}
+
+// compare appends the differences between two canonical values
+func compare(path string, a, b any, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for key := range av {
+			keys = append(keys, key)
+		}
+		for key := range bv {
+			if _, ok := av[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := joinPath(path, key)
+			aItem, inA := av[key]
+			bItem, inB := bv[key]
+			switch {
+			case !inB:
+				*changes = append(*changes, Change{Path: child, Type: Removed, Old: aItem})
+			case !inA:
+				*changes = append(*changes, Change{Path: child, Type: Added, New: bItem})
+			default:
+				compare(child, aItem, bItem, changes)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(bv):
+				*changes = append(*changes, Change{Path: child, Type: Removed, Old: av[i]})
+			case i >= len(av):
+				*changes = append(*changes, Change{Path: child, Type: Added, New: bv[i]})
+			default:
+				compare(child, av[i], bv[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Type: Changed, Old: a, New: b})
+	}
+}
+
+// joinPath appends an object key to a path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
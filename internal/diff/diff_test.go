@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func deployedDashboard() map[string]any {
+	var dashboard map[string]any
+	_ = json.Unmarshal([]byte(`{
+		"id": 42,
+		"uid": "checkout",
+		"version": 7,
+		"iteration": 1712345678901,
+		"title": "Checkout",
+		"description": null,
+		"panels": [
+			{"id": 1, "type": "timeseries", "pluginVersion": "11.2.0", "targets": [{"refId": "A", "expr": "rate(http_requests_total[5m])"}]},
+			{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "stat", "pluginVersion": "11.2.0"}]}
+		]
+	}`), &dashboard)
+	return dashboard
+}
+
+func generatedDashboard() map[string]any {
+	return map[string]any{
+		"uid":   "checkout",
+		"title": "Checkout",
+		"panels": []any{
+			map[string]any{"id": 1, "type": "timeseries", "targets": []any{map[string]any{"expr": "rate(http_requests_total[5m])", "refId": "A"}}},
+			map[string]any{"id": 2, "type": "row", "collapsed": true, "panels": []map[string]any{{"id": 3, "type": "stat"}}},
+		},
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	canonical := Canonicalize(deployedDashboard())
+	require.NotContains(t, canonical, "id")
+	require.NotContains(t, canonical, "version")
+	require.NotContains(t, canonical, "iteration")
+	require.NotContains(t, canonical, "description")
+
+	panels := canonical["panels"].([]any)
+	require.NotContains(t, panels[0], "pluginVersion")
+	require.NotContains(t, panels[1].(map[string]any)["panels"].([]any)[0], "pluginVersion")
+
+	a, err := CanonicalJSON(deployedDashboard())
+	require.NoError(t, err)
+	b, err := CanonicalJSON(generatedDashboard())
+	require.NoError(t, err)
+	require.JSONEq(t, string(a), string(b))
+	require.Equal(t, string(a), string(b), "canonical JSON should be byte-identical")
+
+	original := deployedDashboard()
+	Canonicalize(original)
+	require.Contains(t, original, "version", "the input must not be modified")
+}
+
+func TestEqual(t *testing.T) {
+	require.True(t, Equal(deployedDashboard(), generatedDashboard()))
+
+	changed := generatedDashboard()
+	changed["title"] = "Checkout v2"
+	require.False(t, Equal(deployedDashboard(), changed))
+}
+
+func TestDashboards(t *testing.T) {
+	require.Empty(t, Dashboards(deployedDashboard(), generatedDashboard()))
+
+	changed := generatedDashboard()
+	changed["title"] = "Checkout v2"
+	changed["tags"] = []any{"payments"}
+	panels := changed["panels"].([]any)
+	panels[0].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"] = "rate(http_requests_total[1m])"
+	changed["panels"] = panels[:1]
+
+	changes := Dashboards(deployedDashboard(), changed)
+	require.Equal(t, []Change{
+		{Path: "panels[0].targets[0].expr", Type: Changed, Old: "rate(http_requests_total[5m])", New: "rate(http_requests_total[1m])"},
+		{Path: "panels[1]", Type: Removed, Old: Canonicalize(deployedDashboard())["panels"].([]any)[1]},
+		{Path: "tags", Type: Added, New: []any{"payments"}},
+		{Path: "title", Type: Changed, Old: "Checkout", New: "Checkout v2"},
+	}, changes)
+}
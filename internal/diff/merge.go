@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Conflict is a path both sides changed differently since the base version.
+// The merge keeps Theirs (the current deployed value) for conflicting paths.
+type Conflict struct {
+	Path   string `json:"path"`
+	Base   any    `json:"base,omitempty"`
+	Ours   any    `json:"ours,omitempty"`
+	Theirs any    `json:"theirs,omitempty"`
+}
+
+// absent marks a key missing on one side of a merge
+type absent struct{}
+
+// Merge3 merges the changes from base to ours (the agent's version) into
+// theirs (the version currently deployed, possibly edited by a human). All
+// three dashboards are canonicalized first. Changes made on only one side are
+// applied; paths changed differently on both sides are reported as conflicts
+// and keep their deployed value. Panels are matched by id, so edits to
+// different panels never conflict.
+func Merge3(base, ours, theirs map[string]any) (map[string]any, []Conflict) {
+	var conflicts []Conflict
+	merged := merge("", Canonicalize(base), Canonicalize(ours), Canonicalize(theirs), &conflicts)
+	result, _ := merged.(map[string]any)
+	return result, conflicts
+}
+
+// merge merges a single value
+func merge(path string, base, ours, theirs any, conflicts *[]Conflict) any {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours
+	case reflect.DeepEqual(base, ours):
+		return theirs
+	case reflect.DeepEqual(base, theirs):
+		return ours
+	}
+
+	baseMap, baseOK := base.(map[string]any)
+	oursMap, oursOK := ours.(map[string]any)
+	theirsMap, theirsOK := theirs.(map[string]any)
+	if oursOK && theirsOK {
+		if !baseOK {
+			baseMap = map[string]any{}
+		}
+		return mergeMaps(path, baseMap, oursMap, theirsMap, conflicts)
+	}
+
+	baseList, _ := base.([]any)
+	oursList, oursOK := ours.([]any)
+	theirsList, theirsOK := theirs.([]any)
+	if oursOK && theirsOK {
+		if merged, ok := mergeByID(path, baseList, oursList, theirsList, conflicts); ok {
+			return merged
+		}
+	}
+
+	*conflicts = append(*conflicts, Conflict{Path: path, Base: present(base), Ours: present(ours), Theirs: present(theirs)})
+	return theirs
+}
+
+// mergeMaps merges objects key by key
+func mergeMaps(path string, base, ours, theirs map[string]any, conflicts *[]Conflict) map[string]any {
+	keys := map[string]bool{}
+	for _, m := range []map[string]any{base, ours, theirs} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	merged := make(map[string]any, len(keys))
+	for _, key := range sorted {
+		value := merge(joinPath(path, key), lookup(base, key), lookup(ours, key), lookup(theirs, key), conflicts)
+		if _, missing := value.(absent); !missing {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// mergeByID merges lists of objects identified by a unique "id" (panels).
+// It reports false when the lists cannot be matched by id.
+func mergeByID(path string, base, ours, theirs []any, conflicts *[]Conflict) ([]any, bool) {
+	baseByID, ok := indexByID(base)
+	if !ok {
+		return nil, false
+	}
+	oursByID, ok := indexByID(ours)
+	if !ok {
+		return nil, false
+	}
+	theirsByID, ok := indexByID(theirs)
+	if !ok {
+		return nil, false
+	}
+
+	// Keep the deployed order, then append panels only the agent added
+	var order []string
+	for _, item := range theirs {
+		order = append(order, itemID(item))
+	}
+	for _, item := range ours {
+		if id := itemID(item); theirsByID[id] == nil && baseByID[id] == nil {
+			order = append(order, id)
+		}
+	}
+
+	var merged []any
+	for _, id := range order {
+		value := merge(fmt.Sprintf("%s[id=%s]", path, id), lookupItem(baseByID, id), lookupItem(oursByID, id), lookupItem(theirsByID, id), conflicts)
+		if _, missing := value.(absent); !missing {
+			merged = append(merged, value)
+		}
+	}
+	return merged, true
+}
+
+// indexByID indexes list items by their id; ok is false unless every item is
+// an object with a unique id
+func indexByID(items []any) (map[string]any, bool) {
+	index := make(map[string]any, len(items))
+	for _, item := range items {
+		id := itemID(item)
+		if id == "" || index[id] != nil {
+			return nil, false
+		}
+		index[id] = item
+	}
+	return index, true
+}
+
+// itemID returns the id of a list item, empty when it has none
+func itemID(item any) string {
+	object, ok := item.(map[string]any)
+	if !ok || object["id"] == nil {
+		return ""
+	}
+	return fmt.Sprint(object["id"])
+}
+
+// lookup returns a map value or absent
+func lookup(m map[string]any, key string) any {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	return absent{}
+}
+
+// lookupItem returns an indexed item or absent
+func lookupItem(index map[string]any, id string) any {
+	if item, ok := index[id]; ok {
+		return item
+	}
+	return absent{}
+}
+
+// present hides the absent marker from reported conflicts
+func present(value any) any {
+	if _, missing := value.(absent); missing {
+		return nil
+	}
+	return value
+}
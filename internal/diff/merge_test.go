@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func mergeBase() map[string]any {
+	return map[string]any{
+		"uid":     "checkout",
+		"version": 3,
+		"title":   "Checkout",
+		"refresh": "30s",
+		"panels": []any{
+			map[string]any{"id": 1, "title": "Requests", "targets": []any{map[string]any{"expr": "rate(http_requests_total[5m])"}}},
+			map[string]any{"id": 2, "title": "Errors"},
+		},
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	// The agent changes the query of panel 1 and adds panel 3
+	ours := mergeBase()
+	ours["panels"] = []any{
+		map[string]any{"id": 1, "title": "Requests", "targets": []any{map[string]any{"expr": "sum(rate(http_requests_total[5m]))"}}},
+		map[string]any{"id": 2, "title": "Errors"},
+		map[string]any{"id": 3, "title": "Latency"},
+	}
+
+	// A human renamed panel 2, moved it first and changed the refresh
+	theirs := mergeBase()
+	theirs["version"] = 5
+	theirs["refresh"] = "1m"
+	theirs["panels"] = []any{
+		map[string]any{"id": 2, "title": "Error rate"},
+		map[string]any{"id": 1, "title": "Requests", "targets": []any{map[string]any{"expr": "rate(http_requests_total[5m])"}}},
+	}
+
+	merged, conflicts := Merge3(mergeBase(), ours, theirs)
+	require.Empty(t, conflicts)
+	require.Equal(t, "1m", merged["refresh"])
+	require.Equal(t, []any{
+		map[string]any{"id": 2.0, "title": "Error rate"},
+		map[string]any{"id": 1.0, "title": "Requests", "targets": []any{map[string]any{"expr": "sum(rate(http_requests_total[5m]))"}}},
+		map[string]any{"id": 3.0, "title": "Latency"},
+	}, merged["panels"])
+}
+
+func TestMerge3Conflicts(t *testing.T) {
+	ours := mergeBase()
+	ours["title"] = "Checkout (generated)"
+	delete(ours, "refresh")
+
+	theirs := mergeBase()
+	theirs["title"] = "Checkout (edited)"
+	theirs["panels"] = []any{map[string]any{"id": 1, "title": "Requests"}}
+
+	merged, conflicts := Merge3(mergeBase(), ours, theirs)
+	require.Equal(t, []Conflict{{Path: "title", Base: "Checkout", Ours: "Checkout (generated)", Theirs: "Checkout (edited)"}}, conflicts)
+	require.Equal(t, "Checkout (edited)", merged["title"], "conflicts keep the deployed value")
+	require.NotContains(t, merged, "refresh", "removals by one side are applied")
+	require.Len(t, merged["panels"], 1, "panels removed by one side stay removed")
+}
+
+func TestMerge3DeletedAndEditedPanel(t *testing.T) {
+	ours := mergeBase()
+	ours["panels"] = ours["panels"].([]any)[:1]
+
+	theirs := mergeBase()
+	theirs["panels"].([]any)[1].(map[string]any)["title"] = "Error rate"
+
+	merged, conflicts := Merge3(mergeBase(), ours, theirs)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, "panels[id=2]", conflicts[0].Path)
+	require.Nil(t, conflicts[0].Ours)
+	require.Len(t, merged["panels"], 2)
+}
@@ -0,0 +1,333 @@
+// Package exposition parses the Prometheus text exposition format and
+// OpenMetrics, the output of an exporter's /metrics endpoint
+package exposition
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptHeader negotiates OpenMetrics, falling back to the text format
+const AcceptHeader = "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+
+// maxLineSize bounds a single exposition line; label values can be long but
+// a line beyond this is not a sane exposition
+const maxLineSize = 1 << 20
+
+// Family is a metric family: its metadata and samples. Type is the declared
+// type (counter, gauge, histogram, summary, ...) or "unknown" when the family
+// has no TYPE line.
+type Family struct {
+	Name    string
+	Type    string
+	Help    string
+	Unit    string
+	Samples []Sample
+}
+
+// Sample is a single exposed sample
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// typeSuffixes are the sample name suffixes each family type exposes
+var typeSuffixes = map[string][]string{
+	"counter":        {"_total", "_created"},
+	"histogram":      {"_bucket", "_count", "_sum", "_created"},
+	"gaugehistogram": {"_bucket", "_gcount", "_gsum"},
+	"summary":        {"_count", "_sum", "_created"},
+	"info":           {"_info"},
+}
+
+// SeriesName returns the name the family's values are queried by in
+// Prometheus: counters exposed as OpenMetrics ("foo" with foo_total samples)
+// are queried as foo_total, every other family by its name
+func (f Family) SeriesName() string {
+	if f.Type == "counter" && !strings.HasSuffix(f.Name, "_total") {
+		for _, sample := range f.Samples {
+			if sample.Name == f.Name+"_total" {
+				return sample.Name
+			}
+		}
+	}
+	return f.Name
+}
+
+// LabelValues returns the distinct values of each label across the samples
+func (f Family) LabelValues() map[string][]string {
+	seen := map[string]map[string]bool{}
+	for _, sample := range f.Samples {
+		for name, value := range sample.Labels {
+			if seen[name] == nil {
+				seen[name] = map[string]bool{}
+			}
+			seen[name][value] = true
+		}
+	}
+
+	values := make(map[string][]string, len(seen))
+	for name, set := range seen {
+		for value := range set {
+			values[name] = append(values[name], value)
+		}
+		sort.Strings(values[name])
+	}
+	return values
+}
+
+// Parse reads an exposition in the text format or OpenMetrics and returns
+// the metric families in the order they first appear
+func Parse(r io.Reader) ([]Family, error) {
+	p := &parser{index: map[string]int{}}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "# EOF" {
+			break
+		}
+
+		var err error
+		if strings.HasPrefix(line, "#") {
+			err = p.parseComment(line)
+		} else {
+			err = p.parseSample(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read exposition: %w", err)
+	}
+
+	return p.families, nil
+}
+
+// parser accumulates families while reading an exposition
+type parser struct {
+	families []Family
+	index    map[string]int
+}
+
+// family returns the family with the given name, declaring it if needed
+func (p *parser) family(name string) *Family {
+	if i, ok := p.index[name]; ok {
+		return &p.families[i]
+	}
+	p.index[name] = len(p.families)
+	p.families = append(p.families, Family{Name: name, Type: "unknown"})
+	return &p.families[len(p.families)-1]
+}
+
+// parseComment handles HELP, TYPE and UNIT lines; other comments are ignored
+func (p *parser) parseComment(line string) error {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "#")), " ", 3)
+	if len(fields) < 3 {
+		return nil
+	}
+
+	switch fields[0] {
+	case "HELP":
+		p.family(unquoteName(fields[1])).Help = unescape(fields[2], false)
+	case "TYPE":
+		typ := strings.TrimSpace(fields[2])
+		if typ == "untyped" {
+			typ = "unknown"
+		}
+		p.family(unquoteName(fields[1])).Type = typ
+	case "UNIT":
+		p.family(unquoteName(fields[1])).Unit = strings.TrimSpace(fields[2])
+	}
+	return nil
+}
+
+// parseSample handles `name{labels} value [timestamp] [# exemplar]`
+func (p *parser) parseSample(line string) error {
+	name, labels, rest, err := parseSeries(line)
+	if err != nil {
+		return err
+	}
+
+	valueField := strings.Fields(rest)
+	if len(valueField) == 0 {
+		return fmt.Errorf("sample %s has no value", name)
+	}
+	value, err := strconv.ParseFloat(valueField[0], 64)
+	if err != nil {
+		return fmt.Errorf("sample %s has an invalid value %q", name, valueField[0])
+	}
+
+	family := p.family(p.familyName(name))
+	family.Samples = append(family.Samples, Sample{Name: name, Labels: labels, Value: value})
+	return nil
+}
+
+// familyName resolves the family a sample belongs to from the declared
+// families and the suffixes their types expose
+func (p *parser) familyName(sampleName string) string {
+	if _, ok := p.index[sampleName]; ok {
+		return sampleName
+	}
+	for _, family := range p.families {
+		for _, suffix := range typeSuffixes[family.Type] {
+			if sampleName == family.Name+suffix {
+				return family.Name
+			}
+		}
+	}
+	return sampleName
+}
+
+// parseSeries splits a sample line into its name, labels and the remainder
+// holding the value
+func parseSeries(line string) (string, map[string]string, string, error) {
+	labels := map[string]string{}
+	brace := strings.IndexAny(line, "{ \t")
+	if brace < 0 {
+		return "", nil, "", fmt.Errorf("sample %q has no value", line)
+	}
+	name := line[:brace]
+	if line[brace] != '{' {
+		return name, labels, line[brace:], nil
+	}
+
+	i := brace + 1
+	for {
+		i = skipSpace(line, i)
+		if i >= len(line) {
+			return "", nil, "", fmt.Errorf("unterminated label set in %q", line)
+		}
+		if line[i] == '}' {
+			i++
+			break
+		}
+
+		var key string
+		if line[i] == '"' {
+			quoted, next, err := readQuoted(line, i)
+			if err != nil {
+				return "", nil, "", err
+			}
+			key, i = quoted, skipSpace(line, next)
+			// a quoted string without a value is a UTF-8 metric name
+			if i < len(line) && (line[i] == ',' || line[i] == '}') {
+				name = key
+				if line[i] == ',' {
+					i++
+				}
+				continue
+			}
+		} else {
+			start := i
+			for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+			key = line[start:i]
+			i = skipSpace(line, i)
+		}
+
+		if i >= len(line) || line[i] != '=' {
+			return "", nil, "", fmt.Errorf("label %q in %q has no value", key, line)
+		}
+		i = skipSpace(line, i+1)
+		if i >= len(line) || line[i] != '"' {
+			return "", nil, "", fmt.Errorf("label %q in %q has an unquoted value", key, line)
+		}
+		value, next, err := readQuoted(line, i)
+		if err != nil {
+			return "", nil, "", err
+		}
+		labels[key] = value
+
+		i = skipSpace(line, next)
+		if i < len(line) && line[i] == ',' {
+			i++
+		}
+	}
+
+	if name == "" {
+		return "", nil, "", fmt.Errorf("sample %q has no metric name", line)
+	}
+	return name, labels, line[i:], nil
+}
+
+// readQuoted reads the escaped string starting at the quote at line[start]
+// and returns it with the index after the closing quote
+func readQuoted(line string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				switch line[i] {
+				case 'n':
+					b.WriteByte('\n')
+				default:
+					b.WriteByte(line[i])
+				}
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(line[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string in %q", line)
+}
+
+// unescape resolves the escapes of HELP text; quoted text also unescapes \"
+func unescape(text string, quoted bool) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			switch text[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			case '"':
+				if quoted {
+					b.WriteByte('"')
+					i++
+					continue
+				}
+			}
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// unquoteName unquotes a UTF-8 metric name in a HELP, TYPE or UNIT line
+func unquoteName(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return unescape(name[1:len(name)-1], true)
+	}
+	return name
+}
+
+// skipSpace returns the index of the next non-blank character
+func skipSpace(line string, i int) int {
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return i
+}
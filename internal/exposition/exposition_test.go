@@ -0,0 +1,92 @@
+package exposition
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func TestParseTextFormat(t *testing.T) {
+	input := `# HELP http_requests_total Total HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 1027 1395066363000
+http_requests_total{method="POST", code="500",} 3
+# HELP http_request_duration_seconds Request latency\nin seconds.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.1"} 24054
+http_request_duration_seconds_bucket{le="+Inf"} 144320
+http_request_duration_seconds_sum 53423
+http_request_duration_seconds_count 144320
+# a free-form comment
+process_start_time_seconds 1.7e+09
+build_info{path="C:\\DIR\\",msg="say \"hi\", {ok}"} NaN
+`
+
+	families, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, families, 4)
+
+	counter := families[0]
+	require.Equal(t, "counter", counter.Type)
+	require.Equal(t, "http_requests_total", counter.SeriesName())
+	require.Len(t, counter.Samples, 2)
+	require.Equal(t, map[string]string{"method": "POST", "code": "500"}, counter.Samples[1].Labels)
+	require.Equal(t, map[string][]string{"code": {"200", "500"}, "method": {"GET", "POST"}}, counter.LabelValues())
+
+	histogram := families[1]
+	require.Equal(t, "histogram", histogram.Type)
+	require.Equal(t, "Request latency\nin seconds.", histogram.Help)
+	require.Len(t, histogram.Samples, 4)
+	require.Equal(t, "+Inf", histogram.Samples[1].Labels["le"])
+
+	require.Equal(t, "unknown", families[2].Type)
+	require.Equal(t, `C:\DIR\`, families[3].Samples[0].Labels["path"])
+	require.Equal(t, `say "hi", {ok}`, families[3].Samples[0].Labels["msg"])
+	require.True(t, math.IsNaN(families[3].Samples[0].Value))
+}
+
+func TestParseOpenMetrics(t *testing.T) {
+	input := `# TYPE rpc_requests counter
+# UNIT rpc_requests requests
+# HELP rpc_requests RPCs served.
+rpc_requests_total{service="checkout"} 12 # {trace_id="abc"} 1 1700000000.1
+rpc_requests_created{service="checkout"} 1700000000
+# TYPE target info
+target_info{service_name="checkout",service_version="1.2.0"} 1
+# TYPE "http.server.duration" histogram
+{"http.server.duration",le="1"} 3
+{"http.server.duration_count"} 3
+# EOF
+ignored_after_eof 1
+`
+
+	families, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, families, 3)
+
+	require.Equal(t, "rpc_requests", families[0].Name)
+	require.Equal(t, "rpc_requests_total", families[0].SeriesName())
+	require.Equal(t, "requests", families[0].Unit)
+	require.Len(t, families[0].Samples, 2)
+
+	require.Equal(t, "info", families[1].Type)
+	require.Equal(t, "checkout", families[1].Samples[0].Labels["service_name"])
+
+	require.Equal(t, "http.server.duration", families[2].Name)
+	require.Equal(t, map[string]string{"le": "1"}, families[2].Samples[0].Labels)
+	require.Equal(t, "http.server.duration_count", families[2].Samples[1].Name)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"up\n",
+		"up{job=\"api\" 1\n",
+		"up{job=api} 1\n",
+		"up abc\n",
+	} {
+		_, err := Parse(strings.NewReader(input))
+		require.Error(t, err, input)
+	}
+}
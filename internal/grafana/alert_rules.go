@@ -0,0 +1,42 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExpressionDatasourceUID is the datasource UID of server-side expressions
+// (reduce, math, threshold) in alert rule queries
+const ExpressionDatasourceUID = "__expr__"
+
+// AlertRule is a Grafana-managed alert rule
+type AlertRule struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUID"`
+	RuleGroup string `json:"ruleGroup"`
+	// Condition is the refId of the query or expression that decides whether the rule fires
+	Condition   string            `json:"condition"`
+	Data        []AlertQuery      `json:"data"`
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	IsPaused    bool              `json:"isPaused,omitempty"`
+}
+
+// AlertQuery is a query or server-side expression of an alert rule
+type AlertQuery struct {
+	RefID         string         `json:"refId"`
+	DatasourceUID string         `json:"datasourceUid"`
+	Model         map[string]any `json:"model"`
+}
+
+// ListAlertRules lists the Grafana-managed alert rules of the organization
+func (g *grafanaImpl) ListAlertRules(ctx context.Context, grafanaURL, apiKey string) ([]AlertRule, error) {
+	var rules []AlertRule
+	if _, err := g.doJSON(ctx, "GET", strings.TrimRight(grafanaURL, "/")+"/api/v1/provisioning/alert-rules", apiKey, nil, &rules); err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,40 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestListAlertRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/provisioning/alert-rules", r.URL.Path)
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{
+			"uid": "high-errors", "title": "HighErrorRate", "folderUID": "platform", "ruleGroup": "checkout",
+			"condition": "B", "for": "5m", "labels": {"severity": "critical"},
+			"data": [
+				{"refId": "A", "datasourceUid": "prom", "model": {"expr": "rate(errors_total[5m])"}},
+				{"refId": "B", "datasourceUid": "__expr__", "model": {"type": "threshold", "expression": "A"}}
+			]
+		}]`))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	rules, err := service.ListAlertRules(context.Background(), server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, "checkout", rules[0].RuleGroup)
+	require.Len(t, rules[0].Data, 2)
+	require.Equal(t, ExpressionDatasourceUID, rules[0].Data[1].DatasourceUID)
+	require.Equal(t, "rate(errors_total[5m])", rules[0].Data[0].Model["expr"])
+}
@@ -0,0 +1,114 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path prefixes of the cached read endpoints, used to invalidate them
+const (
+	foldersPath = "/api/folders"
+	searchPath  = "/api/search"
+)
+
+// cacheKey separates the responses seen by different credentials and
+// organizations
+type cacheKey struct {
+	apiKey string
+	orgID  int
+	url    string
+}
+
+// cacheEntry is a raw response body; it is decoded on every hit so callers
+// never share the decoded values
+type cacheEntry struct {
+	body      json.RawMessage
+	expiresAt time.Time
+}
+
+// responseCache keeps GET responses of list endpoints for a short TTL, so a
+// conversation listing folders, picking one and deploying does not repeat
+// the same calls against a rate-limited Grafana. Writes invalidate the
+// endpoints they change.
+type responseCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+// newResponseCache returns a cache keeping responses for ttl; a zero ttl
+// disables caching
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, now: time.Now, entries: map[cacheKey]cacheEntry{}}
+}
+
+// get returns the cached body of a request, if it has not expired
+func (c *responseCache) get(key cacheKey) (json.RawMessage, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// put caches the body of a request
+func (c *responseCache) put(key cacheKey, body json.RawMessage) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{body: body, expiresAt: c.now().Add(c.ttl)}
+}
+
+// invalidate drops the cached responses of the given endpoints of a Grafana
+// instance, for every credential and organization
+func (c *responseCache) invalidate(grafanaURL string, paths ...string) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	base := strings.TrimRight(grafanaURL, "/")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		for _, path := range paths {
+			if strings.HasPrefix(key.url, base+path) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// cachedGet is doJSON for GET requests whose responses may be served from
+// the response cache
+func (g *grafanaImpl) cachedGet(ctx context.Context, url, apiKey string, out any) error {
+	key := cacheKey{apiKey: apiKey, orgID: OrgIDFromContext(ctx), url: url}
+	body, ok := g.cache.get(key)
+	if !ok {
+		if _, err := g.doJSON(ctx, "GET", url, apiKey, nil, &body); err != nil {
+			return err
+		}
+		g.cache.put(key, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestResponseCache(t *testing.T) {
+	var folderCalls, searchCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/folders":
+			folderCalls.Add(1)
+			require.NoError(t, json.NewEncoder(w).Encode([]Folder{{UID: "platform", Title: "Platform"}}))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/folders":
+			require.NoError(t, json.NewEncoder(w).Encode(Folder{UID: "payments", Title: "Payments"}))
+		case r.URL.Path == "/api/search":
+			searchCalls.Add(1)
+			require.NoError(t, json.NewEncoder(w).Encode([]DashboardHit{{UID: "checkout"}}))
+		case r.URL.Path == "/api/dashboards/db":
+			require.NoError(t, json.NewEncoder(w).Encode(DashboardResponse{UID: "checkout"}))
+		}
+	}))
+	defer server.Close()
+
+	service, err := NewGrafanaService(zap.NewNop(), &config.Config{Grafana: config.GrafanaConfig{CacheTTL: time.Minute}}, nil)
+	require.NoError(t, err)
+	now := time.Now()
+	service.(*grafanaImpl).cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	folders, err := service.ListFolders(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	folders[0].Title = "changed by the caller"
+	folders, err = service.ListFolders(ctx, "", server.URL+"/", "key")
+	require.NoError(t, err)
+	require.Equal(t, "Platform", folders[0].Title)
+	require.EqualValues(t, 1, folderCalls.Load())
+
+	// Other credentials do not share responses
+	_, err = service.ListFolders(ctx, "", server.URL, "other-key")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, folderCalls.Load())
+
+	_, err = service.SearchDashboards(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	_, err = service.SearchDashboards(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, searchCalls.Load())
+
+	_, err = service.CreateDashboard(ctx, Dashboard{Dashboard: map[string]any{"title": "Checkout"}}, server.URL, "key")
+	require.NoError(t, err)
+	_, err = service.SearchDashboards(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, searchCalls.Load())
+
+	_, err = service.CreateFolder(ctx, "Payments", "", server.URL, "key")
+	require.NoError(t, err)
+	_, err = service.ListFolders(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, folderCalls.Load())
+
+	now = now.Add(time.Minute)
+	_, err = service.ListFolders(ctx, "", server.URL, "key")
+	require.NoError(t, err)
+	require.EqualValues(t, 4, folderCalls.Load())
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.NoError(t, json.NewEncoder(w).Encode([]Datasource{{UID: "prom"}}))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	for range 2 {
+		_, err := service.ListDatasources(context.Background(), server.URL, "key")
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 2, calls.Load())
+}
@@ -0,0 +1,95 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ContactPoint is an integration (Slack, PagerDuty, email, ...) of a
+// Grafana contact point; a contact point is the set of integrations sharing
+// a name
+type ContactPoint struct {
+	UID                   string         `json:"uid"`
+	Name                  string         `json:"name"`
+	Type                  string         `json:"type"`
+	Settings              map[string]any `json:"settings"`
+	DisableResolveMessage bool           `json:"disableResolveMessage,omitempty"`
+}
+
+// TestAlert is the alert sent by a contact point test
+type TestAlert struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ContactPointTest is the outcome of a contact point test
+type ContactPointTest struct {
+	NotifiedAt   time.Time         `json:"notified_at"`
+	Integrations []IntegrationTest `json:"integrations"`
+}
+
+// IntegrationTest is the outcome of the test of one integration
+type IntegrationTest struct {
+	UID    string `json:"uid"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ListContactPoints lists the integrations of the contact points, only those
+// of the named contact point when name is set
+func (g *grafanaImpl) ListContactPoints(ctx context.Context, name, grafanaURL, apiKey string) ([]ContactPoint, error) {
+	endpoint := strings.TrimRight(grafanaURL, "/") + "/api/v1/provisioning/contact-points"
+	if name != "" {
+		endpoint += "?name=" + url.QueryEscape(name)
+	}
+
+	var contactPoints []ContactPoint
+	if _, err := g.doJSON(ctx, "GET", endpoint, apiKey, nil, &contactPoints); err != nil {
+		return nil, fmt.Errorf("failed to list contact points: %w", err)
+	}
+	return contactPoints, nil
+}
+
+// TestContactPoint sends a test notification through every integration of
+// a contact point. Integrations are referenced by UID, so Grafana uses their
+// stored secrets (webhook URLs, routing keys) rather than the redacted ones.
+func (g *grafanaImpl) TestContactPoint(ctx context.Context, name string, integrations []ContactPoint, alert TestAlert, grafanaURL, apiKey string) (*ContactPointTest, error) {
+	configs := make([]map[string]any, 0, len(integrations))
+	for _, integration := range integrations {
+		configs = append(configs, map[string]any{
+			"uid":                   integration.UID,
+			"name":                  name,
+			"type":                  integration.Type,
+			"settings":              integration.Settings,
+			"disableResolveMessage": integration.DisableResolveMessage,
+		})
+	}
+	body := map[string]any{
+		"alert": alert,
+		"receivers": []map[string]any{{
+			"name":                             name,
+			"grafana_managed_receiver_configs": configs,
+		}},
+	}
+
+	var resp struct {
+		NotifiedAt time.Time `json:"notified_at"`
+		Receivers  []struct {
+			Configs []IntegrationTest `json:"grafana_managed_receiver_configs"`
+		} `json:"receivers"`
+	}
+	// Grafana answers 207 when only some of the integrations failed
+	if _, err := g.doJSON(ctx, "POST", strings.TrimRight(grafanaURL, "/")+"/api/alertmanager/grafana/config/api/v1/receivers/test", apiKey, body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to test contact point %q: %w", name, err)
+	}
+
+	test := &ContactPointTest{NotifiedAt: resp.NotifiedAt}
+	for _, receiver := range resp.Receivers {
+		test.Integrations = append(test.Integrations, receiver.Configs...)
+	}
+	return test, nil
+}
@@ -0,0 +1,59 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestContactPoints(t *testing.T) {
+	var tested map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/provisioning/contact-points":
+			require.Equal(t, "oncall", r.URL.Query().Get("name"))
+			_, _ = w.Write([]byte(`[
+				{"uid": "slack-1", "name": "oncall", "type": "slack", "settings": {"recipient": "#alerts", "url": "[REDACTED]"}},
+				{"uid": "pd-1", "name": "oncall", "type": "pagerduty", "settings": {"integrationKey": "[REDACTED]"}}
+			]`))
+		case "/api/alertmanager/grafana/config/api/v1/receivers/test":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&tested))
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = w.Write([]byte(`{"notified_at": "2026-01-10T22:00:00Z", "receivers": [{"name": "oncall", "grafana_managed_receiver_configs": [
+				{"uid": "slack-1", "name": "oncall", "status": "ok"},
+				{"uid": "pd-1", "name": "oncall", "status": "failed", "error": "invalid routing key"}
+			]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	integrations, err := service.ListContactPoints(context.Background(), "oncall", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, integrations, 2)
+	require.Equal(t, "pagerduty", integrations[1].Type)
+
+	test, err := service.TestContactPoint(context.Background(), "oncall", integrations, TestAlert{Labels: map[string]string{"alertname": "Test"}}, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, test.Integrations, 2)
+	require.Equal(t, "failed", test.Integrations[1].Status)
+	require.Equal(t, "invalid routing key", test.Integrations[1].Error)
+	require.Equal(t, 2026, test.NotifiedAt.Year())
+
+	receivers := tested["receivers"].([]any)
+	configs := receivers[0].(map[string]any)["grafana_managed_receiver_configs"].([]any)
+	require.Equal(t, "slack-1", configs[0].(map[string]any)["uid"])
+	require.Equal(t, "Test", tested["alert"].(map[string]any)["labels"].(map[string]any)["alertname"])
+}
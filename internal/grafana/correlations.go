@@ -0,0 +1,98 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	zap "go.uber.org/zap"
+)
+
+// Correlation links a field of a source datasource's results to a query
+// against a target datasource (e.g. metrics to logs), so panels offer a
+// one-click pivot to the related data
+type Correlation struct {
+	UID         string            `json:"uid,omitempty"`
+	SourceUID   string            `json:"sourceUID,omitempty"`
+	TargetUID   string            `json:"targetUID"`
+	Label       string            `json:"label"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Config      CorrelationConfig `json:"config"`
+}
+
+// CorrelationConfig describes the query run against the target datasource
+type CorrelationConfig struct {
+	// Type is the correlation type ("query"), kept here for Grafana 10
+	Type string `json:"type,omitempty"`
+	// Field is the result field the link is attached to
+	Field string `json:"field"`
+	// Target is the query model for the target datasource; ${field} references
+	// are interpolated from the clicked row
+	Target map[string]any `json:"target"`
+}
+
+// correlationsURL builds the correlations endpoint of a source datasource
+func correlationsURL(grafanaURL, sourceUID string) string {
+	return fmt.Sprintf("%s/api/datasources/uid/%s/correlations", strings.TrimRight(grafanaURL, "/"), neturl.PathEscape(sourceUID))
+}
+
+// ListCorrelations lists the correlations of a source datasource
+func (g *grafanaImpl) ListCorrelations(ctx context.Context, sourceUID, grafanaURL, apiKey string) ([]Correlation, error) {
+	var raw json.RawMessage
+	status, err := g.doJSON(ctx, "GET", correlationsURL(grafanaURL, sourceUID), apiKey, nil, &raw)
+	if status == http.StatusNotFound {
+		// Grafana answers 404 when the datasource has no correlations
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list correlations: %w", err)
+	}
+
+	var correlations []Correlation
+	if err := json.Unmarshal(raw, &correlations); err == nil {
+		return correlations, nil
+	}
+
+	// Newer Grafana versions wrap the list in a paged envelope
+	var paged struct {
+		Correlations []Correlation `json:"correlations"`
+	}
+	if err := json.Unmarshal(raw, &paged); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return paged.Correlations, nil
+}
+
+// CreateCorrelation creates a correlation from correlation.SourceUID
+func (g *grafanaImpl) CreateCorrelation(ctx context.Context, correlation Correlation, grafanaURL, apiKey string) (*Correlation, error) {
+	var response struct {
+		Result Correlation `json:"result"`
+	}
+	if _, err := g.doJSON(ctx, "POST", correlationsURL(grafanaURL, correlation.SourceUID), apiKey, correlation, &response); err != nil {
+		return nil, fmt.Errorf("failed to create correlation: %w", err)
+	}
+
+	g.logger.Info("Correlation created successfully",
+		zap.String("uid", response.Result.UID),
+		zap.String("source_uid", correlation.SourceUID),
+		zap.String("target_uid", correlation.TargetUID))
+
+	return &response.Result, nil
+}
+
+// DeleteCorrelation deletes a correlation of a source datasource
+func (g *grafanaImpl) DeleteCorrelation(ctx context.Context, sourceUID, correlationUID, grafanaURL, apiKey string) error {
+	url := fmt.Sprintf("%s/%s", correlationsURL(grafanaURL, sourceUID), neturl.PathEscape(correlationUID))
+	if _, err := g.doJSON(ctx, "DELETE", url, apiKey, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete correlation: %w", err)
+	}
+
+	g.logger.Info("Correlation deleted successfully",
+		zap.String("uid", correlationUID),
+		zap.String("source_uid", sourceUID))
+	return nil
+}
@@ -0,0 +1,90 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestListCorrelations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/datasources/uid/prom/correlations":
+			_ = json.NewEncoder(w).Encode([]Correlation{{UID: "c1", SourceUID: "prom", TargetUID: "loki", Label: "Related logs"}})
+		case "/api/datasources/uid/mimir/correlations":
+			_ = json.NewEncoder(w).Encode(map[string]any{"correlations": []Correlation{{UID: "c2", SourceUID: "mimir", TargetUID: "tempo"}}, "totalCount": 1})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	correlations, err := service.ListCorrelations(context.Background(), "prom", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, correlations, 1)
+	require.Equal(t, "loki", correlations[0].TargetUID)
+
+	correlations, err = service.ListCorrelations(context.Background(), "mimir", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, correlations, 1)
+	require.Equal(t, "c2", correlations[0].UID)
+
+	correlations, err = service.ListCorrelations(context.Background(), "unused", server.URL, "test-api-key")
+	require.NoError(t, err, "a datasource without correlations is not an error")
+	require.Empty(t, correlations)
+}
+
+func TestCreateCorrelation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/api/datasources/uid/prom/correlations", r.URL.Path)
+
+		var body Correlation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "loki", body.TargetUID)
+		require.Equal(t, "job", body.Config.Field)
+
+		body.UID = "new-uid"
+		_ = json.NewEncoder(w).Encode(map[string]any{"message": "Correlation created", "result": body})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	correlation, err := service.CreateCorrelation(context.Background(), Correlation{
+		SourceUID: "prom",
+		TargetUID: "loki",
+		Label:     "Related logs",
+		Config:    CorrelationConfig{Field: "job", Target: map[string]any{"expr": `{job="${job}"}`}},
+	}, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "new-uid", correlation.UID)
+}
+
+func TestDeleteCorrelation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		if r.URL.Path != "/api/datasources/uid/prom/correlations/c1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "Correlation deleted"})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	require.NoError(t, service.DeleteCorrelation(context.Background(), "prom", "c1", server.URL, "test-api-key"))
+
+	err := service.DeleteCorrelation(context.Background(), "prom", "missing", server.URL, "test-api-key")
+	require.ErrorContains(t, err, "status 404")
+}
@@ -0,0 +1,167 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+// Datasource is a Grafana datasource
+type Datasource struct {
+	ID        int    `json:"id"`
+	UID       string `json:"uid"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Access    string `json:"access,omitempty"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// DatasourceHealth is the result of a datasource health check
+type DatasourceHealth struct {
+	// Status is "OK" or "ERROR" as reported by the datasource plugin, or
+	// "UNSUPPORTED" when the plugin has no health check
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Datasource health statuses
+const (
+	HealthOK          = "OK"
+	HealthError       = "ERROR"
+	HealthUnsupported = "UNSUPPORTED"
+)
+
+// ListDatasources lists the datasources of the organization; responses are
+// cached for the cache TTL
+func (g *grafanaImpl) ListDatasources(ctx context.Context, grafanaURL, apiKey string) ([]Datasource, error) {
+	var datasources []Datasource
+	if err := g.cachedGet(ctx, strings.TrimRight(grafanaURL, "/")+"/api/datasources", apiKey, &datasources); err != nil {
+		return nil, fmt.Errorf("failed to list datasources: %w", err)
+	}
+	return datasources, nil
+}
+
+// GetDatasource looks up a datasource by UID, falling back to its name
+func (g *grafanaImpl) GetDatasource(ctx context.Context, uidOrName, grafanaURL, apiKey string) (*Datasource, error) {
+	base := strings.TrimRight(grafanaURL, "/")
+
+	var datasource Datasource
+	status, err := g.doJSON(ctx, "GET", fmt.Sprintf("%s/api/datasources/uid/%s", base, neturl.PathEscape(uidOrName)), apiKey, nil, &datasource)
+	if status == http.StatusNotFound {
+		status, err = g.doJSON(ctx, "GET", fmt.Sprintf("%s/api/datasources/name/%s", base, neturl.PathEscape(uidOrName)), apiKey, nil, &datasource)
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("datasource %q not found", uidOrName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get datasource: %w", err)
+	}
+	return &datasource, nil
+}
+
+// CheckDatasourceHealth runs the health check of a datasource. A failing
+// check is reported in the returned health, not as an error.
+func (g *grafanaImpl) CheckDatasourceHealth(ctx context.Context, uid, grafanaURL, apiKey string) (*DatasourceHealth, error) {
+	url := fmt.Sprintf("%s/api/datasources/uid/%s/health", strings.TrimRight(grafanaURL, "/"), neturl.PathEscape(uid))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check datasource health: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var health DatasourceHealth
+	body, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(body, &health)
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if health.Status == "" {
+			health.Status = HealthOK
+		}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound && !strings.EqualFold(health.Status, HealthError):
+		// Plugins without a health check answer 404
+		health = DatasourceHealth{Status: HealthUnsupported, Message: health.Message}
+	case resp.StatusCode >= http.StatusInternalServerError && health.Message == "":
+		return nil, fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	default:
+		// 400 (and plugin errors surfaced as 5xx with a message) carry the
+		// reason the datasource is unhealthy
+		health.Status = HealthError
+		if health.Message == "" {
+			health.Message = fmt.Sprintf("grafana returned status %d", resp.StatusCode)
+		}
+	}
+	return &health, nil
+}
+
+// QueryDatasource runs a single query model against a datasource through
+// /api/ds/query over the last five minutes and returns the number of data
+// frames it produced. Query errors reported by the datasource are returned as
+// "datasource query failed: ..." errors.
+func (g *grafanaImpl) QueryDatasource(ctx context.Context, datasource Datasource, query map[string]any, grafanaURL, apiKey string) (int, error) {
+	target := map[string]any{"refId": "A"}
+	for k, v := range query {
+		target[k] = v
+	}
+	target["datasource"] = map[string]string{"type": datasource.Type, "uid": datasource.UID}
+
+	jsonData, err := json.Marshal(map[string]any{
+		"queries": []any{target},
+		"from":    "now-5m",
+		"to":      "now",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/ds/query", strings.TrimRight(grafanaURL, "/"))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query datasource: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var queryResp struct {
+		Message string `json:"message"`
+		Results map[string]struct {
+			Error  string            `json:"error"`
+			Frames []json.RawMessage `json:"frames"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return 0, fmt.Errorf("grafana returned status %d: failed to decode query response: %w", resp.StatusCode, err)
+	}
+
+	result, ok := queryResp.Results["A"]
+	if result.Error != "" {
+		return 0, fmt.Errorf("datasource query failed: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK || !ok {
+		return 0, fmt.Errorf("grafana returned status %d: %s", resp.StatusCode, queryResp.Message)
+	}
+	return len(result.Frames), nil
+}
@@ -0,0 +1,125 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestListDatasources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/datasources", r.URL.Path)
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode([]Datasource{
+			{ID: 1, UID: "prom", Name: "Prometheus", Type: "prometheus", IsDefault: true},
+			{ID: 2, UID: "loki", Name: "Loki", Type: "loki"},
+		})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	datasources, err := service.ListDatasources(context.Background(), server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, datasources, 2)
+	require.True(t, datasources[0].IsDefault)
+	require.Equal(t, "loki", datasources[1].UID)
+}
+
+func TestGetDatasource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources/uid/prom", "/api/datasources/name/Prometheus":
+			_ = json.NewEncoder(w).Encode(Datasource{ID: 1, UID: "prom", Name: "Prometheus", Type: "prometheus"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	for _, ref := range []string{"prom", "Prometheus"} {
+		datasource, err := service.GetDatasource(context.Background(), ref, server.URL, "test-api-key")
+		require.NoError(t, err)
+		require.Equal(t, "prom", datasource.UID)
+	}
+
+	_, err := service.GetDatasource(context.Background(), "loki", server.URL, "test-api-key")
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestCheckDatasourceHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected DatasourceHealth
+		wantErr  bool
+	}{
+		{name: "healthy", status: http.StatusOK, body: `{"status":"OK","message":"Successfully queried the Prometheus API."}`, expected: DatasourceHealth{Status: HealthOK, Message: "Successfully queried the Prometheus API."}},
+		{name: "unhealthy", status: http.StatusBadRequest, body: `{"status":"ERROR","message":"connection refused"}`, expected: DatasourceHealth{Status: HealthError, Message: "connection refused"}},
+		{name: "plugin error", status: http.StatusInternalServerError, body: `{"message":"Plugin health check failed"}`, expected: DatasourceHealth{Status: HealthError, Message: "Plugin health check failed"}},
+		{name: "no health check", status: http.StatusNotFound, body: `{"message":"Plugin not found"}`, expected: DatasourceHealth{Status: HealthUnsupported, Message: "Plugin not found"}},
+		{name: "forbidden", status: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/datasources/uid/prom/health", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+			health, err := service.CheckDatasourceHealth(context.Background(), "prom", server.URL, "test-api-key")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, *health)
+		})
+	}
+}
+
+func TestQueryDatasource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/ds/query", r.URL.Path)
+
+		var body struct {
+			Queries []map[string]any `json:"queries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		query := body.Queries[0]
+		require.Equal(t, map[string]any{"type": "prometheus", "uid": "prom"}, query["datasource"])
+
+		if query["expr"] == "vector(1)" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": map[string]any{"A": map[string]any{"frames": []any{map[string]any{}}}}})
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": map[string]any{"A": map[string]any{"error": "parse error"}}})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	datasource := Datasource{UID: "prom", Type: "prometheus"}
+
+	frames, err := service.QueryDatasource(context.Background(), datasource, map[string]any{"expr": "vector(1)"}, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, 1, frames)
+
+	_, err = service.QueryDatasource(context.Background(), datasource, map[string]any{"expr": "vector("}, server.URL, "test-api-key")
+	require.ErrorContains(t, err, "datasource query failed: parse error")
+}
@@ -4,16 +4,44 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
 	zap "go.uber.org/zap"
 
 	config "github.com/inference-gateway/grafana-agent/config"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
 )
 
+// ErrDashboardNotFound is returned by GetDashboard for unknown UIDs
+var ErrDashboardNotFound = errors.New("dashboard not found")
+
+// ErrSaveConflict matches every *SaveConflictError
+var ErrSaveConflict = errors.New("dashboard save conflict")
+
+// SaveConflictError is returned when Grafana refuses to save a dashboard
+// with 412 Precondition Failed, e.g. because it was changed by someone else
+// since the expected version ("version-mismatch") or another dashboard in the
+// folder has the same title ("name-exists")
+type SaveConflictError struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *SaveConflictError) Error() string {
+	return fmt.Sprintf("grafana returned status 412: %s (%s)", e.Message, e.Status)
+}
+
+// Is makes errors.Is(err, ErrSaveConflict) match
+func (e *SaveConflictError) Is(target error) bool {
+	return target == ErrSaveConflict
+}
+
 // Dashboard represents a Grafana dashboard
 type Dashboard struct {
 	Dashboard map[string]any `json:"dashboard"`
@@ -32,31 +60,116 @@ type DashboardResponse struct {
 	Slug    string `json:"slug"`
 }
 
+// Folder represents a Grafana folder
+type Folder struct {
+	ID        int    `json:"id"`
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	ParentUID string `json:"parentUid,omitempty"`
+}
+
 // Grafana represents the grafana service interface
 type Grafana interface {
 	CreateDashboard(ctx context.Context, dashboard Dashboard, grafanaURL, apiKey string) (*DashboardResponse, error)
-	UpdateDashboard(ctx context.Context, dashboard Dashboard, grafanaURL, apiKey string) (*DashboardResponse, error)
+	UpdateDashboard(ctx context.Context, dashboard Dashboard, expectedVersion int, grafanaURL, apiKey string) (*DashboardResponse, error)
 	GetDashboard(ctx context.Context, uid, grafanaURL, apiKey string) (*Dashboard, error)
 	DeleteDashboard(ctx context.Context, uid, grafanaURL, apiKey string) error
+	// SearchDashboards lists the dashboards whose title matches query (all when empty)
+	SearchDashboards(ctx context.Context, query, grafanaURL, apiKey string) ([]DashboardHit, error)
+	// GetDashboardVersion returns a saved version of a dashboard by version number
+	GetDashboardVersion(ctx context.Context, uid string, version int, grafanaURL, apiKey string) (*DashboardVersion, error)
+	// ListFolders lists the folders directly under parentUID (top level when empty)
+	ListFolders(ctx context.Context, parentUID, grafanaURL, apiKey string) ([]Folder, error)
+	// GetFolder returns a folder by UID
+	GetFolder(ctx context.Context, uid, grafanaURL, apiKey string) (*Folder, error)
+	// CreateFolder creates a folder under parentUID (top level when empty)
+	CreateFolder(ctx context.Context, title, parentUID, grafanaURL, apiKey string) (*Folder, error)
+	// ListCorrelations lists the correlations whose source is the given datasource
+	ListCorrelations(ctx context.Context, sourceUID, grafanaURL, apiKey string) ([]Correlation, error)
+	// CreateCorrelation creates a correlation from correlation.SourceUID
+	CreateCorrelation(ctx context.Context, correlation Correlation, grafanaURL, apiKey string) (*Correlation, error)
+	// DeleteCorrelation deletes a correlation of the given source datasource
+	DeleteCorrelation(ctx context.Context, sourceUID, correlationUID, grafanaURL, apiKey string) error
+	// GetPublicDashboard returns the public dashboard of a dashboard, nil when it has none
+	GetPublicDashboard(ctx context.Context, dashboardUID, grafanaURL, apiKey string) (*PublicDashboard, error)
+	// ListPublicDashboards lists every public dashboard of the org
+	ListPublicDashboards(ctx context.Context, grafanaURL, apiKey string) ([]PublicDashboard, error)
+	// CreatePublicDashboard shares a dashboard publicly
+	CreatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard PublicDashboard, grafanaURL, apiKey string) (*PublicDashboard, error)
+	// UpdatePublicDashboard updates an existing public dashboard (e.g. enables or disables it)
+	UpdatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard PublicDashboard, grafanaURL, apiKey string) (*PublicDashboard, error)
+	// GetPreferences returns the preferences of the org (teamID 0) or a team
+	GetPreferences(ctx context.Context, teamID int, grafanaURL, apiKey string) (*Preferences, error)
+	// SetHomeDashboard sets the home dashboard of the org (teamID 0) or a team
+	SetHomeDashboard(ctx context.Context, teamID int, dashboardUID, grafanaURL, apiKey string) error
+	// FindTeam looks up a team by name
+	FindTeam(ctx context.Context, name, grafanaURL, apiKey string) (*Team, error)
+	// ListDatasources lists the datasources of the organization
+	ListDatasources(ctx context.Context, grafanaURL, apiKey string) ([]Datasource, error)
+	// GetDatasource looks up a datasource by UID or name
+	GetDatasource(ctx context.Context, uidOrName, grafanaURL, apiKey string) (*Datasource, error)
+	// CheckDatasourceHealth runs the health check of a datasource
+	CheckDatasourceHealth(ctx context.Context, uid, grafanaURL, apiKey string) (*DatasourceHealth, error)
+	// ListAlertRules lists the Grafana-managed alert rules of the organization
+	ListAlertRules(ctx context.Context, grafanaURL, apiKey string) ([]AlertRule, error)
+	// CreateSilence creates a silence in the Grafana-managed Alertmanager and returns its ID
+	CreateSilence(ctx context.Context, silence Silence, grafanaURL, apiKey string) (string, error)
+	// DeleteSilence expires a silence of the Grafana-managed Alertmanager
+	DeleteSilence(ctx context.Context, id, grafanaURL, apiKey string) error
+	// ListContactPoints lists the contact point integrations, only those of the named contact point when name is set
+	ListContactPoints(ctx context.Context, name, grafanaURL, apiKey string) ([]ContactPoint, error)
+	// TestContactPoint sends a test notification through the integrations of a contact point
+	TestContactPoint(ctx context.Context, name string, integrations []ContactPoint, alert TestAlert, grafanaURL, apiKey string) (*ContactPointTest, error)
+	// GetCurrentUser returns the user or service account a token authenticates as, with its org role
+	GetCurrentUser(ctx context.Context, grafanaURL, apiKey string) (*CurrentUser, error)
+	// ListOrgs lists the organizations the token's user belongs to, with its role in each
+	ListOrgs(ctx context.Context, grafanaURL, apiKey string) ([]Org, error)
+	// GetHealth returns the version of the Grafana server
+	GetHealth(ctx context.Context, grafanaURL, apiKey string) (*Health, error)
+	// QueryDatasource runs a query model against a datasource and returns the number of frames
+	QueryDatasource(ctx context.Context, datasource Datasource, query map[string]any, grafanaURL, apiKey string) (int, error)
 }
 
 // grafanaImpl is the implementation of Grafana
 type grafanaImpl struct {
 	logger *zap.Logger
 	client *http.Client
+	cache  *responseCache
+	// searchMaxResults bounds the dashboards a paginated search collects
+	searchMaxResults int
+	// gzipRequests compresses large dashboard saves
+	gzipRequests bool
 }
 
-// NewGrafanaService creates a new instance of Grafana
-func NewGrafanaService(logger *zap.Logger, cfg *config.Config) (Grafana, error) {
+// NewGrafanaService creates a new instance of Grafana sending requests
+// through transport, shared with the other services; nil uses
+// http.DefaultTransport
+func NewGrafanaService(logger *zap.Logger, cfg *config.Config, transport http.RoundTripper) (Grafana, error) {
 	logger.Info("initializing grafana service")
 
+	breaker := circuit.NewBreaker(logger, "grafana", cfg.Circuit.FailureThreshold, cfg.Circuit.Cooldown)
+	transport, err := NewOAuthTransport(&cfg.Grafana, transport, breaker.Transport(NewGzipTransport(transport)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure grafana oauth: %w", err)
+	}
+	if cfg.Grafana.OAuth.TokenURL != "" {
+		logger.Info("grafana requests authenticate through oauth2",
+			zap.String("grant_type", cfg.Grafana.OAuth.GrantType),
+			zap.String("header", cfg.Grafana.OAuth.Header))
+	}
+
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout:   30 * time.Second,
+		Transport: newOrgTransport(transport),
 	}
 
 	return &grafanaImpl{
 		logger: logger,
 		client: client,
+		cache:  newResponseCache(cfg.Grafana.CacheTTL),
+
+		searchMaxResults: cfg.Grafana.SearchMaxResults,
+		gzipRequests:     cfg.Grafana.GzipRequests,
 	}, nil
 }
 
@@ -69,12 +182,23 @@ func (g *grafanaImpl) CreateDashboard(ctx context.Context, dashboard Dashboard,
 		return nil, fmt.Errorf("failed to marshal dashboard: %w", err)
 	}
 
+	// Dashboards with hundreds of panels are megabytes of JSON
+	compressed := g.gzipRequests && len(jsonData) >= gzipMinBytes
+	if compressed {
+		if jsonData, err = compressBody(jsonData); err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 
 	resp, err := g.client.Do(req)
@@ -83,6 +207,12 @@ func (g *grafanaImpl) CreateDashboard(ctx context.Context, dashboard Dashboard,
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		conflict := &SaveConflictError{}
+		_ = json.NewDecoder(resp.Body).Decode(conflict)
+		return nil, conflict
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("grafana returned status %d", resp.StatusCode)
 	}
@@ -92,6 +222,7 @@ func (g *grafanaImpl) CreateDashboard(ctx context.Context, dashboard Dashboard,
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	g.cache.invalidate(grafanaURL, searchPath)
 	g.logger.Info("Dashboard created successfully",
 		zap.Int("id", dashboardResp.ID),
 		zap.String("uid", dashboardResp.UID),
@@ -100,9 +231,18 @@ func (g *grafanaImpl) CreateDashboard(ctx context.Context, dashboard Dashboard,
 	return &dashboardResp, nil
 }
 
-// UpdateDashboard updates an existing dashboard in Grafana
-func (g *grafanaImpl) UpdateDashboard(ctx context.Context, dashboard Dashboard, grafanaURL, apiKey string) (*DashboardResponse, error) {
-	dashboard.Overwrite = true
+// UpdateDashboard updates an existing dashboard in Grafana. Unless
+// dashboard.Overwrite is set, the save only succeeds while expectedVersion is
+// still the current version; otherwise a *SaveConflictError is returned.
+func (g *grafanaImpl) UpdateDashboard(ctx context.Context, dashboard Dashboard, expectedVersion int, grafanaURL, apiKey string) (*DashboardResponse, error) {
+	if expectedVersion > 0 {
+		model := make(map[string]any, len(dashboard.Dashboard)+1)
+		for k, v := range dashboard.Dashboard {
+			model[k] = v
+		}
+		model["version"] = expectedVersion
+		dashboard.Dashboard = model
+	}
 	return g.CreateDashboard(ctx, dashboard, grafanaURL, apiKey)
 }
 
@@ -124,7 +264,7 @@ func (g *grafanaImpl) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("dashboard not found")
+		return nil, ErrDashboardNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -133,7 +273,9 @@ func (g *grafanaImpl) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey
 
 	var response struct {
 		Dashboard map[string]any `json:"dashboard"`
-		Meta      map[string]any `json:"meta"`
+		Meta      struct {
+			FolderUID string `json:"folderUid"`
+		} `json:"meta"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
@@ -142,6 +284,7 @@ func (g *grafanaImpl) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey
 
 	return &Dashboard{
 		Dashboard: response.Dashboard,
+		FolderUID: response.Meta.FolderUID,
 	}, nil
 }
 
@@ -166,6 +309,123 @@ func (g *grafanaImpl) DeleteDashboard(ctx context.Context, uid, grafanaURL, apiK
 		return fmt.Errorf("grafana returned status %d", resp.StatusCode)
 	}
 
+	g.cache.invalidate(grafanaURL, searchPath)
 	g.logger.Info("Dashboard deleted successfully", zap.String("uid", uid))
 	return nil
 }
+
+// ListFolders lists the folders directly under a parent folder; responses
+// are cached until a folder is created or the cache TTL passes
+func (g *grafanaImpl) ListFolders(ctx context.Context, parentUID, grafanaURL, apiKey string) ([]Folder, error) {
+	params := neturl.Values{}
+	params.Set("limit", "1000")
+	if parentUID != "" {
+		params.Set("parentUid", parentUID)
+	}
+	url := fmt.Sprintf("%s%s?%s", strings.TrimRight(grafanaURL, "/"), foldersPath, params.Encode())
+
+	var folders []Folder
+	if err := g.cachedGet(ctx, url, apiKey, &folders); err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	return folders, nil
+}
+
+// GetFolder returns a folder by UID
+func (g *grafanaImpl) GetFolder(ctx context.Context, uid, grafanaURL, apiKey string) (*Folder, error) {
+	url := fmt.Sprintf("%s%s/%s", strings.TrimRight(grafanaURL, "/"), foldersPath, neturl.PathEscape(uid))
+
+	var folder Folder
+	if _, err := g.doJSON(ctx, http.MethodGet, url, apiKey, nil, &folder); err != nil {
+		return nil, fmt.Errorf("failed to get folder %s: %w", uid, err)
+	}
+	return &folder, nil
+}
+
+// CreateFolder creates a folder, nested under parentUID when set
+func (g *grafanaImpl) CreateFolder(ctx context.Context, title, parentUID, grafanaURL, apiKey string) (*Folder, error) {
+	url := fmt.Sprintf("%s/api/folders", strings.TrimRight(grafanaURL, "/"))
+
+	body := map[string]string{"title": title}
+	if parentUID != "" {
+		body["parentUid"] = parentUID
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal folder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	}
+
+	var folder Folder
+	if err := json.NewDecoder(resp.Body).Decode(&folder); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	g.cache.invalidate(grafanaURL, foldersPath, searchPath)
+	g.logger.Info("Folder created successfully",
+		zap.String("uid", folder.UID),
+		zap.String("title", folder.Title),
+		zap.String("parent_uid", parentUID))
+
+	return &folder, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes a JSON
+// response into out (when non-nil). Non-2xx responses are returned as
+// "grafana returned status N" errors with the status in the second value.
+func (g *grafanaImpl) doJSON(ctx context.Context, method, url, apiKey string, body, out any) (int, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
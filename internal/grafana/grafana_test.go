@@ -3,6 +3,7 @@ package grafana
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,7 +19,7 @@ func TestNewGrafanaService(t *testing.T) {
 	logger := zap.NewNop()
 	cfg := &config.Config{}
 
-	service, err := NewGrafanaService(logger, cfg)
+	service, err := NewGrafanaService(logger, cfg, nil)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -125,7 +126,7 @@ func TestCreateDashboard(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
 			defer server.Close()
 
-			service, _ := NewGrafanaService(logger, &config.Config{})
+			service, _ := NewGrafanaService(logger, &config.Config{}, nil)
 
 			resp, err := service.CreateDashboard(context.Background(), tt.dashboard, server.URL, "test-api-key")
 
@@ -161,12 +162,13 @@ func TestUpdateDashboard(t *testing.T) {
 	tests := []struct {
 		name            string
 		dashboard       Dashboard
-		expectOverwrite bool
+		expectedVersion int
 		serverResponse  func(w http.ResponseWriter, r *http.Request)
 		wantErr         bool
+		wantConflict    bool
 	}{
 		{
-			name: "update sets overwrite to true",
+			name: "update sends the expected version without forcing overwrite",
 			dashboard: Dashboard{
 				Dashboard: map[string]any{
 					"title": "Updated Dashboard",
@@ -174,15 +176,18 @@ func TestUpdateDashboard(t *testing.T) {
 				},
 				Overwrite: false,
 			},
-			expectOverwrite: true,
+			expectedVersion: 3,
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				var received Dashboard
 				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
 					t.Fatalf("Failed to decode request body: %v", err)
 				}
 
-				if !received.Overwrite {
-					t.Error("Expected Overwrite to be true for update")
+				if received.Overwrite {
+					t.Error("Expected Overwrite to stay false for update")
+				}
+				if received.Dashboard["version"] != 3.0 {
+					t.Errorf("Expected version 3, got %v", received.Dashboard["version"])
 				}
 
 				w.WriteHeader(http.StatusOK)
@@ -197,6 +202,22 @@ func TestUpdateDashboard(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "version mismatch is a save conflict",
+			dashboard: Dashboard{
+				Dashboard: map[string]any{"title": "Updated Dashboard", "uid": "existing-uid"},
+			},
+			expectedVersion: 3,
+			serverResponse: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				require.NoError(t, json.NewEncoder(w).Encode(map[string]string{
+					"status":  "version-mismatch",
+					"message": "The dashboard has been changed by someone else",
+				}))
+			},
+			wantErr:      true,
+			wantConflict: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,14 +225,18 @@ func TestUpdateDashboard(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
 			defer server.Close()
 
-			service, _ := NewGrafanaService(logger, &config.Config{})
+			service, _ := NewGrafanaService(logger, &config.Config{}, nil)
 
-			resp, err := service.UpdateDashboard(context.Background(), tt.dashboard, server.URL, "test-api-key")
+			resp, err := service.UpdateDashboard(context.Background(), tt.dashboard, tt.expectedVersion, server.URL, "test-api-key")
 
 			if tt.wantErr {
 				if err == nil {
 					t.Error("Expected error but got none")
 				}
+				var conflict *SaveConflictError
+				if tt.wantConflict && (!errors.As(err, &conflict) || conflict.Status != "version-mismatch" || !errors.Is(err, ErrSaveConflict)) {
+					t.Errorf("Expected a version-mismatch conflict, got %v", err)
+				}
 				return
 			}
 
@@ -255,7 +280,8 @@ func TestGetDashboard(t *testing.T) {
 						"uid":   "test-uid",
 					},
 					"meta": map[string]any{
-						"version": 1,
+						"version":   1,
+						"folderUid": "platform",
 					},
 				}
 				require.NoError(t, json.NewEncoder(w).Encode(response))
@@ -265,6 +291,9 @@ func TestGetDashboard(t *testing.T) {
 				if dashboard.Dashboard["title"] != "Existing Dashboard" {
 					t.Errorf("Expected title 'Existing Dashboard', got %v", dashboard.Dashboard["title"])
 				}
+				if dashboard.FolderUID != "platform" {
+					t.Errorf("Expected folder UID 'platform', got %q", dashboard.FolderUID)
+				}
 			},
 		},
 		{
@@ -304,7 +333,7 @@ func TestGetDashboard(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
 			defer server.Close()
 
-			service, _ := NewGrafanaService(logger, &config.Config{})
+			service, _ := NewGrafanaService(logger, &config.Config{}, nil)
 
 			dashboard, err := service.GetDashboard(context.Background(), tt.uid, server.URL, "test-api-key")
 
@@ -379,7 +408,7 @@ func TestDeleteDashboard(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
 			defer server.Close()
 
-			service, _ := NewGrafanaService(logger, &config.Config{})
+			service, _ := NewGrafanaService(logger, &config.Config{}, nil)
 
 			err := service.DeleteDashboard(context.Background(), tt.uid, server.URL, "test-api-key")
 
@@ -396,3 +425,58 @@ func TestDeleteDashboard(t *testing.T) {
 		})
 	}
 }
+
+func TestListFolders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/folders", r.URL.Path)
+		require.Equal(t, "parent-uid", r.URL.Query().Get("parentUid"))
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewEncoder(w).Encode([]Folder{{ID: 1, UID: "child-uid", Title: "Payments", ParentUID: "parent-uid"}}))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	folders, err := service.ListFolders(context.Background(), "parent-uid", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	require.Equal(t, "child-uid", folders[0].UID)
+	require.Equal(t, "Payments", folders[0].Title)
+}
+
+func TestGetFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/folders/child-uid" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(Folder{ID: 1, UID: "child-uid", Title: "Payments", ParentUID: "parent-uid"}))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	folder, err := service.GetFolder(context.Background(), "child-uid", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "parent-uid", folder.ParentUID)
+
+	_, err = service.GetFolder(context.Background(), "missing-uid", server.URL, "test-api-key")
+	require.ErrorContains(t, err, "status 404")
+}
+
+func TestCreateFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]string{"title": "Payments", "parentUid": "parent-uid"}, body)
+		require.NoError(t, json.NewEncoder(w).Encode(Folder{ID: 2, UID: "new-uid", Title: "Payments", ParentUID: "parent-uid"}))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	folder, err := service.CreateFolder(context.Background(), "Payments", "parent-uid", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "new-uid", folder.UID)
+
+	_, err = service.CreateFolder(context.Background(), "Payments", "", "http://127.0.0.1:1", "test-api-key")
+	require.Error(t, err)
+}
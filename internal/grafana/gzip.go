@@ -0,0 +1,90 @@
+package grafana
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipMinBytes is the smallest request body worth compressing
+const gzipMinBytes = 16 << 10
+
+// gzipTransport asks for gzip-encoded responses and decodes them, whatever
+// the next transport does about compression
+type gzipTransport struct {
+	next http.RoundTripper
+}
+
+// NewGzipTransport returns a transport that accepts gzip responses. A nil
+// next uses http.DefaultTransport.
+func NewGzipTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &gzipTransport{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *gzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Callers negotiating an encoding themselves decode the response too
+	if req.Header.Get("Accept-Encoding") != "" || req.Method == http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	gzipped := req.Clone(req.Context())
+	gzipped.Header.Set("Accept-Encoding", "gzip")
+	resp, err := t.next.RoundTrip(gzipped)
+	if err != nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp, err
+	}
+
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// gzipBody decodes a gzip response body, opening the decoder on first read
+// so empty bodies of error responses do not fail
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+// Read implements io.Reader
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = gzip.NewReader(b.body)
+		if b.err != nil {
+			b.err = fmt.Errorf("failed to decompress response: %w", b.err)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+// Close implements io.Closer
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// compressBody gzips a request body
+func compressBody(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress request: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,83 @@
+package grafana
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestGzipTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		if r.URL.Path == "/empty" {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		_, _ = writer.Write([]byte(`[{"uid":"prom"}]`))
+		_ = writer.Close()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewGzipTransport(nil)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, `[{"uid":"prom"}]`, string(body))
+	require.Empty(t, resp.Header.Get("Content-Encoding"))
+
+	resp, err = client.Get(server.URL + "/empty")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestCreateDashboardGzipRequest(t *testing.T) {
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		body := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = reader
+		}
+		var saved Dashboard
+		require.NoError(t, json.NewDecoder(body).Decode(&saved))
+		require.NoError(t, json.NewEncoder(w).Encode(DashboardResponse{UID: fmt.Sprint(saved.Dashboard["uid"])}))
+	}))
+	defer server.Close()
+
+	panels := make([]any, 300)
+	for i := range panels {
+		panels[i] = map[string]any{"id": i, "title": fmt.Sprintf("Panel %d", i), "targets": []any{map[string]any{"expr": "up"}}}
+	}
+	large := Dashboard{Dashboard: map[string]any{"uid": "large", "panels": panels}}
+	small := Dashboard{Dashboard: map[string]any{"uid": "small"}}
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{Grafana: config.GrafanaConfig{GzipRequests: true}}, nil)
+	for _, dashboard := range []Dashboard{large, small} {
+		response, err := service.CreateDashboard(context.Background(), dashboard, server.URL, "test-api-key")
+		require.NoError(t, err)
+		require.Equal(t, dashboard.Dashboard["uid"], response.UID)
+	}
+
+	disabled, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	_, err := disabled.CreateDashboard(context.Background(), large, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, []string{"gzip", "", ""}, encodings)
+}
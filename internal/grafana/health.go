@@ -0,0 +1,23 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Health is the health and build information of a Grafana server
+type Health struct {
+	Version  string `json:"version"`
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+}
+
+// GetHealth returns the version of a Grafana server from its health endpoint
+func (g *grafanaImpl) GetHealth(ctx context.Context, grafanaURL, apiKey string) (*Health, error) {
+	var health Health
+	if _, err := g.doJSON(ctx, "GET", strings.TrimRight(grafanaURL, "/")+"/api/health", apiKey, nil, &health); err != nil {
+		return nil, fmt.Errorf("failed to get grafana health: %w", err)
+	}
+	return &health, nil
+}
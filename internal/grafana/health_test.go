@@ -0,0 +1,27 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestGetHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/health", r.URL.Path)
+		_, _ = w.Write([]byte(`{"commit":"83b9528bce","database":"ok","version":"11.2.0"}`))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	health, err := service.GetHealth(context.Background(), server.URL+"/", "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, &Health{Version: "11.2.0", Commit: "83b9528bce", Database: "ok"}, health)
+}
@@ -0,0 +1,206 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	oauth2 "golang.org/x/oauth2"
+	clientcredentials "golang.org/x/oauth2/clientcredentials"
+	jwt "golang.org/x/oauth2/jwt"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// Supported OAuth2 grant types
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantJWTBearer         = "jwt_bearer"
+)
+
+// OAuthReplacesAPIKey reports whether the OAuth2 token is sent in the
+// Authorization header, in which case Grafana authenticates the token itself
+// (JWT auth) and GRAFANA_API_KEY is not required
+func OAuthReplacesAPIKey(cfg *config.GrafanaConfig) bool {
+	return cfg != nil && cfg.OAuth.TokenURL != "" && strings.EqualFold(cfg.OAuth.Header, "Authorization")
+}
+
+// oauthTransport attaches an OAuth2 access token to requests for the
+// configured Grafana host. Tokens are refreshed shortly before they expire,
+// and once more when the proxy rejects a token it revoked early.
+type oauthTransport struct {
+	tokens *tokenCache
+	header string
+	host   string
+	next   http.RoundTripper
+}
+
+// tokenSource fetches a new token within the deadline of ctx
+type tokenSource func(ctx context.Context) (*oauth2.Token, error)
+
+// tokenCache reuses a token until it expires or is invalidated
+type tokenCache struct {
+	source tokenSource
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// Token returns the cached token, fetching a new one with ctx when needed
+func (c *tokenCache) Token(ctx context.Context) (*oauth2.Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token.Valid() {
+		return c.token, nil
+	}
+	token, err := c.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.token = token
+	return token, nil
+}
+
+// invalidate drops the cached token if it is still the given one
+func (c *tokenCache) invalidate(token *oauth2.Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = nil
+	}
+}
+
+// fetchToken builds a source that bypasses the caching the oauth2 package
+// layers onto its token sources, so tokenCache alone decides when to
+// refresh. Tokens are requested with client, within the deadline of the
+// request that needs them.
+func fetchToken(client *http.Client, newSource func(context.Context) oauth2.TokenSource) tokenSource {
+	return func(ctx context.Context) (*oauth2.Token, error) {
+		return newSource(context.WithValue(ctx, oauth2.HTTPClient, client)).Token()
+	}
+}
+
+// NewOAuthTransport wraps next with OAuth2 authentication when
+// GRAFANA_OAUTH_TOKEN_URL is set; otherwise next is returned unchanged.
+// Tokens are requested through base, the transport shared by the services;
+// nil uses http.DefaultTransport.
+func NewOAuthTransport(cfg *config.GrafanaConfig, base, next http.RoundTripper) (http.RoundTripper, error) {
+	if cfg == nil || cfg.OAuth.TokenURL == "" {
+		return next, nil
+	}
+
+	grafanaURL, err := neturl.Parse(cfg.URL)
+	if err != nil || grafanaURL.Host == "" {
+		return nil, fmt.Errorf("GRAFANA_OAUTH_TOKEN_URL requires a valid GRAFANA_URL to scope the token to, got %q", cfg.URL)
+	}
+
+	// Token requests go straight to the identity provider, not through the
+	// Grafana transport chain
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client := &http.Client{Transport: base, Timeout: 30 * time.Second}
+
+	var source tokenSource
+	switch cfg.OAuth.GrantType {
+	case "", GrantClientCredentials:
+		if cfg.OAuth.ClientID == "" || cfg.OAuth.ClientSecret == "" {
+			return nil, fmt.Errorf("the client_credentials grant requires GRAFANA_OAUTH_CLIENT_ID and GRAFANA_OAUTH_CLIENT_SECRET")
+		}
+		params := neturl.Values{}
+		if cfg.OAuth.Audience != "" {
+			params.Set("audience", cfg.OAuth.Audience)
+		}
+		grant := &clientcredentials.Config{
+			ClientID:       cfg.OAuth.ClientID,
+			ClientSecret:   cfg.OAuth.ClientSecret,
+			TokenURL:       cfg.OAuth.TokenURL,
+			Scopes:         cfg.OAuth.Scopes,
+			EndpointParams: params,
+		}
+		source = fetchToken(client, grant.TokenSource)
+	case GrantJWTBearer:
+		if cfg.OAuth.ClientID == "" || cfg.OAuth.PrivateKeyFile == "" {
+			return nil, fmt.Errorf("the jwt_bearer grant requires GRAFANA_OAUTH_CLIENT_ID and GRAFANA_OAUTH_PRIVATE_KEY_FILE")
+		}
+		key, err := os.ReadFile(cfg.OAuth.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GRAFANA_OAUTH_PRIVATE_KEY_FILE: %w", err)
+		}
+		grant := &jwt.Config{
+			Email:      cfg.OAuth.ClientID,
+			PrivateKey: key,
+			Subject:    cfg.OAuth.Subject,
+			Scopes:     cfg.OAuth.Scopes,
+			TokenURL:   cfg.OAuth.TokenURL,
+			Audience:   cfg.OAuth.Audience,
+		}
+		source = fetchToken(client, grant.TokenSource)
+	default:
+		return nil, fmt.Errorf("unsupported GRAFANA_OAUTH_GRANT_TYPE %q (use %s or %s)", cfg.OAuth.GrantType, GrantClientCredentials, GrantJWTBearer)
+	}
+
+	header := cfg.OAuth.Header
+	if header == "" {
+		header = "Proxy-Authorization"
+	}
+
+	return &oauthTransport{
+		tokens: &tokenCache{source: source},
+		header: http.CanonicalHeaderKey(header),
+		host:   grafanaURL.Host,
+		next:   next,
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Never hand the token to other hosts (e.g. a grafana_url argument)
+	if !strings.EqualFold(req.URL.Host, t.host) {
+		return t.next.RoundTrip(req)
+	}
+
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to obtain OAuth2 token for %s: %w", t.host, err)
+	}
+
+	resp, err := t.next.RoundTrip(t.authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	// The token was rejected before its expiry; fetch a fresh one and retry once
+	t.tokens.invalidate(token)
+	token, tokenErr := t.tokens.Token(req.Context())
+	if tokenErr != nil {
+		return resp, nil
+	}
+	retry := t.authorize(req, token)
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	_ = resp.Body.Close()
+	return t.next.RoundTrip(retry)
+}
+
+// authorize returns a copy of req carrying the token
+func (t *oauthTransport) authorize(req *http.Request, token *oauth2.Token) *http.Request {
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set(t.header, token.Type()+" "+token.AccessToken)
+	return authorized
+}
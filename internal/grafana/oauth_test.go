@@ -0,0 +1,218 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// newTokenServer issues numbered client-credentials tokens
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var issued int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		require.Equal(t, "grafana", r.Form.Get("audience"))
+
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func oauthConfig(tokenURL, grafanaURL string) *config.Config {
+	cfg := &config.Config{}
+	cfg.Grafana.URL = grafanaURL
+	cfg.Grafana.OAuth = config.GrafanaOAuthConfig{
+		TokenURL:     tokenURL,
+		GrantType:    GrantClientCredentials,
+		ClientID:     "agent",
+		ClientSecret: "secret",
+		Audience:     "grafana",
+		Header:       "Proxy-Authorization",
+	}
+	return cfg
+}
+
+func TestOAuthTransport(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+
+	var seen []string
+	grafanaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Proxy-Authorization"))
+		require.Equal(t, "Bearer api-key", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode([]Folder{})
+	}))
+	defer grafanaServer.Close()
+
+	svc, err := NewGrafanaService(zap.NewNop(), oauthConfig(tokenServer.URL, grafanaServer.URL), nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = svc.ListFolders(context.Background(), "", grafanaServer.URL, "api-key")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, seen)
+	require.EqualValues(t, 1, atomic.LoadInt32(issued), "token should be reused until it expires")
+}
+
+func TestOAuthTransportRefreshesExpiredToken(t *testing.T) {
+	// Tokens expiring within the refresh margin are never reused
+	tokenServer, issued := newTokenServer(t, 5)
+
+	grafanaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]Folder{})
+	}))
+	defer grafanaServer.Close()
+
+	svc, err := NewGrafanaService(zap.NewNop(), oauthConfig(tokenServer.URL, grafanaServer.URL), nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = svc.ListFolders(context.Background(), "", grafanaServer.URL, "api-key")
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 2, atomic.LoadInt32(issued))
+}
+
+func TestOAuthTransportRetriesRejectedToken(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+
+	grafanaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "Payments", body["title"])
+		_ = json.NewEncoder(w).Encode(Folder{UID: "payments", Title: "Payments"})
+	}))
+	defer grafanaServer.Close()
+
+	svc, err := NewGrafanaService(zap.NewNop(), oauthConfig(tokenServer.URL, grafanaServer.URL), nil)
+	require.NoError(t, err)
+
+	folder, err := svc.CreateFolder(context.Background(), "Payments", "", grafanaServer.URL, "api-key")
+	require.NoError(t, err)
+	require.Equal(t, "payments", folder.UID)
+	require.EqualValues(t, 2, atomic.LoadInt32(issued))
+}
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	requests int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOAuthTransportFetchesTokensThroughSharedTransport(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+
+	grafanaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]Folder{})
+	}))
+	defer grafanaServer.Close()
+
+	shared := &countingTransport{}
+	svc, err := NewGrafanaService(zap.NewNop(), oauthConfig(tokenServer.URL, grafanaServer.URL), shared)
+	require.NoError(t, err)
+
+	_, err = svc.ListFolders(context.Background(), "", grafanaServer.URL, "api-key")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(issued))
+	require.EqualValues(t, 2, atomic.LoadInt32(&shared.requests), "the token and folder requests should share the transport")
+}
+
+func TestOAuthTransportFetchesTokensWithinRequestDeadline(t *testing.T) {
+	release := make(chan struct{})
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer tokenServer.Close()
+	defer close(release)
+
+	cfg := oauthConfig(tokenServer.URL, "https://grafana.internal.example")
+	svc, err := NewGrafanaService(zap.NewNop(), cfg, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err = svc.ListFolders(ctx, "", cfg.Grafana.URL, "api-key")
+	require.Error(t, err)
+	require.Less(t, time.Since(started), 5*time.Second, "the token request should stop at the request deadline")
+}
+
+func TestOAuthTransportScopedToGrafanaHost(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+
+	otherServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.Header.Get("Proxy-Authorization"))
+		_ = json.NewEncoder(w).Encode([]Folder{})
+	}))
+	defer otherServer.Close()
+
+	svc, err := NewGrafanaService(zap.NewNop(), oauthConfig(tokenServer.URL, "https://grafana.internal.example"), nil)
+	require.NoError(t, err)
+
+	_, err = svc.ListFolders(context.Background(), "", otherServer.URL, "api-key")
+	require.NoError(t, err)
+	require.EqualValues(t, 0, atomic.LoadInt32(issued))
+}
+
+func TestNewOAuthTransportValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cfg *config.GrafanaConfig)
+		errMsg string
+	}{
+		{name: "missing grafana url", mutate: func(cfg *config.GrafanaConfig) { cfg.URL = "" }, errMsg: "valid GRAFANA_URL"},
+		{name: "missing client secret", mutate: func(cfg *config.GrafanaConfig) { cfg.OAuth.ClientSecret = "" }, errMsg: "GRAFANA_OAUTH_CLIENT_SECRET"},
+		{name: "missing private key", mutate: func(cfg *config.GrafanaConfig) { cfg.OAuth.GrantType = GrantJWTBearer }, errMsg: "GRAFANA_OAUTH_PRIVATE_KEY_FILE"},
+		{name: "unknown grant", mutate: func(cfg *config.GrafanaConfig) { cfg.OAuth.GrantType = "password" }, errMsg: "unsupported GRAFANA_OAUTH_GRANT_TYPE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := oauthConfig("https://idp.example/token", "https://grafana.example")
+			tt.mutate(&cfg.Grafana)
+
+			_, err := NewOAuthTransport(&cfg.Grafana, nil, http.DefaultTransport)
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestOAuthReplacesAPIKey(t *testing.T) {
+	cfg := oauthConfig("https://idp.example/token", "https://grafana.example")
+	require.False(t, OAuthReplacesAPIKey(&cfg.Grafana))
+
+	cfg.Grafana.OAuth.Header = "authorization"
+	require.True(t, OAuthReplacesAPIKey(&cfg.Grafana))
+
+	require.False(t, OAuthReplacesAPIKey(&config.GrafanaConfig{OAuth: config.GrafanaOAuthConfig{Header: "Authorization"}}))
+}
@@ -0,0 +1,68 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OrgHeader selects the organization a Grafana request runs in
+const OrgHeader = "X-Grafana-Org-Id"
+
+// orgKey is the context key of the organization ID of a request
+type orgKey struct{}
+
+// Org is an organization the token's user belongs to, with the user's role in it
+type Org struct {
+	OrgID int    `json:"orgId"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+// WithOrgID returns a context whose Grafana requests run in the given
+// organization instead of the token's current one
+func WithOrgID(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// OrgIDFromContext returns the organization set with WithOrgID, 0 when none is
+func OrgIDFromContext(ctx context.Context) int {
+	orgID, _ := ctx.Value(orgKey{}).(int)
+	return orgID
+}
+
+// orgTransport sets the organization header from the request context
+type orgTransport struct {
+	next http.RoundTripper
+}
+
+// newOrgTransport wraps next so requests carry the organization of their context
+func newOrgTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &orgTransport{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *orgTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	orgID := OrgIDFromContext(req.Context())
+	if orgID <= 0 || req.Header.Get(OrgHeader) != "" {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(OrgHeader, strconv.Itoa(orgID))
+	return t.next.RoundTrip(req)
+}
+
+// ListOrgs lists the organizations the token's user belongs to
+func (g *grafanaImpl) ListOrgs(ctx context.Context, grafanaURL, apiKey string) ([]Org, error) {
+	var orgs []Org
+	url := strings.TrimRight(grafanaURL, "/") + "/api/user/orgs"
+	if _, err := g.doJSON(ctx, "GET", url, apiKey, nil, &orgs); err != nil {
+		return nil, fmt.Errorf("failed to list the organizations of the current user: %w", err)
+	}
+	return orgs, nil
+}
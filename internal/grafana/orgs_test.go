@@ -0,0 +1,52 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestListOrgs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/user/orgs", r.URL.Path)
+		require.Empty(t, r.Header.Get(OrgHeader))
+		_, _ = w.Write([]byte(`[{"orgId":1,"name":"Main","role":"Admin"},{"orgId":2,"name":"Payments","role":"Editor"}]`))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	orgs, err := service.ListOrgs(context.Background(), server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, []Org{{OrgID: 1, Name: "Main", Role: RoleAdmin}, {OrgID: 2, Name: "Payments", Role: RoleEditor}}, orgs)
+}
+
+func TestOrgIDFromContext(t *testing.T) {
+	seen := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen[r.Header.Get(OrgHeader)]++
+		_, _ = w.Write([]byte(`[{"uid":"f1","title":"Team"}]`))
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{Grafana: config.GrafanaConfig{CacheTTL: time.Minute}}, nil)
+	for _, ctx := range []context.Context{
+		context.Background(),
+		WithOrgID(context.Background(), 2),
+		WithOrgID(context.Background(), 2),
+		WithOrgID(context.Background(), 3),
+	} {
+		_, err := service.ListFolders(ctx, "", server.URL, "test-api-key")
+		require.NoError(t, err)
+	}
+
+	// Each organization is requested, and cached, separately
+	require.Equal(t, map[string]int{"": 1, "2": 1, "3": 1}, seen)
+}
@@ -0,0 +1,75 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	zap "go.uber.org/zap"
+)
+
+// Preferences are the org or team preferences relevant to the agent
+type Preferences struct {
+	HomeDashboardUID string `json:"homeDashboardUID,omitempty"`
+	Timezone         string `json:"timezone,omitempty"`
+	WeekStart        string `json:"weekStart,omitempty"`
+}
+
+// Team is a Grafana team
+type Team struct {
+	ID   int    `json:"id"`
+	UID  string `json:"uid,omitempty"`
+	Name string `json:"name"`
+}
+
+// preferencesURL builds the preferences endpoint of the org (teamID 0) or a team
+func preferencesURL(grafanaURL string, teamID int) string {
+	grafanaURL = strings.TrimRight(grafanaURL, "/")
+	if teamID == 0 {
+		return grafanaURL + "/api/org/preferences"
+	}
+	return fmt.Sprintf("%s/api/teams/%d/preferences", grafanaURL, teamID)
+}
+
+// GetPreferences returns the preferences of the org (teamID 0) or a team
+func (g *grafanaImpl) GetPreferences(ctx context.Context, teamID int, grafanaURL, apiKey string) (*Preferences, error) {
+	var preferences Preferences
+	if _, err := g.doJSON(ctx, "GET", preferencesURL(grafanaURL, teamID), apiKey, nil, &preferences); err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+// SetHomeDashboard sets the home dashboard of the org (teamID 0) or a team,
+// leaving the other preferences untouched
+func (g *grafanaImpl) SetHomeDashboard(ctx context.Context, teamID int, dashboardUID, grafanaURL, apiKey string) error {
+	body := Preferences{HomeDashboardUID: dashboardUID}
+	if _, err := g.doJSON(ctx, "PATCH", preferencesURL(grafanaURL, teamID), apiKey, body, nil); err != nil {
+		return fmt.Errorf("failed to set home dashboard: %w", err)
+	}
+
+	g.logger.Info("Home dashboard set successfully",
+		zap.String("dashboard_uid", dashboardUID),
+		zap.Int("team_id", teamID))
+	return nil
+}
+
+// FindTeam looks up a team by its exact name (case-insensitive)
+func (g *grafanaImpl) FindTeam(ctx context.Context, name, grafanaURL, apiKey string) (*Team, error) {
+	url := fmt.Sprintf("%s/api/teams/search?name=%s", strings.TrimRight(grafanaURL, "/"), neturl.QueryEscape(name))
+
+	var response struct {
+		Teams []Team `json:"teams"`
+	}
+	if _, err := g.doJSON(ctx, "GET", url, apiKey, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to search teams: %w", err)
+	}
+
+	for i := range response.Teams {
+		if strings.EqualFold(response.Teams[i].Name, name) {
+			return &response.Teams[i], nil
+		}
+	}
+	return nil, fmt.Errorf("team %q not found", name)
+}
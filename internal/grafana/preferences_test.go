@@ -0,0 +1,69 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestSetHomeDashboard(t *testing.T) {
+	var patched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]any{"homeDashboardUID": "overview"}, body, "other preferences must not be reset")
+
+		patched = append(patched, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "Preferences updated"})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	require.NoError(t, service.SetHomeDashboard(context.Background(), 0, "overview", server.URL, "test-api-key"))
+	require.NoError(t, service.SetHomeDashboard(context.Background(), 7, "overview", server.URL, "test-api-key"))
+	require.Equal(t, []string{"/api/org/preferences", "/api/teams/7/preferences"}, patched)
+}
+
+func TestGetPreferences(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/org/preferences", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{"homeDashboardId": 3, "homeDashboardUID": "old-home", "theme": "dark"})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	preferences, err := service.GetPreferences(context.Background(), 0, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "old-home", preferences.HomeDashboardUID)
+}
+
+func TestFindTeam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/teams/search", r.URL.Path)
+		teams := []Team{}
+		if r.URL.Query().Get("name") == "payments" {
+			teams = append(teams, Team{ID: 7, Name: "Payments"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"teams": teams})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	team, err := service.FindTeam(context.Background(), "payments", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, 7, team.ID)
+
+	_, err = service.FindTeam(context.Background(), "sre", server.URL, "test-api-key")
+	require.ErrorContains(t, err, "not found")
+}
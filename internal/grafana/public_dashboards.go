@@ -0,0 +1,102 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	zap "go.uber.org/zap"
+)
+
+// PublicDashboard is the public (unauthenticated) share of a dashboard
+type PublicDashboard struct {
+	UID                  string `json:"uid,omitempty"`
+	DashboardUID         string `json:"dashboardUid,omitempty"`
+	Title                string `json:"title,omitempty"`
+	AccessToken          string `json:"accessToken,omitempty"`
+	IsEnabled            bool   `json:"isEnabled"`
+	TimeSelectionEnabled bool   `json:"timeSelectionEnabled"`
+	AnnotationsEnabled   bool   `json:"annotationsEnabled"`
+	Share                string `json:"share,omitempty"`
+}
+
+// publicDashboardsPageSize is the page size used when listing public dashboards
+const publicDashboardsPageSize = 100
+
+// publicDashboardURL builds the public-dashboards endpoint of a dashboard
+func publicDashboardURL(grafanaURL, dashboardUID string) string {
+	return fmt.Sprintf("%s/api/dashboards/uid/%s/public-dashboards", strings.TrimRight(grafanaURL, "/"), neturl.PathEscape(dashboardUID))
+}
+
+// GetPublicDashboard returns the public dashboard of a dashboard, or nil when
+// the dashboard is not shared publicly
+func (g *grafanaImpl) GetPublicDashboard(ctx context.Context, dashboardUID, grafanaURL, apiKey string) (*PublicDashboard, error) {
+	var publicDashboard PublicDashboard
+	status, err := g.doJSON(ctx, "GET", publicDashboardURL(grafanaURL, dashboardUID), apiKey, nil, &publicDashboard)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public dashboard: %w", err)
+	}
+	// Older Grafana versions answer 200 with an empty object
+	if publicDashboard.UID == "" {
+		return nil, nil
+	}
+	return &publicDashboard, nil
+}
+
+// ListPublicDashboards lists every public dashboard of the org
+func (g *grafanaImpl) ListPublicDashboards(ctx context.Context, grafanaURL, apiKey string) ([]PublicDashboard, error) {
+	var publicDashboards []PublicDashboard
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/dashboards/public-dashboards?perpage=%d&page=%d", strings.TrimRight(grafanaURL, "/"), publicDashboardsPageSize, page)
+
+		var response struct {
+			PublicDashboards []PublicDashboard `json:"publicDashboards"`
+			TotalCount       int               `json:"totalCount"`
+		}
+		if _, err := g.doJSON(ctx, "GET", url, apiKey, nil, &response); err != nil {
+			return nil, fmt.Errorf("failed to list public dashboards: %w", err)
+		}
+
+		publicDashboards = append(publicDashboards, response.PublicDashboards...)
+		if len(response.PublicDashboards) < publicDashboardsPageSize || len(publicDashboards) >= response.TotalCount {
+			return publicDashboards, nil
+		}
+	}
+}
+
+// CreatePublicDashboard shares a dashboard publicly
+func (g *grafanaImpl) CreatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard PublicDashboard, grafanaURL, apiKey string) (*PublicDashboard, error) {
+	var created PublicDashboard
+	if _, err := g.doJSON(ctx, "POST", publicDashboardURL(grafanaURL, dashboardUID), apiKey, publicDashboard, &created); err != nil {
+		return nil, fmt.Errorf("failed to create public dashboard: %w", err)
+	}
+
+	g.logger.Info("Public dashboard created successfully",
+		zap.String("dashboard_uid", dashboardUID),
+		zap.String("uid", created.UID),
+		zap.Bool("enabled", created.IsEnabled))
+
+	return &created, nil
+}
+
+// UpdatePublicDashboard updates the settings of an existing public dashboard
+func (g *grafanaImpl) UpdatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard PublicDashboard, grafanaURL, apiKey string) (*PublicDashboard, error) {
+	url := fmt.Sprintf("%s/%s", publicDashboardURL(grafanaURL, dashboardUID), neturl.PathEscape(publicDashboard.UID))
+
+	var updated PublicDashboard
+	if _, err := g.doJSON(ctx, "PATCH", url, apiKey, publicDashboard, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update public dashboard: %w", err)
+	}
+
+	g.logger.Info("Public dashboard updated successfully",
+		zap.String("dashboard_uid", dashboardUID),
+		zap.String("uid", updated.UID),
+		zap.Bool("enabled", updated.IsEnabled))
+
+	return &updated, nil
+}
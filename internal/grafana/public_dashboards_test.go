@@ -0,0 +1,94 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestGetPublicDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		if r.URL.Path != "/api/dashboards/uid/status/public-dashboards" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(PublicDashboard{UID: "pd1", DashboardUID: "status", AccessToken: "abc", IsEnabled: true})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	publicDashboard, err := service.GetPublicDashboard(context.Background(), "status", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "abc", publicDashboard.AccessToken)
+
+	publicDashboard, err = service.GetPublicDashboard(context.Background(), "private", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Nil(t, publicDashboard)
+}
+
+func TestListPublicDashboardsPaginates(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/dashboards/public-dashboards", r.URL.Path)
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		count := publicDashboardsPageSize
+		if page == "2" {
+			count = 1
+		}
+		var items []PublicDashboard
+		for i := 0; i < count; i++ {
+			items = append(items, PublicDashboard{UID: fmt.Sprintf("pd-%s-%d", page, i)})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"publicDashboards": items, "totalCount": publicDashboardsPageSize + 1})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	publicDashboards, err := service.ListPublicDashboards(context.Background(), server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, publicDashboards, publicDashboardsPageSize+1)
+	require.Equal(t, []string{"1", "2"}, pages)
+}
+
+func TestCreateAndUpdatePublicDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body PublicDashboard
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/dashboards/uid/status/public-dashboards":
+			require.True(t, body.IsEnabled)
+			body.UID, body.AccessToken = "pd1", "abc"
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/dashboards/uid/status/public-dashboards/pd1":
+			require.False(t, body.IsEnabled)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	created, err := service.CreatePublicDashboard(context.Background(), "status", PublicDashboard{IsEnabled: true, Share: "public"}, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "pd1", created.UID)
+
+	created.IsEnabled = false
+	updated, err := service.UpdatePublicDashboard(context.Background(), "status", *created, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.False(t, updated.IsEnabled)
+}
@@ -0,0 +1,62 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+	"strings"
+)
+
+// DashboardHit is a dashboard found by the search API
+type DashboardHit struct {
+	ID          int      `json:"id"`
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	FolderUID   string   `json:"folderUid,omitempty"`
+	FolderTitle string   `json:"folderTitle,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// dashboardSearchPageSize is the largest page the search API returns
+const dashboardSearchPageSize = 1000
+
+// defaultSearchMaxResults bounds a search when GRAFANA_SEARCH_MAX_RESULTS is unset
+const defaultSearchMaxResults = 10000
+
+// SearchDashboards lists the dashboards whose title matches query, or every
+// dashboard when query is empty, requesting page after page until a short
+// page. A search matching more than the configured maximum fails rather
+// than returning a partial list. Responses are cached until a dashboard or
+// folder is saved or deleted, or the cache TTL passes.
+func (g *grafanaImpl) SearchDashboards(ctx context.Context, query, grafanaURL, apiKey string) ([]DashboardHit, error) {
+	maxResults := g.searchMaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSearchMaxResults
+	}
+
+	var hits []DashboardHit
+	for page := 1; ; page++ {
+		params := neturl.Values{}
+		params.Set("type", "dash-db")
+		params.Set("limit", fmt.Sprint(dashboardSearchPageSize))
+		params.Set("page", fmt.Sprint(page))
+		if query != "" {
+			params.Set("query", query)
+		}
+
+		var pageHits []DashboardHit
+		url := strings.TrimRight(grafanaURL, "/") + searchPath + "?" + params.Encode()
+		if err := g.cachedGet(ctx, url, apiKey, &pageHits); err != nil {
+			return nil, fmt.Errorf("failed to search dashboards: %w", err)
+		}
+		hits = append(hits, pageHits...)
+
+		if len(hits) > maxResults {
+			return nil, fmt.Errorf("dashboard search must match at most %d dashboards; narrow the query or raise GRAFANA_SEARCH_MAX_RESULTS", maxResults)
+		}
+		if len(pageHits) < dashboardSearchPageSize {
+			return hits, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestSearchDashboards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/search", r.URL.Path)
+		require.Equal(t, "dash-db", r.URL.Query().Get("type"))
+		require.Equal(t, "checkout", r.URL.Query().Get("query"))
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode([]DashboardHit{
+			{ID: 1, UID: "checkout", Title: "Checkout", FolderUID: "platform", FolderTitle: "Platform"},
+		})
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	hits, err := service.SearchDashboards(context.Background(), "checkout", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, "Platform", hits[0].FolderTitle)
+}
+
+func TestSearchDashboardsPagination(t *testing.T) {
+	const total = 2*dashboardSearchPageSize + 5
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, strconv.Itoa(dashboardSearchPageSize), r.URL.Query().Get("limit"))
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		var hits []DashboardHit
+		for i := (page - 1) * dashboardSearchPageSize; i < min(page*dashboardSearchPageSize, total); i++ {
+			hits = append(hits, DashboardHit{UID: strconv.Itoa(i)})
+		}
+		_ = json.NewEncoder(w).Encode(hits)
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	hits, err := service.SearchDashboards(context.Background(), "", server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Len(t, hits, total)
+	require.Equal(t, "2004", hits[total-1].UID)
+	require.Equal(t, []string{"1", "2", "3"}, pages)
+
+	limited, _ := NewGrafanaService(zap.NewNop(), &config.Config{Grafana: config.GrafanaConfig{SearchMaxResults: 1500}}, nil)
+	_, err = limited.SearchDashboards(context.Background(), "", server.URL, "test-api-key")
+	require.ErrorContains(t, err, "at most 1500 dashboards")
+}
@@ -0,0 +1,52 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AlertRuleUIDLabel is the label Grafana adds to the alert instances of a
+// Grafana-managed rule
+const AlertRuleUIDLabel = "__alert_rule_uid__"
+
+// Silence is a silence of the Grafana-managed Alertmanager
+type Silence struct {
+	Matchers  []SilenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// SilenceMatcher selects the alerts a silence mutes by label
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// silencesPath is the Alertmanager API of Grafana's own Alertmanager
+const silencesPath = "/api/alertmanager/grafana/api/v2/"
+
+// CreateSilence creates a silence and returns its ID
+func (g *grafanaImpl) CreateSilence(ctx context.Context, silence Silence, grafanaURL, apiKey string) (string, error) {
+	var created struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if _, err := g.doJSON(ctx, "POST", strings.TrimRight(grafanaURL, "/")+silencesPath+"silences", apiKey, silence, &created); err != nil {
+		return "", fmt.Errorf("failed to create silence: %w", err)
+	}
+	return created.SilenceID, nil
+}
+
+// DeleteSilence expires a silence
+func (g *grafanaImpl) DeleteSilence(ctx context.Context, id, grafanaURL, apiKey string) error {
+	if _, err := g.doJSON(ctx, "DELETE", strings.TrimRight(grafanaURL, "/")+silencesPath+"silence/"+url.PathEscape(id), apiKey, nil, nil); err != nil {
+		return fmt.Errorf("failed to expire silence %s: %w", id, err)
+	}
+	return nil
+}
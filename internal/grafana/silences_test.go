@@ -0,0 +1,53 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestSilences(t *testing.T) {
+	var created Silence
+	deleted := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/alertmanager/grafana/api/v2/silences":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"silenceID": "silence-1"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/alertmanager/grafana/api/v2/silence/silence-1":
+			deleted = "silence-1"
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+
+	start := time.Date(2026, 1, 1, 22, 0, 0, 0, time.UTC)
+	id, err := service.CreateSilence(context.Background(), Silence{
+		Matchers:  []SilenceMatcher{{Name: AlertRuleUIDLabel, Value: "a|b", IsRegex: true, IsEqual: true}},
+		StartsAt:  start,
+		EndsAt:    start.Add(time.Hour),
+		CreatedBy: "grafana-agent",
+	}, server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "silence-1", id)
+	require.Equal(t, "a|b", created.Matchers[0].Value)
+	require.True(t, created.EndsAt.Equal(start.Add(time.Hour)))
+
+	require.NoError(t, service.DeleteSilence(context.Background(), id, server.URL, "test-api-key"))
+	require.Equal(t, "silence-1", deleted)
+	require.Error(t, service.DeleteSilence(context.Background(), "missing", server.URL, "test-api-key"))
+}
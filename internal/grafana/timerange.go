@@ -0,0 +1,108 @@
+package grafana
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTimeRange resolves a Grafana dashboard time range (e.g. "now-6h" to
+// "now") into absolute timestamps relative to now
+func ParseTimeRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	start, err := ParseTime(from, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid time range start %q: %w", from, err)
+	}
+
+	end, err := ParseTime(to, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid time range end %q: %w", to, err)
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("time range start %q must be before end %q", from, to)
+	}
+
+	return start, end, nil
+}
+
+// ParseTime resolves a single Grafana time expression. Supported forms are
+// relative expressions ("now", "now-6h", "now-7d"), RFC3339 timestamps and
+// epoch milliseconds. Rounding suffixes such as "/d" are ignored.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty time expression")
+	}
+
+	if strings.HasPrefix(value, "now") {
+		expr := strings.TrimPrefix(value, "now")
+		if idx := strings.Index(expr, "/"); idx >= 0 {
+			expr = expr[:idx]
+		}
+		if expr == "" {
+			return now, nil
+		}
+
+		sign := time.Duration(1)
+		switch expr[0] {
+		case '-':
+			sign = -1
+		case '+':
+		default:
+			return time.Time{}, fmt.Errorf("unsupported relative time %q", value)
+		}
+
+		d, err := ParseDuration(expr[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(sign * d), nil
+	}
+
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unsupported time expression %q", value)
+	}
+	return t, nil
+}
+
+// ParseDuration parses Grafana/Prometheus style durations, which in addition
+// to Go durations accept days (d), weeks (w) and years (y)
+func ParseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	unit := value[len(value)-1]
+	multiplier := time.Duration(0)
+	switch unit {
+	case 'd':
+		multiplier = 24 * time.Hour
+	case 'w':
+		multiplier = 7 * 24 * time.Hour
+	case 'y':
+		multiplier = 365 * 24 * time.Hour
+	case 'M':
+		multiplier = 30 * 24 * time.Hour
+	}
+
+	if multiplier > 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * multiplier, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,98 @@
+package grafana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "relative hours",
+			from:      "now-6h",
+			to:        "now",
+			wantStart: now.Add(-6 * time.Hour),
+			wantEnd:   now,
+		},
+		{
+			name:      "relative days with rounding suffix",
+			from:      "now-7d/d",
+			to:        "now",
+			wantStart: now.Add(-7 * 24 * time.Hour),
+			wantEnd:   now,
+		},
+		{
+			name:      "absolute timestamps",
+			from:      "2024-04-30T12:00:00Z",
+			to:        "1714564800000",
+			wantStart: now.Add(-24 * time.Hour),
+			wantEnd:   now,
+		},
+		{
+			name:    "inverted range",
+			from:    "now",
+			to:      "now-1h",
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			from:    "yesterday",
+			to:      "now",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseTimeRange(tt.from, tt.to, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", start, tt.wantStart)
+			}
+			if !end.Equal(tt.wantEnd) {
+				t.Errorf("end = %v, want %v", end, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"5m":  5 * time.Minute,
+		"2d":  48 * time.Hour,
+		"1w":  7 * 24 * time.Hour,
+		"90s": 90 * time.Second,
+	}
+
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	if _, err := ParseDuration("abc"); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
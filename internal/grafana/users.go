@@ -0,0 +1,53 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Organization roles, from least to most privileged
+const (
+	RoleNone   = "None"
+	RoleViewer = "Viewer"
+	RoleEditor = "Editor"
+	RoleAdmin  = "Admin"
+)
+
+// CurrentUser is the user or service account a token authenticates as, with
+// its role in the token's current organization
+type CurrentUser struct {
+	ID             int    `json:"id"`
+	Login          string `json:"login"`
+	OrgID          int    `json:"orgId"`
+	Role           string `json:"role"`
+	IsGrafanaAdmin bool   `json:"isGrafanaAdmin"`
+}
+
+// CanEdit reports whether the user may save dashboards and folders
+func (u *CurrentUser) CanEdit() bool {
+	return u.IsGrafanaAdmin || u.Role == RoleEditor || u.Role == RoleAdmin
+}
+
+// GetCurrentUser returns the identity of a token and its organization role
+func (g *grafanaImpl) GetCurrentUser(ctx context.Context, grafanaURL, apiKey string) (*CurrentUser, error) {
+	base := strings.TrimRight(grafanaURL, "/")
+
+	var user CurrentUser
+	if _, err := g.doJSON(ctx, "GET", base+"/api/user", apiKey, nil, &user); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	orgs, err := g.ListOrgs(ctx, grafanaURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = RoleNone
+	for _, org := range orgs {
+		if org.OrgID == user.OrgID {
+			user.Role = org.Role
+		}
+	}
+	return &user, nil
+}
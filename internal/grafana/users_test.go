@@ -0,0 +1,39 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestGetCurrentUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-api-key", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/user":
+			_, _ = w.Write([]byte(`{"id":7,"login":"sa-grafana-agent","orgId":2,"isGrafanaAdmin":false}`))
+		case "/api/user/orgs":
+			_, _ = w.Write([]byte(`[{"orgId":1,"name":"Main","role":"Admin"},{"orgId":2,"name":"Payments","role":"Viewer"}]`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+	user, err := service.GetCurrentUser(context.Background(), server.URL, "test-api-key")
+	require.NoError(t, err)
+	require.Equal(t, "sa-grafana-agent", user.Login)
+	require.Equal(t, RoleViewer, user.Role)
+	require.False(t, user.CanEdit())
+
+	user.Role = RoleEditor
+	require.True(t, user.CanEdit())
+}
@@ -0,0 +1,57 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"strings"
+)
+
+// DashboardVersion is a saved version of a dashboard
+type DashboardVersion struct {
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Message string `json:"message,omitempty"`
+	// Data is the dashboard model of this version
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// dashboardVersionsPageSize bounds how far back versions are searched
+const dashboardVersionsPageSize = 100
+
+// GetDashboardVersion returns the dashboard model saved as the given version
+// number. Versions are looked up by number in the version history because
+// the versions endpoint addresses them by their internal id.
+func (g *grafanaImpl) GetDashboardVersion(ctx context.Context, uid string, version int, grafanaURL, apiKey string) (*DashboardVersion, error) {
+	base := fmt.Sprintf("%s/api/dashboards/uid/%s/versions", strings.TrimRight(grafanaURL, "/"), neturl.PathEscape(uid))
+
+	var raw json.RawMessage
+	if _, err := g.doJSON(ctx, "GET", fmt.Sprintf("%s?limit=%d", base, dashboardVersionsPageSize), apiKey, nil, &raw); err != nil {
+		return nil, fmt.Errorf("failed to list dashboard versions: %w", err)
+	}
+
+	// Newer Grafana versions wrap the history in an object
+	var versions []DashboardVersion
+	if err := json.Unmarshal(raw, &versions); err != nil {
+		var wrapped struct {
+			Versions []DashboardVersion `json:"versions"`
+		}
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		versions = wrapped.Versions
+	}
+
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+		var full DashboardVersion
+		if _, err := g.doJSON(ctx, "GET", fmt.Sprintf("%s/%d", base, v.ID), apiKey, nil, &full); err != nil {
+			return nil, fmt.Errorf("failed to get dashboard version %d: %w", version, err)
+		}
+		return &full, nil
+	}
+	return nil, fmt.Errorf("dashboard %s version %d not found", uid, version)
+}
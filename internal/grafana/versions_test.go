@@ -0,0 +1,44 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestGetDashboardVersion(t *testing.T) {
+	for _, wrapped := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/dashboards/uid/checkout/versions":
+				versions := []DashboardVersion{{ID: 31, Version: 3}, {ID: 30, Version: 2}}
+				if wrapped {
+					_ = json.NewEncoder(w).Encode(map[string]any{"versions": versions})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(versions)
+			case "/api/dashboards/uid/checkout/versions/30":
+				_ = json.NewEncoder(w).Encode(DashboardVersion{ID: 30, Version: 2, Data: map[string]any{"title": "Checkout"}})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		service, _ := NewGrafanaService(zap.NewNop(), &config.Config{}, nil)
+		version, err := service.GetDashboardVersion(context.Background(), "checkout", 2, server.URL, "test-api-key")
+		require.NoError(t, err)
+		require.Equal(t, "Checkout", version.Data["title"])
+
+		_, err = service.GetDashboardVersion(context.Background(), "checkout", 9, server.URL, "test-api-key")
+		require.ErrorContains(t, err, "not found")
+		server.Close()
+	}
+}
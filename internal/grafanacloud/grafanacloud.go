@@ -0,0 +1,295 @@
+package grafanacloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
+)
+
+// serviceAccountName is the stack service account the agent mints tokens for
+const serviceAccountName = "grafana-agent"
+
+// tokenRefreshMargin is how long before expiry a cached stack token is replaced
+const tokenRefreshMargin = 5 * time.Minute
+
+// Stack resolution errors
+var (
+	ErrNotConfigured  = errors.New("grafana cloud is not configured - set GRAFANA_CLOUD_ORG and GRAFANA_CLOUD_TOKEN")
+	ErrStackNotFound  = errors.New("grafana cloud stack not found")
+	ErrAmbiguousStack = errors.New("grafana cloud stack is ambiguous")
+)
+
+// Stack represents a Grafana Cloud stack (hosted Grafana instance)
+type Stack struct {
+	ID         int    `json:"id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	RegionSlug string `json:"regionSlug"`
+	OrgSlug    string `json:"orgSlug"`
+	// PrometheusURL and PrometheusUserID are the stack's hosted metrics
+	// instance, the remote_write target and its basic auth username
+	PrometheusURL    string `json:"hmInstancePromUrl,omitempty"`
+	PrometheusUserID int    `json:"hmInstancePromId,omitempty"`
+	// ActiveSeries is the number of series active in the hosted metrics
+	// instance
+	ActiveSeries int `json:"hmInstancePromCurrentActiveSeries,omitempty"`
+}
+
+// StackCredentials are the URL and a short-lived service-account token for a stack
+type StackCredentials struct {
+	Stack     Stack
+	Token     string
+	ExpiresAt time.Time
+}
+
+// GrafanaCloud represents the grafanacloud service interface
+// Grafana Cloud service for resolving stacks and their credentials and
+// reading the org's usage
+type GrafanaCloud interface {
+	// ListStacks lists the stacks of the configured Grafana Cloud org
+	ListStacks(ctx context.Context) ([]Stack, error)
+	// ResolveStack finds a stack by slug, name or a unique partial match ("prod")
+	ResolveStack(ctx context.Context, query string) (*Stack, error)
+	// StackCredentials returns the stack URL and a service-account token,
+	// minting (and caching) a new token when needed
+	StackCredentials(ctx context.Context, query string) (*StackCredentials, error)
+	// Usage returns the org's current active series, log ingestion and
+	// users against what its plan includes
+	Usage(ctx context.Context) (*Usage, error)
+}
+
+// grafanaCloudImpl is the implementation of GrafanaCloud
+type grafanaCloudImpl struct {
+	logger *zap.Logger
+	client *http.Client
+	config config.GrafanaCloudConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]*StackCredentials
+}
+
+// NewGrafanaCloudService creates a new instance of GrafanaCloud sending
+// requests through transport, shared with the other services; nil uses
+// http.DefaultTransport
+func NewGrafanaCloudService(logger *zap.Logger, cfg *config.Config, transport http.RoundTripper) (GrafanaCloud, error) {
+	logger.Info("initializing grafana cloud service")
+
+	breaker := circuit.NewBreaker(logger, "grafana-cloud", cfg.Circuit.FailureThreshold, cfg.Circuit.Cooldown)
+
+	return &grafanaCloudImpl{
+		logger: logger,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: breaker.Transport(transport),
+		},
+		config: cfg.Grafana.Cloud,
+		now:    time.Now,
+		tokens: map[string]*StackCredentials{},
+	}, nil
+}
+
+// ListStacks lists the stacks of the configured org
+func (g *grafanaCloudImpl) ListStacks(ctx context.Context) ([]Stack, error) {
+	if g.config.Org == "" || g.config.Token == "" {
+		return nil, ErrNotConfigured
+	}
+
+	var response struct {
+		Items []Stack `json:"items"`
+	}
+	path := fmt.Sprintf("/api/orgs/%s/instances", neturl.PathEscape(g.config.Org))
+	if err := g.do(ctx, "GET", path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list grafana cloud stacks: %w", err)
+	}
+
+	return response.Items, nil
+}
+
+// ResolveStack matches a stack by exact slug, then exact name, then a unique
+// case-insensitive substring of either
+func (g *grafanaCloudImpl) ResolveStack(ctx context.Context, query string) (*Stack, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("stack is required")
+	}
+
+	stacks, err := g.ListStacks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchStack(stacks, query)
+}
+
+// StackCredentials resolves a stack and returns a cached or newly minted token
+func (g *grafanaCloudImpl) StackCredentials(ctx context.Context, query string) (*StackCredentials, error) {
+	stack, err := g.ResolveStack(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	cached, ok := g.tokens[stack.Slug]
+	g.mu.Unlock()
+	if ok && g.now().Add(tokenRefreshMargin).Before(cached.ExpiresAt) {
+		return cached, nil
+	}
+
+	accountID, err := g.serviceAccountID(ctx, stack.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := g.config.TokenTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	var token struct {
+		Key string `json:"key"`
+	}
+	path := fmt.Sprintf("/api/instances/%s/api/serviceaccounts/%d/tokens", neturl.PathEscape(stack.Slug), accountID)
+	body := map[string]any{
+		"name":          fmt.Sprintf("%s-%d", serviceAccountName, g.now().Unix()),
+		"secondsToLive": int(ttl.Seconds()),
+	}
+	if err := g.do(ctx, "POST", path, body, &token); err != nil {
+		return nil, fmt.Errorf("failed to create token for stack %s: %w", stack.Slug, err)
+	}
+
+	credentials := &StackCredentials{Stack: *stack, Token: token.Key, ExpiresAt: g.now().Add(ttl)}
+
+	g.mu.Lock()
+	g.tokens[stack.Slug] = credentials
+	g.mu.Unlock()
+
+	g.logger.Info("created grafana cloud stack token",
+		zap.String("stack", stack.Slug),
+		zap.Time("expires_at", credentials.ExpiresAt))
+
+	return credentials, nil
+}
+
+// serviceAccountID finds or creates the agent's service account on a stack
+func (g *grafanaCloudImpl) serviceAccountID(ctx context.Context, stackSlug string) (int, error) {
+	var search struct {
+		ServiceAccounts []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"serviceAccounts"`
+	}
+	path := fmt.Sprintf("/api/instances/%s/api/serviceaccounts/search?query=%s", neturl.PathEscape(stackSlug), neturl.QueryEscape(serviceAccountName))
+	if err := g.do(ctx, "GET", path, nil, &search); err != nil {
+		return 0, fmt.Errorf("failed to look up service account on stack %s: %w", stackSlug, err)
+	}
+	for _, account := range search.ServiceAccounts {
+		if account.Name == serviceAccountName {
+			return account.ID, nil
+		}
+	}
+
+	var account struct {
+		ID int `json:"id"`
+	}
+	path = fmt.Sprintf("/api/instances/%s/api/serviceaccounts", neturl.PathEscape(stackSlug))
+	body := map[string]any{"name": serviceAccountName, "role": "Editor"}
+	if err := g.do(ctx, "POST", path, body, &account); err != nil {
+		return 0, fmt.Errorf("failed to create service account on stack %s: %w", stackSlug, err)
+	}
+
+	g.logger.Info("created grafana cloud stack service account",
+		zap.String("stack", stackSlug),
+		zap.Int("id", account.ID))
+
+	return account.ID, nil
+}
+
+// do sends an authenticated request to the Grafana Cloud API and decodes the JSON response
+func (g *grafanaCloudImpl) do(ctx context.Context, method, path string, body any, out any) error {
+	var reader *bytes.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	url := strings.TrimRight(g.config.APIURL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", g.config.Token))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("grafana cloud returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// matchStack picks the stack a user refers to
+func matchStack(stacks []Stack, query string) (*Stack, error) {
+	for i := range stacks {
+		if stacks[i].Slug == query {
+			return &stacks[i], nil
+		}
+	}
+	for i := range stacks {
+		if strings.EqualFold(stacks[i].Name, query) || strings.EqualFold(stacks[i].Slug, query) {
+			return &stacks[i], nil
+		}
+	}
+
+	lower := strings.ToLower(query)
+	var matches []*Stack
+	for i := range stacks {
+		if strings.Contains(strings.ToLower(stacks[i].Slug), lower) || strings.Contains(strings.ToLower(stacks[i].Name), lower) {
+			matches = append(matches, &stacks[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: no stack matches %q", ErrStackNotFound, query)
+	case 1:
+		return matches[0], nil
+	}
+
+	slugs := make([]string, len(matches))
+	for i, stack := range matches {
+		slugs[i] = stack.Slug
+	}
+	return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousStack, query, strings.Join(slugs, ", "))
+}
@@ -0,0 +1,144 @@
+package grafanacloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func newTestService(t *testing.T, handler http.HandlerFunc) *grafanaCloudImpl {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	cfg.Grafana.Cloud = config.GrafanaCloudConfig{APIURL: server.URL, Org: "acme", Token: "org-token", TokenTTL: time.Hour}
+
+	svc, err := NewGrafanaCloudService(zap.NewNop(), cfg, nil)
+	require.NoError(t, err)
+	return svc.(*grafanaCloudImpl)
+}
+
+var testStacks = []Stack{
+	{ID: 1, Slug: "acmeprod", Name: "acmeprod.grafana.net", URL: "https://acmeprod.grafana.net", Status: "active"},
+	{ID: 2, Slug: "acmestaging", Name: "acmestaging.grafana.net", URL: "https://acmestaging.grafana.net", Status: "active"},
+	{ID: 3, Slug: "acmedev", Name: "Development", URL: "https://acmedev.grafana.net", Status: "active"},
+}
+
+func TestListStacks(t *testing.T) {
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/orgs/acme/instances", r.URL.Path)
+		require.Equal(t, "Bearer org-token", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": testStacks})
+	})
+
+	stacks, err := svc.ListStacks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stacks, 3)
+	require.Equal(t, "https://acmeprod.grafana.net", stacks[0].URL)
+}
+
+func TestListStacksNotConfigured(t *testing.T) {
+	svc, err := NewGrafanaCloudService(zap.NewNop(), &config.Config{}, nil)
+	require.NoError(t, err)
+
+	_, err = svc.ListStacks(context.Background())
+	require.ErrorIs(t, err, ErrNotConfigured)
+}
+
+func TestMatchStack(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		err      error
+	}{
+		{name: "exact slug", query: "acmeprod", expected: "acmeprod"},
+		{name: "name is case-insensitive", query: "development", expected: "acmedev"},
+		{name: "unique substring", query: "prod", expected: "acmeprod"},
+		{name: "ambiguous substring", query: "acme", err: ErrAmbiguousStack},
+		{name: "unknown", query: "qa", err: ErrStackNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack, err := matchStack(testStacks, tt.query)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, stack.Slug)
+		})
+	}
+}
+
+func TestStackCredentials(t *testing.T) {
+	var created, tokens int
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/orgs/acme/instances":
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": testStacks})
+		case r.URL.Path == "/api/instances/acmeprod/api/serviceaccounts/search":
+			require.Equal(t, "grafana-agent", r.URL.Query().Get("query"))
+			accounts := []any{}
+			if created > 0 {
+				accounts = append(accounts, map[string]any{"id": 42, "name": "grafana-agent"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"serviceAccounts": accounts})
+		case r.URL.Path == "/api/instances/acmeprod/api/serviceaccounts" && r.Method == "POST":
+			created++
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 42})
+		case r.URL.Path == "/api/instances/acmeprod/api/serviceaccounts/42/tokens" && r.Method == "POST":
+			tokens++
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.EqualValues(t, 3600, body["secondsToLive"])
+			_ = json.NewEncoder(w).Encode(map[string]any{"key": "glsa_token"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	credentials, err := svc.StackCredentials(context.Background(), "prod")
+	require.NoError(t, err)
+	require.Equal(t, "https://acmeprod.grafana.net", credentials.Stack.URL)
+	require.Equal(t, "glsa_token", credentials.Token)
+	require.Equal(t, now.Add(time.Hour), credentials.ExpiresAt)
+
+	_, err = svc.StackCredentials(context.Background(), "acmeprod")
+	require.NoError(t, err)
+	require.Equal(t, 1, tokens, "cached token should be reused")
+
+	now = now.Add(56 * time.Minute)
+	_, err = svc.StackCredentials(context.Background(), "acmeprod")
+	require.NoError(t, err)
+	require.Equal(t, 2, tokens, "token close to expiry should be replaced")
+	require.Equal(t, 1, created, "existing service account should be reused")
+}
+
+func TestStackCredentialsUpstreamError(t *testing.T) {
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := svc.StackCredentials(context.Background(), "prod")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status 401")
+	require.False(t, errors.Is(err, ErrStackNotFound))
+}
@@ -0,0 +1,93 @@
+package grafanacloud
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+)
+
+// usageWarningRatio is the share of an included quota from which usage is
+// reported as close to the plan limit
+const usageWarningRatio = 0.9
+
+// Quota is the current usage of a billed dimension and what the plan
+// includes; Included is 0 when the plan sets no limit
+type Quota struct {
+	Current  float64 `json:"current"`
+	Included float64 `json:"included,omitempty"`
+}
+
+// Ratio is the used share of the included quota, 0 without a limit
+func (q Quota) Ratio() float64 {
+	if q.Included <= 0 {
+		return 0
+	}
+	return q.Current / q.Included
+}
+
+// Usage is the current usage of the Grafana Cloud org against its plan
+type Usage struct {
+	Org string `json:"org"`
+	// ActiveSeries are the metrics series active across the org's stacks
+	ActiveSeries Quota `json:"active_series"`
+	// LogsGB are the gigabytes of logs ingested this billing month
+	LogsGB Quota `json:"logs_gb"`
+	// Users are the users active in the org's Grafana instances this month
+	Users Quota `json:"users"`
+}
+
+// orgUsage holds the usage fields of a /api/orgs/<org> response
+type orgUsage struct {
+	Slug                 string  `json:"slug"`
+	HMCurrentUsage       float64 `json:"hmCurrentUsage"`
+	HMIncludedSeries     float64 `json:"hmIncludedSeries"`
+	HLCurrentUsage       float64 `json:"hlCurrentUsage"`
+	HLIncludedUsage      float64 `json:"hlIncludedUsage"`
+	HGCurrentActiveUsers float64 `json:"hgCurrentActiveUsers"`
+	HGIncludedUsers      float64 `json:"hgIncludedUsers"`
+}
+
+// Usage fetches the current usage of the configured org
+func (g *grafanaCloudImpl) Usage(ctx context.Context) (*Usage, error) {
+	if g.config.Org == "" || g.config.Token == "" {
+		return nil, ErrNotConfigured
+	}
+
+	var org orgUsage
+	path := fmt.Sprintf("/api/orgs/%s", neturl.PathEscape(g.config.Org))
+	if err := g.do(ctx, "GET", path, nil, &org); err != nil {
+		return nil, fmt.Errorf("failed to get grafana cloud usage: %w", err)
+	}
+
+	return &Usage{
+		Org:          org.Slug,
+		ActiveSeries: Quota{Current: org.HMCurrentUsage, Included: org.HMIncludedSeries},
+		LogsGB:       Quota{Current: org.HLCurrentUsage, Included: org.HLIncludedUsage},
+		Users:        Quota{Current: org.HGCurrentActiveUsers, Included: org.HGIncludedUsers},
+	}, nil
+}
+
+// Warnings reports the dimensions at or above 90% of their included quota
+// once additionalSeries more active series are added, such as the output
+// series of a planned recording rule set
+func (u *Usage) Warnings(additionalSeries int) []string {
+	var warnings []string
+	series := u.ActiveSeries
+	series.Current += float64(additionalSeries)
+	if additionalSeries > 0 && series.Ratio() >= usageWarningRatio {
+		warnings = append(warnings, fmt.Sprintf("adding %d active series brings the org to %.0f of the %.0f series its plan includes (%.0f%%)",
+			additionalSeries, series.Current, series.Included, series.Ratio()*100))
+	} else if series.Ratio() >= usageWarningRatio {
+		warnings = append(warnings, fmt.Sprintf("the org has %.0f active series, %.0f%% of the %.0f its plan includes",
+			series.Current, series.Ratio()*100, series.Included))
+	}
+	if u.LogsGB.Ratio() >= usageWarningRatio {
+		warnings = append(warnings, fmt.Sprintf("the org ingested %.1f GB of logs this month, %.0f%% of the %.0f GB its plan includes",
+			u.LogsGB.Current, u.LogsGB.Ratio()*100, u.LogsGB.Included))
+	}
+	if u.Users.Ratio() >= usageWarningRatio {
+		warnings = append(warnings, fmt.Sprintf("the org has %.0f active users, %.0f%% of the %.0f its plan includes",
+			u.Users.Current, u.Users.Ratio()*100, u.Users.Included))
+	}
+	return warnings
+}
@@ -0,0 +1,54 @@
+package grafanacloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	svc := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/orgs/acme", r.URL.Path)
+		require.Equal(t, "Bearer org-token", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"slug":                 "acme",
+			"hmCurrentUsage":       8500,
+			"hmIncludedSeries":     10000,
+			"hlCurrentUsage":       12.5,
+			"hlIncludedUsage":      50,
+			"hgCurrentActiveUsers": 3,
+			"hgIncludedUsers":      3,
+		})
+	})
+
+	usage, err := svc.Usage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "acme", usage.Org)
+	require.Equal(t, Quota{Current: 8500, Included: 10000}, usage.ActiveSeries)
+	require.Equal(t, 12.5, usage.LogsGB.Current)
+	require.Equal(t, 1.0, usage.Users.Ratio())
+}
+
+func TestUsageWarnings(t *testing.T) {
+	usage := &Usage{
+		ActiveSeries: Quota{Current: 8500, Included: 10000},
+		LogsGB:       Quota{Current: 12.5, Included: 50},
+		Users:        Quota{Current: 2},
+	}
+
+	require.Empty(t, usage.Warnings(0))
+
+	warnings := usage.Warnings(1200)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "adding 1200 active series brings the org to 9700 of the 10000")
+
+	usage.ActiveSeries.Current = 9500
+	usage.LogsGB.Current = 49
+	warnings = usage.Warnings(0)
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], "95% of the 10000")
+	require.Contains(t, warnings[1], "49.0 GB of logs")
+}
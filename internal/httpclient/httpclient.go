@@ -0,0 +1,42 @@
+// Package httpclient builds the HTTP transport shared by the Grafana,
+// Grafana Cloud and Prometheus services, so their connections are pooled
+// and kept alive across tool calls instead of per client
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// NewTransport returns a transport with keep-alives and the per-host
+// connection limits of cfg; zero limits leave Go's defaults in place
+func NewTransport(cfg *config.HTTPConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg == nil {
+		return transport
+	}
+
+	keepAlive := cfg.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = 30 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: keepAlive}
+	transport.DialContext = dialer.DialContext
+
+	if cfg.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	return transport
+}
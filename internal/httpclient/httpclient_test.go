@@ -0,0 +1,28 @@
+package httpclient
+
+import (
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(&config.HTTPConfig{
+		MaxConnsPerHost:     8,
+		MaxIdleConnsPerHost: 4,
+		MaxIdleConns:        50,
+		IdleConnTimeout:     time.Minute,
+	})
+	require.Equal(t, 8, transport.MaxConnsPerHost)
+	require.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 50, transport.MaxIdleConns)
+	require.Equal(t, time.Minute, transport.IdleConnTimeout)
+	require.NotNil(t, transport.DialContext)
+
+	defaults := NewTransport(nil)
+	require.Zero(t, defaults.MaxConnsPerHost)
+	require.Equal(t, 100, defaults.MaxIdleConns)
+}
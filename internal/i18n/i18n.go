@@ -0,0 +1,125 @@
+// Package i18n localizes the text the agent renders itself, such as
+// synthesized panel descriptions and dashboard summaries. Messages are keyed
+// by their English format string; languages without a catalog render
+// English, and free-form text is left to the LLM.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLanguage is the language of the message keys
+const DefaultLanguage = "en"
+
+// catalogs holds the translations of each supported language
+var catalogs = map[string]map[string]string{
+	"de": german,
+	"es": spanish,
+	"fr": french,
+}
+
+// languageNames name the languages the LLM can be asked to write in
+var languageNames = map[string]string{
+	"da": "Danish",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fi": "Finnish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"no": "Norwegian",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+// nounCapitalizing languages keep the first letter of a phrase capitalized
+// when it is embedded in a sentence
+var nounCapitalizing = map[string]bool{"de": true}
+
+// Base reduces a language tag such as "de-AT" or "fr_FR" to its base
+// language ("de", "fr"); empty means English
+func Base(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" {
+		return DefaultLanguage
+	}
+	return tag
+}
+
+// Name returns the English name of a language tag, or the tag itself when
+// it is not known
+func Name(tag string) string {
+	if name, ok := languageNames[Base(tag)]; ok {
+		return name
+	}
+	return strings.TrimSpace(tag)
+}
+
+// Localized reports whether the agent's own text is translated into the
+// language; other languages render English templates
+func Localized(tag string) bool {
+	base := Base(tag)
+	_, ok := catalogs[base]
+	return ok || base == DefaultLanguage
+}
+
+// Printer renders messages in one language
+type Printer struct {
+	language string
+	messages map[string]string
+}
+
+// NewPrinter returns a printer for a language tag, falling back to English
+// for languages without a catalog
+func NewPrinter(tag string) Printer {
+	base := Base(tag)
+	messages, ok := catalogs[base]
+	if !ok {
+		base = DefaultLanguage
+	}
+	return Printer{language: base, messages: messages}
+}
+
+// Language returns the base language the printer renders
+func (p Printer) Language() string {
+	if p.language == "" {
+		return DefaultLanguage
+	}
+	return p.language
+}
+
+// T translates a message
+func (p Printer) T(message string) string {
+	if translated, ok := p.messages[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf translates a format string and formats it
+func (p Printer) Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(p.T(format), args...)
+}
+
+// Embed prepares a phrase to be embedded in a sentence, lowercasing its
+// first letter unless the language capitalizes nouns
+func (p Printer) Embed(phrase string) string {
+	if phrase == "" || nounCapitalizing[p.language] {
+		return phrase
+	}
+	r, size := utf8.DecodeRuneInString(phrase)
+	return string(unicode.ToLower(r)) + phrase[size:]
+}
@@ -0,0 +1,42 @@
+package i18n
+
+import (
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func TestBaseAndName(t *testing.T) {
+	require.Equal(t, "de", Base("de-AT"))
+	require.Equal(t, "fr", Base(" fr_FR "))
+	require.Equal(t, "en", Base(""))
+	require.Equal(t, "German", Name("de-CH"))
+	require.Equal(t, "Japanese", Name("ja"))
+	require.Equal(t, "tlh", Name("tlh"))
+	require.True(t, Localized("es-MX"))
+	require.True(t, Localized("en-GB"))
+	require.False(t, Localized("ja"))
+}
+
+func TestPrinter(t *testing.T) {
+	german := NewPrinter("de")
+	require.Equal(t, "de", german.Language())
+	require.Equal(t, "Einheit: Sekunden", german.Sprintf("Unit: %s", german.T("seconds")))
+	require.Equal(t, "Rate pro Sekunde", german.Embed("Rate pro Sekunde"))
+	require.Equal(t, "unknown message", german.T("unknown message"))
+
+	fallback := NewPrinter("ja")
+	require.Equal(t, "en", fallback.Language())
+	require.Equal(t, "Unit: seconds", fallback.Sprintf("Unit: %s", fallback.T("seconds")))
+	require.Equal(t, "per-second rate", fallback.Embed("Per-second rate"))
+	require.Equal(t, "en", Printer{}.Language())
+}
+
+func TestCatalogsTranslateEveryMessage(t *testing.T) {
+	for language, messages := range catalogs {
+		require.Len(t, messages, len(german), "catalog %s", language)
+		for key := range german {
+			require.Contains(t, messages, key, "catalog %s", language)
+		}
+	}
+}
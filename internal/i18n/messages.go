@@ -0,0 +1,259 @@
+package i18n
+
+// german translates the agent's messages into German
+var german = map[string]string{
+	// Panel descriptions
+	"Per-second rate of %s":                         "Rate pro Sekunde von %s",
+	"Instant per-second rate of %s":                 "Momentane Rate pro Sekunde von %s",
+	"Increase of %s":                                "Zunahme von %s",
+	"Change of %s":                                  "Änderung von %s",
+	"Change between the last two samples of %s":     "Änderung zwischen den letzten zwei Messwerten von %s",
+	"Per-second derivative of %s":                   "Ableitung pro Sekunde von %s",
+	"Average of %s":                                 "Durchschnitt von %s",
+	"Maximum of %s":                                 "Maximum von %s",
+	"Minimum of %s":                                 "Minimum von %s",
+	"Sum of %s":                                     "Summe von %s",
+	"Number of samples of %s":                       "Anzahl der Messwerte von %s",
+	"Last value of %s":                              "Letzter Wert von %s",
+	"Number of value changes of %s":                 "Anzahl der Wertänderungen von %s",
+	"Number of counter resets of %s":                "Anzahl der Zähler-Resets von %s",
+	" and ":                                         " und ",
+	"Value of %s":                                   "Wert von %s",
+	"%s of %s, computed from its histogram buckets": "%s von %s, berechnet aus den Histogramm-Buckets",
+	"Ratio of the %s to the %s":                     "Verhältnis der %s zur %s",
+	" over %s":                                      " über %s",
+	"the dashboard rate interval":                   "das Rate-Intervall des Dashboards",
+	"the panel interval":                            "das Panel-Intervall",
+	"the dashboard time range":                      "den Zeitraum des Dashboards",
+	", keeping the top %s series":                   ", nur die %s höchsten Serien",
+	", keeping the bottom %s series":                ", nur die %s niedrigsten Serien",
+	"summed":                                        "summiert",
+	"averaged":                                      "gemittelt",
+	"minimum taken":                                 "Minimum gebildet",
+	"maximum taken":                                 "Maximum gebildet",
+	"series counted":                                "Serien gezählt",
+	"standard deviation taken":                      "Standardabweichung gebildet",
+	", %s by %s":                                    ", %s nach %s",
+	", %s across all labels except %s":              ", %s über alle Labels außer %s",
+	", %s across all series":                        ", %s über alle Serien",
+	", shown only where it is %s %s":                ", nur angezeigt, wo der Wert %s %s ist",
+	"above":                                         "über",
+	"below":                                         "unter",
+	"at least":                                      "mindestens",
+	"at most":                                       "höchstens",
+	"equal to":                                      "gleich",
+	"not equal to":                                  "ungleich",
+	", as it was %s earlier":                        ", wie er %s zuvor war",
+	"Unit: %s":                                      "Einheit: %s",
+	"seconds":                                       "Sekunden",
+	"milliseconds":                                  "Millisekunden",
+	"microseconds":                                  "Mikrosekunden",
+	"nanoseconds":                                   "Nanosekunden",
+	"bytes":                                         "Bytes",
+	"bits":                                          "Bits",
+	"bytes per second":                              "Bytes pro Sekunde",
+	"bits per second":                               "Bits pro Sekunde",
+	"percent (0-100)":                               "Prozent (0-100)",
+	"percent (0.0-1.0)":                             "Prozent (0,0-1,0)",
+	"requests per second":                           "Anfragen pro Sekunde",
+	"operations per second":                         "Operationen pro Sekunde",
+	"degrees Celsius":                               "Grad Celsius",
+	"ratio (0-1)":                                   "Verhältnis (0-1)",
+	"percent":                                       "Prozent",
+	"meters":                                        "Meter",
+	"joules":                                        "Joule",
+	"volts":                                         "Volt",
+	"per second":                                    "pro Sekunde",
+	"%s per second":                                 "%s pro Sekunde",
+
+	// Dashboard summaries
+	"Dashboard %q: %d panels, %d variables": "Dashboard %q: %d Panels, %d Variablen",
+	", time %v to %v":                       ", Zeitraum %v bis %v",
+	", refresh %s":                          ", Aktualisierung %s",
+	"Tags: %s":                              "Tags: %s",
+	"Layout: %s":                            "Layout: %s",
+	"Panels:":                               "Panels:",
+	"Variables:":                            "Variablen:",
+	"(no query)":                            "(keine Abfrage)",
+	"Panel %d":                              "Panel %d",
+	"Untitled":                              "Ohne Titel",
+	"automatic":                             "automatisch",
+	"%d rows (%s panels per row), %d grid units tall": "%d Zeilen (%s Panels pro Zeile), %d Rastereinheiten hoch",
+
+	// Dashboard variants
+	"%s (trends)":         "%s (Trends)",
+	"Trends over 30 days": "Trends über 30 Tage",
+	"Real-time":           "Echtzeit",
+}
+
+// french translates the agent's messages into French
+var french = map[string]string{
+	// Panel descriptions
+	"Per-second rate of %s":                         "Taux par seconde de %s",
+	"Instant per-second rate of %s":                 "Taux instantané par seconde de %s",
+	"Increase of %s":                                "Augmentation de %s",
+	"Change of %s":                                  "Variation de %s",
+	"Change between the last two samples of %s":     "Variation entre les deux derniers échantillons de %s",
+	"Per-second derivative of %s":                   "Dérivée par seconde de %s",
+	"Average of %s":                                 "Moyenne de %s",
+	"Maximum of %s":                                 "Maximum de %s",
+	"Minimum of %s":                                 "Minimum de %s",
+	"Sum of %s":                                     "Somme de %s",
+	"Number of samples of %s":                       "Nombre d'échantillons de %s",
+	"Last value of %s":                              "Dernière valeur de %s",
+	"Number of value changes of %s":                 "Nombre de changements de valeur de %s",
+	"Number of counter resets of %s":                "Nombre de remises à zéro du compteur %s",
+	" and ":                                         " et ",
+	"Value of %s":                                   "Valeur de %s",
+	"%s of %s, computed from its histogram buckets": "%s de %s, calculé à partir des buckets de l'histogramme",
+	"Ratio of the %s to the %s":                     "Rapport entre le %s et le %s",
+	" over %s":                                      " sur %s",
+	"the dashboard rate interval":                   "l'intervalle de taux du tableau de bord",
+	"the panel interval":                            "l'intervalle du panneau",
+	"the dashboard time range":                      "la période du tableau de bord",
+	", keeping the top %s series":                   ", en gardant les %s séries les plus hautes",
+	", keeping the bottom %s series":                ", en gardant les %s séries les plus basses",
+	"summed":                                        "additionné",
+	"averaged":                                      "moyenné",
+	"minimum taken":                                 "minimum retenu",
+	"maximum taken":                                 "maximum retenu",
+	"series counted":                                "séries comptées",
+	"standard deviation taken":                      "écart type calculé",
+	", %s by %s":                                    ", %s par %s",
+	", %s across all labels except %s":              ", %s sur tous les labels sauf %s",
+	", %s across all series":                        ", %s sur toutes les séries",
+	", shown only where it is %s %s":                ", affiché uniquement lorsqu'il est %s %s",
+	"above":                                         "supérieur à",
+	"below":                                         "inférieur à",
+	"at least":                                      "au moins",
+	"at most":                                       "au plus",
+	"equal to":                                      "égal à",
+	"not equal to":                                  "différent de",
+	", as it was %s earlier":                        ", tel qu'il était %s plus tôt",
+	"Unit: %s":                                      "Unité : %s",
+	"seconds":                                       "secondes",
+	"milliseconds":                                  "millisecondes",
+	"microseconds":                                  "microsecondes",
+	"nanoseconds":                                   "nanosecondes",
+	"bytes":                                         "octets",
+	"bits":                                          "bits",
+	"bytes per second":                              "octets par seconde",
+	"bits per second":                               "bits par seconde",
+	"percent (0-100)":                               "pourcentage (0-100)",
+	"percent (0.0-1.0)":                             "pourcentage (0,0-1,0)",
+	"requests per second":                           "requêtes par seconde",
+	"operations per second":                         "opérations par seconde",
+	"degrees Celsius":                               "degrés Celsius",
+	"ratio (0-1)":                                   "ratio (0-1)",
+	"percent":                                       "pourcentage",
+	"meters":                                        "mètres",
+	"joules":                                        "joules",
+	"volts":                                         "volts",
+	"per second":                                    "par seconde",
+	"%s per second":                                 "%s par seconde",
+
+	// Dashboard summaries
+	"Dashboard %q: %d panels, %d variables": "Tableau de bord %q : %d panneaux, %d variables",
+	", time %v to %v":                       ", période %v à %v",
+	", refresh %s":                          ", actualisation %s",
+	"Tags: %s":                              "Tags : %s",
+	"Layout: %s":                            "Disposition : %s",
+	"Panels:":                               "Panneaux :",
+	"Variables:":                            "Variables :",
+	"(no query)":                            "(aucune requête)",
+	"Panel %d":                              "Panneau %d",
+	"Untitled":                              "Sans titre",
+	"automatic":                             "automatique",
+	"%d rows (%s panels per row), %d grid units tall": "%d lignes (%s panneaux par ligne), %d unités de grille de haut",
+
+	// Dashboard variants
+	"%s (trends)":         "%s (tendances)",
+	"Trends over 30 days": "Tendances sur 30 jours",
+	"Real-time":           "Temps réel",
+}
+
+// spanish translates the agent's messages into Spanish
+var spanish = map[string]string{
+	// Panel descriptions
+	"Per-second rate of %s":                         "Tasa por segundo de %s",
+	"Instant per-second rate of %s":                 "Tasa instantánea por segundo de %s",
+	"Increase of %s":                                "Incremento de %s",
+	"Change of %s":                                  "Variación de %s",
+	"Change between the last two samples of %s":     "Variación entre las dos últimas muestras de %s",
+	"Per-second derivative of %s":                   "Derivada por segundo de %s",
+	"Average of %s":                                 "Promedio de %s",
+	"Maximum of %s":                                 "Máximo de %s",
+	"Minimum of %s":                                 "Mínimo de %s",
+	"Sum of %s":                                     "Suma de %s",
+	"Number of samples of %s":                       "Número de muestras de %s",
+	"Last value of %s":                              "Último valor de %s",
+	"Number of value changes of %s":                 "Número de cambios de valor de %s",
+	"Number of counter resets of %s":                "Número de reinicios del contador %s",
+	" and ":                                         " y ",
+	"Value of %s":                                   "Valor de %s",
+	"%s of %s, computed from its histogram buckets": "%s de %s, calculado a partir de los buckets del histograma",
+	"Ratio of the %s to the %s":                     "Relación entre la %s y la %s",
+	" over %s":                                      " en %s",
+	"the dashboard rate interval":                   "el intervalo de tasa del dashboard",
+	"the panel interval":                            "el intervalo del panel",
+	"the dashboard time range":                      "el rango de tiempo del dashboard",
+	", keeping the top %s series":                   ", conservando las %s series más altas",
+	", keeping the bottom %s series":                ", conservando las %s series más bajas",
+	"summed":                                        "sumado",
+	"averaged":                                      "promediado",
+	"minimum taken":                                 "mínimo tomado",
+	"maximum taken":                                 "máximo tomado",
+	"series counted":                                "series contadas",
+	"standard deviation taken":                      "desviación estándar calculada",
+	", %s by %s":                                    ", %s por %s",
+	", %s across all labels except %s":              ", %s en todas las etiquetas excepto %s",
+	", %s across all series":                        ", %s en todas las series",
+	", shown only where it is %s %s":                ", mostrado solo donde es %s %s",
+	"above":                                         "mayor que",
+	"below":                                         "menor que",
+	"at least":                                      "al menos",
+	"at most":                                       "como máximo",
+	"equal to":                                      "igual a",
+	"not equal to":                                  "distinto de",
+	", as it was %s earlier":                        ", tal como era %s antes",
+	"Unit: %s":                                      "Unidad: %s",
+	"seconds":                                       "segundos",
+	"milliseconds":                                  "milisegundos",
+	"microseconds":                                  "microsegundos",
+	"nanoseconds":                                   "nanosegundos",
+	"bytes":                                         "bytes",
+	"bits":                                          "bits",
+	"bytes per second":                              "bytes por segundo",
+	"bits per second":                               "bits por segundo",
+	"percent (0-100)":                               "porcentaje (0-100)",
+	"percent (0.0-1.0)":                             "porcentaje (0,0-1,0)",
+	"requests per second":                           "peticiones por segundo",
+	"operations per second":                         "operaciones por segundo",
+	"degrees Celsius":                               "grados Celsius",
+	"ratio (0-1)":                                   "proporción (0-1)",
+	"percent":                                       "porcentaje",
+	"meters":                                        "metros",
+	"joules":                                        "julios",
+	"volts":                                         "voltios",
+	"per second":                                    "por segundo",
+	"%s per second":                                 "%s por segundo",
+
+	// Dashboard summaries
+	"Dashboard %q: %d panels, %d variables": "Dashboard %q: %d paneles, %d variables",
+	", time %v to %v":                       ", periodo %v a %v",
+	", refresh %s":                          ", actualización %s",
+	"Tags: %s":                              "Etiquetas: %s",
+	"Layout: %s":                            "Disposición: %s",
+	"Panels:":                               "Paneles:",
+	"Variables:":                            "Variables:",
+	"(no query)":                            "(sin consulta)",
+	"Panel %d":                              "Panel %d",
+	"Untitled":                              "Sin título",
+	"automatic":                             "automática",
+	"%d rows (%s panels per row), %d grid units tall": "%d filas (%s paneles por fila), %d unidades de cuadrícula de alto",
+
+	// Dashboard variants
+	"%s (trends)":         "%s (tendencias)",
+	"Trends over 30 days": "Tendencias de 30 días",
+	"Real-time":           "Tiempo real",
+}
@@ -0,0 +1,7 @@
+package inventory
+
+// Register the database/sql drivers of the backends
+import (
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
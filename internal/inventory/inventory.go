@@ -0,0 +1,103 @@
+// Package inventory records the dashboards the agent manages in a database,
+// so drift detection, cleanup and listing them work across restarts.
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+)
+
+//go:generate go tool counterfeiter -generate
+
+// Supported INVENTORY_BACKEND values
+const (
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// Dashboard is the record of a dashboard the agent deployed
+type Dashboard struct {
+	UID        string `json:"uid"`
+	Title      string `json:"title"`
+	GrafanaURL string `json:"grafana_url"`
+	// OrgID is the Grafana organization the dashboard was deployed to; 0
+	// when it could not be determined
+	OrgID int `json:"org_id,omitempty"`
+	// Stack is the Grafana Cloud stack the dashboard was deployed to, if any
+	Stack     string `json:"stack,omitempty"`
+	FolderUID string `json:"folder_uid,omitempty"`
+	// Template is the dashboard template or builder the dashboard came
+	// from; empty for dashboards assembled panel by panel
+	Template string `json:"template,omitempty"`
+	// Prompt summarizes the user request the dashboard was deployed for
+	Prompt string `json:"prompt,omitempty"`
+	// RequestID is the A2A task the dashboard was deployed in
+	RequestID string `json:"request_id,omitempty"`
+	// Hash is the content hash of the model Grafana stored on deploy (see
+	// Hash); a different hash of the dashboard in Grafana means it was
+	// edited since
+	Hash      string    `json:"hash"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Filter narrows a listing; empty fields match every dashboard
+type Filter struct {
+	GrafanaURL string
+	OrgID      int
+	Template   string
+}
+
+// Store persists the inventory of managed dashboards
+//
+//counterfeiter:generate . Store
+type Store interface {
+	// Record inserts or replaces the record of a dashboard, identified by
+	// its Grafana URL, organization and UID; the creation time of a replaced
+	// record is kept
+	Record(ctx context.Context, dashboard Dashboard) error
+	// List returns the recorded dashboards matching the filter, by Grafana
+	// URL, organization and title
+	List(ctx context.Context, filter Filter) ([]Dashboard, error)
+	// Forget removes the record of a dashboard
+	Forget(ctx context.Context, grafanaURL string, orgID int, uid string) error
+	Close() error
+}
+
+// Open opens the store configured with INVENTORY_BACKEND and INVENTORY_DSN.
+// Without a backend the inventory is disabled and Open returns nil.
+func Open(ctx context.Context, cfg *config.InventoryConfig) (Store, error) {
+	if cfg == nil || cfg.Backend == "" {
+		return nil, nil
+	}
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("INVENTORY_DSN is required with INVENTORY_BACKEND=%s", cfg.Backend)
+	}
+	switch cfg.Backend {
+	case BackendSQLite:
+		return openSQL(ctx, sqlite, cfg.DSN)
+	case BackendPostgres:
+		return openSQL(ctx, postgres, cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unsupported INVENTORY_BACKEND %q (expected %s or %s)", cfg.Backend, BackendSQLite, BackendPostgres)
+	}
+}
+
+// Hash returns the content hash of a dashboard model, taken over its
+// canonical form (see diff.Canonicalize) so that the id and version Grafana
+// bumps on every save do not count as changes.
+func Hash(dashboard map[string]any) (string, error) {
+	data, err := diff.CanonicalJSON(dashboard)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,99 @@
+package inventory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestHash(t *testing.T) {
+	deployed := map[string]any{"id": 12, "uid": "abc", "version": 3, "title": "Checkout", "panels": []any{map[string]any{"id": 1, "type": "stat", "pluginVersion": "11.0.0"}}}
+	saved := map[string]any{"id": 12, "uid": "abc", "version": 4, "title": "Checkout", "panels": []any{map[string]any{"id": 1, "type": "stat", "pluginVersion": "11.2.0"}}}
+	edited := map[string]any{"id": 12, "uid": "abc", "version": 5, "title": "Checkout", "panels": []any{map[string]any{"id": 1, "type": "timeseries"}}}
+
+	want, err := Hash(deployed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := Hash(saved); got != want {
+		t.Error("Expected the fields Grafana bumps on save to be ignored")
+	}
+	if got, _ := Hash(edited); got == want {
+		t.Error("Expected an edited dashboard to hash differently")
+	}
+	if _, ok := saved["version"]; !ok {
+		t.Error("Expected the dashboard to be left unchanged")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	query := "SELECT * FROM t WHERE (? = '' OR a = ?) AND b = ?"
+	if got := sqlite.rebind(query); got != query {
+		t.Errorf("Expected SQLite placeholders unchanged, got %s", got)
+	}
+	if got, want := postgres.rebind(query), "SELECT * FROM t WHERE ($1 = '' OR a = $2) AND b = $3"; got != want {
+		t.Errorf("rebind = %s, want %s", got, want)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(context.Background(), &config.InventoryConfig{})
+	if store != nil || err != nil {
+		t.Errorf("Expected a disabled inventory without a backend, got %v, %v", store, err)
+	}
+	if _, err := Open(context.Background(), &config.InventoryConfig{Backend: BackendSQLite}); err == nil {
+		t.Error("Expected an error without a DSN")
+	}
+	if _, err := Open(context.Background(), &config.InventoryConfig{Backend: "mysql", DSN: "x"}); err == nil {
+		t.Error("Expected an error for an unsupported backend")
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(ctx, &config.InventoryConfig{Backend: BackendSQLite, DSN: filepath.Join(t.TempDir(), "inventory.db")})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dashboard := Dashboard{UID: "abc", Title: "Checkout", GrafanaURL: "http://grafana", OrgID: 1, Template: "service_overview", Hash: "h1", Version: 1, CreatedAt: created, UpdatedAt: created}
+	if err := store.Record(ctx, dashboard); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dashboard.Hash, dashboard.Version, dashboard.CreatedAt, dashboard.UpdatedAt = "h2", 2, time.Time{}, created.Add(time.Hour)
+	if err := store.Record(ctx, dashboard); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dashboards, err := store.List(ctx, Filter{Template: "service_overview"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dashboards) != 1 || dashboards[0].Hash != "h2" || !dashboards[0].CreatedAt.Equal(created) {
+		t.Errorf("Expected the replaced record with its creation time kept, got %+v", dashboards)
+	}
+
+	// The same UID in another organization is another dashboard
+	other := Dashboard{UID: "abc", Title: "Checkout", GrafanaURL: "http://grafana", OrgID: 2, Hash: "h3", Version: 1}
+	if err := store.Record(ctx, other); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dashboards, _ := store.List(ctx, Filter{}); len(dashboards) != 2 {
+		t.Errorf("Expected one record per organization, got %+v", dashboards)
+	}
+	if dashboards, _ := store.List(ctx, Filter{OrgID: 2}); len(dashboards) != 1 || dashboards[0].Hash != "h3" {
+		t.Errorf("Expected only the record of organization 2, got %+v", dashboards)
+	}
+
+	if err := store.Forget(ctx, "http://grafana", 1, "abc"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dashboards, _ := store.List(ctx, Filter{}); len(dashboards) != 1 || dashboards[0].OrgID != 2 {
+		t.Errorf("Expected only the record of organization 1 to be forgotten, got %+v", dashboards)
+	}
+}
@@ -0,0 +1,340 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package inventoryfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/inference-gateway/grafana-agent/internal/inventory"
+)
+
+type FakeStore struct {
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ForgetStub        func(context.Context, string, int, string) error
+	forgetMutex       sync.RWMutex
+	forgetArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 string
+	}
+	forgetReturns struct {
+		result1 error
+	}
+	forgetReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListStub        func(context.Context, inventory.Filter) ([]inventory.Dashboard, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+		arg2 inventory.Filter
+	}
+	listReturns struct {
+		result1 []inventory.Dashboard
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []inventory.Dashboard
+		result2 error
+	}
+	RecordStub        func(context.Context, inventory.Dashboard) error
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		arg1 context.Context
+		arg2 inventory.Dashboard
+	}
+	recordReturns struct {
+		result1 error
+	}
+	recordReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeStore) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeStore) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Forget(arg1 context.Context, arg2 string, arg3 int, arg4 string) error {
+	fake.forgetMutex.Lock()
+	ret, specificReturn := fake.forgetReturnsOnCall[len(fake.forgetArgsForCall)]
+	fake.forgetArgsForCall = append(fake.forgetArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ForgetStub
+	fakeReturns := fake.forgetReturns
+	fake.recordInvocation("Forget", []interface{}{arg1, arg2, arg3, arg4})
+	fake.forgetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) ForgetCallCount() int {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return len(fake.forgetArgsForCall)
+}
+
+func (fake *FakeStore) ForgetCalls(stub func(context.Context, string, int, string) error) {
+	fake.forgetMutex.Lock()
+	defer fake.forgetMutex.Unlock()
+	fake.ForgetStub = stub
+}
+
+func (fake *FakeStore) ForgetArgsForCall(i int) (context.Context, string, int, string) {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	argsForCall := fake.forgetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStore) ForgetReturns(result1 error) {
+	fake.forgetMutex.Lock()
+	defer fake.forgetMutex.Unlock()
+	fake.ForgetStub = nil
+	fake.forgetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) ForgetReturnsOnCall(i int, result1 error) {
+	fake.forgetMutex.Lock()
+	defer fake.forgetMutex.Unlock()
+	fake.ForgetStub = nil
+	if fake.forgetReturnsOnCall == nil {
+		fake.forgetReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.forgetReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) List(arg1 context.Context, arg2 inventory.Filter) ([]inventory.Dashboard, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 inventory.Filter
+	}{arg1, arg2})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStore) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeStore) ListCalls(stub func(context.Context, inventory.Filter) ([]inventory.Dashboard, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeStore) ListArgsForCall(i int) (context.Context, inventory.Filter) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) ListReturns(result1 []inventory.Dashboard, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []inventory.Dashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) ListReturnsOnCall(i int, result1 []inventory.Dashboard, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []inventory.Dashboard
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []inventory.Dashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Record(arg1 context.Context, arg2 inventory.Dashboard) error {
+	fake.recordMutex.Lock()
+	ret, specificReturn := fake.recordReturnsOnCall[len(fake.recordArgsForCall)]
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		arg1 context.Context
+		arg2 inventory.Dashboard
+	}{arg1, arg2})
+	stub := fake.RecordStub
+	fakeReturns := fake.recordReturns
+	fake.recordInvocation("Record", []interface{}{arg1, arg2})
+	fake.recordMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStore) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeStore) RecordCalls(stub func(context.Context, inventory.Dashboard) error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = stub
+}
+
+func (fake *FakeStore) RecordArgsForCall(i int) (context.Context, inventory.Dashboard) {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	argsForCall := fake.recordArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStore) RecordReturns(result1 error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = nil
+	fake.recordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) RecordReturnsOnCall(i int, result1 error) {
+	fake.recordMutex.Lock()
+	defer fake.recordMutex.Unlock()
+	fake.RecordStub = nil
+	if fake.recordReturnsOnCall == nil {
+		fake.recordReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ inventory.Store = new(FakeStore)
@@ -0,0 +1,166 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dialect is what differs between the SQL databases the inventory supports
+type dialect struct {
+	// driver is the database/sql driver name the backend's driver registers
+	driver string
+	// numbered is set when placeholders are $1, $2, ... instead of ?
+	numbered bool
+}
+
+var (
+	// sqlite is served by modernc.org/sqlite (pure Go, no cgo)
+	sqlite = dialect{driver: "sqlite"}
+	// postgres is served by the pgx standard library driver
+	postgres = dialect{driver: "pgx", numbered: true}
+)
+
+// schema creates the inventory table; both SQLite and Postgres accept it
+const schema = `CREATE TABLE IF NOT EXISTS managed_dashboards (
+	grafana_url TEXT NOT NULL,
+	org_id      INTEGER NOT NULL DEFAULT 0,
+	uid         TEXT NOT NULL,
+	title       TEXT NOT NULL,
+	stack       TEXT NOT NULL DEFAULT '',
+	folder_uid  TEXT NOT NULL DEFAULT '',
+	template    TEXT NOT NULL DEFAULT '',
+	prompt      TEXT NOT NULL DEFAULT '',
+	request_id  TEXT NOT NULL DEFAULT '',
+	hash        TEXT NOT NULL,
+	version     INTEGER NOT NULL,
+	created_at  BIGINT NOT NULL,
+	updated_at  BIGINT NOT NULL,
+	PRIMARY KEY (grafana_url, org_id, uid)
+)`
+
+const (
+	recordQuery = `INSERT INTO managed_dashboards
+	(grafana_url, org_id, uid, title, stack, folder_uid, template, prompt, request_id, hash, version, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (grafana_url, org_id, uid) DO UPDATE SET
+		title = excluded.title,
+		stack = excluded.stack,
+		folder_uid = excluded.folder_uid,
+		template = excluded.template,
+		prompt = excluded.prompt,
+		request_id = excluded.request_id,
+		hash = excluded.hash,
+		version = excluded.version,
+		updated_at = excluded.updated_at`
+
+	listQuery = `SELECT grafana_url, org_id, uid, title, stack, folder_uid, template, prompt, request_id, hash, version, created_at, updated_at
+	FROM managed_dashboards
+	WHERE (? = '' OR grafana_url = ?) AND (? = 0 OR org_id = ?) AND (? = '' OR template = ?)
+	ORDER BY grafana_url, org_id, title, uid`
+
+	forgetQuery = `DELETE FROM managed_dashboards WHERE grafana_url = ? AND org_id = ? AND uid = ?`
+)
+
+// sqlStore keeps the inventory in a SQL database
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// openSQL connects to the database and creates the inventory table
+func openSQL(ctx context.Context, d dialect, dsn string) (Store, error) {
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the %s inventory: %w", d.driver, err)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create the inventory table: %w", err)
+	}
+	return &sqlStore{db: db, dialect: d}, nil
+}
+
+// Record inserts or replaces the record of a dashboard
+func (s *sqlStore) Record(ctx context.Context, dashboard Dashboard) error {
+	now := time.Now()
+	if dashboard.UpdatedAt.IsZero() {
+		dashboard.UpdatedAt = now
+	}
+	if dashboard.CreatedAt.IsZero() {
+		dashboard.CreatedAt = dashboard.UpdatedAt
+	}
+	_, err := s.db.ExecContext(ctx, s.dialect.rebind(recordQuery),
+		dashboard.GrafanaURL, dashboard.OrgID, dashboard.UID, dashboard.Title, dashboard.Stack, dashboard.FolderUID,
+		dashboard.Template, dashboard.Prompt, dashboard.RequestID, dashboard.Hash, dashboard.Version,
+		dashboard.CreatedAt.UnixMilli(), dashboard.UpdatedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to record dashboard %s: %w", dashboard.UID, err)
+	}
+	return nil
+}
+
+// List returns the recorded dashboards matching the filter
+func (s *sqlStore) List(ctx context.Context, filter Filter) ([]Dashboard, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(listQuery),
+		filter.GrafanaURL, filter.GrafanaURL, filter.OrgID, filter.OrgID, filter.Template, filter.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed dashboards: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var dashboards []Dashboard
+	for rows.Next() {
+		var (
+			dashboard        Dashboard
+			created, updated int64
+		)
+		if err := rows.Scan(&dashboard.GrafanaURL, &dashboard.OrgID, &dashboard.UID, &dashboard.Title, &dashboard.Stack, &dashboard.FolderUID,
+			&dashboard.Template, &dashboard.Prompt, &dashboard.RequestID, &dashboard.Hash, &dashboard.Version,
+			&created, &updated); err != nil {
+			return nil, fmt.Errorf("failed to read managed dashboard: %w", err)
+		}
+		dashboard.CreatedAt = time.UnixMilli(created).UTC()
+		dashboard.UpdatedAt = time.UnixMilli(updated).UTC()
+		dashboards = append(dashboards, dashboard)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list managed dashboards: %w", err)
+	}
+	return dashboards, nil
+}
+
+// Forget removes the record of a dashboard
+func (s *sqlStore) Forget(ctx context.Context, grafanaURL string, orgID int, uid string) error {
+	if _, err := s.db.ExecContext(ctx, s.dialect.rebind(forgetQuery), grafanaURL, orgID, uid); err != nil {
+		return fmt.Errorf("failed to forget dashboard %s: %w", uid, err)
+	}
+	return nil
+}
+
+// Close closes the database connection
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// rebind rewrites the ? placeholders of a query into the dialect's
+// numbered placeholders, if it uses them
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
@@ -0,0 +1,255 @@
+// Package naming enforces the dashboard naming convention configured with
+// GRAFANA_NAMING_*: a title prefix, title case, a maximum length and
+// forbidden words. Generated titles are made to conform with Apply; titles
+// deployed as given are checked with Check.
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// Naming rules a title can break
+const (
+	RulePrefix        = "prefix"
+	RuleTitleCase     = "title_case"
+	RuleMaxLength     = "max_length"
+	RuleForbiddenWord = "forbidden_word"
+)
+
+// ErrNonConforming is wrapped by the LintError of a title that breaks the
+// naming convention
+var ErrNonConforming = errors.New("dashboard title does not follow the naming convention")
+
+// minorWords stay lowercase in title case unless they start the title
+var minorWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "by": true,
+	"for": true, "in": true, "of": true, "on": true, "or": true, "per": true,
+	"the": true, "to": true, "vs": true, "via": true, "with": true,
+}
+
+// separators are trimmed from the ends of a title once words are removed
+const separators = " -–—:|/,."
+
+// Rules is a dashboard naming convention; the zero value accepts any title
+type Rules struct {
+	// Prefix starts every title, followed by a space
+	Prefix string
+	// TitleCase capitalizes every word but minor ones such as "by" and
+	// "of"; words with capitals of their own, such as "gRPC", are kept
+	TitleCase bool
+	// MaxLength is the most characters a title may have, 0 for no limit
+	MaxLength int
+	// ForbiddenWords may not appear in titles, in any case
+	ForbiddenWords []string
+}
+
+// NewRules returns the naming convention of the configuration
+func NewRules(cfg *config.GrafanaNamingConfig) Rules {
+	if cfg == nil {
+		return Rules{}
+	}
+	rules := Rules{
+		Prefix:    strings.TrimSpace(cfg.Prefix),
+		TitleCase: cfg.TitleCase,
+		MaxLength: max(cfg.MaxLength, 0),
+	}
+	for _, word := range cfg.ForbiddenWords {
+		if word = strings.TrimSpace(word); word != "" {
+			rules.ForbiddenWords = append(rules.ForbiddenWords, word)
+		}
+	}
+	return rules
+}
+
+// IsZero reports whether the rules accept any title
+func (r Rules) IsZero() bool {
+	return r.Prefix == "" && !r.TitleCase && r.MaxLength == 0 && len(r.ForbiddenWords) == 0
+}
+
+// Violation is a naming rule a title breaks
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// LintError lists the rules a title breaks, with the conforming title Apply
+// suggests instead
+type LintError struct {
+	Title      string      `json:"title"`
+	Violations []Violation `json:"violations"`
+	Suggestion string      `json:"suggestion"`
+}
+
+// Error implements the error interface
+func (e *LintError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("dashboard title %q does not follow the naming convention: %s (suggested title %q)",
+		e.Title, strings.Join(messages, "; "), e.Suggestion)
+}
+
+// Unwrap returns ErrNonConforming
+func (e *LintError) Unwrap() error {
+	return ErrNonConforming
+}
+
+// Apply returns the title made to follow the rules: forbidden words are
+// removed, the words are title-cased, the title is shortened at a word
+// boundary, keeping a trailing qualifier such as "(trends)", and the prefix
+// is added if missing
+func (r Rules) Apply(title string) string {
+	if r.IsZero() {
+		return title
+	}
+	body := r.stripPrefix(strings.TrimSpace(title))
+
+	if len(r.ForbiddenWords) > 0 {
+		var kept []string
+		for _, word := range strings.Fields(body) {
+			if r.forbidden(word) == "" {
+				kept = append(kept, word)
+			}
+		}
+		body = strings.Trim(strings.Join(kept, " "), separators)
+	}
+	if r.TitleCase {
+		body = titleCase(body)
+	}
+	if r.MaxLength > 0 {
+		limit := r.MaxLength
+		if r.Prefix != "" {
+			limit -= utf8.RuneCountInString(r.Prefix) + 1
+		}
+		body = truncate(body, limit)
+	}
+	return r.join(body)
+}
+
+// Lint returns the rules the title breaks
+func (r Rules) Lint(title string) []Violation {
+	var violations []Violation
+	body := title
+	if r.Prefix != "" {
+		if !strings.HasPrefix(title, r.Prefix+" ") {
+			violations = append(violations, Violation{RulePrefix, fmt.Sprintf("it does not start with %q", r.Prefix)})
+		}
+		body = r.stripPrefix(title)
+	}
+	if r.TitleCase && body != titleCase(body) {
+		violations = append(violations, Violation{RuleTitleCase, "it is not in title case"})
+	}
+	if length := utf8.RuneCountInString(title); r.MaxLength > 0 && length > r.MaxLength {
+		violations = append(violations, Violation{RuleMaxLength, fmt.Sprintf("it has %d characters, more than %d", length, r.MaxLength)})
+	}
+	for _, word := range strings.Fields(body) {
+		if forbidden := r.forbidden(word); forbidden != "" {
+			violations = append(violations, Violation{RuleForbiddenWord, fmt.Sprintf("it contains the forbidden word %q", forbidden)})
+		}
+	}
+	return violations
+}
+
+// Check returns a LintError if the title breaks the rules
+func (r Rules) Check(title string) error {
+	violations := r.Lint(title)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &LintError{Title: title, Violations: violations, Suggestion: r.Apply(title)}
+}
+
+// stripPrefix removes the prefix, in any case, from the start of a title
+func (r Rules) stripPrefix(title string) string {
+	if r.Prefix == "" || len(title) < len(r.Prefix) || !strings.EqualFold(title[:len(r.Prefix)], r.Prefix) {
+		return title
+	}
+	return strings.TrimSpace(title[len(r.Prefix):])
+}
+
+// join puts the prefix in front of a title body
+func (r Rules) join(body string) string {
+	if r.Prefix == "" {
+		return body
+	}
+	if body == "" {
+		return r.Prefix
+	}
+	return r.Prefix + " " + body
+}
+
+// forbidden returns the forbidden word a title word is, ignoring case and
+// surrounding punctuation, or "" if it is allowed
+func (r Rules) forbidden(word string) string {
+	word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+	for _, forbidden := range r.ForbiddenWords {
+		if strings.EqualFold(word, forbidden) {
+			return forbidden
+		}
+	}
+	return ""
+}
+
+// titleCase capitalizes the first letter of every word but minor words
+// after the first; words that already have a capital are left as they are
+func titleCase(title string) string {
+	words := strings.Split(title, " ")
+	first := true
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		lower := strings.ToLower(word)
+		switch {
+		case !first && minorWords[lower] && (word == lower || word == capitalize(lower)):
+			words[i] = lower
+		case word == lower:
+			words[i] = capitalize(word)
+		}
+		first = false
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalize upper-cases the first letter of a word, after any leading
+// punctuation such as the parenthesis of "(trends)"
+func capitalize(word string) string {
+	for i, r := range word {
+		if unicode.IsLetter(r) {
+			return word[:i] + string(unicode.ToUpper(r)) + word[i+utf8.RuneLen(r):]
+		}
+	}
+	return word
+}
+
+// truncate shortens a title to limit characters at a word boundary. A
+// trailing qualifier in parentheses is kept, so variants such as
+// "Checkout (trends)" stay distinct from "Checkout".
+func truncate(title string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(title) <= limit {
+		return title
+	}
+	qualifier := ""
+	if i := strings.LastIndex(title, " ("); i > 0 && strings.HasSuffix(title, ")") {
+		if n := utf8.RuneCountInString(title[i:]); n < limit/2 {
+			title, qualifier = title[:i], title[i:]
+			limit -= n
+		}
+	}
+
+	runes := []rune(title)
+	cut := string(runes[:limit])
+	if limit < len(runes) && runes[limit] != ' ' {
+		if space := strings.LastIndex(cut, " "); space > 0 {
+			cut = cut[:space]
+		}
+	}
+	return strings.TrimRight(cut, separators) + qualifier
+}
@@ -0,0 +1,74 @@
+package naming
+
+import (
+	"errors"
+	"testing"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestApply(t *testing.T) {
+	rules := NewRules(&config.GrafanaNamingConfig{
+		Prefix:         "[Platform]",
+		TitleCase:      true,
+		MaxLength:      40,
+		ForbiddenWords: []string{"tmp", " test "},
+	})
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"checkout overview", "[Platform] Checkout Overview"},
+		{"[platform] errors by job", "[Platform] Errors by Job"},
+		{"gRPC latency per method - tmp", "[Platform] gRPC Latency per Method"},
+		{"TEST: node exporter", "[Platform] Node Exporter"},
+		{"payment service request latency and saturation (trends)", "[Platform] Payment Service (Trends)"},
+		{"payment service request latency and saturation", "[Platform] Payment Service Request"},
+	}
+	for _, tt := range tests {
+		got := rules.Apply(tt.title)
+		if got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+		if violations := rules.Lint(got); len(violations) != 0 {
+			t.Errorf("Expected %q to conform, got %+v", got, violations)
+		}
+	}
+
+	if got := (Rules{}).Apply(" any title "); got != " any title " {
+		t.Errorf("Expected no rules to keep the title, got %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules := NewRules(&config.GrafanaNamingConfig{Prefix: "[Platform]", TitleCase: true, MaxLength: 20, ForbiddenWords: []string{"copy"}})
+
+	err := rules.Check("checkout latency dashboard copy")
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || !errors.Is(err, ErrNonConforming) {
+		t.Fatalf("Expected a LintError, got %v", err)
+	}
+	var broken []string
+	for _, violation := range lintErr.Violations {
+		broken = append(broken, violation.Rule)
+	}
+	want := []string{RulePrefix, RuleTitleCase, RuleMaxLength, RuleForbiddenWord}
+	if len(broken) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, broken)
+	}
+	for i := range want {
+		if broken[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, broken)
+		}
+	}
+	if lintErr.Suggestion != "[Platform] Checkout" {
+		t.Errorf("Unexpected suggestion %q", lintErr.Suggestion)
+	}
+
+	if err := rules.Check("[Platform] Checkout"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := (Rules{}).Check("anything goes"); err != nil {
+		t.Errorf("Unexpected error without rules: %v", err)
+	}
+}
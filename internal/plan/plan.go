@@ -0,0 +1,139 @@
+// Package plan records the changes a bulk operation would make to Grafana,
+// so they can be reviewed as a terraform-style plan and applied later
+// exactly as reviewed.
+package plan
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+)
+
+// Change actions
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// ErrNotFound is returned for unknown, expired or already applied plans
+var ErrNotFound = errors.New("plan not found")
+
+// Change is a single planned change to a dashboard
+type Change struct {
+	Action string `json:"action"`
+	UID    string `json:"uid"`
+	Title  string `json:"title"`
+	Folder string `json:"folder,omitempty"`
+	// Version is the deployed version the change was planned against, 0
+	// for creates; apply refuses changes to dashboards edited since
+	Version int `json:"version,omitempty"`
+	// Changes lists the differences an update makes
+	Changes []diff.Change `json:"changes,omitempty"`
+
+	// Dashboard, FolderUID and FolderPath are what is saved on apply
+	Dashboard  map[string]any `json:"-"`
+	FolderUID  string         `json:"-"`
+	FolderPath string         `json:"-"`
+}
+
+// Summary counts the changes of a plan by action
+type Summary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Unchanged int `json:"unchanged"`
+}
+
+// String renders the summary like terraform, e.g.
+// "Plan: 2 to add, 1 to change, 0 to destroy."
+func (s Summary) String() string {
+	if s.Create+s.Update+s.Delete == 0 {
+		return "No changes. Grafana matches the desired dashboards."
+	}
+	return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", s.Create, s.Update, s.Delete)
+}
+
+// Plan is the set of changes computed against the Grafana state at CreatedAt
+type Plan struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	GrafanaURL string    `json:"grafana_url"`
+	// OrgID is the organization the plan was computed in, 0 for the default
+	OrgID     int      `json:"org_id,omitempty"`
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Summary counts the changes of the plan
+func (p *Plan) Summary() Summary {
+	summary := Summary{Unchanged: p.Unchanged}
+	for _, change := range p.Changes {
+		switch change.Action {
+		case Create:
+			summary.Create++
+		case Update:
+			summary.Update++
+		case Delete:
+			summary.Delete++
+		}
+	}
+	return summary
+}
+
+// Store keeps plans in memory until they are applied or expire
+type Store struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	plans map[string]*Plan
+}
+
+// NewStore returns a store keeping plans for ttl
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, now: time.Now, plans: map[string]*Plan{}}
+}
+
+// Put assigns the plan an ID and keeps it
+func (s *Store) Put(p *Plan) (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate plan id: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	p.ID = hex.EncodeToString(id)
+	p.CreatedAt = s.now()
+	s.plans[p.ID] = p
+	return p.ID, nil
+}
+
+// Take removes and returns a plan, so each plan is applied at most once
+func (s *Store) Take(id string) (*Plan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	p, ok := s.plans[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s may have expired or been applied already; create a new plan", ErrNotFound, id)
+	}
+	delete(s.plans, id)
+	return p, nil
+}
+
+// expire drops the plans older than the TTL; the caller holds the lock
+func (s *Store) expire() {
+	for id, p := range s.plans {
+		if s.now().Sub(p.CreatedAt) >= s.ttl {
+			delete(s.plans, id)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	p := &Plan{
+		Changes: []Change{
+			{Action: Create, UID: "a"},
+			{Action: Create, UID: "b"},
+			{Action: Update, UID: "c"},
+			{Action: Delete, UID: "d"},
+		},
+		Unchanged: 3,
+	}
+	summary := p.Summary()
+	require.Equal(t, Summary{Create: 2, Update: 1, Delete: 1, Unchanged: 3}, summary)
+	require.Equal(t, "Plan: 2 to add, 1 to change, 1 to destroy.", summary.String())
+	require.Equal(t, "No changes. Grafana matches the desired dashboards.", (&Plan{Unchanged: 2}).Summary().String())
+}
+
+func TestStore(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	store := NewStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	id, err := store.Put(&Plan{GrafanaURL: "http://grafana"})
+	require.NoError(t, err)
+	require.Len(t, id, 16)
+
+	p, err := store.Take(id)
+	require.NoError(t, err)
+	require.Equal(t, "http://grafana", p.GrafanaURL)
+	require.Equal(t, now, p.CreatedAt)
+
+	_, err = store.Take(id)
+	require.True(t, errors.Is(err, ErrNotFound), "a plan is applied at most once")
+
+	id, err = store.Put(&Plan{})
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = store.Take(id)
+	require.ErrorIs(t, err, ErrNotFound)
+}
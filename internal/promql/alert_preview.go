@@ -0,0 +1,87 @@
+package promql
+
+import (
+	"sort"
+	"time"
+)
+
+// maxPreviewFirings bounds the firings listed in an alert preview
+const maxPreviewFirings = 10
+
+// AlertPreview reports when an alert rule would have fired had it been
+// evaluated over a past window
+type AlertPreview struct {
+	Window string `json:"window"`
+	Fired  bool   `json:"fired"`
+	// TotalFirings counts every firing; Firings lists the most recent ones
+	TotalFirings int           `json:"total_firings"`
+	FiringSeries int           `json:"firing_series"`
+	FiringTime   string        `json:"firing_time,omitempty"`
+	Firings      []AlertFiring `json:"firings,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// AlertFiring is a period during which an alert would have been firing
+type AlertFiring struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Start  time.Time         `json:"start"`
+	End    time.Time         `json:"end"`
+	// Ongoing is set when the alert would still be firing at the end of the window
+	Ongoing bool `json:"ongoing,omitempty"`
+}
+
+// EvaluateAlertPreview replays an alert rule over the result of a range
+// query of its expression, evaluated every step until end. A series is
+// pending while it has a sample at consecutive steps and fires once it has
+// been pending for forDuration, mirroring the Prometheus rule evaluator.
+func EvaluateAlertPreview(series []Series, forDuration, step time.Duration, window time.Duration, end time.Time) AlertPreview {
+	preview := AlertPreview{Window: formatDuration(window)}
+
+	var firings []AlertFiring
+	var firingTime time.Duration
+	for _, s := range series {
+		samples := append([]SamplePoint(nil), s.Samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+		fired := false
+		for i := 0; i < len(samples); {
+			// A run is a stretch of samples without a missed evaluation
+			j := i
+			for j+1 < len(samples) && samples[j+1].Timestamp.Sub(samples[j].Timestamp) <= step+step/2 {
+				j++
+			}
+
+			start, last := samples[i].Timestamp, samples[j].Timestamp
+			if last.Sub(start) >= forDuration {
+				// The alert resolves at the first evaluation without a sample
+				firing := AlertFiring{
+					Labels: s.Labels,
+					Start:  start.Add(forDuration),
+					End:    last.Add(step),
+				}
+				if !firing.End.Before(end) {
+					firing.End, firing.Ongoing = end, true
+				}
+				firings = append(firings, firing)
+				firingTime += firing.End.Sub(firing.Start)
+				fired = true
+			}
+			i = j + 1
+		}
+		if fired {
+			preview.FiringSeries++
+		}
+	}
+
+	sort.SliceStable(firings, func(i, j int) bool { return firings[i].Start.After(firings[j].Start) })
+	preview.TotalFirings = len(firings)
+	preview.Fired = len(firings) > 0
+	if preview.Fired {
+		preview.FiringTime = formatDuration(firingTime)
+	}
+	if len(firings) > maxPreviewFirings {
+		firings = firings[:maxPreviewFirings]
+	}
+	preview.Firings = firings
+	return preview
+}
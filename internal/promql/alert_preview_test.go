@@ -0,0 +1,46 @@
+package promql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateAlertPreview(t *testing.T) {
+	end := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	start := end.Add(-24 * time.Hour)
+	samples := func(from, to time.Duration) []SamplePoint {
+		var points []SamplePoint
+		for offset := from; offset <= to; offset += time.Minute {
+			points = append(points, SamplePoint{Timestamp: start.Add(offset), Value: 1})
+		}
+		return points
+	}
+
+	series := []Series{
+		// Pending for 3m only: never fires with for 5m
+		{Labels: map[string]string{"instance": "a"}, Samples: samples(time.Hour, time.Hour+3*time.Minute)},
+		// Two separate firings, the second still firing at the end
+		{Labels: map[string]string{"instance": "b"}, Samples: append(samples(2*time.Hour, 2*time.Hour+10*time.Minute), samples(23*time.Hour, 24*time.Hour)...)},
+	}
+
+	preview := EvaluateAlertPreview(series, 5*time.Minute, time.Minute, 24*time.Hour, end)
+	if !preview.Fired || preview.TotalFirings != 2 || preview.FiringSeries != 1 || preview.Window != "24h" {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+
+	latest := preview.Firings[0]
+	if !latest.Ongoing || !latest.Start.Equal(start.Add(23*time.Hour+5*time.Minute)) || !latest.End.Equal(end) {
+		t.Errorf("unexpected latest firing %+v", latest)
+	}
+	earlier := preview.Firings[1]
+	if earlier.Ongoing || !earlier.Start.Equal(start.Add(2*time.Hour+5*time.Minute)) || !earlier.End.Equal(start.Add(2*time.Hour+11*time.Minute)) {
+		t.Errorf("unexpected earlier firing %+v", earlier)
+	}
+	if preview.FiringTime != "61m" {
+		t.Errorf("expected 61m of firing time, got %s", preview.FiringTime)
+	}
+
+	if quiet := EvaluateAlertPreview(nil, 0, time.Minute, 24*time.Hour, end); quiet.Fired || len(quiet.Firings) != 0 {
+		t.Errorf("expected no firings without series, got %+v", quiet)
+	}
+}
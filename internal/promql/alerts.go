@@ -0,0 +1,98 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AlertRuleCandidate is a suggested Prometheus/Grafana alert rule derived from a metric
+type AlertRuleCandidate struct {
+	Name        string `json:"name"`
+	Expr        string `json:"expr"`
+	For         string `json:"for"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	// Labels are the routing labels (team, service, severity) to attach so
+	// existing Alertmanager routes match the rule
+	Labels map[string]string `json:"labels,omitempty"`
+	// Preview reports when the rule would have fired over the past day
+	Preview *AlertPreview `json:"preview,omitempty"`
+}
+
+// absentLookback is how long a metric may be missing before absent_over_time fires
+const absentLookback = "10m"
+
+// generateAlertRules suggests alert rules detecting a metric (or the target
+// exporting it) disappearing, and counter resets caused by process restarts
+func generateAlertRules(metricInfo *MetricInfo) []AlertRuleCandidate {
+	series := metricInfo.Name
+	if metricInfo.Type == MetricTypeHistogram || metricInfo.Type == MetricTypeSummary {
+		series = histogramBaseName(metricInfo.Name) + "_count"
+	}
+	alertName := toAlertName(histogramBaseName(metricInfo.Name))
+
+	rules := []AlertRuleCandidate{
+		{
+			Name:        alertName + "Absent",
+			Expr:        fmt.Sprintf("absent(%s)", series),
+			For:         "5m",
+			Severity:    "critical",
+			Description: fmt.Sprintf("No %s series exist; every target exporting it has stopped reporting", series),
+		},
+		{
+			Name:        alertName + "AbsentOverTime",
+			Expr:        fmt.Sprintf("absent_over_time(%s[%s])", series, absentLookback),
+			For:         "0m",
+			Severity:    "warning",
+			Description: fmt.Sprintf("%s has not received a sample for %s (tolerates short scrape gaps)", series, absentLookback),
+		},
+	}
+
+	if slices.Contains(metricInfo.Labels, "job") {
+		rules = append(rules, AlertRuleCandidate{
+			Name:        alertName + "TargetDisappeared",
+			Expr:        fmt.Sprintf("count by (job) (%[1]s offset 1h) unless count by (job) (%[1]s)", series),
+			For:         "5m",
+			Severity:    "warning",
+			Description: fmt.Sprintf("A job reported %s an hour ago but no longer does", series),
+		})
+	}
+
+	// delta counters drop on every quiet export interval, so resets say
+	// nothing about restarts
+	resettable := metricInfo.Type == MetricTypeCounter || metricInfo.Type == MetricTypeHistogram || metricInfo.Type == MetricTypeSummary
+	if resettable && metricInfo.Temporality != TemporalityDelta {
+		by := ""
+		if slices.Contains(metricInfo.Labels, "instance") {
+			by = " by (instance)"
+		}
+		rules = append(rules, AlertRuleCandidate{
+			Name:        alertName + "CounterReset",
+			Expr:        fmt.Sprintf("sum%s (resets(%s[15m])) > 0", by, series),
+			For:         "0m",
+			Severity:    "info",
+			Description: fmt.Sprintf("%s reset within the last 15m, usually because the exporting process restarted", series),
+		})
+	}
+
+	return rules
+}
+
+// toAlertName converts a snake_case metric name into a CamelCase alert name
+func toAlertName(metricName string) string {
+	name := make([]byte, 0, len(metricName))
+	upper := true
+	for i := 0; i < len(metricName); i++ {
+		c := metricName[i]
+		if c == '_' || c == ':' {
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		name = append(name, c)
+	}
+	return string(name)
+}
@@ -0,0 +1,60 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestGenerateAlertRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		metricInfo *MetricInfo
+		expected   map[string]string
+		missing    []string
+	}{
+		{
+			name:       "counter with job and instance labels",
+			metricInfo: &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter, Labels: []string{"job", "instance"}},
+			expected: map[string]string{
+				"HttpRequestsTotalAbsent":            "absent(http_requests_total)",
+				"HttpRequestsTotalAbsentOverTime":    "absent_over_time(http_requests_total[10m])",
+				"HttpRequestsTotalTargetDisappeared": "count by (job) (http_requests_total offset 1h) unless count by (job) (http_requests_total)",
+				"HttpRequestsTotalCounterReset":      "sum by (instance) (resets(http_requests_total[15m])) > 0",
+			},
+		},
+		{
+			name:       "gauge has no counter reset rule",
+			metricInfo: &MetricInfo{Name: "node_memory_bytes", Type: MetricTypeGauge},
+			expected: map[string]string{
+				"NodeMemoryBytesAbsent": "absent(node_memory_bytes)",
+			},
+			missing: []string{"NodeMemoryBytesCounterReset", "NodeMemoryBytesTargetDisappeared"},
+		},
+		{
+			name:       "histogram uses the count series",
+			metricInfo: &MetricInfo{Name: "request_duration_seconds_bucket", Type: MetricTypeHistogram},
+			expected: map[string]string{
+				"RequestDurationSecondsAbsent":       "absent(request_duration_seconds_count)",
+				"RequestDurationSecondsCounterReset": "sum (resets(request_duration_seconds_count[15m])) > 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := map[string]string{}
+			for _, rule := range generateAlertRules(tt.metricInfo) {
+				rules[rule.Name] = rule.Expr
+			}
+			for name, expr := range tt.expected {
+				if rules[name] != expr {
+					t.Errorf("Rule %s = %q, want %q", name, rules[name], expr)
+				}
+			}
+			for _, name := range tt.missing {
+				if _, ok := rules[name]; ok {
+					t.Errorf("Did not expect rule %s", name)
+				}
+			}
+		})
+	}
+}
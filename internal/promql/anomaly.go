@@ -0,0 +1,93 @@
+package promql
+
+import "fmt"
+
+// CategoryAnomaly marks suggestions that flag unusual values or forecast
+// saturation, for capacity and saturation dashboards
+const CategoryAnomaly = "anomaly"
+
+// Anomaly detection parameters
+const (
+	// anomalyBaseline is the range the z-score compares the current value to
+	anomalyBaseline = "1d"
+	// forecastRange and forecastHorizon drive the linear forecast: the trend
+	// of the last 6h extrapolated 4h ahead
+	forecastRange   = "6h"
+	forecastHorizon = 4 * 3600
+	// smoothingFactor and trendFactor weigh recent samples and the trend in
+	// the double exponential smoothing
+	smoothingFactor = 0.3
+	trendFactor     = 0.3
+)
+
+// zScoreThresholds colour deviations beyond two and three standard deviations
+func zScoreThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(2), Color: "orange"},
+		{Value: floatPtr(3), Color: "red"},
+	}
+}
+
+// generateAnomalyQueries derives anomaly suggestions from the primary query
+// of a metric. Every metric gets a z-score against its daily baseline;
+// gauges, which saturate, also get a linear forecast and, when the server
+// names its smoothing function, a double exponential smoothing. Queries that
+// are not a bare selector, such as a counter's rate, go through a subquery.
+func generateAnomalyQueries(metricInfo *MetricInfo, base QuerySuggestion, opts GenerateOptions) []QuerySuggestion {
+	isGauge := metricInfo.Type == MetricTypeGauge
+	isCounter := metricInfo.Type == MetricTypeCounter && metricInfo.Temporality != TemporalityDelta
+	if !isGauge && !isCounter {
+		return nil
+	}
+
+	step := formatDuration(rateWindow(metricInfo))
+	baseline := rangeOf(base.Query, anomalyBaseline, step)
+	suggestions := []QuerySuggestion{
+		{
+			Query:             fmt.Sprintf("(%s - avg_over_time(%s)) / stddev_over_time(%s)", base.Query, baseline, baseline),
+			Description:       fmt.Sprintf("%s as a z-score: standard deviations from its %s average", base.Description, anomalyBaseline),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "z-score",
+			Category:          CategoryAnomaly,
+			Thresholds:        zScoreThresholds(),
+			Group:             base.Group,
+		},
+	}
+	if !isGauge {
+		return suggestions
+	}
+
+	suggestions = append(suggestions, QuerySuggestion{
+		Query:             fmt.Sprintf("predict_linear(%s, %d)", rangeOf(base.Query, forecastRange, step), forecastHorizon),
+		Description:       fmt.Sprintf("%s forecast %dh ahead from the %s trend", base.Description, forecastHorizon/3600, forecastRange),
+		VisualizationType: "timeseries",
+		YAxisLabel:        base.YAxisLabel,
+		Category:          CategoryAnomaly,
+		Unit:              base.Unit,
+		Group:             base.Group,
+	})
+
+	if opts.Features != nil && opts.Features.Smoothing != "" {
+		suggestions = append(suggestions, QuerySuggestion{
+			Query:             fmt.Sprintf("%s(%s, %g, %g)", opts.Features.Smoothing, rangeOf(base.Query, "1h", step), smoothingFactor, trendFactor),
+			Description:       fmt.Sprintf("%s smoothed over 1h (double exponential smoothing), to tell a shift from noise", base.Description),
+			VisualizationType: "timeseries",
+			YAxisLabel:        base.YAxisLabel,
+			Category:          CategoryAnomaly,
+			Unit:              base.Unit,
+			Group:             base.Group,
+		})
+	}
+
+	return suggestions
+}
+
+// rangeOf turns a query into a range vector: a range selector when the query
+// is a bare selector, otherwise a subquery at the given resolution
+func rangeOf(query, r, step string) string {
+	if selectors := ParseSelectors(query); len(selectors) == 1 && selectors[0].Start == 0 && selectors[0].End == len(query) {
+		return fmt.Sprintf("%s[%s]", query, r)
+	}
+	return fmt.Sprintf("(%s)[%s:%s]", query, r, step)
+}
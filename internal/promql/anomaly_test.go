@@ -0,0 +1,64 @@
+package promql
+
+import (
+	"testing"
+)
+
+func anomalySuggestions(metricInfo *MetricInfo, features *ServerFeatures) map[string]QuerySuggestion {
+	found := map[string]QuerySuggestion{}
+	for _, s := range generateQueries(metricInfo, GenerateOptions{Intents: []QueryIntent{QueryIntentAnomaly}, Features: features}) {
+		if s.Category == CategoryAnomaly {
+			found[s.Query] = s
+		}
+	}
+	return found
+}
+
+func TestGenerateQueries_Anomaly(t *testing.T) {
+	t.Run("gauge", func(t *testing.T) {
+		gauge := &MetricInfo{Name: "node_filesystem_avail_bytes", Type: MetricTypeGauge}
+		found := anomalySuggestions(gauge, &ServerFeatures{Smoothing: "holt_winters"})
+
+		for _, query := range []string{
+			"(node_filesystem_avail_bytes - avg_over_time(node_filesystem_avail_bytes[1d])) / stddev_over_time(node_filesystem_avail_bytes[1d])",
+			"predict_linear(node_filesystem_avail_bytes[6h], 14400)",
+			"holt_winters(node_filesystem_avail_bytes[1h], 0.3, 0.3)",
+		} {
+			if _, ok := found[query]; !ok {
+				t.Errorf("Expected anomaly suggestion %s in %v", query, found)
+			}
+		}
+		zScore := found["(node_filesystem_avail_bytes - avg_over_time(node_filesystem_avail_bytes[1d])) / stddev_over_time(node_filesystem_avail_bytes[1d])"]
+		if len(zScore.Thresholds) != 3 || *zScore.Thresholds[2].Value != 3 {
+			t.Errorf("Expected z-score thresholds at 2 and 3, got %+v", zScore.Thresholds)
+		}
+	})
+
+	t.Run("smoothing needs a known function", func(t *testing.T) {
+		gauge := &MetricInfo{Name: "queue_depth", Type: MetricTypeGauge}
+		if found := anomalySuggestions(gauge, nil); len(found) != 2 {
+			t.Errorf("Expected the z-score and forecast only, got %v", found)
+		}
+		found := anomalySuggestions(gauge, &ServerFeatures{Smoothing: "double_exponential_smoothing"})
+		if _, ok := found["double_exponential_smoothing(queue_depth[1h], 0.3, 0.3)"]; !ok {
+			t.Errorf("Expected the Prometheus 3 smoothing function, got %v", found)
+		}
+	})
+
+	t.Run("counter rate through a subquery", func(t *testing.T) {
+		counter := &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter}
+		found := anomalySuggestions(counter, nil)
+
+		want := "(rate(http_requests_total[5m]) - avg_over_time((rate(http_requests_total[5m]))[1d:5m])) / stddev_over_time((rate(http_requests_total[5m]))[1d:5m])"
+		if _, ok := found[want]; !ok || len(found) != 1 {
+			t.Errorf("Expected only the rate z-score %s, got %v", want, found)
+		}
+	})
+
+	t.Run("histograms are skipped", func(t *testing.T) {
+		histogram := &MetricInfo{Name: "http_request_duration_seconds_bucket", Type: MetricTypeHistogram}
+		if found := anomalySuggestions(histogram, nil); len(found) != 0 {
+			t.Errorf("Expected no anomaly suggestions for histograms, got %v", found)
+		}
+	})
+}
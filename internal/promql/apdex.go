@@ -0,0 +1,80 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// CategoryApdex marks Apdex score suggestions
+const CategoryApdex = "apdex"
+
+// Default Apdex thresholds in seconds: requests faster than T are satisfied,
+// requests faster than 4T are tolerated
+const (
+	defaultApdexSatisfied        = 0.3
+	defaultApdexToleratingFactor = 4
+)
+
+// latencyMetricPattern identifies histograms that measure durations
+var latencyMetricPattern = regexp.MustCompile(`(duration|latency|seconds|_time)`)
+
+// Threshold is a Grafana threshold step; a nil Value marks the base step
+type Threshold struct {
+	Value *float64 `json:"value"`
+	Color string   `json:"color"`
+}
+
+// apdexThresholds colours Apdex scores using the conventional rating bands
+func apdexThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "red"},
+		{Value: floatPtr(0.5), Color: "orange"},
+		{Value: floatPtr(0.7), Color: "yellow"},
+		{Value: floatPtr(0.85), Color: "green"},
+	}
+}
+
+// apdexBounds resolves the satisfied and tolerating thresholds from the options
+func apdexBounds(opts GenerateOptions) (float64, float64) {
+	satisfied := opts.ApdexSatisfied
+	if satisfied <= 0 {
+		satisfied = defaultApdexSatisfied
+	}
+	tolerating := opts.ApdexTolerating
+	if tolerating <= satisfied {
+		tolerating = satisfied * defaultApdexToleratingFactor
+	}
+	return satisfied, tolerating
+}
+
+// generateApdexQueries builds an Apdex score suggestion for a latency histogram:
+// (satisfied + tolerating/2) / total, where the bucket at 4T already contains
+// the satisfied requests
+func generateApdexQueries(baseName, window string, opts GenerateOptions) []QuerySuggestion {
+	if !latencyMetricPattern.MatchString(baseName) {
+		return nil
+	}
+
+	satisfied, tolerating := apdexBounds(opts)
+	le := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	query := fmt.Sprintf(
+		`(sum(rate(%[1]s_bucket{le="%[2]s"}[%[4]s])) + sum(rate(%[1]s_bucket{le="%[3]s"}[%[4]s]))) / 2 / sum(rate(%[1]s_count[%[4]s]))`,
+		baseName, le(satisfied), le(tolerating), window)
+
+	return []QuerySuggestion{
+		{
+			Query:             query,
+			Description:       fmt.Sprintf("Apdex score (satisfied <= %ss, tolerating <= %ss); le values must match bucket boundaries", le(satisfied), le(tolerating)),
+			VisualizationType: "stat",
+			YAxisLabel:        "apdex",
+			Category:          CategoryApdex,
+			Thresholds:        apdexThresholds(),
+		},
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
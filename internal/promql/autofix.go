@@ -0,0 +1,283 @@
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// QueryFix represents a candidate repair for an invalid PromQL query
+type QueryFix struct {
+	Query       string  `json:"query"`
+	Description string  `json:"description"`
+	Confidence  float64 `json:"confidence"`
+	Valid       *bool   `json:"valid,omitempty"`
+}
+
+// promqlFunctions lists the functions and aggregation operators understood by PromQL
+var promqlFunctions = []string{
+	"abs", "absent", "absent_over_time", "acos", "acosh", "asin", "asinh", "atan", "atanh",
+	"avg_over_time", "ceil", "changes", "clamp", "clamp_max", "clamp_min", "cos", "cosh",
+	"count_over_time", "day_of_month", "day_of_week", "day_of_year", "days_in_month", "deg",
+	"delta", "deriv", "exp", "floor", "histogram_avg", "histogram_count", "histogram_fraction",
+	"histogram_quantile", "histogram_stddev", "histogram_stdvar", "histogram_sum", "holt_winters",
+	"double_exponential_smoothing", "hour", "idelta", "increase", "irate", "label_join",
+	"label_replace", "last_over_time", "ln", "log10", "log2", "mad_over_time", "max_over_time",
+	"min_over_time", "minute", "month", "pi", "predict_linear", "present_over_time",
+	"quantile_over_time", "rad", "rate", "resets", "round", "scalar", "sgn", "sin", "sinh",
+	"sort", "sort_by_label", "sort_by_label_desc", "sort_desc", "sqrt", "stddev_over_time",
+	"stdvar_over_time", "sum_over_time", "tan", "tanh", "time", "timestamp", "vector", "year",
+	"sum", "avg", "min", "max", "count", "stddev", "stdvar", "topk", "bottomk", "quantile",
+	"count_values", "group", "limitk", "limit_ratio",
+	"by", "without", "on", "ignoring", "group_left", "group_right", "bool", "and", "or", "unless",
+}
+
+// suggestFixes proposes structured repairs for an invalid query: balancing
+// delimiters, quoting bare label values and correcting misspelled function
+// names. Candidates are ranked by confidence, highest first.
+func suggestFixes(query string) []QueryFix {
+	var fixes []QueryFix
+	seen := map[string]bool{query: true}
+
+	add := func(candidate, description string, confidence float64) {
+		if seen[candidate] {
+			return
+		}
+		seen[candidate] = true
+		fixes = append(fixes, QueryFix{Query: candidate, Description: description, Confidence: confidence})
+	}
+
+	combined := query
+	var applied []string
+	combinedConfidence := 1.0
+
+	if quoted := quoteBareLabelValues(query); quoted != query {
+		add(quoted, "quoted bare label values", 0.9)
+		combined = quoteBareLabelValues(combined)
+		applied = append(applied, "quoted bare label values")
+		combinedConfidence *= 0.9
+	}
+
+	for _, fn := range unknownFunctions(query) {
+		for i, candidate := range closestFunctions(fn.name) {
+			distance := EditDistance(fn.name, candidate)
+			confidence := 1 - 0.2*float64(distance) - 0.05*float64(i)
+			add(query[:fn.start]+candidate+query[fn.end:],
+				fmt.Sprintf("replaced unknown function %q with %q", fn.name, candidate), confidence)
+
+			if i == 0 {
+				combined = replaceIdentifier(combined, fn.name, candidate)
+				applied = append(applied, fmt.Sprintf("%s -> %s", fn.name, candidate))
+				combinedConfidence *= confidence
+			}
+		}
+	}
+
+	if balanced := balanceDelimiters(query); balanced != query {
+		add(balanced, "balanced unclosed or stray brackets, braces and quotes", 0.8)
+		combined = balanceDelimiters(combined)
+		applied = append(applied, "balanced delimiters")
+		combinedConfidence *= 0.8
+	}
+
+	if len(applied) > 1 {
+		add(combined, "applied all repairs: "+strings.Join(applied, ", "), combinedConfidence)
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return fixes[i].Confidence > fixes[j].Confidence
+	})
+
+	return fixes
+}
+
+// closestFunctions returns up to three known functions close to name, breaking
+// edit-distance ties in favour of the longest shared prefix
+func closestFunctions(name string) []string {
+	candidates := ClosestMatches(name, promqlFunctions, 3, len(promqlFunctions))
+	sort.SliceStable(candidates, func(i, j int) bool {
+		di, dj := EditDistance(name, candidates[i]), EditDistance(name, candidates[j])
+		if di != dj {
+			return di < dj
+		}
+		return commonPrefixLen(name, candidates[i]) > commonPrefixLen(name, candidates[j])
+	})
+	if len(candidates) > 3 {
+		candidates = candidates[:3]
+	}
+	return candidates
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+type identifierSpan struct {
+	name       string
+	start, end int
+}
+
+// unknownFunctions returns identifiers used as function calls that PromQL does not define
+func unknownFunctions(query string) []identifierSpan {
+	known := make(map[string]bool, len(promqlFunctions))
+	for _, fn := range promqlFunctions {
+		known[fn] = true
+	}
+
+	var unknown []identifierSpan
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(query, i)
+		case c == '{':
+			i = skipUntil(query, i, '}')
+		case c == '[':
+			i = skipUntil(query, i, ']')
+		case c == '$':
+			i = skipVariable(query, i)
+		case isIdentStart(c) && (i == 0 || !isIdentChar(query[i-1])):
+			start := i
+			for i < len(query) && isIdentChar(query[i]) {
+				i++
+			}
+			next := skipSpaces(query, i)
+			name := query[start:i]
+			if next < len(query) && query[next] == '(' && !known[name] {
+				unknown = append(unknown, identifierSpan{name: name, start: start, end: i})
+			}
+		default:
+			i++
+		}
+	}
+
+	return unknown
+}
+
+// replaceIdentifier replaces whole-word occurrences of an identifier used as a function call
+func replaceIdentifier(query, from, to string) string {
+	for _, fn := range unknownFunctions(query) {
+		if fn.name == from {
+			return query[:fn.start] + to + query[fn.end:]
+		}
+	}
+	return query
+}
+
+// quoteBareLabelValues wraps unquoted label matcher values in double quotes
+func quoteBareLabelValues(query string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := skipString(query, i)
+			b.WriteString(query[i:end])
+			i = end
+		case c == '$' && i+1 < len(query) && query[i+1] == '{':
+			end := skipUntil(query, i, '}')
+			b.WriteString(query[i:end])
+			i = end
+		case c == '{':
+			end := skipUntil(query, i, '}')
+			b.WriteString(quoteMatcherBlock(query[i:end]))
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// quoteMatcherBlock quotes bare values within a single `{...}` block
+func quoteMatcherBlock(block string) string {
+	var b strings.Builder
+	i := 0
+	for i < len(block) {
+		c := block[i]
+		if c == '"' || c == '\'' || c == '`' {
+			end := skipString(block, i)
+			b.WriteString(block[i:end])
+			i = end
+			continue
+		}
+
+		op := ""
+		for _, candidate := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(block[i:], candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		b.WriteString(op)
+		i += len(op)
+		vStart := skipSpaces(block, i)
+		b.WriteString(block[i:vStart])
+		i = vStart
+		if i < len(block) && (block[i] == '"' || block[i] == '\'' || block[i] == '`') {
+			continue
+		}
+
+		for i < len(block) && block[i] != ',' && block[i] != '}' {
+			i++
+		}
+		value := strings.TrimSpace(block[vStart:i])
+		if value != "" {
+			b.WriteString(quoteLabelValue(value))
+		}
+	}
+	return b.String()
+}
+
+// balanceDelimiters drops stray closing delimiters and appends the missing
+// closers for unterminated strings, parentheses, braces and brackets
+func balanceDelimiters(query string) string {
+	pairs := map[byte]byte{')': '(', '}': '{', ']': '['}
+	closers := map[byte]byte{'(': ')', '{': '}', '[': ']'}
+
+	var b strings.Builder
+	var stack []byte
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch c {
+		case '"', '\'', '`':
+			end := skipString(query, i)
+			b.WriteString(query[i:end])
+			if end == len(query) && (end-i < 2 || query[end-1] != c) {
+				b.WriteByte(c)
+			}
+			i = end
+			continue
+		case '(', '{', '[':
+			stack = append(stack, c)
+		case ')', '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != pairs[c] {
+				i++
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+		b.WriteByte(c)
+		i++
+	}
+
+	for j := len(stack) - 1; j >= 0; j-- {
+		b.WriteByte(closers[stack[j]])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestSuggestFixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "unclosed parenthesis",
+			query:    "sum(rate(http_requests_total[5m])",
+			expected: "sum(rate(http_requests_total[5m]))",
+		},
+		{
+			name:     "stray closing brace",
+			query:    `up{job="api"}}`,
+			expected: `up{job="api"}`,
+		},
+		{
+			name:     "bare label value",
+			query:    "up{job=api, env!=prod}",
+			expected: `up{job="api", env!="prod"}`,
+		},
+		{
+			name:     "misspelled function",
+			query:    "rat(http_requests_total[5m])",
+			expected: "rate(http_requests_total[5m])",
+		},
+		{
+			name:     "misspelled histogram function",
+			query:    "histogram_quantil(0.9, rate(x_bucket[5m]))",
+			expected: "histogram_quantile(0.9, rate(x_bucket[5m]))",
+		},
+		{
+			name:     "combined repairs",
+			query:    "rat(up{job=api}[5m]",
+			expected: `rate(up{job="api"}[5m])`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixes := suggestFixes(tt.query)
+			found := false
+			for _, fix := range fixes {
+				if fix.Query == tt.expected {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("suggestFixes(%q) = %+v, want a candidate %q", tt.query, fixes, tt.expected)
+			}
+			for i := 1; i < len(fixes); i++ {
+				if fixes[i-1].Confidence < fixes[i].Confidence {
+					t.Errorf("fixes are not ranked by confidence: %+v", fixes)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestFixes_ValidQueryHasNoFixes(t *testing.T) {
+	if fixes := suggestFixes(`sum by (job) (rate(http_requests_total{code=~"5.."}[5m]))`); len(fixes) != 0 {
+		t.Errorf("Expected no fixes for a well-formed query, got %+v", fixes)
+	}
+}
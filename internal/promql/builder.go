@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -28,6 +32,18 @@ type MetricInfo struct {
 	Type   MetricType `json:"type"`
 	Help   string     `json:"help"`
 	Labels []string   `json:"labels"`
+	// ScrapeInterval is the longest scrape interval of the targets exposing
+	// the metric (e.g. "15s"), when known
+	ScrapeInterval string `json:"scrape_interval,omitempty"`
+	// LabelCardinality holds the number of distinct values per label, when known
+	LabelCardinality map[string]int `json:"label_cardinality,omitempty"`
+	// LabelValues holds the observed values of outcome labels (status, code, ...)
+	LabelValues map[string][]string `json:"label_values,omitempty"`
+	// SeriesCount is the number of active series of the metric, when known
+	SeriesCount int `json:"series_count,omitempty"`
+	// Temporality is TemporalityDelta for OpenTelemetry delta counters and
+	// empty for cumulative metrics
+	Temporality string `json:"temporality,omitempty"`
 }
 
 // QuerySuggestion represents a suggested PromQL query for a metric
@@ -36,74 +52,112 @@ type QuerySuggestion struct {
 	Description       string `json:"description"`
 	VisualizationType string `json:"visualization_type"`
 	YAxisLabel        string `json:"y_axis_label"`
+	// DashboardQuery is the query to use in dashboard targets, with rate
+	// windows expressed as $__rate_interval so Grafana adapts them to the step
+	DashboardQuery string `json:"dashboard_query,omitempty"`
+	// Category marks suggestions serving a specific purpose (trend, comparison, ...)
+	Category string `json:"category,omitempty"`
+	// Thresholds are suggested threshold steps for the panel, when meaningful
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+	// PanelDescription explains the panel showing the query: what it
+	// computes, the metric HELP text and the unit
+	PanelDescription string `json:"panel_description,omitempty"`
+	// Exemplar is set when the server stores exemplars for the query, so
+	// panels should request them alongside the samples
+	Exemplar bool `json:"exemplar,omitempty"`
+	// Unit is the Grafana unit of the query result, when known
+	Unit string `json:"unit,omitempty"`
+	// Group is the dashboard row the panel belongs in, e.g. "HTTP server"
+	// for metrics following the OpenTelemetry semantic conventions
+	Group string `json:"group,omitempty"`
+	// Sample is what the query returns now, when results were sampled
+	Sample *ResultSample `json:"sample,omitempty"`
+	// Interval is the minimum interval for the panel, set when the queries
+	// read downsampled data over a long time range
+	Interval string `json:"interval,omitempty"`
+	// Warning notes a problem with the query over the requested time range
+	Warning string `json:"warning,omitempty"`
 }
 
+// RateIntervalVariable is the Grafana variable used for rate windows in dashboard targets
+const RateIntervalVariable = "$__rate_interval"
+
+// defaultRateWindow is the rate window used when the scrape interval is unknown
+const defaultRateWindow = 5 * time.Minute
+
+// SamplePoint represents a single sample of a time series
+type SamplePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// Series represents a time series returned by a Prometheus query
+type Series struct {
+	Labels  map[string]string `json:"labels"`
+	Samples []SamplePoint     `json:"samples,omitempty"`
+}
+
+// seriesCountQuery counts the active series of every metric name
+const seriesCountQuery = `count by (__name__) ({__name__=~".+"})`
+
+// cardinalityWindow is the time range whose series label values are counted
+const cardinalityWindow = time.Hour
+
+// cardinalitySeriesLimit caps the series read to count label values; the
+// counts of metrics with more series are lower bounds
+const cardinalitySeriesLimit = 10000
+
 // prometheusClient handles communication with Prometheus API
 type prometheusClient struct {
 	baseURL string
 	client  *http.Client
+	// datasource is set when Prometheus is reached through a Grafana datasource
+	datasource *grafanaDatasource
+	// intervals caches the scrape intervals of the active targets; nil
+	// fetches them on every lookup
+	intervals *targetIntervals
 }
 
-// newPrometheusClient creates a new Prometheus client
-func newPrometheusClient(baseURL string) *prometheusClient {
+// newPrometheusClient creates a new Prometheus client. A nil transport
+// uses http.DefaultTransport.
+func newPrometheusClient(baseURL string, transport http.RoundTripper) *prometheusClient {
 	return &prometheusClient{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
 	}
 }
 
 // discoverMetrics discovers all available metrics from Prometheus with optional filtering
-func (c *prometheusClient) discoverMetrics(ctx context.Context, namePattern string, metricType MetricType) ([]MetricInfo, error) {
-	// Get all metric names
-	metricsURL := fmt.Sprintf("%s/api/v1/label/__name__/values", c.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", metricsURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query Prometheus metrics: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
-	}
-
-	var metricsResp struct {
-		Status string   `json:"status"`
-		Data   []string `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&metricsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode metrics response: %w", err)
-	}
-
-	if metricsResp.Status != "success" {
-		return nil, fmt.Errorf("prometheus API returned non-success status: %s", metricsResp.Status)
-	}
-
-	// Compile regex pattern if provided
-	var pattern *regexp.Regexp
-	if namePattern != "" {
-		pattern, err = regexp.Compile(namePattern)
+func (c *prometheusClient) discoverMetrics(ctx context.Context, pattern *regexp.Regexp, metricType MetricType, selector string) ([]MetricInfo, error) {
+	var metricNames []string
+	var seriesLabels map[string][]string
+	var err error
+
+	if selector != "" {
+		// Scope discovery to the series matching the selector
+		metricNames, seriesLabels, err = c.getSeriesMetrics(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Get all metric names
+		metricNames, err = c.getLabelValues(ctx, "__name__", nil)
 		if err != nil {
-			return nil, fmt.Errorf("invalid name pattern: %w", err)
+			return nil, fmt.Errorf("failed to query Prometheus metrics: %w", err)
 		}
 	}
 
 	// Fetch metadata for all metrics
 	metadataURL := fmt.Sprintf("%s/api/v1/metadata", c.baseURL)
-	req, err = http.NewRequestWithContext(ctx, "GET", metadataURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", metadataURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create metadata request: %w", err)
 	}
 
-	resp, err = c.client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query Prometheus metadata: %w", err)
 	}
@@ -131,7 +185,7 @@ func (c *prometheusClient) discoverMetrics(ctx context.Context, namePattern stri
 
 	// Filter and build result
 	var results []MetricInfo
-	for _, metricName := range metricsResp.Data {
+	for _, metricName := range metricNames {
 		// Apply name pattern filter
 		if pattern != nil && !pattern.MatchString(metricName) {
 			continue
@@ -156,10 +210,13 @@ func (c *prometheusClient) discoverMetrics(ctx context.Context, namePattern stri
 			continue
 		}
 
-		// Get labels for this metric
-		labels, err := c.getMetricLabels(ctx, metricName)
-		if err != nil {
-			labels = []string{}
+		// Get labels for this metric; scoped discovery already knows them
+		labels, ok := seriesLabels[metricName]
+		if !ok {
+			labels, err = c.getMetricLabels(ctx, metricName)
+			if err != nil {
+				labels = []string{}
+			}
 		}
 
 		results = append(results, MetricInfo{
@@ -173,6 +230,94 @@ func (c *prometheusClient) discoverMetrics(ctx context.Context, namePattern stri
 	return results, nil
 }
 
+// getSeries returns the label sets of the series matching the given
+// selectors between start and end; zero times leave the range to the
+// server, which defaults to its whole retention. A positive limit caps the
+// number of series returned on servers that support it.
+func (c *prometheusClient) getSeries(ctx context.Context, matchers []string, start, end time.Time, limit int) ([]map[string]string, error) {
+	seriesURL := fmt.Sprintf("%s/api/v1/series", c.baseURL)
+
+	data := url.Values{}
+	for _, m := range matchers {
+		data.Add("match[]", m)
+	}
+	if !start.IsZero() {
+		data.Set("start", strconv.FormatInt(start.Unix(), 10))
+	}
+	if !end.IsZero() {
+		data.Set("end", strconv.FormatInt(end.Unix(), 10))
+	}
+	if limit > 0 {
+		data.Set("limit", strconv.Itoa(limit))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", seriesURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create series request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query series: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var seriesResp struct {
+		Status    string              `json:"status"`
+		Error     string              `json:"error"`
+		ErrorType string              `json:"errorType"`
+		Data      []map[string]string `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&seriesResp); err != nil {
+		return nil, fmt.Errorf("failed to decode series response: %w", err)
+	}
+
+	if seriesResp.Status != "success" {
+		return nil, fmt.Errorf("series query failed: %s (%s)", seriesResp.Error, seriesResp.ErrorType)
+	}
+
+	return seriesResp.Data, nil
+}
+
+// getSeriesMetrics returns the metric names matching a series selector along
+// with the label names observed on each metric's series
+func (c *prometheusClient) getSeriesMetrics(ctx context.Context, selector string) ([]string, map[string][]string, error) {
+	series, err := c.getSeries(ctx, []string{selector}, time.Time{}, time.Time{}, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+
+	labelSets := map[string]map[string]bool{}
+	for _, s := range series {
+		name := s["__name__"]
+		if name == "" {
+			continue
+		}
+		if labelSets[name] == nil {
+			labelSets[name] = map[string]bool{"__name__": true}
+		}
+		for label := range s {
+			labelSets[name][label] = true
+		}
+	}
+
+	names := make([]string, 0, len(labelSets))
+	labels := make(map[string][]string, len(labelSets))
+	for name, set := range labelSets {
+		names = append(names, name)
+		for label := range set {
+			labels[name] = append(labels[name], label)
+		}
+		sort.Strings(labels[name])
+	}
+	sort.Strings(names)
+
+	return names, labels, nil
+}
+
 // getMetricMetadata fetches metadata for a specific metric from Prometheus
 func (c *prometheusClient) getMetricMetadata(ctx context.Context, metricName string) (*MetricInfo, error) {
 	metadataURL := fmt.Sprintf("%s/api/v1/metadata?metric=%s", c.baseURL, url.QueryEscape(metricName))
@@ -223,17 +368,136 @@ func (c *prometheusClient) getMetricMetadata(ctx context.Context, metricName str
 		labels = []string{}
 	}
 
-	return &MetricInfo{
+	info := &MetricInfo{
 		Name:   metricName,
 		Type:   data[0].Type,
 		Help:   data[0].Help,
 		Labels: labels,
-	}, nil
+	}
+
+	if interval, err := c.getScrapeInterval(ctx, metricName); err == nil && interval > 0 {
+		info.ScrapeInterval = formatDuration(interval)
+	}
+
+	info.LabelCardinality, info.LabelValues = c.getLabelCardinality(ctx, metricName, labels)
+
+	if isDeltaCandidate(info) {
+		if temporality, err := c.detectTemporality(ctx, metricName); err == nil && temporality == TemporalityDelta {
+			info.Type, info.Temporality = MetricTypeCounter, temporality
+		}
+	}
+
+	return info, nil
+}
+
+// getLabelCardinality counts the distinct values of each label on a metric
+// from a single series lookup over the last cardinalityWindow, capped at
+// cardinalitySeriesLimit series. The values of outcome labels are returned
+// as well so error queries can match them.
+func (c *prometheusClient) getLabelCardinality(ctx context.Context, metricName string, labels []string) (map[string]int, map[string][]string) {
+	end := time.Now()
+	series, err := c.getSeries(ctx, []string{fmt.Sprintf("{__name__=%q}", metricName)}, end.Add(-cardinalityWindow), end, cardinalitySeriesLimit)
+	if err != nil {
+		return nil, nil
+	}
+
+	distinct := map[string]map[string]bool{}
+	for _, s := range series {
+		for _, label := range labels {
+			value, ok := s[label]
+			if !ok || strings.HasPrefix(label, "__") {
+				continue
+			}
+			if distinct[label] == nil {
+				distinct[label] = map[string]bool{}
+			}
+			distinct[label][value] = true
+		}
+	}
+
+	cardinality := map[string]int{}
+	outcomeValues := map[string][]string{}
+	for label, values := range distinct {
+		cardinality[label] = len(values)
+		if isOutcomeLabel(label) {
+			outcomeValues[label] = slices.Sorted(maps.Keys(values))
+		}
+	}
+
+	if len(cardinality) == 0 {
+		cardinality = nil
+	}
+	if len(outcomeValues) == 0 {
+		outcomeValues = nil
+	}
+	return cardinality, outcomeValues
+}
+
+// getScrapeInterval resolves the longest scrape interval among the targets
+// exposing a metric, using /api/v1/targets/metadata to find the targets and
+// the intervals of the active targets, cached per Prometheus, for their
+// configured intervals
+func (c *prometheusClient) getScrapeInterval(ctx context.Context, metricName string) (time.Duration, error) {
+	metadataURL := fmt.Sprintf("%s/api/v1/targets/metadata?metric=%s", c.baseURL, url.QueryEscape(metricName))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", metadataURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query target metadata: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	var metadataResp struct {
+		Status string `json:"status"`
+		Data   []struct {
+			Target map[string]string `json:"target"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&metadataResp); err != nil {
+		return 0, fmt.Errorf("failed to decode target metadata response: %w", err)
+	}
+
+	if metadataResp.Status != "success" {
+		return 0, fmt.Errorf("target metadata API returned non-success status: %s", metadataResp.Status)
+	}
+
+	jobs := map[string]bool{}
+	for _, d := range metadataResp.Data {
+		if job := d.Target["job"]; job != "" {
+			jobs[job] = true
+		}
+	}
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
+	intervals, err := c.intervals.get(ctx, c.baseURL, c.getJobIntervals)
+	if err != nil {
+		return 0, err
+	}
+
+	var longest time.Duration
+	for job := range jobs {
+		longest = max(longest, intervals[job])
+	}
+
+	return longest, nil
 }
 
 // getMetricLabels fetches available labels for a metric
 func (c *prometheusClient) getMetricLabels(ctx context.Context, metricName string) ([]string, error) {
-	labelsURL := fmt.Sprintf("%s/api/v1/labels", c.baseURL)
+	params := url.Values{}
+	params.Set("match[]", fmt.Sprintf("{__name__=%q}", metricName))
+	labelsURL := fmt.Sprintf("%s/api/v1/labels?%s", c.baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "GET", labelsURL, nil)
 	if err != nil {
@@ -268,6 +532,10 @@ func (c *prometheusClient) getMetricLabels(ctx context.Context, metricName strin
 
 // validateQuery validates a PromQL query against Prometheus
 func (c *prometheusClient) validateQuery(ctx context.Context, query string) error {
+	if c.datasource != nil {
+		return c.dsValidateQuery(ctx, query)
+	}
+
 	queryURL := fmt.Sprintf("%s/api/v1/query", c.baseURL)
 
 	data := url.Values{}
@@ -304,34 +572,348 @@ func (c *prometheusClient) validateQuery(ctx context.Context, query string) erro
 	return nil
 }
 
+// getSeriesCounts returns the number of active series per metric name
+func (c *prometheusClient) getSeriesCounts(ctx context.Context) (map[string]int, error) {
+	if c.datasource != nil {
+		return c.dsSeriesCounts(ctx)
+	}
+
+	queryURL := fmt.Sprintf("%s/api/v1/query", c.baseURL)
+
+	data := url.Values{}
+	data.Set("query", seriesCountQuery)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", queryURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create series count request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count series: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var queryResp struct {
+		Status    string `json:"status"`
+		Error     string `json:"error"`
+		ErrorType string `json:"errorType"`
+		Data      struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []any             `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("failed to decode series count response: %w", err)
+	}
+
+	if queryResp.Status != "success" {
+		return nil, fmt.Errorf("series count query failed: %s (%s)", queryResp.Error, queryResp.ErrorType)
+	}
+
+	counts := make(map[string]int, len(queryResp.Data.Result))
+	for _, r := range queryResp.Data.Result {
+		if point, ok := parseSamplePoint(r.Value); ok {
+			counts[r.Metric["__name__"]] = int(point.Value)
+		}
+	}
+
+	return counts, nil
+}
+
+// queryRange executes a PromQL range query and returns the resulting series
+func (c *prometheusClient) queryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	if c.datasource != nil {
+		return c.dsQuery(ctx, query, start, end, step, false)
+	}
+
+	queryURL := fmt.Sprintf("%s/api/v1/query_range", c.baseURL)
+
+	data := url.Values{}
+	data.Set("query", query)
+	data.Set("start", strconv.FormatInt(start.Unix(), 10))
+	data.Set("end", strconv.FormatInt(end.Unix(), 10))
+	data.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", queryURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var queryResp struct {
+		Status    string `json:"status"`
+		Error     string `json:"error"`
+		ErrorType string `json:"errorType"`
+		Data      struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Values [][]any           `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("failed to decode query response: %w", err)
+	}
+
+	if queryResp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s (%s)", queryResp.Error, queryResp.ErrorType)
+	}
+
+	series := make([]Series, 0, len(queryResp.Data.Result))
+	for _, r := range queryResp.Data.Result {
+		s := Series{Labels: r.Metric}
+		for _, v := range r.Values {
+			if point, ok := parseSamplePoint(v); ok {
+				s.Samples = append(s.Samples, point)
+			}
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// query executes an instant PromQL query at the given time. Scalar results
+// are returned as a single series without labels.
+func (c *prometheusClient) query(ctx context.Context, query string, at time.Time) ([]Series, error) {
+	if c.datasource != nil {
+		return c.dsQuery(ctx, query, at.Add(-time.Minute), at, 0, true)
+	}
+
+	queryURL := fmt.Sprintf("%s/api/v1/query", c.baseURL)
+
+	data := url.Values{}
+	data.Set("query", query)
+	data.Set("time", strconv.FormatInt(at.Unix(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", queryURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var queryResp struct {
+		Status    string `json:"status"`
+		Error     string `json:"error"`
+		ErrorType string `json:"errorType"`
+		Data      struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("failed to decode query response: %w", err)
+	}
+
+	if queryResp.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s (%s)", queryResp.Error, queryResp.ErrorType)
+	}
+
+	switch queryResp.Data.ResultType {
+	case "scalar":
+		var value []any
+		if err := json.Unmarshal(queryResp.Data.Result, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode scalar result: %w", err)
+		}
+		s := Series{Labels: map[string]string{}}
+		if point, ok := parseSamplePoint(value); ok {
+			s.Samples = append(s.Samples, point)
+		}
+		return []Series{s}, nil
+	case "vector":
+		var result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []any             `json:"value"`
+		}
+		if err := json.Unmarshal(queryResp.Data.Result, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode vector result: %w", err)
+		}
+		series := make([]Series, 0, len(result))
+		for _, r := range result {
+			s := Series{Labels: r.Metric}
+			if point, ok := parseSamplePoint(r.Value); ok {
+				s.Samples = append(s.Samples, point)
+			}
+			series = append(series, s)
+		}
+		return series, nil
+	}
+	return nil, fmt.Errorf("unsupported result type %q of an instant query", queryResp.Data.ResultType)
+}
+
+// getLabelValues fetches the values of a label, optionally scoped by series matchers
+func (c *prometheusClient) getLabelValues(ctx context.Context, label string, matchers []string) ([]string, error) {
+	params := url.Values{}
+	for _, m := range matchers {
+		params.Add("match[]", m)
+	}
+
+	valuesURL := fmt.Sprintf("%s/api/v1/label/%s/values", c.baseURL, url.PathEscape(label))
+	if len(params) > 0 {
+		valuesURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", valuesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query label values: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	var valuesResp struct {
+		Status string   `json:"status"`
+		Data   []string `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&valuesResp); err != nil {
+		return nil, fmt.Errorf("failed to decode label values response: %w", err)
+	}
+
+	if valuesResp.Status != "success" {
+		return nil, fmt.Errorf("label values API returned non-success status: %s", valuesResp.Status)
+	}
+
+	return valuesResp.Data, nil
+}
+
+// parseSamplePoint converts a Prometheus [timestamp, "value"] pair into a SamplePoint
+func parseSamplePoint(raw []any) (SamplePoint, bool) {
+	if len(raw) != 2 {
+		return SamplePoint{}, false
+	}
+	ts, ok := raw[0].(float64)
+	if !ok {
+		return SamplePoint{}, false
+	}
+	str, ok := raw[1].(string)
+	if !ok {
+		return SamplePoint{}, false
+	}
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return SamplePoint{}, false
+	}
+	sec := int64(ts)
+	return SamplePoint{
+		Timestamp: time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC(),
+		Value:     value,
+	}, true
+}
+
 // generateQueries generates appropriate PromQL queries based on metric type and name
-func generateQueries(metricInfo *MetricInfo) []QuerySuggestion {
+func generateQueries(metricInfo *MetricInfo, opts GenerateOptions) []QuerySuggestion {
 	var suggestions []QuerySuggestion
 
 	switch metricInfo.Type {
 	case MetricTypeCounter:
+		if metricInfo.Temporality == TemporalityDelta {
+			suggestions = generateDeltaCounterQueries(metricInfo)
+			break
+		}
 		suggestions = generateCounterQueries(metricInfo)
 	case MetricTypeGauge:
 		suggestions = generateGaugeQueries(metricInfo)
 	case MetricTypeHistogram:
 		suggestions = generateHistogramQueries(metricInfo)
+		suggestions = append(suggestions, generateApdexQueries(histogramBaseName(metricInfo.Name), formatDuration(rateWindow(metricInfo)), opts)...)
 	case MetricTypeSummary:
 		suggestions = generateSummaryQueries(metricInfo)
 	default:
 		suggestions = generateDefaultQueries(metricInfo)
 	}
 
+	suggestions = applyConventions(metricInfo, suggestions, formatDuration(rateWindow(metricInfo)))
+	suggestions = applyProbeQueries(metricInfo, suggestions)
+	suggestions = applyStateQueries(metricInfo, suggestions)
+
+	if len(suggestions) > 0 {
+		suggestions = append(suggestions, generateIntentQueries(suggestions[0], opts)...)
+		if opts.hasIntent(QueryIntentAnomaly) {
+			suggestions = append(suggestions, generateAnomalyQueries(metricInfo, suggestions[0], opts)...)
+		}
+	}
+
+	suggestions = applyServerFeatures(metricInfo, suggestions, formatDuration(rateWindow(metricInfo)), opts.Features)
+	suggestions = applyReplicaDeduplication(metricInfo, suggestions, opts.ReplicaLabels)
+
+	window := "[" + formatDuration(rateWindow(metricInfo)) + "]"
+	for i := range suggestions {
+		suggestions[i].DashboardQuery = strings.ReplaceAll(suggestions[i].Query, window, "["+RateIntervalVariable+"]")
+	}
+	suggestions = applyDownsampling(suggestions, DownsamplingResolution(opts.Range, opts.Features))
+	for i := range suggestions {
+		suggestions[i].PanelDescription = DescribeSuggestion(metricInfo, suggestions[i], suggestions[i].Unit)
+	}
+
 	return suggestions
 }
 
+// rateWindow returns the minimum safe range for rate() over a metric: four
+// scrape intervals (matching Grafana's $__rate_interval floor), or five
+// minutes when the scrape interval is unknown
+func rateWindow(metricInfo *MetricInfo) time.Duration {
+	if metricInfo.ScrapeInterval == "" {
+		return defaultRateWindow
+	}
+	interval, err := time.ParseDuration(metricInfo.ScrapeInterval)
+	if err != nil || interval <= 0 {
+		return defaultRateWindow
+	}
+	return max(4*interval, time.Minute)
+}
+
+// formatDuration renders a duration in PromQL notation (e.g. 90s -> "90s", 5m0s -> "5m")
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
+
 // generateCounterQueries generates queries for counter metrics
 func generateCounterQueries(metricInfo *MetricInfo) []QuerySuggestion {
 	metricName := metricInfo.Name
+	window := formatDuration(rateWindow(metricInfo))
 
 	suggestions := []QuerySuggestion{
 		{
-			Query:             fmt.Sprintf("rate(%s[5m])", metricName),
-			Description:       "Rate per second over 5 minutes",
+			Query:             fmt.Sprintf("rate(%s[%s])", metricName, window),
+			Description:       fmt.Sprintf("Rate per second over %s", window),
 			VisualizationType: "timeseries",
 			YAxisLabel:        "per second",
 		},
@@ -344,18 +926,28 @@ func generateCounterQueries(metricInfo *MetricInfo) []QuerySuggestion {
 	}
 
 	if len(metricInfo.Labels) > 0 {
-		for _, label := range metricInfo.Labels {
-			if label != "__name__" && !strings.HasPrefix(label, "__") {
+		for _, label := range rankGroupingLabels(metricInfo) {
+			if isHighCardinalityLabel(metricInfo, label) {
 				suggestions = append(suggestions, QuerySuggestion{
-					Query:             fmt.Sprintf("sum by (%s) (rate(%s[5m]))", label, metricName),
-					Description:       fmt.Sprintf("Rate per second grouped by %s", label),
+					Query:             fmt.Sprintf("topk(%d, sum by (%s) (rate(%s[%s])))", topKLimit, label, metricName, window),
+					Description:       fmt.Sprintf("Top %d %s values by rate per second", topKLimit, label),
 					VisualizationType: "timeseries",
 					YAxisLabel:        "per second",
+					Category:          CategoryTopK,
 				})
+				continue
 			}
+			suggestions = append(suggestions, QuerySuggestion{
+				Query:             fmt.Sprintf("sum by (%s) (rate(%s[%s]))", label, metricName, window),
+				Description:       fmt.Sprintf("Rate per second grouped by %s", label),
+				VisualizationType: "timeseries",
+				YAxisLabel:        "per second",
+			})
 		}
 	}
 
+	suggestions = append(suggestions, generateErrorRatioQueries(metricInfo, metricName, window)...)
+
 	return suggestions
 }
 
@@ -400,15 +992,13 @@ func generateGaugeQueries(metricInfo *MetricInfo) []QuerySuggestion {
 			},
 		)
 
-		for _, label := range metricInfo.Labels {
-			if label != "__name__" && !strings.HasPrefix(label, "__") {
-				suggestions = append(suggestions, QuerySuggestion{
-					Query:             fmt.Sprintf("avg by (%s) (%s)", label, metricName),
-					Description:       fmt.Sprintf("Average grouped by %s", label),
-					VisualizationType: "timeseries",
-					YAxisLabel:        "avg value",
-				})
-			}
+		for _, label := range rankGroupingLabels(metricInfo) {
+			suggestions = append(suggestions, QuerySuggestion{
+				Query:             fmt.Sprintf("avg by (%s) (%s)", label, metricName),
+				Description:       fmt.Sprintf("Average grouped by %s", label),
+				VisualizationType: "timeseries",
+				YAxisLabel:        "avg value",
+			})
 		}
 	}
 
@@ -417,60 +1007,69 @@ func generateGaugeQueries(metricInfo *MetricInfo) []QuerySuggestion {
 
 // generateHistogramQueries generates queries for histogram metrics
 func generateHistogramQueries(metricInfo *MetricInfo) []QuerySuggestion {
-	baseName := strings.TrimSuffix(metricInfo.Name, "_bucket")
-	baseName = strings.TrimSuffix(baseName, "_count")
-	baseName = strings.TrimSuffix(baseName, "_sum")
+	baseName := histogramBaseName(metricInfo.Name)
+	window := formatDuration(rateWindow(metricInfo))
 
 	suggestions := []QuerySuggestion{
 		{
-			Query:             fmt.Sprintf("histogram_quantile(0.50, rate(%s_bucket[5m]))", baseName),
-			Description:       "50th percentile (median) over 5 minutes",
+			Query:             fmt.Sprintf("histogram_quantile(0.50, rate(%s_bucket[%s]))", baseName, window),
+			Description:       fmt.Sprintf("50th percentile (median) over %s", window),
 			VisualizationType: "timeseries",
 			YAxisLabel:        "duration",
 		},
 		{
-			Query:             fmt.Sprintf("histogram_quantile(0.95, rate(%s_bucket[5m]))", baseName),
-			Description:       "95th percentile over 5 minutes",
+			Query:             fmt.Sprintf("histogram_quantile(0.95, rate(%s_bucket[%s]))", baseName, window),
+			Description:       fmt.Sprintf("95th percentile over %s", window),
 			VisualizationType: "timeseries",
 			YAxisLabel:        "duration",
 		},
 		{
-			Query:             fmt.Sprintf("histogram_quantile(0.99, rate(%s_bucket[5m]))", baseName),
-			Description:       "99th percentile over 5 minutes",
+			Query:             fmt.Sprintf("histogram_quantile(0.99, rate(%s_bucket[%s]))", baseName, window),
+			Description:       fmt.Sprintf("99th percentile over %s", window),
 			VisualizationType: "timeseries",
 			YAxisLabel:        "duration",
 		},
 		{
-			Query:             fmt.Sprintf("rate(%s_count[5m])", baseName),
+			Query:             fmt.Sprintf("rate(%s_count[%s])", baseName, window),
 			Description:       "Request rate (requests per second)",
 			VisualizationType: "timeseries",
 			YAxisLabel:        "requests/sec",
 		},
 		{
-			Query:             fmt.Sprintf("rate(%s_sum[5m]) / rate(%s_count[5m])", baseName, baseName),
+			Query:             fmt.Sprintf("rate(%s_sum[%s]) / rate(%s_count[%s])", baseName, window, baseName, window),
 			Description:       "Average duration",
 			VisualizationType: "timeseries",
 			YAxisLabel:        "avg duration",
 		},
 	}
 
+	suggestions = append(suggestions, generateErrorRatioQueries(metricInfo, baseName+"_count", window)...)
+
 	return suggestions
 }
 
+// histogramBaseName strips the _bucket/_count/_sum suffix of a histogram series
+func histogramBaseName(metricName string) string {
+	baseName := strings.TrimSuffix(metricName, "_bucket")
+	baseName = strings.TrimSuffix(baseName, "_count")
+	return strings.TrimSuffix(baseName, "_sum")
+}
+
 // generateSummaryQueries generates queries for summary metrics
 func generateSummaryQueries(metricInfo *MetricInfo) []QuerySuggestion {
 	baseName := strings.TrimSuffix(metricInfo.Name, "_count")
 	baseName = strings.TrimSuffix(baseName, "_sum")
+	window := formatDuration(rateWindow(metricInfo))
 
 	suggestions := []QuerySuggestion{
 		{
-			Query:             fmt.Sprintf("rate(%s_count[5m])", baseName),
+			Query:             fmt.Sprintf("rate(%s_count[%s])", baseName, window),
 			Description:       "Request rate (requests per second)",
 			VisualizationType: "timeseries",
 			YAxisLabel:        "requests/sec",
 		},
 		{
-			Query:             fmt.Sprintf("rate(%s_sum[5m]) / rate(%s_count[5m])", baseName, baseName),
+			Query:             fmt.Sprintf("rate(%s_sum[%s]) / rate(%s_count[%s])", baseName, window, baseName, window),
 			Description:       "Average value",
 			VisualizationType: "timeseries",
 			YAxisLabel:        "avg value",
@@ -502,6 +1101,8 @@ func generateDefaultQueries(metricInfo *MetricInfo) []QuerySuggestion {
 		return generateCounterQueries(metricInfo)
 	}
 
+	window := formatDuration(rateWindow(metricInfo))
+
 	return []QuerySuggestion{
 		{
 			Query:             metricName,
@@ -510,14 +1111,88 @@ func generateDefaultQueries(metricInfo *MetricInfo) []QuerySuggestion {
 			YAxisLabel:        "value",
 		},
 		{
-			Query:             fmt.Sprintf("rate(%s[5m])", metricName),
-			Description:       "Rate of change over 5 minutes",
+			Query:             fmt.Sprintf("rate(%s[%s])", metricName, window),
+			Description:       fmt.Sprintf("Rate of change over %s", window),
 			VisualizationType: "timeseries",
 			YAxisLabel:        "per second",
 		},
 	}
 }
 
+// topKLimit is the number of series kept by topk() variants for high-cardinality labels
+const topKLimit = 5
+
+// highCardinalityThreshold is the distinct-value count above which a grouping
+// label is considered too wide to plot every series
+const highCardinalityThreshold = 10
+
+// highCardinalityLabels are labels that typically carry one value per
+// instance, pod or request path
+var highCardinalityLabels = map[string]bool{
+	"instance": true, "pod": true, "pod_name": true, "container": true, "container_id": true,
+	"path": true, "url": true, "uri": true, "route": true, "endpoint": true, "handler": true,
+	"user": true, "user_id": true, "id": true, "ip": true, "client": true, "host": true,
+}
+
+// semanticLabelPriority ranks labels that make meaningful dashboard groupings;
+// lower values are preferred
+var semanticLabelPriority = map[string]int{
+	"status": 0, "code": 0, "status_code": 0, "grpc_code": 0, "outcome": 0,
+	"method": 1, "grpc_method": 1, "operation": 1,
+	"service": 2, "job": 2, "namespace": 2, "cluster": 2, "region": 2,
+}
+
+// rankGroupingLabels orders a metric's labels for `by (...)` suggestions:
+// semantically meaningful labels first, then ascending distinct-value count,
+// with instance-level labels last. Labels with a single known value are
+// dropped since grouping by them adds nothing.
+func rankGroupingLabels(metricInfo *MetricInfo) []string {
+	labels := make([]string, 0, len(metricInfo.Labels))
+	for _, label := range metricInfo.Labels {
+		if strings.HasPrefix(label, "__") {
+			continue
+		}
+		if count, ok := metricInfo.LabelCardinality[label]; ok && count <= 1 {
+			continue
+		}
+		labels = append(labels, label)
+	}
+
+	priority := func(label string) int {
+		if p, ok := semanticLabelPriority[label]; ok {
+			return p
+		}
+		if highCardinalityLabels[label] {
+			return 4
+		}
+		return 3
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		pi, pj := priority(labels[i]), priority(labels[j])
+		if pi != pj {
+			return pi < pj
+		}
+		ci, iok := metricInfo.LabelCardinality[labels[i]]
+		cj, jok := metricInfo.LabelCardinality[labels[j]]
+		if iok && jok && ci != cj {
+			return ci < cj
+		}
+		return false
+	})
+
+	return labels
+}
+
+// isHighCardinalityLabel reports whether grouping by a label is likely to
+// produce too many series, using known cardinality when available
+func isHighCardinalityLabel(metricInfo *MetricInfo, label string) bool {
+	if count, ok := metricInfo.LabelCardinality[label]; ok {
+		return count > highCardinalityThreshold
+	}
+	return highCardinalityLabels[label]
+}
+
 // inferMetricType attempts to infer the metric type from the metric name
 func inferMetricType(metricName string) MetricType {
 	if strings.HasSuffix(metricName, "_total") ||
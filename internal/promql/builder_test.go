@@ -1,7 +1,13 @@
 package promql
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestInferMetricType(t *testing.T) {
@@ -88,6 +94,224 @@ func TestGenerateCounterQueries(t *testing.T) {
 	}
 }
 
+func TestGenerateCounterQueries_TopK(t *testing.T) {
+	metricInfo := &MetricInfo{
+		Name:             "http_requests_total",
+		Type:             MetricTypeCounter,
+		Labels:           []string{"method", "pod", "status"},
+		LabelCardinality: map[string]int{"status": 250},
+	}
+
+	expected := map[string]string{
+		"sum by (method) (rate(http_requests_total[5m]))":          "",
+		"topk(5, sum by (pod) (rate(http_requests_total[5m])))":    CategoryTopK,
+		"topk(5, sum by (status) (rate(http_requests_total[5m])))": CategoryTopK,
+	}
+
+	found := 0
+	for _, suggestion := range generateCounterQueries(metricInfo) {
+		if category, ok := expected[suggestion.Query]; ok {
+			found++
+			if suggestion.Category != category {
+				t.Errorf("Query %s category = %q, want %q", suggestion.Query, suggestion.Category, category)
+			}
+		}
+		if suggestion.Query == "sum by (pod) (rate(http_requests_total[5m]))" {
+			t.Error("Expected ungrouped pod sum to be replaced by a topk variant")
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("Expected %d grouped suggestions, found %d", len(expected), found)
+	}
+}
+
+func TestRankGroupingLabels(t *testing.T) {
+	metricInfo := &MetricInfo{
+		Name:   "http_requests_total",
+		Labels: []string{"__name__", "instance", "region", "team", "method", "status", "env"},
+		LabelCardinality: map[string]int{
+			"instance": 40,
+			"region":   3,
+			"team":     7,
+			"method":   4,
+			"status":   6,
+			"env":      1,
+		},
+	}
+
+	got := rankGroupingLabels(metricInfo)
+	want := []string{"status", "method", "region", "team", "instance"}
+	if len(got) != len(want) {
+		t.Fatalf("rankGroupingLabels() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rankGroupingLabels() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPrometheusClientGetLabelCardinality(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/v1/series" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("match[]") != `{__name__="http_requests_total"}` {
+			t.Errorf("expected series to be scoped to the metric, got %q", r.Form.Get("match[]"))
+		}
+		if r.Form.Get("limit") != "10000" {
+			t.Errorf("expected series lookup to be limited, got %q", r.Form.Get("limit"))
+		}
+		if r.Form.Get("start") == "" || r.Form.Get("end") == "" {
+			t.Error("expected series lookup to be bounded in time")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data": []map[string]string{
+				{"__name__": "http_requests_total", "method": "GET", "pod": "a", "code": "500"},
+				{"__name__": "http_requests_total", "method": "POST", "pod": "b", "code": "200"},
+				{"__name__": "http_requests_total", "method": "GET", "pod": "c", "code": "200"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cardinality, values := newPrometheusClient(server.URL, nil).getLabelCardinality(context.Background(), "http_requests_total", []string{"__name__", "method", "pod", "code"})
+	if requests != 1 {
+		t.Errorf("expected a single series lookup, got %d requests", requests)
+	}
+	if cardinality["method"] != 2 || cardinality["pod"] != 3 || cardinality["code"] != 2 {
+		t.Errorf("unexpected cardinality %v", cardinality)
+	}
+	if _, ok := cardinality["__name__"]; ok {
+		t.Error("Expected internal labels to be skipped")
+	}
+	if got := strings.Join(values["code"], ","); got != "200,500" {
+		t.Errorf("expected sorted outcome values, got %s", got)
+	}
+	if _, ok := values["method"]; ok {
+		t.Error("Expected only outcome label values to be returned")
+	}
+}
+
+func TestPrometheusClientDiscoverMetricsWithSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/series":
+			_ = r.ParseForm()
+			if r.Form.Get("match[]") != `{job="checkout"}` {
+				t.Errorf("expected selector to be forwarded, got %q", r.Form.Get("match[]"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": "success",
+				"data": []map[string]string{
+					{"__name__": "http_requests_total", "job": "checkout", "code": "200"},
+					{"__name__": "http_requests_total", "job": "checkout", "method": "POST"},
+					{"__name__": "up", "job": "checkout"},
+				},
+			})
+		case "/api/v1/metadata":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": "success",
+				"data": map[string]any{
+					"http_requests_total": []map[string]string{{"type": "counter", "help": "Requests"}},
+				},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	metrics, err := newPrometheusClient(server.URL, nil).discoverMetrics(context.Background(), nil, "", `{job="checkout"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(metrics) != 2 || metrics[0].Name != "http_requests_total" || metrics[1].Name != "up" {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	if got := strings.Join(metrics[0].Labels, ","); got != "__name__,code,job,method" {
+		t.Errorf("expected labels from the matching series, got %s", got)
+	}
+	if metrics[0].Type != MetricTypeCounter {
+		t.Errorf("expected counter type, got %s", metrics[0].Type)
+	}
+}
+
+func TestPrometheusClientGetSeriesCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data": map[string]any{
+				"resultType": "vector",
+				"result": []map[string]any{
+					{"metric": map[string]string{"__name__": "up"}, "value": []any{1700000000.0, "3"}},
+					{"metric": map[string]string{"__name__": "http_requests_total"}, "value": []any{1700000000.0, "120"}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	counts, err := newPrometheusClient(server.URL, nil).getSeriesCounts(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts["up"] != 3 || counts["http_requests_total"] != 120 {
+		t.Errorf("unexpected series counts %v", counts)
+	}
+}
+
+func TestPrometheusClientQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("time") != "1700000000" {
+			t.Errorf("expected the query time, got %q", r.Form.Get("time"))
+		}
+		result := map[string]any{
+			"resultType": "vector",
+			"result": []map[string]any{
+				{"metric": map[string]string{"job": "api"}, "value": []any{1700000000.0, "2.5"}},
+			},
+		}
+		if r.Form.Get("query") == "scalar(up)" {
+			result = map[string]any{"resultType": "scalar", "result": []any{1700000000.0, "1"}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": result})
+	}))
+	defer server.Close()
+
+	client := newPrometheusClient(server.URL, nil)
+	at := time.Unix(1700000000, 0)
+
+	series, err := client.query(context.Background(), "sum by (job) (rate(x[5m]))", at)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(series) != 1 || series[0].Labels["job"] != "api" || series[0].Samples[0].Value != 2.5 {
+		t.Errorf("unexpected vector result %+v", series)
+	}
+
+	series, err = client.query(context.Background(), "scalar(up)", at)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(series) != 1 || len(series[0].Labels) != 0 || series[0].Samples[0].Value != 1 {
+		t.Errorf("unexpected scalar result %+v", series)
+	}
+}
+
 func TestGenerateGaugeQueries(t *testing.T) {
 	metricInfo := &MetricInfo{
 		Name:   "memory_usage_bytes",
@@ -169,13 +393,13 @@ func TestGetBestQuery(t *testing.T) {
 }
 
 func TestPrometheusClientValidateQuery(t *testing.T) {
-	client := newPrometheusClient("http://localhost:9090")
+	client := newPrometheusClient("http://localhost:9090", nil)
 
 	if client.baseURL != "http://localhost:9090" {
 		t.Errorf("Expected baseURL to be http://localhost:9090, got %s", client.baseURL)
 	}
 
-	clientWithSlash := newPrometheusClient("http://localhost:9090/")
+	clientWithSlash := newPrometheusClient("http://localhost:9090/", nil)
 	if clientWithSlash.baseURL != "http://localhost:9090" {
 		t.Errorf("Expected trailing slash to be trimmed, got %s", clientWithSlash.baseURL)
 	}
@@ -219,6 +443,111 @@ func TestQuerySuggestionCreation(t *testing.T) {
 	}
 }
 
+func TestGenerateQueries_RateInterval(t *testing.T) {
+	tests := []struct {
+		name           string
+		scrapeInterval string
+		wantQuery      string
+	}{
+		{
+			name:      "unknown scrape interval keeps 5m window",
+			wantQuery: "rate(http_requests_total[5m])",
+		},
+		{
+			name:           "15s scrape interval uses a 1m window",
+			scrapeInterval: "15s",
+			wantQuery:      "rate(http_requests_total[1m])",
+		},
+		{
+			name:           "60s scrape interval uses a 4m window",
+			scrapeInterval: "1m",
+			wantQuery:      "rate(http_requests_total[4m])",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := generateQueries(&MetricInfo{
+				Name:           "http_requests_total",
+				Type:           MetricTypeCounter,
+				ScrapeInterval: tt.scrapeInterval,
+			}, GenerateOptions{})
+
+			if suggestions[0].Query != tt.wantQuery {
+				t.Errorf("Query = %s, want %s", suggestions[0].Query, tt.wantQuery)
+			}
+			if suggestions[0].DashboardQuery != "rate(http_requests_total[$__rate_interval])" {
+				t.Errorf("DashboardQuery = %s, want $__rate_interval window", suggestions[0].DashboardQuery)
+			}
+			if suggestions[1].DashboardQuery != "increase(http_requests_total[1h])" {
+				t.Errorf("Expected fixed increase window to be kept, got %s", suggestions[1].DashboardQuery)
+			}
+		})
+	}
+}
+
+func TestPrometheusClientGetScrapeInterval(t *testing.T) {
+	targetLists := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/targets/metadata":
+			job := map[string]string{"http_requests_total": "api", "node_load1": "node"}[r.URL.Query().Get("metric")]
+			if job == "" {
+				t.Errorf("unexpected metric %q", r.URL.Query().Get("metric"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": "success",
+				"data": []any{
+					map[string]any{"target": map[string]string{"job": job, "instance": "a"}},
+				},
+			})
+		case "/api/v1/targets":
+			targetLists++
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"status": "success",
+				"data": map[string]any{
+					"activeTargets": []any{
+						map[string]any{"labels": map[string]string{"job": "api"}, "scrapeInterval": "30s"},
+						map[string]any{"labels": map[string]string{"job": "node"}, "scrapeInterval": "15s"},
+						map[string]any{"labels": map[string]string{"job": "node"}, "scrapeInterval": "1m"},
+						map[string]any{"labels": map[string]string{"job": "other"}, "scrapeInterval": "5m"},
+					},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := newTargetIntervals(time.Minute)
+	cache.now = func() time.Time { return now }
+	client := newPrometheusClient(server.URL, nil)
+	client.intervals = cache
+
+	for metric, want := range map[string]time.Duration{"http_requests_total": 30 * time.Second, "node_load1": time.Minute} {
+		interval, err := client.getScrapeInterval(context.Background(), metric)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if interval != want {
+			t.Errorf("Expected %v scrape interval for %s, got %v", want, metric, interval)
+		}
+	}
+	if targetLists != 1 {
+		t.Errorf("Expected the target list fetched once, got %d", targetLists)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := client.getScrapeInterval(context.Background(), "node_load1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if targetLists != 2 {
+		t.Errorf("Expected the target list fetched again once expired, got %d", targetLists)
+	}
+}
+
 // Benchmark tests for performance verification
 func BenchmarkGenerateCounterQueries(b *testing.B) {
 	metricInfo := &MetricInfo{
@@ -249,3 +578,30 @@ func BenchmarkInferMetricType(b *testing.B) {
 		}
 	}
 }
+
+func TestPrometheusClientSeriesForwardsTimeRange(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	end := start.Add(6 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("match[]") != `{__name__="up"}` {
+			t.Errorf("expected matcher to be forwarded, got %q", r.Form.Get("match[]"))
+		}
+		if r.Form.Get("start") != "1700000000" || r.Form.Get("end") != "1700021600" {
+			t.Errorf("expected time range to be forwarded, got start=%q end=%q", r.Form.Get("start"), r.Form.Get("end"))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   []map[string]string{{"__name__": "up", "job": "api"}},
+		})
+	}))
+	defer server.Close()
+
+	series, err := newPrometheusClient(server.URL, nil).getSeries(context.Background(), []string{`{__name__="up"}`}, start, end, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(series) != 1 || series[0]["job"] != "api" {
+		t.Errorf("unexpected series %v", series)
+	}
+}
@@ -0,0 +1,228 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ServerType identifies the Prometheus-compatible server behind a URL
+type ServerType string
+
+const (
+	ServerTypePrometheus      ServerType = "prometheus"
+	ServerTypeThanos          ServerType = "thanos"
+	ServerTypeMimir           ServerType = "mimir"
+	ServerTypeVictoriaMetrics ServerType = "victoriametrics"
+	ServerTypeUnknown         ServerType = "unknown"
+)
+
+// ServerFeatures are the optional query features a server supports. Query
+// generation only uses a feature when it is known to be available.
+type ServerFeatures struct {
+	// NativeHistograms is set when the server ingests native histograms,
+	// which are queried without the _bucket suffix and le label
+	NativeHistograms bool `json:"native_histograms"`
+	// Exemplars is set when the server stores exemplars, so panels can link
+	// latency outliers to traces
+	Exemplars bool `json:"exemplars"`
+	// AtModifier is set when the server evaluates the `@` modifier
+	AtModifier bool `json:"at_modifier"`
+	// Smoothing is the double exponential smoothing function the server
+	// evaluates: holt_winters, or double_exponential_smoothing as it was
+	// renamed in Prometheus 3 behind a feature flag; empty when unknown
+	Smoothing string `json:"smoothing,omitempty"`
+	// Downsampling is set when the server answers long ranges from
+	// downsampled data, as a Thanos querier with auto downsampling does
+	Downsampling bool `json:"downsampling"`
+}
+
+// ServerInfo describes the detected server and its features
+type ServerInfo struct {
+	Type     ServerType     `json:"type"`
+	Version  string         `json:"version,omitempty"`
+	Features ServerFeatures `json:"features"`
+}
+
+// buildInfo is the data of a /api/v1/status/buildinfo response
+type buildInfo struct {
+	// Application is only reported by Mimir ("Grafana Mimir")
+	Application string `json:"application"`
+	Version     string `json:"version"`
+	Revision    string `json:"revision"`
+	GoVersion   string `json:"goVersion"`
+}
+
+// getBuildInfo fetches the server build information
+func (c *prometheusClient) getBuildInfo(ctx context.Context) (*buildInfo, error) {
+	var info buildInfo
+	if err := c.getStatus(ctx, "buildinfo", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// getFlags fetches the command-line flags the server was started with
+func (c *prometheusClient) getFlags(ctx context.Context) (map[string]string, error) {
+	var flags map[string]string
+	if err := c.getStatus(ctx, "flags", &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// getStatus decodes the data of a /api/v1/status/<endpoint> response
+func (c *prometheusClient) getStatus(ctx context.Context, endpoint string, data any) error {
+	statusURL := fmt.Sprintf("%s/api/v1/status/%s", c.baseURL, endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", endpoint, err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", endpoint, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request failed with status %d", endpoint, resp.StatusCode)
+	}
+
+	var statusResp struct {
+		Status string          `json:"status"`
+		Error  string          `json:"error"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", endpoint, err)
+	}
+	if statusResp.Status != "success" {
+		return fmt.Errorf("%s request failed: %s", endpoint, statusResp.Error)
+	}
+	if err := json.Unmarshal(statusResp.Data, data); err != nil {
+		return fmt.Errorf("failed to decode %s data: %w", endpoint, err)
+	}
+	return nil
+}
+
+// thanosFlags are flags only a Thanos querier is started with
+var thanosFlags = []string{"query.replica-label", "endpoint", "store"}
+
+// detectServer classifies the server from its build information and flags.
+// flags is nil when the server does not expose them (VictoriaMetrics, or
+// Mimir without the flags endpoint).
+func detectServer(build *buildInfo, flags map[string]string) *ServerInfo {
+	info := &ServerInfo{Type: ServerTypeUnknown}
+	if build == nil {
+		return info
+	}
+	info.Version = strings.TrimPrefix(build.Version, "v")
+
+	switch {
+	case strings.Contains(strings.ToLower(build.Application), "mimir"):
+		info.Type = ServerTypeMimir
+	case hasAnyFlag(flags, thanosFlags), flags == nil && strings.HasPrefix(info.Version, "0."):
+		info.Type = ServerTypeThanos
+	case flags != nil, build.Revision != "" || build.GoVersion != "":
+		info.Type = ServerTypePrometheus
+	case info.Version != "":
+		// VictoriaMetrics answers buildinfo with a bare Prometheus-compatible
+		// version and exposes neither flags nor a revision
+		info.Type = ServerTypeVictoriaMetrics
+	}
+
+	info.Features = serverFeatures(info.Type, info.Version, flags)
+	return info
+}
+
+// serverFeatures derives the supported query features per server type:
+// Prometheus gates native histograms and exemplars behind feature flags and
+// enables `@` by default since 2.33; Thanos and Mimir support them from the
+// listed versions, and Thanos downsamples unless its flags show auto
+// downsampling is off; VictoriaMetrics evaluates `@` but has neither native
+// histograms nor exemplars. Unknown servers get none. Smoothing is only
+// reported where the function's name is known for the version.
+func serverFeatures(serverType ServerType, version string, flags map[string]string) ServerFeatures {
+	switch serverType {
+	case ServerTypePrometheus:
+		enabled := enabledFeatures(flags)
+		features := ServerFeatures{
+			NativeHistograms: enabled["native-histograms"],
+			Exemplars:        enabled["exemplar-storage"],
+			AtModifier:       enabled["promql-at-modifier"] || versionAtLeast(version, 2, 33),
+		}
+		switch {
+		case !versionAtLeast(version, 3, 0):
+			features.Smoothing = "holt_winters"
+		case enabled["promql-experimental-functions"]:
+			features.Smoothing = "double_exponential_smoothing"
+		}
+		return features
+	case ServerTypeThanos:
+		return ServerFeatures{
+			NativeHistograms: versionAtLeast(version, 0, 32),
+			Exemplars:        versionAtLeast(version, 0, 22),
+			AtModifier:       versionAtLeast(version, 0, 23),
+			Downsampling:     flags == nil || flags["query.auto-downsampling"] == "true",
+		}
+	case ServerTypeMimir:
+		return ServerFeatures{
+			NativeHistograms: versionAtLeast(version, 2, 7),
+			Exemplars:        true,
+			AtModifier:       true,
+		}
+	case ServerTypeVictoriaMetrics:
+		return ServerFeatures{AtModifier: true, Smoothing: "holt_winters"}
+	default:
+		return ServerFeatures{}
+	}
+}
+
+// enabledFeatures parses the comma separated --enable-feature flag
+func enabledFeatures(flags map[string]string) map[string]bool {
+	enabled := map[string]bool{}
+	for _, feature := range strings.Split(flags["enable-feature"], ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			enabled[feature] = true
+		}
+	}
+	return enabled
+}
+
+// hasAnyFlag reports whether any of the names is a known flag
+func hasAnyFlag(flags map[string]string, names []string) bool {
+	for _, name := range names {
+		if _, ok := flags[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// versionAtLeast reports whether a major.minor[.patch] version is at least
+// major.minor; unparseable versions never are
+func versionAtLeast(version string, major, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	gotMajor, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	// pre-release minors such as "33-rc" count as their release
+	digits := strings.IndexFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+	if digits < 0 {
+		digits = len(parts[1])
+	}
+	gotMinor, err := strconv.Atoi(parts[1][:digits])
+	if err != nil {
+		return false
+	}
+	return gotMajor > major || gotMajor == major && gotMinor >= minor
+}
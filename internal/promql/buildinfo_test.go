@@ -0,0 +1,138 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestDetectServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    map[string]string
+		flags    map[string]string
+		wantType ServerType
+		want     ServerFeatures
+	}{
+		{
+			name:     "prometheus with feature flags",
+			build:    map[string]string{"version": "2.53.1", "revision": "abc", "goVersion": "go1.22"},
+			flags:    map[string]string{"enable-feature": "exemplar-storage,native-histograms", "storage.tsdb.retention.time": "15d"},
+			wantType: ServerTypePrometheus,
+			want:     ServerFeatures{NativeHistograms: true, Exemplars: true, AtModifier: true, Smoothing: "holt_winters"},
+		},
+		{
+			name:     "old prometheus",
+			build:    map[string]string{"version": "2.30.0", "revision": "abc"},
+			flags:    map[string]string{"enable-feature": ""},
+			wantType: ServerTypePrometheus,
+			want:     ServerFeatures{Smoothing: "holt_winters"},
+		},
+		{
+			name:     "prometheus 3 with experimental functions",
+			build:    map[string]string{"version": "3.1.0", "revision": "abc"},
+			flags:    map[string]string{"enable-feature": "promql-experimental-functions"},
+			wantType: ServerTypePrometheus,
+			want:     ServerFeatures{AtModifier: true, Smoothing: "double_exponential_smoothing"},
+		},
+		{
+			name:     "thanos querier",
+			build:    map[string]string{"version": "0.35.1", "revision": "abc"},
+			flags:    map[string]string{"query.replica-label": "replica", "endpoint": "sidecar:10901", "query.auto-downsampling": "true"},
+			wantType: ServerTypeThanos,
+			want:     ServerFeatures{NativeHistograms: true, Exemplars: true, AtModifier: true, Downsampling: true},
+		},
+		{
+			name:     "thanos querier without auto downsampling",
+			build:    map[string]string{"version": "0.35.1", "revision": "abc"},
+			flags:    map[string]string{"query.replica-label": "replica", "query.auto-downsampling": "false"},
+			wantType: ServerTypeThanos,
+			want:     ServerFeatures{NativeHistograms: true, Exemplars: true, AtModifier: true},
+		},
+		{
+			name:     "mimir",
+			build:    map[string]string{"application": "Grafana Mimir", "version": "2.12.0", "revision": "abc"},
+			wantType: ServerTypeMimir,
+			want:     ServerFeatures{NativeHistograms: true, Exemplars: true, AtModifier: true},
+		},
+		{
+			name:     "victoriametrics",
+			build:    map[string]string{"version": "2.24.0"},
+			wantType: ServerTypeVictoriaMetrics,
+			want:     ServerFeatures{AtModifier: true, Smoothing: "holt_winters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1/status/buildinfo":
+					_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": tt.build})
+				case "/api/v1/status/flags":
+					if tt.flags == nil {
+						http.NotFound(w, r)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": tt.flags})
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			svc, err := NewPromQLService(zap.NewNop(), &config.Config{}, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			info, err := svc.DetectServer(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if info.Type != tt.wantType || info.Version != tt.build["version"] {
+				t.Errorf("expected %s %s, got %s %s", tt.wantType, tt.build["version"], info.Type, info.Version)
+			}
+			if info.Features != tt.want {
+				t.Errorf("expected features %+v, got %+v", tt.want, info.Features)
+			}
+		})
+	}
+}
+
+func TestDetectServerWithoutBuildInfo(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	svc, err := NewPromQLService(zap.NewNop(), &config.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := svc.DetectServer(context.Background(), server.URL); err == nil {
+		t.Error("expected an error when buildinfo is unavailable")
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"2.33.0", true},
+		{"2.33-rc.0", true},
+		{"3.0.1", true},
+		{"2.32.9", false},
+		{"1.99.0", false},
+		{"dev", false},
+	}
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, 2, 33); got != tt.want {
+			t.Errorf("versionAtLeast(%q, 2, 33) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
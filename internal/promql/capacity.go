@@ -0,0 +1,209 @@
+package promql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of saturation resources a capacity dashboard forecasts
+const (
+	ResourceDisk           = "disk"
+	ResourceMemory         = "memory"
+	ResourceConnectionPool = "connection_pool"
+)
+
+// capacitySubqueryStep is the resolution of the subqueries the forecasts
+// run over the aggregated usage
+const capacitySubqueryStep = "5m"
+
+// ignoredFilesystems are the pseudo and read-only filesystems node_exporter
+// reports, which never fill up
+const ignoredFilesystems = `fstype!~"tmpfs|ramfs|overlay|squashfs|nsfs|fuse.lxcfs"`
+
+// saturationDefinition describes how a saturating resource is recognised
+// and how its usage and capacity are computed
+type saturationDefinition struct {
+	name string
+	kind string
+	// metrics must all be present for the resource to be detected
+	metrics []string
+	// used and capacity are selectors or expressions aggregated by groupBy
+	used, capacity string
+	groupBy        []string
+	unit           string
+}
+
+// knownSaturations lists the resources capacity dashboards forecast
+var knownSaturations = []saturationDefinition{
+	{
+		name: "Filesystem", kind: ResourceDisk,
+		metrics:  []string{"node_filesystem_avail_bytes", "node_filesystem_size_bytes"},
+		used:     fmt.Sprintf("node_filesystem_size_bytes{%[1]s} - node_filesystem_avail_bytes{%[1]s}", ignoredFilesystems),
+		capacity: fmt.Sprintf("node_filesystem_size_bytes{%s}", ignoredFilesystems),
+		groupBy:  []string{"instance", "mountpoint"},
+		unit:     "bytes",
+	},
+	{
+		name: "Windows volume", kind: ResourceDisk,
+		metrics:  []string{"windows_logical_disk_free_bytes", "windows_logical_disk_size_bytes"},
+		used:     fmt.Sprintf("windows_logical_disk_size_bytes{%[1]s} - windows_logical_disk_free_bytes{%[1]s}", ignoredWindowsVolumes),
+		capacity: fmt.Sprintf("windows_logical_disk_size_bytes{%s}", ignoredWindowsVolumes),
+		groupBy:  []string{"instance", "volume"},
+		unit:     "bytes",
+	},
+	{
+		name: "Persistent volume", kind: ResourceDisk,
+		metrics:  []string{"kubelet_volume_stats_used_bytes", "kubelet_volume_stats_capacity_bytes"},
+		used:     "kubelet_volume_stats_used_bytes",
+		capacity: "kubelet_volume_stats_capacity_bytes",
+		groupBy:  []string{"namespace", "persistentvolumeclaim"},
+		unit:     "bytes",
+	},
+	{
+		name: "Host memory", kind: ResourceMemory,
+		metrics:  []string{"node_memory_MemAvailable_bytes", "node_memory_MemTotal_bytes"},
+		used:     "node_memory_MemTotal_bytes - node_memory_MemAvailable_bytes",
+		capacity: "node_memory_MemTotal_bytes",
+		groupBy:  []string{"instance"},
+		unit:     "bytes",
+	},
+	{
+		name: "Windows host memory", kind: ResourceMemory,
+		metrics:  []string{"windows_memory_physical_free_bytes", "windows_memory_physical_total_bytes"},
+		used:     "windows_memory_physical_total_bytes - windows_memory_physical_free_bytes",
+		capacity: "windows_memory_physical_total_bytes",
+		groupBy:  []string{"instance"},
+		unit:     "bytes",
+	},
+	{
+		// containers without a memory limit report a limit of 0
+		name: "Container memory", kind: ResourceMemory,
+		metrics:  []string{"container_memory_working_set_bytes", "container_spec_memory_limit_bytes"},
+		used:     `container_memory_working_set_bytes{container!=""}`,
+		capacity: `container_spec_memory_limit_bytes{container!=""} > 0`,
+		groupBy:  []string{"namespace", "pod", "container"},
+		unit:     "bytes",
+	},
+	{
+		name: "Database connection pool", kind: ResourceConnectionPool,
+		metrics:  []string{"db_client_connection_count", "db_client_connection_max"},
+		used:     `db_client_connection_count{db_client_connection_state="used"}`,
+		capacity: "db_client_connection_max",
+		groupBy:  []string{"db_client_connection_pool_name"},
+		unit:     "short",
+	},
+	{
+		name: "Database connection pool", kind: ResourceConnectionPool,
+		metrics:  []string{"db_client_connections_usage", "db_client_connections_max"},
+		used:     `db_client_connections_usage{state="used"}`,
+		capacity: "db_client_connections_max",
+		groupBy:  []string{"pool_name"},
+		unit:     "short",
+	},
+	{
+		name: "HikariCP connection pool", kind: ResourceConnectionPool,
+		metrics:  []string{"hikaricp_connections_active", "hikaricp_connections_max"},
+		used:     "hikaricp_connections_active",
+		capacity: "hikaricp_connections_max",
+		groupBy:  []string{"pool"},
+		unit:     "short",
+	},
+	{
+		name: "database/sql connection pool", kind: ResourceConnectionPool,
+		metrics:  []string{"go_sql_stats_connections_in_use", "go_sql_stats_connections_max_open"},
+		used:     "go_sql_stats_connections_in_use",
+		capacity: "go_sql_stats_connections_max_open > 0",
+		groupBy:  []string{"db_name"},
+		unit:     "short",
+	},
+}
+
+// SaturationResource is a finite resource found among the metrics, whose
+// usage can be forecast against its capacity
+type SaturationResource struct {
+	Name string `json:"name"`
+	// Kind is disk, memory or connection_pool
+	Kind    string   `json:"kind"`
+	Metrics []string `json:"metrics"`
+	// Used and Capacity are the usage and capacity aggregated by GroupBy
+	Used     string   `json:"used"`
+	Capacity string   `json:"capacity"`
+	GroupBy  []string `json:"group_by"`
+	Unit     string   `json:"unit"`
+}
+
+// DetectSaturationResources returns the resources whose usage and capacity
+// metrics are all present, in catalog order
+func DetectSaturationResources(metricNames []string) []SaturationResource {
+	present := make(map[string]bool, len(metricNames))
+	for _, name := range metricNames {
+		present[name] = true
+	}
+
+	var resources []SaturationResource
+	for _, def := range knownSaturations {
+		complete := true
+		for _, metric := range def.metrics {
+			complete = complete && present[metric]
+		}
+		if !complete {
+			continue
+		}
+		by := strings.Join(def.groupBy, ", ")
+		resources = append(resources, SaturationResource{
+			Name:     def.name,
+			Kind:     def.kind,
+			Metrics:  def.metrics,
+			Used:     fmt.Sprintf("sum by (%s) (%s)", by, def.used),
+			Capacity: fmt.Sprintf("sum by (%s) (%s)", by, def.capacity),
+			GroupBy:  def.groupBy,
+			Unit:     def.unit,
+		})
+	}
+	return resources
+}
+
+// CapacityQueries are the forecast queries of a saturation resource
+type CapacityQueries struct {
+	// Utilization is the used fraction of the capacity
+	Utilization string `json:"utilization"`
+	// Forecast is the utilization predicted at the horizon from the trend
+	// over the forecast range
+	Forecast string `json:"forecast"`
+	// DaysUntilFull is the time left until the capacity is exhausted at the
+	// current growth, only for the resources that grow
+	DaysUntilFull string `json:"days_until_full"`
+	// Exhaustion selects the resources predicted to run out within the
+	// horizon, for alert rules
+	Exhaustion string `json:"exhaustion"`
+}
+
+// Queries builds the forecast queries of the resource from the trend over
+// forecastRange (e.g. "6h"), looking horizonSeconds ahead
+func (r SaturationResource) Queries(forecastRange string, horizonSeconds int) CapacityQueries {
+	utilization := fmt.Sprintf("%s / %s", r.Used, r.Capacity)
+	free := fmt.Sprintf("%s - %s", r.Capacity, r.Used)
+	return CapacityQueries{
+		Utilization: utilization,
+		Forecast:    fmt.Sprintf("predict_linear(%s, %d)", rangeOf(utilization, forecastRange, capacitySubqueryStep), horizonSeconds),
+		DaysUntilFull: fmt.Sprintf("(%s) / (deriv(%s) > 0) / 86400",
+			free, rangeOf(r.Used, forecastRange, capacitySubqueryStep)),
+		Exhaustion: fmt.Sprintf("predict_linear(%s, %d) < 0", rangeOf(free, forecastRange, capacitySubqueryStep), horizonSeconds),
+	}
+}
+
+// UtilizationThresholds colour a resource's utilization: orange from 80%,
+// red from 95%
+func UtilizationThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(0.8), Color: "orange"},
+		{Value: floatPtr(0.95), Color: "red"},
+	}
+}
+
+// DaysUntilFullThresholds colour the days left until a resource is full:
+// red within a week, orange within a month
+func DaysUntilFullThresholds() []Threshold {
+	return countdownThresholds()
+}
@@ -0,0 +1,61 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestDetectSaturationResources(t *testing.T) {
+	resources := DetectSaturationResources([]string{
+		"node_filesystem_avail_bytes", "node_filesystem_size_bytes",
+		"node_memory_MemAvailable_bytes",
+		"hikaricp_connections_active", "hikaricp_connections_max",
+		"up",
+	})
+
+	if len(resources) != 2 {
+		t.Fatalf("Expected the filesystem and HikariCP pool, got %+v", resources)
+	}
+	if resources[0].Kind != ResourceDisk || resources[1].Kind != ResourceConnectionPool {
+		t.Errorf("Expected catalog order disk, connection_pool, got %s, %s", resources[0].Kind, resources[1].Kind)
+	}
+	if want := "sum by (pool) (hikaricp_connections_max)"; resources[1].Capacity != want {
+		t.Errorf("Expected capacity %s, got %s", want, resources[1].Capacity)
+	}
+
+	windows := DetectSaturationResources([]string{
+		"windows_logical_disk_free_bytes", "windows_logical_disk_size_bytes",
+		"windows_memory_physical_free_bytes", "windows_memory_physical_total_bytes",
+	})
+	if len(windows) != 2 || windows[0].Name != "Windows volume" || windows[1].Name != "Windows host memory" {
+		t.Errorf("Expected the Windows volume and host memory, got %+v", windows)
+	}
+
+	if found := DetectSaturationResources([]string{"node_memory_MemTotal_bytes"}); len(found) != 0 {
+		t.Errorf("Expected no resource without its usage metric, got %+v", found)
+	}
+}
+
+func TestSaturationResourceQueries(t *testing.T) {
+	resource := SaturationResource{
+		Used:     "sum by (pool) (hikaricp_connections_active)",
+		Capacity: "sum by (pool) (hikaricp_connections_max)",
+		GroupBy:  []string{"pool"},
+	}
+	queries := resource.Queries("6h", 4*3600)
+
+	tests := map[string]struct{ got, want string }{
+		"utilization": {queries.Utilization,
+			"sum by (pool) (hikaricp_connections_active) / sum by (pool) (hikaricp_connections_max)"},
+		"forecast": {queries.Forecast,
+			"predict_linear((sum by (pool) (hikaricp_connections_active) / sum by (pool) (hikaricp_connections_max))[6h:5m], 14400)"},
+		"days until full": {queries.DaysUntilFull,
+			"(sum by (pool) (hikaricp_connections_max) - sum by (pool) (hikaricp_connections_active)) / (deriv((sum by (pool) (hikaricp_connections_active))[6h:5m]) > 0) / 86400"},
+		"exhaustion": {queries.Exhaustion,
+			"predict_linear((sum by (pool) (hikaricp_connections_max) - sum by (pool) (hikaricp_connections_active))[6h:5m], 14400) < 0"},
+	}
+	for name, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", name, tt.want, tt.got)
+		}
+	}
+}
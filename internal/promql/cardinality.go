@@ -0,0 +1,106 @@
+package promql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Scrape metrics Prometheus records for every target, which attribute series
+// and samples to the labels of the targets that send them
+const (
+	scrapeSamplesScraped         = "scrape_samples_scraped"
+	scrapeSamplesPostRelabeling  = "scrape_samples_post_metric_relabeling"
+	scrapeSeriesAdded            = "scrape_series_added"
+	samplesPerSecondWindow       = "5m"
+	samplesPerSecondWindowLength = 300
+)
+
+// SeriesCount is a name with its number of series, or of values for the
+// label names of a TSDB report
+type SeriesCount struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// TSDBStats is the cardinality report of the head block, from
+// /api/v1/status/tsdb
+type TSDBStats struct {
+	// HeadSeries is the number of active series
+	HeadSeries int `json:"head_series"`
+	// TopMetrics are the metric names with the most series
+	TopMetrics []SeriesCount `json:"top_metrics,omitempty"`
+	// TopLabels are the label names with the most distinct values
+	TopLabels []SeriesCount `json:"top_labels,omitempty"`
+	// TopLabelPairs are the label pairs, such as "job=node", with the most series
+	TopLabelPairs []SeriesCount `json:"top_label_pairs,omitempty"`
+}
+
+// tsdbStatus is the data of a /api/v1/status/tsdb response
+type tsdbStatus struct {
+	HeadStats struct {
+		NumSeries int `json:"numSeries"`
+	} `json:"headStats"`
+	SeriesCountByMetricName     []SeriesCount `json:"seriesCountByMetricName"`
+	LabelValueCountByLabelName  []SeriesCount `json:"labelValueCountByLabelName"`
+	SeriesCountByLabelValuePair []SeriesCount `json:"seriesCountByLabelValuePair"`
+}
+
+// getTSDBStats fetches the cardinality report of the head block. Only
+// Prometheus serves it; Mimir and VictoriaMetrics answer with an error.
+func (c *prometheusClient) getTSDBStats(ctx context.Context) (*TSDBStats, error) {
+	var status tsdbStatus
+	if err := c.getStatus(ctx, "tsdb", &status); err != nil {
+		return nil, err
+	}
+	return &TSDBStats{
+		HeadSeries:    status.HeadStats.NumSeries,
+		TopMetrics:    status.SeriesCountByMetricName,
+		TopLabels:     status.LabelValueCountByLabelName,
+		TopLabelPairs: status.SeriesCountByLabelValuePair,
+	}, nil
+}
+
+// ScrapeSeriesMetric returns the scrape metric counting the series a target
+// stores: the count after metric relabeling when it is present, since
+// relabeling drops series before they are billed; empty when the scrape
+// metrics are missing
+func ScrapeSeriesMetric(metricNames []string) string {
+	switch {
+	case slices.Contains(metricNames, scrapeSamplesPostRelabeling):
+		return scrapeSamplesPostRelabeling
+	case slices.Contains(metricNames, scrapeSamplesScraped):
+		return scrapeSamplesScraped
+	}
+	return ""
+}
+
+// CostQueries attribute the series and samples ingested to the values of a
+// target label such as job or namespace
+type CostQueries struct {
+	// ActiveSeries is the number of series the targets send
+	ActiveSeries string `json:"active_series"`
+	// SamplesPerSecond is the ingestion rate, what samples-based plans bill
+	SamplesPerSecond string `json:"samples_per_second"`
+	// SeriesGrowth is the change in active series over the last day
+	SeriesGrowth string `json:"series_growth"`
+	// SeriesChurn is the number of new series created per hour, which
+	// count towards active series until they go stale
+	SeriesChurn string `json:"series_churn"`
+}
+
+// AttributionQueries builds the cost queries of seriesMetric (see
+// ScrapeSeriesMetric) aggregated by label; an empty label totals them
+func AttributionQueries(seriesMetric, label string) CostQueries {
+	sum := "sum"
+	if label != "" {
+		sum = fmt.Sprintf("sum by (%s)", label)
+	}
+	return CostQueries{
+		ActiveSeries: fmt.Sprintf("%s (%s)", sum, seriesMetric),
+		SamplesPerSecond: fmt.Sprintf("%s (sum_over_time(%s[%s])) / %d",
+			sum, seriesMetric, samplesPerSecondWindow, samplesPerSecondWindowLength),
+		SeriesGrowth: fmt.Sprintf("%[1]s (%[2]s) - %[1]s (%[2]s offset 1d)", sum, seriesMetric),
+		SeriesChurn:  fmt.Sprintf("%s (sum_over_time(%s[1h]))", sum, scrapeSeriesAdded),
+	}
+}
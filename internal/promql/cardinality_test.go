@@ -0,0 +1,73 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrometheusClientGetTSDBStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/status/tsdb" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data": map[string]any{
+				"headStats":                   map[string]any{"numSeries": 5120, "chunkCount": 9000},
+				"seriesCountByMetricName":     []map[string]any{{"name": "http_request_duration_seconds_bucket", "value": 2400}},
+				"labelValueCountByLabelName":  []map[string]any{{"name": "pod", "value": 48}},
+				"seriesCountByLabelValuePair": []map[string]any{{"name": "job=api", "value": 3100}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	stats, err := newPrometheusClient(server.URL, nil).getTSDBStats(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.HeadSeries != 5120 {
+		t.Errorf("Expected 5120 head series, got %d", stats.HeadSeries)
+	}
+	if len(stats.TopMetrics) != 1 || stats.TopMetrics[0].Value != 2400 ||
+		len(stats.TopLabels) != 1 || stats.TopLabels[0].Name != "pod" ||
+		len(stats.TopLabelPairs) != 1 || stats.TopLabelPairs[0].Name != "job=api" {
+		t.Errorf("Unexpected tsdb stats %+v", stats)
+	}
+}
+
+func TestScrapeSeriesMetric(t *testing.T) {
+	tests := []struct {
+		metrics []string
+		want    string
+	}{
+		{[]string{"up", "scrape_samples_scraped", "scrape_samples_post_metric_relabeling"}, "scrape_samples_post_metric_relabeling"},
+		{[]string{"up", "scrape_samples_scraped"}, "scrape_samples_scraped"},
+		{[]string{"http_requests_total"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ScrapeSeriesMetric(tt.metrics); got != tt.want {
+			t.Errorf("ScrapeSeriesMetric(%v) = %q, want %q", tt.metrics, got, tt.want)
+		}
+	}
+}
+
+func TestAttributionQueries(t *testing.T) {
+	queries := AttributionQueries("scrape_samples_scraped", "namespace")
+	want := CostQueries{
+		ActiveSeries:     "sum by (namespace) (scrape_samples_scraped)",
+		SamplesPerSecond: "sum by (namespace) (sum_over_time(scrape_samples_scraped[5m])) / 300",
+		SeriesGrowth:     "sum by (namespace) (scrape_samples_scraped) - sum by (namespace) (scrape_samples_scraped offset 1d)",
+		SeriesChurn:      "sum by (namespace) (sum_over_time(scrape_series_added[1h]))",
+	}
+	if queries != want {
+		t.Errorf("Expected %+v, got %+v", want, queries)
+	}
+
+	if total := AttributionQueries("scrape_samples_scraped", ""); total.ActiveSeries != "sum (scrape_samples_scraped)" {
+		t.Errorf("Expected a total without grouping, got %s", total.ActiveSeries)
+	}
+}
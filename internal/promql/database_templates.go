@@ -0,0 +1,375 @@
+package promql
+
+import "fmt"
+
+// hitRatio is the share of lookups served from a cache, from the rates of
+// its hit and miss counters summed by the given labels
+func hitRatio(hits, misses, by string) string {
+	hitRate := fmt.Sprintf("sum by (%s) (rate(%s[5m]))", by, hits)
+	return fmt.Sprintf("%s / (%s + sum by (%s) (rate(%s[5m])))", hitRate, hitRate, by, misses)
+}
+
+// higherIsBetter returns thresholds that are red below orange, orange
+// below green and green above, as for hit ratios
+func higherIsBetter(orange, green float64) []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "red"},
+		{Value: floatPtr(orange), Color: "orange"},
+		{Value: floatPtr(green), Color: "green"},
+	}
+}
+
+// lowerIsBetter returns thresholds that are green below orange, orange
+// below red and red above, as for replication lag
+func lowerIsBetter(orange, red float64) []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(orange), Color: "orange"},
+		{Value: floatPtr(red), Color: "red"},
+	}
+}
+
+// postgresTemplate covers postgres_exporter. An OLTP database should serve
+// nearly every block from shared buffers, so its cache hit ratio turns
+// orange below 99%.
+var postgresTemplate = DashboardTemplate{
+	Name:        "postgresql",
+	Title:       "PostgreSQL",
+	Description: "Cache hit ratio, rows fetched per row returned, replication lag, connection usage and transaction rates from postgres_exporter",
+	Signature:   []string{"pg_up", "pg_stat_database_blks_hit"},
+	Breakdown:   []string{"datname"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Cache hit ratio",
+			Type:        "stat",
+			Description: "Share of blocks read from shared buffers rather than disk; below 99% the working set no longer fits in memory.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "min(" + hitRatio("pg_stat_database_blks_hit", "pg_stat_database_blks_read", "instance") + ")"}},
+			Thresholds:  higherIsBetter(0.95, 0.99),
+			requires:    []string{"pg_stat_database_blks_hit", "pg_stat_database_blks_read"},
+		},
+		{
+			Title:       "Replication lag",
+			Type:        "stat",
+			Description: "Longest replay delay of any replica behind its primary.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(pg_replication_lag_seconds)"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			requires:    []string{"pg_replication_lag_seconds"},
+		},
+		{
+			Title:       "Replication lag",
+			Type:        "stat",
+			Description: "Longest replay delay of any replica behind its primary.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(pg_replication_lag)"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			requires:    []string{"pg_replication_lag"},
+		},
+		{
+			Title:       "Connection usage",
+			Type:        "stat",
+			Description: "Highest share of max_connections in use on any server.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(sum by (instance) (pg_stat_activity_count) / max by (instance) (pg_settings_max_connections))"}},
+			Thresholds:  lowerIsBetter(0.8, 0.9),
+			requires:    []string{"pg_stat_activity_count", "pg_settings_max_connections"},
+		},
+		{
+			Title:       "Cache hit ratio by <by>",
+			Type:        "timeseries",
+			Description: "Share of blocks read from shared buffers rather than disk per database.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: hitRatio("pg_stat_database_blks_hit", "pg_stat_database_blks_read", "<by>"), Legend: "{{<by>}}"}},
+			Max:         floatPtr(1),
+			Group:       "Efficiency",
+			requires:    []string{"pg_stat_database_blks_hit", "pg_stat_database_blks_read"},
+		},
+		{
+			Title:       "Rows fetched per row returned by <by>",
+			Type:        "timeseries",
+			Description: "Rows fetched by index scans per row scanned; a low ratio means queries read many rows to return few, usually sequential scans missing an index.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(pg_stat_database_tup_fetched[5m])) / sum by (<by>) (rate(pg_stat_database_tup_returned[5m]))", Legend: "{{<by>}}"}},
+			Thresholds:  higherIsBetter(0.1, 0.5),
+			Group:       "Efficiency",
+			requires:    []string{"pg_stat_database_tup_fetched", "pg_stat_database_tup_returned"},
+		},
+		{
+			Title:       "Rollback ratio by <by>",
+			Type:        "timeseries",
+			Description: "Share of transactions rolled back per database.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(pg_stat_database_xact_rollback[5m])) / (sum by (<by>) (rate(pg_stat_database_xact_commit[5m])) + sum by (<by>) (rate(pg_stat_database_xact_rollback[5m])))", Legend: "{{<by>}}"}},
+			Thresholds:  lowerIsBetter(0.05, 0.1),
+			Group:       "Efficiency",
+			requires:    []string{"pg_stat_database_xact_commit", "pg_stat_database_xact_rollback"},
+		},
+		{
+			Title:       "Transactions by <by>",
+			Type:        "timeseries",
+			Description: "Committed transactions per second per database.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(pg_stat_database_xact_commit[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Activity",
+			requires:    []string{"pg_stat_database_xact_commit"},
+		},
+		{
+			Title:       "Connections by state",
+			Type:        "timeseries",
+			Description: "Backends per state; many idle in transaction connections hold locks and block vacuum.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (state) (pg_stat_activity_count)", Legend: "{{state}}"}},
+			Group:       "Activity",
+			requires:    []string{"pg_stat_activity_count"},
+		},
+		{
+			Title:       "Deadlocks by <by>",
+			Type:        "timeseries",
+			Description: "Deadlocks detected per second per database.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(pg_stat_database_deadlocks[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Activity",
+			requires:    []string{"pg_stat_database_deadlocks"},
+		},
+		{
+			Title:       "Replication lag by replica",
+			Type:        "timeseries",
+			Description: "Replay delay of each replica behind its primary.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (instance) (pg_replication_lag_seconds)", Legend: "{{instance}}"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			Group:       "Replication",
+			requires:    []string{"pg_replication_lag_seconds"},
+		},
+		{
+			Title:       "Replication lag by replica",
+			Type:        "timeseries",
+			Description: "Replay delay of each replica behind its primary.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (instance) (pg_replication_lag)", Legend: "{{instance}}"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			Group:       "Replication",
+			requires:    []string{"pg_replication_lag"},
+		},
+	},
+}
+
+// mysqlTemplate covers mysqld_exporter. InnoDB reads ahead into the buffer
+// pool, so like PostgreSQL its hit ratio turns orange below 99%; replicas
+// report their lag as seconds behind the source, or behind the master
+// before MySQL 8.0.22.
+var mysqlTemplate = DashboardTemplate{
+	Name:        "mysql",
+	Title:       "MySQL",
+	Description: "InnoDB buffer pool hit ratio, full table scan ratio, replication lag, connection usage and query rates from mysqld_exporter",
+	Signature:   []string{"mysql_up", "mysql_global_status_queries"},
+	Breakdown:   []string{"instance"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Buffer pool hit ratio",
+			Type:        "stat",
+			Description: "Share of InnoDB page reads served from the buffer pool rather than disk.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "min(" + mysqlBufferPoolHitRatio("instance") + ")"}},
+			Thresholds:  higherIsBetter(0.95, 0.99),
+			requires:    []string{"mysql_global_status_innodb_buffer_pool_reads", "mysql_global_status_innodb_buffer_pool_read_requests"},
+		},
+		{
+			Title:       "Replication lag",
+			Type:        "stat",
+			Description: "Longest delay of any replica behind its source.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(mysql_slave_status_seconds_behind_source)"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			requires:    []string{"mysql_slave_status_seconds_behind_source"},
+		},
+		{
+			Title:       "Replication lag",
+			Type:        "stat",
+			Description: "Longest delay of any replica behind its source.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(mysql_slave_status_seconds_behind_master)"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			requires:    []string{"mysql_slave_status_seconds_behind_master"},
+		},
+		{
+			Title:       "Connection usage",
+			Type:        "stat",
+			Description: "Highest share of max_connections in use on any server.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(max by (instance) (mysql_global_status_threads_connected) / max by (instance) (mysql_global_variables_max_connections))"}},
+			Thresholds:  lowerIsBetter(0.8, 0.9),
+			requires:    []string{"mysql_global_status_threads_connected", "mysql_global_variables_max_connections"},
+		},
+		{
+			Title:       "Buffer pool hit ratio by <by>",
+			Type:        "timeseries",
+			Description: "Share of InnoDB page reads served from the buffer pool per server.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: mysqlBufferPoolHitRatio("<by>"), Legend: "{{<by>}}"}},
+			Max:         floatPtr(1),
+			Group:       "Efficiency",
+			requires:    []string{"mysql_global_status_innodb_buffer_pool_reads", "mysql_global_status_innodb_buffer_pool_read_requests"},
+		},
+		{
+			Title:       "Full table scan ratio by <by>",
+			Type:        "timeseries",
+			Description: "Share of row reads that are sequential reads of a table's next row; a high ratio means queries scan tables instead of using an index.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: `sum by (<by>) (rate(mysql_global_status_handlers_total{handler="read_rnd_next"}[5m])) / sum by (<by>) (rate(mysql_global_status_handlers_total{handler=~"read_.*"}[5m]))`, Legend: "{{<by>}}"}},
+			Thresholds:  lowerIsBetter(0.5, 0.8),
+			Group:       "Efficiency",
+			requires:    []string{"mysql_global_status_handlers_total"},
+		},
+		{
+			Title:       "Queries by <by>",
+			Type:        "timeseries",
+			Description: "Statements executed per second per server, with the slow ones.",
+			Unit:        "ops",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (rate(mysql_global_status_queries[5m]))", Legend: "{{<by>}}"},
+				{Expr: "sum by (<by>) (rate(mysql_global_status_slow_queries[5m]))", Legend: "{{<by>}} slow"},
+			},
+			Group:    "Activity",
+			requires: []string{"mysql_global_status_queries", "mysql_global_status_slow_queries"},
+		},
+		{
+			Title:       "Connections by <by>",
+			Type:        "timeseries",
+			Description: "Connected and running threads per server.",
+			Unit:        "short",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (mysql_global_status_threads_connected)", Legend: "{{<by>}} connected"},
+				{Expr: "sum by (<by>) (mysql_global_status_threads_running)", Legend: "{{<by>}} running"},
+			},
+			Group:    "Activity",
+			requires: []string{"mysql_global_status_threads_connected", "mysql_global_status_threads_running"},
+		},
+		{
+			Title:       "Replication lag by <by>",
+			Type:        "timeseries",
+			Description: "Delay of each replica behind its source.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (<by>) (mysql_slave_status_seconds_behind_source)", Legend: "{{<by>}}"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			Group:       "Replication",
+			requires:    []string{"mysql_slave_status_seconds_behind_source"},
+		},
+		{
+			Title:       "Replication lag by <by>",
+			Type:        "timeseries",
+			Description: "Delay of each replica behind its source.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (<by>) (mysql_slave_status_seconds_behind_master)", Legend: "{{<by>}}"}},
+			Thresholds:  lowerIsBetter(30, 300),
+			Group:       "Replication",
+			requires:    []string{"mysql_slave_status_seconds_behind_master"},
+		},
+	},
+}
+
+// mysqlBufferPoolHitRatio is the share of InnoDB read requests that did not
+// have to read from disk
+func mysqlBufferPoolHitRatio(by string) string {
+	return fmt.Sprintf("1 - sum by (%s) (rate(mysql_global_status_innodb_buffer_pool_reads[5m])) / sum by (%s) (rate(mysql_global_status_innodb_buffer_pool_read_requests[5m]))", by, by)
+}
+
+// redisTemplate covers redis_exporter. A cache misses by design, so its hit
+// ratio turns orange below 90% and red below 80%; replicas stream from
+// memory and normally lag by about a second, so lag turns orange at 5s.
+var redisTemplate = DashboardTemplate{
+	Name:        "redis",
+	Title:       "Redis",
+	Description: "Keyspace hit ratio, memory usage, evictions, replication lag and command rates from redis_exporter",
+	Signature:   []string{"redis_up", "redis_keyspace_hits_total"},
+	Breakdown:   []string{"instance"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Keyspace hit ratio",
+			Type:        "stat",
+			Description: "Share of key lookups that found the key across all servers.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "min(" + hitRatio("redis_keyspace_hits_total", "redis_keyspace_misses_total", "instance") + ")"}},
+			Thresholds:  higherIsBetter(0.8, 0.9),
+			requires:    []string{"redis_keyspace_hits_total", "redis_keyspace_misses_total"},
+		},
+		{
+			Title:       "Memory usage",
+			Type:        "stat",
+			Description: "Highest share of maxmemory in use on any server; at the limit Redis evicts keys or rejects writes.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(redis_memory_used_bytes / (redis_memory_max_bytes > 0))"}},
+			Thresholds:  lowerIsBetter(0.8, 0.9),
+			requires:    []string{"redis_memory_used_bytes", "redis_memory_max_bytes"},
+		},
+		{
+			Title:       "Replication lag",
+			Type:        "stat",
+			Description: "Longest time since any replica acknowledged the replication stream.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(redis_connected_slave_lag_seconds)"}},
+			Thresholds:  lowerIsBetter(5, 30),
+			requires:    []string{"redis_connected_slave_lag_seconds"},
+		},
+		{
+			Title:       "Keyspace hit ratio by <by>",
+			Type:        "timeseries",
+			Description: "Share of key lookups that found the key per server.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: hitRatio("redis_keyspace_hits_total", "redis_keyspace_misses_total", "<by>"), Legend: "{{<by>}}"}},
+			Max:         floatPtr(1),
+			Group:       "Efficiency",
+			requires:    []string{"redis_keyspace_hits_total", "redis_keyspace_misses_total"},
+		},
+		{
+			Title:       "Evictions by <by>",
+			Type:        "timeseries",
+			Description: "Keys evicted per second to stay under maxmemory; evictions alongside a falling hit ratio mean the cache is too small.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(redis_evicted_keys_total[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Efficiency",
+			requires:    []string{"redis_evicted_keys_total"},
+		},
+		{
+			Title:       "Memory by <by>",
+			Type:        "timeseries",
+			Description: "Memory used per server against maxmemory.",
+			Unit:        "bytes",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (redis_memory_used_bytes)", Legend: "{{<by>}} used"},
+				{Expr: "sum by (<by>) (redis_memory_max_bytes > 0)", Legend: "{{<by>}} max"},
+			},
+			Group:    "Memory",
+			requires: []string{"redis_memory_used_bytes", "redis_memory_max_bytes"},
+		},
+		{
+			Title:       "Commands by <by>",
+			Type:        "timeseries",
+			Description: "Commands processed per second per server.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(redis_commands_processed_total[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Activity",
+			requires:    []string{"redis_commands_processed_total"},
+		},
+		{
+			Title:       "Connected clients by <by>",
+			Type:        "timeseries",
+			Description: "Client connections per server.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (redis_connected_clients)", Legend: "{{<by>}}"}},
+			Group:       "Activity",
+			requires:    []string{"redis_connected_clients"},
+		},
+		{
+			Title:       "Replication lag by replica",
+			Type:        "timeseries",
+			Description: "Time since each replica acknowledged the replication stream.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (<by>, slave_ip, slave_port) (redis_connected_slave_lag_seconds)", Legend: "{{<by>}} {{slave_ip}}:{{slave_port}}"}},
+			Thresholds:  lowerIsBetter(5, 30),
+			Group:       "Replication",
+			requires:    []string{"redis_connected_slave_lag_seconds"},
+		},
+	},
+}
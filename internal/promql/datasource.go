@@ -0,0 +1,201 @@
+package promql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// grafanaDatasource routes queries through a Grafana Prometheus datasource
+// for setups where Prometheus is only reachable via Grafana
+type grafanaDatasource struct {
+	grafanaURL string
+	uid        string
+}
+
+// bearerTransport adds a bearer token to every request
+type bearerTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(authorized)
+}
+
+// newDatasourceClient creates a Prometheus client that reaches Prometheus
+// through Grafana: metadata, label and series lookups go through the
+// datasource resource API and queries through /api/ds/query, authenticated
+// with the Grafana API key
+func newDatasourceClient(grafanaURL, uid, apiKey string, transport http.RoundTripper) *prometheusClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if apiKey != "" {
+		transport = &bearerTransport{token: apiKey, next: transport}
+	}
+
+	grafanaURL = strings.TrimRight(grafanaURL, "/")
+	client := newPrometheusClient(fmt.Sprintf("%s/api/datasources/uid/%s/resources", grafanaURL, url.PathEscape(uid)), transport)
+	client.datasource = &grafanaDatasource{grafanaURL: grafanaURL, uid: uid}
+	return client
+}
+
+// dsQueryFrame is a data frame of an /api/ds/query response
+type dsQueryFrame struct {
+	Schema struct {
+		Fields []struct {
+			Name   string            `json:"name"`
+			Type   string            `json:"type"`
+			Labels map[string]string `json:"labels"`
+		} `json:"fields"`
+	} `json:"schema"`
+	Data struct {
+		Values [][]any `json:"values"`
+	} `json:"data"`
+}
+
+// dsQuery runs a single PromQL query through Grafana's /api/ds/query and
+// converts the returned data frames into series
+func (c *prometheusClient) dsQuery(ctx context.Context, query string, start, end time.Time, step time.Duration, instant bool) ([]Series, error) {
+	target := map[string]any{
+		"refId":      "A",
+		"datasource": map[string]string{"type": "prometheus", "uid": c.datasource.uid},
+		"expr":       query,
+		"instant":    instant,
+		"range":      !instant,
+	}
+	if !instant && step > 0 {
+		target["intervalMs"] = step.Milliseconds()
+		target["interval"] = step.String()
+		target["maxDataPoints"] = int64(end.Sub(start)/step) + 1
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"queries": []any{target},
+		"from":    strconv.FormatInt(start.UnixMilli(), 10),
+		"to":      strconv.FormatInt(end.UnixMilli(), 10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.datasource.grafanaURL+"/api/ds/query", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var queryResp struct {
+		Message string `json:"message"`
+		Results map[string]struct {
+			Error  string         `json:"error"`
+			Frames []dsQueryFrame `json:"frames"`
+		} `json:"results"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&queryResp); err != nil {
+		return nil, fmt.Errorf("grafana returned status %d: failed to decode query response: %w", resp.StatusCode, err)
+	}
+
+	result, ok := queryResp.Results["A"]
+	if result.Error != "" {
+		return nil, fmt.Errorf("datasource query failed: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK || !ok {
+		return nil, fmt.Errorf("grafana returned status %d: %s", resp.StatusCode, queryResp.Message)
+	}
+
+	var series []Series
+	for _, frame := range result.Frames {
+		series = append(series, frameSeries(frame)...)
+	}
+	return series, nil
+}
+
+// frameSeries converts a time/value data frame into one series per value field
+func frameSeries(frame dsQueryFrame) []Series {
+	timeIndex := -1
+	for i, field := range frame.Schema.Fields {
+		if field.Type == "time" {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex < 0 || timeIndex >= len(frame.Data.Values) {
+		return nil
+	}
+	timestamps := frame.Data.Values[timeIndex]
+
+	var series []Series
+	for i, field := range frame.Schema.Fields {
+		if i == timeIndex || field.Type != "number" || i >= len(frame.Data.Values) {
+			continue
+		}
+
+		s := Series{Labels: field.Labels}
+		if s.Labels == nil {
+			s.Labels = map[string]string{}
+		}
+		for j, raw := range frame.Data.Values[i] {
+			if j >= len(timestamps) {
+				break
+			}
+			ts, ok := timestamps[j].(float64)
+			value, valueOK := raw.(float64)
+			if !ok || !valueOK {
+				continue
+			}
+			s.Samples = append(s.Samples, SamplePoint{
+				Timestamp: time.UnixMilli(int64(ts)),
+				Value:     value,
+			})
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+// dsValidateQuery validates a query by running it as an instant query
+func (c *prometheusClient) dsValidateQuery(ctx context.Context, query string) error {
+	now := time.Now()
+	if _, err := c.dsQuery(ctx, query, now.Add(-time.Minute), now, 0, true); err != nil {
+		return fmt.Errorf("query validation failed: %w", err)
+	}
+	return nil
+}
+
+// dsSeriesCounts counts the active series per metric name through Grafana
+func (c *prometheusClient) dsSeriesCounts(ctx context.Context) (map[string]int, error) {
+	now := time.Now()
+	series, err := c.dsQuery(ctx, seriesCountQuery, now.Add(-time.Minute), now, 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("series count query failed: %w", err)
+	}
+
+	counts := make(map[string]int, len(series))
+	for _, s := range series {
+		if len(s.Samples) == 0 || math.IsNaN(s.Samples[len(s.Samples)-1].Value) {
+			continue
+		}
+		counts[s.Labels["__name__"]] = int(s.Samples[len(s.Samples)-1].Value)
+	}
+	return counts, nil
+}
@@ -0,0 +1,153 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// newGrafanaServer fakes the Grafana endpoints used by the datasource client
+func newGrafanaServer(t *testing.T, queries *[]map[string]any) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer grafana-key" {
+			t.Errorf("expected the Grafana API key, got %q", r.Header.Get("Authorization"))
+		}
+
+		switch r.URL.Path {
+		case "/api/datasources/uid/prom/resources/api/v1/label/__name__/values":
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": []string{"up"}})
+		case "/api/ds/query":
+			var body struct {
+				Queries []map[string]any `json:"queries"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode query: %v", err)
+			}
+			*queries = append(*queries, body.Queries...)
+
+			if strings.Contains(body.Queries[0]["expr"].(string), "((") {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"results": map[string]any{"A": map[string]any{"error": "bad_data: parse error"}},
+				})
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"results": map[string]any{"A": map[string]any{"frames": []any{
+					map[string]any{
+						"schema": map[string]any{"fields": []any{
+							map[string]any{"name": "Time", "type": "time"},
+							map[string]any{"name": "Value", "type": "number", "labels": map[string]string{"__name__": "up", "job": "api"}},
+						}},
+						"data": map[string]any{"values": []any{
+							[]any{1700000000000.0, 1700000015000.0},
+							[]any{1.0, nil},
+						}},
+					},
+				}}},
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDatasourceClient(t *testing.T) {
+	var queries []map[string]any
+	server := newGrafanaServer(t, &queries)
+	defer server.Close()
+
+	client := newDatasourceClient(server.URL+"/", "prom", "grafana-key", nil)
+
+	t.Run("label values use the resource API", func(t *testing.T) {
+		values, err := client.getLabelValues(context.Background(), "__name__", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(values) != 1 || values[0] != "up" {
+			t.Errorf("unexpected values %v", values)
+		}
+	})
+
+	t.Run("range queries use ds/query", func(t *testing.T) {
+		end := time.UnixMilli(1700000015000)
+		series, err := client.queryRange(context.Background(), "up", end.Add(-time.Minute), end, 15*time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(series) != 1 || series[0].Labels["job"] != "api" {
+			t.Fatalf("unexpected series %+v", series)
+		}
+		if len(series[0].Samples) != 1 || series[0].Samples[0].Value != 1 || !series[0].Samples[0].Timestamp.Equal(time.UnixMilli(1700000000000)) {
+			t.Errorf("null samples should be skipped, got %+v", series[0].Samples)
+		}
+
+		query := queries[len(queries)-1]
+		if query["range"] != true || query["intervalMs"] != 15000.0 || query["maxDataPoints"] != 5.0 {
+			t.Errorf("unexpected query model %v", query)
+		}
+		if ds, _ := query["datasource"].(map[string]any); ds["uid"] != "prom" {
+			t.Errorf("unexpected datasource %v", query["datasource"])
+		}
+	})
+
+	t.Run("validation reports the datasource error", func(t *testing.T) {
+		err := client.validateQuery(context.Background(), "rate((up[5m])")
+		if err == nil || !strings.Contains(err.Error(), "bad_data: parse error") {
+			t.Errorf("expected parse error, got %v", err)
+		}
+		if queries[len(queries)-1]["instant"] != true {
+			t.Errorf("validation should run an instant query")
+		}
+	})
+
+	t.Run("series counts", func(t *testing.T) {
+		counts, err := client.getSeriesCounts(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if counts["up"] != 1 {
+			t.Errorf("unexpected counts %v", counts)
+		}
+	})
+}
+
+func TestNewPromQLServiceViaGrafana(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Prometheus.ViaGrafana = true
+
+	if _, err := NewPromQLService(zap.NewNop(), cfg, nil); err == nil {
+		t.Error("expected an error without GRAFANA_URL and PROMETHEUS_DATASOURCE_UID")
+	}
+
+	var queries []map[string]any
+	server := newGrafanaServer(t, &queries)
+	defer server.Close()
+
+	cfg.Grafana.URL = server.URL
+	cfg.Grafana.APIKey = "grafana-key"
+	cfg.Prometheus.DatasourceUID = "prom"
+
+	svc, err := NewPromQLService(zap.NewNop(), cfg, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := svc.ValidateQuery(context.Background(), "http://ignored:9090", "up"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(queries) != 1 {
+		t.Errorf("expected the query to go through Grafana, got %d queries", len(queries))
+	}
+}
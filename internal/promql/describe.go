@@ -0,0 +1,326 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	i18n "github.com/inference-gateway/grafana-agent/internal/i18n"
+)
+
+// noMetadataHelp is the HELP placeholder of metrics without metadata
+const noMetadataHelp = "No metadata available"
+
+var (
+	quantilePattern = regexp.MustCompile(`histogram_quantile\(\s*([0-9.]+)`)
+	// rangeFunctionPattern matches the functions that take a range vector
+	rangeFunctionPattern = regexp.MustCompile(`\b(rate|irate|increase|delta|idelta|deriv|avg_over_time|max_over_time|min_over_time|sum_over_time|count_over_time|last_over_time|changes|resets)\s*\(`)
+	rangePattern         = regexp.MustCompile(`\[([^\]:]+)(?::[^\]]*)?\]`)
+	// aggregationPattern matches an aggregation with its grouping clause written first
+	aggregationPattern = regexp.MustCompile(`\b(sum|avg|min|max|count|stddev|topk|bottomk)\s*(?:(by|without)\s*\(([^)]*)\))?\s*\(`)
+	// trailingGroupingPattern matches a grouping clause written after the aggregated expression
+	trailingGroupingPattern = regexp.MustCompile(`\)\s*(by|without)\s*\(([^)]*)\)`)
+	topKPattern             = regexp.MustCompile(`\b(topk|bottomk)\s*\(\s*(\d+)`)
+	comparisonPattern       = regexp.MustCompile(`\s(>=|<=|==|!=|>|<)\s*(-?[0-9.eE+]+)\s*$`)
+	offsetPattern           = regexp.MustCompile(`\boffset\s+(\S+?)\)*$`)
+	quotedPattern           = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// rangeFunctionPhrases describe what each range function computes
+var rangeFunctionPhrases = map[string]string{
+	"rate":            "Per-second rate of %s",
+	"irate":           "Instant per-second rate of %s",
+	"increase":        "Increase of %s",
+	"delta":           "Change of %s",
+	"idelta":          "Change between the last two samples of %s",
+	"deriv":           "Per-second derivative of %s",
+	"avg_over_time":   "Average of %s",
+	"max_over_time":   "Maximum of %s",
+	"min_over_time":   "Minimum of %s",
+	"sum_over_time":   "Sum of %s",
+	"count_over_time": "Number of samples of %s",
+	"last_over_time":  "Last value of %s",
+	"changes":         "Number of value changes of %s",
+	"resets":          "Number of counter resets of %s",
+}
+
+// aggregationPhrases describe how each aggregation combines series
+var aggregationPhrases = map[string]string{
+	"sum":    "summed",
+	"avg":    "averaged",
+	"min":    "minimum taken",
+	"max":    "maximum taken",
+	"count":  "series counted",
+	"stddev": "standard deviation taken",
+}
+
+// rangeVariables name the Grafana variables used as range windows
+var rangeVariables = map[string]string{
+	RateIntervalVariable: "the dashboard rate interval",
+	"$__interval":        "the panel interval",
+	"$__range":           "the dashboard time range",
+}
+
+// unitNames name the common Grafana unit IDs
+var unitNames = map[string]string{
+	"s":           "seconds",
+	"ms":          "milliseconds",
+	"µs":          "microseconds",
+	"ns":          "nanoseconds",
+	"bytes":       "bytes",
+	"decbytes":    "bytes",
+	"bits":        "bits",
+	"Bps":         "bytes per second",
+	"bps":         "bits per second",
+	"percent":     "percent (0-100)",
+	"percentunit": "percent (0.0-1.0)",
+	"reqps":       "requests per second",
+	"ops":         "operations per second",
+	"celsius":     "degrees Celsius",
+}
+
+// metricUnitSuffixes map the base unit suffixes of the Prometheus naming
+// conventions to unit names
+var metricUnitSuffixes = []struct{ suffix, unit string }{
+	{"_seconds", "seconds"},
+	{"_bytes", "bytes"},
+	{"_ratio", "ratio (0-1)"},
+	{"_percent", "percent"},
+	{"_celsius", "degrees Celsius"},
+	{"_meters", "meters"},
+	{"_joules", "joules"},
+	{"_volts", "volts"},
+}
+
+// Describer synthesizes panel descriptions in one language
+type Describer struct {
+	printer i18n.Printer
+}
+
+// NewDescriber returns a describer for a language tag; languages without a
+// catalog are described in English
+func NewDescriber(language string) Describer {
+	return Describer{printer: i18n.NewPrinter(language)}
+}
+
+// english describes panels for the package-level functions
+var english = NewDescriber(i18n.DefaultLanguage)
+
+// PanelDescription synthesizes a panel description from what its query
+// computes, the metric HELP text and the unit, skipping the parts that are
+// unknown
+func PanelDescription(explanation, help, unit string) string {
+	return english.PanelDescription(explanation, help, unit)
+}
+
+// DescribeSuggestion describes the panel of a query suggestion; metricInfo
+// may be nil for queries not generated from a metric, and unit is the
+// panel's Grafana unit ID, if set
+func DescribeSuggestion(metricInfo *MetricInfo, suggestion QuerySuggestion, unit string) string {
+	return english.DescribeSuggestion(metricInfo, suggestion, unit)
+}
+
+// ExplainQuery describes in words what a PromQL query computes: the range
+// function and window, the aggregation and its grouping, a trailing
+// comparison and an offset. It reads the query text rather than parsing it,
+// so unusual queries get a coarser explanation.
+func ExplainQuery(query string) string {
+	return english.ExplainQuery(query)
+}
+
+// DescribeUnit names a Grafana unit ID, or infers the unit of a query from
+// the unit suffix of its metric and the function applied to it
+func DescribeUnit(unit, query string) string {
+	return english.DescribeUnit(unit, query)
+}
+
+// PanelDescription is the package-level PanelDescription in the describer's
+// language; the HELP text is kept as exposed by the metric
+func (d Describer) PanelDescription(explanation, help, unit string) string {
+	var sentences []string
+	for _, part := range []string{explanation, help} {
+		part = strings.TrimSpace(part)
+		if part == "" || part == noMetadataHelp {
+			continue
+		}
+		sentences = append(sentences, sentence(part))
+	}
+	if unit != "" {
+		sentences = append(sentences, sentence(d.printer.Sprintf("Unit: %s", unit)))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// DescribeSuggestion is the package-level DescribeSuggestion in the
+// describer's language. Suggestion descriptions are English, so other
+// languages explain the query instead.
+func (d Describer) DescribeSuggestion(metricInfo *MetricInfo, suggestion QuerySuggestion, unit string) string {
+	explanation, help := d.ExplainQuery(suggestion.Query), ""
+	if metricInfo != nil {
+		help = metricInfo.Help
+		if suggestion.Description != "" && d.printer.Language() == i18n.DefaultLanguage {
+			explanation = fmt.Sprintf("%s of %s", suggestion.Description, metricInfo.Name)
+		}
+	}
+	return d.PanelDescription(explanation, help, d.DescribeUnit(unit, suggestion.Query))
+}
+
+// ExplainQuery is the package-level ExplainQuery in the describer's language
+func (d Describer) ExplainQuery(query string) string {
+	p := d.printer
+	query = strings.TrimSpace(query)
+	metrics := MetricNames(query)
+	if len(metrics) == 0 {
+		return ""
+	}
+
+	phrase := "Value of %s"
+	if match := rangeFunctionPattern.FindStringSubmatch(query); match != nil {
+		phrase = rangeFunctionPhrases[match[1]]
+	}
+
+	var explanation string
+	switch match := quantilePattern.FindStringSubmatch(query); {
+	case match != nil:
+		explanation = p.Sprintf("%s of %s, computed from its histogram buckets", quantileName(match[1]), histogramBaseName(metrics[0]))
+	case len(metrics) == 2 && isRatio(query):
+		explanation = p.Sprintf("Ratio of the %s to the %s", p.Embed(p.Sprintf(phrase, metrics[0])), p.Embed(p.Sprintf(phrase, metrics[1])))
+	default:
+		explanation = p.Sprintf(phrase, strings.Join(metrics, p.T(" and ")))
+	}
+	if match := rangePattern.FindStringSubmatch(query); match != nil {
+		window := strings.TrimSpace(match[1])
+		if name, ok := rangeVariables[window]; ok {
+			window = p.T(name)
+		}
+		explanation += p.Sprintf(" over %s", window)
+	}
+
+	if match := topKPattern.FindStringSubmatch(query); match != nil {
+		if match[1] == "bottomk" {
+			explanation += p.Sprintf(", keeping the bottom %s series", match[2])
+		} else {
+			explanation += p.Sprintf(", keeping the top %s series", match[2])
+		}
+	} else if match := aggregationPattern.FindStringSubmatch(query); match != nil {
+		mode, labels := match[2], match[3]
+		if mode == "" {
+			if trailing := trailingGroupingPattern.FindStringSubmatch(query); trailing != nil {
+				mode, labels = trailing[1], trailing[2]
+			}
+		}
+		labels = strings.Join(strings.Fields(strings.ReplaceAll(labels, ",", " ")), ", ")
+		aggregation := p.T(aggregationPhrases[match[1]])
+		switch mode {
+		case "by":
+			explanation += p.Sprintf(", %s by %s", aggregation, labels)
+		case "without":
+			explanation += p.Sprintf(", %s across all labels except %s", aggregation, labels)
+		default:
+			explanation += p.Sprintf(", %s across all series", aggregation)
+		}
+	}
+
+	if match := comparisonPattern.FindStringSubmatch(query); match != nil {
+		explanation += p.Sprintf(", shown only where it is %s %s", p.T(comparisonWords(match[1])), match[2])
+	}
+	if match := offsetPattern.FindStringSubmatch(query); match != nil {
+		explanation += p.Sprintf(", as it was %s earlier", match[1])
+	}
+	return explanation
+}
+
+// DescribeUnit is the package-level DescribeUnit in the describer's language
+func (d Describer) DescribeUnit(unit, query string) string {
+	p := d.printer
+	if unit != "" {
+		if name, ok := unitNames[unit]; ok {
+			return p.T(name)
+		}
+		if unit == "short" || unit == "none" {
+			return ""
+		}
+		return unit
+	}
+
+	metrics := MetricNames(query)
+	if len(metrics) == 0 {
+		return ""
+	}
+	name := strings.TrimSuffix(histogramBaseName(metrics[0]), "_total")
+	base := ""
+	for _, suffix := range metricUnitSuffixes {
+		if strings.HasSuffix(name, suffix.suffix) {
+			base = p.T(suffix.unit)
+			break
+		}
+	}
+
+	function := ""
+	if match := rangeFunctionPattern.FindStringSubmatch(query); match != nil {
+		function = match[1]
+	}
+	switch {
+	case quantilePattern.MatchString(query):
+		return base
+	case len(metrics) == 2 && isRatio(query):
+		// sum/count ratios keep the unit of the observed values
+		if strings.HasSuffix(metrics[0], "_sum") {
+			return base
+		}
+		return ""
+	case function == "rate" || function == "irate" || function == "deriv":
+		if base == "" || strings.HasSuffix(metrics[0], "_count") {
+			return p.T("per second")
+		}
+		return p.Sprintf("%s per second", base)
+	case function == "count_over_time" || function == "changes" || function == "resets":
+		return ""
+	}
+	return base
+}
+
+// isRatio reports whether a query divides, ignoring slashes in label values
+func isRatio(query string) bool {
+	return strings.Contains(quotedPattern.ReplaceAllString(query, `""`), "/")
+}
+
+// quantileName renders a histogram_quantile argument such as 0.99 as "p99"
+func quantileName(raw string) string {
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw + " quantile"
+	}
+	return "p" + strconv.FormatFloat(value*100, 'f', -1, 64)
+}
+
+// comparisonWords spells out a comparison operator
+func comparisonWords(op string) string {
+	switch op {
+	case ">":
+		return "above"
+	case "<":
+		return "below"
+	case ">=":
+		return "at least"
+	case "<=":
+		return "at most"
+	case "==":
+		return "equal to"
+	default:
+		return "not equal to"
+	}
+}
+
+// sentence capitalizes text and ends it with a period
+func sentence(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	text = string(unicode.ToUpper(r)) + text[size:]
+	if !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+	return text
+}
@@ -0,0 +1,108 @@
+package promql
+
+import "testing"
+
+func TestExplainQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    `sum by (job, instance) (rate(http_requests_total{path="/api/v1"}[$__rate_interval]))`,
+			expected: "Per-second rate of http_requests_total over the dashboard rate interval, summed by job, instance",
+		},
+		{
+			query:    `histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le))`,
+			expected: "p99 of http_request_duration_seconds, computed from its histogram buckets over 5m, summed by le",
+		},
+		{
+			query:    `rate(rpc_duration_seconds_sum[5m]) / rate(rpc_duration_seconds_count[5m])`,
+			expected: "Ratio of the per-second rate of rpc_duration_seconds_sum to the per-second rate of rpc_duration_seconds_count over 5m",
+		},
+		{
+			query:    `topk(5, node_load1)`,
+			expected: "Value of node_load1, keeping the top 5 series",
+		},
+		{
+			query:    `avg without (cpu) (node_memory_MemAvailable_bytes) < 1e9`,
+			expected: "Value of node_memory_MemAvailable_bytes, averaged across all labels except cpu, shown only where it is below 1e9",
+		},
+		{
+			query:    `increase(jobs_total[1h] offset 1w)`,
+			expected: "Increase of jobs_total over 1h, as it was 1w earlier",
+		},
+		{query: `vector(1)`, expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExplainQuery(tt.query); got != tt.expected {
+			t.Errorf("ExplainQuery(%q) = %q, expected %q", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestDescribeUnit(t *testing.T) {
+	tests := []struct {
+		unit, query, expected string
+	}{
+		{unit: "reqps", query: "rate(http_requests_total[5m])", expected: "requests per second"},
+		{unit: "short", query: "up", expected: ""},
+		{query: "rate(http_requests_total[5m])", expected: "per second"},
+		{query: "rate(process_cpu_seconds_total[5m])", expected: "seconds per second"},
+		{query: "histogram_quantile(0.95, rate(http_request_duration_seconds_bucket[5m]))", expected: "seconds"},
+		{query: "rate(rpc_duration_seconds_sum[5m]) / rate(rpc_duration_seconds_count[5m])", expected: "seconds"},
+		{query: "node_memory_MemAvailable_bytes", expected: "bytes"},
+		{query: "changes(process_start_time_seconds[1h])", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := DescribeUnit(tt.unit, tt.query); got != tt.expected {
+			t.Errorf("DescribeUnit(%q, %q) = %q, expected %q", tt.unit, tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestDescribeSuggestion(t *testing.T) {
+	info := &MetricInfo{Name: "http_requests_total", Help: "Total number of HTTP requests"}
+	suggestion := QuerySuggestion{Query: "rate(http_requests_total[5m])", Description: "Rate per second over 5m"}
+
+	expected := "Rate per second over 5m of http_requests_total. Total number of HTTP requests. Unit: per second."
+	if got := DescribeSuggestion(info, suggestion, ""); got != expected {
+		t.Errorf("DescribeSuggestion() = %q, expected %q", got, expected)
+	}
+
+	info.Help = noMetadataHelp
+	expected = "Rate per second over 5m of http_requests_total. Unit: requests per second."
+	if got := DescribeSuggestion(info, suggestion, "reqps"); got != expected {
+		t.Errorf("DescribeSuggestion() = %q, expected %q", got, expected)
+	}
+
+	expected = "Per-second rate of http_requests_total over 5m. Unit: per second."
+	if got := DescribeSuggestion(nil, suggestion, ""); got != expected {
+		t.Errorf("DescribeSuggestion() = %q, expected %q", got, expected)
+	}
+}
+
+func TestDescriberLanguages(t *testing.T) {
+	info := &MetricInfo{Name: "http_requests_total", Help: "Total number of HTTP requests"}
+	suggestion := QuerySuggestion{Query: "sum by (job) (rate(http_requests_total[$__rate_interval]))", Description: "Rate per second"}
+
+	tests := []struct {
+		language, expected string
+	}{
+		{language: "de-AT", expected: "Rate pro Sekunde von http_requests_total über das Rate-Intervall des Dashboards, summiert nach job. Total number of HTTP requests. Einheit: pro Sekunde."},
+		{language: "fr", expected: "Taux par seconde de http_requests_total sur l'intervalle de taux du tableau de bord, additionné par job. Total number of HTTP requests. Unité : par seconde."},
+		{language: "ja", expected: "Rate per second of http_requests_total. Total number of HTTP requests. Unit: per second."},
+	}
+
+	for _, tt := range tests {
+		if got := NewDescriber(tt.language).DescribeSuggestion(info, suggestion, ""); got != tt.expected {
+			t.Errorf("DescribeSuggestion(%s) = %q, expected %q", tt.language, got, tt.expected)
+		}
+	}
+
+	expected := "Verhältnis der Rate pro Sekunde von errors_total zur Rate pro Sekunde von requests_total über 5m"
+	if got := NewDescriber("de").ExplainQuery("rate(errors_total[5m]) / rate(requests_total[5m])"); got != expected {
+		t.Errorf("ExplainQuery() = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// LongRange is the dashboard time range from which queries are aligned to
+// the resolution of downsampled data
+const LongRange = 30 * 24 * time.Hour
+
+// downsamplingResolutions are the resolutions the Thanos compactor
+// downsamples blocks to, coarsest first
+var downsamplingResolutions = []time.Duration{time.Hour, 5 * time.Minute}
+
+// panelDataPoints approximates the number of points Grafana requests for a
+// panel, which sets the query step over a time range
+const panelDataPoints = 1000
+
+// rangeFunctions are the functions that need at least two samples in their
+// range window to return a value
+var rangeFunctions = regexp.MustCompile(`^(rate|irate|increase|delta|idelta|deriv)\s*\(`)
+
+// rangeWindow matches range and subquery windows such as [5m] or [1h:1m]
+var rangeWindow = regexp.MustCompile(`\[([0-9][0-9a-zA-Z]*)(:[^\]]*)?\]`)
+
+// DownsamplingResolution returns the resolution of the data a server with
+// downsampling answers queries over span with, or zero for raw data. A
+// Thanos querier with auto downsampling reads the coarsest resolution no
+// larger than a fifth of the step, so only long ranges (LongRange and up)
+// are considered.
+func DownsamplingResolution(span time.Duration, features *ServerFeatures) time.Duration {
+	if features == nil || !features.Downsampling || span < LongRange {
+		return 0
+	}
+	maxSourceResolution := span / panelDataPoints / 5
+	for _, resolution := range downsamplingResolutions {
+		if maxSourceResolution >= resolution {
+			return resolution
+		}
+	}
+	return 0
+}
+
+// AlignToResolution widens the range windows of a query that are shorter
+// than four samples at resolution, the same floor $__rate_interval keeps
+// for scrape intervals. It reports whether a window of rate() or a similar
+// function was widened, i.e. the query would have returned gaps or nothing.
+func AlignToResolution(query string, resolution time.Duration) (string, bool) {
+	minWindow := 4 * resolution
+	rateWidened := false
+	// Rewrite from the end so earlier offsets stay valid
+	matches := rangeWindow.FindAllStringSubmatchIndex(query, -1)
+	slices.Reverse(matches)
+	for _, loc := range matches {
+		window, err := grafana.ParseDuration(query[loc[2]:loc[3]])
+		if err != nil || window >= minWindow {
+			continue
+		}
+		if start, _, ok := enclosingCall(query, loc[0]); ok && rangeFunctions.MatchString(query[start:]) {
+			rateWidened = true
+		}
+		query = query[:loc[2]] + formatDuration(minWindow) + query[loc[3]:]
+	}
+	return query, rateWidened
+}
+
+// applyDownsampling aligns the suggestions to the resolution of downsampled
+// data: fixed windows are widened in both queries, the panels get the
+// resolution as their minimum interval so $__interval and $__rate_interval
+// never drop below it, and suggestions whose rate() window was too short
+// say so
+func applyDownsampling(suggestions []QuerySuggestion, resolution time.Duration) []QuerySuggestion {
+	if resolution <= 0 {
+		return suggestions
+	}
+	for i := range suggestions {
+		query, widened := AlignToResolution(suggestions[i].Query, resolution)
+		suggestions[i].Query = query
+		suggestions[i].DashboardQuery, _ = AlignToResolution(suggestions[i].DashboardQuery, resolution)
+		suggestions[i].Interval = formatDuration(resolution)
+		if widened {
+			suggestions[i].Warning = fmt.Sprintf("rate() needs at least %s over %s-resolution downsampled data; shorter windows were widened",
+				formatDuration(4*resolution), formatDuration(resolution))
+		}
+	}
+	return suggestions
+}
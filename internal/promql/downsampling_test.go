@@ -0,0 +1,74 @@
+package promql
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownsamplingResolution(t *testing.T) {
+	thanos := &ServerFeatures{Downsampling: true}
+	tests := []struct {
+		span     time.Duration
+		features *ServerFeatures
+		want     time.Duration
+	}{
+		{24 * time.Hour, thanos, 0},
+		{30 * 24 * time.Hour, thanos, 5 * time.Minute},
+		{90 * 24 * time.Hour, thanos, 5 * time.Minute},
+		{365 * 24 * time.Hour, thanos, time.Hour},
+		{365 * 24 * time.Hour, &ServerFeatures{}, 0},
+		{365 * 24 * time.Hour, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := DownsamplingResolution(tt.span, tt.features); got != tt.want {
+			t.Errorf("DownsamplingResolution(%s, %+v) = %s, want %s", tt.span, tt.features, got, tt.want)
+		}
+	}
+}
+
+func TestAlignToResolution(t *testing.T) {
+	tests := []struct {
+		query       string
+		want        string
+		rateWidened bool
+	}{
+		{`sum(rate(x[5m]))`, `sum(rate(x[20m]))`, true},
+		{`sum(rate(x[$__rate_interval]))`, `sum(rate(x[$__rate_interval]))`, false},
+		{`avg_over_time(x[10m])`, `avg_over_time(x[20m])`, false},
+		{`max_over_time(rate(x[2m])[1h:5m])`, `max_over_time(rate(x[20m])[1h:5m])`, true},
+		{`increase(x[1d])`, `increase(x[1d])`, false},
+	}
+	for _, tt := range tests {
+		got, rateWidened := AlignToResolution(tt.query, 5*time.Minute)
+		if got != tt.want || rateWidened != tt.rateWidened {
+			t.Errorf("AlignToResolution(%s) = %s, %v, want %s, %v", tt.query, got, rateWidened, tt.want, tt.rateWidened)
+		}
+	}
+}
+
+func TestGenerateQueriesAlignsToDownsampling(t *testing.T) {
+	metricInfo := &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter, Labels: []string{"job"}}
+	opts := GenerateOptions{Features: &ServerFeatures{Downsampling: true}, Range: 30 * 24 * time.Hour}
+	for _, suggestion := range generateQueries(metricInfo, opts) {
+		if suggestion.Interval != "5m" {
+			t.Errorf("expected a 5m panel interval for %s, got %q", suggestion.Query, suggestion.Interval)
+		}
+		if strings.Contains(suggestion.Query, "[5m]") {
+			t.Errorf("expected the 5m window widened in %s", suggestion.Query)
+		}
+		if strings.Contains(suggestion.Query, "rate(") && suggestion.Warning == "" {
+			t.Errorf("expected a warning for %s", suggestion.Query)
+		}
+		if strings.Contains(suggestion.DashboardQuery, "rate(") && !strings.Contains(suggestion.DashboardQuery, RateIntervalVariable) {
+			t.Errorf("expected the dashboard query to keep %s, got %s", RateIntervalVariable, suggestion.DashboardQuery)
+		}
+	}
+
+	opts.Range = 24 * time.Hour
+	for _, suggestion := range generateQueries(metricInfo, opts) {
+		if suggestion.Interval != "" || suggestion.Warning != "" {
+			t.Errorf("expected a short range left alone, got %+v", suggestion)
+		}
+	}
+}
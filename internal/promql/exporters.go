@@ -0,0 +1,267 @@
+package promql
+
+import (
+	"sort"
+	"strings"
+)
+
+// ExporterTemplate is a dashboard template that applies to an exporter,
+// published on grafana.com or built in
+type ExporterTemplate struct {
+	Name         string `json:"name"`
+	GrafanaComID int    `json:"grafana_com_id,omitempty"`
+	// Builtin names the built-in template (see DashboardTemplates)
+	Builtin     string `json:"builtin,omitempty"`
+	Description string `json:"description"`
+}
+
+// exporterDefinition describes how a well-known exporter is recognised
+type exporterDefinition struct {
+	name             string
+	prefixes         []string
+	signatureMetrics []string
+	jobHints         []string
+	templates        []ExporterTemplate
+}
+
+// DetectedExporter is an exporter identified from the metric namespace and up{job=...} series
+type DetectedExporter struct {
+	Exporter string `json:"exporter"`
+	// Confidence is high when signature metrics are present, medium when only
+	// the metric prefix matches and low when only a job name hints at it
+	Confidence       string             `json:"confidence"`
+	MetricCount      int                `json:"metric_count"`
+	SignatureMetrics []string           `json:"signature_metrics,omitempty"`
+	Jobs             []string           `json:"jobs,omitempty"`
+	Templates        []ExporterTemplate `json:"templates"`
+}
+
+// Detection confidence levels
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// knownExporters lists the exporters that can be detected
+var knownExporters = []exporterDefinition{
+	{
+		name:             "node_exporter",
+		prefixes:         []string{"node_"},
+		signatureMetrics: []string{"node_cpu_seconds_total", "node_memory_MemAvailable_bytes", "node_filesystem_avail_bytes"},
+		jobHints:         []string{"node"},
+		templates: []ExporterTemplate{
+			{Name: "Node Exporter Full", GrafanaComID: 1860, Description: "Host CPU, memory, disk, filesystem and network"},
+			{Name: "Hosts", Builtin: "node_host", Description: "CPU, load, memory, filesystem, disk and network usage per host"},
+		},
+	},
+	{
+		name:             "windows_exporter",
+		prefixes:         []string{"windows_", "wmi_"},
+		signatureMetrics: []string{"windows_cpu_time_total", "windows_os_info", "wmi_cpu_time_total"},
+		jobHints:         []string{"windows", "wmi"},
+		templates: []ExporterTemplate{
+			{Name: "Windows Exporter Dashboard", GrafanaComID: 14694, Description: "Host CPU, memory, volumes, network and services"},
+			{Name: "Windows hosts", Builtin: "windows_host", Description: "CPU, processor queue, memory, volume, disk and network usage and stopped services per host"},
+		},
+	},
+	{
+		name:             "cadvisor",
+		prefixes:         []string{"container_"},
+		signatureMetrics: []string{"container_cpu_usage_seconds_total", "container_memory_working_set_bytes"},
+		jobHints:         []string{"cadvisor", "kubelet"},
+		templates: []ExporterTemplate{
+			{Name: "cAdvisor exporter", GrafanaComID: 14282, Description: "Per-container CPU, memory, network and filesystem usage"},
+			{Name: "Kubernetes containers", Builtin: "kubernetes_containers", Description: "Memory and CPU usage per container against its limits and requests"},
+		},
+	},
+	{
+		name:             "blackbox_exporter",
+		prefixes:         []string{"probe_"},
+		signatureMetrics: []string{"probe_success", "probe_duration_seconds"},
+		jobHints:         []string{"blackbox", "probe"},
+		templates: []ExporterTemplate{
+			{Name: "Prometheus Blackbox Exporter", GrafanaComID: 7587, Description: "Probe success, latency, HTTP status and SSL expiry"},
+			{Name: "Synthetic monitoring", Builtin: "synthetic_monitoring", Description: "Probe success rate, latency by probe location and SSL expiry countdown"},
+		},
+	},
+	{
+		name:             "postgres_exporter",
+		prefixes:         []string{"pg_"},
+		signatureMetrics: []string{"pg_up", "pg_stat_database_xact_commit", "pg_stat_activity_count"},
+		jobHints:         []string{"postgres", "pg"},
+		templates: []ExporterTemplate{
+			{Name: "PostgreSQL Database", GrafanaComID: 9628, Description: "Connections, transactions, locks, cache hit ratio and replication"},
+			{Name: "PostgreSQL", Builtin: "postgresql", Description: "Cache hit ratio, rows fetched per row returned and replication lag with PostgreSQL thresholds"},
+		},
+	},
+	{
+		name:             "mysqld_exporter",
+		prefixes:         []string{"mysql_"},
+		signatureMetrics: []string{"mysql_up", "mysql_global_status_queries", "mysql_global_status_threads_connected"},
+		jobHints:         []string{"mysql", "mariadb"},
+		templates: []ExporterTemplate{
+			{Name: "MySQL Overview", GrafanaComID: 7362, Description: "Connections, queries, InnoDB buffer pool and network traffic"},
+			{Name: "MySQL", Builtin: "mysql", Description: "Buffer pool hit ratio, full table scan ratio and replication lag with MySQL thresholds"},
+		},
+	},
+	{
+		name:             "redis_exporter",
+		prefixes:         []string{"redis_"},
+		signatureMetrics: []string{"redis_up", "redis_keyspace_hits_total", "redis_memory_used_bytes"},
+		jobHints:         []string{"redis"},
+		templates: []ExporterTemplate{
+			{Name: "Redis Dashboard for Prometheus Redis Exporter", GrafanaComID: 763, Description: "Commands, memory, clients, keys and evictions"},
+			{Name: "Redis", Builtin: "redis", Description: "Keyspace hit ratio, memory usage, evictions and replication lag with cache thresholds"},
+		},
+	},
+	{
+		name:             "istio",
+		prefixes:         []string{"istio_", "envoy_"},
+		signatureMetrics: []string{"istio_requests_total", "istio_request_duration_milliseconds_bucket"},
+		jobHints:         []string{"istio", "envoy"},
+		templates: []ExporterTemplate{
+			{Name: "Istio Mesh Dashboard", GrafanaComID: 7639, Description: "Global request volume, success rate and per-service traffic"},
+			{Name: "Service mesh", Builtin: "istio_mesh", Description: "Traffic per source and destination workload, mTLS ratio and retry rate with namespace and workload variables"},
+		},
+	},
+	{
+		name:             "ingress-nginx",
+		prefixes:         []string{"nginx_ingress_controller_"},
+		signatureMetrics: []string{"nginx_ingress_controller_requests", "nginx_ingress_controller_request_duration_seconds_bucket"},
+		jobHints:         []string{"ingress"},
+		templates: []ExporterTemplate{
+			{Name: "NGINX Ingress controller", GrafanaComID: 9614, Description: "Controller request volume, success rate, config reloads and latency"},
+			{Name: "NGINX ingress", Builtin: "nginx_ingress", Description: "Requests, 4xx and 5xx ratios and latency per host and path"},
+		},
+	},
+	{
+		name:             "traefik",
+		prefixes:         []string{"traefik_"},
+		signatureMetrics: []string{"traefik_entrypoint_requests_total", "traefik_router_requests_total", "traefik_service_requests_total"},
+		jobHints:         []string{"traefik"},
+		templates: []ExporterTemplate{
+			{Name: "Traefik Official Standalone Dashboard", GrafanaComID: 17346, Description: "Entrypoint, router and service requests and latency"},
+			{Name: "Traefik", Builtin: "traefik", Description: "Requests, 4xx and 5xx ratios and latency per router and service"},
+		},
+	},
+	{
+		name:             "haproxy",
+		prefixes:         []string{"haproxy_"},
+		signatureMetrics: []string{"haproxy_frontend_http_requests_total", "haproxy_backend_http_responses_total"},
+		jobHints:         []string{"haproxy"},
+		templates: []ExporterTemplate{
+			{Name: "HAProxy 2 Full", GrafanaComID: 12693, Description: "Frontend, backend and server traffic, sessions and errors"},
+			{Name: "HAProxy", Builtin: "haproxy", Description: "Requests, 4xx and 5xx ratios per frontend and backend, and queued requests"},
+		},
+	},
+	{
+		name:             "kafka_exporter",
+		prefixes:         []string{"kafka_"},
+		signatureMetrics: []string{"kafka_brokers", "kafka_consumergroup_lag", "kafka_topic_partitions"},
+		jobHints:         []string{"kafka"},
+		templates: []ExporterTemplate{
+			{Name: "Kafka Exporter Overview", GrafanaComID: 7589, Description: "Broker count, topic partitions and consumer group lag"},
+			{Name: "Kafka consumer lag", Builtin: "kafka_lag", Description: "Consumer group lag, time to drain and consumption rate"},
+		},
+	},
+	{
+		name:             "rabbitmq",
+		prefixes:         []string{"rabbitmq_"},
+		signatureMetrics: []string{"rabbitmq_queue_messages_ready", "rabbitmq_queue_messages_unacked", "rabbitmq_queue_messages_unacknowledged"},
+		jobHints:         []string{"rabbitmq", "rabbit"},
+		templates: []ExporterTemplate{
+			{Name: "RabbitMQ-Overview", GrafanaComID: 10991, Description: "Nodes, connections, channels, queues and message rates"},
+			{Name: "RabbitMQ queues", Builtin: "rabbitmq_queues", Description: "Queue depth, unacknowledged messages, consumers and time to drain"},
+		},
+	},
+	{
+		name:             "nats_exporter",
+		prefixes:         []string{"gnatsd_", "jetstream_"},
+		signatureMetrics: []string{"gnatsd_varz_in_msgs", "jetstream_consumer_num_pending"},
+		jobHints:         []string{"nats", "jetstream"},
+		templates: []ExporterTemplate{
+			{Name: "NATS Server Dashboard", GrafanaComID: 2279, Description: "Server connections, message and byte rates, and slow consumers"},
+			{Name: "NATS", Builtin: "nats", Description: "JetStream consumer pending messages, time to drain and slow consumers"},
+		},
+	},
+	{
+		name:             "dcgm_exporter",
+		prefixes:         []string{"DCGM_"},
+		signatureMetrics: []string{"DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_USED"},
+		jobHints:         []string{"dcgm", "gpu"},
+		templates: []ExporterTemplate{
+			{Name: "NVIDIA DCGM Exporter Dashboard", GrafanaComID: 12239, Description: "GPU utilization, memory, temperature, power and clocks"},
+			{Name: "GPUs", Builtin: "nvidia_gpu", Description: "GPU utilization, memory and temperature per GPU, and GPU allocation per node, namespace and pod"},
+		},
+	},
+}
+
+// DetectExporters identifies well-known exporters from the metric names a
+// Prometheus server exposes and the job labels of its up series
+func DetectExporters(metricNames, upJobs []string) []DetectedExporter {
+	present := make(map[string]bool, len(metricNames))
+	for _, name := range metricNames {
+		present[name] = true
+	}
+
+	var detected []DetectedExporter
+	for _, def := range knownExporters {
+		result := DetectedExporter{Exporter: def.name, Templates: def.templates}
+
+		for _, name := range metricNames {
+			for _, prefix := range def.prefixes {
+				if strings.HasPrefix(name, prefix) {
+					result.MetricCount++
+					break
+				}
+			}
+		}
+		for _, sig := range def.signatureMetrics {
+			if present[sig] {
+				result.SignatureMetrics = append(result.SignatureMetrics, sig)
+			}
+		}
+		for _, job := range upJobs {
+			if jobMatchesHint(job, def.jobHints) {
+				result.Jobs = append(result.Jobs, job)
+			}
+		}
+
+		switch {
+		case len(result.SignatureMetrics) > 0:
+			result.Confidence = ConfidenceHigh
+		case result.MetricCount > 0:
+			result.Confidence = ConfidenceMedium
+		case len(result.Jobs) > 0:
+			result.Confidence = ConfidenceLow
+		default:
+			continue
+		}
+
+		detected = append(detected, result)
+	}
+
+	confidenceRank := map[string]int{ConfidenceHigh: 0, ConfidenceMedium: 1, ConfidenceLow: 2}
+	sort.SliceStable(detected, func(i, j int) bool {
+		return confidenceRank[detected[i].Confidence] < confidenceRank[detected[j].Confidence]
+	})
+
+	return detected
+}
+
+// jobMatchesHint reports whether a job name contains one of the hints as a
+// whole word (separated by -, _ or /)
+func jobMatchesHint(job string, hints []string) bool {
+	words := strings.FieldsFunc(strings.ToLower(job), func(r rune) bool {
+		return r == '-' || r == '_' || r == '/' || r == '.'
+	})
+	for _, word := range words {
+		for _, hint := range hints {
+			if word == hint || (len(hint) > 3 && strings.HasPrefix(word, hint)) {
+				return true
+			}
+		}
+	}
+	return false
+}
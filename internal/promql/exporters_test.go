@@ -0,0 +1,94 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestDetectExporters(t *testing.T) {
+	metricNames := []string{
+		"node_cpu_seconds_total", "node_load1",
+		"container_fs_reads_total",
+		"probe_success", "probe_http_status_code",
+		"rabbitmq_queue_messages_ready", "rabbitmq_queue_consumers",
+		"jetstream_consumer_num_pending",
+		"redis_up",
+		"wmi_cpu_time_total",
+		"DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_GPU_TEMP",
+		"http_requests_total", "up",
+	}
+	upJobs := []string{"node-exporter", "kafka-exporter", "checkout"}
+
+	detected := DetectExporters(metricNames, upJobs)
+
+	byName := map[string]DetectedExporter{}
+	for _, d := range detected {
+		byName[d.Exporter] = d
+	}
+
+	tests := []struct {
+		exporter    string
+		confidence  string
+		metricCount int
+		jobs        int
+	}{
+		{"node_exporter", ConfidenceHigh, 2, 1},
+		{"blackbox_exporter", ConfidenceHigh, 2, 0},
+		{"cadvisor", ConfidenceMedium, 1, 0},
+		{"kafka_exporter", ConfidenceLow, 0, 1},
+		{"rabbitmq", ConfidenceHigh, 2, 0},
+		{"nats_exporter", ConfidenceHigh, 1, 0},
+		{"redis_exporter", ConfidenceHigh, 1, 0},
+		{"windows_exporter", ConfidenceHigh, 1, 0},
+		{"dcgm_exporter", ConfidenceHigh, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exporter, func(t *testing.T) {
+			d, ok := byName[tt.exporter]
+			if !ok {
+				t.Fatalf("Expected %s to be detected", tt.exporter)
+			}
+			if d.Confidence != tt.confidence {
+				t.Errorf("Confidence = %s, want %s", d.Confidence, tt.confidence)
+			}
+			if d.MetricCount != tt.metricCount {
+				t.Errorf("MetricCount = %d, want %d", d.MetricCount, tt.metricCount)
+			}
+			if len(d.Jobs) != tt.jobs {
+				t.Errorf("Jobs = %v, want %d jobs", d.Jobs, tt.jobs)
+			}
+			if len(d.Templates) == 0 {
+				t.Error("Expected templates for a detected exporter")
+			}
+		})
+	}
+
+	if _, ok := byName["postgres_exporter"]; ok {
+		t.Error("Did not expect postgres_exporter to be detected")
+	}
+	if detected[len(detected)-1].Confidence != ConfidenceLow {
+		t.Error("Expected detections to be ordered by confidence")
+	}
+}
+
+func TestJobMatchesHint(t *testing.T) {
+	tests := []struct {
+		job      string
+		hints    []string
+		expected bool
+	}{
+		{"node-exporter", []string{"node"}, true},
+		{"kubernetes-nodes", []string{"node"}, true},
+		{"monitoring/postgres-exporter", []string{"postgres", "pg"}, true},
+		{"pgbouncer", []string{"pg"}, false},
+		{"checkout", []string{"kafka"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.job, func(t *testing.T) {
+			if got := jobMatchesHint(tt.job, tt.hints); got != tt.expected {
+				t.Errorf("jobMatchesHint(%q) = %v, want %v", tt.job, got, tt.expected)
+			}
+		})
+	}
+}
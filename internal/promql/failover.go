@@ -0,0 +1,91 @@
+package promql
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	zap "go.uber.org/zap"
+)
+
+// failoverTransport sends the requests for one replica of a Prometheus HA
+// pair to the other replica when the first cannot be reached or answers with
+// a server error. Behind the circuit breaker, a replica that keeps failing
+// costs no more than the breaker's immediate error until it recovers.
+type failoverTransport struct {
+	logger   *zap.Logger
+	next     http.RoundTripper
+	replicas [2]*url.URL
+}
+
+// newFailoverTransport pairs the two replica URLs of PROMETHEUS_REPLICA_URLS.
+// Without replica URLs it returns next unchanged.
+func newFailoverTransport(logger *zap.Logger, next http.RoundTripper, replicaURLs []string) (http.RoundTripper, error) {
+	if len(replicaURLs) == 0 {
+		return next, nil
+	}
+	if len(replicaURLs) != 2 {
+		return nil, fmt.Errorf("PROMETHEUS_REPLICA_URLS must list the two replicas of the HA pair, got %d URLs", len(replicaURLs))
+	}
+	t := &failoverTransport{logger: logger, next: next}
+	for i, raw := range replicaURLs {
+		u, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(raw), "/"))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid prometheus replica URL %q", raw)
+		}
+		t.replicas[i] = u
+	}
+	return t, nil
+}
+
+// RoundTrip sends the request, then to the other replica if it failed
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	from, to, ok := t.counterpart(req.URL)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return t.next.RoundTrip(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if (err == nil && resp.StatusCode < http.StatusInternalServerError) || req.Context().Err() != nil {
+		return resp, err
+	}
+
+	retry := req.Clone(req.Context())
+	retry.URL.Scheme = to.Scheme
+	retry.URL.Host = to.Host
+	retry.URL.Path = to.Path + strings.TrimPrefix(req.URL.Path, from.Path)
+	retry.Host = ""
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, err
+		}
+		retry.Body = body
+	}
+
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	} else {
+		reason = resp.Status
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	t.logger.Warn("prometheus replica failed, retrying on its peer",
+		zap.String("replica", from.Host),
+		zap.String("peer", to.Host),
+		zap.String("reason", reason))
+	return t.next.RoundTrip(retry)
+}
+
+// counterpart returns the replica a request URL addresses and its peer
+func (t *failoverTransport) counterpart(u *url.URL) (from, to *url.URL, ok bool) {
+	for i, replica := range t.replicas {
+		if u.Scheme == replica.Scheme && u.Host == replica.Host && strings.HasPrefix(u.Path, replica.Path) {
+			return replica, t.replicas[1-i], true
+		}
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,81 @@
+package promql
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+)
+
+func TestFailoverTransport(t *testing.T) {
+	var primaryCalls int
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	var peerBody string
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		peerBody = r.URL.Path + "?" + string(body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer peer.Close()
+
+	transport, err := newFailoverTransport(zap.NewNop(), http.DefaultTransport, []string{primary.URL, peer.URL + "/"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(primary.URL+"/api/v1/query", "application/x-www-form-urlencoded", strings.NewReader("query=up"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || primaryCalls != 1 || peerBody != "/api/v1/query?query=up" {
+		t.Errorf("expected the request replayed on the peer, got status %d, %d primary calls, peer saw %q", resp.StatusCode, primaryCalls, peerBody)
+	}
+
+	t.Run("fails over from an unreachable replica", func(t *testing.T) {
+		peer.Close()
+		resp, err := client.Get(peer.URL + "/api/v1/labels")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable || primaryCalls != 2 {
+			t.Errorf("expected the primary's answer, got status %d after %d primary calls", resp.StatusCode, primaryCalls)
+		}
+	})
+
+	t.Run("leaves other servers alone", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer other.Close()
+		resp, err := client.Get(other.URL + "/api/v1/labels")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadGateway || primaryCalls != 2 {
+			t.Errorf("expected no failover, got status %d after %d primary calls", resp.StatusCode, primaryCalls)
+		}
+	})
+}
+
+func TestNewFailoverTransportValidation(t *testing.T) {
+	if transport, err := newFailoverTransport(zap.NewNop(), http.DefaultTransport, nil); err != nil || transport != http.DefaultTransport {
+		t.Errorf("expected the transport unchanged without replicas, got %v, %v", transport, err)
+	}
+	if _, err := newFailoverTransport(zap.NewNop(), http.DefaultTransport, []string{"http://prom-0:9090"}); err == nil {
+		t.Error("expected an error for a single replica")
+	}
+	if _, err := newFailoverTransport(zap.NewNop(), http.DefaultTransport, []string{"http://prom-0:9090", "prom-1"}); err == nil {
+		t.Error("expected an error for a URL without scheme")
+	}
+}
@@ -0,0 +1,63 @@
+package promql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CategoryNativeHistogram marks suggestions that query a native histogram
+const CategoryNativeHistogram = "native_histogram"
+
+// applyServerFeatures adapts the suggestions to the optional features of the
+// server they will run on: native histogram quantiles are added, latency
+// quantiles are marked for exemplars and top-k panels are pinned to the
+// series ranking highest at the end of the range with `@ end()`, so lines do
+// not come and go as the ranking shifts. Without features nothing changes.
+func applyServerFeatures(metricInfo *MetricInfo, suggestions []QuerySuggestion, window string, features *ServerFeatures) []QuerySuggestion {
+	if features == nil {
+		return suggestions
+	}
+
+	if features.AtModifier {
+		for i := range suggestions {
+			if suggestions[i].Category == CategoryTopK {
+				suggestions[i].Query = stableTopK(suggestions[i].Query, window)
+				suggestions[i].Description += " at the end of the range"
+			}
+		}
+	}
+
+	if features.NativeHistograms && metricInfo.Type == MetricTypeHistogram {
+		baseName := histogramBaseName(metricInfo.Name)
+		suggestions = append(suggestions, QuerySuggestion{
+			Query:             fmt.Sprintf("histogram_quantile(0.95, sum(rate(%s[%s])))", baseName, window),
+			Description:       fmt.Sprintf("95th percentile over %s from the native histogram", window),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "duration",
+			Category:          CategoryNativeHistogram,
+		})
+	}
+
+	if features.Exemplars {
+		for i := range suggestions {
+			if strings.HasPrefix(suggestions[i].Query, "histogram_quantile(") {
+				suggestions[i].Exemplar = true
+			}
+		}
+	}
+
+	return suggestions
+}
+
+// stableTopK rewrites `topk(k, expr)` into `expr and topk(k, expr @ end())`:
+// the ranking is taken once at the end of the range and the selected series
+// are shown over the whole range
+func stableTopK(query, window string) string {
+	open := strings.Index(query, ", ")
+	if !strings.HasPrefix(query, "topk(") || open < 0 || !strings.HasSuffix(query, ")") {
+		return query
+	}
+	inner := query[open+2 : len(query)-1]
+	selector := "[" + window + "]"
+	return fmt.Sprintf("%s and %s", inner, strings.Replace(query, selector, selector+" @ end()", 1))
+}
@@ -0,0 +1,56 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestGenerateQueriesWithServerFeatures(t *testing.T) {
+	histogram := &MetricInfo{Name: "http_request_duration_seconds", Type: MetricTypeHistogram}
+	counter := &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter, Labels: []string{"pod"}}
+
+	t.Run("without features", func(t *testing.T) {
+		for _, s := range append(generateQueries(histogram, GenerateOptions{}), generateQueries(counter, GenerateOptions{})...) {
+			if s.Category == CategoryNativeHistogram || s.Exemplar {
+				t.Errorf("unexpected feature-dependent suggestion %+v", s)
+			}
+		}
+	})
+
+	t.Run("native histograms and exemplars", func(t *testing.T) {
+		features := &ServerFeatures{NativeHistograms: true, Exemplars: true}
+		var native *QuerySuggestion
+		suggestions := generateQueries(histogram, GenerateOptions{Features: features})
+		for i, s := range suggestions {
+			if s.Category == CategoryNativeHistogram {
+				native = &suggestions[i]
+			}
+		}
+		if native == nil {
+			t.Fatalf("expected a native histogram suggestion, got %+v", suggestions)
+		}
+		if native.Query != "histogram_quantile(0.95, sum(rate(http_request_duration_seconds[5m])))" {
+			t.Errorf("unexpected native histogram query %s", native.Query)
+		}
+		if !native.Exemplar || !suggestions[0].Exemplar {
+			t.Errorf("expected quantile suggestions to request exemplars")
+		}
+	})
+
+	t.Run("at modifier pins top-k series", func(t *testing.T) {
+		suggestions := generateQueries(counter, GenerateOptions{Features: &ServerFeatures{AtModifier: true}})
+		for _, s := range suggestions {
+			if s.Category != CategoryTopK {
+				continue
+			}
+			want := "sum by (pod) (rate(http_requests_total[5m])) and topk(5, sum by (pod) (rate(http_requests_total[5m] @ end())))"
+			if s.Query != want {
+				t.Errorf("expected %s, got %s", want, s.Query)
+			}
+			if s.DashboardQuery != "sum by (pod) (rate(http_requests_total[$__rate_interval])) and topk(5, sum by (pod) (rate(http_requests_total[$__rate_interval] @ end())))" {
+				t.Errorf("unexpected dashboard query %s", s.DashboardQuery)
+			}
+			return
+		}
+		t.Errorf("expected a top-k suggestion, got %+v", suggestions)
+	})
+}
@@ -0,0 +1,85 @@
+package promql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FleetColumn is a per-job column of a fleet overview table. Each query
+// returns one series per job.
+type FleetColumn struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Expr        string `json:"expr"`
+	Unit        string `json:"unit"`
+	// Sparkline columns are range queries drawn as a trend in their cell;
+	// the others are instant queries over the dashboard's time range
+	Sparkline  bool        `json:"sparkline,omitempty"`
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+}
+
+// availabilityThresholds turn a job orange below 99% of its targets up over
+// the time range and red below 95%
+func availabilityThresholds() []Threshold {
+	return higherIsBetter(0.95, 0.99)
+}
+
+// FleetColumns returns the columns of a fleet overview: availability from
+// the up series, the error ratio and 95th percentile latency from the
+// request conventions present, and CPU usage as a sparkline. Each job's
+// errors and latency come from the first request convention it exposes.
+func FleetColumns(metricNames []string) []FleetColumn {
+	present := make(map[string]bool, len(metricNames))
+	for _, name := range metricNames {
+		present[name] = true
+	}
+
+	columns := []FleetColumn{{
+		Name:        "Availability",
+		Description: "Share of the job's targets up over the time range.",
+		Expr:        "avg by (job) (avg_over_time(up[$__range]))",
+		Unit:        "percentunit",
+		Thresholds:  availabilityThresholds(),
+	}}
+
+	var errors, latencies []string
+	for _, m := range serviceRequests {
+		if present[m.requests] {
+			errors = append(errors, fmt.Sprintf("(%s / %s)", m.rate(m.errors, "job"), m.rate("", "job")))
+		}
+		if present[m.bucket] {
+			latency := p95(m.bucket, "job")
+			if m.unit == "ms" {
+				latency = fmt.Sprintf("(%s / 1000)", latency)
+			}
+			latencies = append(latencies, latency)
+		}
+	}
+	if len(errors) > 0 {
+		columns = append(columns, FleetColumn{
+			Name:        "Error ratio",
+			Description: "Share of requests that failed over the last 5 minutes.",
+			Expr:        strings.Join(errors, " or "),
+			Unit:        "percentunit",
+			Thresholds:  serverErrorThresholds(),
+		})
+	}
+	if len(latencies) > 0 {
+		columns = append(columns, FleetColumn{
+			Name:        "P95 latency",
+			Description: "95th percentile request duration over the last 5 minutes.",
+			Expr:        strings.Join(latencies, " or "),
+			Unit:        "s",
+		})
+	}
+	if present["process_cpu_seconds_total"] {
+		columns = append(columns, FleetColumn{
+			Name:        "CPU",
+			Description: "CPU cores the job's processes use.",
+			Expr:        "sum by (job) (rate(process_cpu_seconds_total[5m]))",
+			Unit:        "short",
+			Sparkline:   true,
+		})
+	}
+	return columns
+}
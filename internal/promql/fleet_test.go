@@ -0,0 +1,39 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestFleetColumns(t *testing.T) {
+	columns := FleetColumns([]string{
+		"up",
+		"http_server_duration_milliseconds_count", "http_server_duration_milliseconds_bucket",
+		"http_requests_total", "http_request_duration_seconds_bucket",
+		"process_cpu_seconds_total",
+	})
+
+	byName := map[string]FleetColumn{}
+	var names []string
+	for _, column := range columns {
+		byName[column.Name] = column
+		names = append(names, column.Name)
+	}
+	if len(names) != 4 {
+		t.Fatalf("Expected availability, error ratio, latency and CPU columns, got %v", names)
+	}
+
+	expected := `(histogram_quantile(0.95, sum by (job, le) (rate(http_server_duration_milliseconds_bucket[5m]))) / 1000) or histogram_quantile(0.95, sum by (job, le) (rate(http_request_duration_seconds_bucket[5m])))`
+	if latency := byName["P95 latency"]; latency.Expr != expected || latency.Unit != "s" {
+		t.Errorf("Expected the latencies in seconds, OpenTelemetry first, got %s in %s", latency.Expr, latency.Unit)
+	}
+	if errors := byName["Error ratio"]; errors.Expr != `(sum by (job) (rate(http_server_duration_milliseconds_count{http_status_code=~"5.."}[5m])) / sum by (job) (rate(http_server_duration_milliseconds_count[5m]))) or (sum by (job) (rate(http_requests_total{code=~"5.."}[5m])) / sum by (job) (rate(http_requests_total[5m])))` {
+		t.Errorf("Unexpected error ratio %s", errors.Expr)
+	}
+	if !byName["CPU"].Sparkline {
+		t.Error("Expected the CPU column as a sparkline")
+	}
+
+	if only := FleetColumns([]string{"up"}); len(only) != 1 || only[0].Name != "Availability" {
+		t.Errorf("Expected only availability without request metrics, got %+v", only)
+	}
+}
@@ -0,0 +1,84 @@
+package promql
+
+import (
+	"slices"
+	"strings"
+)
+
+// GaugeRange returns the bounds of a gauge showing values in unit: 0–100
+// for percent and 0–1 for percentunit. Other units have no fixed bounds and
+// return nil.
+func GaugeRange(unit string) (min, max *float64) {
+	switch unit {
+	case "percent":
+		return floatPtr(0), floatPtr(100)
+	case "percentunit":
+		return floatPtr(0), floatPtr(1)
+	}
+	return nil, nil
+}
+
+// usageWords are the name parts of metrics measuring the used part of a
+// pool, which their capacity metric names max or limit instead, e.g.
+// process_open_fds and process_max_fds
+var usageWords = []string{"used", "usage", "active", "open", "current", "inuse"}
+
+// CapacityCandidates returns the names a metric's companion capacity metric
+// may have, in order of preference: the metric with a usage word replaced
+// by max or limit, then with max or limit appended before the unit, e.g.
+// jvm_memory_max_bytes for jvm_memory_used_bytes.
+func CapacityCandidates(metric string) []string {
+	parts := strings.Split(metric, "_")
+	var candidates []string
+	add := func(name string) {
+		if name != metric && !slices.Contains(candidates, name) {
+			candidates = append(candidates, name)
+		}
+	}
+	for i, part := range parts {
+		if !slices.Contains(usageWords, part) {
+			continue
+		}
+		for _, capacity := range []string{"max", "limit"} {
+			replaced := slices.Clone(parts)
+			replaced[i] = capacity
+			add(strings.Join(replaced, "_"))
+		}
+	}
+	// in_use is two name parts
+	for _, capacity := range []string{"max", "limit"} {
+		add(strings.Replace(metric, "_in_use", "_"+capacity, 1))
+	}
+
+	base, unit := metric, ""
+	if i := strings.LastIndex(metric, "_"); i > 0 && slices.Contains(baseUnits, metric[i+1:]) {
+		base, unit = metric[:i], metric[i:]
+	}
+	for _, capacity := range []string{"max", "limit"} {
+		add(base + "_" + capacity + unit)
+	}
+	return candidates
+}
+
+// baseUnits are the unit suffixes of Prometheus metric names, which stay
+// last in the name of a capacity metric
+var baseUnits = []string{"bytes", "seconds", "connections", "threads", "fds", "ratio"}
+
+// CapacityMetric returns the first of the metric's capacity candidates
+// among metricNames
+func CapacityMetric(metric string, metricNames []string) (string, bool) {
+	for _, candidate := range CapacityCandidates(metric) {
+		if slices.Contains(metricNames, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// CapacityQuery returns the query of a gauge's upper bound: its query over
+// the capacity metric instead of the used one, so the same selectors and
+// aggregation apply
+func CapacityQuery(query, metric, capacity string) string {
+	renamed, _ := RenameMetrics(query, map[string]string{metric: capacity})
+	return renamed
+}
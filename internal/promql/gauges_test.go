@@ -0,0 +1,47 @@
+package promql
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCapacityMetric(t *testing.T) {
+	tests := []struct {
+		metric   string
+		present  []string
+		expected string
+	}{
+		{"process_open_fds", []string{"process_max_fds"}, "process_max_fds"},
+		{"jvm_memory_used_bytes", []string{"jvm_memory_committed_bytes", "jvm_memory_max_bytes"}, "jvm_memory_max_bytes"},
+		{"hikaricp_connections_active", []string{"hikaricp_connections_max"}, "hikaricp_connections_max"},
+		{"db_client_connections_in_use", []string{"db_client_connections_max"}, "db_client_connections_max"},
+		{"pool_size_bytes", []string{"pool_size_limit_bytes"}, "pool_size_limit_bytes"},
+		{"queue_depth", []string{"queue_depth_max"}, "queue_depth_max"},
+		{"process_open_fds", []string{"process_resident_memory_bytes"}, ""},
+	}
+	for _, tt := range tests {
+		capacity, ok := CapacityMetric(tt.metric, tt.present)
+		if capacity != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("CapacityMetric(%s) = %q, %v; expected %q", tt.metric, capacity, ok, tt.expected)
+		}
+	}
+
+	if candidates := CapacityCandidates("jvm_memory_used_bytes"); !slices.Equal(candidates, []string{"jvm_memory_max_bytes", "jvm_memory_limit_bytes", "jvm_memory_used_max_bytes", "jvm_memory_used_limit_bytes"}) {
+		t.Errorf("unexpected candidates %v", candidates)
+	}
+	if query := CapacityQuery(`sum by (pod) (jvm_memory_used_bytes{area="heap"})`, "jvm_memory_used_bytes", "jvm_memory_max_bytes"); query != `sum by (pod) (jvm_memory_max_bytes{area="heap"})` {
+		t.Errorf("unexpected capacity query %s", query)
+	}
+}
+
+func TestGaugeRange(t *testing.T) {
+	if min, max := GaugeRange("percent"); *min != 0 || *max != 100 {
+		t.Errorf("Expected 0-100 for percent, got %v-%v", *min, *max)
+	}
+	if min, max := GaugeRange("percentunit"); *min != 0 || *max != 1 {
+		t.Errorf("Expected 0-1 for percentunit, got %v-%v", *min, *max)
+	}
+	if min, max := GaugeRange("bytes"); min != nil || max != nil {
+		t.Error("Expected no range for bytes")
+	}
+}
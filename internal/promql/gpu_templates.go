@@ -0,0 +1,158 @@
+package promql
+
+import "fmt"
+
+// dcgmGPU groups DCGM metrics by GPU. dcgm-exporter labels each GPU with its
+// index on the host and the host name.
+const dcgmGPU = "Hostname, gpu"
+
+// dcgmMemoryUsage is the share of each GPU's framebuffer memory in use
+const dcgmMemoryUsage = "sum by (" + dcgmGPU + ") (DCGM_FI_DEV_FB_USED) / sum by (" + dcgmGPU + ") (DCGM_FI_DEV_FB_USED + DCGM_FI_DEV_FB_FREE)"
+
+// dcgmPodUtilization is the utilization of the GPUs each pod holds.
+// dcgm-exporter labels a GPU with the pod it is assigned to when its
+// Kubernetes pod mapping is enabled.
+const dcgmPodUtilization = `avg by (namespace, pod) (DCGM_FI_DEV_GPU_UTIL{pod!=""}) / 100`
+
+// gpuRequests selects the GPUs requested by running containers. Finished
+// pods keep their requests in kube-state-metrics but no longer hold a GPU.
+const gpuRequests = `kube_pod_container_resource_requests{resource="nvidia_com_gpu"} * on (namespace, pod) group_left () (kube_pod_status_phase{phase="Running"} == 1)`
+
+// gpuAllocatable selects the GPUs the nodes offer to pods
+const gpuAllocatable = `kube_node_status_allocatable{resource="nvidia_com_gpu"}`
+
+// gpuTemperatureThresholds turn a GPU orange at 80°C, where data center
+// GPUs start to throttle their clocks, and red at 90°C
+func gpuTemperatureThresholds() []Threshold {
+	return lowerIsBetter(80, 90)
+}
+
+// nvidiaGPUTemplate covers NVIDIA's dcgm-exporter and the GPU requests
+// kube-state-metrics reports, so allocated GPUs can be compared with the
+// work they do
+var nvidiaGPUTemplate = DashboardTemplate{
+	Name:        "nvidia_gpu",
+	Title:       "GPUs",
+	Description: "Utilization, memory, temperature and power per GPU from dcgm-exporter, and GPU allocation per node, namespace and pod",
+	Signature:   []string{"DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_USED"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Average GPU utilization",
+			Type:        "stat",
+			Description: "Share of time the GPUs ran a kernel, averaged over all GPUs.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "avg(DCGM_FI_DEV_GPU_UTIL) / 100"}},
+			requires:    []string{"DCGM_FI_DEV_GPU_UTIL"},
+		},
+		{
+			Title:       "Highest GPU memory usage",
+			Type:        "stat",
+			Description: "Memory usage of the GPU with the least framebuffer memory free.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(" + dcgmMemoryUsage + ")"}},
+			Thresholds:  UtilizationThresholds(),
+			requires:    []string{"DCGM_FI_DEV_FB_USED", "DCGM_FI_DEV_FB_FREE"},
+		},
+		{
+			Title:       "Hottest GPU",
+			Type:        "stat",
+			Description: "Temperature of the hottest GPU.",
+			Unit:        "celsius",
+			Queries:     []TemplateQuery{{Expr: "max(DCGM_FI_DEV_GPU_TEMP)"}},
+			Thresholds:  gpuTemperatureThresholds(),
+			requires:    []string{"DCGM_FI_DEV_GPU_TEMP"},
+		},
+		{
+			Title:       "GPUs allocated",
+			Type:        "stat",
+			Description: "Share of the GPUs nodes offer that running pods request.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("sum(%s) / sum(%s)", gpuRequests, gpuAllocatable)}},
+			Thresholds:  UtilizationThresholds(),
+			requires:    []string{"kube_pod_container_resource_requests", "kube_pod_status_phase", "kube_node_status_allocatable"},
+		},
+		{
+			Title:       "GPU utilization by GPU",
+			Type:        "timeseries",
+			Description: "Share of time each GPU ran a kernel. A GPU counts as busy while any kernel runs, however few of its cores it uses.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("avg by (%s) (DCGM_FI_DEV_GPU_UTIL) / 100", dcgmGPU), Legend: "{{Hostname}} GPU {{gpu}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Utilization",
+			requires:    []string{"DCGM_FI_DEV_GPU_UTIL"},
+		},
+		{
+			Title:       "Tensor core activity by GPU",
+			Type:        "timeseries",
+			Description: "Share of cycles each GPU's tensor cores were busy; low activity on a busy GPU means the model is not using them.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("avg by (%s) (DCGM_FI_PROF_PIPE_TENSOR_ACTIVE)", dcgmGPU), Legend: "{{Hostname}} GPU {{gpu}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Utilization",
+			requires:    []string{"DCGM_FI_PROF_PIPE_TENSOR_ACTIVE"},
+		},
+		{
+			Title:       "Power draw by GPU",
+			Type:        "timeseries",
+			Description: "Power each GPU draws.",
+			Unit:        "watt",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("sum by (%s) (DCGM_FI_DEV_POWER_USAGE)", dcgmGPU), Legend: "{{Hostname}} GPU {{gpu}}"}},
+			Group:       "Utilization",
+			requires:    []string{"DCGM_FI_DEV_POWER_USAGE"},
+		},
+		{
+			Title:       "GPU memory usage by GPU",
+			Type:        "timeseries",
+			Description: "Share of each GPU's framebuffer memory in use; a full GPU fails allocations with out of memory errors.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: dcgmMemoryUsage, Legend: "{{Hostname}} GPU {{gpu}}"}},
+			Thresholds:  UtilizationThresholds(),
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Memory and temperature",
+			requires:    []string{"DCGM_FI_DEV_FB_USED", "DCGM_FI_DEV_FB_FREE"},
+		},
+		{
+			Title:       "GPU temperature by GPU",
+			Type:        "timeseries",
+			Description: "Temperature of each GPU.",
+			Unit:        "celsius",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("max by (%s) (DCGM_FI_DEV_GPU_TEMP)", dcgmGPU), Legend: "{{Hostname}} GPU {{gpu}}"}},
+			Thresholds:  gpuTemperatureThresholds(),
+			Group:       "Memory and temperature",
+			requires:    []string{"DCGM_FI_DEV_GPU_TEMP"},
+		},
+		{
+			Title:       "GPU allocation by node",
+			Type:        "timeseries",
+			Description: "Share of each node's GPUs that running pods request.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("sum by (node) (%s) / sum by (node) (%s)", gpuRequests, gpuAllocatable), Legend: "{{node}}"}},
+			Thresholds:  UtilizationThresholds(),
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Allocation",
+			requires:    []string{"kube_pod_container_resource_requests", "kube_pod_status_phase", "kube_node_status_allocatable"},
+		},
+		{
+			Title:       "GPUs requested by namespace",
+			Type:        "timeseries",
+			Description: "GPUs requested by the running pods of each namespace.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("sum by (namespace) (%s)", gpuRequests), Legend: "{{namespace}}"}},
+			Group:       "Allocation",
+			requires:    []string{"kube_pod_container_resource_requests", "kube_pod_status_phase"},
+		},
+		{
+			Title:       "GPU utilization by pod",
+			Type:        "table",
+			Description: "Utilization of the GPUs each pod holds, least busy first; a pod near 0% keeps a GPU from other workloads without using it.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "sort(" + dcgmPodUtilization + ")", Instant: true}},
+			Group:       "Allocation",
+			requires:    []string{"DCGM_FI_DEV_GPU_UTIL"},
+		},
+	},
+}
@@ -0,0 +1,235 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+)
+
+// hostMetrics names the metrics a host exporter reports the basic
+// resources with, so Linux and Windows hosts share their panels
+type hostMetrics struct {
+	// cpu counts CPU seconds by mode, with an idle mode
+	cpu string
+	// memoryAvailable and memoryTotal are gauges in bytes
+	memoryAvailable, memoryTotal string
+	// filesystemFree and filesystemSize are gauges in bytes labelled with
+	// filesystemLabel; filesystemFilter leaves out pseudo filesystems
+	filesystemFree, filesystemSize    string
+	filesystemLabel, filesystemFilter string
+	// diskRead and diskWritten count bytes
+	diskRead, diskWritten string
+	// networkReceived and networkSent count bytes; networkFilter leaves
+	// out loopback interfaces
+	networkReceived, networkSent, networkFilter string
+}
+
+// filesystemUsage is the share of each filesystem in use, by instance and
+// the filesystem label
+func (m hostMetrics) filesystemUsage() string {
+	return fmt.Sprintf("1 - sum by (instance, %[1]s) (%[2]s{%[4]s}) / sum by (instance, %[1]s) (%[3]s{%[4]s})",
+		m.filesystemLabel, m.filesystemFree, m.filesystemSize, m.filesystemFilter)
+}
+
+// memoryUsage is the share of each host's memory in use
+func (m hostMetrics) memoryUsage() string {
+	return fmt.Sprintf("1 - sum by (instance) (%s) / sum by (instance) (%s)", m.memoryAvailable, m.memoryTotal)
+}
+
+// cpuUsage is the share of each host's CPU time spent outside idle
+func (m hostMetrics) cpuUsage() string {
+	return fmt.Sprintf(`1 - avg by (instance) (rate(%s{mode="idle"}[5m]))`, m.cpu)
+}
+
+// hostPanels returns the CPU, memory, filesystem, disk and network panels
+// of a host exporter
+func hostPanels(m hostMetrics) []TemplatePanel {
+	return []TemplatePanel{
+		{
+			Title:       "Busiest CPU",
+			Type:        "stat",
+			Description: "CPU usage of the busiest host.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(" + m.cpuUsage() + ")"}},
+			Thresholds:  UtilizationThresholds(),
+			requires:    []string{m.cpu},
+		},
+		{
+			Title:       "Highest memory usage",
+			Type:        "stat",
+			Description: "Memory usage of the host with the least memory available.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(" + m.memoryUsage() + ")"}},
+			Thresholds:  UtilizationThresholds(),
+			requires:    []string{m.memoryAvailable, m.memoryTotal},
+		},
+		{
+			Title:       "Fullest filesystem",
+			Type:        "stat",
+			Description: "Usage of the fullest filesystem on any host.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(" + m.filesystemUsage() + ")"}},
+			Thresholds:  UtilizationThresholds(),
+			requires:    []string{m.filesystemFree, m.filesystemSize},
+		},
+		{
+			Title:       "CPU usage by host",
+			Type:        "timeseries",
+			Description: "Share of CPU time spent outside idle per host.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: m.cpuUsage(), Legend: "{{instance}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "CPU and memory",
+			requires:    []string{m.cpu},
+		},
+		{
+			Title:       "Memory usage by host",
+			Type:        "timeseries",
+			Description: "Share of memory in use per host.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: m.memoryUsage(), Legend: "{{instance}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "CPU and memory",
+			requires:    []string{m.memoryAvailable, m.memoryTotal},
+		},
+		{
+			Title:       "Filesystem usage",
+			Type:        "timeseries",
+			Description: "Share of each filesystem in use.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: m.filesystemUsage(), Legend: fmt.Sprintf("{{instance}} {{%s}}", m.filesystemLabel)}},
+			Thresholds:  UtilizationThresholds(),
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Storage",
+			requires:    []string{m.filesystemFree, m.filesystemSize},
+		},
+		{
+			Title:       "Disk throughput by host",
+			Type:        "timeseries",
+			Description: "Bytes read from and written to disk per second per host.",
+			Unit:        "Bps",
+			Queries: []TemplateQuery{
+				{Expr: fmt.Sprintf("sum by (instance) (rate(%s[5m]))", m.diskRead), Legend: "{{instance}} read"},
+				{Expr: fmt.Sprintf("sum by (instance) (rate(%s[5m]))", m.diskWritten), Legend: "{{instance}} written"},
+			},
+			Group:    "Storage",
+			requires: []string{m.diskRead, m.diskWritten},
+		},
+		{
+			Title:       "Network throughput by host",
+			Type:        "timeseries",
+			Description: "Bytes received and sent per second per host, excluding loopback.",
+			Unit:        "Bps",
+			Queries: []TemplateQuery{
+				{Expr: fmt.Sprintf("sum by (instance) (rate(%s{%s}[5m]))", m.networkReceived, m.networkFilter), Legend: "{{instance}} received"},
+				{Expr: fmt.Sprintf("sum by (instance) (rate(%s{%s}[5m]))", m.networkSent, m.networkFilter), Legend: "{{instance}} sent"},
+			},
+			Group:    "Network",
+			requires: []string{m.networkReceived, m.networkSent},
+		},
+	}
+}
+
+// nodeExporterMetrics are the node_exporter metrics of Linux and other Unix
+// hosts
+var nodeExporterMetrics = hostMetrics{
+	cpu:              "node_cpu_seconds_total",
+	memoryAvailable:  "node_memory_MemAvailable_bytes",
+	memoryTotal:      "node_memory_MemTotal_bytes",
+	filesystemFree:   "node_filesystem_avail_bytes",
+	filesystemSize:   "node_filesystem_size_bytes",
+	filesystemLabel:  "mountpoint",
+	filesystemFilter: ignoredFilesystems,
+	diskRead:         "node_disk_read_bytes_total",
+	diskWritten:      "node_disk_written_bytes_total",
+	networkReceived:  "node_network_receive_bytes_total",
+	networkSent:      "node_network_transmit_bytes_total",
+	networkFilter:    `device!="lo"`,
+}
+
+// windowsMetrics returns the windows_exporter metrics under a prefix:
+// windows_, or wmi_ before the exporter was renamed in v0.15. memoryFree
+// and memoryTotal name the memory collector metrics, which moved from the
+// os and cs collectors to the memory collector in later releases.
+func windowsMetrics(prefix, memoryFree, memoryTotal string) hostMetrics {
+	return hostMetrics{
+		cpu:              prefix + "cpu_time_total",
+		memoryAvailable:  prefix + memoryFree,
+		memoryTotal:      prefix + memoryTotal,
+		filesystemFree:   prefix + "logical_disk_free_bytes",
+		filesystemSize:   prefix + "logical_disk_size_bytes",
+		filesystemLabel:  "volume",
+		filesystemFilter: ignoredWindowsVolumes,
+		diskRead:         prefix + "logical_disk_read_bytes_total",
+		diskWritten:      prefix + "logical_disk_write_bytes_total",
+		networkReceived:  prefix + "net_bytes_received_total",
+		networkSent:      prefix + "net_bytes_sent_total",
+		networkFilter:    `nic!~".*[Ll]oopback.*"`,
+	}
+}
+
+// ignoredWindowsVolumes are the unmounted partitions windows_exporter
+// reports by device name, such as recovery partitions
+const ignoredWindowsVolumes = `volume!~"HarddiskVolume.*"`
+
+// nodeHostTemplate covers the hosts node_exporter monitors
+var nodeHostTemplate = DashboardTemplate{
+	Name:        "node_host",
+	Title:       "Hosts",
+	Description: "CPU, load, memory, filesystem, disk and network usage of Linux and Unix hosts from node_exporter",
+	Signature:   []string{"node_cpu_seconds_total", "node_memory_MemAvailable_bytes"},
+	panels: append(hostPanels(nodeExporterMetrics), TemplatePanel{
+		Title:       "Load per core by host",
+		Type:        "timeseries",
+		Description: "One-minute load average divided by the number of cores; above 1 processes wait for a CPU.",
+		Unit:        "short",
+		Queries:     []TemplateQuery{{Expr: `sum by (instance) (node_load1) / count by (instance) (node_cpu_seconds_total{mode="idle"})`, Legend: "{{instance}}"}},
+		Group:       "CPU and memory",
+		requires:    []string{"node_load1", "node_cpu_seconds_total"},
+	}),
+}
+
+// windowsHostTemplate covers the hosts windows_exporter monitors. It lists
+// the panels of every metric naming the exporter has used, newest first, so
+// each panel reads the names the discovered exporter exposes.
+var windowsHostTemplate = DashboardTemplate{
+	Name:        "windows_host",
+	Title:       "Windows hosts",
+	Description: "CPU, processor queue, memory, volume, disk and network usage and stopped services of Windows hosts from windows_exporter",
+	Signature:   []string{"windows_cpu_time_total", "wmi_cpu_time_total"},
+	panels: slices.Concat(
+		hostPanels(windowsMetrics("windows_", "memory_physical_free_bytes", "memory_physical_total_bytes")),
+		hostPanels(windowsMetrics("windows_", "os_physical_memory_free_bytes", "cs_physical_memory_bytes")),
+		hostPanels(windowsMetrics("wmi_", "os_physical_memory_free_bytes", "cs_physical_memory_bytes")),
+		windowsSystemPanels("windows_"),
+		windowsSystemPanels("wmi_"),
+	),
+}
+
+// windowsSystemPanels returns the Windows specific panels under a prefix
+func windowsSystemPanels(prefix string) []TemplatePanel {
+	return []TemplatePanel{
+		{
+			Title:       "Processor queue by host",
+			Type:        "timeseries",
+			Description: "Threads ready to run but waiting for a CPU per host; a queue above twice the core count means the CPU is saturated.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("sum by (instance) (%ssystem_processor_queue_length)", prefix), Legend: "{{instance}}"}},
+			Group:       "CPU and memory",
+			requires:    []string{prefix + "system_processor_queue_length"},
+		},
+		{
+			Title:       "Stopped automatic services",
+			Type:        "table",
+			Description: "Services set to start automatically that are not running.",
+			Queries: []TemplateQuery{{
+				Expr:    fmt.Sprintf(`%[1]sservice_state{state="stopped"} == 1 and on (instance, name) %[1]sservice_start_mode{start_mode="auto"} == 1`, prefix),
+				Instant: true,
+			}},
+			Group:    "Services",
+			requires: []string{prefix + "service_state", prefix + "service_start_mode"},
+		},
+	}
+}
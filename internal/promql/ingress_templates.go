@@ -0,0 +1,392 @@
+package promql
+
+import "fmt"
+
+// statusRatio is the share of a request counter's rate whose status matches
+// the matcher, summed by the given labels or in total when by is empty
+func statusRatio(metric, matcher, by string) string {
+	sum := "sum"
+	if by != "" {
+		sum = fmt.Sprintf("sum by (%s)", by)
+	}
+	return fmt.Sprintf("%s (rate(%s{%s}[5m])) / %s (rate(%s[5m]))", sum, metric, matcher, sum, metric)
+}
+
+// p95 is the 95th percentile of a histogram, by the given labels
+func p95(bucket, by string) string {
+	if by != "" {
+		by += ", "
+	}
+	return fmt.Sprintf("histogram_quantile(0.95, sum by (%sle) (rate(%s[5m])))", by, bucket)
+}
+
+// clientErrorThresholds are looser than serverErrorThresholds: scanners and
+// expired sessions keep some 4xx responses flowing at any ingress
+func clientErrorThresholds() []Threshold {
+	return lowerIsBetter(0.1, 0.25)
+}
+
+// serverErrorThresholds turn a 5xx ratio orange at 1% and red at 5%
+func serverErrorThresholds() []Threshold {
+	return lowerIsBetter(0.01, 0.05)
+}
+
+// nginxIngressTemplate covers the ingress-nginx controller, whose request
+// metrics carry the host and path of the matching ingress rule
+var nginxIngressTemplate = DashboardTemplate{
+	Name:        "nginx_ingress",
+	Title:       "NGINX ingress",
+	Description: "Requests, 4xx and 5xx ratios and latency per host and path, and certificate expiry from the ingress-nginx controller",
+	Signature:   []string{"nginx_ingress_controller_requests"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Request rate",
+			Type:        "stat",
+			Description: "Requests per second across all ingresses.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum(rate(nginx_ingress_controller_requests[5m]))"}},
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "4xx ratio",
+			Type:        "stat",
+			Description: "Share of requests answered with a 4xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("nginx_ingress_controller_requests", `status=~"4.."`, "")}},
+			Thresholds:  clientErrorThresholds(),
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "5xx ratio",
+			Type:        "stat",
+			Description: "Share of requests answered with a 5xx response, including upstream failures.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("nginx_ingress_controller_requests", `status=~"5.."`, "")}},
+			Thresholds:  serverErrorThresholds(),
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "P95 latency",
+			Type:        "stat",
+			Description: "95th percentile request duration across all ingresses.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: p95("nginx_ingress_controller_request_duration_seconds_bucket", "")}},
+			requires:    []string{"nginx_ingress_controller_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Requests by host",
+			Type:        "timeseries",
+			Description: "Requests per second per host.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum by (host) (rate(nginx_ingress_controller_requests[5m]))", Legend: "{{host}}"}},
+			Group:       "Hosts",
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "4xx ratio by host",
+			Type:        "timeseries",
+			Description: "Share of requests per host answered with a 4xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("nginx_ingress_controller_requests", `status=~"4.."`, "host"), Legend: "{{host}}"}},
+			Thresholds:  clientErrorThresholds(),
+			Group:       "Hosts",
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "5xx ratio by host",
+			Type:        "timeseries",
+			Description: "Share of requests per host answered with a 5xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("nginx_ingress_controller_requests", `status=~"5.."`, "host"), Legend: "{{host}}"}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Hosts",
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "P95 latency by host",
+			Type:        "timeseries",
+			Description: "95th percentile request duration per host.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: p95("nginx_ingress_controller_request_duration_seconds_bucket", "host"), Legend: "{{host}}"}},
+			Group:       "Hosts",
+			requires:    []string{"nginx_ingress_controller_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Busiest paths",
+			Type:        "bargauge",
+			Description: "Paths with the most requests per second.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "topk(10, sum by (host, path) (rate(nginx_ingress_controller_requests[5m])))", Legend: "{{host}}{{path}}", Instant: true}},
+			Group:       "Paths",
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "Paths by 5xx ratio",
+			Type:        "bargauge",
+			Description: "Paths with the highest share of 5xx responses.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "topk(10, " + statusRatio("nginx_ingress_controller_requests", `status=~"5.."`, "host, path") + ")", Legend: "{{host}}{{path}}", Instant: true}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Paths",
+			requires:    []string{"nginx_ingress_controller_requests"},
+		},
+		{
+			Title:       "Slowest paths",
+			Type:        "bargauge",
+			Description: "Paths with the highest 95th percentile request duration.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "topk(10, " + p95("nginx_ingress_controller_request_duration_seconds_bucket", "host, path") + ")", Legend: "{{host}}{{path}}", Instant: true}},
+			Group:       "Paths",
+			requires:    []string{"nginx_ingress_controller_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Days until certificate expiry",
+			Type:        "stat",
+			Description: "Days until the certificate served for each host expires.",
+			Unit:        "d",
+			Queries:     []TemplateQuery{{Expr: certificateDaysLeft("nginx_ingress_controller_ssl_expire_time_seconds", "host"), Legend: "{{host}}", Instant: true}},
+			Thresholds:  countdownThresholds(),
+			Group:       "Controller",
+			requires:    []string{"nginx_ingress_controller_ssl_expire_time_seconds"},
+		},
+		{
+			Title:       "Active connections by controller pod",
+			Type:        "timeseries",
+			Description: "Client connections open on each controller pod.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: `sum by (controller_pod) (nginx_ingress_controller_nginx_process_connections{state="active"})`, Legend: "{{controller_pod}}"}},
+			Group:       "Controller",
+			requires:    []string{"nginx_ingress_controller_nginx_process_connections"},
+		},
+	},
+}
+
+// traefikTemplate covers Traefik v2 and v3. Traefik labels requests by
+// router rather than host and path; each router matches a host and path
+// rule, so the router panels stand in for the per-host breakdown.
+var traefikTemplate = DashboardTemplate{
+	Name:        "traefik",
+	Title:       "Traefik",
+	Description: "Requests, 4xx and 5xx ratios and latency per entrypoint, router (host and path rule) and service, and certificate expiry from Traefik",
+	Signature:   []string{"traefik_entrypoint_requests_total", "traefik_router_requests_total", "traefik_service_requests_total"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Request rate",
+			Type:        "stat",
+			Description: "Requests per second across all entrypoints.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum(rate(traefik_entrypoint_requests_total[5m]))"}},
+			requires:    []string{"traefik_entrypoint_requests_total"},
+		},
+		{
+			Title:       "4xx ratio",
+			Type:        "stat",
+			Description: "Share of requests answered with a 4xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("traefik_entrypoint_requests_total", `code=~"4.."`, "")}},
+			Thresholds:  clientErrorThresholds(),
+			requires:    []string{"traefik_entrypoint_requests_total"},
+		},
+		{
+			Title:       "5xx ratio",
+			Type:        "stat",
+			Description: "Share of requests answered with a 5xx response, including unreachable services.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("traefik_entrypoint_requests_total", `code=~"5.."`, "")}},
+			Thresholds:  serverErrorThresholds(),
+			requires:    []string{"traefik_entrypoint_requests_total"},
+		},
+		{
+			Title:       "P95 latency",
+			Type:        "stat",
+			Description: "95th percentile request duration across all entrypoints.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: p95("traefik_entrypoint_request_duration_seconds_bucket", "")}},
+			requires:    []string{"traefik_entrypoint_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Requests by router",
+			Type:        "timeseries",
+			Description: "Requests per second per router, i.e. per host and path rule.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum by (router) (rate(traefik_router_requests_total[5m]))", Legend: "{{router}}"}},
+			Group:       "Routers",
+			requires:    []string{"traefik_router_requests_total"},
+		},
+		{
+			Title:       "4xx ratio by router",
+			Type:        "timeseries",
+			Description: "Share of requests per router answered with a 4xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("traefik_router_requests_total", `code=~"4.."`, "router"), Legend: "{{router}}"}},
+			Thresholds:  clientErrorThresholds(),
+			Group:       "Routers",
+			requires:    []string{"traefik_router_requests_total"},
+		},
+		{
+			Title:       "5xx ratio by router",
+			Type:        "timeseries",
+			Description: "Share of requests per router answered with a 5xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("traefik_router_requests_total", `code=~"5.."`, "router"), Legend: "{{router}}"}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Routers",
+			requires:    []string{"traefik_router_requests_total"},
+		},
+		{
+			Title:       "P95 latency by router",
+			Type:        "timeseries",
+			Description: "95th percentile request duration per router.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: p95("traefik_router_request_duration_seconds_bucket", "router"), Legend: "{{router}}"}},
+			Group:       "Routers",
+			requires:    []string{"traefik_router_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Requests by service",
+			Type:        "timeseries",
+			Description: "Requests per second forwarded to each backend service.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum by (service) (rate(traefik_service_requests_total[5m]))", Legend: "{{service}}"}},
+			Group:       "Services",
+			requires:    []string{"traefik_service_requests_total"},
+		},
+		{
+			Title:       "5xx ratio by service",
+			Type:        "timeseries",
+			Description: "Share of requests per backend service answered with a 5xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("traefik_service_requests_total", `code=~"5.."`, "service"), Legend: "{{service}}"}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Services",
+			requires:    []string{"traefik_service_requests_total"},
+		},
+		{
+			Title:       "P95 latency by service",
+			Type:        "timeseries",
+			Description: "95th percentile request duration per backend service.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: p95("traefik_service_request_duration_seconds_bucket", "service"), Legend: "{{service}}"}},
+			Group:       "Services",
+			requires:    []string{"traefik_service_request_duration_seconds_bucket"},
+		},
+		{
+			Title:       "Days until certificate expiry",
+			Type:        "stat",
+			Description: "Days until each certificate Traefik serves expires.",
+			Unit:        "d",
+			Queries:     []TemplateQuery{{Expr: certificateDaysLeft("traefik_tls_certs_not_after", "cn"), Legend: "{{cn}}", Instant: true}},
+			Thresholds:  countdownThresholds(),
+			Group:       "Certificates",
+			requires:    []string{"traefik_tls_certs_not_after"},
+		},
+	},
+}
+
+// haproxyTemplate covers the Prometheus exporter built into HAProxy 2.0 and
+// later, which counts responses by status class (code="5xx") per proxy.
+// HAProxy has no per-host or per-path metrics, so frontends and backends
+// stand in for them.
+var haproxyTemplate = DashboardTemplate{
+	Name:        "haproxy",
+	Title:       "HAProxy",
+	Description: "Requests, 4xx and 5xx ratios per frontend and backend, backend response time and queued requests from HAProxy's built-in exporter",
+	Signature:   []string{"haproxy_frontend_http_requests_total", "haproxy_backend_http_responses_total"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Request rate",
+			Type:        "stat",
+			Description: "HTTP requests per second across all frontends.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum(rate(haproxy_frontend_http_requests_total[5m]))"}},
+			requires:    []string{"haproxy_frontend_http_requests_total"},
+		},
+		{
+			Title:       "4xx ratio",
+			Type:        "stat",
+			Description: "Share of frontend responses with a 4xx status.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("haproxy_frontend_http_responses_total", `code="4xx"`, "")}},
+			Thresholds:  clientErrorThresholds(),
+			requires:    []string{"haproxy_frontend_http_responses_total"},
+		},
+		{
+			Title:       "5xx ratio",
+			Type:        "stat",
+			Description: "Share of frontend responses with a 5xx status.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("haproxy_frontend_http_responses_total", `code="5xx"`, "")}},
+			Thresholds:  serverErrorThresholds(),
+			requires:    []string{"haproxy_frontend_http_responses_total"},
+		},
+		{
+			Title:       "Queued requests",
+			Type:        "stat",
+			Description: "Requests waiting for a free backend server; any queue means the backends are at their connection limit.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(haproxy_backend_current_queue)"}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(1), Color: "orange"},
+			},
+			requires: []string{"haproxy_backend_current_queue"},
+		},
+		{
+			Title:       "Requests by frontend",
+			Type:        "timeseries",
+			Description: "HTTP requests per second per frontend.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "sum by (proxy) (rate(haproxy_frontend_http_requests_total[5m]))", Legend: "{{proxy}}"}},
+			Group:       "Frontends",
+			requires:    []string{"haproxy_frontend_http_requests_total"},
+		},
+		{
+			Title:       "4xx ratio by frontend",
+			Type:        "timeseries",
+			Description: "Share of responses per frontend with a 4xx status.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("haproxy_frontend_http_responses_total", `code="4xx"`, "proxy"), Legend: "{{proxy}}"}},
+			Thresholds:  clientErrorThresholds(),
+			Group:       "Frontends",
+			requires:    []string{"haproxy_frontend_http_responses_total"},
+		},
+		{
+			Title:       "5xx ratio by frontend",
+			Type:        "timeseries",
+			Description: "Share of responses per frontend with a 5xx status.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("haproxy_frontend_http_responses_total", `code="5xx"`, "proxy"), Legend: "{{proxy}}"}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Frontends",
+			requires:    []string{"haproxy_frontend_http_responses_total"},
+		},
+		{
+			Title:       "5xx ratio by backend",
+			Type:        "timeseries",
+			Description: "Share of responses per backend with a 5xx status.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: statusRatio("haproxy_backend_http_responses_total", `code="5xx"`, "proxy"), Legend: "{{proxy}}"}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       "Backends",
+			requires:    []string{"haproxy_backend_http_responses_total"},
+		},
+		{
+			Title:       "Response time by backend",
+			Type:        "timeseries",
+			Description: "Average response time of each backend over its last 1024 requests.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max by (proxy) (haproxy_backend_response_time_average_seconds)", Legend: "{{proxy}}"}},
+			Group:       "Backends",
+			requires:    []string{"haproxy_backend_response_time_average_seconds"},
+		},
+		{
+			Title:       "Queued requests by backend",
+			Type:        "timeseries",
+			Description: "Requests waiting for a free server per backend.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (proxy) (haproxy_backend_current_queue)", Legend: "{{proxy}}"}},
+			Group:       "Backends",
+			requires:    []string{"haproxy_backend_current_queue"},
+		},
+	},
+}
@@ -0,0 +1,131 @@
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryIntent describes a kind of panel the user asked for beyond the
+// default per-type suggestions
+type QueryIntent string
+
+const (
+	QueryIntentTrend        QueryIntent = "trend"
+	QueryIntentWeekOverWeek QueryIntent = "week_over_week"
+	QueryIntentAnomaly      QueryIntent = "anomaly"
+)
+
+// Suggestion categories mark suggestions that serve a specific purpose
+const (
+	CategoryTrend      = "trend"
+	CategoryComparison = "comparison"
+	CategoryTopK       = "topk"
+)
+
+// GenerateOptions tunes query generation beyond the metric metadata
+type GenerateOptions struct {
+	// Intents requests additional suggestion families (trend, week_over_week,
+	// anomaly)
+	Intents []QueryIntent `json:"intents,omitempty"`
+	// ApdexSatisfied and ApdexTolerating are the Apdex latency thresholds in
+	// seconds; they default to 0.3s and four times the satisfied threshold
+	ApdexSatisfied  float64 `json:"apdex_satisfied,omitempty"`
+	ApdexTolerating float64 `json:"apdex_tolerating,omitempty"`
+	// Features are the detected features of the target server; nil keeps
+	// to queries every Prometheus-compatible server evaluates
+	Features *ServerFeatures `json:"features,omitempty"`
+	// ReplicaLabels are the labels telling the replicas of a Prometheus HA
+	// pair apart; queries over series carrying one are deduplicated
+	ReplicaLabels []string `json:"replica_labels,omitempty"`
+	// Range is the dashboard time range the queries are for; over long
+	// ranges on servers with downsampling, windows are aligned to the
+	// resolution of the downsampled data
+	Range time.Duration `json:"range,omitempty"`
+}
+
+// hasIntent reports whether the options request the given intent
+func (o GenerateOptions) hasIntent(intent QueryIntent) bool {
+	for _, i := range o.Intents {
+		if i == intent {
+			return true
+		}
+	}
+	return false
+}
+
+// generateIntentQueries derives trend and week-over-week suggestions from the
+// primary query of a metric
+func generateIntentQueries(base QuerySuggestion, opts GenerateOptions) []QuerySuggestion {
+	var suggestions []QuerySuggestion
+
+	if opts.hasIntent(QueryIntentWeekOverWeek) {
+		lastWeek := addOffset(base.Query, "1w")
+		suggestions = append(suggestions,
+			QuerySuggestion{
+				Query:             lastWeek,
+				Description:       fmt.Sprintf("%s one week ago (offset 1w)", base.Description),
+				VisualizationType: "timeseries",
+				YAxisLabel:        base.YAxisLabel,
+				Category:          CategoryComparison,
+				Unit:              base.Unit,
+				Group:             base.Group,
+			},
+			QuerySuggestion{
+				Query:             fmt.Sprintf("(%s) / (%s)", base.Query, lastWeek),
+				Description:       "Week-over-week ratio (1 = unchanged)",
+				VisualizationType: "timeseries",
+				YAxisLabel:        "ratio",
+				Category:          CategoryComparison,
+				Group:             base.Group,
+			},
+		)
+	}
+
+	if opts.hasIntent(QueryIntentTrend) {
+		suggestions = append(suggestions,
+			QuerySuggestion{
+				Query:             fmt.Sprintf("avg_over_time((%s)[1h:])", base.Query),
+				Description:       fmt.Sprintf("%s smoothed as a 1h moving average", base.Description),
+				VisualizationType: "timeseries",
+				YAxisLabel:        base.YAxisLabel,
+				Category:          CategoryTrend,
+				Unit:              base.Unit,
+				Group:             base.Group,
+			},
+			QuerySuggestion{
+				Query:             fmt.Sprintf("max_over_time((%s)[1h:])", base.Query),
+				Description:       fmt.Sprintf("%s hourly peak", base.Description),
+				VisualizationType: "timeseries",
+				YAxisLabel:        base.YAxisLabel,
+				Category:          CategoryTrend,
+				Unit:              base.Unit,
+				Group:             base.Group,
+			},
+		)
+	}
+
+	return suggestions
+}
+
+// addOffset applies an offset modifier to every selector in a query, placing
+// it after the range of range-vector selectors
+func addOffset(query, offset string) string {
+	selectors := ParseSelectors(query)
+	sort.Slice(selectors, func(i, j int) bool { return selectors[i].Start > selectors[j].Start })
+
+	for _, sel := range selectors {
+		insertAt := sel.End
+		next := skipSpaces(query, insertAt)
+		if next < len(query) && query[next] == '[' {
+			insertAt = skipUntil(query, next, ']')
+		}
+		if strings.HasPrefix(strings.TrimSpace(query[insertAt:]), "offset") {
+			continue
+		}
+		query = query[:insertAt] + " offset " + offset + query[insertAt:]
+	}
+
+	return query
+}
@@ -0,0 +1,87 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestAddOffset(t *testing.T) {
+	tests := map[string]string{
+		"up":                "up offset 1w",
+		"rate(x_total[5m])": "rate(x_total[5m] offset 1w)",
+		`rate(x_sum{job="a"}[5m]) / rate(x_count[5m])`: `rate(x_sum{job="a"}[5m] offset 1w) / rate(x_count[5m] offset 1w)`,
+		"rate(x_total[5m] offset 1d)":                  "rate(x_total[5m] offset 1d)",
+	}
+
+	for input, want := range tests {
+		if got := addOffset(input, "1w"); got != want {
+			t.Errorf("addOffset(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestGenerateQueries_Intents(t *testing.T) {
+	metricInfo := &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter}
+
+	plain := generateQueries(metricInfo, GenerateOptions{})
+	for _, s := range plain {
+		if s.Category != "" {
+			t.Errorf("Expected no categorised suggestions without intents, got %+v", s)
+		}
+	}
+
+	withIntents := generateQueries(metricInfo, GenerateOptions{
+		Intents: []QueryIntent{QueryIntentTrend, QueryIntentWeekOverWeek},
+	})
+
+	expected := map[string]string{
+		"rate(http_requests_total[5m] offset 1w)":                                     CategoryComparison,
+		"(rate(http_requests_total[5m])) / (rate(http_requests_total[5m] offset 1w))": CategoryComparison,
+		"avg_over_time((rate(http_requests_total[5m]))[1h:])":                         CategoryTrend,
+		"max_over_time((rate(http_requests_total[5m]))[1h:])":                         CategoryTrend,
+	}
+
+	found := 0
+	for _, s := range withIntents {
+		if category, ok := expected[s.Query]; ok {
+			found++
+			if s.Category != category {
+				t.Errorf("Query %s category = %q, want %q", s.Query, s.Category, category)
+			}
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("Expected %d intent suggestions, found %d in %+v", len(expected), found, withIntents)
+	}
+}
+
+func TestGenerateQueries_Apdex(t *testing.T) {
+	find := func(suggestions []QuerySuggestion) *QuerySuggestion {
+		for i := range suggestions {
+			if suggestions[i].Category == CategoryApdex {
+				return &suggestions[i]
+			}
+		}
+		return nil
+	}
+
+	defaults := find(generateQueries(&MetricInfo{Name: "http_request_duration_seconds_bucket", Type: MetricTypeHistogram}, GenerateOptions{}))
+	if defaults == nil {
+		t.Fatal("Expected an Apdex suggestion for a latency histogram")
+	}
+	want := `(sum(rate(http_request_duration_seconds_bucket{le="0.3"}[5m])) + sum(rate(http_request_duration_seconds_bucket{le="1.2"}[5m]))) / 2 / sum(rate(http_request_duration_seconds_count[5m]))`
+	if defaults.Query != want {
+		t.Errorf("Query = %s, want %s", defaults.Query, want)
+	}
+	if defaults.VisualizationType != "stat" || len(defaults.Thresholds) == 0 || defaults.Thresholds[0].Value != nil {
+		t.Errorf("Expected a stat panel with a base threshold step, got %+v", defaults)
+	}
+
+	custom := find(generateQueries(&MetricInfo{Name: "rpc_latency_bucket", Type: MetricTypeHistogram}, GenerateOptions{ApdexSatisfied: 0.5, ApdexTolerating: 1}))
+	if custom == nil || custom.Query != `(sum(rate(rpc_latency_bucket{le="0.5"}[5m])) + sum(rate(rpc_latency_bucket{le="1"}[5m]))) / 2 / sum(rate(rpc_latency_count[5m]))` {
+		t.Errorf("Unexpected custom Apdex suggestion %+v", custom)
+	}
+
+	if find(generateQueries(&MetricInfo{Name: "response_size_bytes_bucket", Type: MetricTypeHistogram}, GenerateOptions{})) != nil {
+		t.Error("Expected no Apdex suggestion for a non-latency histogram")
+	}
+}
@@ -0,0 +1,132 @@
+package promql
+
+import "fmt"
+
+// k8sContainer groups container series by container. cAdvisor also
+// reports the pod's cgroup as a series without a container label, which
+// would count the pod twice.
+const k8sContainer = "namespace, pod, container"
+
+// k8sSelected selects the containers of the selected namespaces
+const k8sSelected = `container!="", namespace=~"$namespace"`
+
+// Usage of each container, from cAdvisor
+var (
+	k8sMemoryUsage = fmt.Sprintf("sum by (%s) (container_memory_working_set_bytes{%s})", k8sContainer, k8sSelected)
+	k8sCPUUsage    = fmt.Sprintf("sum by (%s) (rate(container_cpu_usage_seconds_total{%s}[5m]))", k8sContainer, k8sSelected)
+)
+
+// k8sResource is the limit or request of each container for a resource,
+// from kube-state-metrics. Containers without one have no series, so
+// ratios against it leave them out.
+func k8sResource(metric, resource string) string {
+	return fmt.Sprintf("sum by (%s) (%s{resource=%q, %s})", k8sContainer, metric, resource, k8sSelected)
+}
+
+// k8sUtilization is a container's usage as a share of its limit or request
+func k8sUtilization(usage, metric, resource string) string {
+	return fmt.Sprintf("%s / %s", usage, k8sResource(metric, resource))
+}
+
+// k8sUtilizationThresholds turn a container orange at 80% of its limit or
+// request and red at 90%, short of the OOM kill or CPU throttling at 100%
+func k8sUtilizationThresholds() []Threshold {
+	return lowerIsBetter(0.8, 0.9)
+}
+
+// Kubernetes resource metrics of kube-state-metrics
+const (
+	k8sLimits   = "kube_pod_container_resource_limits"
+	k8sRequests = "kube_pod_container_resource_requests"
+)
+
+// kubernetesContainersTemplate covers the container usage cAdvisor reports,
+// against the limits and requests kube-state-metrics reports when both are
+// scraped. Usage is drawn raw only when the limits are missing.
+var kubernetesContainersTemplate = DashboardTemplate{
+	Name:        "kubernetes_containers",
+	Title:       "Kubernetes containers",
+	Description: "Memory and CPU usage per container from cAdvisor, as a share of the container's limits and requests from kube-state-metrics when present",
+	Signature:   []string{"container_cpu_usage_seconds_total", "container_memory_working_set_bytes"},
+	Variables: []TemplateVariable{
+		{Name: "namespace", Label: "Namespace", Selector: `container_memory_working_set_bytes{container!=""}`, Source: "namespace"},
+	},
+	panels: []TemplatePanel{
+		{
+			Title:       "Containers near their memory limit",
+			Type:        "stat",
+			Description: "Containers using over 90% of their memory limit, which are OOM killed on reaching it.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("count(%s > 0.9) or vector(0)", k8sUtilization(k8sMemoryUsage, k8sLimits, "memory"))}},
+			Thresholds:  []Threshold{{Value: nil, Color: "green"}, {Value: floatPtr(1), Color: "red"}},
+			requires:    []string{"container_memory_working_set_bytes", k8sLimits},
+		},
+		{
+			Title:       "Containers near their CPU limit",
+			Type:        "stat",
+			Description: "Containers using over 90% of their CPU limit, which are throttled on reaching it.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: fmt.Sprintf("count(%s > 0.9) or vector(0)", k8sUtilization(k8sCPUUsage, k8sLimits, "cpu"))}},
+			Thresholds:  []Threshold{{Value: nil, Color: "green"}, {Value: floatPtr(1), Color: "orange"}},
+			requires:    []string{"container_cpu_usage_seconds_total", k8sLimits},
+		},
+		{
+			Title:       "Memory by container",
+			Type:        "timeseries",
+			Description: "Working set memory of each container as a share of its memory limit.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: k8sUtilization(k8sMemoryUsage, k8sLimits, "memory"), Legend: "{{pod}}/{{container}}"}},
+			Thresholds:  k8sUtilizationThresholds(),
+			Group:       "Memory",
+			requires:    []string{"container_memory_working_set_bytes", k8sLimits},
+		},
+		{
+			Title:       "Memory by container",
+			Type:        "timeseries",
+			Description: "Working set memory of each container, what the OOM killer compares with the limit.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: k8sMemoryUsage, Legend: "{{pod}}/{{container}}"}},
+			Group:       "Memory",
+			requires:    []string{"container_memory_working_set_bytes"},
+		},
+		{
+			Title:       "Memory against requests",
+			Type:        "timeseries",
+			Description: "Working set memory of each container as a share of its memory request; containers over it are evicted first when the node runs short.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: k8sUtilization(k8sMemoryUsage, k8sRequests, "memory"), Legend: "{{pod}}/{{container}}"}},
+			Thresholds:  k8sUtilizationThresholds(),
+			Group:       "Memory",
+			requires:    []string{"container_memory_working_set_bytes", k8sRequests},
+		},
+		{
+			Title:       "CPU by container",
+			Type:        "timeseries",
+			Description: "CPU usage of each container as a share of its CPU limit.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: k8sUtilization(k8sCPUUsage, k8sLimits, "cpu"), Legend: "{{pod}}/{{container}}"}},
+			Thresholds:  k8sUtilizationThresholds(),
+			Group:       "CPU",
+			requires:    []string{"container_cpu_usage_seconds_total", k8sLimits},
+		},
+		{
+			Title:       "CPU by container",
+			Type:        "timeseries",
+			Description: "CPU cores each container uses.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: k8sCPUUsage, Legend: "{{pod}}/{{container}}"}},
+			Group:       "CPU",
+			requires:    []string{"container_cpu_usage_seconds_total"},
+		},
+		{
+			Title:       "CPU against requests",
+			Type:        "timeseries",
+			Description: "CPU usage of each container as a share of its CPU request, the share of the node's CPU it is guaranteed.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: k8sUtilization(k8sCPUUsage, k8sRequests, "cpu"), Legend: "{{pod}}/{{container}}"}},
+			Thresholds:  k8sUtilizationThresholds(),
+			Group:       "CPU",
+			requires:    []string{"container_cpu_usage_seconds_total", k8sRequests},
+		},
+	},
+}
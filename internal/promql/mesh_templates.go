@@ -0,0 +1,178 @@
+package promql
+
+import "fmt"
+
+// istioDestination selects what the destination sidecars reported for the
+// selected namespaces and workloads. Both sidecars of a call report it, so
+// counting one reporter avoids double counting; the destination also knows
+// whether the connection used mutual TLS.
+const istioDestination = `reporter="destination", destination_workload_namespace=~"$namespace", destination_workload=~"$workload"`
+
+// istioRequests selects the destination-reported requests, narrowed by the
+// extra matchers
+func istioRequests(extra string) string {
+	matchers := istioDestination
+	if extra != "" {
+		matchers += ", " + extra
+	}
+	return fmt.Sprintf("istio_requests_total{%s}", matchers)
+}
+
+// istioRequestRate is the rate of istioRequests summed by the given labels,
+// or in total when by is empty
+func istioRequestRate(extra, by string) string {
+	if by == "" {
+		return fmt.Sprintf("sum(rate(%s[5m]))", istioRequests(extra))
+	}
+	return fmt.Sprintf("sum by (%s) (rate(%s[5m]))", by, istioRequests(extra))
+}
+
+// istioLatency is the 95th percentile request duration of the selected
+// workloads, by the given labels
+func istioLatency(by string) string {
+	if by != "" {
+		by += ", "
+	}
+	return fmt.Sprintf("histogram_quantile(0.95, sum by (%sle) (rate(istio_request_duration_milliseconds_bucket{%s}[5m])))", by, istioDestination)
+}
+
+// mtlsThresholds turn the mTLS ratio orange on any plaintext traffic and
+// red below 90%, since a mesh in STRICT mode should only carry mTLS
+func mtlsThresholds() []Threshold {
+	return higherIsBetter(0.9, 1)
+}
+
+// istioMeshTemplate covers the standard Istio metrics and the Envoy retry
+// stats of its sidecars. The namespace and workload variables chain, so the
+// workload list follows the selected namespaces.
+var istioMeshTemplate = DashboardTemplate{
+	Name:        "istio_mesh",
+	Title:       "Service mesh",
+	Description: "Request rate, errors and latency per source and destination workload, mTLS ratio and retry rate from Istio and its Envoy sidecars",
+	Signature:   []string{"istio_requests_total"},
+	Variables: []TemplateVariable{
+		{Name: "namespace", Label: "Namespace", Selector: `istio_requests_total{reporter="destination"}`, Source: "destination_workload_namespace"},
+		{Name: "workload", Label: "Workload", Selector: `istio_requests_total{reporter="destination", destination_workload_namespace=~"$namespace"}`, Source: "destination_workload"},
+	},
+	panels: []TemplatePanel{
+		{
+			Title:       "Request rate",
+			Type:        "stat",
+			Description: "Requests per second received by the selected workloads.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate("", "")}},
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Success rate",
+			Type:        "stat",
+			Description: "Share of requests answered without a 5xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate(`response_code!~"5.."`, "") + " / " + istioRequestRate("", "")}},
+			Thresholds:  higherIsBetter(0.95, 0.99),
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "P95 latency",
+			Type:        "stat",
+			Description: "95th percentile request duration across the selected workloads.",
+			Unit:        "ms",
+			Queries:     []TemplateQuery{{Expr: istioLatency("")}},
+			requires:    []string{"istio_request_duration_milliseconds_bucket"},
+		},
+		{
+			Title:       "mTLS ratio",
+			Type:        "stat",
+			Description: "Share of requests received over mutual TLS; anything below 100% means some clients still connect in plaintext.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate(`connection_security_policy="mutual_tls"`, "") + " / " + istioRequestRate("", "")}},
+			Thresholds:  mtlsThresholds(),
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Requests by destination workload",
+			Type:        "timeseries",
+			Description: "Requests per second received by each workload.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate("", "destination_workload_namespace, destination_workload"), Legend: "{{destination_workload_namespace}}/{{destination_workload}}"}},
+			Group:       "Traffic",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Requests by source workload",
+			Type:        "timeseries",
+			Description: "Requests per second the selected workloads receive from each calling workload.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate("", "source_workload_namespace, source_workload"), Legend: "{{source_workload_namespace}}/{{source_workload}}"}},
+			Group:       "Traffic",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Top source to destination edges",
+			Type:        "bargauge",
+			Description: "Busiest calls between workloads.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: "topk(20, " + istioRequestRate("", "source_workload, destination_workload") + ")", Legend: "{{source_workload}} → {{destination_workload}}", Instant: true}},
+			Group:       "Traffic",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Error ratio by destination workload",
+			Type:        "timeseries",
+			Description: "Share of requests each workload answered with a 5xx response.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate(`response_code=~"5.."`, "destination_workload") + " / " + istioRequestRate("", "destination_workload"), Legend: "{{destination_workload}}"}},
+			Thresholds:  lowerIsBetter(0.01, 0.05),
+			Group:       "Errors and latency",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "P95 latency by destination workload",
+			Type:        "timeseries",
+			Description: "95th percentile request duration per workload, as measured by its sidecar.",
+			Unit:        "ms",
+			Queries:     []TemplateQuery{{Expr: istioLatency("destination_workload"), Legend: "{{destination_workload}}"}},
+			Group:       "Errors and latency",
+			requires:    []string{"istio_request_duration_milliseconds_bucket"},
+		},
+		{
+			Title:       "mTLS ratio by destination workload",
+			Type:        "timeseries",
+			Description: "Share of requests each workload received over mutual TLS.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate(`connection_security_policy="mutual_tls"`, "destination_workload") + " / " + istioRequestRate("", "destination_workload"), Legend: "{{destination_workload}}"}},
+			Thresholds:  mtlsThresholds(),
+			Max:         floatPtr(1),
+			Group:       "Security",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Plaintext requests by source workload",
+			Type:        "timeseries",
+			Description: "Requests per second received without mutual TLS, by calling workload; these clients block moving the namespace to STRICT mode.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: istioRequestRate(`connection_security_policy!="mutual_tls"`, "source_workload, destination_workload"), Legend: "{{source_workload}} → {{destination_workload}}"}},
+			Group:       "Security",
+			requires:    []string{"istio_requests_total"},
+		},
+		{
+			Title:       "Retry rate by upstream cluster",
+			Type:        "timeseries",
+			Description: "Share of upstream requests the sidecars in the selected namespaces retried; retries hide failures from callers but multiply load on the destination.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: `sum by (cluster_name) (rate(envoy_cluster_upstream_rq_retry{namespace=~"$namespace"}[5m])) / sum by (cluster_name) (rate(envoy_cluster_upstream_rq_total{namespace=~"$namespace"}[5m]))`, Legend: "{{cluster_name}}"}},
+			Thresholds:  lowerIsBetter(0.05, 0.1),
+			Group:       "Retries",
+			requires:    []string{"envoy_cluster_upstream_rq_retry", "envoy_cluster_upstream_rq_total"},
+		},
+		{
+			Title:       "Retry overflow by upstream cluster",
+			Type:        "timeseries",
+			Description: "Retries per second dropped because the cluster's retry budget or circuit breaker was exhausted.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: `sum by (cluster_name) (rate(envoy_cluster_upstream_rq_retry_overflow{namespace=~"$namespace"}[5m]))`, Legend: "{{cluster_name}}"}},
+			Group:       "Retries",
+			requires:    []string{"envoy_cluster_upstream_rq_retry_overflow"},
+		},
+	},
+}
@@ -0,0 +1,148 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CategoryErrorRatio marks success/error ratio suggestions
+const CategoryErrorRatio = "error_ratio"
+
+// outcomeLabels lists labels that carry a request outcome, in order of preference
+var outcomeLabels = []string{"status", "code", "status_code", "http_status_code", "response_code", "grpc_code", "outcome", "result"}
+
+// grpcServerErrorCodes are the gRPC status codes that indicate a server-side failure
+var grpcServerErrorCodes = []string{"Unknown", "DeadlineExceeded", "Unimplemented", "Internal", "Unavailable", "DataLoss"}
+
+var (
+	httpStatusPattern      = regexp.MustCompile(`^[1-5][0-9][0-9]$`)
+	httpStatusClassPattern = regexp.MustCompile(`^[1-5]xx$`)
+	failureValuePattern    = regexp.MustCompile(`(?i)(error|fail|exception|server_error|timeout)`)
+)
+
+// isOutcomeLabel reports whether a label carries request outcomes
+func isOutcomeLabel(label string) bool {
+	return slices.Contains(outcomeLabels, label)
+}
+
+// errorMatcher builds the label matcher selecting failed requests for a
+// metric, based on the outcome label it actually carries and, when known, the
+// values observed for that label. It returns an empty string when the metric
+// has no outcome label or no value of a free-form outcome label indicates a
+// failure.
+func errorMatcher(metricInfo *MetricInfo) (string, bool) {
+	for _, label := range outcomeLabels {
+		if !slices.Contains(metricInfo.Labels, label) {
+			continue
+		}
+
+		values, known := metricInfo.LabelValues[label]
+		if !known {
+			switch label {
+			case "grpc_code":
+				return grpcErrorMatcher(label), true
+			case "outcome", "result":
+				return fmt.Sprintf(`%s=~"(?i).*(error|fail).*"`, label), true
+			default:
+				return fmt.Sprintf(`%s=~"5.."`, label), true
+			}
+		}
+
+		if matcher := errorMatcherFromValues(label, values); matcher != "" {
+			return matcher, true
+		}
+	}
+
+	return "", false
+}
+
+// errorMatcherFromValues derives the failure matcher from observed label
+// values. Once the values reveal the label's convention (HTTP status codes,
+// status classes or gRPC codes) its failure matcher is used even if no
+// failure was observed yet, so healthy services still get an error ratio.
+func errorMatcherFromValues(label string, values []string) string {
+	switch {
+	case anyMatch(values, httpStatusPattern):
+		return fmt.Sprintf(`%s=~"5.."`, label)
+	case anyMatch(values, httpStatusClassPattern):
+		return fmt.Sprintf(`%s="5xx"`, label)
+	case slices.Contains(values, "OK"):
+		return grpcErrorMatcher(label)
+	}
+
+	var failures []string
+	for _, v := range values {
+		if failureValuePattern.MatchString(v) {
+			failures = append(failures, v)
+		}
+	}
+	// Mirror the 5xx-only HTTP matcher: when server-side failures are
+	// distinguishable, client errors are not counted
+	var serverFailures []string
+	for _, v := range failures {
+		if strings.Contains(strings.ToLower(v), "server") {
+			serverFailures = append(serverFailures, v)
+		}
+	}
+	if len(serverFailures) > 0 {
+		failures = serverFailures
+	}
+
+	switch len(failures) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(`%s=%q`, label, failures[0])
+	default:
+		patterns := make([]string, len(failures))
+		for i, v := range failures {
+			patterns[i] = regexp.QuoteMeta(v)
+		}
+		return fmt.Sprintf(`%s=~%q`, label, strings.Join(patterns, "|"))
+	}
+}
+
+// grpcErrorMatcher selects the gRPC codes of server-side failures
+func grpcErrorMatcher(label string) string {
+	return fmt.Sprintf(`%s=~"%s"`, label, strings.Join(grpcServerErrorCodes, "|"))
+}
+
+// generateErrorRatioQueries builds success and error ratio suggestions for a
+// request counter from its real outcome label
+func generateErrorRatioQueries(metricInfo *MetricInfo, counterName, window string) []QuerySuggestion {
+	matcher, ok := errorMatcher(metricInfo)
+	if !ok {
+		return nil
+	}
+
+	errorRate := fmt.Sprintf("sum(rate(%s{%s}[%s]))", counterName, matcher, window)
+	totalRate := fmt.Sprintf("sum(rate(%s[%s]))", counterName, window)
+
+	return []QuerySuggestion{
+		{
+			Query:             fmt.Sprintf("%s / %s", errorRate, totalRate),
+			Description:       fmt.Sprintf("Error ratio (%s)", matcher),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "ratio",
+			Category:          CategoryErrorRatio,
+		},
+		{
+			Query:             fmt.Sprintf("1 - (%s / %s)", errorRate, totalRate),
+			Description:       fmt.Sprintf("Success ratio (requests not matching %s)", matcher),
+			VisualizationType: "stat",
+			YAxisLabel:        "ratio",
+			Category:          CategoryErrorRatio,
+		},
+	}
+}
+
+func anyMatch(values []string, pattern *regexp.Regexp) bool {
+	for _, v := range values {
+		if pattern.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
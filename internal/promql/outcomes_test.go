@@ -0,0 +1,129 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestErrorMatcher(t *testing.T) {
+	tests := []struct {
+		name       string
+		metricInfo *MetricInfo
+		want       string
+		wantOK     bool
+	}{
+		{
+			name: "numeric http code label",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"code", "method"},
+				LabelValues: map[string][]string{"code": {"200", "404", "503"}},
+			},
+			want:   `code=~"5.."`,
+			wantOK: true,
+		},
+		{
+			name: "http status class values",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"status"},
+				LabelValues: map[string][]string{"status": {"2xx", "4xx", "5xx"}},
+			},
+			want:   `status="5xx"`,
+			wantOK: true,
+		},
+		{
+			name: "no server errors observed",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"status"},
+				LabelValues: map[string][]string{"status": {"200", "404"}},
+			},
+			want:   `status=~"5.."`,
+			wantOK: true,
+		},
+		{
+			name: "healthy status classes",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"status"},
+				LabelValues: map[string][]string{"status": {"2xx", "3xx"}},
+			},
+			want:   `status="5xx"`,
+			wantOK: true,
+		},
+		{
+			name: "grpc codes",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"grpc_code"},
+				LabelValues: map[string][]string{"grpc_code": {"OK", "NotFound"}},
+			},
+			want:   `grpc_code=~"Unknown|DeadlineExceeded|Unimplemented|Internal|Unavailable|DataLoss"`,
+			wantOK: true,
+		},
+		{
+			name: "spring outcome label",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"outcome", "uri"},
+				LabelValues: map[string][]string{"outcome": {"SUCCESS", "CLIENT_ERROR", "SERVER_ERROR"}},
+			},
+			want:   `outcome="SERVER_ERROR"`,
+			wantOK: true,
+		},
+		{
+			name: "regex characters in outcome values",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"result"},
+				LabelValues: map[string][]string{"result": {"ok", "server.error", "server.timeout"}},
+			},
+			want:   `result=~"server\\.error|server\\.timeout"`,
+			wantOK: true,
+		},
+		{
+			name: "no failure among free-form outcomes",
+			metricInfo: &MetricInfo{
+				Labels:      []string{"outcome"},
+				LabelValues: map[string][]string{"outcome": {"SUCCESS"}},
+			},
+			wantOK: false,
+		},
+		{
+			name:       "grpc code without observed values",
+			metricInfo: &MetricInfo{Labels: []string{"grpc_code"}},
+			want:       `grpc_code=~"Unknown|DeadlineExceeded|Unimplemented|Internal|Unavailable|DataLoss"`,
+			wantOK:     true,
+		},
+		{
+			name:       "no outcome label",
+			metricInfo: &MetricInfo{Labels: []string{"method", "instance"}},
+			wantOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := errorMatcher(tt.metricInfo)
+			if ok != tt.wantOK {
+				t.Fatalf("errorMatcher() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("errorMatcher() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCounterQueries_ErrorRatio(t *testing.T) {
+	metricInfo := &MetricInfo{
+		Name:        "grpc_server_handled_total",
+		Type:        MetricTypeCounter,
+		Labels:      []string{"grpc_code", "grpc_method"},
+		LabelValues: map[string][]string{"grpc_code": {"OK", "Unavailable"}},
+	}
+
+	want := `sum(rate(grpc_server_handled_total{grpc_code=~"Unknown|DeadlineExceeded|Unimplemented|Internal|Unavailable|DataLoss"}[5m])) / sum(rate(grpc_server_handled_total[5m]))`
+	for _, s := range generateCounterQueries(metricInfo) {
+		if s.Query == want {
+			if s.Category != CategoryErrorRatio {
+				t.Errorf("Expected error_ratio category, got %q", s.Category)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected error ratio query %s", want)
+}
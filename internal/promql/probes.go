@@ -0,0 +1,94 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Probe metrics of blackbox_exporter and Synthetic Monitoring whose raw
+// values make poor panels: a Unix timestamp and a 0/1 result
+const (
+	probeSSLExpiryMetric = "probe_ssl_earliest_cert_expiry"
+	probeSuccessMetric   = "probe_success"
+)
+
+// certificateDaysLeft is the countdown to the expiry timestamp of a
+// certificate metric, in days, per value of by
+func certificateDaysLeft(metric, by string) string {
+	return fmt.Sprintf("min by (%s) ((%s - time()) / 86400)", by, metric)
+}
+
+// uptimeRatio is the share of successful probes over a window per value of by
+func uptimeRatio(metric, by, window string) string {
+	return fmt.Sprintf("avg by (%s) (avg_over_time(%s[%s]))", by, metric, window)
+}
+
+// countdownThresholds colour a number of days left: red within a week,
+// orange within a month
+func countdownThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "red"},
+		{Value: floatPtr(7), Color: "orange"},
+		{Value: floatPtr(30), Color: "green"},
+	}
+}
+
+// uptimeThresholds colour an uptime ratio: red below 99%, orange below 99.9%
+func uptimeThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "red"},
+		{Value: floatPtr(0.99), Color: "orange"},
+		{Value: floatPtr(0.999), Color: "green"},
+	}
+}
+
+// applyProbeQueries puts the expressions probe metrics need ahead of the
+// generic suggestions: a days-left countdown for certificate expiry
+// timestamps and uptime percentages for probe results
+func applyProbeQueries(metricInfo *MetricInfo, suggestions []QuerySuggestion) []QuerySuggestion {
+	by := "instance"
+	if len(metricInfo.Labels) > 0 && !slices.Contains(metricInfo.Labels, by) {
+		by = "job"
+	}
+
+	switch metricInfo.Name {
+	case probeSSLExpiryMetric:
+		return append([]QuerySuggestion{
+			{
+				Query:             certificateDaysLeft(metricInfo.Name, by),
+				Description:       "Days until the earliest certificate in the chain expires",
+				VisualizationType: "stat",
+				YAxisLabel:        "days left",
+				Unit:              "d",
+				Thresholds:        countdownThresholds(),
+			},
+			{
+				Query:             fmt.Sprintf("count((%s - time()) / 86400 < 30) or vector(0)", metricInfo.Name),
+				Description:       "Targets whose certificate expires within 30 days",
+				VisualizationType: "stat",
+				YAxisLabel:        "targets",
+				Unit:              "short",
+				Thresholds:        []Threshold{{Value: nil, Color: "green"}, {Value: floatPtr(1), Color: "orange"}},
+			},
+		}, suggestions...)
+	case probeSuccessMetric:
+		return append([]QuerySuggestion{
+			{
+				Query:             uptimeRatio(metricInfo.Name, by, "1d"),
+				Description:       "Uptime over the last 24 hours",
+				VisualizationType: "stat",
+				YAxisLabel:        "uptime",
+				Unit:              "percentunit",
+				Thresholds:        uptimeThresholds(),
+			},
+			{
+				Query:             uptimeRatio(metricInfo.Name, by, formatDuration(rateWindow(metricInfo))),
+				Description:       "Share of successful probes",
+				VisualizationType: "timeseries",
+				YAxisLabel:        "uptime",
+				Unit:              "percentunit",
+			},
+		}, suggestions...)
+	}
+	return suggestions
+}
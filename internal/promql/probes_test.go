@@ -0,0 +1,42 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestGenerateQueries_Probes(t *testing.T) {
+	t.Run("certificate expiry countdown", func(t *testing.T) {
+		expiry := &MetricInfo{Name: "probe_ssl_earliest_cert_expiry", Type: MetricTypeGauge, Labels: []string{"instance", "job"}}
+		suggestions := generateQueries(expiry, GenerateOptions{})
+
+		best := getBestQuery(suggestions)
+		if best.Query != "min by (instance) ((probe_ssl_earliest_cert_expiry - time()) / 86400)" {
+			t.Errorf("Expected the countdown as the best query, got %s", best.Query)
+		}
+		if best.VisualizationType != "stat" || best.Unit != "d" {
+			t.Errorf("Expected a stat in days, got %s %s", best.VisualizationType, best.Unit)
+		}
+		if len(best.Thresholds) != 3 || *best.Thresholds[1].Value != 7 || *best.Thresholds[2].Value != 30 {
+			t.Errorf("Expected countdown thresholds at 7 and 30 days, got %+v", best.Thresholds)
+		}
+	})
+
+	t.Run("probe uptime", func(t *testing.T) {
+		success := &MetricInfo{Name: "probe_success", Type: MetricTypeGauge, Labels: []string{"instance", "job"}, ScrapeInterval: "30s"}
+		suggestions := generateQueries(success, GenerateOptions{})
+
+		if suggestions[0].Query != "avg by (instance) (avg_over_time(probe_success[1d]))" || suggestions[0].Unit != "percentunit" {
+			t.Errorf("Expected the 24h uptime first, got %+v", suggestions[0])
+		}
+		if suggestions[1].DashboardQuery != "avg by (instance) (avg_over_time(probe_success[$__rate_interval]))" {
+			t.Errorf("Expected the uptime over the rate interval, got %s", suggestions[1].DashboardQuery)
+		}
+	})
+
+	t.Run("without an instance label", func(t *testing.T) {
+		success := &MetricInfo{Name: "probe_success", Type: MetricTypeGauge, Labels: []string{"job"}}
+		if query := generateQueries(success, GenerateOptions{})[0].Query; query != "avg by (job) (avg_over_time(probe_success[1d]))" {
+			t.Errorf("Expected the uptime by job, got %s", query)
+		}
+	})
+}
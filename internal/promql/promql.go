@@ -2,10 +2,17 @@ package promql
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
 
 	zap "go.uber.org/zap"
 
 	config "github.com/inference-gateway/grafana-agent/config"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
 )
 
 //go:generate go tool counterfeiter -generate
@@ -15,45 +22,142 @@ import (
 //
 //counterfeiter:generate . PromQL
 type PromQL interface {
-	// DiscoverMetrics discovers all available metrics from Prometheus with optional filtering
-	DiscoverMetrics(ctx context.Context, prometheusURL, namePattern string, metricType MetricType) ([]MetricInfo, error)
+	// DiscoverMetrics discovers all available metrics from Prometheus with optional filtering,
+	// optionally scoped to the series matching a selector
+	DiscoverMetrics(ctx context.Context, prometheusURL string, namePattern *regexp.Regexp, metricType MetricType, selector string) ([]MetricInfo, error)
 
 	// GetMetricMetadata fetches metadata for a specific metric from Prometheus
 	GetMetricMetadata(ctx context.Context, prometheusURL, metricName string) (*MetricInfo, error)
 
 	// GenerateQueries generates appropriate PromQL queries based on metric type and name
-	GenerateQueries(metricInfo *MetricInfo) []QuerySuggestion
+	GenerateQueries(metricInfo *MetricInfo, opts GenerateOptions) []QuerySuggestion
 
 	// ValidateQuery validates a PromQL query against Prometheus
 	ValidateQuery(ctx context.Context, prometheusURL, query string) error
 
 	// GetBestQuery selects the most appropriate query for visualization
 	GetBestQuery(suggestions []QuerySuggestion) QuerySuggestion
+
+	// QueryRange executes a range query against Prometheus and returns the resulting series
+	QueryRange(ctx context.Context, prometheusURL, query string, start, end time.Time, step time.Duration) ([]Series, error)
+
+	// Query executes an instant query against Prometheus at the given time
+	// and returns the resulting series, each with a single sample
+	Query(ctx context.Context, prometheusURL, query string, at time.Time) ([]Series, error)
+
+	// GetLabelValues fetches the values of a label, optionally scoped by series matchers
+	GetLabelValues(ctx context.Context, prometheusURL, label string, matchers []string) ([]string, error)
+
+	// SuggestQueryFixes proposes ranked structured repairs for an invalid query
+	SuggestQueryFixes(query string) []QueryFix
+
+	// Series returns the label sets of the series matching any of the
+	// matchers that have samples between start and end
+	Series(ctx context.Context, prometheusURL string, matchers []string, start, end time.Time) ([]map[string]string, error)
+
+	// ScrapeTarget fetches and parses the exposition of an exporter's
+	// /metrics endpoint (text format or OpenMetrics)
+	ScrapeTarget(ctx context.Context, metricsURL string) ([]exposition.Family, error)
+
+	// DetectServer identifies the server type and version from its build
+	// information and flags, and the query features it supports
+	DetectServer(ctx context.Context, prometheusURL string) (*ServerInfo, error)
+
+	// GetSeriesCounts returns the number of active series per metric name
+	GetSeriesCounts(ctx context.Context, prometheusURL string) (map[string]int, error)
+
+	// GetTSDBStats returns the cardinality report of the server's head block
+	GetTSDBStats(ctx context.Context, prometheusURL string) (*TSDBStats, error)
+
+	// GenerateAlertRules suggests absent() and counter-reset alert rule candidates for a metric
+	GenerateAlertRules(metricInfo *MetricInfo) []AlertRuleCandidate
 }
 
 // promqlImpl is the implementation of PromQL
 type promqlImpl struct {
-	logger *zap.Logger
+	logger    *zap.Logger
+	transport http.RoundTripper
+	// scrapeTransport reaches exporters directly, without the Prometheus
+	// circuit breaker and Grafana credentials
+	scrapeTransport http.RoundTripper
+	// grafanaURL, datasourceUID and apiKey are set when queries are routed
+	// through a Grafana Prometheus datasource (PROMETHEUS_VIA_GRAFANA)
+	grafanaURL    string
+	datasourceUID string
+	apiKey        string
+	// replicaLabels tell the replicas of a Prometheus HA pair apart in
+	// series that carry both (PROMETHEUS_REPLICA_LABELS)
+	replicaLabels []string
+	// intervals is shared by the clients of every request, so the scrape
+	// intervals of a build's metrics are resolved from one target list
+	intervals *targetIntervals
 }
 
-// NewPromQLService creates a new instance of PromQL
-func NewPromQLService(logger *zap.Logger, cfg *config.Config) (PromQL, error) {
+// NewPromQLService creates a new instance of PromQL sending requests
+// through transport, shared with the other services; nil uses
+// http.DefaultTransport
+func NewPromQLService(logger *zap.Logger, cfg *config.Config, transport http.RoundTripper) (PromQL, error) {
 	logger.Info("initializing promql service")
 
-	return &promqlImpl{
-		logger: logger,
-	}, nil
+	breaker := circuit.NewBreaker(logger, "prometheus", cfg.Circuit.FailureThreshold, cfg.Circuit.Cooldown)
+	failover, err := newFailoverTransport(logger, breaker.Transport(grafana.NewGzipTransport(transport)), cfg.Prometheus.ReplicaURLs)
+	if err != nil {
+		return nil, err
+	}
+	impl := &promqlImpl{
+		logger:          logger,
+		transport:       failover,
+		scrapeTransport: transport,
+		replicaLabels:   cfg.Prometheus.ReplicaLabels,
+		intervals:       newTargetIntervals(targetIntervalsTTL),
+	}
+
+	if cfg.Prometheus.ViaGrafana {
+		if cfg.Grafana.URL == "" || cfg.Prometheus.DatasourceUID == "" {
+			return nil, fmt.Errorf("PROMETHEUS_VIA_GRAFANA requires GRAFANA_URL and PROMETHEUS_DATASOURCE_UID")
+		}
+		transport, err := grafana.NewOAuthTransport(&cfg.Grafana, impl.scrapeTransport, impl.transport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure grafana oauth: %w", err)
+		}
+		impl.transport = transport
+		impl.grafanaURL = cfg.Grafana.URL
+		impl.datasourceUID = cfg.Prometheus.DatasourceUID
+		impl.apiKey = cfg.Grafana.APIKey
+
+		logger.Info("routing prometheus queries through grafana",
+			zap.String("grafana_url", impl.grafanaURL),
+			zap.String("datasource_uid", impl.datasourceUID))
+	}
+
+	return impl, nil
+}
+
+// newClient creates the client for a request: direct to prometheusURL, or
+// through the Grafana datasource when configured, in which case
+// prometheusURL is ignored
+func (p *promqlImpl) newClient(prometheusURL string) *prometheusClient {
+	var client *prometheusClient
+	if p.datasourceUID != "" {
+		client = newDatasourceClient(p.grafanaURL, p.datasourceUID, p.apiKey, p.transport)
+	} else {
+		client = newPrometheusClient(prometheusURL, p.transport)
+	}
+	client.intervals = p.intervals
+	return client
 }
 
-// DiscoverMetrics discovers all available metrics from Prometheus with optional filtering
-func (p *promqlImpl) DiscoverMetrics(ctx context.Context, prometheusURL, namePattern string, metricType MetricType) ([]MetricInfo, error) {
+// DiscoverMetrics discovers all available metrics from Prometheus with optional filtering,
+// optionally scoped to the series matching a selector
+func (p *promqlImpl) DiscoverMetrics(ctx context.Context, prometheusURL string, namePattern *regexp.Regexp, metricType MetricType, selector string) ([]MetricInfo, error) {
 	p.logger.Debug("discovering metrics",
 		zap.String("prometheus_url", prometheusURL),
-		zap.String("name_pattern", namePattern),
-		zap.String("metric_type", string(metricType)))
+		zap.Stringer("name_pattern", namePattern),
+		zap.String("metric_type", string(metricType)),
+		zap.String("selector", selector))
 
-	client := newPrometheusClient(prometheusURL)
-	return client.discoverMetrics(ctx, namePattern, metricType)
+	client := p.newClient(prometheusURL)
+	return client.discoverMetrics(ctx, namePattern, metricType, selector)
 }
 
 // GetMetricMetadata fetches metadata for a specific metric from Prometheus
@@ -62,17 +166,21 @@ func (p *promqlImpl) GetMetricMetadata(ctx context.Context, prometheusURL, metri
 		zap.String("metric", metricName),
 		zap.String("prometheus_url", prometheusURL))
 
-	client := newPrometheusClient(prometheusURL)
+	client := p.newClient(prometheusURL)
 	return client.getMetricMetadata(ctx, metricName)
 }
 
 // GenerateQueries generates appropriate PromQL queries based on metric type and name
-func (p *promqlImpl) GenerateQueries(metricInfo *MetricInfo) []QuerySuggestion {
+func (p *promqlImpl) GenerateQueries(metricInfo *MetricInfo, opts GenerateOptions) []QuerySuggestion {
 	p.logger.Debug("generating queries",
 		zap.String("metric", metricInfo.Name),
-		zap.String("type", string(metricInfo.Type)))
+		zap.String("type", string(metricInfo.Type)),
+		zap.Any("options", opts))
 
-	return generateQueries(metricInfo)
+	if opts.ReplicaLabels == nil {
+		opts.ReplicaLabels = p.replicaLabels
+	}
+	return generateQueries(metricInfo, opts)
 }
 
 // ValidateQuery validates a PromQL query against Prometheus
@@ -81,7 +189,7 @@ func (p *promqlImpl) ValidateQuery(ctx context.Context, prometheusURL, query str
 		zap.String("query", query),
 		zap.String("prometheus_url", prometheusURL))
 
-	client := newPrometheusClient(prometheusURL)
+	client := p.newClient(prometheusURL)
 	return client.validateQuery(ctx, query)
 }
 
@@ -92,3 +200,118 @@ func (p *promqlImpl) GetBestQuery(suggestions []QuerySuggestion) QuerySuggestion
 
 	return getBestQuery(suggestions)
 }
+
+// QueryRange executes a range query against Prometheus and returns the resulting series
+func (p *promqlImpl) QueryRange(ctx context.Context, prometheusURL, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	p.logger.Debug("executing range query",
+		zap.String("query", query),
+		zap.String("prometheus_url", prometheusURL),
+		zap.Time("start", start),
+		zap.Time("end", end),
+		zap.Duration("step", step))
+
+	client := p.newClient(prometheusURL)
+	return client.queryRange(ctx, query, start, end, step)
+}
+
+// Query executes an instant query against Prometheus and returns the resulting series
+func (p *promqlImpl) Query(ctx context.Context, prometheusURL, query string, at time.Time) ([]Series, error) {
+	p.logger.Debug("executing instant query",
+		zap.String("query", query),
+		zap.String("prometheus_url", prometheusURL),
+		zap.Time("at", at))
+
+	client := p.newClient(prometheusURL)
+	return client.query(ctx, query, at)
+}
+
+// GetLabelValues fetches the values of a label, optionally scoped by series matchers
+func (p *promqlImpl) GetLabelValues(ctx context.Context, prometheusURL, label string, matchers []string) ([]string, error) {
+	p.logger.Debug("fetching label values",
+		zap.String("label", label),
+		zap.Strings("matchers", matchers),
+		zap.String("prometheus_url", prometheusURL))
+
+	client := p.newClient(prometheusURL)
+	return client.getLabelValues(ctx, label, matchers)
+}
+
+// Series returns the label sets of the series matching any of the matchers
+// that have samples between start and end
+func (p *promqlImpl) Series(ctx context.Context, prometheusURL string, matchers []string, start, end time.Time) ([]map[string]string, error) {
+	p.logger.Debug("fetching series",
+		zap.Strings("matchers", matchers),
+		zap.String("prometheus_url", prometheusURL),
+		zap.Time("start", start),
+		zap.Time("end", end))
+
+	client := p.newClient(prometheusURL)
+	return client.getSeries(ctx, matchers, start, end, 0)
+}
+
+// ScrapeTarget fetches and parses the exposition of an exporter's /metrics
+// endpoint
+func (p *promqlImpl) ScrapeTarget(ctx context.Context, metricsURL string) ([]exposition.Family, error) {
+	p.logger.Debug("scraping target", zap.String("metrics_url", metricsURL))
+	return scrapeTarget(ctx, p.scrapeTransport, metricsURL)
+}
+
+// DetectServer identifies the server type and version and the query features
+// it supports. Servers that do not expose their flags are classified from the
+// build information alone.
+func (p *promqlImpl) DetectServer(ctx context.Context, prometheusURL string) (*ServerInfo, error) {
+	client := p.newClient(prometheusURL)
+	build, err := client.getBuildInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect server: %w", err)
+	}
+
+	flags, err := client.getFlags(ctx)
+	if err != nil {
+		p.logger.Debug("server flags unavailable",
+			zap.String("prometheus_url", prometheusURL),
+			zap.Error(err))
+	}
+
+	info := detectServer(build, flags)
+	p.logger.Debug("detected server",
+		zap.String("prometheus_url", prometheusURL),
+		zap.String("type", string(info.Type)),
+		zap.String("version", info.Version))
+	return info, nil
+}
+
+// GetSeriesCounts returns the number of active series per metric name
+func (p *promqlImpl) GetSeriesCounts(ctx context.Context, prometheusURL string) (map[string]int, error) {
+	p.logger.Debug("counting series per metric",
+		zap.String("prometheus_url", prometheusURL))
+
+	client := p.newClient(prometheusURL)
+	return client.getSeriesCounts(ctx)
+}
+
+// GetTSDBStats returns the cardinality report of the server's head block
+func (p *promqlImpl) GetTSDBStats(ctx context.Context, prometheusURL string) (*TSDBStats, error) {
+	p.logger.Debug("fetching tsdb stats",
+		zap.String("prometheus_url", prometheusURL))
+
+	client := p.newClient(prometheusURL)
+	return client.getTSDBStats(ctx)
+}
+
+// SuggestQueryFixes proposes ranked structured repairs for an invalid query
+func (p *promqlImpl) SuggestQueryFixes(query string) []QueryFix {
+	p.logger.Debug("suggesting query fixes",
+		zap.String("query", query))
+
+	return suggestFixes(query)
+}
+
+// GenerateAlertRules suggests absent() and counter-reset alert rule candidates for a metric
+func (p *promqlImpl) GenerateAlertRules(metricInfo *MetricInfo) []AlertRuleCandidate {
+	p.logger.Debug("generating alert rule candidates",
+		zap.String("metric", metricInfo.Name),
+		zap.String("type", string(metricInfo.Type)))
+
+	return generateAlertRules(metricInfo)
+}
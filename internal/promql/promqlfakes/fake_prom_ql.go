@@ -3,19 +3,37 @@ package promqlfakes
 
 import (
 	"context"
+	"regexp"
 	"sync"
+	"time"
 
+	"github.com/inference-gateway/grafana-agent/internal/exposition"
 	"github.com/inference-gateway/grafana-agent/internal/promql"
 )
 
 type FakePromQL struct {
-	DiscoverMetricsStub        func(context.Context, string, string, promql.MetricType) ([]promql.MetricInfo, error)
+	DetectServerStub        func(context.Context, string) (*promql.ServerInfo, error)
+	detectServerMutex       sync.RWMutex
+	detectServerArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	detectServerReturns struct {
+		result1 *promql.ServerInfo
+		result2 error
+	}
+	detectServerReturnsOnCall map[int]struct {
+		result1 *promql.ServerInfo
+		result2 error
+	}
+	DiscoverMetricsStub        func(context.Context, string, *regexp.Regexp, promql.MetricType, string) ([]promql.MetricInfo, error)
 	discoverMetricsMutex       sync.RWMutex
 	discoverMetricsArgsForCall []struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 *regexp.Regexp
 		arg4 promql.MetricType
+		arg5 string
 	}
 	discoverMetricsReturns struct {
 		result1 []promql.MetricInfo
@@ -25,10 +43,22 @@ type FakePromQL struct {
 		result1 []promql.MetricInfo
 		result2 error
 	}
-	GenerateQueriesStub        func(*promql.MetricInfo) []promql.QuerySuggestion
+	GenerateAlertRulesStub        func(*promql.MetricInfo) []promql.AlertRuleCandidate
+	generateAlertRulesMutex       sync.RWMutex
+	generateAlertRulesArgsForCall []struct {
+		arg1 *promql.MetricInfo
+	}
+	generateAlertRulesReturns struct {
+		result1 []promql.AlertRuleCandidate
+	}
+	generateAlertRulesReturnsOnCall map[int]struct {
+		result1 []promql.AlertRuleCandidate
+	}
+	GenerateQueriesStub        func(*promql.MetricInfo, promql.GenerateOptions) []promql.QuerySuggestion
 	generateQueriesMutex       sync.RWMutex
 	generateQueriesArgsForCall []struct {
 		arg1 *promql.MetricInfo
+		arg2 promql.GenerateOptions
 	}
 	generateQueriesReturns struct {
 		result1 []promql.QuerySuggestion
@@ -47,6 +77,22 @@ type FakePromQL struct {
 	getBestQueryReturnsOnCall map[int]struct {
 		result1 promql.QuerySuggestion
 	}
+	GetLabelValuesStub        func(context.Context, string, string, []string) ([]string, error)
+	getLabelValuesMutex       sync.RWMutex
+	getLabelValuesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 []string
+	}
+	getLabelValuesReturns struct {
+		result1 []string
+		result2 error
+	}
+	getLabelValuesReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	GetMetricMetadataStub        func(context.Context, string, string) (*promql.MetricInfo, error)
 	getMetricMetadataMutex       sync.RWMutex
 	getMetricMetadataArgsForCall []struct {
@@ -62,6 +108,110 @@ type FakePromQL struct {
 		result1 *promql.MetricInfo
 		result2 error
 	}
+	GetSeriesCountsStub        func(context.Context, string) (map[string]int, error)
+	getSeriesCountsMutex       sync.RWMutex
+	getSeriesCountsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getSeriesCountsReturns struct {
+		result1 map[string]int
+		result2 error
+	}
+	getSeriesCountsReturnsOnCall map[int]struct {
+		result1 map[string]int
+		result2 error
+	}
+	GetTSDBStatsStub        func(context.Context, string) (*promql.TSDBStats, error)
+	getTSDBStatsMutex       sync.RWMutex
+	getTSDBStatsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getTSDBStatsReturns struct {
+		result1 *promql.TSDBStats
+		result2 error
+	}
+	getTSDBStatsReturnsOnCall map[int]struct {
+		result1 *promql.TSDBStats
+		result2 error
+	}
+	QueryStub        func(context.Context, string, string, time.Time) ([]promql.Series, error)
+	queryMutex       sync.RWMutex
+	queryArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}
+	queryReturns struct {
+		result1 []promql.Series
+		result2 error
+	}
+	queryReturnsOnCall map[int]struct {
+		result1 []promql.Series
+		result2 error
+	}
+	QueryRangeStub        func(context.Context, string, string, time.Time, time.Time, time.Duration) ([]promql.Series, error)
+	queryRangeMutex       sync.RWMutex
+	queryRangeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 time.Time
+		arg6 time.Duration
+	}
+	queryRangeReturns struct {
+		result1 []promql.Series
+		result2 error
+	}
+	queryRangeReturnsOnCall map[int]struct {
+		result1 []promql.Series
+		result2 error
+	}
+	ScrapeTargetStub        func(context.Context, string) ([]exposition.Family, error)
+	scrapeTargetMutex       sync.RWMutex
+	scrapeTargetArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	scrapeTargetReturns struct {
+		result1 []exposition.Family
+		result2 error
+	}
+	scrapeTargetReturnsOnCall map[int]struct {
+		result1 []exposition.Family
+		result2 error
+	}
+	SeriesStub        func(context.Context, string, []string, time.Time, time.Time) ([]map[string]string, error)
+	seriesMutex       sync.RWMutex
+	seriesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 time.Time
+		arg5 time.Time
+	}
+	seriesReturns struct {
+		result1 []map[string]string
+		result2 error
+	}
+	seriesReturnsOnCall map[int]struct {
+		result1 []map[string]string
+		result2 error
+	}
+	SuggestQueryFixesStub        func(string) []promql.QueryFix
+	suggestQueryFixesMutex       sync.RWMutex
+	suggestQueryFixesArgsForCall []struct {
+		arg1 string
+	}
+	suggestQueryFixesReturns struct {
+		result1 []promql.QueryFix
+	}
+	suggestQueryFixesReturnsOnCall map[int]struct {
+		result1 []promql.QueryFix
+	}
 	ValidateQueryStub        func(context.Context, string, string) error
 	validateQueryMutex       sync.RWMutex
 	validateQueryArgsForCall []struct {
@@ -79,21 +229,87 @@ type FakePromQL struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakePromQL) DiscoverMetrics(arg1 context.Context, arg2 string, arg3 string, arg4 promql.MetricType) ([]promql.MetricInfo, error) {
+func (fake *FakePromQL) DetectServer(arg1 context.Context, arg2 string) (*promql.ServerInfo, error) {
+	fake.detectServerMutex.Lock()
+	ret, specificReturn := fake.detectServerReturnsOnCall[len(fake.detectServerArgsForCall)]
+	fake.detectServerArgsForCall = append(fake.detectServerArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DetectServerStub
+	fakeReturns := fake.detectServerReturns
+	fake.recordInvocation("DetectServer", []interface{}{arg1, arg2})
+	fake.detectServerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) DetectServerCallCount() int {
+	fake.detectServerMutex.RLock()
+	defer fake.detectServerMutex.RUnlock()
+	return len(fake.detectServerArgsForCall)
+}
+
+func (fake *FakePromQL) DetectServerCalls(stub func(context.Context, string) (*promql.ServerInfo, error)) {
+	fake.detectServerMutex.Lock()
+	defer fake.detectServerMutex.Unlock()
+	fake.DetectServerStub = stub
+}
+
+func (fake *FakePromQL) DetectServerArgsForCall(i int) (context.Context, string) {
+	fake.detectServerMutex.RLock()
+	defer fake.detectServerMutex.RUnlock()
+	argsForCall := fake.detectServerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromQL) DetectServerReturns(result1 *promql.ServerInfo, result2 error) {
+	fake.detectServerMutex.Lock()
+	defer fake.detectServerMutex.Unlock()
+	fake.DetectServerStub = nil
+	fake.detectServerReturns = struct {
+		result1 *promql.ServerInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) DetectServerReturnsOnCall(i int, result1 *promql.ServerInfo, result2 error) {
+	fake.detectServerMutex.Lock()
+	defer fake.detectServerMutex.Unlock()
+	fake.DetectServerStub = nil
+	if fake.detectServerReturnsOnCall == nil {
+		fake.detectServerReturnsOnCall = make(map[int]struct {
+			result1 *promql.ServerInfo
+			result2 error
+		})
+	}
+	fake.detectServerReturnsOnCall[i] = struct {
+		result1 *promql.ServerInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) DiscoverMetrics(arg1 context.Context, arg2 string, arg3 *regexp.Regexp, arg4 promql.MetricType, arg5 string) ([]promql.MetricInfo, error) {
 	fake.discoverMetricsMutex.Lock()
 	ret, specificReturn := fake.discoverMetricsReturnsOnCall[len(fake.discoverMetricsArgsForCall)]
 	fake.discoverMetricsArgsForCall = append(fake.discoverMetricsArgsForCall, struct {
 		arg1 context.Context
 		arg2 string
-		arg3 string
+		arg3 *regexp.Regexp
 		arg4 promql.MetricType
-	}{arg1, arg2, arg3, arg4})
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.DiscoverMetricsStub
 	fakeReturns := fake.discoverMetricsReturns
-	fake.recordInvocation("DiscoverMetrics", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("DiscoverMetrics", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.discoverMetricsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -107,17 +323,17 @@ func (fake *FakePromQL) DiscoverMetricsCallCount() int {
 	return len(fake.discoverMetricsArgsForCall)
 }
 
-func (fake *FakePromQL) DiscoverMetricsCalls(stub func(context.Context, string, string, promql.MetricType) ([]promql.MetricInfo, error)) {
+func (fake *FakePromQL) DiscoverMetricsCalls(stub func(context.Context, string, *regexp.Regexp, promql.MetricType, string) ([]promql.MetricInfo, error)) {
 	fake.discoverMetricsMutex.Lock()
 	defer fake.discoverMetricsMutex.Unlock()
 	fake.DiscoverMetricsStub = stub
 }
 
-func (fake *FakePromQL) DiscoverMetricsArgsForCall(i int) (context.Context, string, string, promql.MetricType) {
+func (fake *FakePromQL) DiscoverMetricsArgsForCall(i int) (context.Context, string, *regexp.Regexp, promql.MetricType, string) {
 	fake.discoverMetricsMutex.RLock()
 	defer fake.discoverMetricsMutex.RUnlock()
 	argsForCall := fake.discoverMetricsArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakePromQL) DiscoverMetricsReturns(result1 []promql.MetricInfo, result2 error) {
@@ -146,18 +362,80 @@ func (fake *FakePromQL) DiscoverMetricsReturnsOnCall(i int, result1 []promql.Met
 	}{result1, result2}
 }
 
-func (fake *FakePromQL) GenerateQueries(arg1 *promql.MetricInfo) []promql.QuerySuggestion {
+func (fake *FakePromQL) GenerateAlertRules(arg1 *promql.MetricInfo) []promql.AlertRuleCandidate {
+	fake.generateAlertRulesMutex.Lock()
+	ret, specificReturn := fake.generateAlertRulesReturnsOnCall[len(fake.generateAlertRulesArgsForCall)]
+	fake.generateAlertRulesArgsForCall = append(fake.generateAlertRulesArgsForCall, struct {
+		arg1 *promql.MetricInfo
+	}{arg1})
+	stub := fake.GenerateAlertRulesStub
+	fakeReturns := fake.generateAlertRulesReturns
+	fake.recordInvocation("GenerateAlertRules", []interface{}{arg1})
+	fake.generateAlertRulesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromQL) GenerateAlertRulesCallCount() int {
+	fake.generateAlertRulesMutex.RLock()
+	defer fake.generateAlertRulesMutex.RUnlock()
+	return len(fake.generateAlertRulesArgsForCall)
+}
+
+func (fake *FakePromQL) GenerateAlertRulesCalls(stub func(*promql.MetricInfo) []promql.AlertRuleCandidate) {
+	fake.generateAlertRulesMutex.Lock()
+	defer fake.generateAlertRulesMutex.Unlock()
+	fake.GenerateAlertRulesStub = stub
+}
+
+func (fake *FakePromQL) GenerateAlertRulesArgsForCall(i int) *promql.MetricInfo {
+	fake.generateAlertRulesMutex.RLock()
+	defer fake.generateAlertRulesMutex.RUnlock()
+	argsForCall := fake.generateAlertRulesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePromQL) GenerateAlertRulesReturns(result1 []promql.AlertRuleCandidate) {
+	fake.generateAlertRulesMutex.Lock()
+	defer fake.generateAlertRulesMutex.Unlock()
+	fake.GenerateAlertRulesStub = nil
+	fake.generateAlertRulesReturns = struct {
+		result1 []promql.AlertRuleCandidate
+	}{result1}
+}
+
+func (fake *FakePromQL) GenerateAlertRulesReturnsOnCall(i int, result1 []promql.AlertRuleCandidate) {
+	fake.generateAlertRulesMutex.Lock()
+	defer fake.generateAlertRulesMutex.Unlock()
+	fake.GenerateAlertRulesStub = nil
+	if fake.generateAlertRulesReturnsOnCall == nil {
+		fake.generateAlertRulesReturnsOnCall = make(map[int]struct {
+			result1 []promql.AlertRuleCandidate
+		})
+	}
+	fake.generateAlertRulesReturnsOnCall[i] = struct {
+		result1 []promql.AlertRuleCandidate
+	}{result1}
+}
+
+func (fake *FakePromQL) GenerateQueries(arg1 *promql.MetricInfo, arg2 promql.GenerateOptions) []promql.QuerySuggestion {
 	fake.generateQueriesMutex.Lock()
 	ret, specificReturn := fake.generateQueriesReturnsOnCall[len(fake.generateQueriesArgsForCall)]
 	fake.generateQueriesArgsForCall = append(fake.generateQueriesArgsForCall, struct {
 		arg1 *promql.MetricInfo
-	}{arg1})
+		arg2 promql.GenerateOptions
+	}{arg1, arg2})
 	stub := fake.GenerateQueriesStub
 	fakeReturns := fake.generateQueriesReturns
-	fake.recordInvocation("GenerateQueries", []interface{}{arg1})
+	fake.recordInvocation("GenerateQueries", []interface{}{arg1, arg2})
 	fake.generateQueriesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -171,17 +449,17 @@ func (fake *FakePromQL) GenerateQueriesCallCount() int {
 	return len(fake.generateQueriesArgsForCall)
 }
 
-func (fake *FakePromQL) GenerateQueriesCalls(stub func(*promql.MetricInfo) []promql.QuerySuggestion) {
+func (fake *FakePromQL) GenerateQueriesCalls(stub func(*promql.MetricInfo, promql.GenerateOptions) []promql.QuerySuggestion) {
 	fake.generateQueriesMutex.Lock()
 	defer fake.generateQueriesMutex.Unlock()
 	fake.GenerateQueriesStub = stub
 }
 
-func (fake *FakePromQL) GenerateQueriesArgsForCall(i int) *promql.MetricInfo {
+func (fake *FakePromQL) GenerateQueriesArgsForCall(i int) (*promql.MetricInfo, promql.GenerateOptions) {
 	fake.generateQueriesMutex.RLock()
 	defer fake.generateQueriesMutex.RUnlock()
 	argsForCall := fake.generateQueriesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakePromQL) GenerateQueriesReturns(result1 []promql.QuerySuggestion) {
@@ -273,6 +551,78 @@ func (fake *FakePromQL) GetBestQueryReturnsOnCall(i int, result1 promql.QuerySug
 	}{result1}
 }
 
+func (fake *FakePromQL) GetLabelValues(arg1 context.Context, arg2 string, arg3 string, arg4 []string) ([]string, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.getLabelValuesMutex.Lock()
+	ret, specificReturn := fake.getLabelValuesReturnsOnCall[len(fake.getLabelValuesArgsForCall)]
+	fake.getLabelValuesArgsForCall = append(fake.getLabelValuesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 []string
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.GetLabelValuesStub
+	fakeReturns := fake.getLabelValuesReturns
+	fake.recordInvocation("GetLabelValues", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.getLabelValuesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) GetLabelValuesCallCount() int {
+	fake.getLabelValuesMutex.RLock()
+	defer fake.getLabelValuesMutex.RUnlock()
+	return len(fake.getLabelValuesArgsForCall)
+}
+
+func (fake *FakePromQL) GetLabelValuesCalls(stub func(context.Context, string, string, []string) ([]string, error)) {
+	fake.getLabelValuesMutex.Lock()
+	defer fake.getLabelValuesMutex.Unlock()
+	fake.GetLabelValuesStub = stub
+}
+
+func (fake *FakePromQL) GetLabelValuesArgsForCall(i int) (context.Context, string, string, []string) {
+	fake.getLabelValuesMutex.RLock()
+	defer fake.getLabelValuesMutex.RUnlock()
+	argsForCall := fake.getLabelValuesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePromQL) GetLabelValuesReturns(result1 []string, result2 error) {
+	fake.getLabelValuesMutex.Lock()
+	defer fake.getLabelValuesMutex.Unlock()
+	fake.GetLabelValuesStub = nil
+	fake.getLabelValuesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) GetLabelValuesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.getLabelValuesMutex.Lock()
+	defer fake.getLabelValuesMutex.Unlock()
+	fake.GetLabelValuesStub = nil
+	if fake.getLabelValuesReturnsOnCall == nil {
+		fake.getLabelValuesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.getLabelValuesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePromQL) GetMetricMetadata(arg1 context.Context, arg2 string, arg3 string) (*promql.MetricInfo, error) {
 	fake.getMetricMetadataMutex.Lock()
 	ret, specificReturn := fake.getMetricMetadataReturnsOnCall[len(fake.getMetricMetadataArgsForCall)]
@@ -339,6 +689,471 @@ func (fake *FakePromQL) GetMetricMetadataReturnsOnCall(i int, result1 *promql.Me
 	}{result1, result2}
 }
 
+func (fake *FakePromQL) GetSeriesCounts(arg1 context.Context, arg2 string) (map[string]int, error) {
+	fake.getSeriesCountsMutex.Lock()
+	ret, specificReturn := fake.getSeriesCountsReturnsOnCall[len(fake.getSeriesCountsArgsForCall)]
+	fake.getSeriesCountsArgsForCall = append(fake.getSeriesCountsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetSeriesCountsStub
+	fakeReturns := fake.getSeriesCountsReturns
+	fake.recordInvocation("GetSeriesCounts", []interface{}{arg1, arg2})
+	fake.getSeriesCountsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) GetSeriesCountsCallCount() int {
+	fake.getSeriesCountsMutex.RLock()
+	defer fake.getSeriesCountsMutex.RUnlock()
+	return len(fake.getSeriesCountsArgsForCall)
+}
+
+func (fake *FakePromQL) GetSeriesCountsCalls(stub func(context.Context, string) (map[string]int, error)) {
+	fake.getSeriesCountsMutex.Lock()
+	defer fake.getSeriesCountsMutex.Unlock()
+	fake.GetSeriesCountsStub = stub
+}
+
+func (fake *FakePromQL) GetSeriesCountsArgsForCall(i int) (context.Context, string) {
+	fake.getSeriesCountsMutex.RLock()
+	defer fake.getSeriesCountsMutex.RUnlock()
+	argsForCall := fake.getSeriesCountsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromQL) GetSeriesCountsReturns(result1 map[string]int, result2 error) {
+	fake.getSeriesCountsMutex.Lock()
+	defer fake.getSeriesCountsMutex.Unlock()
+	fake.GetSeriesCountsStub = nil
+	fake.getSeriesCountsReturns = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) GetSeriesCountsReturnsOnCall(i int, result1 map[string]int, result2 error) {
+	fake.getSeriesCountsMutex.Lock()
+	defer fake.getSeriesCountsMutex.Unlock()
+	fake.GetSeriesCountsStub = nil
+	if fake.getSeriesCountsReturnsOnCall == nil {
+		fake.getSeriesCountsReturnsOnCall = make(map[int]struct {
+			result1 map[string]int
+			result2 error
+		})
+	}
+	fake.getSeriesCountsReturnsOnCall[i] = struct {
+		result1 map[string]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) GetTSDBStats(arg1 context.Context, arg2 string) (*promql.TSDBStats, error) {
+	fake.getTSDBStatsMutex.Lock()
+	ret, specificReturn := fake.getTSDBStatsReturnsOnCall[len(fake.getTSDBStatsArgsForCall)]
+	fake.getTSDBStatsArgsForCall = append(fake.getTSDBStatsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetTSDBStatsStub
+	fakeReturns := fake.getTSDBStatsReturns
+	fake.recordInvocation("GetTSDBStats", []interface{}{arg1, arg2})
+	fake.getTSDBStatsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) GetTSDBStatsCallCount() int {
+	fake.getTSDBStatsMutex.RLock()
+	defer fake.getTSDBStatsMutex.RUnlock()
+	return len(fake.getTSDBStatsArgsForCall)
+}
+
+func (fake *FakePromQL) GetTSDBStatsCalls(stub func(context.Context, string) (*promql.TSDBStats, error)) {
+	fake.getTSDBStatsMutex.Lock()
+	defer fake.getTSDBStatsMutex.Unlock()
+	fake.GetTSDBStatsStub = stub
+}
+
+func (fake *FakePromQL) GetTSDBStatsArgsForCall(i int) (context.Context, string) {
+	fake.getTSDBStatsMutex.RLock()
+	defer fake.getTSDBStatsMutex.RUnlock()
+	argsForCall := fake.getTSDBStatsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromQL) GetTSDBStatsReturns(result1 *promql.TSDBStats, result2 error) {
+	fake.getTSDBStatsMutex.Lock()
+	defer fake.getTSDBStatsMutex.Unlock()
+	fake.GetTSDBStatsStub = nil
+	fake.getTSDBStatsReturns = struct {
+		result1 *promql.TSDBStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) GetTSDBStatsReturnsOnCall(i int, result1 *promql.TSDBStats, result2 error) {
+	fake.getTSDBStatsMutex.Lock()
+	defer fake.getTSDBStatsMutex.Unlock()
+	fake.GetTSDBStatsStub = nil
+	if fake.getTSDBStatsReturnsOnCall == nil {
+		fake.getTSDBStatsReturnsOnCall = make(map[int]struct {
+			result1 *promql.TSDBStats
+			result2 error
+		})
+	}
+	fake.getTSDBStatsReturnsOnCall[i] = struct {
+		result1 *promql.TSDBStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) Query(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time) ([]promql.Series, error) {
+	fake.queryMutex.Lock()
+	ret, specificReturn := fake.queryReturnsOnCall[len(fake.queryArgsForCall)]
+	fake.queryArgsForCall = append(fake.queryArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.QueryStub
+	fakeReturns := fake.queryReturns
+	fake.recordInvocation("Query", []interface{}{arg1, arg2, arg3, arg4})
+	fake.queryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) QueryCallCount() int {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	return len(fake.queryArgsForCall)
+}
+
+func (fake *FakePromQL) QueryCalls(stub func(context.Context, string, string, time.Time) ([]promql.Series, error)) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = stub
+}
+
+func (fake *FakePromQL) QueryArgsForCall(i int) (context.Context, string, string, time.Time) {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	argsForCall := fake.queryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePromQL) QueryReturns(result1 []promql.Series, result2 error) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = nil
+	fake.queryReturns = struct {
+		result1 []promql.Series
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) QueryReturnsOnCall(i int, result1 []promql.Series, result2 error) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = nil
+	if fake.queryReturnsOnCall == nil {
+		fake.queryReturnsOnCall = make(map[int]struct {
+			result1 []promql.Series
+			result2 error
+		})
+	}
+	fake.queryReturnsOnCall[i] = struct {
+		result1 []promql.Series
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) QueryRange(arg1 context.Context, arg2 string, arg3 string, arg4 time.Time, arg5 time.Time, arg6 time.Duration) ([]promql.Series, error) {
+	fake.queryRangeMutex.Lock()
+	ret, specificReturn := fake.queryRangeReturnsOnCall[len(fake.queryRangeArgsForCall)]
+	fake.queryRangeArgsForCall = append(fake.queryRangeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Time
+		arg5 time.Time
+		arg6 time.Duration
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.QueryRangeStub
+	fakeReturns := fake.queryRangeReturns
+	fake.recordInvocation("QueryRange", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.queryRangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) QueryRangeCallCount() int {
+	fake.queryRangeMutex.RLock()
+	defer fake.queryRangeMutex.RUnlock()
+	return len(fake.queryRangeArgsForCall)
+}
+
+func (fake *FakePromQL) QueryRangeCalls(stub func(context.Context, string, string, time.Time, time.Time, time.Duration) ([]promql.Series, error)) {
+	fake.queryRangeMutex.Lock()
+	defer fake.queryRangeMutex.Unlock()
+	fake.QueryRangeStub = stub
+}
+
+func (fake *FakePromQL) QueryRangeArgsForCall(i int) (context.Context, string, string, time.Time, time.Time, time.Duration) {
+	fake.queryRangeMutex.RLock()
+	defer fake.queryRangeMutex.RUnlock()
+	argsForCall := fake.queryRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakePromQL) QueryRangeReturns(result1 []promql.Series, result2 error) {
+	fake.queryRangeMutex.Lock()
+	defer fake.queryRangeMutex.Unlock()
+	fake.QueryRangeStub = nil
+	fake.queryRangeReturns = struct {
+		result1 []promql.Series
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) QueryRangeReturnsOnCall(i int, result1 []promql.Series, result2 error) {
+	fake.queryRangeMutex.Lock()
+	defer fake.queryRangeMutex.Unlock()
+	fake.QueryRangeStub = nil
+	if fake.queryRangeReturnsOnCall == nil {
+		fake.queryRangeReturnsOnCall = make(map[int]struct {
+			result1 []promql.Series
+			result2 error
+		})
+	}
+	fake.queryRangeReturnsOnCall[i] = struct {
+		result1 []promql.Series
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) ScrapeTarget(arg1 context.Context, arg2 string) ([]exposition.Family, error) {
+	fake.scrapeTargetMutex.Lock()
+	ret, specificReturn := fake.scrapeTargetReturnsOnCall[len(fake.scrapeTargetArgsForCall)]
+	fake.scrapeTargetArgsForCall = append(fake.scrapeTargetArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ScrapeTargetStub
+	fakeReturns := fake.scrapeTargetReturns
+	fake.recordInvocation("ScrapeTarget", []interface{}{arg1, arg2})
+	fake.scrapeTargetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) ScrapeTargetCallCount() int {
+	fake.scrapeTargetMutex.RLock()
+	defer fake.scrapeTargetMutex.RUnlock()
+	return len(fake.scrapeTargetArgsForCall)
+}
+
+func (fake *FakePromQL) ScrapeTargetCalls(stub func(context.Context, string) ([]exposition.Family, error)) {
+	fake.scrapeTargetMutex.Lock()
+	defer fake.scrapeTargetMutex.Unlock()
+	fake.ScrapeTargetStub = stub
+}
+
+func (fake *FakePromQL) ScrapeTargetArgsForCall(i int) (context.Context, string) {
+	fake.scrapeTargetMutex.RLock()
+	defer fake.scrapeTargetMutex.RUnlock()
+	argsForCall := fake.scrapeTargetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromQL) ScrapeTargetReturns(result1 []exposition.Family, result2 error) {
+	fake.scrapeTargetMutex.Lock()
+	defer fake.scrapeTargetMutex.Unlock()
+	fake.ScrapeTargetStub = nil
+	fake.scrapeTargetReturns = struct {
+		result1 []exposition.Family
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) ScrapeTargetReturnsOnCall(i int, result1 []exposition.Family, result2 error) {
+	fake.scrapeTargetMutex.Lock()
+	defer fake.scrapeTargetMutex.Unlock()
+	fake.ScrapeTargetStub = nil
+	if fake.scrapeTargetReturnsOnCall == nil {
+		fake.scrapeTargetReturnsOnCall = make(map[int]struct {
+			result1 []exposition.Family
+			result2 error
+		})
+	}
+	fake.scrapeTargetReturnsOnCall[i] = struct {
+		result1 []exposition.Family
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) Series(arg1 context.Context, arg2 string, arg3 []string, arg4 time.Time, arg5 time.Time) ([]map[string]string, error) {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.seriesMutex.Lock()
+	ret, specificReturn := fake.seriesReturnsOnCall[len(fake.seriesArgsForCall)]
+	fake.seriesArgsForCall = append(fake.seriesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 []string
+		arg4 time.Time
+		arg5 time.Time
+	}{arg1, arg2, arg3Copy, arg4, arg5})
+	stub := fake.SeriesStub
+	fakeReturns := fake.seriesReturns
+	fake.recordInvocation("Series", []interface{}{arg1, arg2, arg3Copy, arg4, arg5})
+	fake.seriesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromQL) SeriesCallCount() int {
+	fake.seriesMutex.RLock()
+	defer fake.seriesMutex.RUnlock()
+	return len(fake.seriesArgsForCall)
+}
+
+func (fake *FakePromQL) SeriesCalls(stub func(context.Context, string, []string, time.Time, time.Time) ([]map[string]string, error)) {
+	fake.seriesMutex.Lock()
+	defer fake.seriesMutex.Unlock()
+	fake.SeriesStub = stub
+}
+
+func (fake *FakePromQL) SeriesArgsForCall(i int) (context.Context, string, []string, time.Time, time.Time) {
+	fake.seriesMutex.RLock()
+	defer fake.seriesMutex.RUnlock()
+	argsForCall := fake.seriesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakePromQL) SeriesReturns(result1 []map[string]string, result2 error) {
+	fake.seriesMutex.Lock()
+	defer fake.seriesMutex.Unlock()
+	fake.SeriesStub = nil
+	fake.seriesReturns = struct {
+		result1 []map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) SeriesReturnsOnCall(i int, result1 []map[string]string, result2 error) {
+	fake.seriesMutex.Lock()
+	defer fake.seriesMutex.Unlock()
+	fake.SeriesStub = nil
+	if fake.seriesReturnsOnCall == nil {
+		fake.seriesReturnsOnCall = make(map[int]struct {
+			result1 []map[string]string
+			result2 error
+		})
+	}
+	fake.seriesReturnsOnCall[i] = struct {
+		result1 []map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromQL) SuggestQueryFixes(arg1 string) []promql.QueryFix {
+	fake.suggestQueryFixesMutex.Lock()
+	ret, specificReturn := fake.suggestQueryFixesReturnsOnCall[len(fake.suggestQueryFixesArgsForCall)]
+	fake.suggestQueryFixesArgsForCall = append(fake.suggestQueryFixesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SuggestQueryFixesStub
+	fakeReturns := fake.suggestQueryFixesReturns
+	fake.recordInvocation("SuggestQueryFixes", []interface{}{arg1})
+	fake.suggestQueryFixesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromQL) SuggestQueryFixesCallCount() int {
+	fake.suggestQueryFixesMutex.RLock()
+	defer fake.suggestQueryFixesMutex.RUnlock()
+	return len(fake.suggestQueryFixesArgsForCall)
+}
+
+func (fake *FakePromQL) SuggestQueryFixesCalls(stub func(string) []promql.QueryFix) {
+	fake.suggestQueryFixesMutex.Lock()
+	defer fake.suggestQueryFixesMutex.Unlock()
+	fake.SuggestQueryFixesStub = stub
+}
+
+func (fake *FakePromQL) SuggestQueryFixesArgsForCall(i int) string {
+	fake.suggestQueryFixesMutex.RLock()
+	defer fake.suggestQueryFixesMutex.RUnlock()
+	argsForCall := fake.suggestQueryFixesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePromQL) SuggestQueryFixesReturns(result1 []promql.QueryFix) {
+	fake.suggestQueryFixesMutex.Lock()
+	defer fake.suggestQueryFixesMutex.Unlock()
+	fake.SuggestQueryFixesStub = nil
+	fake.suggestQueryFixesReturns = struct {
+		result1 []promql.QueryFix
+	}{result1}
+}
+
+func (fake *FakePromQL) SuggestQueryFixesReturnsOnCall(i int, result1 []promql.QueryFix) {
+	fake.suggestQueryFixesMutex.Lock()
+	defer fake.suggestQueryFixesMutex.Unlock()
+	fake.SuggestQueryFixesStub = nil
+	if fake.suggestQueryFixesReturnsOnCall == nil {
+		fake.suggestQueryFixesReturnsOnCall = make(map[int]struct {
+			result1 []promql.QueryFix
+		})
+	}
+	fake.suggestQueryFixesReturnsOnCall[i] = struct {
+		result1 []promql.QueryFix
+	}{result1}
+}
+
 func (fake *FakePromQL) ValidateQuery(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.validateQueryMutex.Lock()
 	ret, specificReturn := fake.validateQueryReturnsOnCall[len(fake.validateQueryArgsForCall)]
@@ -405,14 +1220,34 @@ func (fake *FakePromQL) ValidateQueryReturnsOnCall(i int, result1 error) {
 func (fake *FakePromQL) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.detectServerMutex.RLock()
+	defer fake.detectServerMutex.RUnlock()
 	fake.discoverMetricsMutex.RLock()
 	defer fake.discoverMetricsMutex.RUnlock()
+	fake.generateAlertRulesMutex.RLock()
+	defer fake.generateAlertRulesMutex.RUnlock()
 	fake.generateQueriesMutex.RLock()
 	defer fake.generateQueriesMutex.RUnlock()
 	fake.getBestQueryMutex.RLock()
 	defer fake.getBestQueryMutex.RUnlock()
+	fake.getLabelValuesMutex.RLock()
+	defer fake.getLabelValuesMutex.RUnlock()
 	fake.getMetricMetadataMutex.RLock()
 	defer fake.getMetricMetadataMutex.RUnlock()
+	fake.getSeriesCountsMutex.RLock()
+	defer fake.getSeriesCountsMutex.RUnlock()
+	fake.getTSDBStatsMutex.RLock()
+	defer fake.getTSDBStatsMutex.RUnlock()
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	fake.queryRangeMutex.RLock()
+	defer fake.queryRangeMutex.RUnlock()
+	fake.scrapeTargetMutex.RLock()
+	defer fake.scrapeTargetMutex.RUnlock()
+	fake.seriesMutex.RLock()
+	defer fake.seriesMutex.RUnlock()
+	fake.suggestQueryFixesMutex.RLock()
+	defer fake.suggestQueryFixesMutex.RUnlock()
 	fake.validateQueryMutex.RLock()
 	defer fake.validateQueryMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
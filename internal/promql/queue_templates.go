@@ -0,0 +1,310 @@
+package promql
+
+import "fmt"
+
+// drainTime is the seconds a backlog takes to drain at the rate it is
+// consumed, both summed by the given labels. Consumer offsets and sequences
+// are gauges that only grow, so rate reads them like counters. A stalled
+// consumer divides by zero and shows as +Inf, which is what a backlog that
+// never drains looks like.
+func drainTime(backlog, consumed, by string) string {
+	return fmt.Sprintf("sum by (%s) (%s) / sum by (%s) (rate(%s[5m]))", by, backlog, by, consumed)
+}
+
+// drainThresholds turn a drain time orange at 5 minutes and red at 30
+func drainThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(300), Color: "orange"},
+		{Value: floatPtr(1800), Color: "red"},
+	}
+}
+
+// kafkaLagTemplate covers the consumer group metrics of kafka_exporter
+var kafkaLagTemplate = DashboardTemplate{
+	Name:        "kafka_lag",
+	Title:       "Kafka consumer lag",
+	Description: "Consumer group lag, time to drain, and consumption and production rates from kafka_exporter",
+	Signature:   []string{"kafka_consumergroup_lag"},
+	Breakdown:   []string{"consumergroup"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Total lag",
+			Type:        "stat",
+			Description: "Messages produced but not yet consumed, across all consumer groups.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(kafka_consumergroup_lag)"}},
+			requires:    []string{"kafka_consumergroup_lag"},
+		},
+		{
+			Title:       "Longest time to drain",
+			Type:        "stat",
+			Description: "Time the most backed up consumer group needs to catch up at its current consumption rate.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(" + drainTime("kafka_consumergroup_lag", "kafka_consumergroup_current_offset", "<by>, topic") + ")"}},
+			Thresholds:  drainThresholds(),
+			requires:    []string{"kafka_consumergroup_lag", "kafka_consumergroup_current_offset"},
+		},
+		{
+			Title:       "Lag by <by>",
+			Type:        "timeseries",
+			Description: "Consumer lag per consumer group and topic; lag that keeps growing means consumers cannot keep up.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>, topic) (kafka_consumergroup_lag)", Legend: "{{<by>}} {{topic}}"}},
+			Group:       "Lag",
+			requires:    []string{"kafka_consumergroup_lag"},
+		},
+		{
+			Title:       "Time to drain by <by>",
+			Type:        "timeseries",
+			Description: "Time each consumer group needs to catch up at its current consumption rate; +Inf when it has stopped consuming.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: drainTime("kafka_consumergroup_lag", "kafka_consumergroup_current_offset", "<by>, topic"), Legend: "{{<by>}} {{topic}}"}},
+			Group:       "Lag",
+			requires:    []string{"kafka_consumergroup_lag", "kafka_consumergroup_current_offset"},
+		},
+		{
+			Title:       "Lag by partition",
+			Type:        "bargauge",
+			Description: "Partitions with the most lag; lag concentrated on a few partitions points at a skewed key or a stuck consumer.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "topk(10, sum by (<by>, topic, partition) (kafka_consumergroup_lag))", Legend: "{{<by>}} {{topic}}/{{partition}}", Instant: true}},
+			Group:       "Lag",
+			requires:    []string{"kafka_consumergroup_lag"},
+		},
+		{
+			Title:       "Consumption rate by <by>",
+			Type:        "timeseries",
+			Description: "Messages consumed per second per consumer group and topic.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>, topic) (rate(kafka_consumergroup_current_offset[5m]))", Legend: "{{<by>}} {{topic}}"}},
+			Group:       "Throughput",
+			requires:    []string{"kafka_consumergroup_current_offset"},
+		},
+		{
+			Title:       "Production rate by topic",
+			Type:        "timeseries",
+			Description: "Messages produced per second per topic.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (topic) (rate(kafka_topic_partition_current_offset[5m]))", Legend: "{{topic}}"}},
+			Group:       "Throughput",
+			requires:    []string{"kafka_topic_partition_current_offset"},
+		},
+	},
+}
+
+// rabbitmqQueuesTemplate covers the queue metrics of RabbitMQ's built-in
+// Prometheus plugin and of rabbitmq_exporter. The plugin labels them by
+// queue only when per-object metrics are enabled, and counts acknowledgements
+// across all queues, so its time to drain is a single figure; where both
+// are scraped the exporter's per-queue panels come first.
+var rabbitmqQueuesTemplate = DashboardTemplate{
+	Name:        "rabbitmq_queues",
+	Title:       "RabbitMQ queues",
+	Description: "Queue depth, unacknowledged messages, consumers and time to drain from RabbitMQ's Prometheus plugin or rabbitmq_exporter",
+	Signature:   []string{"rabbitmq_queue_messages_ready"},
+	Breakdown:   []string{"queue", "instance"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Ready messages",
+			Type:        "stat",
+			Description: "Messages waiting to be delivered to a consumer.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(rabbitmq_queue_messages_ready)"}},
+			requires:    []string{"rabbitmq_queue_messages_ready"},
+		},
+		{
+			Title:       "Unacknowledged messages",
+			Type:        "stat",
+			Description: "Messages delivered to consumers but not yet acknowledged.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(rabbitmq_queue_messages_unacknowledged)"}},
+			requires:    []string{"rabbitmq_queue_messages_unacknowledged"},
+		},
+		{
+			Title:       "Unacknowledged messages",
+			Type:        "stat",
+			Description: "Messages delivered to consumers but not yet acknowledged.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(rabbitmq_queue_messages_unacked)"}},
+			requires:    []string{"rabbitmq_queue_messages_unacked"},
+		},
+		{
+			Title:       "Time to drain",
+			Type:        "stat",
+			Description: "Time the most backed up queue needs to empty at its current acknowledgement rate.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(" + drainTime("rabbitmq_queue_messages_ready", "rabbitmq_queue_messages_ack_total", "vhost, queue") + ")"}},
+			Thresholds:  drainThresholds(),
+			requires:    []string{"rabbitmq_queue_messages_ready", "rabbitmq_queue_messages_ack_total"},
+		},
+		{
+			Title:       "Time to drain",
+			Type:        "stat",
+			Description: "Time all ready messages need to drain at the current acknowledgement rate across queues.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "sum(rabbitmq_queue_messages_ready) / sum(rate(rabbitmq_global_messages_acknowledged_total[5m]))"}},
+			Thresholds:  drainThresholds(),
+			requires:    []string{"rabbitmq_queue_messages_ready", "rabbitmq_global_messages_acknowledged_total"},
+		},
+		{
+			Title:       "Queue depth by <by>",
+			Type:        "timeseries",
+			Description: "Ready messages per queue; a queue that keeps growing has too few or too slow consumers.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rabbitmq_queue_messages_ready)", Legend: "{{<by>}}"}},
+			Group:       "Queues",
+			requires:    []string{"rabbitmq_queue_messages_ready"},
+		},
+		{
+			Title:       "Unacknowledged by <by>",
+			Type:        "timeseries",
+			Description: "Unacknowledged messages per queue; they grow when consumers prefetch more than they process.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rabbitmq_queue_messages_unacknowledged)", Legend: "{{<by>}}"}},
+			Group:       "Queues",
+			requires:    []string{"rabbitmq_queue_messages_unacknowledged"},
+		},
+		{
+			Title:       "Unacknowledged by <by>",
+			Type:        "timeseries",
+			Description: "Unacknowledged messages per queue; they grow when consumers prefetch more than they process.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rabbitmq_queue_messages_unacked)", Legend: "{{<by>}}"}},
+			Group:       "Queues",
+			requires:    []string{"rabbitmq_queue_messages_unacked"},
+		},
+		{
+			Title:       "Time to drain by queue",
+			Type:        "timeseries",
+			Description: "Time each queue needs to empty at its current acknowledgement rate; +Inf when its consumers have stopped.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: drainTime("rabbitmq_queue_messages_ready", "rabbitmq_queue_messages_ack_total", "vhost, queue"), Legend: "{{vhost}} {{queue}}"}},
+			Group:       "Queues",
+			requires:    []string{"rabbitmq_queue_messages_ready", "rabbitmq_queue_messages_ack_total"},
+		},
+		{
+			Title:       "Consumers by <by>",
+			Type:        "timeseries",
+			Description: "Consumers attached to each queue; a queue without consumers only grows.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rabbitmq_queue_consumers)", Legend: "{{<by>}}"}},
+			Group:       "Consumers",
+			requires:    []string{"rabbitmq_queue_consumers"},
+		},
+		{
+			Title:       "Publish and acknowledge rates by <by>",
+			Type:        "timeseries",
+			Description: "Messages published to and acknowledged from each queue per second.",
+			Unit:        "ops",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (rate(rabbitmq_queue_messages_published_total[5m]))", Legend: "{{<by>}} published"},
+				{Expr: "sum by (<by>) (rate(rabbitmq_queue_messages_ack_total[5m]))", Legend: "{{<by>}} acknowledged"},
+			},
+			Group:    "Consumers",
+			requires: []string{"rabbitmq_queue_messages_published_total", "rabbitmq_queue_messages_ack_total"},
+		},
+		{
+			Title:       "Publish and acknowledge rates",
+			Type:        "timeseries",
+			Description: "Messages received from publishers and acknowledged by consumers per second.",
+			Unit:        "ops",
+			Queries: []TemplateQuery{
+				{Expr: "sum(rate(rabbitmq_global_messages_received_total[5m]))", Legend: "received"},
+				{Expr: "sum(rate(rabbitmq_global_messages_acknowledged_total[5m]))", Legend: "acknowledged"},
+			},
+			Group:    "Consumers",
+			requires: []string{"rabbitmq_global_messages_received_total", "rabbitmq_global_messages_acknowledged_total"},
+		},
+	},
+}
+
+// natsTemplate covers the JetStream consumer and core server metrics of
+// prometheus-nats-exporter. A consumer's ack floor is the last sequence
+// acknowledged in order, so its rate is the consumption rate.
+var natsTemplate = DashboardTemplate{
+	Name:        "nats",
+	Title:       "NATS",
+	Description: "JetStream consumer pending messages and time to drain, slow consumers and message rates from prometheus-nats-exporter",
+	Signature:   []string{"jetstream_consumer_num_pending", "gnatsd_varz_in_msgs"},
+	Breakdown:   []string{"server_id", "instance"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Pending messages",
+			Type:        "stat",
+			Description: "Stream messages not yet delivered to their JetStream consumers.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(jetstream_consumer_num_pending)"}},
+			requires:    []string{"jetstream_consumer_num_pending"},
+		},
+		{
+			Title:       "Longest time to drain",
+			Type:        "stat",
+			Description: "Time the most backed up JetStream consumer needs to catch up at its current acknowledgement rate.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "max(" + drainTime("jetstream_consumer_num_pending", "jetstream_consumer_ack_floor_consumer_seq", "stream_name, consumer_name") + ")"}},
+			Thresholds:  drainThresholds(),
+			requires:    []string{"jetstream_consumer_num_pending", "jetstream_consumer_ack_floor_consumer_seq"},
+		},
+		{
+			Title:       "Slow consumers",
+			Type:        "stat",
+			Description: "Clients the servers disconnected for not reading messages fast enough.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(gnatsd_varz_slow_consumers)"}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(1), Color: "red"},
+			},
+			requires: []string{"gnatsd_varz_slow_consumers"},
+		},
+		{
+			Title:       "Pending by consumer",
+			Type:        "timeseries",
+			Description: "Messages pending delivery per JetStream consumer.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (stream_name, consumer_name) (jetstream_consumer_num_pending)", Legend: "{{stream_name}} {{consumer_name}}"}},
+			Group:       "JetStream",
+			requires:    []string{"jetstream_consumer_num_pending"},
+		},
+		{
+			Title:       "Ack pending by consumer",
+			Type:        "timeseries",
+			Description: "Messages delivered but not yet acknowledged per JetStream consumer; they are redelivered once the ack wait expires.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (stream_name, consumer_name) (jetstream_consumer_num_ack_pending)", Legend: "{{stream_name}} {{consumer_name}}"}},
+			Group:       "JetStream",
+			requires:    []string{"jetstream_consumer_num_ack_pending"},
+		},
+		{
+			Title:       "Time to drain by consumer",
+			Type:        "timeseries",
+			Description: "Time each JetStream consumer needs to catch up at its current acknowledgement rate; +Inf when it has stopped acknowledging.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: drainTime("jetstream_consumer_num_pending", "jetstream_consumer_ack_floor_consumer_seq", "stream_name, consumer_name"), Legend: "{{stream_name}} {{consumer_name}}"}},
+			Group:       "JetStream",
+			requires:    []string{"jetstream_consumer_num_pending", "jetstream_consumer_ack_floor_consumer_seq"},
+		},
+		{
+			Title:       "Messages by <by>",
+			Type:        "timeseries",
+			Description: "Messages received and sent per second per server.",
+			Unit:        "ops",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (rate(gnatsd_varz_in_msgs[5m]))", Legend: "{{<by>}} in"},
+				{Expr: "sum by (<by>) (rate(gnatsd_varz_out_msgs[5m]))", Legend: "{{<by>}} out"},
+			},
+			Group:    "Servers",
+			requires: []string{"gnatsd_varz_in_msgs", "gnatsd_varz_out_msgs"},
+		},
+		{
+			Title:       "Pending bytes by <by>",
+			Type:        "timeseries",
+			Description: "Bytes buffered for clients per server; growth means clients are reading slower than messages arrive.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (gnatsd_connz_pending_bytes)", Legend: "{{<by>}}"}},
+			Group:       "Servers",
+			requires:    []string{"gnatsd_connz_pending_bytes"},
+		},
+	},
+}
@@ -0,0 +1,142 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultReplicaLabels are the labels Prometheus HA pairs commonly set as
+// external labels to tell their replicas apart
+var DefaultReplicaLabels = []string{"replica", "prometheus_replica"}
+
+// replicaModifier matches the offset and @ modifiers that follow a selector
+var replicaModifier = regexp.MustCompile(`^\s*(offset\s+-?[0-9a-z]+|@\s*(start\(\)|end\(\)|[0-9.]+))`)
+
+// replicaLabel returns the first of the replica labels a metric's series
+// carry with more than one value, i.e. the series of each HA replica side by
+// side
+func replicaLabel(metricInfo *MetricInfo, replicaLabels []string) (string, bool) {
+	for _, label := range replicaLabels {
+		if !slices.Contains(metricInfo.Labels, label) {
+			continue
+		}
+		if count, ok := metricInfo.LabelCardinality[label]; ok && count <= 1 {
+			continue
+		}
+		return label, true
+	}
+	return "", false
+}
+
+// DeduplicateReplicas keeps one replica's series wherever a query reads a
+// metric: each range function call such as rate() and each bare selector is
+// wrapped in `max without (<label>)`, so sums no longer count every sample
+// once per replica. Queries that already drop the label or pin a replica are
+// returned unchanged.
+func DeduplicateReplicas(query, label string) string {
+	if strings.Contains(query, "without ("+label+")") {
+		return query
+	}
+	selectors := ParseSelectors(query)
+	for _, selector := range selectors {
+		for _, matcher := range selector.Matchers {
+			if matcher.Name == label && matcher.Op == "=" {
+				return query
+			}
+		}
+	}
+
+	wrap := func(expr string) string {
+		return fmt.Sprintf("max without (%s) (%s)", label, expr)
+	}
+	// Rewrite from the end so earlier offsets stay valid; the range calls of
+	// several selectors may be the same one, e.g. a rate of a ratio
+	wrapped := -1
+	for i := len(selectors) - 1; i >= 0; i-- {
+		selector := selectors[i]
+		end := selector.End
+		if end < len(query) && query[end] == '[' {
+			start, close, ok := enclosingCall(query, selector.Start)
+			if !ok || start == wrapped {
+				continue
+			}
+			query = query[:start] + wrap(query[start:close+1]) + query[close+1:]
+			wrapped = start
+			continue
+		}
+		for {
+			modifier := replicaModifier.FindString(query[end:])
+			if modifier == "" {
+				break
+			}
+			end += len(modifier)
+		}
+		query = query[:selector.Start] + wrap(query[selector.Start:end]) + query[end:]
+	}
+	return query
+}
+
+// enclosingCall returns the offsets of the function name and closing
+// parenthesis of the call whose arguments include pos
+func enclosingCall(query string, pos int) (start, close int, ok bool) {
+	depth := 0
+	open := -1
+	for i := pos - 1; i >= 0; i-- {
+		switch query[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth == 0 {
+				open = i
+			} else {
+				depth--
+			}
+		}
+		if open >= 0 {
+			break
+		}
+	}
+	if open < 0 {
+		return 0, 0, false
+	}
+
+	start = open
+	for start > 0 && (query[start-1] == ' ' || isIdentChar(query[start-1])) {
+		start--
+	}
+	for start < open && query[start] == ' ' {
+		start++
+	}
+	if start == open {
+		return 0, 0, false
+	}
+
+	depth = 0
+	for i := open; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return start, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// applyReplicaDeduplication deduplicates the suggestions of a metric whose
+// series come from both replicas of an HA pair
+func applyReplicaDeduplication(metricInfo *MetricInfo, suggestions []QuerySuggestion, replicaLabels []string) []QuerySuggestion {
+	label, ok := replicaLabel(metricInfo, replicaLabels)
+	if !ok {
+		return suggestions
+	}
+	for i := range suggestions {
+		suggestions[i].Query = DeduplicateReplicas(suggestions[i].Query, label)
+	}
+	return suggestions
+}
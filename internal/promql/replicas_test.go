@@ -0,0 +1,47 @@
+package promql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeduplicateReplicas(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`sum by (job) (rate(http_requests_total[5m]))`, `sum by (job) (max without (replica) (rate(http_requests_total[5m])))`},
+		{`histogram_quantile(0.95, sum by (le) (rate(x_bucket{job="a"}[5m])))`, `histogram_quantile(0.95, sum by (le) (max without (replica) (rate(x_bucket{job="a"}[5m]))))`},
+		{`quantile_over_time(0.9, x[5m])`, `max without (replica) (quantile_over_time(0.9, x[5m]))`},
+		{`max_over_time(rate(x[5m])[1h:5m])`, `max_over_time(max without (replica) (rate(x[5m]))[1h:5m])`},
+		{`avg(x) - avg(x offset 1w)`, `avg(max without (replica) (x)) - avg(max without (replica) (x offset 1w))`},
+		{`topk(5, rate(x[5m] @ end()))`, `topk(5, max without (replica) (rate(x[5m] @ end())))`},
+		{`sum(up{replica="a"})`, `sum(up{replica="a"})`},
+		{`sum(max without (replica) (up))`, `sum(max without (replica) (up))`},
+	}
+	for _, tt := range tests {
+		if got := DeduplicateReplicas(tt.query, "replica"); got != tt.want {
+			t.Errorf("DeduplicateReplicas(%s) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateQueriesDeduplicatesReplicas(t *testing.T) {
+	opts := GenerateOptions{ReplicaLabels: DefaultReplicaLabels}
+	metricInfo := &MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter, Labels: []string{"job", "prometheus_replica"}}
+	for _, suggestion := range generateQueries(metricInfo, opts) {
+		if !strings.Contains(suggestion.Query, "max without (prometheus_replica)") {
+			t.Errorf("expected %s deduplicated", suggestion.Query)
+		}
+		if !strings.Contains(suggestion.DashboardQuery, "max without (prometheus_replica)") {
+			t.Errorf("expected dashboard query %s deduplicated", suggestion.DashboardQuery)
+		}
+	}
+
+	metricInfo.LabelCardinality = map[string]int{"prometheus_replica": 1}
+	for _, suggestion := range generateQueries(metricInfo, opts) {
+		if strings.Contains(suggestion.Query, "without (prometheus_replica)") {
+			t.Errorf("expected a single replica left alone, got %s", suggestion.Query)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package promql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const (
+	// resultSampleValues is how many series a result sample shows
+	resultSampleValues = 3
+	// maxPanelSeries is the series count above which a panel's lines or
+	// bars can no longer be told apart
+	maxPanelSeries = 50
+)
+
+// ResultSample summarizes what a query returns now: how many series and the
+// values of the first few, so the query returning a usable number of series
+// can be picked before building a panel on it
+type ResultSample struct {
+	Series int           `json:"series"`
+	Values []SampleValue `json:"values,omitempty"`
+	// Warning flags results a panel would show badly: no series at all, or
+	// too many to tell apart
+	Warning string `json:"warning,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SampleValue is the current value of one series of a query result. The
+// value is formatted as Prometheus does, so NaN and ±Inf survive JSON.
+type SampleValue struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  string            `json:"value"`
+}
+
+// SampleResult summarizes the series of an instant query, keeping the
+// first series ordered by their labels
+func SampleResult(series []Series) ResultSample {
+	sample := ResultSample{Series: len(series)}
+	switch {
+	case len(series) == 0:
+		sample.Warning = "returns no series at the moment; check the metric is scraped and the selectors match"
+	case len(series) > maxPanelSeries:
+		sample.Warning = fmt.Sprintf("returns %d series, too many to tell apart in a panel; aggregate by fewer labels or filter", len(series))
+	}
+
+	sorted := make([]Series, len(series))
+	copy(sorted, series)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return seriesKey(sorted[i].Labels) < seriesKey(sorted[j].Labels)
+	})
+	for _, s := range sorted {
+		if len(sample.Values) == resultSampleValues {
+			break
+		}
+		if len(s.Samples) == 0 {
+			continue
+		}
+		sample.Values = append(sample.Values, SampleValue{
+			Labels: s.Labels,
+			Value:  strconv.FormatFloat(s.Samples[len(s.Samples)-1].Value, 'g', -1, 64),
+		})
+	}
+	return sample
+}
+
+// seriesKey renders a label set in a stable order, for sorting series
+func seriesKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	key := ""
+	for _, name := range names {
+		key += name + "=" + labels[name] + ","
+	}
+	return key
+}
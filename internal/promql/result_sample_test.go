@@ -0,0 +1,47 @@
+package promql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSampleResult(t *testing.T) {
+	t.Run("keeps the first series by labels", func(t *testing.T) {
+		sample := SampleResult([]Series{
+			{Labels: map[string]string{"job": "web"}, Samples: []SamplePoint{{Value: 2}}},
+			{Labels: map[string]string{"job": "api"}, Samples: []SamplePoint{{Value: 1}, {Value: math.NaN()}}},
+			{Labels: map[string]string{"job": "db"}, Samples: []SamplePoint{{Value: 0.25}}},
+			{Labels: map[string]string{"job": "queue"}, Samples: []SamplePoint{{Value: math.Inf(1)}}},
+		})
+		if sample.Series != 4 || sample.Warning != "" {
+			t.Errorf("unexpected sample %+v", sample)
+		}
+		var values []string
+		for _, value := range sample.Values {
+			values = append(values, value.Labels["job"]+"="+value.Value)
+		}
+		if got := strings.Join(values, " "); got != "api=NaN db=0.25 queue=+Inf" {
+			t.Errorf("unexpected values %s", got)
+		}
+	})
+
+	t.Run("warns about empty results", func(t *testing.T) {
+		sample := SampleResult(nil)
+		if sample.Series != 0 || !strings.Contains(sample.Warning, "no series") {
+			t.Errorf("unexpected sample %+v", sample)
+		}
+	})
+
+	t.Run("warns about too many series", func(t *testing.T) {
+		series := make([]Series, maxPanelSeries+1)
+		for i := range series {
+			series[i] = Series{Labels: map[string]string{"pod": fmt.Sprint(i)}, Samples: []SamplePoint{{Value: 1}}}
+		}
+		sample := SampleResult(series)
+		if len(sample.Values) != resultSampleValues || !strings.Contains(sample.Warning, "51 series") {
+			t.Errorf("unexpected sample %+v", sample)
+		}
+	})
+}
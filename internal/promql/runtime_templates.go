@@ -0,0 +1,359 @@
+package promql
+
+// fileDescriptorPanels returns the file descriptor panels for the open and
+// maximum file descriptor metrics of a client library
+func fileDescriptorPanels(open, limit string) []TemplatePanel {
+	return []TemplatePanel{
+		{
+			Title:       "File descriptor usage",
+			Type:        "stat",
+			Description: "Highest share of the file descriptor limit in use; a process at its limit can no longer open connections or files.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(" + open + " / " + limit + ")"}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(0.8), Color: "orange"},
+				{Value: floatPtr(0.9), Color: "red"},
+			},
+			requires: []string{open, limit},
+		},
+		{
+			Title:       "Open file descriptors",
+			Type:        "timeseries",
+			Description: "Open file descriptors against the limit.",
+			Unit:        "short",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (" + open + ")", Legend: "{{<by>}}"},
+				{Expr: "min(" + limit + ")", Legend: "limit"},
+			},
+			Group:    "File descriptors",
+			requires: []string{open, limit},
+		},
+	}
+}
+
+// goRuntimeTemplate covers the go_* and process_* metrics client_golang
+// registers by default, broken down by pod, or by instance outside
+// Kubernetes
+var goRuntimeTemplate = DashboardTemplate{
+	Name:        "go_runtime",
+	Title:       "Go runtime",
+	Description: "Goroutines, OS threads, heap, allocation rate, GC pauses and file descriptors of Go services instrumented with client_golang",
+	Signature:   []string{"go_goroutines", "go_memstats_heap_inuse_bytes"},
+	Breakdown:   []string{"pod", "instance"},
+	panels: append([]TemplatePanel{
+		{
+			Title:       "Goroutines",
+			Type:        "stat",
+			Description: "Goroutines across all instances.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(go_goroutines)"}},
+			requires:    []string{"go_goroutines"},
+		},
+		{
+			Title:       "Heap in use",
+			Type:        "stat",
+			Description: "Heap memory in use across all instances.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: "sum(go_memstats_heap_inuse_bytes)"}},
+			requires:    []string{"go_memstats_heap_inuse_bytes"},
+		},
+		{
+			Title:       "Longest GC pause",
+			Type:        "stat",
+			Description: "Longest stop-the-world GC pause among the recent collections of any instance.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: `max(go_gc_duration_seconds{quantile="1"})`}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(0.01), Color: "orange"},
+				{Value: floatPtr(0.1), Color: "red"},
+			},
+			requires: []string{"go_gc_duration_seconds"},
+		},
+		{
+			Title:       "Goroutines by <by>",
+			Type:        "timeseries",
+			Description: "Goroutines per instance; steady growth without a matching rise in traffic points at a goroutine leak.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (go_goroutines)", Legend: "{{<by>}}"}},
+			Group:       "Goroutines and threads",
+			requires:    []string{"go_goroutines"},
+		},
+		{
+			Title:       "OS threads by <by>",
+			Type:        "timeseries",
+			Description: "OS threads created by the Go runtime per instance; they grow with goroutines blocked in system calls or cgo.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (go_threads)", Legend: "{{<by>}}"}},
+			Group:       "Goroutines and threads",
+			requires:    []string{"go_threads"},
+		},
+		{
+			Title:       "Heap by <by>",
+			Type:        "timeseries",
+			Description: "Heap memory in use per instance against the heap size that triggers the next GC.",
+			Unit:        "bytes",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (go_memstats_heap_inuse_bytes)", Legend: "{{<by>}} in use"},
+				{Expr: "sum by (<by>) (go_memstats_next_gc_bytes)", Legend: "{{<by>}} GC target"},
+			},
+			Group:    "Memory",
+			requires: []string{"go_memstats_heap_inuse_bytes", "go_memstats_next_gc_bytes"},
+		},
+		{
+			Title:       "Allocation rate by <by>",
+			Type:        "timeseries",
+			Description: "Bytes allocated on the heap per second; a higher allocation rate means more frequent GC cycles.",
+			Unit:        "Bps",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(go_memstats_alloc_bytes_total[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Memory",
+			requires:    []string{"go_memstats_alloc_bytes_total"},
+		},
+		{
+			Title:       "Resident memory by <by>",
+			Type:        "timeseries",
+			Description: "Resident memory of the process per instance, including memory the runtime has not returned to the OS.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (process_resident_memory_bytes)", Legend: "{{<by>}}"}},
+			Group:       "Memory",
+			requires:    []string{"process_resident_memory_bytes"},
+		},
+		{
+			Title:       "GC pause duration by <by>",
+			Type:        "timeseries",
+			Description: "Average and longest stop-the-world GC pause per instance.",
+			Unit:        "s",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (rate(go_gc_duration_seconds_sum[5m])) / sum by (<by>) (rate(go_gc_duration_seconds_count[5m]))", Legend: "{{<by>}} avg"},
+				{Expr: `max by (<by>) (go_gc_duration_seconds{quantile="1"})`, Legend: "{{<by>}} max"},
+			},
+			Group:    "Garbage collection",
+			requires: []string{"go_gc_duration_seconds", "go_gc_duration_seconds_sum", "go_gc_duration_seconds_count"},
+		},
+		{
+			Title:       "GC cycles by <by>",
+			Type:        "timeseries",
+			Description: "Garbage collections per second per instance.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>) (rate(go_gc_duration_seconds_count[5m]))", Legend: "{{<by>}}"}},
+			Group:       "Garbage collection",
+			requires:    []string{"go_gc_duration_seconds_count"},
+		},
+	}, fileDescriptorPanels("process_open_fds", "process_max_fds")...),
+	generic: true,
+}
+
+// jvmRuntimeTemplate covers the jvm_* metrics of the Prometheus Java client
+// (and jmx_exporter, which embeds it) and of Micrometer. Their panels share
+// titles; where both libraries' metrics are present the Java client's panel
+// comes first and Micrometer's is left out. Like goRuntimeTemplate it breaks
+// series down by pod, or by instance outside Kubernetes.
+var jvmRuntimeTemplate = DashboardTemplate{
+	Name:        "jvm_runtime",
+	Title:       "JVM runtime",
+	Description: "Heap, GC pauses, threads and file descriptors of JVM services instrumented with the Prometheus Java client, jmx_exporter or Micrometer",
+	Signature:   []string{"jvm_memory_bytes_used", "jvm_memory_used_bytes", "jvm_threads_current", "jvm_threads_live_threads"},
+	Breakdown:   []string{"pod", "instance"},
+	panels: append([]TemplatePanel{
+		{
+			Title:       "Heap usage",
+			Type:        "stat",
+			Description: "Highest share of the maximum heap in use on any instance.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: `max(sum by (instance) (jvm_memory_bytes_used{area="heap"}) / sum by (instance) (jvm_memory_bytes_max{area="heap"} > 0))`}},
+			Thresholds:  heapThresholds(),
+			requires:    []string{"jvm_memory_bytes_used", "jvm_memory_bytes_max"},
+		},
+		{
+			Title:       "Heap usage",
+			Type:        "stat",
+			Description: "Highest share of the maximum heap in use on any instance.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: `max(sum by (instance) (jvm_memory_used_bytes{area="heap"}) / sum by (instance) (jvm_memory_max_bytes{area="heap"} > 0))`}},
+			Thresholds:  heapThresholds(),
+			requires:    []string{"jvm_memory_used_bytes", "jvm_memory_max_bytes"},
+		},
+		{
+			Title:       "Time in GC",
+			Type:        "stat",
+			Description: "Highest share of wall-clock time any instance spent in garbage collection over the last 5 minutes.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(sum by (instance) (rate(jvm_gc_collection_seconds_sum[5m])))"}},
+			Thresholds:  gcTimeThresholds(),
+			requires:    []string{"jvm_gc_collection_seconds_sum"},
+		},
+		{
+			Title:       "Time in GC",
+			Type:        "stat",
+			Description: "Highest share of wall-clock time any instance spent in garbage collection over the last 5 minutes.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "max(sum by (instance) (rate(jvm_gc_pause_seconds_sum[5m])))"}},
+			Thresholds:  gcTimeThresholds(),
+			requires:    []string{"jvm_gc_pause_seconds_sum"},
+		},
+		{
+			Title:       "Live threads",
+			Type:        "stat",
+			Description: "Live threads across all instances.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(jvm_threads_current)"}},
+			requires:    []string{"jvm_threads_current"},
+		},
+		{
+			Title:       "Live threads",
+			Type:        "stat",
+			Description: "Live threads across all instances.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum(jvm_threads_live_threads)"}},
+			requires:    []string{"jvm_threads_live_threads"},
+		},
+		{
+			Title:       "Heap by <by>",
+			Type:        "timeseries",
+			Description: "Heap memory used per instance against the maximum heap.",
+			Unit:        "bytes",
+			Queries: []TemplateQuery{
+				{Expr: `sum by (<by>) (jvm_memory_bytes_used{area="heap"})`, Legend: "{{<by>}} used"},
+				{Expr: `sum by (<by>) (jvm_memory_bytes_max{area="heap"} > 0)`, Legend: "{{<by>}} max"},
+			},
+			Group:    "Memory",
+			requires: []string{"jvm_memory_bytes_used", "jvm_memory_bytes_max"},
+		},
+		{
+			Title:       "Heap by <by>",
+			Type:        "timeseries",
+			Description: "Heap memory used per instance against the maximum heap.",
+			Unit:        "bytes",
+			Queries: []TemplateQuery{
+				{Expr: `sum by (<by>) (jvm_memory_used_bytes{area="heap"})`, Legend: "{{<by>}} used"},
+				{Expr: `sum by (<by>) (jvm_memory_max_bytes{area="heap"} > 0)`, Legend: "{{<by>}} max"},
+			},
+			Group:    "Memory",
+			requires: []string{"jvm_memory_used_bytes", "jvm_memory_max_bytes"},
+		},
+		{
+			Title:       "Non-heap by <by>",
+			Type:        "timeseries",
+			Description: "Non-heap memory (metaspace, code cache) used per instance.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: `sum by (<by>) (jvm_memory_bytes_used{area="nonheap"})`, Legend: "{{<by>}}"}},
+			Group:       "Memory",
+			requires:    []string{"jvm_memory_bytes_used"},
+		},
+		{
+			Title:       "Non-heap by <by>",
+			Type:        "timeseries",
+			Description: "Non-heap memory (metaspace, code cache) used per instance.",
+			Unit:        "bytes",
+			Queries:     []TemplateQuery{{Expr: `sum by (<by>) (jvm_memory_used_bytes{area="nonheap"})`, Legend: "{{<by>}}"}},
+			Group:       "Memory",
+			requires:    []string{"jvm_memory_used_bytes"},
+		},
+		{
+			Title:       "GC pause duration by <by>",
+			Type:        "timeseries",
+			Description: "Average GC pause per instance and collector.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>, gc) (rate(jvm_gc_collection_seconds_sum[5m])) / sum by (<by>, gc) (rate(jvm_gc_collection_seconds_count[5m]))", Legend: "{{<by>}} {{gc}}"}},
+			Group:       "Garbage collection",
+			requires:    []string{"jvm_gc_collection_seconds_sum", "jvm_gc_collection_seconds_count"},
+		},
+		{
+			Title:       "GC pause duration by <by>",
+			Type:        "timeseries",
+			Description: "Average and longest GC pause per instance.",
+			Unit:        "s",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (rate(jvm_gc_pause_seconds_sum[5m])) / sum by (<by>) (rate(jvm_gc_pause_seconds_count[5m]))", Legend: "{{<by>}} avg"},
+				{Expr: "max by (<by>) (jvm_gc_pause_seconds_max)", Legend: "{{<by>}} max"},
+			},
+			Group:    "Garbage collection",
+			requires: []string{"jvm_gc_pause_seconds_sum", "jvm_gc_pause_seconds_count", "jvm_gc_pause_seconds_max"},
+		},
+		{
+			Title:       "GC collections by <by>",
+			Type:        "timeseries",
+			Description: "Garbage collections per second per instance and collector.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>, gc) (rate(jvm_gc_collection_seconds_count[5m]))", Legend: "{{<by>}} {{gc}}"}},
+			Group:       "Garbage collection",
+			requires:    []string{"jvm_gc_collection_seconds_count"},
+		},
+		{
+			Title:       "GC collections by <by>",
+			Type:        "timeseries",
+			Description: "Garbage collections per second per instance and cause.",
+			Unit:        "ops",
+			Queries:     []TemplateQuery{{Expr: "sum by (<by>, cause) (rate(jvm_gc_pause_seconds_count[5m]))", Legend: "{{<by>}} {{cause}}"}},
+			Group:       "Garbage collection",
+			requires:    []string{"jvm_gc_pause_seconds_count"},
+		},
+		{
+			Title:       "Threads by <by>",
+			Type:        "timeseries",
+			Description: "Live and daemon threads per instance; steady growth points at a thread leak or an unbounded pool.",
+			Unit:        "short",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (jvm_threads_current)", Legend: "{{<by>}} live"},
+				{Expr: "sum by (<by>) (jvm_threads_daemon)", Legend: "{{<by>}} daemon"},
+			},
+			Group:    "Threads",
+			requires: []string{"jvm_threads_current", "jvm_threads_daemon"},
+		},
+		{
+			Title:       "Threads by <by>",
+			Type:        "timeseries",
+			Description: "Live and daemon threads per instance; steady growth points at a thread leak or an unbounded pool.",
+			Unit:        "short",
+			Queries: []TemplateQuery{
+				{Expr: "sum by (<by>) (jvm_threads_live_threads)", Legend: "{{<by>}} live"},
+				{Expr: "sum by (<by>) (jvm_threads_daemon_threads)", Legend: "{{<by>}} daemon"},
+			},
+			Group:    "Threads",
+			requires: []string{"jvm_threads_live_threads", "jvm_threads_daemon_threads"},
+		},
+		{
+			Title:       "Thread states",
+			Type:        "timeseries",
+			Description: "Threads per state across all instances; a rise in blocked threads points at lock contention.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (state) (jvm_threads_state)", Legend: "{{state}}"}},
+			Group:       "Threads",
+			requires:    []string{"jvm_threads_state"},
+		},
+		{
+			Title:       "Thread states",
+			Type:        "timeseries",
+			Description: "Threads per state across all instances; a rise in blocked threads points at lock contention.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "sum by (state) (jvm_threads_states_threads)", Legend: "{{state}}"}},
+			Group:       "Threads",
+			requires:    []string{"jvm_threads_states_threads"},
+		},
+	}, append(
+		fileDescriptorPanels("process_open_fds", "process_max_fds"),
+		fileDescriptorPanels("process_files_open_files", "process_files_max_files")...,
+	)...),
+	generic: true,
+}
+
+// heapThresholds turn heap usage orange at 80% and red at 90% of the
+// maximum heap
+func heapThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(0.8), Color: "orange"},
+		{Value: floatPtr(0.9), Color: "red"},
+	}
+}
+
+// gcTimeThresholds turn the share of time spent in GC orange at 5% and red
+// at 10%
+func gcTimeThresholds() []Threshold {
+	return []Threshold{
+		{Value: nil, Color: "green"},
+		{Value: floatPtr(0.05), Color: "orange"},
+		{Value: floatPtr(0.1), Color: "red"},
+	}
+}
@@ -0,0 +1,133 @@
+package promql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+)
+
+// scrapeTimeout bounds fetching an exporter's /metrics endpoint
+const scrapeTimeout = 10 * time.Second
+
+// maxExpositionSize bounds the exposition read from an exporter
+const maxExpositionSize = 32 << 20
+
+// scrapeTarget fetches and parses the exposition of a /metrics endpoint
+func scrapeTarget(ctx context.Context, transport http.RoundTripper, metricsURL string) ([]exposition.Family, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", metricsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scrape request: %w", err)
+	}
+	req.Header.Set("Accept", exposition.AcceptHeader)
+
+	client := &http.Client{Timeout: scrapeTimeout, Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape %s: %w", metricsURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scrape of %s failed with status %d", metricsURL, resp.StatusCode)
+	}
+
+	families, err := exposition.Parse(io.LimitReader(resp.Body, maxExpositionSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the metrics of %s: %w", metricsURL, err)
+	}
+	return families, nil
+}
+
+// MetricInfoFromFamily converts a scraped metric family into the metric
+// metadata query generation works from. Families without a Prometheus type
+// are typed by name like metrics without metadata; label cardinality and
+// series count come from the scraped samples.
+func MetricInfoFromFamily(family exposition.Family) MetricInfo {
+	info := MetricInfo{
+		Name:        family.SeriesName(),
+		Help:        family.Help,
+		SeriesCount: len(family.Samples),
+	}
+
+	switch family.Type {
+	case "counter":
+		info.Type = MetricTypeCounter
+	case "gauge":
+		info.Type = MetricTypeGauge
+	case "histogram", "gaugehistogram":
+		info.Type = MetricTypeHistogram
+	case "summary":
+		info.Type = MetricTypeSummary
+	default:
+		info.Type = inferMetricType(info.Name)
+	}
+
+	values := family.LabelValues()
+	info.LabelCardinality = make(map[string]int, len(values))
+	for label, labelValues := range values {
+		// bucket bounds and quantiles are part of the type, not groupings
+		if label == "le" || label == "quantile" {
+			continue
+		}
+		info.Labels = append(info.Labels, label)
+		info.LabelCardinality[label] = len(labelValues)
+	}
+	sort.Strings(info.Labels)
+
+	return info
+}
+
+// DiscoverScrapedMetrics lists the metrics of a scraped endpoint, filtered
+// by name and type the way DiscoverMetrics filters a Prometheus server's, so
+// dashboards can be drafted before the service is scraped
+func DiscoverScrapedMetrics(families []exposition.Family, pattern *regexp.Regexp, metricType MetricType) []MetricInfo {
+	var results []MetricInfo
+	for _, family := range families {
+		info := MetricInfoFromFamily(family)
+		if pattern != nil && !pattern.MatchString(info.Name) {
+			continue
+		}
+		if metricType != "" && metricType != MetricTypeUnknown && info.Type != metricType {
+			continue
+		}
+		if info.Help == "" {
+			info.Help = "No metadata available"
+		}
+		if info.Labels == nil {
+			info.Labels = []string{}
+		}
+		results = append(results, info)
+	}
+	return results
+}
+
+// ScrapedMetricNames returns the names the scraped families are queried by
+func ScrapedMetricNames(families []exposition.Family) []string {
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.SeriesName())
+	}
+	return names
+}
+
+// runtimeMetricPrefixes are the client library metrics every instrumented
+// process exposes, beside the service's own metrics
+var runtimeMetricPrefixes = []string{"go_", "process_", "promhttp_", "jvm_", "python_", "nodejs_", "dotnet_"}
+
+// IsRuntimeMetric reports whether a metric comes from the client library
+// runtime instrumentation rather than the service itself
+func IsRuntimeMetric(name string) bool {
+	for _, prefix := range runtimeMetricPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package promql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+)
+
+func TestScrapeTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+			t.Errorf("expected OpenMetrics to be negotiated, got %q", r.Header.Get("Accept"))
+		}
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`# TYPE orders counter
+# HELP orders Orders placed.
+orders_total{status="ok"} 10
+orders_total{status="failed"} 1
+# TYPE order_duration_seconds histogram
+order_duration_seconds_bucket{le="0.5",region="eu"} 4
+order_duration_seconds_bucket{le="+Inf",region="eu"} 5
+order_duration_seconds_count{region="eu"} 5
+order_duration_seconds_sum{region="eu"} 1.2
+cache_errors 3
+# EOF
+`))
+	}))
+	defer server.Close()
+
+	svc, err := NewPromQLService(zap.NewNop(), &config.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	families, err := svc.ScrapeTarget(context.Background(), server.URL+"/metrics")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(ScrapedMetricNames(families), ","); got != "orders_total,order_duration_seconds,cache_errors" {
+		t.Errorf("unexpected metric names %s", got)
+	}
+
+	counter := MetricInfoFromFamily(families[0])
+	if counter.Type != MetricTypeCounter || counter.Help != "Orders placed." || counter.LabelCardinality["status"] != 2 || counter.SeriesCount != 2 {
+		t.Errorf("unexpected counter info %+v", counter)
+	}
+	histogram := MetricInfoFromFamily(families[1])
+	if histogram.Type != MetricTypeHistogram || strings.Join(histogram.Labels, ",") != "region" {
+		t.Errorf("expected le to be left out of the histogram labels, got %+v", histogram)
+	}
+	if untyped := MetricInfoFromFamily(families[2]); untyped.Type != MetricTypeCounter {
+		t.Errorf("expected the untyped metric to be typed by name, got %s", untyped.Type)
+	}
+
+	if _, err := svc.ScrapeTarget(context.Background(), server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected the scrape status in the error, got %v", err)
+	}
+}
+
+func TestDiscoverScrapedMetrics(t *testing.T) {
+	families := []exposition.Family{
+		{Name: "orders", Type: "counter", Help: "Orders placed.", Samples: []exposition.Sample{{Name: "orders_total", Labels: map[string]string{"status": "ok"}}}},
+		{Name: "queue_depth", Type: "unknown", Samples: []exposition.Sample{{Name: "queue_depth"}}},
+		{Name: "request_duration_seconds", Type: "histogram"},
+	}
+
+	all := DiscoverScrapedMetrics(families, nil, "")
+	if len(all) != 3 || all[1].Help != "No metadata available" || all[2].Labels == nil {
+		t.Errorf("unexpected metrics %+v", all)
+	}
+
+	counters := DiscoverScrapedMetrics(families, regexp.MustCompile("^orders"), MetricTypeCounter)
+	if len(counters) != 1 || counters[0].Name != "orders_total" {
+		t.Errorf("expected the counter to be matched by its series name, got %+v", counters)
+	}
+	if histograms := DiscoverScrapedMetrics(families, nil, MetricTypeHistogram); len(histograms) != 1 || histograms[0].Name != "request_duration_seconds" {
+		t.Errorf("expected the histogram only, got %+v", histograms)
+	}
+}
@@ -0,0 +1,478 @@
+package promql
+
+import (
+	"sort"
+	"strings"
+)
+
+// LabelMatcher represents a single label matcher inside a vector selector
+type LabelMatcher struct {
+	Name  string `json:"name"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// Selector represents a vector selector found in a PromQL expression
+type Selector struct {
+	Metric   string         `json:"metric,omitempty"`
+	Matchers []LabelMatcher `json:"matchers,omitempty"`
+	// Start and End are the byte offsets of the selector within the query
+	Start int `json:"-"`
+	End   int `json:"-"`
+}
+
+// promqlKeywords are identifiers that can never be metric names
+var promqlKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true, "bool": true, "offset": true,
+	"and": true, "or": true, "unless": true, "atan2": true,
+	"inf": true, "nan": true, "start": true, "end": true,
+	"sum": true, "avg": true, "min": true, "max": true, "count": true,
+	"stddev": true, "stdvar": true, "topk": true, "bottomk": true,
+	"quantile": true, "count_values": true, "group": true,
+}
+
+// groupingKeywords are keywords followed by a parenthesised label list
+var groupingKeywords = map[string]bool{
+	"by": true, "without": true, "on": true, "ignoring": true,
+	"group_left": true, "group_right": true,
+}
+
+// ParseSelectors extracts the vector selectors referenced by a PromQL
+// expression. It is a lightweight lexer rather than a full parser: it is
+// tolerant of Grafana template variables and returns whatever selectors it
+// can recognise.
+func ParseSelectors(query string) []Selector {
+	var selectors []Selector
+
+	i := 0
+	for i < len(query) {
+		c := query[i]
+
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(query, i)
+		case c == '[':
+			i = skipUntil(query, i, ']')
+		case c == '$':
+			i = skipVariable(query, i)
+		case c == '{':
+			matchers, end := parseMatchers(query, i)
+			selectors = append(selectors, Selector{Matchers: matchers, Start: i, End: end})
+			i = end
+		case isIdentStart(c):
+			start := i
+			for i < len(query) && isIdentChar(query[i]) {
+				i++
+			}
+			ident := query[start:i]
+
+			next := skipSpaces(query, i)
+			if groupingKeywords[ident] && next < len(query) && query[next] == '(' {
+				i = skipUntil(query, next, ')')
+				continue
+			}
+			if promqlKeywords[ident] {
+				continue
+			}
+			if next < len(query) && query[next] == '(' {
+				continue
+			}
+			if start > 0 && isDigit(query[start-1]) {
+				continue
+			}
+
+			sel := Selector{Metric: ident, Start: start, End: i}
+			if next < len(query) && query[next] == '{' {
+				matchers, end := parseMatchers(query, next)
+				sel.Matchers = matchers
+				sel.End = end
+				i = end
+			}
+			selectors = append(selectors, sel)
+		case isDigit(c) || c == '.':
+			for i < len(query) && (isIdentChar(query[i]) || query[i] == '.') {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	return selectors
+}
+
+// MetricNames returns the distinct metric names referenced by a PromQL expression
+func MetricNames(query string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, sel := range ParseSelectors(query) {
+		name := sel.Metric
+		if name == "" {
+			for _, m := range sel.Matchers {
+				if m.Name == "__name__" && m.Op == "=" {
+					name = m.Value
+				}
+			}
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// InjectMatchers adds the given equality matchers to every vector selector
+// of a query that does not already constrain the label, e.g. scoping
+// `rate(http_requests_total[5m])` to `cluster="prod"`
+func InjectMatchers(query string, matchers []LabelMatcher) string {
+	if len(matchers) == 0 {
+		return query
+	}
+
+	selectors := ParseSelectors(query)
+	for i := len(selectors) - 1; i >= 0; i-- {
+		sel := selectors[i]
+
+		constrained := map[string]bool{}
+		for _, m := range sel.Matchers {
+			constrained[m.Name] = true
+		}
+
+		var parts []string
+		for _, m := range matchers {
+			if constrained[m.Name] {
+				continue
+			}
+			op := m.Op
+			if op == "" {
+				op = "="
+			}
+			parts = append(parts, m.Name+op+quoteLabelValue(m.Value))
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		injected := strings.Join(parts, ", ")
+
+		if sel.End == 0 || query[sel.End-1] != '}' {
+			query = query[:sel.End] + "{" + injected + "}" + query[sel.End:]
+			continue
+		}
+
+		closing := sel.End - 1
+		prev := closing - 1
+		for prev >= sel.Start && (query[prev] == ' ' || query[prev] == '\t' || query[prev] == '\n') {
+			prev--
+		}
+		if prev >= sel.Start && query[prev] != '{' && query[prev] != ',' {
+			injected = ", " + injected
+		}
+		query = query[:closing] + injected + query[closing:]
+	}
+
+	return query
+}
+
+// MatcherRewrite replaces the value of a label matcher, e.g. turning
+// `env="staging"` into `env="prod"`
+type MatcherRewrite struct {
+	Label string `json:"label"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// RewriteMatchers applies the rewrites to every label matcher of a query and
+// returns the rewritten query with the number of matchers changed. Equality
+// matchers are rewritten when their value equals From; regex matchers when
+// one of their `|` alternatives does. Selectors without a rewritten matcher
+// are left untouched.
+func RewriteMatchers(query string, rewrites []MatcherRewrite) (string, int) {
+	if len(rewrites) == 0 {
+		return query, 0
+	}
+
+	changed := 0
+	selectors := ParseSelectors(query)
+	for i := len(selectors) - 1; i >= 0; i-- {
+		sel := selectors[i]
+
+		rewritten := false
+		for j, m := range sel.Matchers {
+			if value, ok := rewriteMatcherValue(m, rewrites); ok {
+				sel.Matchers[j].Value = value
+				rewritten = true
+				changed++
+			}
+		}
+		if rewritten {
+			query = query[:sel.Start] + sel.String() + query[sel.End:]
+		}
+	}
+
+	return query, changed
+}
+
+// rewriteMatcherValue returns the new value of a matcher, if a rewrite applies
+func rewriteMatcherValue(m LabelMatcher, rewrites []MatcherRewrite) (string, bool) {
+	alternatives := []string{m.Value}
+	if m.Op == "=~" || m.Op == "!~" {
+		alternatives = strings.Split(m.Value, "|")
+	}
+
+	matched := false
+	for k, alternative := range alternatives {
+		for _, r := range rewrites {
+			if r.Label == m.Name && alternative == r.From {
+				alternatives[k] = r.To
+				matched = true
+				break
+			}
+		}
+	}
+	return strings.Join(alternatives, "|"), matched
+}
+
+// RenameMetrics replaces the metric names of a query's selectors, including
+// `{__name__="..."}` matchers, and returns the renamed query with the number
+// of selectors changed
+func RenameMetrics(query string, renames map[string]string) (string, int) {
+	if len(renames) == 0 {
+		return query, 0
+	}
+
+	changed := 0
+	selectors := ParseSelectors(query)
+	for i := len(selectors) - 1; i >= 0; i-- {
+		sel := selectors[i]
+
+		if to, ok := renames[sel.Metric]; ok && sel.Metric != "" {
+			query = query[:sel.Start] + to + query[sel.Start+len(sel.Metric):]
+			changed++
+			continue
+		}
+
+		renamed := false
+		for j, m := range sel.Matchers {
+			if to, ok := renames[m.Value]; ok && m.Name == "__name__" && m.Op == "=" {
+				sel.Matchers[j].Value = to
+				renamed = true
+			}
+		}
+		if renamed {
+			query = query[:sel.Start] + sel.String() + query[sel.End:]
+			changed++
+		}
+	}
+
+	return query, changed
+}
+
+// String renders the selector back into PromQL
+func (s Selector) String() string {
+	if len(s.Matchers) == 0 {
+		return s.Metric
+	}
+	parts := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		parts = append(parts, m.Name+m.Op+quoteLabelValue(m.Value))
+	}
+	return s.Metric + "{" + strings.Join(parts, ", ") + "}"
+}
+
+// parseMatchers parses a `{...}` matcher block starting at pos and returns the
+// matchers along with the offset just past the closing brace
+func parseMatchers(query string, pos int) ([]LabelMatcher, int) {
+	var matchers []LabelMatcher
+	i := pos + 1
+
+	for i < len(query) {
+		i = skipSpaces(query, i)
+		if i >= len(query) {
+			break
+		}
+		if query[i] == '}' {
+			return matchers, i + 1
+		}
+		if query[i] == ',' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(query) && isIdentChar(query[i]) {
+			i++
+		}
+		name := query[start:i]
+		if name == "" {
+			i++
+			continue
+		}
+
+		i = skipSpaces(query, i)
+		op := ""
+		for _, candidate := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(query[i:], candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			continue
+		}
+		i = skipSpaces(query, i+len(op))
+
+		value := ""
+		if i < len(query) && (query[i] == '"' || query[i] == '\'' || query[i] == '`') {
+			end := skipString(query, i)
+			value = unquoteLabelValue(query[i:end])
+			i = end
+		} else {
+			vStart := i
+			for i < len(query) && query[i] != ',' && query[i] != '}' {
+				i++
+			}
+			value = strings.TrimSpace(query[vStart:i])
+		}
+
+		matchers = append(matchers, LabelMatcher{Name: name, Op: op, Value: value})
+	}
+
+	return matchers, len(query)
+}
+
+// skipString returns the offset just past the string literal starting at pos
+func skipString(query string, pos int) int {
+	quote := query[pos]
+	i := pos + 1
+	for i < len(query) {
+		if query[i] == '\\' && quote != '`' {
+			i += 2
+			continue
+		}
+		if query[i] == quote {
+			return i + 1
+		}
+		i++
+	}
+	return len(query)
+}
+
+// skipUntil returns the offset just past the first occurrence of closer after pos
+func skipUntil(query string, pos int, closer byte) int {
+	idx := strings.IndexByte(query[pos:], closer)
+	if idx < 0 {
+		return len(query)
+	}
+	return pos + idx + 1
+}
+
+// skipVariable returns the offset just past a Grafana template variable
+// ($var, ${var} or [[var]]-style references are all tolerated)
+func skipVariable(query string, pos int) int {
+	i := pos + 1
+	if i < len(query) && query[i] == '{' {
+		return skipUntil(query, i, '}')
+	}
+	for i < len(query) && isIdentChar(query[i]) {
+		i++
+	}
+	return i
+}
+
+func skipSpaces(query string, pos int) int {
+	for pos < len(query) && (query[pos] == ' ' || query[pos] == '\t' || query[pos] == '\n' || query[pos] == '\r') {
+		pos++
+	}
+	return pos
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func quoteLabelValue(value string) string {
+	return "\"" + strings.ReplaceAll(strings.ReplaceAll(value, "\\", "\\\\"), "\"", "\\\"") + "\""
+}
+
+func unquoteLabelValue(raw string) string {
+	if len(raw) < 2 {
+		return raw
+	}
+	inner := raw[1 : len(raw)-1]
+	if raw[0] == '`' {
+		return inner
+	}
+	inner = strings.ReplaceAll(inner, "\\\"", "\"")
+	inner = strings.ReplaceAll(inner, "\\'", "'")
+	return strings.ReplaceAll(inner, "\\\\", "\\")
+}
+
+// EditDistance returns the Levenshtein distance between two strings
+func EditDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// ClosestMatches returns up to limit candidates ordered by edit distance to
+// target, ignoring candidates further away than maxDistance
+func ClosestMatches(target string, candidates []string, maxDistance, limit int) []string {
+	type scored struct {
+		value    string
+		distance int
+	}
+
+	var matches []scored
+	for _, c := range candidates {
+		if c == target {
+			continue
+		}
+		if d := EditDistance(target, c); d <= maxDistance {
+			matches = append(matches, scored{value: c, distance: d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].value < matches[j].value
+	})
+
+	result := make([]string, 0, limit)
+	for _, m := range matches {
+		if len(result) >= limit {
+			break
+		}
+		result = append(result, m.value)
+	}
+	return result
+}
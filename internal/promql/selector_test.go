@@ -0,0 +1,248 @@
+package promql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []Selector
+	}{
+		{
+			name:     "bare metric",
+			query:    "up",
+			expected: []Selector{{Metric: "up"}},
+		},
+		{
+			name:  "metric with matchers inside rate",
+			query: `rate(http_requests_total{job="api", code=~"5.."}[5m])`,
+			expected: []Selector{{
+				Metric: "http_requests_total",
+				Matchers: []LabelMatcher{
+					{Name: "job", Op: "=", Value: "api"},
+					{Name: "code", Op: "=~", Value: "5.."},
+				},
+			}},
+		},
+		{
+			name:  "aggregation with grouping and binary operator",
+			query: `sum by (job) (rate(errors_total[5m])) / sum without (instance) (rate(requests_total[5m]))`,
+			expected: []Selector{
+				{Metric: "errors_total"},
+				{Metric: "requests_total"},
+			},
+		},
+		{
+			name:  "histogram quantile with template variables",
+			query: `histogram_quantile(0.95, sum by (le) (rate(latency_bucket{job="$job"}[$__rate_interval])))`,
+			expected: []Selector{{
+				Metric:   "latency_bucket",
+				Matchers: []LabelMatcher{{Name: "job", Op: "=", Value: "$job"}},
+			}},
+		},
+		{
+			name:  "matcher-only selector",
+			query: `{__name__="up", job!="node"}`,
+			expected: []Selector{{
+				Matchers: []LabelMatcher{
+					{Name: "__name__", Op: "=", Value: "up"},
+					{Name: "job", Op: "!=", Value: "node"},
+				},
+			}},
+		},
+		{
+			name:     "offset modifier",
+			query:    "rate(requests_total[5m] offset 1w)",
+			expected: []Selector{{Metric: "requests_total"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSelectors(tt.query)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseSelectors(%q) returned %d selectors, want %d: %+v", tt.query, len(got), len(tt.expected), got)
+			}
+			for i := range got {
+				if got[i].Metric != tt.expected[i].Metric {
+					t.Errorf("selector %d metric = %q, want %q", i, got[i].Metric, tt.expected[i].Metric)
+				}
+				if !reflect.DeepEqual(got[i].Matchers, tt.expected[i].Matchers) {
+					t.Errorf("selector %d matchers = %+v, want %+v", i, got[i].Matchers, tt.expected[i].Matchers)
+				}
+				if tt.query[got[i].Start:got[i].End] == "" {
+					t.Errorf("selector %d has empty source span", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricNames(t *testing.T) {
+	names := MetricNames(`rate(a_total[5m]) / rate(a_total[5m]) + on(job) b{__name__="c"} + {__name__="d"}`)
+	expected := []string{"a_total", "b", "d"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("MetricNames() = %v, want %v", names, expected)
+	}
+}
+
+func TestInjectMatchers(t *testing.T) {
+	env := []LabelMatcher{{Name: "cluster", Value: "$cluster"}, {Name: "region", Op: "=", Value: "eu-west-1"}}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "bare metric",
+			query:    "up",
+			expected: `up{cluster="$cluster", region="eu-west-1"}`,
+		},
+		{
+			name:     "existing matchers and range",
+			query:    `sum by (job) (rate(http_requests_total{job="api"}[$__rate_interval]))`,
+			expected: `sum by (job) (rate(http_requests_total{job="api", cluster="$cluster", region="eu-west-1"}[$__rate_interval]))`,
+		},
+		{
+			name:     "already constrained label is kept",
+			query:    `a{cluster="other"} / b{}`,
+			expected: `a{cluster="other", region="eu-west-1"} / b{cluster="$cluster", region="eu-west-1"}`,
+		},
+		{
+			name:     "bare matcher block and offset",
+			query:    `{__name__="up"} offset 1h`,
+			expected: `{__name__="up", cluster="$cluster", region="eu-west-1"} offset 1h`,
+		},
+		{
+			name:     "no selectors",
+			query:    "vector(1)",
+			expected: "vector(1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InjectMatchers(tt.query, env); got != tt.expected {
+				t.Errorf("InjectMatchers() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRewriteMatchers(t *testing.T) {
+	rewrites := []MatcherRewrite{{Label: "env", From: "staging", To: "prod"}, {Label: "env", From: "dev", To: "test"}}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		changed  int
+	}{
+		{
+			name:     "equality matcher",
+			query:    `sum(rate(http_requests_total{env="staging", job="api"}[5m]))`,
+			expected: `sum(rate(http_requests_total{env="prod", job="api"}[5m]))`,
+			changed:  1,
+		},
+		{
+			name:     "regex alternatives and negation",
+			query:    `a{env=~"staging|dev|qa"} / b{env!="staging"}`,
+			expected: `a{env=~"prod|test|qa"} / b{env!="prod"}`,
+			changed:  2,
+		},
+		{
+			name:     "other labels and values are untouched",
+			query:    `up{environment="staging",env="qa"}`,
+			expected: `up{environment="staging",env="qa"}`,
+		},
+		{
+			name:     "template variable query",
+			query:    `label_values(up{env="staging"}, job)`,
+			expected: `label_values(up{env="prod"}, job)`,
+			changed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := RewriteMatchers(tt.query, rewrites)
+			if got != tt.expected || changed != tt.changed {
+				t.Errorf("RewriteMatchers() = %s (%d changed), want %s (%d changed)", got, changed, tt.expected, tt.changed)
+			}
+		})
+	}
+}
+
+func TestRenameMetrics(t *testing.T) {
+	renames := map[string]string{"http_requests_total": "http_server_requests_total", "up": "target_up"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+		changed  int
+	}{
+		{
+			name:     "metric with matchers keeps its formatting",
+			query:    `sum by (route) (rate(http_requests_total{job="api",status=~"5.."}[5m])) / sum(rate(http_requests_total[5m]))`,
+			expected: `sum by (route) (rate(http_server_requests_total{job="api",status=~"5.."}[5m])) / sum(rate(http_server_requests_total[5m]))`,
+			changed:  2,
+		},
+		{
+			name:     "name matcher",
+			query:    `{__name__="up", job="api"}`,
+			expected: `{__name__="target_up", job="api"}`,
+			changed:  1,
+		},
+		{
+			name:     "prefixes and labels are not renamed",
+			query:    `http_requests_total_created{up="1"} offset 1h`,
+			expected: `http_requests_total_created{up="1"} offset 1h`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := RenameMetrics(tt.query, renames)
+			if got != tt.expected || changed != tt.changed {
+				t.Errorf("RenameMetrics() = %s (%d changed), want %s (%d changed)", got, changed, tt.expected, tt.changed)
+			}
+		})
+	}
+}
+
+func TestSelectorString(t *testing.T) {
+	sel := Selector{
+		Metric:   "up",
+		Matchers: []LabelMatcher{{Name: "job", Op: "=", Value: `say "hi"`}},
+	}
+	if got := sel.String(); got != `up{job="say \"hi\""}` {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestClosestMatches(t *testing.T) {
+	candidates := []string{"checkout", "checkout-api", "cart", "payments"}
+
+	got := ClosestMatches("chekout", candidates, 3, 2)
+	if len(got) == 0 || got[0] != "checkout" {
+		t.Errorf("ClosestMatches() = %v, want checkout first", got)
+	}
+
+	if got := ClosestMatches("zzzzzzzz", candidates, 2, 3); len(got) != 0 {
+		t.Errorf("Expected no matches beyond max distance, got %v", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	if d := EditDistance("kitten", "sitting"); d != 3 {
+		t.Errorf("EditDistance(kitten, sitting) = %d, want 3", d)
+	}
+	if d := EditDistance("", "abc"); d != 3 {
+		t.Errorf("EditDistance(\"\", abc) = %d, want 3", d)
+	}
+}
@@ -0,0 +1,238 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Panel groups of the OpenTelemetry semantic conventions, used as dashboard
+// row titles
+const (
+	GroupHTTPServer = "HTTP server"
+	GroupHTTPClient = "HTTP client"
+	GroupRPCServer  = "RPC server"
+	GroupRPCClient  = "RPC client"
+	GroupDBClient   = "Database client"
+)
+
+// conventionKind selects the query templates of a convention
+type conventionKind int
+
+const (
+	// conventionDuration is a latency histogram of operations
+	conventionDuration conventionKind = iota
+	// conventionSize is a histogram of payload sizes
+	conventionSize
+	// conventionInFlight is an up-down counter of operations in progress
+	conventionInFlight
+	// conventionConnections is an up-down counter of pool connections by state
+	conventionConnections
+)
+
+// errorSelector selects the failed operations of a convention through one label
+type errorSelector struct {
+	label, op, value string
+}
+
+// conventionDefinition describes an OpenTelemetry metric convention
+type conventionDefinition struct {
+	// name is the OpenTelemetry metric name
+	name  string
+	group string
+	kind  conventionKind
+	// unit is the Grafana unit of the instrument when the exported name has
+	// no unit suffix
+	unit string
+	// groupBy lists the attributes breaking operations down, current
+	// semantic conventions first
+	groupBy []string
+	// errors lists the ways failed operations are recorded, in order of preference
+	errors []errorSelector
+}
+
+var (
+	httpServerErrors = []errorSelector{{"http_response_status_code", "=~", "5.."}, {"http_status_code", "=~", "5.."}}
+	httpClientErrors = []errorSelector{{"error_type", "!=", ""}, {"http_response_status_code", "=~", "5.."}, {"http_status_code", "=~", "5.."}}
+	rpcErrors        = []errorSelector{{"rpc_grpc_status_code", "!=", "0"}, {"error_type", "!=", ""}}
+	dbErrors         = []errorSelector{{"error_type", "!=", ""}}
+)
+
+// knownConventions lists the OpenTelemetry conventions with dedicated query
+// templates, covering the current names and the ones they replaced
+var knownConventions = []conventionDefinition{
+	{name: "http.server.request.duration", group: GroupHTTPServer, kind: conventionDuration, unit: "s", groupBy: []string{"http_route", "http_request_method"}, errors: httpServerErrors},
+	{name: "http.server.duration", group: GroupHTTPServer, kind: conventionDuration, unit: "ms", groupBy: []string{"http_route", "http_target", "http_method"}, errors: httpServerErrors},
+	{name: "http.server.active_requests", group: GroupHTTPServer, kind: conventionInFlight},
+	{name: "http.server.request.body.size", group: GroupHTTPServer, kind: conventionSize, unit: "bytes", groupBy: []string{"http_route"}},
+	{name: "http.server.response.body.size", group: GroupHTTPServer, kind: conventionSize, unit: "bytes", groupBy: []string{"http_route"}},
+	{name: "http.client.request.duration", group: GroupHTTPClient, kind: conventionDuration, unit: "s", groupBy: []string{"server_address", "http_request_method"}, errors: httpClientErrors},
+	{name: "http.client.duration", group: GroupHTTPClient, kind: conventionDuration, unit: "ms", groupBy: []string{"net_peer_name", "http_method"}, errors: httpClientErrors},
+	{name: "rpc.server.duration", group: GroupRPCServer, kind: conventionDuration, unit: "ms", groupBy: []string{"rpc_method", "rpc_service"}, errors: rpcErrors},
+	{name: "rpc.client.duration", group: GroupRPCClient, kind: conventionDuration, unit: "ms", groupBy: []string{"rpc_method", "rpc_service"}, errors: rpcErrors},
+	{name: "db.client.operation.duration", group: GroupDBClient, kind: conventionDuration, unit: "s", groupBy: []string{"db_operation_name", "db_collection_name", "db_system"}, errors: dbErrors},
+	{name: "db.client.connection.count", group: GroupDBClient, kind: conventionConnections, groupBy: []string{"db_client_connection_state"}},
+	{name: "db.client.connections.usage", group: GroupDBClient, kind: conventionConnections, groupBy: []string{"state"}},
+	{name: "db.client.connections.use_time", group: GroupDBClient, kind: conventionDuration, unit: "ms", groupBy: []string{"pool_name"}},
+}
+
+// conventionUnitSuffixes map the unit suffixes Prometheus exporters append to
+// OpenTelemetry metric names to Grafana units
+var conventionUnitSuffixes = []struct{ suffix, unit string }{
+	{"_milliseconds", "ms"},
+	{"_seconds", "s"},
+	{"_bytes", "bytes"},
+}
+
+// Convention is the OpenTelemetry semantic convention a metric follows
+type Convention struct {
+	// Name is the OpenTelemetry metric name, e.g. http.server.request.duration
+	Name string `json:"name"`
+	// Group is the dashboard row the metric's panels belong in
+	Group string `json:"group"`
+	// Unit is the Grafana unit of the metric's values
+	Unit string `json:"unit,omitempty"`
+
+	definition *conventionDefinition
+}
+
+// MatchConvention recognises an OpenTelemetry semantic convention from the
+// Prometheus-exported form of its metric name: dots become underscores and
+// exporters may append the unit, _total and the histogram series suffixes
+// (http.server.request.duration is http_server_request_duration_seconds_bucket)
+func MatchConvention(metricName string) (Convention, bool) {
+	name := strings.TrimSuffix(histogramBaseName(metricName), "_total")
+	unit := ""
+	for _, s := range conventionUnitSuffixes {
+		if trimmed, ok := strings.CutSuffix(name, s.suffix); ok {
+			name, unit = trimmed, s.unit
+			break
+		}
+	}
+
+	for i := range knownConventions {
+		definition := &knownConventions[i]
+		if strings.ReplaceAll(definition.name, ".", "_") != name {
+			continue
+		}
+		if unit == "" {
+			unit = definition.unit
+		}
+		return Convention{Name: definition.name, Group: definition.group, Unit: unit, definition: definition}, true
+	}
+	return Convention{}, false
+}
+
+// applyConventions puts the query templates of the metric's semantic
+// convention ahead of the generic suggestions, so they become the best
+// query, and files every suggestion under the convention's panel group
+func applyConventions(metricInfo *MetricInfo, suggestions []QuerySuggestion, window string) []QuerySuggestion {
+	convention, ok := MatchConvention(metricInfo.Name)
+	if !ok {
+		return suggestions
+	}
+
+	suggestions = append(conventionQueries(metricInfo, convention, window), suggestions...)
+	for i := range suggestions {
+		suggestions[i].Group = convention.Group
+	}
+	return suggestions
+}
+
+// conventionQueries generates the convention-specific suggestions: latency
+// quantiles, throughput and error ratios for operation durations, average
+// sizes for payloads and breakdowns by state for pools
+func conventionQueries(metricInfo *MetricInfo, convention Convention, window string) []QuerySuggestion {
+	definition := convention.definition
+	groupBy := ""
+	for _, label := range definition.groupBy {
+		if slices.Contains(metricInfo.Labels, label) {
+			groupBy = label
+			break
+		}
+	}
+
+	switch definition.kind {
+	case conventionDuration:
+		return durationConventionQueries(metricInfo, convention, groupBy, window)
+	case conventionSize:
+		baseName := histogramBaseName(metricInfo.Name)
+		return []QuerySuggestion{{
+			Query:             fmt.Sprintf("sum(rate(%s_sum[%s])) / sum(rate(%s_count[%s]))", baseName, window, baseName, window),
+			Description:       "Average size",
+			VisualizationType: "timeseries",
+			YAxisLabel:        "size",
+			Unit:              convention.Unit,
+		}}
+	case conventionInFlight:
+		return []QuerySuggestion{{
+			Query:             fmt.Sprintf("sum(%s)", metricInfo.Name),
+			Description:       "Requests in flight",
+			VisualizationType: "timeseries",
+			YAxisLabel:        "requests",
+			Unit:              "short",
+		}}
+	case conventionConnections:
+		query := fmt.Sprintf("sum(%s)", metricInfo.Name)
+		description := "Open connections"
+		if groupBy != "" {
+			query = fmt.Sprintf("sum by (%s) (%s)", groupBy, metricInfo.Name)
+			description = "Connections by state"
+		}
+		return []QuerySuggestion{{
+			Query:             query,
+			Description:       description,
+			VisualizationType: "timeseries",
+			YAxisLabel:        "connections",
+			Unit:              "short",
+		}}
+	}
+	return nil
+}
+
+// durationConventionQueries generates the RED panels of an operation
+// duration histogram: p95 latency, throughput and the error ratio
+func durationConventionQueries(metricInfo *MetricInfo, convention Convention, groupBy, window string) []QuerySuggestion {
+	baseName := histogramBaseName(metricInfo.Name)
+	suggestions := []QuerySuggestion{{
+		Query:             fmt.Sprintf("histogram_quantile(0.95, sum by (le) (rate(%s_bucket[%s])))", baseName, window),
+		Description:       fmt.Sprintf("95th percentile latency over %s", window),
+		VisualizationType: "timeseries",
+		YAxisLabel:        "duration",
+		Unit:              convention.Unit,
+	}}
+
+	throughput := QuerySuggestion{
+		Query:             fmt.Sprintf("sum(rate(%s_count[%s]))", baseName, window),
+		Description:       "Operations per second",
+		VisualizationType: "timeseries",
+		YAxisLabel:        "per second",
+		Unit:              "ops",
+	}
+	if strings.HasPrefix(convention.Name, "http.") {
+		throughput.Description, throughput.Unit = "Requests per second", "reqps"
+	}
+	if groupBy != "" {
+		throughput.Query = fmt.Sprintf("sum by (%s) (rate(%s_count[%s]))", groupBy, baseName, window)
+		throughput.Description += fmt.Sprintf(" by %s", groupBy)
+	}
+	suggestions = append(suggestions, throughput)
+
+	for _, selector := range convention.definition.errors {
+		if !slices.Contains(metricInfo.Labels, selector.label) {
+			continue
+		}
+		matcher := fmt.Sprintf("%s%s%q", selector.label, selector.op, selector.value)
+		suggestions = append(suggestions, QuerySuggestion{
+			Query:             fmt.Sprintf("sum(rate(%s_count{%s}[%s])) / sum(rate(%s_count[%s]))", baseName, matcher, window, baseName, window),
+			Description:       "Error ratio",
+			VisualizationType: "timeseries",
+			YAxisLabel:        "error ratio",
+			Category:          CategoryErrorRatio,
+			Unit:              "percentunit",
+		})
+		break
+	}
+
+	return suggestions
+}
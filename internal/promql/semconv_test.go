@@ -0,0 +1,87 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestMatchConvention(t *testing.T) {
+	tests := []struct {
+		metric    string
+		wantName  string
+		wantGroup string
+		wantUnit  string
+	}{
+		{"http_server_request_duration_seconds_bucket", "http.server.request.duration", GroupHTTPServer, "s"},
+		{"http_server_duration_milliseconds", "http.server.duration", GroupHTTPServer, "ms"},
+		{"http_server_duration", "http.server.duration", GroupHTTPServer, "ms"},
+		{"rpc_client_duration_milliseconds_count", "rpc.client.duration", GroupRPCClient, "ms"},
+		{"db_client_connections_usage", "db.client.connections.usage", GroupDBClient, ""},
+		{"http_server_response_body_size_bytes_sum", "http.server.response.body.size", GroupHTTPServer, "bytes"},
+		{"http_request_duration_seconds", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			convention, ok := MatchConvention(tt.metric)
+			if ok != (tt.wantName != "") {
+				t.Fatalf("expected match %v, got %+v", tt.wantName != "", convention)
+			}
+			if convention.Name != tt.wantName || convention.Group != tt.wantGroup || convention.Unit != tt.wantUnit {
+				t.Errorf("expected %s/%s/%s, got %+v", tt.wantName, tt.wantGroup, tt.wantUnit, convention)
+			}
+		})
+	}
+}
+
+func TestGenerateQueriesWithConventions(t *testing.T) {
+	t.Run("http server duration", func(t *testing.T) {
+		metricInfo := &MetricInfo{
+			Name:   "http_server_request_duration_seconds",
+			Type:   MetricTypeHistogram,
+			Labels: []string{"http_request_method", "http_response_status_code", "http_route"},
+		}
+		suggestions := generateQueries(metricInfo, GenerateOptions{})
+
+		want := []struct{ query, unit string }{
+			{"histogram_quantile(0.95, sum by (le) (rate(http_server_request_duration_seconds_bucket[5m])))", "s"},
+			{"sum by (http_route) (rate(http_server_request_duration_seconds_count[5m]))", "reqps"},
+			{`sum(rate(http_server_request_duration_seconds_count{http_response_status_code=~"5.."}[5m])) / sum(rate(http_server_request_duration_seconds_count[5m]))`, "percentunit"},
+		}
+		for i, w := range want {
+			if suggestions[i].Query != w.query || suggestions[i].Unit != w.unit {
+				t.Errorf("suggestion %d: expected %s (%s), got %s (%s)", i, w.query, w.unit, suggestions[i].Query, suggestions[i].Unit)
+			}
+		}
+		for _, s := range suggestions {
+			if s.Group != GroupHTTPServer {
+				t.Errorf("expected every suggestion in the HTTP server group, got %q for %s", s.Group, s.Query)
+			}
+		}
+	})
+
+	t.Run("rpc errors use the gRPC status code", func(t *testing.T) {
+		metricInfo := &MetricInfo{Name: "rpc_server_duration_milliseconds", Type: MetricTypeHistogram, Labels: []string{"rpc_grpc_status_code", "rpc_method"}}
+		suggestions := generateQueries(metricInfo, GenerateOptions{})
+		if suggestions[0].Unit != "ms" || suggestions[1].Unit != "ops" {
+			t.Errorf("unexpected units %s, %s", suggestions[0].Unit, suggestions[1].Unit)
+		}
+		if suggestions[2].Query != `sum(rate(rpc_server_duration_milliseconds_count{rpc_grpc_status_code!="0"}[5m])) / sum(rate(rpc_server_duration_milliseconds_count[5m]))` {
+			t.Errorf("unexpected error ratio %s", suggestions[2].Query)
+		}
+	})
+
+	t.Run("connection pool by state", func(t *testing.T) {
+		metricInfo := &MetricInfo{Name: "db_client_connections_usage", Type: MetricTypeGauge, Labels: []string{"pool_name", "state"}}
+		best := getBestQuery(generateQueries(metricInfo, GenerateOptions{}))
+		if best.Query != "sum by (state) (db_client_connections_usage)" || best.Group != GroupDBClient {
+			t.Errorf("unexpected best query %+v", best)
+		}
+	})
+
+	t.Run("other metrics are unchanged", func(t *testing.T) {
+		for _, s := range generateQueries(&MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter}, GenerateOptions{}) {
+			if s.Group != "" || s.Unit != "" {
+				t.Errorf("unexpected convention on %+v", s)
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GroupServiceGraph is the row of the service graph panels
+const GroupServiceGraph = "Service graph"
+
+// Service graph metrics Tempo's metrics generator derives from the spans of
+// each call between two services, labelled with the client and the server
+const (
+	ServiceGraphRequests = "traces_service_graph_request_total"
+	serviceGraphFailed   = "traces_service_graph_request_failed_total"
+	serviceGraphLatency  = "traces_service_graph_request_server_seconds_bucket"
+)
+
+// serviceGraphRate is the rate of a service graph counter for the calls
+// matching the matcher, by the given label
+func serviceGraphRate(metric, matcher, by string) string {
+	return fmt.Sprintf("sum by (%s) (rate(%s{%s}[5m]))", by, metric, matcher)
+}
+
+// ServiceGraphPanels returns the call rate and error ratio of the services
+// calling the service (upstream) and of the services it calls (downstream),
+// with the latency of the downstream calls. The metrics carry the service
+// name rather than the job of the service, so the queries select it by the
+// client and server labels.
+func ServiceGraphPanels(metricNames []string, service string, upstream, downstream bool) []TemplatePanel {
+	asServer := fmt.Sprintf("server=%q", service)
+	asClient := fmt.Sprintf("client=%q", service)
+
+	var panels []TemplatePanel
+	if upstream {
+		panels = append(panels,
+			TemplatePanel{
+				Title:       "Calls from upstream services",
+				Type:        "timeseries",
+				Description: fmt.Sprintf("Calls per second %s receives from each service, from trace spans.", service),
+				Unit:        "reqps",
+				Queries:     []TemplateQuery{{Expr: serviceGraphRate(ServiceGraphRequests, asServer, "client"), Legend: "{{client}}"}},
+				Group:       GroupServiceGraph,
+				requires:    []string{ServiceGraphRequests},
+			},
+			TemplatePanel{
+				Title:       "Error ratio of upstream calls",
+				Type:        "timeseries",
+				Description: fmt.Sprintf("Share of the calls from each service that %s failed.", service),
+				Unit:        "percentunit",
+				Queries:     []TemplateQuery{{Expr: serviceGraphRate(serviceGraphFailed, asServer, "client") + " / " + serviceGraphRate(ServiceGraphRequests, asServer, "client"), Legend: "{{client}}"}},
+				Thresholds:  serverErrorThresholds(),
+				Group:       GroupServiceGraph,
+				requires:    []string{ServiceGraphRequests, serviceGraphFailed},
+			},
+		)
+	}
+	if downstream {
+		panels = append(panels,
+			TemplatePanel{
+				Title:       "Calls to downstream services",
+				Type:        "timeseries",
+				Description: fmt.Sprintf("Calls per second %s makes to each service, from trace spans.", service),
+				Unit:        "reqps",
+				Queries:     []TemplateQuery{{Expr: serviceGraphRate(ServiceGraphRequests, asClient, "server"), Legend: "{{server}}"}},
+				Group:       GroupServiceGraph,
+				requires:    []string{ServiceGraphRequests},
+			},
+			TemplatePanel{
+				Title:       "Error ratio of downstream calls",
+				Type:        "timeseries",
+				Description: fmt.Sprintf("Share of the calls %s makes that each service failed.", service),
+				Unit:        "percentunit",
+				Queries:     []TemplateQuery{{Expr: serviceGraphRate(serviceGraphFailed, asClient, "server") + " / " + serviceGraphRate(ServiceGraphRequests, asClient, "server"), Legend: "{{server}}"}},
+				Thresholds:  serverErrorThresholds(),
+				Group:       GroupServiceGraph,
+				requires:    []string{ServiceGraphRequests, serviceGraphFailed},
+			},
+			TemplatePanel{
+				Title:       "P95 latency of downstream calls",
+				Type:        "timeseries",
+				Description: fmt.Sprintf("95th percentile duration of the calls %s makes, as measured by each server.", service),
+				Unit:        "s",
+				Queries:     []TemplateQuery{{Expr: p95(fmt.Sprintf("%s{%s}", serviceGraphLatency, asClient), "server"), Legend: "{{server}}"}},
+				Group:       GroupServiceGraph,
+				requires:    []string{serviceGraphLatency},
+			},
+		)
+	}
+	return DashboardTemplate{panels: panels}.Panels(metricNames, "")
+}
+
+// NodeGraphEdges shapes a query returning one series per call, labelled
+// with the caller and the callee, into the edges frame of Grafana's node
+// graph, which reads the source, target and id fields
+func NodeGraphEdges(expr, source, target string) string {
+	shaped := fmt.Sprintf(`label_replace(label_replace(%s, "source", "$1", %q, "(.*)"), "target", "$1", %q, "(.*)")`, expr, source, target)
+	return fmt.Sprintf(`label_join(%s, "id", "->", "source", "target")`, shaped)
+}
+
+// NodeGraphNodes shapes a query returning one series per node, labelled
+// with the node's name, into the nodes frame of Grafana's node graph, which
+// reads the id and title fields
+func NodeGraphNodes(expr, label string) string {
+	return fmt.Sprintf(`label_replace(label_replace(%s, "id", "$1", %q, "(.*)"), "title", "$1", %q, "(.*)")`, expr, label, label)
+}
+
+// ServiceGraphTopology returns the edges and nodes queries of the calls to
+// and from a service. Each edge's main stat is its call rate and each
+// node's the rate of calls it receives; services that only call others,
+// such as batch jobs, are drawn with a rate of zero.
+func ServiceGraphTopology(service string, neighbours []string) (edges, nodes string) {
+	edges = NodeGraphEdges(fmt.Sprintf("sum by (client, server) (rate(%[1]s{client=%[2]q}[5m]) or rate(%[1]s{server=%[2]q}[5m]))", ServiceGraphRequests, service), "client", "server")
+
+	names := make([]string, 0, len(neighbours)+1)
+	for _, name := range append([]string{service}, neighbours...) {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	selected := strings.Join(names, "|")
+	servers := fmt.Sprintf("sum by (server) (rate(%s{server=~%q}[5m]))", ServiceGraphRequests, selected)
+	clients := fmt.Sprintf(`sum by (server) (label_replace(rate(%s{client=~%q}[5m]), "server", "$1", "client", "(.*)")) * 0`, ServiceGraphRequests, selected)
+	return edges, NodeGraphNodes(servers+" or "+clients, "server")
+}
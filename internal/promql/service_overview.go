@@ -0,0 +1,170 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Panel groups of a service overview, used as dashboard row titles
+const (
+	GroupRequests     = "Requests"
+	GroupRuntime      = "Runtime"
+	GroupDependencies = "Dependencies"
+)
+
+// grpcServerFaults matches the gRPC status codes that report a fault of the
+// server rather than of the request
+const grpcServerFaults = `grpc_code=~"Unknown|Internal|Unavailable|DataLoss|DeadlineExceeded"`
+
+// redMetrics names the metrics a client library reports the rate, errors
+// and duration of requests with
+type redMetrics struct {
+	// noun names the requests in panel titles, such as "outgoing HTTP
+	// requests"
+	noun string
+	// requests counts requests; errors matches the failed ones
+	requests, errors string
+	// bucket is the duration histogram's bucket series, in unit
+	bucket, unit string
+	// by is the label the panels break requests down by
+	by string
+}
+
+// rate is the rate of requests matching the matcher, by the given labels or
+// in total when by is empty
+func (m redMetrics) rate(matcher, by string) string {
+	selector := m.requests
+	if matcher != "" {
+		selector = fmt.Sprintf("%s{%s}", m.requests, matcher)
+	}
+	if by == "" {
+		return fmt.Sprintf("sum(rate(%s[5m]))", selector)
+	}
+	return fmt.Sprintf("sum by (%s) (rate(%s[5m]))", by, selector)
+}
+
+// requestPanels returns the request rate, error ratio and 95th percentile
+// latency of the requests broken down by their label
+func requestPanels(m redMetrics, group string) []TemplatePanel {
+	title := strings.ToUpper(m.noun[:1]) + m.noun[1:]
+	legend := fmt.Sprintf("{{%s}}", m.by)
+	return []TemplatePanel{
+		{
+			Title:       fmt.Sprintf("%s by %s", title, m.by),
+			Type:        "timeseries",
+			Description: fmt.Sprintf("Rate of %s by %s.", m.noun, m.by),
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: m.rate("", m.by), Legend: legend}},
+			Group:       group,
+			requires:    []string{m.requests},
+		},
+		{
+			Title:       fmt.Sprintf("Error ratio of %s by %s", m.noun, m.by),
+			Type:        "timeseries",
+			Description: fmt.Sprintf("Share of %s that failed, by %s.", m.noun, m.by),
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: m.rate(m.errors, m.by) + " / " + m.rate("", m.by), Legend: legend}},
+			Thresholds:  serverErrorThresholds(),
+			Group:       group,
+			requires:    []string{m.requests},
+		},
+		{
+			Title:       fmt.Sprintf("P95 latency of %s by %s", m.noun, m.by),
+			Type:        "timeseries",
+			Description: fmt.Sprintf("95th percentile duration of %s by %s.", m.noun, m.by),
+			Unit:        m.unit,
+			Queries:     []TemplateQuery{{Expr: p95(m.bucket, m.by), Legend: legend}},
+			Group:       group,
+			requires:    []string{m.bucket},
+		},
+	}
+}
+
+// servicePanels returns the request rate, error ratio and latency stats of
+// the requests a service serves, followed by their breakdown
+func servicePanels(m redMetrics) []TemplatePanel {
+	return append([]TemplatePanel{
+		{
+			Title:       "Request rate",
+			Type:        "stat",
+			Description: "Requests per second the service serves.",
+			Unit:        "reqps",
+			Queries:     []TemplateQuery{{Expr: m.rate("", "")}},
+			requires:    []string{m.requests},
+		},
+		{
+			Title:       "Error ratio",
+			Type:        "stat",
+			Description: "Share of requests that failed.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: m.rate(m.errors, "") + " / " + m.rate("", "")}},
+			Thresholds:  serverErrorThresholds(),
+			requires:    []string{m.requests},
+		},
+		{
+			Title:       "P95 latency",
+			Type:        "stat",
+			Description: "95th percentile request duration.",
+			Unit:        m.unit,
+			Queries:     []TemplateQuery{{Expr: p95(m.bucket, "")}},
+			requires:    []string{m.bucket},
+		},
+	}, requestPanels(m, GroupRequests)...)
+}
+
+// serviceRequests lists the request metrics services serve with, in order of
+// preference: OpenTelemetry's current and previous HTTP server conventions,
+// the Prometheus client libraries' HTTP convention and go-grpc-prometheus
+var serviceRequests = []redMetrics{
+	{noun: "requests", requests: "http_server_request_duration_seconds_count", errors: `http_response_status_code=~"5.."`, bucket: "http_server_request_duration_seconds_bucket", unit: "s", by: "http_route"},
+	{noun: "requests", requests: "http_server_duration_milliseconds_count", errors: `http_status_code=~"5.."`, bucket: "http_server_duration_milliseconds_bucket", unit: "ms", by: "http_route"},
+	{noun: "requests", requests: "http_requests_total", errors: `code=~"5.."`, bucket: "http_request_duration_seconds_bucket", unit: "s", by: "handler"},
+	{noun: "requests", requests: "grpc_server_handled_total", errors: grpcServerFaults, bucket: "grpc_server_handling_seconds_bucket", unit: "s", by: "grpc_method"},
+}
+
+// serviceDependencies lists the client metrics services call their
+// dependencies with, each kind of call newest convention first
+var serviceDependencies = []redMetrics{
+	{noun: "outgoing HTTP requests", requests: "http_client_request_duration_seconds_count", errors: `error_type!=""`, bucket: "http_client_request_duration_seconds_bucket", unit: "s", by: "server_address"},
+	{noun: "outgoing HTTP requests", requests: "http_client_duration_milliseconds_count", errors: `http_status_code=~"5.."`, bucket: "http_client_duration_milliseconds_bucket", unit: "ms", by: "net_peer_name"},
+	{noun: "outgoing gRPC calls", requests: "grpc_client_handled_total", errors: grpcServerFaults, bucket: "grpc_client_handling_seconds_bucket", unit: "s", by: "grpc_service"},
+	{noun: "database operations", requests: "db_client_operation_duration_seconds_count", errors: `error_type!=""`, bucket: "db_client_operation_duration_seconds_bucket", unit: "s", by: "db_system"},
+}
+
+// ServiceOverviewPanels returns the panels of a service's landing dashboard:
+// the rate, errors and latency of the requests it serves, the stats of the
+// runtime templates that apply and the calls it makes to its dependencies.
+// Requests and each kind of call are drawn from the first convention whose
+// metrics are present, so a service instrumented twice is not counted twice.
+func ServiceOverviewPanels(metricNames []string) []TemplatePanel {
+	var panels []TemplatePanel
+	for _, m := range serviceRequests {
+		if slices.Contains(metricNames, m.requests) {
+			panels = append(panels, servicePanels(m)...)
+			break
+		}
+	}
+
+	for _, template := range dashboardTemplates {
+		if !template.generic || !template.Applies(metricNames) {
+			continue
+		}
+		for _, panel := range template.Panels(metricNames, "instance") {
+			if panel.Type == "stat" && panel.Group == "" {
+				panel.Group = GroupRuntime
+				panels = append(panels, panel)
+			}
+		}
+	}
+
+	called := map[string]bool{}
+	for _, m := range serviceDependencies {
+		if !called[m.noun] && slices.Contains(metricNames, m.requests) {
+			called[m.noun] = true
+			panels = append(panels, requestPanels(m, GroupDependencies)...)
+		}
+	}
+
+	return DashboardTemplate{panels: panels}.Panels(metricNames, "")
+}
@@ -0,0 +1,73 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestServiceOverviewPanels(t *testing.T) {
+	panels := ServiceOverviewPanels([]string{
+		"http_server_request_duration_seconds_count", "http_server_request_duration_seconds_bucket",
+		"http_requests_total",
+		"go_goroutines", "go_memstats_heap_inuse_bytes",
+		"http_client_request_duration_seconds_count",
+		"db_client_operation_duration_seconds_count", "db_client_operation_duration_seconds_bucket",
+	})
+
+	byTitle := map[string]TemplatePanel{}
+	for _, panel := range panels {
+		byTitle[panel.Title] = panel
+	}
+
+	if errors := byTitle["Error ratio"]; errors.Queries[0].Expr != `sum(rate(http_server_request_duration_seconds_count{http_response_status_code=~"5.."}[5m])) / sum(rate(http_server_request_duration_seconds_count[5m]))` {
+		t.Errorf("Expected the error ratio of the OpenTelemetry convention, got %s", errors.Queries[0].Expr)
+	}
+	if _, ok := byTitle["Requests by handler"]; ok {
+		t.Error("Expected the Prometheus client requests to be left out once the OpenTelemetry ones are drawn")
+	}
+	if goroutines, ok := byTitle["Goroutines"]; !ok || goroutines.Group != GroupRuntime {
+		t.Errorf("Expected the goroutines stat in the runtime row, got %+v", goroutines)
+	}
+	if _, ok := byTitle["Goroutines by instance"]; ok {
+		t.Error("Expected only the runtime stats")
+	}
+	if _, ok := byTitle["P95 latency of outgoing HTTP requests by server_address"]; ok {
+		t.Error("Expected no latency panel without the client histogram buckets")
+	}
+	if db, ok := byTitle["P95 latency of database operations by db_system"]; !ok || db.Group != GroupDependencies {
+		t.Errorf("Expected the database latency in the dependencies row, got %+v", db)
+	}
+
+	if found := ServiceOverviewPanels([]string{"up"}); len(found) != 0 {
+		t.Errorf("Expected no panels without request, runtime or dependency metrics, got %+v", found)
+	}
+}
+
+func TestServiceGraphPanels(t *testing.T) {
+	metricNames := []string{"traces_service_graph_request_total", "traces_service_graph_request_failed_total"}
+
+	panels := ServiceGraphPanels(metricNames, "checkout", false, true)
+	var titles []string
+	for _, panel := range panels {
+		titles = append(titles, panel.Title)
+	}
+	if len(titles) != 2 || titles[0] != "Calls to downstream services" || titles[1] != "Error ratio of downstream calls" {
+		t.Fatalf("Expected the downstream calls without latency buckets, got %v", titles)
+	}
+	expected := `sum by (server) (rate(traces_service_graph_request_failed_total{client="checkout"}[5m])) / sum by (server) (rate(traces_service_graph_request_total{client="checkout"}[5m]))`
+	if panels[1].Queries[0].Expr != expected {
+		t.Errorf("Expected %s, got %s", expected, panels[1].Queries[0].Expr)
+	}
+}
+
+func TestServiceGraphTopology(t *testing.T) {
+	edges, nodes := ServiceGraphTopology("checkout", []string{"frontend", "payments.v2"})
+
+	expectedEdges := `label_join(label_replace(label_replace(sum by (client, server) (rate(traces_service_graph_request_total{client="checkout"}[5m]) or rate(traces_service_graph_request_total{server="checkout"}[5m])), "source", "$1", "client", "(.*)"), "target", "$1", "server", "(.*)"), "id", "->", "source", "target")`
+	if edges != expectedEdges {
+		t.Errorf("Expected edges %s, got %s", expectedEdges, edges)
+	}
+	expectedNodes := `label_replace(label_replace(sum by (server) (rate(traces_service_graph_request_total{server=~"checkout|frontend|payments\\.v2"}[5m])) or sum by (server) (label_replace(rate(traces_service_graph_request_total{client=~"checkout|frontend|payments\\.v2"}[5m]), "server", "$1", "client", "(.*)")) * 0, "id", "$1", "server", "(.*)"), "title", "$1", "server", "(.*)")`
+	if nodes != expectedNodes {
+		t.Errorf("Expected nodes %s, got %s", expectedNodes, nodes)
+	}
+}
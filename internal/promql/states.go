@@ -0,0 +1,199 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// State is a value of a binary or enum metric with the text and colour it
+// is shown as in state timeline and status history panels
+type State struct {
+	Value int    `json:"value"`
+	Text  string `json:"text"`
+	Color string `json:"color"`
+}
+
+// binaryStates are the states of 0/1 health metrics such as up and
+// probe_success
+var binaryStates = []State{
+	{Value: 0, Text: "Down", Color: "red"},
+	{Value: 1, Text: "Up", Color: "green"},
+}
+
+// isBinaryMetric reports whether a metric is 1 when its target is healthy
+// and 0 otherwise: up, probe_success and the <exporter>_up metrics
+func isBinaryMetric(name string) bool {
+	return name == "up" || name == probeSuccessMetric || strings.HasSuffix(name, "_up")
+}
+
+// enumMetric is a metric with one series per state, valued 1 for the
+// current state and 0 for the others, e.g. kube_pod_status_phase
+type enumMetric struct {
+	name string
+	// label holds the state, values the states in the order they are
+	// numbered from 1
+	label  string
+	values []string
+	colors []string
+	// by identifies the object the metric describes
+	by []string
+}
+
+// enumMetrics lists the enum metrics of kube-state-metrics
+var enumMetrics = []enumMetric{
+	{
+		name:   "kube_pod_status_phase",
+		label:  "phase",
+		values: []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"},
+		colors: []string{"yellow", "green", "blue", "red", "purple"},
+		by:     []string{"namespace", "pod"},
+	},
+	{
+		name:   "kube_namespace_status_phase",
+		label:  "phase",
+		values: []string{"Active", "Terminating"},
+		colors: []string{"green", "orange"},
+		by:     []string{"namespace"},
+	},
+	{
+		name:   "kube_persistentvolume_status_phase",
+		label:  "phase",
+		values: []string{"Pending", "Available", "Bound", "Released", "Failed"},
+		colors: []string{"yellow", "blue", "green", "orange", "red"},
+		by:     []string{"persistentvolume"},
+	},
+	{
+		name:   "kube_persistentvolumeclaim_status_phase",
+		label:  "phase",
+		values: []string{"Pending", "Bound", "Lost"},
+		colors: []string{"yellow", "green", "red"},
+		by:     []string{"namespace", "persistentvolumeclaim"},
+	},
+	{
+		name:   "kube_deployment_status_condition",
+		label:  "status",
+		values: []string{"true", "false", "unknown"},
+		colors: []string{"green", "red", "orange"},
+		by:     []string{"namespace", "deployment", "condition"},
+	},
+}
+
+// findEnumMetric returns the enum metric with the given name
+func findEnumMetric(name string) (enumMetric, bool) {
+	for _, metric := range enumMetrics {
+		if metric.name == name {
+			return metric, true
+		}
+	}
+	return enumMetric{}, false
+}
+
+// states returns the numbered states of the metric's StateQuery
+func (m enumMetric) states() []State {
+	states := make([]State, len(m.values))
+	for i, value := range m.values {
+		states[i] = State{Value: i + 1, Text: value, Color: m.colors[i]}
+	}
+	return states
+}
+
+// StateQuery encodes an enum metric as one series per object whose value
+// numbers its current state, from 1 in the order StateMappings maps them,
+// e.g. 2 for a Running pod. It returns false for other metrics.
+func StateQuery(metric string) (string, bool) {
+	enum, ok := findEnumMetric(metric)
+	if !ok {
+		return "", false
+	}
+	terms := make([]string, len(enum.values))
+	for i, value := range enum.values {
+		terms[i] = fmt.Sprintf("%s{%s=%q} * %d", enum.name, enum.label, value, i+1)
+	}
+	return fmt.Sprintf("max by (%s) (%s)", strings.Join(enum.by, ", "), strings.Join(terms, " or ")), true
+}
+
+// valueChangingFunctions turn 0/1 samples into counts, ratios or rates,
+// which state mappings would mislabel
+var valueChangingFunctions = regexp.MustCompile(`\b(sum|count|avg|rate|irate|increase|delta|idelta|deriv|quantile|stddev|stdvar|count_values|(avg|sum|count|quantile|stddev|stdvar)_over_time)\b`)
+
+// StateMappings returns the states of the values a query returns: Up and
+// Down for binary metrics kept 0/1, and the states of a StateQuery. It
+// returns nil when the values are not states.
+func StateMappings(query string) []State {
+	for _, name := range MetricNames(query) {
+		if isBinaryMetric(name) && !valueChangingFunctions.MatchString(query) {
+			return binaryStates
+		}
+		if enum, ok := findEnumMetric(name); ok && encodesStates(query, enum) {
+			return enum.states()
+		}
+	}
+	return nil
+}
+
+// encodesStates reports whether a query selects each state of an enum
+// metric by its label, as StateQuery does
+func encodesStates(query string, enum enumMetric) bool {
+	selected := map[string]bool{}
+	for _, selector := range ParseSelectors(query) {
+		for _, matcher := range selector.Matchers {
+			if selector.Metric == enum.name && matcher.Name == enum.label && matcher.Op == "=" {
+				selected[matcher.Value] = true
+			}
+		}
+	}
+	for _, value := range enum.values {
+		if !selected[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueMappings converts states to the value mappings of a Grafana field
+// config
+func ValueMappings(states []State) []any {
+	options := make(map[string]any, len(states))
+	for i, state := range states {
+		options[strconv.Itoa(state.Value)] = map[string]any{"text": state.Text, "color": state.Color, "index": i}
+	}
+	return []any{map[string]any{"type": "value", "options": options}}
+}
+
+// applyStateQueries adds state timeline and status history suggestions for
+// binary and enum metrics, whose line charts hide when a state changed
+func applyStateQueries(metricInfo *MetricInfo, suggestions []QuerySuggestion) []QuerySuggestion {
+	if query, ok := StateQuery(metricInfo.Name); ok {
+		return append(suggestions, QuerySuggestion{
+			Query:             query,
+			Description:       "State of each object over time",
+			VisualizationType: "state-timeline",
+			YAxisLabel:        "state",
+		})
+	}
+	if !isBinaryMetric(metricInfo.Name) {
+		return suggestions
+	}
+
+	by := "instance"
+	if len(metricInfo.Labels) > 0 && !slices.Contains(metricInfo.Labels, by) {
+		by = "job"
+	}
+	return append(suggestions,
+		QuerySuggestion{
+			Query:             fmt.Sprintf("min by (%s) (%s)", by, metricInfo.Name),
+			Description:       fmt.Sprintf("Up or down over time per %s", by),
+			VisualizationType: "state-timeline",
+			YAxisLabel:        "state",
+		},
+		QuerySuggestion{
+			Query:             fmt.Sprintf("min by (job) (%s)", metricInfo.Name),
+			Description:       "Up or down per job, down when any target is",
+			VisualizationType: "status-history",
+			YAxisLabel:        "state",
+		},
+	)
+}
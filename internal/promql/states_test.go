@@ -0,0 +1,61 @@
+package promql
+
+import (
+	"testing"
+)
+
+func TestStateMappings(t *testing.T) {
+	t.Run("binary metrics kept 0/1", func(t *testing.T) {
+		for _, query := range []string{`up{job="api"}`, "min by (instance) (probe_success)", "max_over_time(pg_up[5m])"} {
+			if states := StateMappings(query); len(states) != 2 || states[0].Text != "Down" || states[1].Text != "Up" {
+				t.Errorf("Expected Down and Up for %s, got %+v", query, states)
+			}
+		}
+	})
+
+	t.Run("binary metrics turned into ratios", func(t *testing.T) {
+		for _, query := range []string{"avg(up)", "sum by (job) (up)", "avg_over_time(probe_success[1d])"} {
+			if states := StateMappings(query); states != nil {
+				t.Errorf("Expected no states for %s, got %+v", query, states)
+			}
+		}
+	})
+
+	t.Run("encoded enum metrics", func(t *testing.T) {
+		query, ok := StateQuery("kube_pod_status_phase")
+		if !ok {
+			t.Fatal("Expected a state query for pod phases")
+		}
+		expected := `max by (namespace, pod) (kube_pod_status_phase{phase="Pending"} * 1 or kube_pod_status_phase{phase="Running"} * 2 or kube_pod_status_phase{phase="Succeeded"} * 3 or kube_pod_status_phase{phase="Failed"} * 4 or kube_pod_status_phase{phase="Unknown"} * 5)`
+		if query != expected {
+			t.Errorf("Expected %s, got %s", expected, query)
+		}
+
+		states := StateMappings(InjectMatchers(query, []LabelMatcher{{Name: "namespace", Op: "=", Value: "shop"}}))
+		if len(states) != 5 || states[1] != (State{Value: 2, Text: "Running", Color: "green"}) {
+			t.Errorf("Expected the pod phases numbered from 1, got %+v", states)
+		}
+		if states := StateMappings("kube_pod_status_phase"); states != nil {
+			t.Errorf("Expected no states for the raw series, got %+v", states)
+		}
+	})
+}
+
+func TestGenerateQueries_States(t *testing.T) {
+	up := &MetricInfo{Name: "up", Type: MetricTypeGauge, Labels: []string{"instance", "job"}}
+	var timeline, history bool
+	for _, suggestion := range generateQueries(up, GenerateOptions{}) {
+		timeline = timeline || suggestion.VisualizationType == "state-timeline" && suggestion.Query == "min by (instance) (up)"
+		history = history || suggestion.VisualizationType == "status-history" && suggestion.Query == "min by (job) (up)"
+	}
+	if !timeline || !history {
+		t.Errorf("Expected a state timeline per instance and a status history per job, got timeline %v history %v", timeline, history)
+	}
+
+	phase := &MetricInfo{Name: "kube_namespace_status_phase", Type: MetricTypeGauge, Labels: []string{"namespace", "phase"}}
+	suggestions := generateQueries(phase, GenerateOptions{})
+	last := suggestions[len(suggestions)-1]
+	if last.VisualizationType != "state-timeline" || len(StateMappings(last.Query)) != 2 {
+		t.Errorf("Expected a state timeline of namespace phases, got %+v", last)
+	}
+}
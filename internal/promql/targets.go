@@ -0,0 +1,110 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// targetIntervalsTTL is how long the scrape intervals of the active targets
+// are reused; a dashboard build looks up the interval of every metric it
+// queries within a few seconds
+const targetIntervalsTTL = time.Minute
+
+// jobIntervals is the longest scrape interval of each job's active targets
+type jobIntervals map[string]time.Duration
+
+// targetIntervalsEntry is the job intervals of one Prometheus
+type targetIntervalsEntry struct {
+	jobs      jobIntervals
+	expiresAt time.Time
+}
+
+// targetIntervals caches the job intervals of each Prometheus, so that
+// resolving the scrape interval of many metrics downloads the target list
+// once rather than once per metric
+type targetIntervals struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]targetIntervalsEntry
+}
+
+// newTargetIntervals returns a cache keeping job intervals for ttl
+func newTargetIntervals(ttl time.Duration) *targetIntervals {
+	return &targetIntervals{ttl: ttl, now: time.Now, entries: map[string]targetIntervalsEntry{}}
+}
+
+// get returns the cached job intervals of a Prometheus, calling fetch when
+// there are none or they expired. A nil cache always fetches.
+func (c *targetIntervals) get(ctx context.Context, baseURL string, fetch func(context.Context) (jobIntervals, error)) (jobIntervals, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[baseURL]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.jobs, nil
+	}
+
+	jobs, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[baseURL] = targetIntervalsEntry{jobs: jobs, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return jobs, nil
+}
+
+// getJobIntervals lists the active targets with /api/v1/targets and returns
+// the longest configured scrape interval of each job
+func (c *prometheusClient) getJobIntervals(ctx context.Context) (jobIntervals, error) {
+	targetsURL := fmt.Sprintf("%s/api/v1/targets?state=active", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", targetsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query targets: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	var targetsResp struct {
+		Status string `json:"status"`
+		Data   struct {
+			ActiveTargets []struct {
+				Labels         map[string]string `json:"labels"`
+				ScrapeInterval string            `json:"scrapeInterval"`
+			} `json:"activeTargets"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&targetsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode targets response: %w", err)
+	}
+
+	jobs := jobIntervals{}
+	for _, target := range targetsResp.Data.ActiveTargets {
+		interval, err := time.ParseDuration(target.ScrapeInterval)
+		if err != nil {
+			continue
+		}
+		if job := target.Labels["job"]; interval > jobs[job] {
+			jobs[job] = interval
+		}
+	}
+	return jobs, nil
+}
@@ -0,0 +1,318 @@
+package promql
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// byPlaceholder stands for the breakdown label in template queries and
+// legends, e.g. "avg by (<by>) (probe_duration_seconds)"
+const byPlaceholder = "<by>"
+
+// TemplateQuery is a query of a template panel
+type TemplateQuery struct {
+	Expr    string `json:"expr"`
+	Legend  string `json:"legend,omitempty"`
+	Instant bool   `json:"instant,omitempty"`
+}
+
+// TemplatePanel is a panel of a built-in dashboard template
+type TemplatePanel struct {
+	Title       string          `json:"title"`
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Unit        string          `json:"unit,omitempty"`
+	Queries     []TemplateQuery `json:"queries"`
+	Thresholds  []Threshold     `json:"thresholds,omitempty"`
+	Min         *float64        `json:"min,omitempty"`
+	Max         *float64        `json:"max,omitempty"`
+	// Group is the row the panel is placed in, empty for the top of the
+	// dashboard
+	Group string `json:"group,omitempty"`
+
+	// requires lists the metrics the queries read; the panel is left out
+	// when one of them is missing
+	requires []string
+}
+
+// TemplateVariable is a query variable of a template listing the values of
+// a label on the series a selector matches. A selector may reference an
+// earlier variable, e.g. the workloads of the selected $namespace.
+type TemplateVariable struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Selector string `json:"selector"`
+	// Source is the label whose values the variable lists
+	Source string `json:"source"`
+}
+
+// Query returns the variable's label_values query with the matchers added
+// to its selector
+func (v TemplateVariable) Query(matchers []LabelMatcher) string {
+	return fmt.Sprintf("label_values(%s, %s)", InjectMatchers(v.Selector, matchers), v.Source)
+}
+
+// DashboardTemplate is a built-in dashboard for a family of metrics
+type DashboardTemplate struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Signature lists the metrics that identify the family; the template
+	// applies when any of them is present
+	Signature []string `json:"signature"`
+	// Breakdown lists the labels the panels break series down by, in order
+	// of preference; the first one the signature metrics carry is used.
+	// Empty when the panels name their labels themselves.
+	Breakdown []string `json:"breakdown,omitempty"`
+	// Variables are the dashboard's query variables, each after the ones
+	// its selector references. They allow several values and All, so
+	// panels match them with =~.
+	Variables []TemplateVariable `json:"variables,omitempty"`
+
+	panels []TemplatePanel
+	// generic templates, such as the runtime ones, apply to any process a
+	// client library instruments, so they are only selected automatically
+	// when no specific template applies
+	generic bool
+}
+
+// dashboardTemplates lists the built-in dashboard templates
+var dashboardTemplates = []DashboardTemplate{
+	syntheticMonitoringTemplate,
+	goRuntimeTemplate,
+	jvmRuntimeTemplate,
+	kafkaLagTemplate,
+	rabbitmqQueuesTemplate,
+	natsTemplate,
+	postgresTemplate,
+	mysqlTemplate,
+	redisTemplate,
+	istioMeshTemplate,
+	nginxIngressTemplate,
+	traefikTemplate,
+	haproxyTemplate,
+	nodeHostTemplate,
+	windowsHostTemplate,
+	nvidiaGPUTemplate,
+	kubernetesContainersTemplate,
+}
+
+// DashboardTemplates returns the built-in dashboard templates
+func DashboardTemplates() []DashboardTemplate {
+	return slices.Clone(dashboardTemplates)
+}
+
+// FindDashboardTemplate returns the built-in template with the given name
+func FindDashboardTemplate(name string) (DashboardTemplate, bool) {
+	for _, template := range dashboardTemplates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return DashboardTemplate{}, false
+}
+
+// ApplicableTemplates returns the templates whose signature metrics are
+// among metricNames
+func ApplicableTemplates(metricNames []string) []DashboardTemplate {
+	var applicable []DashboardTemplate
+	for _, template := range dashboardTemplates {
+		if template.Applies(metricNames) {
+			applicable = append(applicable, template)
+		}
+	}
+	return applicable
+}
+
+// SelectTemplates returns the templates to build automatically for the
+// metrics: the specific templates that apply or, when none does, the
+// generic ones. An ingress controller also exposes Go runtime metrics, but
+// its ingress template is the one to build.
+func SelectTemplates(metricNames []string) []DashboardTemplate {
+	var specific, generic []DashboardTemplate
+	for _, template := range ApplicableTemplates(metricNames) {
+		if template.generic {
+			generic = append(generic, template)
+		} else {
+			specific = append(specific, template)
+		}
+	}
+	if len(specific) > 0 {
+		return specific
+	}
+	return generic
+}
+
+// Applies reports whether any of the template's signature metrics is present
+func (t DashboardTemplate) Applies(metricNames []string) bool {
+	for _, metric := range t.Signature {
+		if slices.Contains(metricNames, metric) {
+			return true
+		}
+	}
+	return false
+}
+
+// Panels renders the template's panels whose metrics are all present,
+// breaking series down by the given label. A breakdown panel that renders
+// the same queries as an earlier panel, as "by instance" panels do when the
+// breakdown falls back to instance, is left out, and so is a panel titled
+// like an earlier one, which lets templates list the same panel for
+// several client libraries in order of preference.
+func (t DashboardTemplate) Panels(metricNames []string, by string) []TemplatePanel {
+	present := make(map[string]bool, len(metricNames))
+	for _, name := range metricNames {
+		present[name] = true
+	}
+
+	var panels []TemplatePanel
+	rendered := map[string]bool{}
+	titles := map[string]bool{}
+	for _, panel := range t.panels {
+		complete := true
+		for _, metric := range panel.requires {
+			complete = complete && present[metric]
+		}
+		if !complete {
+			continue
+		}
+		queries := make([]TemplateQuery, len(panel.Queries))
+		exprs := make([]string, len(panel.Queries))
+		for i, query := range panel.Queries {
+			query.Expr = strings.ReplaceAll(query.Expr, byPlaceholder, by)
+			query.Legend = strings.ReplaceAll(query.Legend, byPlaceholder, by)
+			queries[i], exprs[i] = query, query.Expr
+		}
+		key := strings.Join(exprs, "\n")
+		title := strings.ReplaceAll(panel.Title, byPlaceholder, by)
+		if rendered[key] || titles[title] {
+			continue
+		}
+		rendered[key], titles[title] = true, true
+		panel.Title = title
+		panel.Queries = queries
+		panels = append(panels, panel)
+	}
+	return panels
+}
+
+// syntheticMonitoringTemplate covers the probe_* metrics of blackbox_exporter
+// and Grafana Cloud Synthetic Monitoring, which labels each result with the
+// probe location that ran the check
+var syntheticMonitoringTemplate = DashboardTemplate{
+	Name:        "synthetic_monitoring",
+	Title:       "Synthetic monitoring",
+	Description: "Probe success rate, latency by probe location and SSL certificate expiry for blackbox_exporter and Grafana Cloud Synthetic Monitoring checks",
+	Signature:   []string{probeSuccessMetric},
+	Breakdown:   []string{"probe", "instance"},
+	panels: []TemplatePanel{
+		{
+			Title:       "Probe success",
+			Type:        "stat",
+			Description: "Share of successful probes across all targets over the last 5 minutes.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: "avg(avg_over_time(probe_success[5m]))"}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "red"},
+				{Value: floatPtr(0.95), Color: "orange"},
+				{Value: floatPtr(0.99), Color: "green"},
+			},
+			requires: []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Failing targets",
+			Type:        "stat",
+			Description: "Targets whose latest probe failed.",
+			Unit:        "short",
+			Queries:     []TemplateQuery{{Expr: "count(max by (instance) (probe_success) == 0) or vector(0)"}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(1), Color: "red"},
+			},
+			requires: []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Uptime (24h)",
+			Type:        "stat",
+			Description: "Share of successful probes per target over the last 24 hours.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: uptimeRatio(probeSuccessMetric, "instance", "1d"), Legend: "{{instance}}", Instant: true}},
+			Thresholds:  uptimeThresholds(),
+			Group:       "Availability",
+			requires:    []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Probe results by target",
+			Type:        "state-timeline",
+			Description: "Whether each target's probes succeeded, down when any probe location failed.",
+			Queries:     []TemplateQuery{{Expr: "min by (instance) (probe_success)", Legend: "{{instance}}"}},
+			Group:       "Availability",
+			requires:    []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Success rate by target",
+			Type:        "timeseries",
+			Description: "Share of successful probes per target.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: uptimeRatio(probeSuccessMetric, "instance", "5m"), Legend: "{{instance}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Availability",
+			requires:    []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Success rate by <by>",
+			Type:        "timeseries",
+			Description: "Share of successful probes per probe location; a dip at one location only points at the network path rather than the target.",
+			Unit:        "percentunit",
+			Queries:     []TemplateQuery{{Expr: uptimeRatio(probeSuccessMetric, byPlaceholder, "5m"), Legend: "{{<by>}}"}},
+			Min:         floatPtr(0),
+			Max:         floatPtr(1),
+			Group:       "Availability",
+			requires:    []string{probeSuccessMetric},
+		},
+		{
+			Title:       "Probe latency by <by>",
+			Type:        "timeseries",
+			Description: "Average probe duration per probe location.",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "avg by (<by>) (probe_duration_seconds)", Legend: "{{<by>}}"}},
+			Group:       "Latency",
+			requires:    []string{"probe_duration_seconds"},
+		},
+		{
+			Title:       "HTTP latency by phase",
+			Type:        "timeseries",
+			Description: "Average time spent in each phase of HTTP probes (resolve, connect, tls, processing, transfer).",
+			Unit:        "s",
+			Queries:     []TemplateQuery{{Expr: "avg by (phase) (probe_http_duration_seconds)", Legend: "{{phase}}"}},
+			Group:       "Latency",
+			requires:    []string{"probe_http_duration_seconds"},
+		},
+		{
+			Title:       "Days until certificate expiry",
+			Type:        "stat",
+			Description: "Days until the earliest certificate of each target's chain expires.",
+			Unit:        "d",
+			Queries:     []TemplateQuery{{Expr: certificateDaysLeft(probeSSLExpiryMetric, "instance"), Legend: "{{instance}}", Instant: true}},
+			Thresholds:  countdownThresholds(),
+			Group:       "Certificates",
+			requires:    []string{probeSSLExpiryMetric},
+		},
+		{
+			Title:       "HTTP status code",
+			Type:        "stat",
+			Description: "Latest HTTP status code returned to each target's probe.",
+			Unit:        "none",
+			Queries:     []TemplateQuery{{Expr: "max by (instance) (probe_http_status_code)", Legend: "{{instance}}", Instant: true}},
+			Thresholds: []Threshold{
+				{Value: nil, Color: "green"},
+				{Value: floatPtr(400), Color: "orange"},
+				{Value: floatPtr(500), Color: "red"},
+			},
+			Group:    "Availability",
+			requires: []string{"probe_http_status_code"},
+		},
+	},
+}
@@ -0,0 +1,376 @@
+package promql
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDashboardTemplatePanels(t *testing.T) {
+	template, ok := FindDashboardTemplate("synthetic_monitoring")
+	if !ok {
+		t.Fatal("Expected the synthetic_monitoring template")
+	}
+
+	t.Run("breaks down by probe location", func(t *testing.T) {
+		panels := template.Panels([]string{"probe_success", "probe_duration_seconds"}, "probe")
+
+		titles := map[string]TemplatePanel{}
+		for _, panel := range panels {
+			titles[panel.Title] = panel
+		}
+		latency, ok := titles["Probe latency by probe"]
+		if !ok {
+			t.Fatalf("Expected the latency by probe panel, got %v", titles)
+		}
+		if latency.Queries[0].Expr != "avg by (probe) (probe_duration_seconds)" || latency.Queries[0].Legend != "{{probe}}" {
+			t.Errorf("Unexpected latency query %+v", latency.Queries[0])
+		}
+		if _, ok := titles["Days until certificate expiry"]; ok {
+			t.Error("Expected the certificate panel to need probe_ssl_earliest_cert_expiry")
+		}
+	})
+
+	t.Run("instance breakdown drops duplicate panels", func(t *testing.T) {
+		panels := template.Panels([]string{"probe_success"}, "instance")
+		for _, panel := range panels {
+			if panel.Title == "Success rate by instance" {
+				t.Errorf("Expected the instance breakdown to duplicate the by target panel, got %+v", panels)
+			}
+		}
+	})
+}
+
+func TestApplicableTemplates(t *testing.T) {
+	if found := ApplicableTemplates([]string{"probe_success", "up"}); len(found) != 1 || found[0].Name != "synthetic_monitoring" {
+		t.Errorf("Expected the synthetic_monitoring template, got %+v", found)
+	}
+	if found := ApplicableTemplates([]string{"up"}); len(found) != 0 {
+		t.Errorf("Expected no template, got %+v", found)
+	}
+}
+
+func TestRuntimeTemplatePanels(t *testing.T) {
+	t.Run("go runtime", func(t *testing.T) {
+		template, ok := FindDashboardTemplate("go_runtime")
+		if !ok {
+			t.Fatal("Expected the go_runtime template")
+		}
+		panels := template.Panels([]string{"go_goroutines", "go_threads", "process_open_fds", "process_max_fds"}, "pod")
+
+		titles := map[string]TemplatePanel{}
+		for _, panel := range panels {
+			titles[panel.Title] = panel
+		}
+		goroutines, ok := titles["Goroutines by pod"]
+		if !ok {
+			t.Fatalf("Expected the goroutines by pod panel, got %v", titles)
+		}
+		if goroutines.Queries[0].Expr != "sum by (pod) (go_goroutines)" {
+			t.Errorf("Unexpected goroutines query %+v", goroutines.Queries[0])
+		}
+		if fds, ok := titles["File descriptor usage"]; !ok || fds.Queries[0].Expr != "max(process_open_fds / process_max_fds)" {
+			t.Errorf("Expected the file descriptor usage panel, got %+v", fds)
+		}
+		if _, ok := titles["GC pause duration by pod"]; ok {
+			t.Error("Expected the GC pause panel to need go_gc_duration_seconds")
+		}
+	})
+
+	t.Run("jvm runtime prefers one client library per panel", func(t *testing.T) {
+		template, ok := FindDashboardTemplate("jvm_runtime")
+		if !ok {
+			t.Fatal("Expected the jvm_runtime template")
+		}
+		panels := template.Panels([]string{
+			"jvm_threads_current", "jvm_threads_daemon",
+			"jvm_threads_live_threads", "jvm_threads_daemon_threads",
+			"jvm_gc_pause_seconds_sum", "jvm_gc_pause_seconds_count", "jvm_gc_pause_seconds_max",
+		}, "instance")
+
+		count := map[string]int{}
+		for _, panel := range panels {
+			count[panel.Title]++
+			if panel.Title == "Threads by instance" && panel.Queries[0].Expr != "sum by (instance) (jvm_threads_current)" {
+				t.Errorf("Expected the Java client threads panel first, got %+v", panel.Queries)
+			}
+		}
+		for title, n := range count {
+			if n > 1 {
+				t.Errorf("Expected one %q panel, got %d", title, n)
+			}
+		}
+		if count["GC pause duration by instance"] != 1 {
+			t.Errorf("Expected the Micrometer GC pause panel, got %v", count)
+		}
+	})
+}
+
+func TestQueueTemplateDrainTime(t *testing.T) {
+	tests := []struct {
+		template string
+		metrics  []string
+		by       string
+		title    string
+		expected string
+	}{
+		{
+			template: "kafka_lag",
+			metrics:  []string{"kafka_consumergroup_lag", "kafka_consumergroup_current_offset"},
+			by:       "consumergroup",
+			title:    "Time to drain by consumergroup",
+			expected: "sum by (consumergroup, topic) (kafka_consumergroup_lag) / sum by (consumergroup, topic) (rate(kafka_consumergroup_current_offset[5m]))",
+		},
+		{
+			template: "nats",
+			metrics:  []string{"jetstream_consumer_num_pending", "jetstream_consumer_ack_floor_consumer_seq"},
+			by:       "server_id",
+			title:    "Time to drain by consumer",
+			expected: "sum by (stream_name, consumer_name) (jetstream_consumer_num_pending) / sum by (stream_name, consumer_name) (rate(jetstream_consumer_ack_floor_consumer_seq[5m]))",
+		},
+		{
+			template: "rabbitmq_queues",
+			metrics:  []string{"rabbitmq_queue_messages_ready", "rabbitmq_global_messages_acknowledged_total"},
+			by:       "instance",
+			title:    "Time to drain",
+			expected: "sum(rabbitmq_queue_messages_ready) / sum(rate(rabbitmq_global_messages_acknowledged_total[5m]))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, ok := FindDashboardTemplate(tt.template)
+			if !ok {
+				t.Fatalf("Expected the %s template", tt.template)
+			}
+			for _, panel := range template.Panels(tt.metrics, tt.by) {
+				if panel.Title == tt.title {
+					if panel.Queries[0].Expr != tt.expected {
+						t.Errorf("Expected %q, got %q", tt.expected, panel.Queries[0].Expr)
+					}
+					return
+				}
+			}
+			t.Errorf("Expected the %q panel", tt.title)
+		})
+	}
+}
+
+func TestDatabaseTemplateHitRatios(t *testing.T) {
+	tests := []struct {
+		template string
+		metrics  []string
+		title    string
+		expected string
+		orange   float64
+	}{
+		{
+			template: "postgresql",
+			metrics:  []string{"pg_stat_database_blks_hit", "pg_stat_database_blks_read"},
+			title:    "Cache hit ratio",
+			expected: "min(sum by (instance) (rate(pg_stat_database_blks_hit[5m])) / (sum by (instance) (rate(pg_stat_database_blks_hit[5m])) + sum by (instance) (rate(pg_stat_database_blks_read[5m]))))",
+			orange:   0.95,
+		},
+		{
+			template: "mysql",
+			metrics:  []string{"mysql_global_status_innodb_buffer_pool_reads", "mysql_global_status_innodb_buffer_pool_read_requests"},
+			title:    "Buffer pool hit ratio",
+			expected: "min(1 - sum by (instance) (rate(mysql_global_status_innodb_buffer_pool_reads[5m])) / sum by (instance) (rate(mysql_global_status_innodb_buffer_pool_read_requests[5m])))",
+			orange:   0.95,
+		},
+		{
+			template: "redis",
+			metrics:  []string{"redis_keyspace_hits_total", "redis_keyspace_misses_total"},
+			title:    "Keyspace hit ratio",
+			expected: "min(sum by (instance) (rate(redis_keyspace_hits_total[5m])) / (sum by (instance) (rate(redis_keyspace_hits_total[5m])) + sum by (instance) (rate(redis_keyspace_misses_total[5m]))))",
+			orange:   0.8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, ok := FindDashboardTemplate(tt.template)
+			if !ok {
+				t.Fatalf("Expected the %s template", tt.template)
+			}
+			panels := template.Panels(tt.metrics, "instance")
+			if len(panels) == 0 || panels[0].Title != tt.title {
+				t.Fatalf("Expected the %q panel first, got %+v", tt.title, panels)
+			}
+			if panels[0].Queries[0].Expr != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, panels[0].Queries[0].Expr)
+			}
+			if thresholds := panels[0].Thresholds; len(thresholds) != 3 || thresholds[0].Color != "red" || *thresholds[1].Value != tt.orange {
+				t.Errorf("Expected red below %v, got %+v", tt.orange, thresholds)
+			}
+		})
+	}
+}
+
+func TestTemplateVariableQuery(t *testing.T) {
+	template, ok := FindDashboardTemplate("istio_mesh")
+	if !ok {
+		t.Fatal("Expected the istio_mesh template")
+	}
+	if len(template.Variables) != 2 || template.Variables[0].Name != "namespace" {
+		t.Fatalf("Expected the namespace and workload variables, got %+v", template.Variables)
+	}
+
+	query := template.Variables[1].Query([]LabelMatcher{{Name: "cluster", Op: "=", Value: "$cluster"}})
+	expected := `label_values(istio_requests_total{reporter="destination", destination_workload_namespace=~"$namespace", cluster="$cluster"}, destination_workload)`
+	if query != expected {
+		t.Errorf("Expected %s, got %s", expected, query)
+	}
+}
+
+func TestSelectTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  []string
+		expected []string
+	}{
+		{"ingress over runtime", []string{"nginx_ingress_controller_requests", "go_goroutines"}, []string{"nginx_ingress"}},
+		{"runtime alone", []string{"go_goroutines", "http_requests_total"}, []string{"go_runtime"}},
+		{"several ingresses", []string{"traefik_router_requests_total", "haproxy_frontend_http_requests_total"}, []string{"traefik", "haproxy"}},
+		{"nothing applies", []string{"http_requests_total"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, template := range SelectTemplates(tt.metrics) {
+				names = append(names, template.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestIngressTemplateStatusRatios(t *testing.T) {
+	template, ok := FindDashboardTemplate("nginx_ingress")
+	if !ok {
+		t.Fatal("Expected the nginx_ingress template")
+	}
+	for _, panel := range template.Panels([]string{"nginx_ingress_controller_requests"}, "") {
+		if panel.Title == "5xx ratio by host" {
+			expected := `sum by (host) (rate(nginx_ingress_controller_requests{status=~"5.."}[5m])) / sum by (host) (rate(nginx_ingress_controller_requests[5m]))`
+			if panel.Queries[0].Expr != expected {
+				t.Errorf("Expected %s, got %s", expected, panel.Queries[0].Expr)
+			}
+			return
+		}
+	}
+	t.Error("Expected the 5xx ratio by host panel")
+}
+
+func TestWindowsHostTemplateMetricNames(t *testing.T) {
+	template, ok := FindDashboardTemplate("windows_host")
+	if !ok {
+		t.Fatal("Expected the windows_host template")
+	}
+
+	tests := []struct {
+		name        string
+		metricNames []string
+		memory      string
+	}{
+		{
+			"memory collector",
+			[]string{"windows_cpu_time_total", "windows_memory_physical_free_bytes", "windows_memory_physical_total_bytes", "windows_os_physical_memory_free_bytes", "windows_cs_physical_memory_bytes"},
+			"1 - sum by (instance) (windows_memory_physical_free_bytes) / sum by (instance) (windows_memory_physical_total_bytes)",
+		},
+		{
+			"os and cs collectors",
+			[]string{"windows_cpu_time_total", "windows_os_physical_memory_free_bytes", "windows_cs_physical_memory_bytes"},
+			"1 - sum by (instance) (windows_os_physical_memory_free_bytes) / sum by (instance) (windows_cs_physical_memory_bytes)",
+		},
+		{
+			"wmi prefix",
+			[]string{"wmi_cpu_time_total", "wmi_os_physical_memory_free_bytes", "wmi_cs_physical_memory_bytes"},
+			"1 - sum by (instance) (wmi_os_physical_memory_free_bytes) / sum by (instance) (wmi_cs_physical_memory_bytes)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var memory []string
+			for _, panel := range template.Panels(tt.metricNames, "") {
+				if panel.Title == "Memory usage by host" {
+					memory = append(memory, panel.Queries[0].Expr)
+				}
+			}
+			if len(memory) != 1 || memory[0] != tt.memory {
+				t.Errorf("Expected one memory panel with %s, got %v", tt.memory, memory)
+			}
+		})
+	}
+}
+
+func TestGPUTemplateAllocationPanels(t *testing.T) {
+	template, ok := FindDashboardTemplate("nvidia_gpu")
+	if !ok {
+		t.Fatal("Expected the nvidia_gpu template")
+	}
+
+	titles := func(panels []TemplatePanel) []string {
+		var titles []string
+		for _, panel := range panels {
+			titles = append(titles, panel.Title)
+		}
+		return titles
+	}
+
+	dcgm := []string{"DCGM_FI_DEV_GPU_UTIL", "DCGM_FI_DEV_FB_USED", "DCGM_FI_DEV_FB_FREE", "DCGM_FI_DEV_GPU_TEMP"}
+	if got := titles(template.Panels(dcgm, "")); slices.Contains(got, "GPUs allocated") {
+		t.Errorf("Expected no allocation panels without kube-state-metrics, got %v", got)
+	}
+
+	withRequests := append(dcgm, "kube_pod_container_resource_requests", "kube_pod_status_phase", "kube_node_status_allocatable")
+	for _, panel := range template.Panels(withRequests, "") {
+		if panel.Title == "GPU allocation by node" {
+			expected := `sum by (node) (kube_pod_container_resource_requests{resource="nvidia_com_gpu"} * on (namespace, pod) group_left () (kube_pod_status_phase{phase="Running"} == 1)) / sum by (node) (kube_node_status_allocatable{resource="nvidia_com_gpu"})`
+			if panel.Queries[0].Expr != expected {
+				t.Errorf("Expected %s, got %s", expected, panel.Queries[0].Expr)
+			}
+			return
+		}
+	}
+	t.Error("Expected the GPU allocation by node panel")
+}
+
+func TestKubernetesContainersTemplateUtilization(t *testing.T) {
+	template, ok := FindDashboardTemplate("kubernetes_containers")
+	if !ok {
+		t.Fatal("Expected the kubernetes_containers template")
+	}
+	memory := func(panels []TemplatePanel) TemplatePanel {
+		for _, panel := range panels {
+			if panel.Title == "Memory by container" {
+				return panel
+			}
+		}
+		t.Fatal("Expected the memory by container panel")
+		return TemplatePanel{}
+	}
+
+	cadvisor := []string{"container_cpu_usage_seconds_total", "container_memory_working_set_bytes"}
+	raw := template.Panels(cadvisor, "")
+	if panel := memory(raw); panel.Unit != "bytes" || len(raw) != 2 {
+		t.Errorf("Expected only raw usage without kube-state-metrics, got %+v", raw)
+	}
+
+	panels := template.Panels(append(cadvisor, "kube_pod_container_resource_limits"), "")
+	panel := memory(panels)
+	expected := `sum by (namespace, pod, container) (container_memory_working_set_bytes{container!="", namespace=~"$namespace"}) / sum by (namespace, pod, container) (kube_pod_container_resource_limits{resource="memory", container!="", namespace=~"$namespace"})`
+	if panel.Unit != "percentunit" || panel.Queries[0].Expr != expected {
+		t.Errorf("Expected memory against the limit, got %+v", panel)
+	}
+	if *panel.Thresholds[1].Value != 0.8 || *panel.Thresholds[2].Value != 0.9 {
+		t.Errorf("Expected thresholds at 80%% and 90%%, got %+v", panel.Thresholds)
+	}
+	if len(panels) != 4 {
+		t.Errorf("Expected the limit stats and ratios in place of the raw panels, got %d panels", len(panels))
+	}
+}
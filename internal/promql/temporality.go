@@ -0,0 +1,117 @@
+package promql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TemporalityDelta marks OpenTelemetry delta counters: each sample is the
+// increase since the previous export rather than a running total, so the
+// series rises and falls and rate() reads every drop as a counter reset
+const TemporalityDelta = "delta"
+
+const (
+	// temporalityLookback is the range the resets of a counter are counted over
+	temporalityLookback = "1h"
+	// deltaResetRatio is the share of samples that are resets above which a
+	// counter is taken for a delta counter; cumulative counters only reset
+	// when the process restarts
+	deltaResetRatio = 0.2
+	// deltaMinSamples is the number of samples needed to tell the two apart
+	deltaMinSamples = 10
+)
+
+// isDeltaCandidate reports whether a metric may be a delta counter:
+// OpenTelemetry exporters only add the _total suffix to cumulative sums, so
+// counters without it are checked
+func isDeltaCandidate(metricInfo *MetricInfo) bool {
+	if metricInfo.Type != MetricTypeCounter && metricInfo.Type != MetricTypeUnknown {
+		return false
+	}
+	name := metricInfo.Name
+	return !strings.HasSuffix(name, "_total") && histogramBaseName(name) == name
+}
+
+// temporalityQuery returns the share of a metric's samples over the lookback
+// that are lower than the previous one, or nothing when there are too few
+// samples to judge
+func temporalityQuery(metricName string) string {
+	return fmt.Sprintf("sum(resets(%[1]s[%[2]s])) / sum(count_over_time(%[1]s[%[2]s])) and on() sum(count_over_time(%[1]s[%[2]s])) >= %[3]d",
+		metricName, temporalityLookback, deltaMinSamples)
+}
+
+// detectTemporality tells delta counters from cumulative ones by how often
+// their value drops: a delta counter's samples go up and down with the
+// traffic of each export interval
+func (c *prometheusClient) detectTemporality(ctx context.Context, metricName string) (string, error) {
+	now := time.Now()
+	series, err := c.queryRange(ctx, temporalityQuery(metricName), now, now, time.Minute)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range series {
+		for _, sample := range s.Samples {
+			if sample.Value > deltaResetRatio {
+				return TemporalityDelta, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// generateDeltaCounterQueries generates queries for delta counters: the
+// samples are summed over the window with sum_over_time instead of taking
+// rate() or increase() of a running total
+func generateDeltaCounterQueries(metricInfo *MetricInfo) []QuerySuggestion {
+	metricName := metricInfo.Name
+	window := formatDuration(rateWindow(metricInfo))
+
+	suggestions := []QuerySuggestion{
+		{
+			Query:             fmt.Sprintf("sum_over_time(%s[%s])", metricName, window),
+			Description:       fmt.Sprintf("Total per %s (delta samples summed)", window),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "total",
+		},
+		{
+			Query:             fmt.Sprintf("sum_over_time(%s[1h])", metricName),
+			Description:       "Total over 1 hour (delta samples summed)",
+			VisualizationType: "timeseries",
+			YAxisLabel:        "total",
+		},
+	}
+
+	for _, label := range rankGroupingLabels(metricInfo) {
+		if isHighCardinalityLabel(metricInfo, label) {
+			suggestions = append(suggestions, QuerySuggestion{
+				Query:             fmt.Sprintf("topk(%d, sum by (%s) (sum_over_time(%s[%s])))", topKLimit, label, metricName, window),
+				Description:       fmt.Sprintf("Top %d %s values by total per %s", topKLimit, label, window),
+				VisualizationType: "timeseries",
+				YAxisLabel:        "total",
+				Category:          CategoryTopK,
+			})
+			continue
+		}
+		suggestions = append(suggestions, QuerySuggestion{
+			Query:             fmt.Sprintf("sum by (%s) (sum_over_time(%s[%s]))", label, metricName, window),
+			Description:       fmt.Sprintf("Total per %s grouped by %s", window, label),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "total",
+		})
+	}
+
+	if matcher, ok := errorMatcher(metricInfo); ok {
+		suggestions = append(suggestions, QuerySuggestion{
+			Query: fmt.Sprintf("sum(sum_over_time(%s{%s}[%s])) / sum(sum_over_time(%s[%s]))",
+				metricName, matcher, window, metricName, window),
+			Description:       fmt.Sprintf("Error ratio (%s)", matcher),
+			VisualizationType: "timeseries",
+			YAxisLabel:        "ratio",
+			Category:          CategoryErrorRatio,
+		})
+	}
+
+	return suggestions
+}
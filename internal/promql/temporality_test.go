@@ -0,0 +1,92 @@
+package promql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsDeltaCandidate(t *testing.T) {
+	tests := []struct {
+		metricInfo MetricInfo
+		want       bool
+	}{
+		{MetricInfo{Name: "http_server_requests", Type: MetricTypeCounter}, true},
+		{MetricInfo{Name: "queue_messages_processed", Type: MetricTypeUnknown}, true},
+		{MetricInfo{Name: "http_requests_total", Type: MetricTypeCounter}, false},
+		{MetricInfo{Name: "orders_count", Type: MetricTypeCounter}, false},
+		{MetricInfo{Name: "queue_depth", Type: MetricTypeGauge}, false},
+	}
+	for _, tt := range tests {
+		if got := isDeltaCandidate(&tt.metricInfo); got != tt.want {
+			t.Errorf("isDeltaCandidate(%s) = %v, want %v", tt.metricInfo.Name, got, tt.want)
+		}
+	}
+}
+
+func TestPrometheusClientDetectTemporality(t *testing.T) {
+	ratios := map[string]string{"checkout_orders": "0.46", "checkout_payments": "0.01"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = r.ParseForm()
+		query := r.Form.Get("query")
+		result := []any{}
+		for metric, ratio := range ratios {
+			if strings.HasPrefix(query, "sum(resets("+metric+"[1h]))") {
+				result = append(result, map[string]any{"metric": map[string]string{}, "values": [][]any{{1700000000.0, ratio}}})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   map[string]any{"resultType": "matrix", "result": result},
+		})
+	}))
+	defer server.Close()
+
+	client := newPrometheusClient(server.URL, nil)
+	tests := map[string]string{"checkout_orders": TemporalityDelta, "checkout_payments": "", "checkout_refunds": ""}
+	for metric, want := range tests {
+		got, err := client.detectTemporality(context.Background(), metric)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("detectTemporality(%s) = %q, want %q", metric, got, want)
+		}
+	}
+}
+
+func TestGenerateQueriesForDeltaCounters(t *testing.T) {
+	metricInfo := &MetricInfo{
+		Name:        "http_server_requests",
+		Type:        MetricTypeCounter,
+		Labels:      []string{"status"},
+		Temporality: TemporalityDelta,
+	}
+
+	suggestions := generateQueries(metricInfo, GenerateOptions{})
+	if suggestions[0].Query != "sum_over_time(http_server_requests[5m])" || suggestions[0].DashboardQuery != "sum_over_time(http_server_requests[$__rate_interval])" {
+		t.Errorf("unexpected primary suggestion %+v", suggestions[0])
+	}
+	for _, s := range suggestions {
+		if strings.Contains(s.Query, "rate(") || strings.Contains(s.Query, "increase(") {
+			t.Errorf("expected no cumulative counter functions, got %s", s.Query)
+		}
+	}
+	last := suggestions[len(suggestions)-1]
+	if last.Query != `sum(sum_over_time(http_server_requests{status=~"5.."}[5m])) / sum(sum_over_time(http_server_requests[5m]))` {
+		t.Errorf("unexpected error ratio %s", last.Query)
+	}
+
+	for _, rule := range generateAlertRules(metricInfo) {
+		if strings.HasSuffix(rule.Name, "CounterReset") {
+			t.Errorf("expected no counter reset alert for a delta counter, got %s", rule.Expr)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package promql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TrendsWindow is the window the queries of a trends dashboard aggregate
+// over: one point per day, which downsampled data answers cheaply
+const TrendsWindow = "1d"
+
+// subqueryWindow matches the window of a subquery, e.g. [1h:5m]
+var subqueryWindow = regexp.MustCompile(`\[[^\]]*:[^\]]*\]`)
+
+// TrendsQuery rewrites a real-time query for a trends dashboard: rate() and
+// irate() become increase() over a day, other range functions take a day,
+// and bare selectors become their daily avg_over_time(). It reports whether
+// a per-second rate became a daily count, so the panel unit can follow.
+// Subqueries already choose their own resolution and are left unchanged.
+func TrendsQuery(query string) (string, bool) {
+	if subqueryWindow.MatchString(query) {
+		return query, false
+	}
+
+	perDay := false
+	selectors := ParseSelectors(query)
+	// Rewrite from the end so earlier offsets stay valid
+	for i := len(selectors) - 1; i >= 0; i-- {
+		selector := selectors[i]
+		end := selector.End
+		if end < len(query) && query[end] == '[' {
+			close := strings.IndexByte(query[end:], ']')
+			if close < 0 {
+				continue
+			}
+			query = query[:end] + "[" + TrendsWindow + "]" + query[end+close+1:]
+			start, _, ok := enclosingCall(query, selector.Start)
+			if !ok {
+				continue
+			}
+			for _, name := range []string{"rate", "irate"} {
+				if strings.HasPrefix(query[start:], name+"(") || strings.HasPrefix(query[start:], name+" (") {
+					query = query[:start] + "increase" + query[start+len(name):]
+					perDay = true
+					break
+				}
+			}
+			continue
+		}
+
+		for {
+			modifier := replicaModifier.FindString(query[end:])
+			if modifier == "" {
+				break
+			}
+			end += len(modifier)
+		}
+		query = query[:selector.Start] + "avg_over_time(" + query[selector.Start:selector.End] +
+			"[" + TrendsWindow + "]" + query[selector.End:end] + ")" + query[end:]
+	}
+	return query, perDay
+}
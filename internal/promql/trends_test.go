@@ -0,0 +1,25 @@
+package promql
+
+import "testing"
+
+func TestTrendsQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		want   string
+		perDay bool
+	}{
+		{`sum by (job) (rate(http_requests_total[$__rate_interval]))`, `sum by (job) (increase(http_requests_total[1d]))`, true},
+		{`sum(rate(errors_total[5m])) / sum(rate(requests_total[5m]))`, `sum(increase(errors_total[1d])) / sum(increase(requests_total[1d]))`, true},
+		{`histogram_quantile(0.95, sum by (le) (rate(x_bucket{job="a"}[5m])))`, `histogram_quantile(0.95, sum by (le) (increase(x_bucket{job="a"}[1d])))`, true},
+		{`max_over_time(queue_depth[5m])`, `max_over_time(queue_depth[1d])`, false},
+		{`avg(node_memory_bytes{instance="$instance"})`, `avg(avg_over_time(node_memory_bytes{instance="$instance"}[1d]))`, false},
+		{`sum(queue_depth offset 1w)`, `sum(avg_over_time(queue_depth[1d] offset 1w))`, false},
+		{`max_over_time(rate(x[5m])[1h:5m])`, `max_over_time(rate(x[5m])[1h:5m])`, false},
+	}
+	for _, tt := range tests {
+		got, perDay := TrendsQuery(tt.query)
+		if got != tt.want || perDay != tt.perDay {
+			t.Errorf("TrendsQuery(%s) = %s, %v, want %s, %v", tt.query, got, perDay, tt.want, tt.perDay)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package redact keeps credentials out of logs: it masks secret-named
+// fields, Authorization header values, Grafana tokens and URL passwords in
+// log entries and in structures such as the configuration dumped at startup
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+	zapcore "go.uber.org/zap/zapcore"
+)
+
+// Placeholder replaces redacted values
+const Placeholder = "[REDACTED]"
+
+// secretSuffixes end the normalized names of fields holding credentials
+var secretSuffixes = []string{"apikey", "token", "secret", "password", "secretkey", "authorization", "credentials", "dsn"}
+
+var (
+	// schemePattern matches the credentials of Authorization header values
+	schemePattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// grafanaTokenPattern matches Grafana service account and Cloud tokens
+	grafanaTokenPattern = regexp.MustCompile(`\bgl(?:sa|c)_[A-Za-z0-9_=+/-]+`)
+	// parameterPattern matches credentials passed as URL or form parameters
+	parameterPattern = regexp.MustCompile(`(?i)\b(api[_-]?key|access_token|token|secret|password)=[^&\s"]+`)
+	// userinfoPattern matches the password in the userinfo of URLs and DSNs
+	userinfoPattern = regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.-]*://[^/\s:@]+):[^/\s@]+@`)
+)
+
+// IsSecretName reports whether a field or key name denotes a credential,
+// such as APIKey, client_secret or Authorization
+func IsSecretName(name string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(name))
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// String masks the credentials embedded in free text: Authorization header
+// values, Grafana tokens, credential URL parameters and URL passwords
+func String(s string) string {
+	s = schemePattern.ReplaceAllString(s, "$1 "+Placeholder)
+	s = userinfoPattern.ReplaceAllString(s, "$1:"+Placeholder+"@")
+	s = grafanaTokenPattern.ReplaceAllString(s, Placeholder)
+	return parameterPattern.ReplaceAllString(s, "$1="+Placeholder)
+}
+
+// Value returns a copy of v fit for logging: structs become maps of their
+// exported fields, and non-empty strings in secret-named fields or map keys
+// are replaced by Placeholder
+func Value(v any) any {
+	if v == nil {
+		return nil
+	}
+	return value(reflect.ValueOf(v), false)
+}
+
+// value redacts v; secret is set when v is held by a secret-named field
+func value(v reflect.Value, secret bool) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return value(v.Elem(), secret)
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t
+		}
+		fields := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fields[field.Name] = value(v.Field(i), IsSecretName(field.Name))
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			entries[key] = value(iter.Value(), IsSecretName(key))
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = value(v.Index(i), secret)
+		}
+		return items
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			return Placeholder
+		}
+		return String(v.String())
+	case reflect.Int64:
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String()
+		}
+		return v.Int()
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	default:
+		return v.Interface()
+	}
+}
+
+// Fields redacts log fields: secret-named fields are masked entirely, and
+// strings, errors and reflected values are scrubbed of credentials
+func Fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = redactField(field)
+	}
+	return redacted
+}
+
+// redactField redacts a single log field
+func redactField(field zapcore.Field) zapcore.Field {
+	if IsSecretName(field.Key) && field.Type != zapcore.SkipType {
+		return zap.String(field.Key, Placeholder)
+	}
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = String(field.String)
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok && err != nil {
+			return zap.String(field.Key, String(err.Error()))
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok && stringer != nil {
+			return zap.String(field.Key, String(stringer.String()))
+		}
+	case zapcore.ReflectType:
+		return zap.Any(field.Key, Value(field.Interface))
+	}
+	return field
+}
+
+// core redacts every entry before handing it to the wrapped core
+type core struct {
+	zapcore.Core
+}
+
+// Core wraps a zap core so that nothing it writes contains credentials; use
+// it with zap.WrapCore
+func Core(next zapcore.Core) zapcore.Core {
+	return &core{Core: next}
+}
+
+// With implements zapcore.Core
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(Fields(fields))}
+}
+
+// Check implements zapcore.Core
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = String(entry.Message)
+	return c.Core.Write(entry, Fields(fields))
+}
@@ -0,0 +1,82 @@
+package redact
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	require "github.com/stretchr/testify/require"
+	zap "go.uber.org/zap"
+	zapcore "go.uber.org/zap/zapcore"
+	observer "go.uber.org/zap/zaptest/observer"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestIsSecretName(t *testing.T) {
+	for _, name := range []string{"APIKey", "api_key", "ClientSecret", "Authorization", "refresh-token", "SecretKey", "INVENTORY_DSN"} {
+		require.True(t, IsSecretName(name), name)
+	}
+	for _, name := range []string{"MaxTokens", "TokenURL", "TokenTTL", "keys", "url"} {
+		require.False(t, IsSecretName(name), name)
+	}
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "Authorization: Bearer [REDACTED]", String("Authorization: Bearer eyJhbGciOi.abc-def"))
+	require.Equal(t, "token [REDACTED] rejected", String("token glsa_AbC123_def rejected"))
+	require.Equal(t, "GET /api?api_key=[REDACTED]&q=up", String("GET /api?api_key=s3cret&q=up"))
+	require.Equal(t, "open postgres://agent:[REDACTED]@db/inv failed", String("open postgres://agent:pass@db/inv failed"))
+	require.Equal(t, "https://grafana.example.com/d/abc", String("https://grafana.example.com/d/abc"))
+	require.Equal(t, "no credentials here", String("no credentials here"))
+}
+
+func TestValue(t *testing.T) {
+	cfg := config.Config{}
+	cfg.A2A.AgentConfig.APIKey = "llm-key"
+	cfg.A2A.AgentConfig.MaxTokens = 4096
+	cfg.Grafana.URL = "https://grafana.example.com"
+	cfg.Grafana.APIKey = "glsa_secret"
+	cfg.Grafana.OAuth.ClientSecret = "oauth-secret"
+	cfg.Grafana.Cloud.TokenTTL = time.Hour
+	cfg.Inventory.DSN = "postgres://agent:pass@db/inv"
+	cfg.Alerting.Labels = map[string]string{"team": "payments", "webhook_token": "abc"}
+
+	redacted := Value(cfg).(map[string]any)
+	grafana := redacted["Grafana"].(map[string]any)
+	require.Equal(t, Placeholder, grafana["APIKey"])
+	require.Equal(t, "https://grafana.example.com", grafana["URL"])
+	require.Equal(t, Placeholder, grafana["OAuth"].(map[string]any)["ClientSecret"])
+	require.Equal(t, "", grafana["Cloud"].(map[string]any)["Token"])
+	require.Equal(t, "1h0m0s", grafana["Cloud"].(map[string]any)["TokenTTL"])
+
+	require.Equal(t, Placeholder, redacted["Inventory"].(map[string]any)["DSN"])
+
+	agent := redacted["A2A"].(map[string]any)["AgentConfig"].(map[string]any)
+	require.Equal(t, Placeholder, agent["APIKey"])
+	require.EqualValues(t, 4096, agent["MaxTokens"])
+
+	labels := redacted["Alerting"].(map[string]any)["Labels"].(map[string]any)
+	require.Equal(t, map[string]any{"team": "payments", "webhook_token": Placeholder}, labels)
+	require.Equal(t, "glsa_secret", cfg.Grafana.APIKey)
+}
+
+func TestCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(observed).WithOptions(zap.WrapCore(Core)).With(zap.String("api_key", "k"))
+
+	logger.Info("calling with Bearer abc.def",
+		zap.String("header", "Bearer abc.def"),
+		zap.Error(errors.New("request ?token=xyz failed")),
+		zap.Any("config", struct{ Password, User string }{"hunter2", "admin"}),
+		zap.Int("panels", 3))
+
+	entry := logs.All()[0]
+	require.Equal(t, "calling with Bearer [REDACTED]", entry.Message)
+	fields := entry.ContextMap()
+	require.Equal(t, Placeholder, fields["api_key"])
+	require.Equal(t, "Bearer [REDACTED]", fields["header"])
+	require.Equal(t, "request ?token=[REDACTED] failed", fields["error"])
+	require.Equal(t, map[string]any{"Password": Placeholder, "User": "admin"}, fields["config"])
+	require.EqualValues(t, 3, fields["panels"])
+}
@@ -0,0 +1,131 @@
+// Package remotewrite renders remote_write configuration for shipping
+// metrics to Grafana Cloud, as a Prometheus configuration snippet or a
+// Grafana Alloy component
+package remotewrite
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Where the generated snippets read the Grafana Cloud access policy token
+// from; the token itself is never written into the configuration
+const (
+	PasswordFile = "/etc/prometheus/grafana-cloud-token"
+	PasswordEnv  = "GRAFANA_CLOUD_TOKEN"
+)
+
+// Relabel actions used by the generated rules
+const (
+	ActionKeep = "keep"
+	ActionDrop = "drop"
+)
+
+// seriesSuffixes are the series a histogram or summary metric is exposed as
+var seriesSuffixes = []string{"_bucket", "_sum", "_count"}
+
+// Config is a remote_write endpoint with the relabel rules applied before
+// samples are sent
+type Config struct {
+	URL string
+	// Username is the Grafana Cloud Prometheus instance ID; empty leaves
+	// out basic auth
+	Username string
+	Rules    []Rule
+}
+
+// Rule is a write relabel rule
+type Rule struct {
+	SourceLabels []string `yaml:"source_labels,flow" json:"source_labels"`
+	Regex        string   `yaml:"regex" json:"regex"`
+	Action       string   `yaml:"action" json:"action"`
+}
+
+// MetricRule matches the series of the given metrics by name, including the
+// _bucket, _sum and _count series of histograms and summaries
+func MetricRule(action string, metrics []string) Rule {
+	names := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		names = append(names, regexp.QuoteMeta(metric))
+	}
+	sort.Strings(names)
+	return Rule{
+		SourceLabels: []string{"__name__"},
+		Regex:        fmt.Sprintf("(%s)(%s)?", strings.Join(names, "|"), strings.Join(seriesSuffixes, "|")),
+		Action:       action,
+	}
+}
+
+// prometheusConfig is the remote_write section of a Prometheus configuration
+type prometheusConfig struct {
+	RemoteWrite []prometheusRemoteWrite `yaml:"remote_write"`
+}
+
+type prometheusRemoteWrite struct {
+	URL                 string     `yaml:"url"`
+	BasicAuth           *basicAuth `yaml:"basic_auth,omitempty"`
+	WriteRelabelConfigs []Rule     `yaml:"write_relabel_configs,omitempty"`
+}
+
+type basicAuth struct {
+	Username     string `yaml:"username"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+// Prometheus renders the remote_write section of prometheus.yml
+func Prometheus(cfg Config) ([]byte, error) {
+	remoteWrite := prometheusRemoteWrite{URL: cfg.URL, WriteRelabelConfigs: cfg.Rules}
+	if cfg.Username != "" {
+		remoteWrite.BasicAuth = &basicAuth{Username: cfg.Username, PasswordFile: PasswordFile}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(prometheusConfig{RemoteWrite: []prometheusRemoteWrite{remoteWrite}}); err != nil {
+		return nil, fmt.Errorf("failed to encode remote_write config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode remote_write config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Alloy renders a prometheus.remote_write component; scrape components
+// forward to prometheus.remote_write.grafana_cloud.receiver
+func Alloy(cfg Config) string {
+	var b strings.Builder
+	b.WriteString("prometheus.remote_write \"grafana_cloud\" {\n")
+	b.WriteString("  endpoint {\n")
+	fmt.Fprintf(&b, "    url = %s\n", alloyString(cfg.URL))
+	if cfg.Username != "" {
+		b.WriteString("\n    basic_auth {\n")
+		fmt.Fprintf(&b, "      username = %s\n", alloyString(cfg.Username))
+		fmt.Fprintf(&b, "      password = sys.env(%s)\n", alloyString(PasswordEnv))
+		b.WriteString("    }\n")
+	}
+	for _, rule := range cfg.Rules {
+		labels := make([]string, 0, len(rule.SourceLabels))
+		for _, label := range rule.SourceLabels {
+			labels = append(labels, alloyString(label))
+		}
+		b.WriteString("\n    write_relabel_config {\n")
+		fmt.Fprintf(&b, "      source_labels = [%s]\n", strings.Join(labels, ", "))
+		fmt.Fprintf(&b, "      regex         = %s\n", alloyString(rule.Regex))
+		fmt.Fprintf(&b, "      action        = %s\n", alloyString(rule.Action))
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// alloyString quotes a value as an Alloy string literal
+func alloyString(value string) string {
+	return fmt.Sprintf("%q", value)
+}
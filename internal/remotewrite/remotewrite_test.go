@@ -0,0 +1,62 @@
+package remotewrite
+
+import (
+	"regexp"
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestMetricRule(t *testing.T) {
+	rule := MetricRule(ActionKeep, []string{"up", "http_request_duration_seconds"})
+	require.Equal(t, []string{"__name__"}, rule.SourceLabels)
+	require.Equal(t, "(http_request_duration_seconds|up)(_bucket|_sum|_count)?", rule.Regex)
+
+	// relabel regexes are fully anchored
+	anchored := regexp.MustCompile("^(?:" + rule.Regex + ")$")
+	require.True(t, anchored.MatchString("http_request_duration_seconds_bucket"))
+	require.True(t, anchored.MatchString("up"))
+	require.False(t, anchored.MatchString("up_time"))
+}
+
+func TestPrometheus(t *testing.T) {
+	data, err := Prometheus(Config{
+		URL:      "https://prometheus-prod-01-eu-west-0.grafana.net/api/prom/push",
+		Username: "123456",
+		Rules:    []Rule{MetricRule(ActionKeep, []string{"up"}), MetricRule(ActionDrop, []string{"http_requests_total"})},
+	})
+	require.NoError(t, err)
+
+	var decoded struct {
+		RemoteWrite []struct {
+			URL       string `yaml:"url"`
+			BasicAuth struct {
+				Username     string `yaml:"username"`
+				PasswordFile string `yaml:"password_file"`
+			} `yaml:"basic_auth"`
+			WriteRelabelConfigs []Rule `yaml:"write_relabel_configs"`
+		} `yaml:"remote_write"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	require.Len(t, decoded.RemoteWrite, 1)
+	require.Equal(t, "123456", decoded.RemoteWrite[0].BasicAuth.Username)
+	require.Equal(t, PasswordFile, decoded.RemoteWrite[0].BasicAuth.PasswordFile)
+	require.Len(t, decoded.RemoteWrite[0].WriteRelabelConfigs, 2)
+	require.Equal(t, ActionDrop, decoded.RemoteWrite[0].WriteRelabelConfigs[1].Action)
+	require.Contains(t, string(data), "source_labels: [__name__]")
+}
+
+func TestAlloy(t *testing.T) {
+	config := Alloy(Config{
+		URL:      "https://prometheus.example/api/prom/push",
+		Username: "123456",
+		Rules:    []Rule{MetricRule(ActionDrop, []string{"http_requests_total"})},
+	})
+
+	require.Contains(t, config, "prometheus.remote_write \"grafana_cloud\" {\n  endpoint {\n    url = \"https://prometheus.example/api/prom/push\"\n")
+	require.Contains(t, config, "password = sys.env(\"GRAFANA_CLOUD_TOKEN\")")
+	require.Contains(t, config, "      regex         = \"(http_requests_total)(_bucket|_sum|_count)?\"\n      action        = \"drop\"\n")
+
+	require.NotContains(t, Alloy(Config{URL: "http://mimir:9009/api/v1/push"}), "basic_auth")
+}
@@ -0,0 +1,271 @@
+// Package rulefile converts alert rules into Prometheus rule files, the
+// `groups:` YAML loaded by Prometheus, Thanos Ruler and Mimir
+package rulefile
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// File is a Prometheus rule file
+type File struct {
+	Groups []Group `yaml:"groups" json:"groups"`
+}
+
+// Group is a rule group; its rules are evaluated together at Interval
+type Group struct {
+	Name     string `yaml:"name" json:"name"`
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule is a Prometheus alerting rule
+type Rule struct {
+	Alert       string            `yaml:"alert" json:"alert"`
+	Expr        string            `yaml:"expr" json:"expr"`
+	For         string            `yaml:"for,omitempty" json:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// Skipped is a Grafana rule that has no Prometheus equivalent
+type Skipped struct {
+	UID    string `json:"uid"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// variablePattern matches $A and ${A} references in math expressions
+var variablePattern = regexp.MustCompile(`\$\{?([A-Za-z0-9_]+)\}?`)
+
+// Marshal renders a rule file as YAML
+func Marshal(file File) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, fmt.Errorf("failed to encode rule file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode rule file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FromCandidates builds a rule group from alert rule candidates generated
+// by the agent
+func FromCandidates(name string, candidates []promql.AlertRuleCandidate) Group {
+	group := Group{Name: name, Rules: make([]Rule, 0, len(candidates))}
+	for _, candidate := range candidates {
+		rule := Rule{
+			Alert:  candidate.Name,
+			Expr:   candidate.Expr,
+			For:    candidate.For,
+			Labels: map[string]string{},
+		}
+		if rule.For == "0m" || rule.For == "0s" {
+			rule.For = ""
+		}
+		if candidate.Severity != "" {
+			rule.Labels["severity"] = candidate.Severity
+		}
+		for key, value := range candidate.Labels {
+			rule.Labels[key] = value
+		}
+		if candidate.Description != "" {
+			rule.Annotations = map[string]string{"description": candidate.Description}
+		}
+		group.Rules = append(group.Rules, rule)
+	}
+	return group
+}
+
+// FromGrafana converts Grafana-managed alert rules into rule groups, one per
+// folder and rule group. A rule converts when its condition is a Prometheus
+// query, optionally reduced with "last" and compared by a threshold or math
+// expression; other rules are skipped with the reason. isPrometheus reports
+// whether a datasource UID refers to a Prometheus-compatible datasource.
+func FromGrafana(rules []grafana.AlertRule, isPrometheus func(uid string) bool) (File, []Skipped) {
+	type groupKey struct{ folder, name string }
+	groups := map[groupKey]*Group{}
+	names := map[string]int{}
+	var keys []groupKey
+	var skipped []Skipped
+
+	for _, rule := range rules {
+		if rule.IsPaused {
+			skipped = append(skipped, Skipped{UID: rule.UID, Title: rule.Title, Reason: "rule is paused"})
+			continue
+		}
+		expr, err := conditionExpr(rule, isPrometheus)
+		if err != nil {
+			skipped = append(skipped, Skipped{UID: rule.UID, Title: rule.Title, Reason: err.Error()})
+			continue
+		}
+
+		key := groupKey{rule.FolderUID, rule.RuleGroup}
+		group, ok := groups[key]
+		if !ok {
+			group = &Group{Name: rule.RuleGroup}
+			groups[key] = group
+			keys = append(keys, key)
+			names[rule.RuleGroup]++
+		}
+		group.Rules = append(group.Rules, grafanaRule(rule, expr))
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].folder < keys[j].folder
+	})
+
+	file := File{Groups: make([]Group, 0, len(keys))}
+	for _, key := range keys {
+		group := *groups[key]
+		// Group names are only unique within a Grafana folder
+		if names[key.name] > 1 {
+			group.Name = key.name + "-" + key.folder
+		}
+		file.Groups = append(file.Groups, group)
+	}
+	return file, skipped
+}
+
+// grafanaRule converts a Grafana rule whose condition is expr
+func grafanaRule(rule grafana.AlertRule, expr string) Rule {
+	converted := Rule{Alert: rule.Title, Expr: expr, For: rule.For, Labels: rule.Labels}
+	if converted.For == "0s" || converted.For == "0m" {
+		converted.For = ""
+	}
+	for key, value := range rule.Annotations {
+		// Dashboard links only mean something inside Grafana
+		if strings.HasPrefix(key, "__") {
+			continue
+		}
+		if converted.Annotations == nil {
+			converted.Annotations = map[string]string{}
+		}
+		converted.Annotations[key] = value
+	}
+	return converted
+}
+
+// conditionExpr builds the PromQL expression equivalent to the condition of
+// a Grafana rule
+func conditionExpr(rule grafana.AlertRule, isPrometheus func(string) bool) (string, error) {
+	queries := make(map[string]grafana.AlertQuery, len(rule.Data))
+	for _, query := range rule.Data {
+		queries[query.RefID] = query
+	}
+
+	var resolve func(refID string, depth int) (string, error)
+	resolve = func(refID string, depth int) (string, error) {
+		query, ok := queries[refID]
+		if !ok {
+			return "", fmt.Errorf("query %s not found", refID)
+		}
+		if depth > len(rule.Data) {
+			return "", fmt.Errorf("expression %s references itself", refID)
+		}
+
+		if query.DatasourceUID != grafana.ExpressionDatasourceUID {
+			expr, _ := query.Model["expr"].(string)
+			if expr == "" || !isPrometheus(query.DatasourceUID) {
+				return "", fmt.Errorf("query %s is not a Prometheus query", refID)
+			}
+			return expr, nil
+		}
+
+		expressionType, _ := query.Model["type"].(string)
+		input, _ := query.Model["expression"].(string)
+		switch expressionType {
+		case "reduce":
+			if reducer, _ := query.Model["reducer"].(string); reducer != "last" {
+				return "", fmt.Errorf("reduce %q of %s has no PromQL equivalent", reducer, refID)
+			}
+			return resolve(input, depth+1)
+		case "threshold":
+			inner, err := resolve(input, depth+1)
+			if err != nil {
+				return "", err
+			}
+			return thresholdExpr(inner, query.Model)
+		case "math":
+			var resolveErr error
+			expr := variablePattern.ReplaceAllStringFunc(input, func(reference string) string {
+				inner, err := resolve(variablePattern.FindStringSubmatch(reference)[1], depth+1)
+				if err != nil && resolveErr == nil {
+					resolveErr = err
+				}
+				return "(" + inner + ")"
+			})
+			return expr, resolveErr
+		default:
+			return "", fmt.Errorf("%s expression %s has no PromQL equivalent", expressionType, refID)
+		}
+	}
+
+	return resolve(rule.Condition, 0)
+}
+
+// thresholdExpr appends the comparison of a threshold expression to expr
+func thresholdExpr(expr string, model map[string]any) (string, error) {
+	conditions, _ := model["conditions"].([]any)
+	if len(conditions) != 1 {
+		return "", fmt.Errorf("threshold must have exactly one condition")
+	}
+	condition, _ := conditions[0].(map[string]any)
+	evaluator, _ := condition["evaluator"].(map[string]any)
+	evaluatorType, _ := evaluator["type"].(string)
+	params, _ := evaluator["params"].([]any)
+
+	value := func(i int) (string, error) {
+		if i >= len(params) {
+			return "", fmt.Errorf("threshold %s is missing a parameter", evaluatorType)
+		}
+		number, ok := params[i].(float64)
+		if !ok {
+			return "", fmt.Errorf("threshold %s parameter is not a number", evaluatorType)
+		}
+		return fmt.Sprint(number), nil
+	}
+
+	expr = "(" + expr + ")"
+	switch evaluatorType {
+	case "gt", "lt":
+		v, err := value(0)
+		if err != nil {
+			return "", err
+		}
+		op := ">"
+		if evaluatorType == "lt" {
+			op = "<"
+		}
+		return fmt.Sprintf("%s %s %s", expr, op, v), nil
+	case "within_range", "outside_range":
+		low, err := value(0)
+		if err != nil {
+			return "", err
+		}
+		high, err := value(1)
+		if err != nil {
+			return "", err
+		}
+		if evaluatorType == "within_range" {
+			return fmt.Sprintf("%[1]s > %[2]s and %[1]s < %[3]s", expr, low, high), nil
+		}
+		return fmt.Sprintf("%[1]s < %[2]s or %[1]s > %[3]s", expr, low, high), nil
+	default:
+		return "", fmt.Errorf("threshold %q has no PromQL equivalent", evaluatorType)
+	}
+}
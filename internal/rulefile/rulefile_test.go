@@ -0,0 +1,98 @@
+package rulefile
+
+import (
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+func promQuery(refID, expr string) grafana.AlertQuery {
+	return grafana.AlertQuery{RefID: refID, DatasourceUID: "prom", Model: map[string]any{"expr": expr}}
+}
+
+func expression(refID string, model map[string]any) grafana.AlertQuery {
+	return grafana.AlertQuery{RefID: refID, DatasourceUID: grafana.ExpressionDatasourceUID, Model: model}
+}
+
+func threshold(refID, input, evaluator string, params ...any) grafana.AlertQuery {
+	return expression(refID, map[string]any{
+		"type": "threshold", "expression": input,
+		"conditions": []any{map[string]any{"evaluator": map[string]any{"type": evaluator, "params": params}}},
+	})
+}
+
+func isPrometheus(uid string) bool { return uid == "prom" }
+
+func TestFromGrafana(t *testing.T) {
+	rules := []grafana.AlertRule{
+		{
+			UID: "errors", Title: "HighErrorRate", FolderUID: "platform", RuleGroup: "checkout", Condition: "C", For: "5m",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "Errors", "__dashboardUid__": "checkout"},
+			Data: []grafana.AlertQuery{
+				promQuery("A", "sum(rate(errors_total[5m]))"),
+				expression("B", map[string]any{"type": "reduce", "expression": "A", "reducer": "last"}),
+				threshold("C", "B", "gt", 0.05),
+			},
+		},
+		{
+			UID: "latency", Title: "LatencyOutOfRange", FolderUID: "platform", RuleGroup: "checkout", Condition: "B", For: "0s",
+			Data: []grafana.AlertQuery{promQuery("A", "p99_latency"), threshold("B", "A", "outside_range", 0.1, 2.0)},
+		},
+		{
+			UID: "saturation", Title: "Saturation", FolderUID: "infra", RuleGroup: "checkout", Condition: "B",
+			Data: []grafana.AlertQuery{promQuery("A", "cpu_usage"), expression("B", map[string]any{"type": "math", "expression": "${A} * 100 > 90"})},
+		},
+		{
+			UID: "mean", Title: "Mean", RuleGroup: "other", Condition: "B",
+			Data: []grafana.AlertQuery{promQuery("A", "up"), expression("B", map[string]any{"type": "reduce", "expression": "A", "reducer": "mean"})},
+		},
+		{
+			UID: "logs", Title: "Logs", RuleGroup: "other", Condition: "A",
+			Data: []grafana.AlertQuery{{RefID: "A", DatasourceUID: "loki", Model: map[string]any{"expr": `count_over_time({app="x"}[5m])`}}},
+		},
+		{UID: "paused", Title: "Paused", RuleGroup: "other", Condition: "A", IsPaused: true, Data: []grafana.AlertQuery{promQuery("A", "up")}},
+	}
+
+	file, skipped := FromGrafana(rules, isPrometheus)
+	require.Len(t, file.Groups, 2)
+	require.Equal(t, "checkout-infra", file.Groups[0].Name)
+	require.Equal(t, "(cpu_usage) * 100 > 90", file.Groups[0].Rules[0].Expr)
+
+	checkout := file.Groups[1]
+	require.Equal(t, "checkout-platform", checkout.Name)
+	require.Equal(t, Rule{
+		Alert: "HighErrorRate", Expr: "(sum(rate(errors_total[5m]))) > 0.05", For: "5m",
+		Labels:      map[string]string{"severity": "critical"},
+		Annotations: map[string]string{"summary": "Errors"},
+	}, checkout.Rules[0])
+	require.Equal(t, "(p99_latency) < 0.1 or (p99_latency) > 2", checkout.Rules[1].Expr)
+	require.Empty(t, checkout.Rules[1].For)
+
+	require.Len(t, skipped, 3)
+	require.Contains(t, skipped[0].Reason, `reduce "mean"`)
+	require.Contains(t, skipped[1].Reason, "not a Prometheus query")
+	require.Equal(t, "rule is paused", skipped[2].Reason)
+}
+
+func TestFromCandidatesAndMarshal(t *testing.T) {
+	group := FromCandidates("checkout", []promql.AlertRuleCandidate{
+		{Name: "HttpRequestsAbsent", Expr: "absent(http_requests_total)", For: "5m", Severity: "critical", Description: "gone", Labels: map[string]string{"team": "payments"}},
+		{Name: "HttpRequestsCounterReset", Expr: "sum (resets(http_requests_total[15m])) > 0", For: "0m", Severity: "info"},
+	})
+
+	data, err := Marshal(File{Groups: []Group{group}})
+	require.NoError(t, err)
+
+	var decoded File
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	require.Equal(t, "checkout", decoded.Groups[0].Name)
+	require.Equal(t, map[string]string{"severity": "critical", "team": "payments"}, decoded.Groups[0].Rules[0].Labels)
+	require.Equal(t, "gone", decoded.Groups[0].Rules[0].Annotations["description"])
+	require.Empty(t, decoded.Groups[0].Rules[1].For)
+	require.Contains(t, string(data), "groups:\n  - name: checkout\n    rules:\n      - alert: HttpRequestsAbsent\n")
+}
@@ -0,0 +1,725 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Grafana's dashboard grid: 24 columns of 30px cells with an 8px margin
+const (
+	gridColumns      = 24
+	gridCellHeight   = 30
+	gridCellMargin   = 8
+	legacyRowHeight  = 250
+	legacyRowsSchema = 16
+)
+
+// Migrate rewrites the parts of a legacy dashboard its target Grafana can no
+// longer render as is: the rows layout becomes a flat panel list, graph
+// panels become timeseries, singlestat becomes stat (or gauge) and the old
+// table becomes the current table, with their options mapped. Conversions the target does not support yet are
+// skipped; an unknown target (supported 0) is treated as supporting the
+// schema of generated dashboards. The schema version is only raised for the
+// rows layout, so Grafana still runs its own migrations on load.
+func Migrate(dashboard map[string]any, grafanaVersion string, supported int) *Report {
+	report := &Report{
+		GrafanaVersion:   grafanaVersion,
+		SupportedVersion: supported,
+		DashboardVersion: Version(dashboard),
+	}
+	target := supported
+	if target == 0 {
+		target = GeneratedVersion
+	}
+
+	if _, hasRows := dashboard["rows"]; hasRows {
+		if _, hasPanels := dashboard["panels"]; !hasPanels {
+			migrateRows(dashboard)
+			report.Migrations = append(report.Migrations, "rows -> panels")
+		}
+	}
+
+	walkPanels(dashboard, func(path string, panel map[string]any) {
+		switch {
+		case panel["type"] == "graph" && target >= timeseriesVersion:
+			report.Warnings = append(report.Warnings, prefix(path, migrateGraph(panel))...)
+			report.Migrations = append(report.Migrations, path+": graph -> timeseries")
+		case isSinglestat(panel) && target >= statVersion:
+			from := panel["type"].(string)
+			report.Warnings = append(report.Warnings, prefix(path, migrateSinglestat(panel))...)
+			report.Migrations = append(report.Migrations, path+": "+from+" -> "+panel["type"].(string))
+		case isLegacyTable(panel) && target >= tableVersion:
+			from := panel["type"].(string)
+			report.Warnings = append(report.Warnings, prefix(path, migrateTable(panel))...)
+			report.Migrations = append(report.Migrations, path+": "+from+" -> table")
+		}
+	})
+
+	report.Warnings = append(report.Warnings, Check(dashboard, grafanaVersion, supported).Warnings...)
+	return report
+}
+
+func prefix(path string, warnings []string) []string {
+	for i, warning := range warnings {
+		warnings[i] = path + ": " + warning
+	}
+	return warnings
+}
+
+// migrateRows converts the rows layout of dashboards older than schema 16
+// into panels placed on the grid, each row becoming a row panel
+func migrateRows(dashboard map[string]any) {
+	rows, _ := dashboard["rows"].([]any)
+	panels := []any{}
+	y := 0
+	for _, rawRow := range rows {
+		row, ok := rawRow.(map[string]any)
+		if !ok {
+			continue
+		}
+		height := gridHeight(row["height"])
+		collapsed, _ := row["collapse"].(bool)
+		showTitle, _ := row["showTitle"].(bool)
+
+		var rowPanel map[string]any
+		if len(rows) > 1 || showTitle || collapsed {
+			title, _ := row["title"].(string)
+			rowPanel = map[string]any{
+				"type":      "row",
+				"title":     title,
+				"collapsed": collapsed,
+				"gridPos":   map[string]any{"x": 0, "y": y, "w": gridColumns, "h": 1},
+				"panels":    []any{},
+			}
+			if repeat, ok := row["repeat"].(string); ok && repeat != "" {
+				rowPanel["repeat"] = repeat
+			}
+			panels = append(panels, rowPanel)
+			y++
+		}
+
+		rowPanels, _ := row["panels"].([]any)
+		var placed []any
+		x, rowHeight := 0, 0
+		for _, rawPanel := range rowPanels {
+			panel, ok := rawPanel.(map[string]any)
+			if !ok {
+				continue
+			}
+			w := gridColumns / 2
+			if span, ok := panel["span"].(float64); ok && span > 0 {
+				w = min(int(span*2), gridColumns)
+			}
+			h := height
+			if panel["height"] != nil {
+				h = gridHeight(panel["height"])
+			}
+			if x+w > gridColumns {
+				x, y = 0, y+rowHeight
+				rowHeight = 0
+			}
+			panel["gridPos"] = map[string]any{"x": x, "y": y, "w": w, "h": h}
+			delete(panel, "span")
+			delete(panel, "height")
+			placed = append(placed, panel)
+			x += w
+			rowHeight = max(rowHeight, h)
+		}
+		y += rowHeight
+
+		if collapsed && rowPanel != nil {
+			rowPanel["panels"] = append([]any{}, placed...)
+		} else {
+			panels = append(panels, placed...)
+		}
+	}
+
+	dashboard["panels"] = panels
+	delete(dashboard, "rows")
+	if Version(dashboard) < legacyRowsSchema {
+		dashboard["schemaVersion"] = legacyRowsSchema
+	}
+}
+
+// gridHeight converts a legacy height in pixels ("250px" or 250) to grid rows
+func gridHeight(raw any) int {
+	pixels := float64(legacyRowHeight)
+	switch v := raw.(type) {
+	case float64:
+		pixels = v
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "px"), 64); err == nil {
+			pixels = parsed
+		}
+	}
+	return max(int(math.Ceil(pixels/(gridCellHeight+gridCellMargin))), 1)
+}
+
+// legacyGraphFields are the graph options that have no place in timeseries
+// panels once mapped
+var legacyGraphFields = []string{
+	"aliasColors", "bars", "dashLength", "dashes", "fill", "fillGradient",
+	"hiddenSeries", "legend", "lines", "linewidth", "nullPointMode",
+	"percentage", "pluginVersion", "pointradius", "points", "renderer",
+	"seriesOverrides", "spaceLength", "stack", "steppedLine", "thresholds",
+	"timeRegions", "tooltip", "xaxis", "yaxes", "yaxis",
+}
+
+// graphLegendCalcs maps the graph legend values to timeseries reducers
+var graphLegendCalcs = []struct{ option, calc string }{
+	{"min", "min"},
+	{"max", "max"},
+	{"avg", "mean"},
+	{"current", "lastNotNull"},
+	{"total", "sum"},
+}
+
+// migrateGraph turns a graph panel into a timeseries panel, mapping its
+// draw style, axis, legend, tooltip and series colors. It returns what
+// could not be mapped.
+func migrateGraph(panel map[string]any) []string {
+	var warnings []string
+
+	custom := map[string]any{
+		"drawStyle":         "line",
+		"lineWidth":         numberOr(panel["linewidth"], 1),
+		"fillOpacity":       numberOr(panel["fill"], 1) * 10,
+		"showPoints":        "never",
+		"lineInterpolation": "linear",
+		"spanNulls":         panel["nullPointMode"] == "connected",
+	}
+	switch {
+	case panel["bars"] == true:
+		custom["drawStyle"] = "bars"
+	case panel["lines"] == false && panel["points"] == true:
+		custom["drawStyle"] = "points"
+	}
+	if panel["points"] == true {
+		custom["showPoints"] = "always"
+		custom["pointSize"] = numberOr(panel["pointradius"], 2) * 2
+	}
+	if panel["steppedLine"] == true {
+		custom["lineInterpolation"] = "stepAfter"
+	}
+	if panel["stack"] == true {
+		mode := "normal"
+		if panel["percentage"] == true {
+			mode = "percent"
+		}
+		custom["stacking"] = map[string]any{"mode": mode, "group": "A"}
+	}
+	if panel["dashes"] == true {
+		custom["lineStyle"] = map[string]any{
+			"fill": "dash",
+			"dash": []any{numberOr(panel["dashLength"], 10), numberOr(panel["spaceLength"], 10)},
+		}
+	}
+
+	fieldConfig := ensureFieldConfig(panel)
+	defaults := fieldConfig["defaults"].(map[string]any)
+	defaults["custom"] = mergeMaps(custom, defaults["custom"])
+	if yaxes, _ := panel["yaxes"].([]any); len(yaxes) > 0 {
+		if axis, ok := yaxes[0].(map[string]any); ok {
+			setIfAbsent(defaults, "unit", axis["format"])
+			setIfAbsent(defaults, "decimals", axis["decimals"])
+			for _, key := range []string{"min", "max"} {
+				if value, ok := parseNumber(axis[key]); ok {
+					setIfAbsent(defaults, key, value)
+				}
+			}
+		}
+	}
+
+	overrides, _ := fieldConfig["overrides"].([]any)
+	if colors, ok := panel["aliasColors"].(map[string]any); ok {
+		for _, alias := range sortedKeys(colors) {
+			overrides = append(overrides, map[string]any{
+				"matcher": map[string]any{"id": "byName", "options": alias},
+				"properties": []any{map[string]any{
+					"id":    "color",
+					"value": map[string]any{"mode": "fixed", "fixedColor": colors[alias]},
+				}},
+			})
+		}
+	}
+	fieldConfig["overrides"] = overrides
+	if seriesOverrides, _ := panel["seriesOverrides"].([]any); len(seriesOverrides) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d series overrides were not converted", len(seriesOverrides)))
+	}
+	if thresholds, _ := panel["thresholds"].([]any); len(thresholds) > 0 {
+		warnings = append(warnings, "graph thresholds were not converted")
+	}
+
+	legend, _ := panel["legend"].(map[string]any)
+	legendOptions := map[string]any{
+		"showLegend":  legend["show"] != false,
+		"displayMode": "list",
+		"placement":   "bottom",
+		"calcs":       []any{},
+	}
+	if legend["alignAsTable"] == true {
+		legendOptions["displayMode"] = "table"
+	}
+	if legend["rightSide"] == true {
+		legendOptions["placement"] = "right"
+	}
+	for _, c := range graphLegendCalcs {
+		if legend[c.option] == true {
+			legendOptions["calcs"] = append(legendOptions["calcs"].([]any), c.calc)
+		}
+	}
+
+	tooltip, _ := panel["tooltip"].(map[string]any)
+	tooltipOptions := map[string]any{"mode": "single", "sort": "none"}
+	if tooltip["shared"] == true {
+		tooltipOptions["mode"] = "multi"
+	}
+	switch numberOr(tooltip["sort"], 0) {
+	case 1:
+		tooltipOptions["sort"] = "asc"
+	case 2:
+		tooltipOptions["sort"] = "desc"
+	}
+	panel["options"] = map[string]any{"legend": legendOptions, "tooltip": tooltipOptions}
+
+	for _, field := range legacyGraphFields {
+		delete(panel, field)
+	}
+	panel["type"] = "timeseries"
+	return warnings
+}
+
+// isSinglestat reports whether a panel is the angular singlestat, built in
+// or the plugin
+func isSinglestat(panel map[string]any) bool {
+	return panel["type"] == "singlestat" || panel["type"] == "grafana-singlestat-panel"
+}
+
+// legacySinglestatFields are the singlestat options replaced by field config
+// and options
+var legacySinglestatFields = []string{
+	"colorBackground", "colorPostfix", "colorPrefix", "colorValue", "colors",
+	"decimals", "format", "gauge", "mappingType", "mappingTypes", "maxValue",
+	"minValue", "nullPointMode", "nullText", "pluginVersion", "postfix",
+	"postfixFontSize", "prefix", "prefixFontSize", "rangeMaps", "sparkline",
+	"tableColumn", "thresholds", "valueFontSize", "valueMaps", "valueName",
+}
+
+// singlestatCalcs maps the singlestat value names to reducers
+var singlestatCalcs = map[string]string{
+	"avg":       "mean",
+	"current":   "lastNotNull",
+	"delta":     "delta",
+	"diff":      "diff",
+	"first":     "firstNotNull",
+	"last_time": "lastNotNull",
+	"max":       "max",
+	"min":       "min",
+	"range":     "range",
+	"total":     "sum",
+}
+
+// migrateSinglestat turns a singlestat panel into a stat panel, or a gauge
+// panel when it showed a gauge, mapping its unit, reducer, thresholds,
+// coloring, sparkline and value mappings. It returns what could not be
+// mapped.
+func migrateSinglestat(panel map[string]any) []string {
+	var warnings []string
+
+	fieldConfig := ensureFieldConfig(panel)
+	defaults := fieldConfig["defaults"].(map[string]any)
+	setIfAbsent(defaults, "unit", panel["format"])
+	if decimals, ok := parseNumber(panel["decimals"]); ok {
+		setIfAbsent(defaults, "decimals", decimals)
+	}
+	if nullText, _ := panel["nullText"].(string); nullText != "" {
+		setIfAbsent(defaults, "noValue", nullText)
+	}
+	if steps := thresholdSteps(panel); steps != nil {
+		setIfAbsent(defaults, "thresholds", map[string]any{"mode": "absolute", "steps": steps})
+	}
+	if mappings := singlestatMappings(panel); len(mappings) > 0 {
+		setIfAbsent(defaults, "mappings", mappings)
+	}
+
+	calc := "mean"
+	if valueName, _ := panel["valueName"].(string); valueName != "" {
+		if reducer, ok := singlestatCalcs[valueName]; ok {
+			calc = reducer
+		} else {
+			warnings = append(warnings, fmt.Sprintf("value %q was not converted, showing the mean", valueName))
+		}
+	}
+	if column, _ := panel["tableColumn"].(string); column != "" {
+		warnings = append(warnings, fmt.Sprintf("table column %q was not converted", column))
+	}
+	reduceOptions := map[string]any{"calcs": []any{calc}, "fields": "", "values": false}
+
+	var options map[string]any
+	if gauge, _ := panel["gauge"].(map[string]any); gauge["show"] == true {
+		for key, value := range map[string]any{"min": panel["minValue"], "max": panel["maxValue"]} {
+			if n, ok := parseNumber(value); ok {
+				setIfAbsent(defaults, key, n)
+			}
+		}
+		options = map[string]any{
+			"reduceOptions":        reduceOptions,
+			"showThresholdMarkers": gauge["thresholdMarkers"] != false,
+			"showThresholdLabels":  gauge["thresholdLabels"] == true,
+		}
+		panel["type"] = "gauge"
+	} else {
+		colorMode := "none"
+		switch {
+		case panel["colorBackground"] == true:
+			colorMode = "background"
+		case panel["colorValue"] == true:
+			colorMode = "value"
+		}
+		graphMode := "none"
+		if sparkline, _ := panel["sparkline"].(map[string]any); sparkline["show"] == true {
+			graphMode = "area"
+		}
+		options = map[string]any{
+			"reduceOptions": reduceOptions,
+			"colorMode":     colorMode,
+			"graphMode":     graphMode,
+			"justifyMode":   "auto",
+			"textMode":      "auto",
+		}
+		panel["type"] = "stat"
+	}
+	panel["options"] = options
+
+	prefixText, _ := panel["prefix"].(string)
+	postfixText, _ := panel["postfix"].(string)
+	if prefixText != "" || postfixText != "" {
+		warnings = append(warnings, "prefix and postfix were not converted; set a custom unit instead")
+	}
+
+	for _, field := range legacySinglestatFields {
+		delete(panel, field)
+	}
+	return warnings
+}
+
+// singlestatMappings converts the value and range maps of a singlestat panel
+// into value mappings
+func singlestatMappings(panel map[string]any) []any {
+	var mappings []any
+	if numberOr(panel["mappingType"], 1) == 1 {
+		valueMaps, _ := panel["valueMaps"].([]any)
+		options := map[string]any{}
+		for i, raw := range valueMaps {
+			valueMap, _ := raw.(map[string]any)
+			value, _ := valueMap["value"].(string)
+			text, _ := valueMap["text"].(string)
+			if value == "null" {
+				mappings = append(mappings, map[string]any{
+					"type":    "special",
+					"options": map[string]any{"match": "null", "result": map[string]any{"text": text, "index": i}},
+				})
+			} else if value != "" {
+				options[value] = map[string]any{"text": text, "index": i}
+			}
+		}
+		if len(options) > 0 {
+			mappings = append(mappings, map[string]any{"type": "value", "options": options})
+		}
+		return mappings
+	}
+
+	rangeMaps, _ := panel["rangeMaps"].([]any)
+	for i, raw := range rangeMaps {
+		rangeMap, _ := raw.(map[string]any)
+		from, fromOK := parseNumber(rangeMap["from"])
+		to, toOK := parseNumber(rangeMap["to"])
+		if !fromOK && !toOK {
+			continue
+		}
+		text, _ := rangeMap["text"].(string)
+		options := map[string]any{"result": map[string]any{"text": text, "index": i}}
+		if fromOK {
+			options["from"] = from
+		}
+		if toOK {
+			options["to"] = to
+		}
+		mappings = append(mappings, map[string]any{"type": "range", "options": options})
+	}
+	return mappings
+}
+
+// isLegacyTable reports whether a panel is the angular table: table-old, or
+// a "table" saved before Grafana 7 with column styles
+func isLegacyTable(panel map[string]any) bool {
+	if panel["type"] == "table-old" {
+		return true
+	}
+	_, hasStyles := panel["styles"]
+	return panel["type"] == "table" && hasStyles
+}
+
+// legacyTableFields are the old table options replaced by field config,
+// options and transformations
+var legacyTableFields = []string{
+	"columns", "fontSize", "pageSize", "pluginVersion", "scroll",
+	"showHeader", "sort", "styles", "transform",
+}
+
+// tableTransforms maps the old table transforms to transformations
+var tableTransforms = map[string]string{
+	"timeseries_to_rows":    "seriesToRows",
+	"timeseries_to_columns": "seriesToColumns",
+}
+
+// tableAggregations maps the old table aggregation columns to reducers
+var tableAggregations = map[string]string{
+	"avg":     "mean",
+	"min":     "min",
+	"max":     "max",
+	"total":   "sum",
+	"current": "lastNotNull",
+	"count":   "count",
+}
+
+// migrateTable turns the old table into the current table panel: column
+// styles become field config defaults (pattern /.*/) or overrides, and the
+// transform becomes a transformation. It returns what could not be mapped.
+func migrateTable(panel map[string]any) []string {
+	var warnings []string
+
+	fieldConfig := ensureFieldConfig(panel)
+	defaults := fieldConfig["defaults"].(map[string]any)
+	overrides, _ := fieldConfig["overrides"].([]any)
+	styles, _ := panel["styles"].([]any)
+	for _, rawStyle := range styles {
+		style, ok := rawStyle.(map[string]any)
+		if !ok {
+			continue
+		}
+		properties, styleWarnings := styleProperties(style)
+		warnings = append(warnings, styleWarnings...)
+		if len(properties) == 0 {
+			continue
+		}
+
+		pattern, _ := style["pattern"].(string)
+		if pattern == "/.*/" {
+			for _, property := range properties {
+				setPath(defaults, property["id"].(string), property["value"])
+			}
+			continue
+		}
+		matcher := map[string]any{"id": "byName", "options": pattern}
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			matcher = map[string]any{"id": "byRegexp", "options": pattern}
+		}
+		list := make([]any, len(properties))
+		for i, property := range properties {
+			list[i] = property
+		}
+		overrides = append(overrides, map[string]any{"matcher": matcher, "properties": list})
+	}
+	fieldConfig["overrides"] = overrides
+
+	transformations, _ := panel["transformations"].([]any)
+	switch transform, _ := panel["transform"].(string); transform {
+	case "", "table":
+	case "timeseries_aggregations":
+		var reducers []any
+		columns, _ := panel["columns"].([]any)
+		for _, rawColumn := range columns {
+			column, _ := rawColumn.(map[string]any)
+			value, _ := column["value"].(string)
+			if reducer, ok := tableAggregations[value]; ok {
+				reducers = append(reducers, reducer)
+			}
+		}
+		transformations = append(transformations, map[string]any{
+			"id":      "reduce",
+			"options": map[string]any{"reducers": reducers},
+		})
+	default:
+		if id, ok := tableTransforms[transform]; ok {
+			transformations = append(transformations, map[string]any{"id": id, "options": map[string]any{}})
+		} else {
+			warnings = append(warnings, fmt.Sprintf("table transform %q was not converted", transform))
+		}
+	}
+	if len(transformations) > 0 {
+		panel["transformations"] = transformations
+	}
+
+	panel["options"] = map[string]any{"showHeader": panel["showHeader"] != false}
+	for _, field := range legacyTableFields {
+		delete(panel, field)
+	}
+	panel["type"] = "table"
+	return warnings
+}
+
+// styleProperties maps an old table column style to field config
+// properties, addressed by their path in the field config ("custom.hidden")
+func styleProperties(style map[string]any) ([]map[string]any, []string) {
+	var properties []map[string]any
+	var warnings []string
+	add := func(id string, value any) {
+		properties = append(properties, map[string]any{"id": id, "value": value})
+	}
+
+	if alias, _ := style["alias"].(string); alias != "" {
+		add("displayName", alias)
+	}
+	switch style["type"] {
+	case "hidden":
+		add("custom.hidden", true)
+	case "date":
+		format, _ := style["dateFormat"].(string)
+		if format == "" {
+			format = "YYYY-MM-DD HH:mm:ss"
+		}
+		add("unit", "time: "+format)
+	case "number":
+		if unit, _ := style["unit"].(string); unit != "" && unit != "short" {
+			add("unit", unit)
+		}
+		if decimals, ok := parseNumber(style["decimals"]); ok {
+			add("decimals", decimals)
+		}
+	}
+
+	if steps := thresholdSteps(style); steps != nil {
+		add("thresholds", map[string]any{"mode": "absolute", "steps": steps})
+		switch style["colorMode"] {
+		case "cell":
+			add("custom.cellOptions", map[string]any{"type": "color-background"})
+		case "value":
+			add("custom.cellOptions", map[string]any{"type": "color-text"})
+		case "row":
+			add("custom.cellOptions", map[string]any{"type": "color-background"})
+			warnings = append(warnings, "row coloring became cell coloring")
+		}
+	}
+	if style["link"] == true {
+		url, _ := style["linkUrl"].(string)
+		title, _ := style["linkTooltip"].(string)
+		add("links", []any{map[string]any{"title": title, "url": url}})
+	}
+	return properties, warnings
+}
+
+// thresholdSteps converts the thresholds ("50,80") and colors of an old
+// table style into threshold steps, nil when it has none
+func thresholdSteps(style map[string]any) []any {
+	var values []float64
+	switch raw := style["thresholds"].(type) {
+	case []any:
+		for _, v := range raw {
+			if n, ok := parseNumber(v); ok {
+				values = append(values, n)
+			}
+		}
+	case string:
+		for _, v := range strings.Split(raw, ",") {
+			if n, ok := parseNumber(v); ok {
+				values = append(values, n)
+			}
+		}
+	}
+	colors, _ := style["colors"].([]any)
+	if len(values) == 0 || len(colors) < len(values)+1 {
+		return nil
+	}
+
+	steps := []any{map[string]any{"color": colors[0], "value": nil}}
+	for i, value := range values {
+		steps = append(steps, map[string]any{"color": colors[i+1], "value": value})
+	}
+	return steps
+}
+
+// ensureFieldConfig returns the panel's field config, creating the defaults
+// and overrides it lacks
+func ensureFieldConfig(panel map[string]any) map[string]any {
+	fieldConfig, ok := panel["fieldConfig"].(map[string]any)
+	if !ok {
+		fieldConfig = map[string]any{}
+		panel["fieldConfig"] = fieldConfig
+	}
+	if _, ok := fieldConfig["defaults"].(map[string]any); !ok {
+		fieldConfig["defaults"] = map[string]any{}
+	}
+	if _, ok := fieldConfig["overrides"].([]any); !ok {
+		fieldConfig["overrides"] = []any{}
+	}
+	return fieldConfig
+}
+
+// setPath sets a dotted path such as "custom.hidden" in a map
+func setPath(m map[string]any, path string, value any) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// mergeMaps returns base with the entries of overlay, when it is a map, on top
+func mergeMaps(base map[string]any, overlay any) map[string]any {
+	if extra, ok := overlay.(map[string]any); ok {
+		for key, value := range extra {
+			base[key] = value
+		}
+	}
+	return base
+}
+
+func setIfAbsent(m map[string]any, key string, value any) {
+	if value == nil || value == "" {
+		return
+	}
+	if _, ok := m[key]; !ok {
+		m[key] = value
+	}
+}
+
+// numberOr returns a JSON number, or fallback when the value is not one
+func numberOr(value any, fallback float64) float64 {
+	if n, ok := parseNumber(value); ok {
+		return n
+	}
+	return fallback
+}
+
+// parseNumber reads a number that legacy dashboards store as a number or a
+// string ("0", "1.5")
+func parseNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, data string) map[string]any {
+	t.Helper()
+	var dashboard map[string]any
+	if err := json.Unmarshal([]byte(data), &dashboard); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+	return dashboard
+}
+
+func panelAt(dashboard map[string]any, i int) map[string]any {
+	return dashboard["panels"].([]any)[i].(map[string]any)
+}
+
+func TestMigrateRows(t *testing.T) {
+	dashboard := decode(t, `{
+		"schemaVersion": 14,
+		"rows": [
+			{"title": "Traffic", "showTitle": true, "height": "250px", "panels": [
+				{"id": 1, "type": "singlestat", "span": 4},
+				{"id": 2, "type": "table", "span": 8}
+			]},
+			{"title": "Details", "collapse": true, "height": 300, "panels": [
+				{"id": 3, "type": "text", "span": 12}
+			]}
+		]
+	}`)
+
+	report := Migrate(dashboard, "11.2.0", 39)
+
+	if _, ok := dashboard["rows"]; ok || Version(dashboard) != 16 {
+		t.Fatalf("expected the rows replaced and schema version 16, got %v", dashboard)
+	}
+	panels := dashboard["panels"].([]any)
+	if len(panels) != 4 {
+		t.Fatalf("expected 2 rows and 2 panels at the top level, got %d", len(panels))
+	}
+	if got := panelAt(dashboard, 2)["gridPos"]; !reflect.DeepEqual(got, map[string]any{"x": 8, "y": 1, "w": 16, "h": 7}) {
+		t.Errorf("unexpected grid position %v", got)
+	}
+	collapsed := panelAt(dashboard, 3)
+	if collapsed["type"] != "row" || collapsed["collapsed"] != true || len(collapsed["panels"].([]any)) != 1 {
+		t.Errorf("expected the collapsed row to hold its panel, got %v", collapsed)
+	}
+	if report.Migrations[0] != "rows -> panels" || report.DashboardVersion != 14 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestMigrateGraph(t *testing.T) {
+	dashboard := decode(t, `{
+		"schemaVersion": 27,
+		"panels": [{
+			"id": 1, "type": "graph", "title": "Requests",
+			"lines": true, "linewidth": 2, "fill": 3, "stack": true, "percentage": true,
+			"nullPointMode": "connected",
+			"yaxes": [{"format": "reqps", "min": "0", "max": null}, {"format": "short"}],
+			"legend": {"show": true, "alignAsTable": true, "rightSide": true, "avg": true, "current": true},
+			"tooltip": {"shared": true, "sort": 2},
+			"aliasColors": {"errors": "red"},
+			"seriesOverrides": [{"alias": "errors", "yaxis": 2}],
+			"targets": [{"expr": "rate(http_requests_total[5m])"}]
+		}]
+	}`)
+
+	report := Migrate(dashboard, "10.4.0", 39)
+
+	panel := panelAt(dashboard, 0)
+	if panel["type"] != "timeseries" || panel["yaxes"] != nil || panel["targets"] == nil {
+		t.Fatalf("expected a timeseries panel keeping its targets, got %v", panel)
+	}
+	defaults := panel["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+	custom := defaults["custom"].(map[string]any)
+	if defaults["unit"] != "reqps" || defaults["min"] != 0.0 || custom["lineWidth"] != 2.0 || custom["fillOpacity"] != 30.0 || custom["spanNulls"] != true {
+		t.Errorf("unexpected field config %v", defaults)
+	}
+	if stacking := custom["stacking"].(map[string]any); stacking["mode"] != "percent" {
+		t.Errorf("expected percent stacking, got %v", stacking)
+	}
+	legend := panel["options"].(map[string]any)["legend"].(map[string]any)
+	if legend["displayMode"] != "table" || legend["placement"] != "right" || !reflect.DeepEqual(legend["calcs"], []any{"mean", "lastNotNull"}) {
+		t.Errorf("unexpected legend %v", legend)
+	}
+	if tooltip := panel["options"].(map[string]any)["tooltip"].(map[string]any); tooltip["mode"] != "multi" || tooltip["sort"] != "desc" {
+		t.Errorf("unexpected tooltip %v", tooltip)
+	}
+	overrides := panel["fieldConfig"].(map[string]any)["overrides"].([]any)
+	if len(overrides) != 1 {
+		t.Errorf("expected the alias color as an override, got %v", overrides)
+	}
+	if !reflect.DeepEqual(report.Migrations, []string{"panels[0]: graph -> timeseries"}) {
+		t.Errorf("unexpected migrations %v", report.Migrations)
+	}
+	if !reflect.DeepEqual(report.Warnings, []string{"panels[0]: 1 series overrides were not converted"}) {
+		t.Errorf("unexpected warnings %v", report.Warnings)
+	}
+}
+
+func TestMigrateTable(t *testing.T) {
+	dashboard := decode(t, `{
+		"schemaVersion": 22,
+		"panels": [{
+			"id": 1, "type": "table", "transform": "timeseries_aggregations",
+			"columns": [{"text": "Avg", "value": "avg"}, {"text": "Current", "value": "current"}],
+			"styles": [
+				{"pattern": "Time", "type": "date", "dateFormat": "YYYY-MM-DD"},
+				{"pattern": "/.*/", "type": "number", "unit": "percent", "decimals": 1,
+				 "thresholds": ["80", "90"], "colors": ["green", "orange", "red"], "colorMode": "cell"},
+				{"pattern": "/__name__|job/", "type": "hidden"}
+			]
+		}]
+	}`)
+
+	report := Migrate(dashboard, "11.2.0", 39)
+
+	panel := panelAt(dashboard, 0)
+	if panel["type"] != "table" || panel["styles"] != nil {
+		t.Fatalf("expected the current table panel, got %v", panel)
+	}
+	fieldConfig := panel["fieldConfig"].(map[string]any)
+	defaults := fieldConfig["defaults"].(map[string]any)
+	if defaults["unit"] != "percent" || defaults["decimals"] != 1.0 {
+		t.Errorf("expected the catch-all style in the defaults, got %v", defaults)
+	}
+	steps := defaults["thresholds"].(map[string]any)["steps"].([]any)
+	if len(steps) != 3 || steps[2].(map[string]any)["value"] != 90.0 {
+		t.Errorf("unexpected thresholds %v", steps)
+	}
+	if defaults["custom"].(map[string]any)["cellOptions"].(map[string]any)["type"] != "color-background" {
+		t.Errorf("expected cell coloring, got %v", defaults["custom"])
+	}
+	overrides := fieldConfig["overrides"].([]any)
+	if len(overrides) != 2 || overrides[1].(map[string]any)["matcher"].(map[string]any)["id"] != "byRegexp" {
+		t.Errorf("unexpected overrides %v", overrides)
+	}
+	transformations := panel["transformations"].([]any)
+	reduce := transformations[0].(map[string]any)
+	if reduce["id"] != "reduce" || !reflect.DeepEqual(reduce["options"].(map[string]any)["reducers"], []any{"mean", "lastNotNull"}) {
+		t.Errorf("unexpected transformations %v", transformations)
+	}
+	if len(report.Migrations) != 1 || len(report.Warnings) != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestMigrateSinglestat(t *testing.T) {
+	dashboard := decode(t, `{
+		"schemaVersion": 22,
+		"panels": [
+			{"id": 1, "type": "singlestat", "format": "percentunit", "decimals": 2, "valueName": "current",
+			 "thresholds": "0.9,0.99", "colors": ["red", "orange", "green"], "colorBackground": true,
+			 "sparkline": {"show": true}, "postfix": " up",
+			 "mappingType": 1, "valueMaps": [{"op": "=", "value": "null", "text": "N/A"}, {"op": "=", "value": "1", "text": "UP"}]},
+			{"id": 2, "type": "grafana-singlestat-panel", "format": "s", "minValue": 0, "maxValue": "60",
+			 "gauge": {"show": true, "thresholdMarkers": true}, "valueName": "name"}
+		]
+	}`)
+
+	report := Migrate(dashboard, "11.2.0", 39)
+
+	stat := panelAt(dashboard, 0)
+	if stat["type"] != "stat" || stat["format"] != nil || stat["valueMaps"] != nil {
+		t.Fatalf("expected a stat panel without the singlestat options, got %v", stat)
+	}
+	defaults := stat["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+	if defaults["unit"] != "percentunit" || defaults["decimals"] != 2.0 {
+		t.Errorf("unexpected defaults %v", defaults)
+	}
+	if steps := defaults["thresholds"].(map[string]any)["steps"].([]any); len(steps) != 3 || steps[1].(map[string]any)["value"] != 0.9 {
+		t.Errorf("unexpected thresholds %v", steps)
+	}
+	mappings := defaults["mappings"].([]any)
+	if len(mappings) != 2 || mappings[0].(map[string]any)["type"] != "special" || mappings[1].(map[string]any)["options"].(map[string]any)["1"] == nil {
+		t.Errorf("unexpected mappings %v", mappings)
+	}
+	options := stat["options"].(map[string]any)
+	if options["colorMode"] != "background" || options["graphMode"] != "area" ||
+		!reflect.DeepEqual(options["reduceOptions"].(map[string]any)["calcs"], []any{"lastNotNull"}) {
+		t.Errorf("unexpected options %v", options)
+	}
+
+	gauge := panelAt(dashboard, 1)
+	gaugeDefaults := gauge["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+	if gauge["type"] != "gauge" || gaugeDefaults["min"] != 0.0 || gaugeDefaults["max"] != 60.0 {
+		t.Errorf("expected a gauge panel from 0 to 60, got %v", gauge)
+	}
+
+	want := []string{"panels[0]: singlestat -> stat", "panels[1]: grafana-singlestat-panel -> gauge"}
+	if !reflect.DeepEqual(report.Migrations, want) {
+		t.Errorf("unexpected migrations %v", report.Migrations)
+	}
+	if len(report.Warnings) != 2 {
+		t.Errorf("expected the postfix and the name value reported, got %v", report.Warnings)
+	}
+}
+
+func TestMigrateSkipsUnsupportedTargets(t *testing.T) {
+	dashboard := decode(t, `{"schemaVersion": 22, "panels": [{"type": "graph"}]}`)
+
+	report := Migrate(dashboard, "7.5.0", 27)
+
+	if panelAt(dashboard, 0)["type"] != "graph" || len(report.Migrations) != 0 {
+		t.Errorf("expected graph panels kept for a Grafana without timeseries, got %+v", report)
+	}
+}
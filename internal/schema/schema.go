@@ -0,0 +1,169 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeneratedVersion is the schema version of the dashboards the agent
+// generates; Grafana migrates them to its own version when they are loaded
+const GeneratedVersion = 36
+
+// grafanaSchemaVersions lists the highest dashboard schema version of
+// Grafana releases, oldest first. Releases in between write the version of
+// the closest older entry.
+var grafanaSchemaVersions = []struct {
+	major, minor int
+	schema       int
+}{
+	{7, 0, 25},
+	{7, 4, 27},
+	{8, 0, 30},
+	{8, 3, 33},
+	{8, 5, 35},
+	{9, 0, 36},
+	{9, 1, 37},
+	{10, 0, 38},
+	{10, 4, 39},
+	{11, 3, 40},
+	{11, 6, 41},
+}
+
+// Schema versions from which panel types are available
+const (
+	// timeseriesVersion is Grafana 8, where the timeseries panel replaced graph
+	timeseriesVersion = 30
+	// tableVersion is Grafana 7.0, where the React table replaced table-old
+	tableVersion = 25
+	// statVersion is Grafana 7.0, where stat and gauge replaced singlestat
+	statVersion = 25
+	// angularRemovedVersion is Grafana 11, which no longer renders angular panels
+	angularRemovedVersion = 39
+)
+
+// ForGrafana returns the highest dashboard schema version a Grafana release
+// supports, e.g. 39 for "11.2.0"; 0 when the version is not recognized
+func ForGrafana(version string) int {
+	major, minor, ok := parseVersion(version)
+	if !ok {
+		return 0
+	}
+
+	i := sort.Search(len(grafanaSchemaVersions), func(i int) bool {
+		v := grafanaSchemaVersions[i]
+		return v.major > major || (v.major == major && v.minor > minor)
+	})
+	if i == 0 {
+		return 0
+	}
+	return grafanaSchemaVersions[i-1].schema
+}
+
+// parseVersion reads the major and minor version of a Grafana version such
+// as "11.2.0", "v10.4.1" or "12.0.0-pre"
+func parseVersion(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' }))
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// Report describes how a dashboard's schema compares to its target Grafana
+// and the migrations applied to it
+type Report struct {
+	// GrafanaVersion is the version of the target Grafana
+	GrafanaVersion string `json:"grafana_version"`
+	// SupportedVersion is the highest schema version the target supports
+	SupportedVersion int `json:"supported_version"`
+	// DashboardVersion is the dashboard's schema version before migrating
+	DashboardVersion int `json:"dashboard_version"`
+	// Migrations lists the migrations applied, e.g. "panels[2]: graph -> timeseries"
+	Migrations []string `json:"migrations,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// Check compares a dashboard with the schema version its target supports,
+// warning about what the target cannot render as designed
+func Check(dashboard map[string]any, grafanaVersion string, supported int) *Report {
+	report := &Report{
+		GrafanaVersion:   grafanaVersion,
+		SupportedVersion: supported,
+		DashboardVersion: Version(dashboard),
+	}
+	if supported == 0 {
+		return report
+	}
+
+	if report.DashboardVersion > supported {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"dashboard schema version %d is newer than Grafana %s supports (%d); some panels may not render as designed",
+			report.DashboardVersion, grafanaVersion, supported))
+	}
+	if supported >= angularRemovedVersion {
+		walkPanels(dashboard, func(path string, panel map[string]any) {
+			if panelType, _ := panel["type"].(string); angularPanels[panelType] {
+				report.Warnings = append(report.Warnings, fmt.Sprintf(
+					"%s uses the angular %s panel, which Grafana %s no longer renders", path, panelType, grafanaVersion))
+			}
+		})
+	}
+	return report
+}
+
+// Version returns the schema version of a dashboard, 0 when it has none
+func Version(dashboard map[string]any) int {
+	switch v := dashboard["schemaVersion"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// angularPanels are the panel plugins built on AngularJS, which Grafana 11
+// removed
+var angularPanels = map[string]bool{
+	"graph":                           true,
+	"singlestat":                      true,
+	"grafana-singlestat-panel":        true,
+	"table-old":                       true,
+	"grafana-piechart-panel":          true,
+	"grafana-worldmap-panel":          true,
+	"natel-discrete-panel":            true,
+	"vonage-status-panel":             true,
+	"briangann-datatable-panel":       true,
+	"briangann-gauge-panel":           true,
+	"michaeldmoore-annunciator-panel": true,
+}
+
+// walkPanels calls fn for every panel of a dashboard, including the panels
+// of collapsed rows, with its path such as "panels[3].panels[0]"
+func walkPanels(dashboard map[string]any, fn func(path string, panel map[string]any)) {
+	panels, _ := dashboard["panels"].([]any)
+	for i, raw := range panels {
+		panel, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("panels[%d]", i)
+		fn(path, panel)
+		nested, _ := panel["panels"].([]any)
+		for j, rawNested := range nested {
+			if nestedPanel, ok := rawNested.(map[string]any); ok {
+				fn(fmt.Sprintf("%s.panels[%d]", path, j), nestedPanel)
+			}
+		}
+	}
+}
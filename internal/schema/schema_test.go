@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForGrafana(t *testing.T) {
+	tests := map[string]int{
+		"11.2.0":     39,
+		"v10.4.1":    39,
+		"10.1.5":     38,
+		"9.0.0":      36,
+		"12.0.0-pre": 41,
+		"8.3.3":      33,
+		"6.7.4":      0,
+		"nightly":    0,
+		"":           0,
+	}
+	for version, want := range tests {
+		if got := ForGrafana(version); got != want {
+			t.Errorf("ForGrafana(%q) = %d, want %d", version, got, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dashboard := map[string]any{
+		"schemaVersion": float64(39),
+		"panels": []any{
+			map[string]any{"type": "timeseries"},
+			map[string]any{"type": "row", "panels": []any{map[string]any{"type": "grafana-piechart-panel"}}},
+		},
+	}
+
+	report := Check(dashboard, "9.1.0", 37)
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "newer than Grafana 9.1.0 supports (37)") {
+		t.Errorf("expected a schema version warning, got %v", report.Warnings)
+	}
+
+	report = Check(dashboard, "11.2.0", 39)
+	if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], "panels[1].panels[0] uses the angular grafana-piechart-panel panel") {
+		t.Errorf("expected an angular panel warning, got %v", report.Warnings)
+	}
+
+	if report := Check(dashboard, "", 0); len(report.Warnings) != 0 {
+		t.Errorf("expected no warnings for an unknown target, got %v", report.Warnings)
+	}
+}
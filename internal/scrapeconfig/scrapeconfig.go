@@ -0,0 +1,199 @@
+// Package scrapeconfig renders the scrape configuration for a single
+// service, as a Prometheus scrape_config or a Grafana Alloy component, and
+// infers the job name from what the service exposes
+package scrapeconfig
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// DefaultScrapeInterval is the scrape interval of generated configurations
+const DefaultScrapeInterval = "30s"
+
+// Job name sources, from the most to the least reliable
+const (
+	JobSourceArgument     = "argument"
+	JobSourceTargetInfo   = "target_info"
+	JobSourceExporter     = "exporter"
+	JobSourceHostname     = "hostname"
+	JobSourceMetricPrefix = "metric_prefix"
+	JobSourceDefault      = "default"
+)
+
+// defaultJobName is used when nothing hints at the service name
+const defaultJobName = "service"
+
+// Config is the scrape configuration of a single target
+type Config struct {
+	JobName        string            `json:"job_name"`
+	Target         string            `json:"target"`
+	Scheme         string            `json:"scheme"`
+	MetricsPath    string            `json:"metrics_path"`
+	ScrapeInterval string            `json:"scrape_interval"`
+	Labels         map[string]string `json:"labels,omitempty"`
+}
+
+// FromURL derives the target, scheme and metrics path from a /metrics URL
+func FromURL(metricsURL string) (Config, error) {
+	parsed, err := url.Parse(metricsURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return Config{}, fmt.Errorf("invalid metrics URL %q: expected http(s)://host:port/path", metricsURL)
+	}
+
+	path := parsed.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	return Config{
+		Target:         parsed.Host,
+		Scheme:         parsed.Scheme,
+		MetricsPath:    path,
+		ScrapeInterval: DefaultScrapeInterval,
+	}, nil
+}
+
+// InferJobName names the job after the service: the service name reported by
+// OpenTelemetry's target_info, a well-known exporter, the host name or the
+// namespace most of the service's own metrics share, in that order
+func InferJobName(cfg Config, families []exposition.Family) (string, string) {
+	for _, family := range families {
+		if family.Name != "target" && family.Name != "target_info" {
+			continue
+		}
+		for _, sample := range family.Samples {
+			if name := sample.Labels["service_name"]; name != "" {
+				return name, JobSourceTargetInfo
+			}
+		}
+	}
+
+	for _, exporter := range promql.DetectExporters(promql.ScrapedMetricNames(families), nil) {
+		if exporter.Confidence == promql.ConfidenceHigh {
+			return strings.TrimSuffix(exporter.Exporter, "_exporter"), JobSourceExporter
+		}
+	}
+
+	host, _, err := net.SplitHostPort(cfg.Target)
+	if err != nil {
+		host = cfg.Target
+	}
+	if host != "" && host != "localhost" && net.ParseIP(host) == nil {
+		return strings.SplitN(host, ".", 2)[0], JobSourceHostname
+	}
+
+	if prefix := dominantPrefix(families); prefix != "" {
+		return prefix, JobSourceMetricPrefix
+	}
+	return defaultJobName, JobSourceDefault
+}
+
+// genericPrefixes are metric namespaces shared by many services, which say
+// nothing about the service name
+var genericPrefixes = map[string]bool{"http": true, "grpc": true, "rpc": true, "db": true, "messaging": true}
+
+// dominantPrefix returns the namespace (the part before the first
+// underscore) shared by most of the service's own metrics
+func dominantPrefix(families []exposition.Family) string {
+	counts := map[string]int{}
+	total := 0
+	for _, family := range families {
+		name := family.SeriesName()
+		if promql.IsRuntimeMetric(name) || family.Name == "target" || family.Name == "target_info" {
+			continue
+		}
+		total++
+		if prefix, _, ok := strings.Cut(name, "_"); ok && !genericPrefixes[prefix] {
+			counts[prefix]++
+		}
+	}
+
+	best, bestCount := "", 0
+	for prefix, count := range counts {
+		if count > bestCount || count == bestCount && prefix < best {
+			best, bestCount = prefix, count
+		}
+	}
+	if total == 0 || 2*bestCount <= total {
+		return ""
+	}
+	return best
+}
+
+// prometheusConfig is the scrape_configs section of a Prometheus configuration
+type prometheusConfig struct {
+	ScrapeConfigs []prometheusScrapeConfig `yaml:"scrape_configs"`
+}
+
+type prometheusScrapeConfig struct {
+	JobName        string         `yaml:"job_name"`
+	ScrapeInterval string         `yaml:"scrape_interval,omitempty"`
+	Scheme         string         `yaml:"scheme,omitempty"`
+	MetricsPath    string         `yaml:"metrics_path,omitempty"`
+	StaticConfigs  []staticConfig `yaml:"static_configs"`
+}
+
+type staticConfig struct {
+	Targets []string          `yaml:"targets,flow"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// Prometheus renders the scrape_configs section of prometheus.yml
+func Prometheus(cfg Config) ([]byte, error) {
+	scrapeConfig := prometheusScrapeConfig{
+		JobName:        cfg.JobName,
+		ScrapeInterval: cfg.ScrapeInterval,
+		Scheme:         cfg.Scheme,
+		MetricsPath:    cfg.MetricsPath,
+		StaticConfigs:  []staticConfig{{Targets: []string{cfg.Target}, Labels: cfg.Labels}},
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(prometheusConfig{ScrapeConfigs: []prometheusScrapeConfig{scrapeConfig}}); err != nil {
+		return nil, fmt.Errorf("failed to encode scrape config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode scrape config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// identifierPattern matches the characters not allowed in Alloy component labels
+var identifierPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Alloy renders a prometheus.scrape component forwarding to the
+// prometheus.remote_write.grafana_cloud component
+func Alloy(cfg Config) string {
+	target := []string{fmt.Sprintf("%q = %q", "__address__", cfg.Target)}
+	names := make([]string, 0, len(cfg.Labels))
+	for name := range cfg.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		target = append(target, fmt.Sprintf("%q = %q", name, cfg.Labels[name]))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "prometheus.scrape %q {\n", identifierPattern.ReplaceAllString(cfg.JobName, "_"))
+	fmt.Fprintf(&b, "  targets         = [{%s}]\n", strings.Join(target, ", "))
+	fmt.Fprintf(&b, "  job_name        = %q\n", cfg.JobName)
+	fmt.Fprintf(&b, "  scrape_interval = %q\n", cfg.ScrapeInterval)
+	fmt.Fprintf(&b, "  scheme          = %q\n", cfg.Scheme)
+	fmt.Fprintf(&b, "  metrics_path    = %q\n", cfg.MetricsPath)
+	b.WriteString("  forward_to      = [prometheus.remote_write.grafana_cloud.receiver]\n")
+	b.WriteString("}\n")
+	return b.String()
+}
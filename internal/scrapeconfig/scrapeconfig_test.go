@@ -0,0 +1,80 @@
+package scrapeconfig
+
+import (
+	"testing"
+
+	require "github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+)
+
+func families(names ...string) []exposition.Family {
+	result := make([]exposition.Family, 0, len(names))
+	for _, name := range names {
+		result = append(result, exposition.Family{Name: name, Type: "gauge"})
+	}
+	return result
+}
+
+func TestFromURL(t *testing.T) {
+	cfg, err := FromURL("https://checkout.payments.svc:8443")
+	require.NoError(t, err)
+	require.Equal(t, Config{Target: "checkout.payments.svc:8443", Scheme: "https", MetricsPath: "/metrics", ScrapeInterval: DefaultScrapeInterval}, cfg)
+
+	_, err = FromURL("checkout:8080/metrics")
+	require.Error(t, err)
+}
+
+func TestInferJobName(t *testing.T) {
+	local := Config{Target: "127.0.0.1:9100"}
+
+	targetInfo := append(families("checkout_orders_total"), exposition.Family{
+		Name: "target", Type: "info",
+		Samples: []exposition.Sample{{Name: "target_info", Labels: map[string]string{"service_name": "cart"}}},
+	})
+	tests := []struct {
+		name       string
+		cfg        Config
+		families   []exposition.Family
+		wantJob    string
+		wantSource string
+	}{
+		{"target_info", Config{Target: "checkout:8080"}, targetInfo, "cart", JobSourceTargetInfo},
+		{"exporter", local, families("node_cpu_seconds_total", "node_memory_MemAvailable_bytes"), "node", JobSourceExporter},
+		{"hostname", Config{Target: "checkout.payments.svc:8080"}, families("orders_total"), "checkout", JobSourceHostname},
+		{"metric prefix", local, families("go_goroutines", "checkout_orders_total", "checkout_cart_size", "http_requests_total"), "checkout", JobSourceMetricPrefix},
+		{"default", Config{Target: "localhost:8080"}, families("orders_total", "http_requests_total"), defaultJobName, JobSourceDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, source := InferJobName(tt.cfg, tt.families)
+			require.Equal(t, tt.wantJob, job)
+			require.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	cfg := Config{
+		JobName:        "checkout-api",
+		Target:         "checkout:8080",
+		Scheme:         "http",
+		MetricsPath:    "/metrics",
+		ScrapeInterval: "30s",
+		Labels:         map[string]string{"cluster": "prod"},
+	}
+
+	data, err := Prometheus(cfg)
+	require.NoError(t, err)
+	var decoded prometheusConfig
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	require.Equal(t, "checkout-api", decoded.ScrapeConfigs[0].JobName)
+	require.Equal(t, []string{"checkout:8080"}, decoded.ScrapeConfigs[0].StaticConfigs[0].Targets)
+	require.Equal(t, "prod", decoded.ScrapeConfigs[0].StaticConfigs[0].Labels["cluster"])
+
+	alloy := Alloy(cfg)
+	require.Contains(t, alloy, "prometheus.scrape \"checkout_api\" {\n")
+	require.Contains(t, alloy, "targets         = [{\"__address__\" = \"checkout:8080\", \"cluster\" = \"prod\"}]\n")
+	require.Contains(t, alloy, "forward_to      = [prometheus.remote_write.grafana_cloud.receiver]\n")
+}
@@ -0,0 +1,105 @@
+// Package session keeps what each conversation with the agent has
+// established, so later tool calls in the conversation can reuse it.
+package session
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// conversation holds what one conversation has established so far
+type conversation struct {
+	values  map[string]any
+	updated time.Time
+}
+
+// Memory remembers the arguments each conversation (A2A context) settled
+// on, such as its Prometheus URL, Grafana instance, folder and dashboard, so
+// follow-up requests in the same conversation need not repeat them.
+// Conversations are forgotten once idle for the TTL, and the least recently
+// active ones when more than maxContexts are held.
+type Memory struct {
+	ttl         time.Duration
+	maxContexts int
+	now         func() time.Time
+
+	mu            sync.Mutex
+	conversations map[string]*conversation
+}
+
+// NewMemory creates a conversation memory. A TTL of zero or less disables
+// it and returns nil.
+func NewMemory(ttl time.Duration, maxContexts int) *Memory {
+	if ttl <= 0 {
+		return nil
+	}
+	return &Memory{
+		ttl:           ttl,
+		maxContexts:   maxContexts,
+		now:           time.Now,
+		conversations: make(map[string]*conversation),
+	}
+}
+
+// Recall returns a copy of the values remembered for a conversation
+func (m *Memory) Recall(contextID string) map[string]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.conversations[contextID]
+	if !ok {
+		return nil
+	}
+	if m.now().Sub(c.updated) > m.ttl {
+		delete(m.conversations, contextID)
+		return nil
+	}
+	return maps.Clone(c.values)
+}
+
+// Remember merges values into what a conversation has established; a nil
+// value forgets the entry
+func (m *Memory) Remember(contextID string, values map[string]any) {
+	if len(values) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	c, ok := m.conversations[contextID]
+	if !ok || now.Sub(c.updated) > m.ttl {
+		c = &conversation{values: make(map[string]any)}
+		m.conversations[contextID] = c
+	}
+	for name, value := range values {
+		if value == nil {
+			delete(c.values, name)
+			continue
+		}
+		c.values[name] = value
+	}
+	c.updated = now
+	m.evict(now)
+}
+
+// evict drops idle conversations, then the least recently active ones
+// beyond maxContexts
+func (m *Memory) evict(now time.Time) {
+	for id, c := range m.conversations {
+		if now.Sub(c.updated) > m.ttl {
+			delete(m.conversations, id)
+		}
+	}
+	for m.maxContexts > 0 && len(m.conversations) > m.maxContexts {
+		oldest := ""
+		for id, c := range m.conversations {
+			if oldest == "" || c.updated.Before(m.conversations[oldest].updated) {
+				oldest = id
+			}
+		}
+		delete(m.conversations, oldest)
+	}
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	memory := NewMemory(time.Hour, 2)
+	memory.now = func() time.Time { return now }
+
+	memory.Remember("a", map[string]any{"prometheus_url": "http://prom-a", "folder": "Payments"})
+	memory.Remember("a", map[string]any{"folder": nil, "dashboard_uid": "abc"})
+	values := memory.Recall("a")
+	if values["prometheus_url"] != "http://prom-a" || values["dashboard_uid"] != "abc" {
+		t.Errorf("expected the merged values, got %v", values)
+	}
+	if _, ok := values["folder"]; ok {
+		t.Errorf("expected the folder forgotten, got %v", values)
+	}
+	values["prometheus_url"] = "changed"
+	if memory.Recall("a")["prometheus_url"] != "http://prom-a" {
+		t.Error("expected Recall to return a copy")
+	}
+	if memory.Recall("b") != nil {
+		t.Error("expected nothing remembered for another conversation")
+	}
+
+	t.Run("forgets idle conversations", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		if values := memory.Recall("a"); values != nil {
+			t.Errorf("expected the idle conversation forgotten, got %v", values)
+		}
+	})
+
+	t.Run("evicts the least recently active conversation", func(t *testing.T) {
+		for i, id := range []string{"x", "y", "z"} {
+			now = now.Add(time.Duration(i) * time.Minute)
+			memory.Remember(id, map[string]any{"job": id})
+		}
+		if memory.Recall("x") != nil || memory.Recall("y") == nil || memory.Recall("z") == nil {
+			t.Errorf("expected only the oldest conversation evicted, got %v", memory.conversations)
+		}
+	})
+}
+
+func TestNewMemoryDisabled(t *testing.T) {
+	if NewMemory(0, 10) != nil {
+		t.Error("expected a zero TTL to disable the memory")
+	}
+}
@@ -0,0 +1,147 @@
+// Package state exports and imports what the agent needs to be moved to
+// another host or restored after loss: the inventory of the dashboards it
+// manages and its configuration, minus credentials.
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+	redact "github.com/inference-gateway/grafana-agent/internal/redact"
+)
+
+// FormatVersion is the version of the bundle format written by Export
+const FormatVersion = 1
+
+// policyFileVariable names the authorization policy file, whose content is
+// bundled with the configuration
+const policyFileVariable = "AUTHZ_POLICY_FILE"
+
+// ErrInventoryDisabled is returned when a bundle with dashboards is imported
+// without an inventory to record them in
+var ErrInventoryDisabled = errors.New("the bundle has managed dashboards but the inventory is disabled - set INVENTORY_BACKEND and INVENTORY_DSN to import them")
+
+// Bundle is the exported state of an agent
+type Bundle struct {
+	FormatVersion int       `json:"format_version"`
+	Agent         string    `json:"agent,omitempty"`
+	ExportedAt    time.Time `json:"exported_at"`
+	// Environment holds the agent's configuration variables. Credentials
+	// and the INVENTORY_ variables, which point at the old host's database,
+	// are left out.
+	Environment map[string]string `json:"environment"`
+	// Policy is the content of the AUTHZ_POLICY_FILE, if one is configured
+	Policy     string                `json:"policy,omitempty"`
+	Dashboards []inventory.Dashboard `json:"dashboards"`
+}
+
+// Export bundles the dashboards of the inventory, if any, and the
+// configuration variables found in environ (as returned by os.Environ)
+func Export(ctx context.Context, store inventory.Store, environ []string) (*Bundle, error) {
+	bundle := &Bundle{
+		FormatVersion: FormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Environment:   configEnvironment(environ),
+		Dashboards:    []inventory.Dashboard{},
+	}
+	if path := bundle.Environment[policyFileVariable]; path != "" {
+		policy, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read authorization policy: %w", err)
+		}
+		bundle.Policy = string(policy)
+	}
+	if store != nil {
+		dashboards, err := store.List(ctx, inventory.Filter{})
+		if err != nil {
+			return nil, err
+		}
+		bundle.Dashboards = append(bundle.Dashboards, dashboards...)
+	}
+	return bundle, nil
+}
+
+// Import records the dashboards of a bundle in the inventory, keeping their
+// creation and update times, and returns how many it recorded. Records of
+// the same dashboards are replaced.
+func Import(ctx context.Context, store inventory.Store, bundle *Bundle) (int, error) {
+	if bundle.FormatVersion != FormatVersion {
+		return 0, fmt.Errorf("unsupported bundle format version %d (expected %d)", bundle.FormatVersion, FormatVersion)
+	}
+	if len(bundle.Dashboards) == 0 {
+		return 0, nil
+	}
+	if store == nil {
+		return 0, ErrInventoryDisabled
+	}
+	for i, dashboard := range bundle.Dashboards {
+		if dashboard.GrafanaURL == "" || dashboard.UID == "" {
+			return i, fmt.Errorf("dashboard %d of the bundle has no grafana_url or uid", i)
+		}
+		if err := store.Record(ctx, dashboard); err != nil {
+			return i, err
+		}
+	}
+	return len(bundle.Dashboards), nil
+}
+
+// EnvFile renders the configuration of a bundle as a KEY=value env file,
+// sorted by name, quoting the values that need it
+func (b *Bundle) EnvFile() string {
+	names := make([]string, 0, len(b.Environment))
+	for name := range b.Environment {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var file strings.Builder
+	for _, name := range names {
+		value := b.Environment[name]
+		if strings.ContainsAny(value, " \t\n\"'#$\\") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&file, "%s=%s\n", name, value)
+	}
+	return file.String()
+}
+
+// configEnvironment picks the variables of environ that configure the
+// agent, skipping credentials and the inventory's own settings
+func configEnvironment(environ []string) map[string]string {
+	prefixes := configPrefixes()
+	env := map[string]string{}
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || redact.IsSecretName(name) || strings.HasPrefix(name, "INVENTORY_") {
+			continue
+		}
+		if slices.ContainsFunc(prefixes, func(prefix string) bool { return strings.HasPrefix(name, prefix) }) {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// configPrefixes returns the variable prefixes of the configuration
+// sections, such as GRAFANA_, read from the env tags of config.Config
+func configPrefixes() []string {
+	var prefixes []string
+	sections := reflect.TypeFor[config.Config]()
+	for i := 0; i < sections.NumField(); i++ {
+		for option := range strings.SplitSeq(sections.Field(i).Tag.Get("env"), ",") {
+			if prefix, ok := strings.CutPrefix(option, "prefix="); ok {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
@@ -0,0 +1,112 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+	inventoryfakes "github.com/inference-gateway/grafana-agent/internal/inventory/inventoryfakes"
+)
+
+func TestExport(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policyFile, []byte("roles: {}\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store := &inventoryfakes.FakeStore{}
+	store.ListReturns([]inventory.Dashboard{{UID: "abc", GrafanaURL: "http://grafana", Hash: "h"}}, nil)
+
+	bundle, err := Export(context.Background(), store, []string{
+		"GRAFANA_URL=http://grafana",
+		"GRAFANA_API_KEY=glsa_secret",
+		"GRAFANA_OAUTH_CLIENT_SECRET=secret",
+		"TIMEOUTS_TOOLS=create_dashboard=2m",
+		"AUTHZ_POLICY_FILE=" + policyFile,
+		"INVENTORY_DSN=postgres://agent:password@db/agent",
+		"HOME=/root",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"GRAFANA_URL":       "http://grafana",
+		"TIMEOUTS_TOOLS":    "create_dashboard=2m",
+		"AUTHZ_POLICY_FILE": policyFile,
+	}
+	if len(bundle.Environment) != len(want) {
+		t.Errorf("Expected %v, got %v", want, bundle.Environment)
+	}
+	for name, value := range want {
+		if bundle.Environment[name] != value {
+			t.Errorf("Expected %s=%s, got %q", name, value, bundle.Environment[name])
+		}
+	}
+	if bundle.Policy != "roles: {}\n" || bundle.FormatVersion != FormatVersion {
+		t.Errorf("Unexpected bundle %+v", bundle)
+	}
+	if len(bundle.Dashboards) != 1 || bundle.Dashboards[0].UID != "abc" {
+		t.Errorf("Unexpected dashboards %+v", bundle.Dashboards)
+	}
+
+	bundle, err = Export(context.Background(), nil, nil)
+	if err != nil || bundle.Dashboards == nil || len(bundle.Dashboards) != 0 {
+		t.Errorf("Expected an empty dashboard list without an inventory, got %+v, %v", bundle, err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bundle := &Bundle{
+		FormatVersion: FormatVersion,
+		Dashboards: []inventory.Dashboard{
+			{UID: "abc", GrafanaURL: "http://grafana", Hash: "h", CreatedAt: created, UpdatedAt: created},
+			{UID: "def", GrafanaURL: "http://grafana", Hash: "h"},
+		},
+	}
+
+	store := &inventoryfakes.FakeStore{}
+	imported, err := Import(context.Background(), store, bundle)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if imported != 2 || store.RecordCallCount() != 2 {
+		t.Errorf("Expected 2 dashboards recorded, got %d", store.RecordCallCount())
+	}
+	if _, record := store.RecordArgsForCall(0); !record.CreatedAt.Equal(created) {
+		t.Errorf("Expected the creation time to be kept, got %v", record.CreatedAt)
+	}
+
+	if _, err := Import(context.Background(), nil, bundle); !errors.Is(err, ErrInventoryDisabled) {
+		t.Errorf("Expected ErrInventoryDisabled, got %v", err)
+	}
+	if imported, err := Import(context.Background(), nil, &Bundle{FormatVersion: FormatVersion}); imported != 0 || err != nil {
+		t.Errorf("Expected a bundle without dashboards to import without an inventory, got %d, %v", imported, err)
+	}
+	if _, err := Import(context.Background(), store, &Bundle{FormatVersion: 2}); err == nil {
+		t.Error("Expected an error for an unknown format version")
+	}
+	if _, err := Import(context.Background(), store, &Bundle{FormatVersion: FormatVersion, Dashboards: []inventory.Dashboard{{UID: "abc"}}}); err == nil {
+		t.Error("Expected an error for a dashboard without a Grafana URL")
+	}
+}
+
+func TestEnvFile(t *testing.T) {
+	bundle := &Bundle{Environment: map[string]string{
+		"GRAFANA_URL":                    "http://grafana",
+		"ALERTING_LABELS":                "team:platform",
+		"GRAFANA_DEFAULT_TIMEZONE":       "Europe/Berlin",
+		"A2A_AGENT_CLIENT_SYSTEM_PROMPT": "You are \"helpful\"",
+	}}
+	want := "A2A_AGENT_CLIENT_SYSTEM_PROMPT=\"You are \\\"helpful\\\"\"\n" +
+		"ALERTING_LABELS=team:platform\n" +
+		"GRAFANA_DEFAULT_TIMEZONE=Europe/Berlin\n" +
+		"GRAFANA_URL=http://grafana\n"
+	if got := bundle.EnvFile(); got != want {
+		t.Errorf("EnvFile() = %q, want %q", got, want)
+	}
+}
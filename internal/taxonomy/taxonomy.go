@@ -0,0 +1,162 @@
+// Package taxonomy enforces the dashboard tag taxonomy configured with
+// GRAFANA_TAGS_*: tags are key:value pairs such as team:payments whose keys
+// are declared (env, team, service, tier, ...), normalized to lowercase
+// kebab-case. Undeclared tags are dropped or rejected.
+package taxonomy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// Supported GRAFANA_TAGS_MODE values
+const (
+	// ModeCorrect drops undeclared tags
+	ModeCorrect = "correct"
+	// ModeReject fails on undeclared tags
+	ModeReject = "reject"
+)
+
+// Key of the tag that ties a dashboard to a service (job)
+const KeyService = "service"
+
+// ErrUndeclaredTag is wrapped by the TagError of tags outside the taxonomy
+var ErrUndeclaredTag = errors.New("dashboard tags are not declared in the tag taxonomy")
+
+// Taxonomy is a set of declared tag keys; the zero value accepts any tag
+type Taxonomy struct {
+	// Keys are the declared tag keys, in configuration order
+	Keys []string
+	// Reject fails on undeclared tags instead of dropping them
+	Reject bool
+}
+
+// New returns the tag taxonomy of the configuration
+func New(cfg *config.GrafanaTagsConfig) Taxonomy {
+	if cfg == nil {
+		return Taxonomy{}
+	}
+	var taxonomy Taxonomy
+	for _, key := range cfg.Taxonomy {
+		if key = kebab(key); key != "" && !slices.Contains(taxonomy.Keys, key) {
+			taxonomy.Keys = append(taxonomy.Keys, key)
+		}
+	}
+	taxonomy.Reject = strings.EqualFold(strings.TrimSpace(cfg.Mode), ModeReject)
+	return taxonomy
+}
+
+// IsZero reports whether the taxonomy accepts any tag
+func (t Taxonomy) IsZero() bool {
+	return len(t.Keys) == 0
+}
+
+// Declares reports whether a key is declared
+func (t Taxonomy) Declares(key string) bool {
+	return slices.Contains(t.Keys, kebab(key))
+}
+
+// TagError lists the tags outside the taxonomy
+type TagError struct {
+	Tags []string `json:"tags"`
+	// Allowed are the declared tag keys
+	Allowed []string `json:"allowed"`
+}
+
+// Error implements the error interface
+func (e *TagError) Error() string {
+	return fmt.Sprintf("tags %s are not declared in the tag taxonomy: use key:value tags with one of the keys %s",
+		strings.Join(e.Tags, ", "), strings.Join(e.Allowed, ", "))
+}
+
+// Unwrap returns ErrUndeclaredTag
+func (e *TagError) Unwrap() error {
+	return ErrUndeclaredTag
+}
+
+// Correct normalizes tags and splits them into the declared ones, without
+// duplicates, and the dropped undeclared ones, as given. Without a taxonomy
+// the tags are kept as they are.
+func (t Taxonomy) Correct(tags []string) (kept, dropped []string) {
+	if t.IsZero() {
+		return slices.Clone(tags), nil
+	}
+	kept = []string{}
+	for _, tag := range tags {
+		normalized := Normalize(tag)
+		if normalized == "" {
+			continue
+		}
+		if !t.declared(normalized) {
+			dropped = append(dropped, tag)
+			continue
+		}
+		if !slices.Contains(kept, normalized) {
+			kept = append(kept, normalized)
+		}
+	}
+	return kept, dropped
+}
+
+// Enforce normalizes tags and drops the undeclared ones, or fails with a
+// TagError listing them when the taxonomy rejects undeclared tags
+func (t Taxonomy) Enforce(tags []string) (kept, dropped []string, err error) {
+	kept, dropped = t.Correct(tags)
+	if t.Reject && len(dropped) > 0 {
+		return nil, nil, &TagError{Tags: dropped, Allowed: t.Keys}
+	}
+	return kept, dropped, nil
+}
+
+// Tag returns the normalized key:value tag
+func Tag(key, value string) string {
+	return Normalize(key + ":" + value)
+}
+
+// Normalize lowercases a tag and turns its key and value into kebab-case;
+// "=" is accepted as separator, so "Team=Payments Core" becomes
+// "team:payments-core"
+func Normalize(tag string) string {
+	key, value, ok := strings.Cut(strings.Replace(tag, "=", ":", 1), ":")
+	if !ok {
+		return kebab(tag)
+	}
+	key, value = kebab(key), kebab(value)
+	switch {
+	case key == "":
+		return value
+	case value == "":
+		return key
+	}
+	return key + ":" + value
+}
+
+// declared reports whether a normalized tag is a key:value pair with a
+// declared key
+func (t Taxonomy) declared(tag string) bool {
+	key, value, ok := strings.Cut(tag, ":")
+	return ok && value != "" && slices.Contains(t.Keys, key)
+}
+
+// kebab lowercases s and joins its words with single hyphens
+func kebab(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,56 @@
+package taxonomy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Team=Payments Core":    "team:payments-core",
+		"env: Prod":             "env:prod",
+		"SERVICE:node_exporter": "service:node-exporter",
+		"Agent Generated":       "agent-generated",
+		"tier:":                 "tier",
+		":checkout":             "checkout",
+	}
+	for tag, want := range tests {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	tags := []string{"Team: Payments", "env=prod", "team:payments", "capacity", "owner:alice", "tier:1"}
+
+	correct := New(&config.GrafanaTagsConfig{Taxonomy: []string{"env", "Team", "service", "tier"}, Mode: ModeCorrect})
+	kept, dropped, err := correct.Enforce(tags)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"team:payments", "env:prod", "tier:1"}; !slices.Equal(kept, want) {
+		t.Errorf("Expected %v, got %v", want, kept)
+	}
+	if want := []string{"capacity", "owner:alice"}; !slices.Equal(dropped, want) {
+		t.Errorf("Expected %v dropped, got %v", want, dropped)
+	}
+
+	reject := New(&config.GrafanaTagsConfig{Taxonomy: []string{"env", "team"}, Mode: "Reject"})
+	_, _, err = reject.Enforce(tags)
+	var tagErr *TagError
+	if !errors.As(err, &tagErr) || !errors.Is(err, ErrUndeclaredTag) {
+		t.Fatalf("Expected a TagError, got %v", err)
+	}
+	if want := []string{"capacity", "owner:alice", "tier:1"}; !slices.Equal(tagErr.Tags, want) || !slices.Equal(tagErr.Allowed, []string{"env", "team"}) {
+		t.Errorf("Unexpected error %+v", tagErr)
+	}
+
+	kept, dropped, err = New(nil).Enforce(tags)
+	if err != nil || !slices.Equal(kept, tags) || dropped != nil {
+		t.Errorf("Expected tags kept as given without a taxonomy, got %v, %v, %v", kept, dropped, err)
+	}
+}
@@ -1,13 +1,12 @@
-// Code generated by ADL CLI v0.55.0. DO NOT EDIT.
-// This file was automatically generated from an ADL (Agent Definition Language) specification.
-// Manual changes to this file may be overwritten during regeneration.
-
 package main
 
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
@@ -26,9 +25,19 @@ import (
 	config "github.com/inference-gateway/grafana-agent/config"
 	tools "github.com/inference-gateway/grafana-agent/tools"
 
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
 	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	httpclient "github.com/inference-gateway/grafana-agent/internal/httpclient"
+	i18n "github.com/inference-gateway/grafana-agent/internal/i18n"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
 	logger "github.com/inference-gateway/grafana-agent/internal/logger"
 	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	redact "github.com/inference-gateway/grafana-agent/internal/redact"
+	session "github.com/inference-gateway/grafana-agent/internal/session"
+	state "github.com/inference-gateway/grafana-agent/internal/state"
 )
 
 // Version, AgentName and AgentDescription are injected at build time
@@ -139,6 +148,8 @@ func newRootCmd() *cobra.Command {
 		SilenceErrors: true,
 	}
 	root.AddCommand(newStartCmd())
+	root.AddCommand(newExportCmd())
+	root.AddCommand(newImportCmd())
 	return root
 }
 
@@ -155,6 +166,143 @@ func newStartCmd() *cobra.Command {
 	}
 }
 
+// newExportCmd returns the `export` subcommand which writes the managed
+// dashboard inventory and the configuration to a state bundle.
+func newExportCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the dashboard inventory and configuration",
+		Long:  "Export the inventory of managed dashboards and the agent's configuration, without credentials, to a JSON bundle that `import` restores on another host.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(cmd.Context(), output, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write the bundle to (default stdout)")
+	return cmd
+}
+
+// newImportCmd returns the `import` subcommand which restores a state
+// bundle written by `export`.
+func newImportCmd() *cobra.Command {
+	var input, envFile, policyFile string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import a bundle written by export",
+		Long:  "Record the managed dashboards of a bundle written by `export` in the configured inventory, and optionally write its configuration to an env file and its authorization policy to a file.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd.Context(), input, envFile, policyFile, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringVarP(&input, "input", "i", "", "file to read the bundle from (default stdin)")
+	cmd.Flags().StringVar(&envFile, "env-file", "", "file to write the bundle's configuration to, as KEY=value lines")
+	cmd.Flags().StringVar(&policyFile, "policy-file", "", "file to write the bundle's authorization policy to")
+	return cmd
+}
+
+// runExport writes the state bundle of the agent configured in the
+// environment to output, or to w without one
+func runExport(ctx context.Context, output string, w io.Writer) error {
+	var cfg config.Config
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := inventory.Open(ctx, &cfg.Inventory)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer func() { _ = store.Close() }()
+	}
+
+	bundle, err := state.Export(ctx, store, os.Environ())
+	if err != nil {
+		return err
+	}
+	bundle.Agent = AgentName + " " + Version
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		_, err = w.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "exported %d managed dashboards and %d configuration variables to %s\n", len(bundle.Dashboards), len(bundle.Environment), output)
+	return err
+}
+
+// runImport restores the state bundle read from input, or from stdin
+// without one, into the inventory configured in the environment
+func runImport(ctx context.Context, input, envFile, policyFile string, w io.Writer) error {
+	var cfg config.Config
+	if err := envconfig.Process(ctx, &cfg); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if input == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	var bundle state.Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	store, err := inventory.Open(ctx, &cfg.Inventory)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer func() { _ = store.Close() }()
+	}
+	imported, err := state.Import(ctx, store, &bundle)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "imported %d managed dashboards\n", imported); err != nil {
+		return err
+	}
+
+	if policyFile != "" && bundle.Policy != "" {
+		if err := os.WriteFile(policyFile, []byte(bundle.Policy), 0o600); err != nil {
+			return fmt.Errorf("failed to write authorization policy: %w", err)
+		}
+		// The restored configuration points at the policy where it now is
+		if bundle.Environment == nil {
+			bundle.Environment = map[string]string{}
+		}
+		if path, err := filepath.Abs(policyFile); err == nil {
+			bundle.Environment["AUTHZ_POLICY_FILE"] = path
+		}
+		if _, err := fmt.Fprintf(w, "wrote the authorization policy to %s\n", policyFile); err != nil {
+			return err
+		}
+	}
+	if envFile != "" {
+		if err := os.WriteFile(envFile, []byte(bundle.EnvFile()), 0o600); err != nil {
+			return fmt.Errorf("failed to write env file: %w", err)
+		}
+		if _, err := fmt.Fprintf(w, "wrote %d configuration variables to %s; add the credentials, which are not exported\n", len(bundle.Environment), envFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // runStart contains the original agent bootstrap. It is exported as a
 // dedicated function so the cobra command stays a thin shell - easier
 // to test, easier to embed.
@@ -179,6 +327,8 @@ func runStart(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
+	// Never let API keys or Authorization headers reach the logs
+	l = l.WithOptions(zap.WrapCore(redact.Core))
 
 	l.Info("starting "+AgentName+" agent", zap.String("version", Version), zap.Bool("debug", cfg.A2A.Debug))
 	l.Debug("loaded configuration", zap.Any("config", cfg))
@@ -194,17 +344,61 @@ func runStart(ctx context.Context) error {
 		l.Info("loaded skills manifest into system prompt", zap.String("dir", resolvedSkillsDir))
 	}
 
-	// Initialize services
-	grafanaSvc, err := grafana.NewGrafanaService(l, &cfg)
+	// Initialize services; they share one pooled transport
+	transport := httpclient.NewTransport(&cfg.HTTP)
+	grafanaSvc, err := grafana.NewGrafanaService(l, &cfg, transport)
 	if err != nil {
 		l.Error("failed to initialize grafana service", zap.Error(err))
 		return fmt.Errorf("failed to initialize grafana service: %w", err)
 	}
-	promqlSvc, err := promql.NewPromQLService(l, &cfg)
+	promqlSvc, err := promql.NewPromQLService(l, &cfg, transport)
 	if err != nil {
 		l.Error("failed to initialize promql service", zap.Error(err))
 		return fmt.Errorf("failed to initialize promql service: %w", err)
 	}
+	grafanacloudSvc, err := grafanacloud.NewGrafanaCloudService(l, &cfg, transport)
+	if err != nil {
+		l.Error("failed to initialize grafanacloud service", zap.Error(err))
+		return fmt.Errorf("failed to initialize grafanacloud service: %w", err)
+	}
+
+	// Deploy tools need a key that can save dashboards; fail fast otherwise
+	if user, err := deploy.CheckDeployRole(ctx, grafanaSvc, &cfg.Grafana); errors.Is(err, deploy.ErrInsufficientRole) {
+		l.Error("grafana api key cannot deploy dashboards", zap.Error(err))
+		return err
+	} else if err != nil {
+		l.Warn("could not verify the grafana api key role, deployments may fail", zap.Error(err))
+	} else if user != nil {
+		l.Info("grafana api key verified", zap.String("login", user.Login), zap.String("role", user.Role))
+	}
+
+	// Record the dashboards the agent deploys when an inventory is configured
+	inventoryStore, err := inventory.Open(ctx, &cfg.Inventory)
+	if err != nil {
+		l.Error("failed to open dashboard inventory", zap.Error(err))
+		return err
+	}
+	if inventoryStore != nil {
+		defer func() { _ = inventoryStore.Close() }()
+		l.Info("recording deployed dashboards in the inventory", zap.String("backend", cfg.Inventory.Backend))
+	}
+
+	// Settings every deploying tool passes on to its deployer
+	deployOptions := deploy.Options{Inventory: inventoryStore, AgentVersion: AgentName + " " + Version}
+
+	// Restrict tools and folders per caller role when a policy is configured
+	var policy *authz.Policy
+	if cfg.Authz.PolicyFile != "" {
+		policy, err = authz.LoadPolicy(cfg.Authz.PolicyFile)
+		if err != nil {
+			l.Error("failed to load authorization policy", zap.Error(err))
+			return err
+		}
+		l.Info("loaded authorization policy", zap.String("file", cfg.Authz.PolicyFile), zap.Int("roles", len(policy.Roles)))
+	}
+
+	// Remember the servers, folder and dashboard each conversation works with
+	sessionMemory := session.NewMemory(cfg.Session.TTL, cfg.Session.MaxContexts)
 
 	// Create toolbox with default tools (like input_required, create_artifact etc)
 	toolBox := server.NewDefaultToolBox(&cfg.A2A.AgentConfig.ToolBoxConfig)
@@ -219,29 +413,176 @@ func runStart(ctx context.Context) error {
 
 	// Register discover_metrics tool
 	discoverMetricsTool := tools.NewDiscoverMetricsTool(l, promqlSvc)
-	toolBox.AddTool(discoverMetricsTool)
-	l.Info("registered tool: discover_metrics (Discovers available metrics from a Prometheus endpoint with optional filtering)")
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(discoverMetricsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: discover_metrics (Discovers available metrics from a Prometheus endpoint, or from a service's /metrics endpoint before it is scraped, with optional filtering)")
+
+	// Register detect_exporters tool
+	detectExportersTool := tools.NewDetectExportersTool(l, promqlSvc)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(detectExportersTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: detect_exporters (Identifies well-known exporters (node_exporter, windows_exporter, cadvisor, blackbox, postgres_exporter, mysqld_exporter, redis_exporter, istio, ingress-nginx, traefik, haproxy, kafka_exporter, rabbitmq, nats_exporter, dcgm_exporter) and the dashboard templates that apply to each)")
 
 	// Register generate_promql_queries tool
-	generatePromqlQueriesTool := tools.NewGeneratePromqlQueriesTool(l, promqlSvc)
-	toolBox.AddTool(generatePromqlQueriesTool)
+	generatePromqlQueriesTool := tools.NewGeneratePromqlQueriesTool(l, promqlSvc, &cfg.Grafana, &cfg.Environment, &cfg.Alerting)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(generatePromqlQueriesTool, sessionMemory), &cfg.Timeouts), policy))
 	l.Info("registered tool: generate_promql_queries (Generates PromQL query suggestions for given metric names by querying Prometheus metadata)")
 
 	// Register validate_promql_query tool
 	validatePromqlQueryTool := tools.NewValidatePromqlQueryTool(l, promqlSvc)
-	toolBox.AddTool(validatePromqlQueryTool)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(validatePromqlQueryTool, sessionMemory), &cfg.Timeouts), policy))
 	l.Info("registered tool: validate_promql_query (Validates a PromQL query against a Prometheus server)")
 
 	// Register create_dashboard tool
-	createDashboardTool := tools.NewCreateDashboardTool(l, grafanaSvc, &cfg.Grafana)
-	toolBox.AddTool(createDashboardTool)
+	createDashboardTool := tools.NewCreateDashboardTool(l, grafanaSvc, grafanacloudSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createDashboardTool, sessionMemory), &cfg.Timeouts), policy))
 	l.Info("registered tool: create_dashboard (Creates a Grafana dashboard with specified panels, queries, and configurations)")
 
 	// Register deploy_dashboard tool
-	deployDashboardTool := tools.NewDeployDashboardTool(l, grafanaSvc, &cfg.Grafana)
-	toolBox.AddTool(deployDashboardTool)
+	deployDashboardTool := tools.NewDeployDashboardTool(l, grafanaSvc, grafanacloudSvc, &cfg.Grafana, &cfg.Alerting, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(deployDashboardTool, sessionMemory), &cfg.Timeouts), policy))
 	l.Info("registered tool: deploy_dashboard (Deploys a dashboard JSON to Grafana (Cloud or self-hosted))")
 
+	// Register verify_dashboard_data tool
+	verifyDashboardDataTool := tools.NewVerifyDashboardDataTool(l, grafanaSvc, promqlSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(verifyDashboardDataTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: verify_dashboard_data (Runs every target of a deployed dashboard over its time range, reports panels with no data and suggests fixed queries)")
+
+	// Register list_grafana_stacks tool
+	listGrafanaStacksTool := tools.NewListGrafanaStacksTool(l, grafanacloudSvc)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(listGrafanaStacksTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: list_grafana_stacks (Lists the Grafana Cloud stacks of the configured org with their URLs and status)")
+
+	// Register list_grafana_orgs tool
+	listGrafanaOrgsTool := tools.NewListGrafanaOrgsTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(listGrafanaOrgsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: list_grafana_orgs (Lists the Grafana organizations the configured credentials can access, with the role in each, so other tools can be pointed at one with org_id)")
+
+	// Register manage_correlations tool
+	manageCorrelationsTool := tools.NewManageCorrelationsTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(manageCorrelationsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: manage_correlations (Lists, creates or deletes Grafana datasource correlations linking metrics to related logs or traces)")
+
+	// Register manage_public_dashboard tool
+	managePublicDashboardTool := tools.NewManagePublicDashboardTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(managePublicDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: manage_public_dashboard (Lists public dashboards, or publishes, enables or disables the public share of a dashboard (e.g. a status page))")
+
+	// Register set_home_dashboard tool
+	setHomeDashboardTool := tools.NewSetHomeDashboardTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(setHomeDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: set_home_dashboard (Sets a dashboard as the home dashboard of the Grafana org or of a team)")
+
+	// Register verify_datasource tool
+	verifyDatasourceTool := tools.NewVerifyDatasourceTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(verifyDatasourceTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: verify_datasource (Checks that a Grafana datasource works by running its health check and a trivial query, and explains any misconfiguration)")
+
+	// Register diff_dashboard tool
+	diffDashboardTool := tools.NewDiffDashboardTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(diffDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: diff_dashboard (Compares a dashboard JSON with the version deployed in Grafana and lists the semantic changes, ignoring volatile fields)")
+
+	// Register import_dashboard tool; its downloads share the pooled transport
+	// behind a breaker of their own
+	importTransport := circuit.NewBreaker(l, "import", cfg.Circuit.FailureThreshold, cfg.Circuit.Cooldown).Transport(transport)
+	importDashboardTool := tools.NewImportDashboardTool(l, grafanaSvc, grafanacloudSvc, &cfg.Grafana, importTransport, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(importDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: import_dashboard (Imports a dashboard from raw JSON, a file or an HTTP(S) URL (e.g. a grafana.com dashboard), validates it and remaps its datasources before deploying it to Grafana)")
+
+	// Register update_panel tool
+	updatePanelTool := tools.NewUpdatePanelTool(l, grafanaSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(updatePanelTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: update_panel (Updates a single panel of a deployed dashboard (queries, thresholds, title, type, unit or description) without resubmitting the whole dashboard)")
+
+	// Register retag_dashboard tool
+	retagDashboardTool := tools.NewRetagDashboardTool(l, grafanaSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(retagDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: retag_dashboard (Adds, removes or replaces the tags of a deployed dashboard, normalizing them to the configured tag taxonomy and dropping or rejecting undeclared tags)")
+
+	// Register add_panel tool
+	addPanelTool := tools.NewAddPanelTool(l, grafanaSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(addPanelTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: add_panel (Appends a panel, generated from a metric name or an explicit PromQL query, to a deployed dashboard and places it in the next free grid position)")
+
+	// Register clone_dashboard tool
+	cloneDashboardTool := tools.NewCloneDashboardTool(l, grafanaSvc, grafanacloudSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(cloneDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: clone_dashboard (Copies a deployed dashboard to a new UID, title and folder, rewriting label matchers (e.g. env=\"staging\" to env=\"prod\") across all queries and template variables)")
+
+	// Register find_query_usage tool
+	findQueryUsageTool := tools.NewFindQueryUsageTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(findQueryUsageTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: find_query_usage (Scans all dashboards for panel, variable and annotation queries that use a metric or contain a PromQL fragment and reports where they are, e.g. before renaming or deleting a metric)")
+
+	// Register migrate_metrics tool
+	migrateMetricsTool := tools.NewMigrateMetricsTool(l, grafanaSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(migrateMetricsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: migrate_metrics (Renames deprecated metrics in every dashboard that queries them, given an old to new metric mapping; previews the changes as diffs by default and deploys all affected dashboards when dry_run is false)")
+
+	// Register sync_dashboards tool
+	syncDashboardsTool := tools.NewSyncDashboardsTool(l, grafanaSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(syncDashboardsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: sync_dashboards (Brings a set of dashboards in Grafana in line with the given dashboard JSON in two steps; plan computes the creates, updates and deletes against the current state and summarizes them like terraform plan; apply makes exactly the planned changes)")
+
+	// Register generate_remote_write_config tool
+	generateRemoteWriteConfigTool := tools.NewGenerateRemoteWriteConfigTool(l, promqlSvc, grafanacloudSvc)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(generateRemoteWriteConfigTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: generate_remote_write_config (Generates Prometheus and Grafana Alloy remote_write configuration for shipping metrics to Grafana Cloud, with relabel rules that keep the chosen metrics and drop the high-cardinality ones found in Prometheus)")
+
+	// Register generate_scrape_config tool
+	generateScrapeConfigTool := tools.NewGenerateScrapeConfigTool(l, promqlSvc, &cfg.Grafana, &cfg.Environment)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(generateScrapeConfigTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: generate_scrape_config (Onboards a new service from its /metrics URL: scrapes and parses the exposition directly (text format or OpenMetrics), infers the job name, and returns a Prometheus scrape_config, the equivalent Grafana Alloy component and a starter dashboard for its metrics)")
+
+	// Register modernize_dashboard tool
+	modernizeDashboardTool := tools.NewModernizeDashboardTool(l, grafanaSvc, &cfg.Grafana, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(modernizeDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: modernize_dashboard (Rewrites the deprecated angular panels of a deployed dashboard (graph, singlestat, old table) to timeseries, stat or gauge and table panels, mapping their options, ahead of an upgrade to Grafana 11)")
+
+	// Register create_capacity_dashboard tool
+	createCapacityDashboardTool := tools.NewCreateCapacityDashboardTool(l, grafanaSvc, grafanacloudSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createCapacityDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: create_capacity_dashboard (Builds a capacity forecasting dashboard for the saturating resources found in Prometheus (filesystems, persistent volumes, host and container memory, connection pools): utilization with a predict_linear forecast and days-until-full stat panels, optionally deploying it)")
+
+	// Register create_cost_dashboard tool
+	createCostDashboardTool := tools.NewCreateCostDashboardTool(l, grafanaSvc, grafanacloudSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createCostDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: create_cost_dashboard (Builds a metrics cost attribution dashboard showing active series, samples per second, cardinality growth and churn per job and namespace from the scrape metrics, with the top metrics and labels of the TSDB stats, to find which teams drive Grafana Cloud metrics billing; optionally deploys it)")
+
+	// Register get_grafana_cloud_usage tool
+	getGrafanaCloudUsageTool := tools.NewGetGrafanaCloudUsageTool(l, grafanacloudSvc, promqlSvc)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(getGrafanaCloudUsageTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: get_grafana_cloud_usage (Reports the Grafana Cloud org's current active series, log ingestion and active users against its plan, per stack where available, and warns when planned series or a recording rule set would push usage over the plan limits)")
+
+	// Register create_template_dashboard tool
+	createTemplateDashboardTool := tools.NewCreateTemplateDashboardTool(l, grafanaSvc, grafanacloudSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createTemplateDashboardTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: create_template_dashboard (Builds a dashboard from a built-in template for a well-known metric family, keeping the panels whose metrics Prometheus has, optionally scoped to one job and deployed)")
+
+	// Register export_alert_rules tool
+	exportAlertRulesTool := tools.NewExportAlertRulesTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(exportAlertRulesTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: export_alert_rules (Exports Grafana-managed alert rules, or alert rules generated by the agent, as a Prometheus rule file (groups: YAML) for Prometheus, Thanos Ruler or Mimir)")
+
+	// Register test_contact_point tool
+	testContactPointTool := tools.NewTestContactPointTool(l, grafanaSvc, &cfg.Grafana)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(testContactPointTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: test_contact_point (Sends a test notification through every integration (Slack, PagerDuty, email, ...) of a Grafana contact point and reports which ones delivered it, to verify alerting wiring right after it is configured)")
+
+	// Register create_service_overview tool
+	createServiceOverviewTool := tools.NewCreateServiceOverviewTool(l, grafanaSvc, grafanacloudSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createServiceOverviewTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: create_service_overview (Builds a landing dashboard for a service, identified by its job, with request rate, errors and latency, runtime stats, calls to dependencies, upstream and downstream calls from Tempo's service graph, firing alerts and links to the service's detail dashboards, optionally deployed)")
+
+	// Register create_fleet_overview tool
+	createFleetOverviewTool := tools.NewCreateFleetOverviewTool(l, grafanaSvc, grafanacloudSvc, promqlSvc, &cfg.Grafana, &cfg.Environment, deployOptions)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(createFleetOverviewTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: create_fleet_overview (Builds a fleet dashboard with one table row per service (job) showing availability, error ratio, P95 latency and a CPU sparkline, linking each service to its dashboards, optionally deployed)")
+
+	// Register list_managed_dashboards tool
+	listManagedDashboardsTool := tools.NewListManagedDashboardsTool(l, grafanaSvc, &cfg.Grafana, inventoryStore)
+	toolBox.AddTool(tools.WithAuthorization(tools.WithTimeout(tools.WithSessionMemory(listManagedDashboardsTool, sessionMemory), &cfg.Timeouts), policy))
+	l.Info("registered tool: list_managed_dashboards (Lists the dashboards the agent has deployed, with the prompt and template each came from, and reports which were edited in Grafana since (drifted) or deleted there (missing))")
+
 	llmClient, err := server.NewOpenAICompatibleLLMClient(&cfg.A2A.AgentConfig, l)
 	if err != nil {
 		return fmt.Errorf("failed to create LLM client: %w", err)
@@ -257,7 +598,23 @@ When using Prometheus-related tools:
 
 When using Grafana-related tools:
 - Use the GRAFANA_URL environment variable for grafana_url parameters if not explicitly provided by the user
+- Before deploying a dashboard, run verify_datasource on the datasources it queries and report any misconfiguration instead of deploying
+- If a deployment returns status conflict, tell the user the dashboard was changed in Grafana and ask before redeploying; never retry with overwrite on your own
+- When building panels from generate_promql_queries suggestions, use their panel_description as the panel description
+- Run migrate_metrics as a dry run first and show the user the affected dashboards and diffs; only run it with dry_run false once they confirm
+- Run modernize_dashboard as a dry run first and relay its conversions and warnings; only deploy the conversion once the user confirms
+- When asked for a runtime dashboard of a Go or JVM service, use create_template_dashboard with the go_runtime or jvm_runtime template and the service's job
+- When asked for an overview or landing dashboard of a service, use create_service_overview with the service's job, then offer to build the templates it lists for the same job so the overview's Detail dashboards link lists them
+- When asked to compare services or for a fleet view, use create_fleet_overview, limited to the jobs the user names
+- Before adding recording rules or scrape targets that ship to Grafana Cloud, check get_grafana_cloud_usage with the planned series and relay any plan limit warnings
+- After creating or changing alerting configuration, offer to verify the contact points with test_contact_point and relay any failed integrations
+- When asked to tag or retag a deployed dashboard, use retag_dashboard with key:value tags such as team:payments
+- When asked which dashboards the agent manages or whether they were edited by hand, use list_managed_dashboards
+- In follow-up requests of a conversation, prometheus_url, grafana_url, stack, folder and the dashboard_uid of the dashboard just deployed may be omitted; the tools reuse the values the conversation used last
 `
+	if language := cfg.Grafana.Defaults.Language; i18n.Base(language) != i18n.DefaultLanguage {
+		systemPrompt += fmt.Sprintf("- Write dashboard and panel titles, descriptions and summaries in %s; keep metric names, PromQL, label names and units untranslated\n", i18n.Name(language))
+	}
 	if skillsPrompt != "" {
 		systemPrompt = systemPrompt + "\n\n" + skillsPrompt
 	}
@@ -0,0 +1,441 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// gridColumns is the width of the Grafana dashboard grid
+const gridColumns = 24
+
+// AddPanelTool struct holds the tool with services
+type AddPanelTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewAddPanelTool creates a new add_panel tool
+func NewAddPanelTool(logger *zap.Logger, grafanaSvc grafana.Grafana, promqlSvc promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &AddPanelTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		promql:        promqlSvc,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"add_panel",
+		"Appends a panel, generated from a metric name or an explicit PromQL query, to a deployed dashboard and places it in the next free grid position",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard",
+					"type":        "string",
+				},
+				"description": map[string]any{
+					"description": "Optional panel description (defaults to one synthesized from the metric HELP text, what the query computes and the unit)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"height": map[string]any{
+					"description": "Panel height in grid units (default 8)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"legend_format": map[string]any{
+					"description": "Optional legend format such as \"{{route}}\"",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the new dashboard version",
+					"type":        "string",
+				},
+				"metric_name": map[string]any{
+					"description": "Metric to chart; the query and visualization are generated from its type (requires prometheus_url)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL used to look up metric_name and to validate query",
+					"type":        "string",
+				},
+				"query": map[string]any{
+					"description": "Explicit PromQL query for the panel (instead of metric_name)",
+					"type":        "string",
+				},
+				"title": map[string]any{
+					"description": "Panel title (defaults to one derived from the metric)",
+					"type":        "string",
+				},
+				"type": map[string]any{
+					"description": "Panel type (defaults to the suggested visualization, or timeseries)",
+					"type":        "string",
+				},
+				"unit": map[string]any{
+					"description": "Unit of the panel values (e.g. percent, s, bytes)",
+					"type":        "string",
+				},
+				"width": map[string]any{
+					"description": "Panel width in grid units out of 24 (default 12)",
+					"type":        "integer",
+					"minimum":     1,
+					"maximum":     gridColumns,
+				},
+			},
+			"required": []string{"dashboard_uid"},
+		},
+		tool.AddPanelHandler,
+	)
+}
+
+// AddPanelResponse represents the outcome of adding a panel
+type AddPanelResponse struct {
+	*deploy.Result
+	Panel map[string]any `json:"panel"`
+	// Warnings flags metrics of the panel query that have no samples in the
+	// dashboard's time range
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// AddPanelHandler handles the add_panel tool execution
+func (t *AddPanelTool) AddPanelHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "add_panel")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	dashboardUID, _ := args["dashboard_uid"].(string)
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	current, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard %s: %w", dashboardUID, err)
+	}
+	dashboard := current.Dashboard
+
+	panel, err := t.buildPanel(ctx, dashboard, args)
+	if err != nil {
+		return "", err
+	}
+
+	panels, _ := dashboard["panels"].([]any)
+	width, ok := toInt(args["width"])
+	if !ok || width <= 0 || width > gridColumns {
+		width = 12
+	}
+	height, ok := toInt(args["height"])
+	if !ok || height <= 0 {
+		height = 8
+	}
+	panel["id"] = nextPanelID(dashboard)
+	panel["gridPos"] = nextGridPos(panels, width, height)
+	if datasource := dashboardPrometheusDatasource(dashboard); datasource != nil {
+		panel["datasource"] = datasource
+		for _, target := range panel["targets"].([]any) {
+			target.(map[string]any)["datasource"] = datasource
+		}
+	}
+
+	if matchers := environmentMatchers(t.environment, true); len(matchers) > 0 {
+		templating, _ := dashboard["templating"].(map[string]any)
+		if templating == nil {
+			templating = map[string]any{}
+			dashboard["templating"] = templating
+		}
+		list, _ := templating["list"].([]any)
+		templating["list"] = append(list, environmentVariables(t.environment, list)...)
+		scopePanelsToEnvironment(map[string]any{"panels": []any{panel}}, matchers)
+	}
+	if err := enforceScope(map[string]any{"panels": []any{panel}}, t.environment); err != nil {
+		return "", err
+	}
+
+	var warnings []string
+	if prometheusURL, _ := args["prometheus_url"].(string); prometheusURL != "" {
+		from, to, start, end := dashboardTimeRange(dashboard, t.grafanaConfig, time.Now())
+		expr, _ := panel["targets"].([]any)[0].(map[string]any)["expr"].(string)
+		for _, metric := range staleMetrics(ctx, t.logger, t.promql, prometheusURL, promql.MetricNames(expr), start, end) {
+			warnings = append(warnings, staleMetricWarning(metric, from, to))
+		}
+	}
+
+	dashboard["panels"] = append(panels, panel)
+
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = fmt.Sprintf("Added panel %q via grafana-agent", panel["title"])
+	}
+
+	baseVersion, _ := toInt(dashboard["version"])
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:   dashboard,
+		GrafanaURL:  target.GrafanaURL,
+		FolderUID:   current.FolderUID,
+		Message:     message,
+		Overwrite:   baseVersion == 0,
+		BaseVersion: baseVersion,
+		Provenance:  dashboardProvenance(ctx, dashboard),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jsonBytes, err := json.MarshalIndent(AddPanelResponse{Result: result, Panel: panel, Warnings: warnings}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// buildPanel generates the new panel from metric_name or query
+func (t *AddPanelTool) buildPanel(ctx context.Context, dashboard map[string]any, args map[string]any) (map[string]any, error) {
+	metricName, _ := args["metric_name"].(string)
+	query, _ := args["query"].(string)
+	prometheusURL, _ := args["prometheus_url"].(string)
+	metricName, query = strings.TrimSpace(metricName), strings.TrimSpace(query)
+
+	suggestion := promql.QuerySuggestion{Query: query, VisualizationType: "timeseries"}
+	var metricInfo *promql.MetricInfo
+	switch {
+	case metricName != "" && query != "":
+		return nil, fmt.Errorf("metric_name and query are mutually exclusive - provide only one")
+	case metricName != "":
+		if prometheusURL == "" {
+			return nil, fmt.Errorf("prometheus_url is required to generate a panel from metric_name")
+		}
+		info, err := t.promql.GetMetricMetadata(ctx, prometheusURL, metricName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for %s: %w", metricName, err)
+		}
+		metricInfo = info
+		var opts promql.GenerateOptions
+		if server := detectServer(ctx, t.logger, t.promql, prometheusURL); server != nil {
+			opts.Features = &server.Features
+		}
+		suggestion = t.promql.GetBestQuery(t.promql.GenerateQueries(metricInfo, opts))
+		if suggestion.DashboardQuery != "" {
+			suggestion.Query = suggestion.DashboardQuery
+		}
+	case query != "":
+		if prometheusURL != "" {
+			resolved, _ := resolveTemplateVariables(query, dashboard, time.Hour)
+			if err := t.promql.ValidateQuery(ctx, prometheusURL, resolved); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("metric_name or query is required")
+	}
+
+	title := getStringOrDefault(args, "title", "")
+	if title == "" {
+		title = defaultPanelTitle(metricName, suggestion)
+	}
+
+	target := map[string]any{"refId": "A", "expr": suggestion.Query}
+	if suggestion.Exemplar {
+		target["exemplar"] = true
+	}
+	if legend, _ := args["legend_format"].(string); legend != "" {
+		target["legendFormat"] = legend
+	}
+
+	panelType := getStringOrDefault(args, "type", suggestion.VisualizationType)
+	if panelType == "" {
+		panelType = "timeseries"
+	}
+
+	panel := map[string]any{
+		"type":        panelType,
+		"title":       title,
+		"targets":     []any{target},
+		"options":     extractOptions(map[string]any{}),
+		"fieldConfig": extractFieldConfig(map[string]any{}),
+	}
+	if panelType == "state-timeline" || panelType == "status-history" {
+		shapeStatePanel(panel, map[string]any{})
+	}
+	unit, _ := args["unit"].(string)
+	if unit == "" {
+		unit = suggestion.Unit
+	}
+	description, _ := args["description"].(string)
+	if description == "" {
+		describer := promql.NewDescriber(dashboardDefaults(t.grafanaConfig).Language)
+		description = describer.DescribeSuggestion(metricInfo, suggestion, unit)
+	}
+	if description != "" {
+		panel["description"] = description
+	}
+	if unit != "" {
+		fieldConfigDefaults(panel)["unit"] = unit
+	}
+	if len(suggestion.Thresholds) > 0 {
+		fieldConfigDefaults(panel)["thresholds"] = suggestedThresholds(suggestion.Thresholds)
+	}
+	if panelType == "gauge" {
+		if min, _ := promql.GaugeRange(unit); min == nil && prometheusURL != "" {
+			t.addCapacityQuery(ctx, prometheusURL, panel)
+		}
+		shapeGaugePanel(panel)
+	}
+	return panel, nil
+}
+
+// addCapacityQuery gives a gauge over a single metric the max query of the
+// metric's companion capacity metric, such as process_max_fds for
+// process_open_fds, when Prometheus has it
+func (t *AddPanelTool) addCapacityQuery(ctx context.Context, prometheusURL string, panel map[string]any) {
+	targets := panel["targets"].([]any)
+	expr, _ := targets[0].(map[string]any)["expr"].(string)
+	metrics := promql.MetricNames(expr)
+	if len(metrics) != 1 {
+		return
+	}
+	candidates := promql.CapacityCandidates(metrics[0])
+	found, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", []string{fmt.Sprintf("{__name__=~%q}", strings.Join(candidates, "|"))})
+	if err != nil {
+		t.logger.Warn("failed to look up capacity metrics", zap.String("metric", metrics[0]), zap.Error(err))
+		return
+	}
+	if capacity, ok := promql.CapacityMetric(metrics[0], found); ok {
+		panel["targets"] = append(targets, map[string]any{"refId": gaugeCapacity, "expr": promql.CapacityQuery(expr, metrics[0], capacity)})
+	}
+}
+
+// suggestedThresholds converts suggested threshold steps into the absolute
+// thresholds of a panel's field config
+func suggestedThresholds(thresholds []promql.Threshold) map[string]any {
+	steps := make([]any, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		var value any
+		if threshold.Value != nil {
+			value = *threshold.Value
+		}
+		steps = append(steps, map[string]any{"color": threshold.Color, "value": value})
+	}
+	return map[string]any{"mode": "absolute", "steps": steps}
+}
+
+// defaultPanelTitle derives a title from the metric or the query
+func defaultPanelTitle(metricName string, suggestion promql.QuerySuggestion) string {
+	if metricName == "" {
+		if names := promql.MetricNames(suggestion.Query); len(names) > 0 {
+			metricName = names[0]
+		}
+	}
+	if metricName == "" {
+		return "New panel"
+	}
+	if suggestion.YAxisLabel != "" && suggestion.YAxisLabel != "value" {
+		return fmt.Sprintf("%s (%s)", metricName, suggestion.YAxisLabel)
+	}
+	return metricName
+}
+
+// nextPanelID returns an ID not used by any panel of the dashboard
+func nextPanelID(dashboard map[string]any) int {
+	maxID := 0
+	for _, panel := range flattenPanels(dashboard) {
+		if id, ok := toInt(panel["id"]); ok && id > maxID {
+			maxID = id
+		}
+	}
+	return maxID + 1
+}
+
+// nextGridPos places a w×h panel after the last top-level panel, continuing
+// the two-column flow of create_dashboard: beside the last panel when it fits
+// there without overlapping another panel, otherwise on a new row below all
+// panels
+func nextGridPos(panels []any, w, h int) map[string]any {
+	type rect struct{ x, y, w, h int }
+	var (
+		rects  []rect
+		last   rect
+		isRow  bool
+		bottom int
+	)
+	for _, raw := range panels {
+		panel, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		x, y, pw, ph, ok := panelGridPos(panel)
+		if !ok {
+			continue
+		}
+		r := rect{x, y, pw, ph}
+		rects = append(rects, r)
+		if len(rects) == 1 || y > last.y || (y == last.y && x >= last.x) {
+			last, isRow = r, panel["type"] == "row"
+		}
+		bottom = max(bottom, y+ph)
+	}
+
+	if len(rects) > 0 && !isRow {
+		candidate := rect{last.x + last.w, last.y, w, h}
+		fits := candidate.x+w <= gridColumns
+		for _, r := range rects {
+			if fits && candidate.x < r.x+r.w && r.x < candidate.x+w && candidate.y < r.y+r.h && r.y < candidate.y+h {
+				fits = false
+			}
+		}
+		if fits {
+			return map[string]any{"x": candidate.x, "y": candidate.y, "w": w, "h": h}
+		}
+	}
+	return map[string]any{"x": 0, "y": bottom, "w": w, "h": h}
+}
+
+// dashboardPrometheusDatasource returns the datasource of the first panel
+// or target that runs a PromQL expression, so new panels query the same
+// Prometheus as the rest of the dashboard
+func dashboardPrometheusDatasource(dashboard map[string]any) any {
+	for _, panel := range flattenPanels(dashboard) {
+		targets, _ := panel["targets"].([]any)
+		for _, raw := range targets {
+			target, _ := raw.(map[string]any)
+			if _, ok := target["expr"].(string); !ok {
+				continue
+			}
+			if target["datasource"] != nil {
+				return target["datasource"]
+			}
+			if panel["datasource"] != nil {
+				return panel["datasource"]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func runAddPanel(t *testing.T, fake *promqlfakes.FakePromQL, environment *config.EnvironmentConfig, args map[string]any) (AddPanelResponse, map[string]any, error) {
+	t.Helper()
+
+	var deployed map[string]any
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: panelDashboard(), FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = dashboard.Dashboard
+			return &grafana.DashboardResponse{ID: 1, UID: "checkout", Version: 4}, nil
+		},
+	}
+	tool := &AddPanelTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		promql:        fake,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+		environment:   environment,
+	}
+
+	args["dashboard_uid"] = "checkout"
+	result, err := tool.AddPanelHandler(context.Background(), args)
+	if err != nil {
+		return AddPanelResponse{}, deployed, err
+	}
+
+	var response AddPanelResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed, nil
+}
+
+func TestAddPanelHandler(t *testing.T) {
+	t.Run("from a metric name", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_request_duration_seconds", Type: promql.MetricTypeHistogram, Help: "Duration of HTTP requests"}, nil)
+		fake.GetBestQueryReturns(promql.QuerySuggestion{
+			Query:             "histogram_quantile(0.95, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))",
+			DashboardQuery:    "histogram_quantile(0.95, sum by (le) (rate(http_request_duration_seconds_bucket[$__rate_interval])))",
+			VisualizationType: "timeseries",
+			YAxisLabel:        "seconds",
+			Exemplar:          true,
+		})
+
+		response, deployed, err := runAddPanel(t, fake, nil, map[string]any{
+			"metric_name":    "http_request_duration_seconds",
+			"prometheus_url": "http://prometheus.test:9090",
+			"unit":           "s",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		panel := response.Panel
+		target := panel["targets"].([]any)[0].(map[string]any)
+		if panel["title"] != "http_request_duration_seconds (seconds)" || !strings.Contains(target["expr"].(string), "$__rate_interval") {
+			t.Errorf("unexpected panel %v", panel)
+		}
+		if want := "P95 of http_request_duration_seconds, computed from its histogram buckets over the dashboard rate interval, summed by le. Duration of HTTP requests. Unit: seconds."; panel["description"] != want {
+			t.Errorf("expected description %q, got %v", want, panel["description"])
+		}
+		if target["exemplar"] != true {
+			t.Errorf("expected exemplars to be requested, got %v", target)
+		}
+		if panel["id"] != 4.0 {
+			t.Errorf("expected the next free panel ID 4, got %v", panel["id"])
+		}
+		if ds, _ := target["datasource"].(map[string]any); ds["uid"] != "prom" {
+			t.Errorf("expected the dashboard's Prometheus datasource, got %v", target["datasource"])
+		}
+		if panels := deployed["panels"].([]any); len(panels) != 3 {
+			t.Errorf("expected the panel to be appended, got %d panels", len(panels))
+		}
+	})
+
+	t.Run("explicit query is validated and scoped to the environment", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		environment := &config.EnvironmentConfig{Labels: map[string]string{"cluster": "prod"}}
+
+		response, deployed, err := runAddPanel(t, fake, environment, map[string]any{
+			"query":          "sum(rate(http_requests_total[$__rate_interval]))",
+			"prometheus_url": "http://prometheus.test:9090",
+			"title":          "Throughput",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fake.ValidateQueryCallCount() != 1 {
+			t.Errorf("expected the query to be validated")
+		}
+		if _, _, query := fake.ValidateQueryArgsForCall(0); strings.Contains(query, "$__") {
+			t.Errorf("variables should be resolved before validation, got %s", query)
+		}
+
+		target := response.Panel["targets"].([]any)[0].(map[string]any)
+		if target["expr"] != `sum(rate(http_requests_total{cluster="$cluster"}[$__rate_interval]))` {
+			t.Errorf("expected the query to be scoped to the cluster, got %v", target["expr"])
+		}
+		variables := deployed["templating"].(map[string]any)["list"].([]any)
+		if len(variables) != 1 || variables[0].(map[string]any)["name"] != "cluster" {
+			t.Errorf("expected the cluster constant variable, got %v", variables)
+		}
+	})
+
+	t.Run("flags metrics without samples in the dashboard time range", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.SeriesReturns([]map[string]string{{"__name__": "http_requests_total", "job": "checkout"}}, nil)
+
+		response, _, err := runAddPanel(t, fake, nil, map[string]any{
+			"query":          "sum(rate(http_requests_total[5m])) / sum(rate(legacy_requests_total[5m]))",
+			"prometheus_url": "http://prometheus.test:9090",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_, _, matchers, start, end := fake.SeriesArgsForCall(0)
+		if len(matchers) != 2 || matchers[1] != `{__name__=~"legacy_requests_total(_bucket|_sum|_count)?"}` {
+			t.Errorf("unexpected matchers %v", matchers)
+		}
+		if got := end.Sub(start); got != 6*time.Hour {
+			t.Errorf("expected the default 6h dashboard range, got %s", got)
+		}
+		if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "legacy_requests_total has no samples between now-6h and now") {
+			t.Errorf("expected a stale metric warning, got %v", response.Warnings)
+		}
+	})
+
+	t.Run("gauges are bounded by the capacity metric", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"process_max_fds"}, nil)
+
+		response, _, err := runAddPanel(t, fake, nil, map[string]any{
+			"query":          `process_open_fds{job="api"}`,
+			"prometheus_url": "http://prometheus.test:9090",
+			"type":           "gauge",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, _, _, matchers := fake.GetLabelValuesArgsForCall(0); matchers[0] != `{__name__=~"process_max_fds|process_limit_fds|process_open_max_fds|process_open_limit_fds"}` {
+			t.Errorf("unexpected capacity lookup %v", matchers)
+		}
+		targets := response.Panel["targets"].([]any)
+		if len(targets) != 2 || targets[1].(map[string]any)["expr"] != `process_max_fds{job="api"}` {
+			t.Errorf("expected a max query over process_max_fds, got %v", targets)
+		}
+		if transformations := response.Panel["transformations"].([]any); transformations[0].(map[string]any)["id"] != "configFromData" {
+			t.Errorf("expected the max taken from the query, got %v", transformations)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.ValidateQueryReturns(errors.New("query validation failed: parse error"))
+
+		for _, tt := range []struct {
+			args    map[string]any
+			wantErr string
+		}{
+			{map[string]any{}, "metric_name or query is required"},
+			{map[string]any{"metric_name": "up", "query": "up"}, "mutually exclusive"},
+			{map[string]any{"metric_name": "up"}, "prometheus_url is required"},
+			{map[string]any{"query": "rate((up[5m])", "prometheus_url": "http://prometheus.test:9090"}, "parse error"},
+		} {
+			_, _, err := runAddPanel(t, fake, nil, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
+
+func TestNextGridPos(t *testing.T) {
+	panel := func(x, y, w, h int, panelType string) any {
+		return map[string]any{"type": panelType, "gridPos": map[string]any{"x": x, "y": y, "w": w, "h": h}}
+	}
+
+	tests := []struct {
+		name   string
+		panels []any
+		wantX  int
+		wantY  int
+	}{
+		{name: "empty dashboard", panels: nil, wantX: 0, wantY: 0},
+		{name: "beside a half-width panel", panels: []any{panel(0, 0, 12, 8, "stat"), panel(12, 0, 12, 8, "stat"), panel(0, 8, 12, 8, "stat")}, wantX: 12, wantY: 8},
+		{name: "below a full row", panels: []any{panel(0, 0, 12, 8, "stat"), panel(12, 0, 12, 8, "stat")}, wantX: 0, wantY: 8},
+		{name: "below a taller neighbour", panels: []any{panel(12, 0, 12, 16, "stat"), panel(0, 8, 6, 8, "stat")}, wantX: 0, wantY: 16},
+		{name: "below a row header", panels: []any{panel(0, 0, 12, 8, "stat"), panel(0, 8, 24, 1, "row")}, wantX: 0, wantY: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := nextGridPos(tt.panels, 12, 8)
+			if pos["x"] != tt.wantX || pos["y"] != tt.wantY {
+				t.Errorf("expected (%d, %d), got (%v, %v)", tt.wantX, tt.wantY, pos["x"], pos["y"])
+			}
+		})
+	}
+}
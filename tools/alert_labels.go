@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"strings"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// tagLabels extracts routing labels from key:value (or key=value) dashboard
+// tags whose key is one of the routing tags, e.g. "team:payments"
+func tagLabels(tags []string, routingTags []string) map[string]string {
+	routing := make(map[string]bool, len(routingTags))
+	for _, key := range routingTags {
+		routing[strings.ToLower(strings.TrimSpace(key))] = true
+	}
+
+	labels := map[string]string{}
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			key, value, ok = strings.Cut(tag, "=")
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || value == "" || !routing[key] || !labelNamePattern.MatchString(key) {
+			continue
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// applyAlertLabels attaches routing labels to alert rule candidates. Each
+// rule starts from its own severity, then the configured ALERTING_LABELS,
+// then labels derived from the dashboard tags, later sources winning.
+func applyAlertLabels(rules []promql.AlertRuleCandidate, tags []string, alerting *config.AlertingConfig) {
+	var configured map[string]string
+	var routingTags []string
+	if alerting != nil {
+		configured = alerting.Labels
+		routingTags = alerting.RoutingTags
+	}
+	derived := tagLabels(tags, routingTags)
+
+	for i := range rules {
+		labels := map[string]string{}
+		if rules[i].Severity != "" {
+			labels["severity"] = rules[i].Severity
+		}
+		for key, value := range configured {
+			if labelNamePattern.MatchString(key) && value != "" {
+				labels[key] = value
+			}
+		}
+		for key, value := range derived {
+			labels[key] = value
+		}
+		rules[i].Severity = labels["severity"]
+		rules[i].Labels = labels
+	}
+}
@@ -0,0 +1,263 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	server "github.com/inference-gateway/adk/server"
+
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// Argument validation error codes
+const (
+	ArgErrorRequired     = "required"
+	ArgErrorInvalidType  = "invalid_type"
+	ArgErrorInvalidEnum  = "invalid_enum"
+	ArgErrorOutOfRange   = "out_of_range"
+	ArgErrorUnknownField = "unknown_field"
+)
+
+// ArgumentError describes a single argument that does not match a tool schema
+type ArgumentError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ArgumentValidationError is returned when tool arguments do not match the
+// tool's declared JSON schema. Its message embeds the individual errors as
+// JSON so the calling LLM can correct each field.
+type ArgumentValidationError struct {
+	Tool   string          `json:"tool"`
+	Errors []ArgumentError `json:"errors"`
+}
+
+// Error implements the error interface
+func (e *ArgumentValidationError) Error() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Sprintf("invalid arguments for %s", e.Tool)
+	}
+	return "invalid arguments: " + string(data)
+}
+
+// toolHandler is the signature shared by all tool handlers
+type toolHandler func(ctx context.Context, args map[string]any) (string, error)
+
+// newValidatedTool creates a tool whose arguments are checked against its
+// schema (types, enums, required fields and numeric bounds) before the
+// handler runs. Failures are reported as a JSON ErrorEnvelope. An org_id
+// argument switches every Grafana request of the call to that organization.
+func newValidatedTool(name, description string, schema map[string]any, handler toolHandler) server.Tool {
+	return server.NewBasicTool(name, description, schema, func(ctx context.Context, args map[string]any) (string, error) {
+		if err := validateArgs(name, schema, args); err != nil {
+			return "", &ErrorEnvelope{Err: toToolError(err)}
+		}
+		if orgID, ok := toInt(args["org_id"]); ok && orgID > 0 {
+			ctx = grafana.WithOrgID(ctx, orgID)
+		}
+		result, err := handler(ctx, args)
+		if err != nil {
+			return "", &ErrorEnvelope{Err: toToolError(err)}
+		}
+		return result, nil
+	})
+}
+
+// validateArgs checks arguments against a JSON schema object definition
+func validateArgs(tool string, schema map[string]any, args map[string]any) error {
+	var errs []ArgumentError
+	validateObject("", schema, args, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ArgumentValidationError{Tool: tool, Errors: errs}
+}
+
+// validateObject validates the required fields and declared properties of an object
+func validateObject(path string, schema map[string]any, value map[string]any, errs *[]ArgumentError) {
+	properties, _ := schema["properties"].(map[string]any)
+
+	for _, name := range schemaStrings(schema["required"]) {
+		if v, ok := value[name]; !ok || v == nil {
+			*errs = append(*errs, ArgumentError{Field: joinPath(path, name), Code: ArgErrorRequired, Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := value[name]
+		propSchema, ok := properties[name].(map[string]any)
+		if !ok {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				*errs = append(*errs, ArgumentError{Field: joinPath(path, name), Code: ArgErrorUnknownField, Message: "is not a supported argument"})
+			}
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		validateValue(joinPath(path, name), propSchema, v, errs)
+	}
+}
+
+// validateValue validates a single value against its property schema
+func validateValue(path string, schema map[string]any, value any, errs *[]ArgumentError) {
+	expected, _ := schema["type"].(string)
+	if expected != "" && !matchesType(expected, value) {
+		*errs = append(*errs, ArgumentError{
+			Field:   path,
+			Code:    ArgErrorInvalidType,
+			Message: fmt.Sprintf("must be of type %s, got %s", expected, jsonTypeName(value)),
+		})
+		return
+	}
+
+	if enum := schemaValues(schema["enum"]); len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*errs = append(*errs, ArgumentError{
+				Field:   path,
+				Code:    ArgErrorInvalidEnum,
+				Message: fmt.Sprintf("must be one of %v, got %v", enum, value),
+			})
+		}
+	}
+
+	if n, ok := toFloat(value); ok {
+		if minimum, ok := toFloat(schema["minimum"]); ok && n < minimum {
+			*errs = append(*errs, ArgumentError{Field: path, Code: ArgErrorOutOfRange, Message: fmt.Sprintf("must be >= %v", minimum)})
+		}
+		if maximum, ok := toFloat(schema["maximum"]); ok && n > maximum {
+			*errs = append(*errs, ArgumentError{Field: path, Code: ArgErrorOutOfRange, Message: fmt.Sprintf("must be <= %v", maximum)})
+		}
+	}
+
+	switch v := value.(type) {
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), items, item, errs)
+			}
+		}
+	case map[string]any:
+		validateObject(path, schema, v, errs)
+	}
+}
+
+// matchesType reports whether a decoded JSON value has the given schema type
+func matchesType(expected string, value any) bool {
+	switch expected {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		n, ok := toFloat(value)
+		return ok && n == math.Trunc(n)
+	case "array":
+		switch value.(type) {
+		case []any, []string, []map[string]any:
+			return true
+		}
+		return false
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// jsonTypeName names the JSON type of a decoded value for error messages
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any, []string, []map[string]any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// schemaStrings reads a schema list declared as []string or []any
+func schemaStrings(raw any) []string {
+	switch v := raw.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaValues reads an enum declared as []string or []any
+func schemaValues(raw any) []any {
+	switch v := raw.(type) {
+	case []string:
+		out := make([]any, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	case []any:
+		return v
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
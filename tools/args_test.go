@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestValidateArgs(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"url":     map[string]any{"type": "string"},
+			"limit":   map[string]any{"type": "integer", "minimum": 1, "maximum": 10},
+			"ratio":   map[string]any{"type": "number"},
+			"enabled": map[string]any{"type": "boolean"},
+			"mode":    map[string]any{"type": "string", "enum": []string{"fast", "slow"}},
+			"names":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"range": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"from": map[string]any{"type": "string"}},
+				"required":   []string{"from"},
+			},
+		},
+		"required": []string{"url"},
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected []ArgumentError
+	}{
+		{
+			name: "valid arguments",
+			args: map[string]any{
+				"url": "http://x", "limit": float64(3), "ratio": 0.5, "enabled": true,
+				"mode": "fast", "names": []any{"a"}, "range": map[string]any{"from": "now-1h"},
+				"extra": "ignored without additionalProperties",
+			},
+		},
+		{
+			name: "null optional argument",
+			args: map[string]any{"url": "http://x", "limit": nil},
+		},
+		{
+			name:     "missing required",
+			args:     map[string]any{},
+			expected: []ArgumentError{{Field: "url", Code: ArgErrorRequired}},
+		},
+		{
+			name: "wrong types",
+			args: map[string]any{"url": float64(1), "limit": 2.5, "enabled": "yes"},
+			expected: []ArgumentError{
+				{Field: "enabled", Code: ArgErrorInvalidType},
+				{Field: "limit", Code: ArgErrorInvalidType},
+				{Field: "url", Code: ArgErrorInvalidType},
+			},
+		},
+		{
+			name: "enum, range and nested errors",
+			args: map[string]any{"url": "http://x", "mode": "medium", "limit": float64(11), "names": []any{"a", float64(2)}, "range": map[string]any{}},
+			expected: []ArgumentError{
+				{Field: "limit", Code: ArgErrorOutOfRange},
+				{Field: "mode", Code: ArgErrorInvalidEnum},
+				{Field: "names[1]", Code: ArgErrorInvalidType},
+				{Field: "range.from", Code: ArgErrorRequired},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArgs("test_tool", schema, tt.args)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *ArgumentValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ArgumentValidationError, got %v", err)
+			}
+			if len(validationErr.Errors) != len(tt.expected) {
+				t.Fatalf("Expected %d errors, got %+v", len(tt.expected), validationErr.Errors)
+			}
+			for i, e := range tt.expected {
+				got := validationErr.Errors[i]
+				if got.Field != e.Field || got.Code != e.Code {
+					t.Errorf("Error %d: expected %s/%s, got %s/%s", i, e.Field, e.Code, got.Field, got.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateArgs_AdditionalProperties(t *testing.T) {
+	schema := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties":           map[string]any{"file_path": map[string]any{"type": "string"}},
+	}
+
+	err := validateArgs("Read", schema, map[string]any{"file_path": "a", "mode": "x"})
+	var validationErr *ArgumentValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Code != ArgErrorUnknownField {
+		t.Errorf("Expected unknown_field error, got %v", err)
+	}
+}
+
+func TestNewValidatedTool_RejectsBeforeHandler(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	tool := NewDiscoverMetricsTool(zap.NewNop(), fakePromQL)
+
+	_, err := tool.Execute(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"limit":          "ten",
+		"sort_by":        "size",
+	})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if !strings.Contains(err.Error(), `"field":"limit"`) || !strings.Contains(err.Error(), `"code":"invalid_enum"`) {
+		t.Errorf("Expected machine-readable field errors, got %s", err.Error())
+	}
+	if fakePromQL.DiscoverMetricsCallCount() != 0 {
+		t.Error("Expected handler not to run with invalid arguments")
+	}
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+)
+
+// callerMetadataKey is the A2A message metadata entry that identifies the
+// caller, e.g. {"caller": {"id": "alice", "role": "dev"}}
+const callerMetadataKey = "caller"
+
+// authorizedTool checks the caller's role against the authorization policy
+// before running the wrapped tool, and carries the role in the context so
+// the deployer can check the destination folder
+type authorizedTool struct {
+	server.Tool
+	policy *authz.Policy
+}
+
+// WithAuthorization wraps a tool so only the roles the policy allows may run
+// it. A nil policy leaves the tool unrestricted.
+func WithAuthorization(tool server.Tool, policy *authz.Policy) server.Tool {
+	if policy == nil {
+		return tool
+	}
+	return &authorizedTool{Tool: tool, policy: policy}
+}
+
+// Execute runs the wrapped tool when the caller's role allows it
+func (t *authorizedTool) Execute(ctx context.Context, args map[string]any) (string, error) {
+	role := t.policy.Resolve(callerRole(ctx))
+	if err := t.policy.AuthorizeTool(role, t.GetName()); err != nil {
+		return "", &ErrorEnvelope{Err: toToolError(err)}
+	}
+	return t.Tool.Execute(authz.NewContext(ctx, t.policy, role), args)
+}
+
+// callerRole reads the caller's role from the metadata of the latest user
+// message, falling back to the task metadata
+func callerRole(ctx context.Context) string {
+	task, ok := ctx.Value(server.TaskContextKey).(*types.Task)
+	if !ok || task == nil {
+		return ""
+	}
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].Role != types.RoleUser {
+			continue
+		}
+		if role := metadataRole(task.History[i].Metadata); role != "" {
+			return role
+		}
+		break
+	}
+	return metadataRole(task.Metadata)
+}
+
+// metadataRole returns caller.role from A2A metadata
+func metadataRole(metadata *types.Struct) string {
+	if metadata == nil {
+		return ""
+	}
+	caller, _ := (*metadata)[callerMetadataKey].(map[string]any)
+	role, _ := caller["role"].(string)
+	return role
+}
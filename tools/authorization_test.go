@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+)
+
+func callerTask(messageRole, taskRole string) *types.Task {
+	task := &types.Task{ID: "task-1"}
+	if taskRole != "" {
+		task.Metadata = &types.Struct{"caller": map[string]any{"role": taskRole}}
+	}
+	message := types.Message{Role: types.RoleUser}
+	if messageRole != "" {
+		message.Metadata = &types.Struct{"caller": map[string]any{"id": "alice", "role": messageRole}}
+	}
+	task.History = []types.Message{message}
+	return task
+}
+
+func TestCallerRole(t *testing.T) {
+	tests := []struct {
+		name string
+		task *types.Task
+		want string
+	}{
+		{"no task", nil, ""},
+		{"message metadata", callerTask("dev", "sre"), "dev"},
+		{"task metadata", callerTask("", "sre"), "sre"},
+		{"no caller", callerTask("", ""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.task != nil {
+				ctx = context.WithValue(ctx, server.TaskContextKey, tt.task)
+			}
+			if got := callerRole(ctx); got != tt.want {
+				t.Errorf("Expected role %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithAuthorization(t *testing.T) {
+	var scope authz.Scope
+	tool := server.NewBasicTool("deploy_dashboard", "probe", map[string]any{"type": "object"}, func(ctx context.Context, args map[string]any) (string, error) {
+		scope, _ = authz.FromContext(ctx)
+		return "ok", nil
+	})
+	policy := &authz.Policy{
+		DefaultRole: "viewer",
+		Roles: map[string]authz.Role{
+			"viewer": {Tools: []string{"discover_metrics"}},
+			"dev":    {Tools: []string{authz.Wildcard}, Folders: []string{"Sandbox"}},
+		},
+	}
+
+	if unrestricted := WithAuthorization(tool, nil); unrestricted != tool {
+		t.Error("Expected no policy to return the tool unchanged")
+	}
+
+	wrapped := WithAuthorization(tool, policy)
+	ctx := context.WithValue(context.Background(), server.TaskContextKey, callerTask("dev", ""))
+	if _, err := wrapped.Execute(ctx, map[string]any{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if scope.Role() != "dev" || !scope.RestrictsFolders() {
+		t.Errorf("Expected the dev scope in the tool context, got %+v", scope)
+	}
+
+	_, err := wrapped.Execute(context.Background(), map[string]any{})
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrCodePermissionDenied || toolErr.Retryable {
+		t.Fatalf("Expected a permission_denied error for the default role, got %v", err)
+	}
+	if !errors.Is(err, authz.ErrForbidden) {
+		t.Errorf("Expected the error to wrap ErrForbidden, got %v", err)
+	}
+}
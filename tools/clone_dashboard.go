@@ -0,0 +1,339 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// CloneDashboardTool struct holds the tool with services
+type CloneDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	grafanaConfig *config.GrafanaConfig
+	deployOptions deploy.Options
+}
+
+// NewCloneDashboardTool creates a new clone_dashboard tool
+func NewCloneDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CloneDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"clone_dashboard",
+		"Copies a deployed dashboard to a new UID, title and folder, rewriting label matchers (e.g. env=\"staging\" to env=\"prod\") across all queries and template variables",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"folder": map[string]any{
+					"description": "Optional folder path such as \"Platform/Payments\" for the copy; missing folders are created (mutually exclusive with folder_uid). Defaults to the source dashboard's folder",
+					"type":        "string",
+				},
+				"folder_uid": map[string]any{
+					"description": "Optional folder UID for the copy. Defaults to the source dashboard's folder",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the copy's first version",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"rewrites": map[string]any{
+					"description": "Label rewrite rules applied to every query, annotation and template variable; regex matchers are rewritten per | alternative",
+					"type":        "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"label": map[string]any{"type": "string", "description": "Label name, e.g. env"},
+							"from":  map[string]any{"type": "string", "description": "Value to replace, e.g. staging"},
+							"to":    map[string]any{"type": "string", "description": "Replacement value, e.g. prod"},
+						},
+						"required": []string{"label", "from", "to"},
+					},
+				},
+				"source_uid": map[string]any{
+					"description": "UID of the dashboard to copy",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack holding the source dashboard and receiving the copy, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+				"title": map[string]any{
+					"description": "Title of the copy (defaults to the source title with \" (copy)\" appended)",
+					"type":        "string",
+				},
+				"uid": map[string]any{
+					"description": "UID of the copy (Grafana generates one when omitted)",
+					"type":        "string",
+				},
+			},
+			"required": []string{"source_uid"},
+		},
+		tool.CloneDashboardHandler,
+	)
+}
+
+// RewriteUsage reports how often a rewrite rule was applied
+type RewriteUsage struct {
+	promql.MatcherRewrite
+	Matches int `json:"matches"`
+}
+
+// CloneDashboardResponse represents the outcome of a clone
+type CloneDashboardResponse struct {
+	*deploy.Result
+	SourceUID string         `json:"source_uid"`
+	Rewrites  []RewriteUsage `json:"rewrites,omitempty"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// CloneDashboardHandler handles the clone_dashboard tool execution
+func (t *CloneDashboardTool) CloneDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "clone_dashboard")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	sourceUID, _ := args["source_uid"].(string)
+	uid, _ := args["uid"].(string)
+	if uid != "" && uid == sourceUID {
+		return "", fmt.Errorf("uid must differ from source_uid")
+	}
+
+	rewrites, err := parseRewrites(args["rewrites"])
+	if err != nil {
+		return "", err
+	}
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, stack)
+	if err != nil {
+		return "", err
+	}
+
+	source, err := t.grafanaSvc.GetDashboard(ctx, sourceUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard %s: %w", sourceUID, err)
+	}
+	dashboard := source.Dashboard
+
+	title := getStringOrDefault(args, "title", "")
+	if title == "" {
+		title = getStringOrDefault(dashboard, "title", sourceUID) + " (copy)"
+	}
+	dashboard["title"] = title
+	delete(dashboard, "id")
+	delete(dashboard, "version")
+	delete(dashboard, "uid")
+	if uid != "" {
+		dashboard["uid"] = uid
+	}
+
+	usage := rewriteDashboardLabels(dashboard, rewrites)
+	var warnings []string
+	for _, u := range usage {
+		if u.Matches == 0 {
+			warnings = append(warnings, fmt.Sprintf("rewrite %s=%q matched no query or variable", u.Label, u.From))
+		}
+	}
+
+	folderUID, _ := args["folder_uid"].(string)
+	folderPath, _ := args["folder"].(string)
+	if folderUID == "" && folderPath == "" {
+		folderUID = source.FolderUID
+	}
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = fmt.Sprintf("Cloned from %s via grafana-agent", sourceUID)
+	}
+
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:  dashboard,
+		GrafanaURL: grafanaURL,
+		Stack:      stack,
+		FolderUID:  folderUID,
+		FolderPath: folderPath,
+		Message:    message,
+		Provenance: dashboardProvenance(ctx, dashboard),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jsonBytes, err := json.MarshalIndent(CloneDashboardResponse{
+		Result:    result,
+		SourceUID: sourceUID,
+		Rewrites:  usage,
+		Warnings:  warnings,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// parseRewrites validates the rewrites argument
+func parseRewrites(raw any) ([]promql.MatcherRewrite, error) {
+	items, _ := raw.([]any)
+	rewrites := make([]promql.MatcherRewrite, 0, len(items))
+	for i, item := range items {
+		rule, _ := item.(map[string]any)
+		label, _ := rule["label"].(string)
+		from, _ := rule["from"].(string)
+		to, _ := rule["to"].(string)
+		switch {
+		case strings.TrimSpace(label) == "":
+			return nil, fmt.Errorf("rewrites[%d].label is required", i)
+		case from == "":
+			return nil, fmt.Errorf("rewrites[%d].from is required", i)
+		case from == to:
+			return nil, fmt.Errorf("rewrites[%d].to must differ from from", i)
+		}
+		rewrites = append(rewrites, promql.MatcherRewrite{Label: strings.TrimSpace(label), From: from, To: to})
+	}
+	return rewrites, nil
+}
+
+// rewriteDashboardLabels applies the rewrites in place to the queries of a
+// dashboard and counts the matches of each rule. Variables named after a
+// rewritten label (constant, custom and textbox variables such as `env`) have
+// their values rewritten too.
+func rewriteDashboardLabels(dashboard map[string]any, rewrites []promql.MatcherRewrite) []RewriteUsage {
+	usage := make([]RewriteUsage, len(rewrites))
+	for i, r := range rewrites {
+		usage[i].MatcherRewrite = r
+	}
+	if len(rewrites) == 0 {
+		return usage
+	}
+
+	rewriteDashboardQueries(dashboard, func(expr string) string {
+		for i, r := range rewrites {
+			if _, n := promql.RewriteMatchers(expr, []promql.MatcherRewrite{r}); n > 0 {
+				usage[i].Matches += n
+			}
+		}
+		expr, _ = promql.RewriteMatchers(expr, rewrites)
+		return expr
+	})
+
+	templating, _ := dashboard["templating"].(map[string]any)
+	variables, _ := templating["list"].([]any)
+	for _, raw := range variables {
+		variable, ok := raw.(map[string]any)
+		if !ok || variable["type"] == "query" {
+			continue
+		}
+		name, _ := variable["name"].(string)
+		for i, r := range rewrites {
+			if r.Label == name {
+				usage[i].Matches += rewriteVariableValues(variable, r)
+			}
+		}
+	}
+
+	return usage
+}
+
+// rewriteDashboardQueries replaces every target and annotation expression and
+// every query variable query of a dashboard with the result of rewrite
+func rewriteDashboardQueries(dashboard map[string]any, rewrite func(string) string) {
+	rewriteField := func(object map[string]any, field string) {
+		if expr, ok := object[field].(string); ok && expr != "" {
+			object[field] = rewrite(expr)
+		}
+	}
+
+	for _, panel := range flattenPanels(dashboard) {
+		targets, _ := panel["targets"].([]any)
+		for _, raw := range targets {
+			if target, ok := raw.(map[string]any); ok {
+				rewriteField(target, "expr")
+			}
+		}
+	}
+
+	annotations, _ := dashboard["annotations"].(map[string]any)
+	annotationList, _ := annotations["list"].([]any)
+	for _, raw := range annotationList {
+		if annotation, ok := raw.(map[string]any); ok {
+			rewriteField(annotation, "expr")
+		}
+	}
+
+	templating, _ := dashboard["templating"].(map[string]any)
+	variables, _ := templating["list"].([]any)
+	for _, raw := range variables {
+		variable, ok := raw.(map[string]any)
+		if !ok || variable["type"] != "query" {
+			continue
+		}
+		rewriteField(variable, "definition")
+		if query, ok := variable["query"].(map[string]any); ok {
+			rewriteField(query, "query")
+		} else {
+			rewriteField(variable, "query")
+		}
+	}
+}
+
+// rewriteVariableValues replaces a value of a constant, custom or textbox
+// variable, including its current selection and options
+func rewriteVariableValues(variable map[string]any, r promql.MatcherRewrite) int {
+	matches := 0
+	replace := func(object map[string]any, field string) {
+		if value, ok := object[field].(string); ok && value == r.From {
+			object[field] = r.To
+			matches++
+		}
+	}
+
+	if query, ok := variable["query"].(string); ok {
+		values := strings.Split(query, ",")
+		for i, value := range values {
+			if strings.TrimSpace(value) == r.From {
+				values[i] = strings.Replace(value, r.From, r.To, 1)
+				matches++
+			}
+		}
+		variable["query"] = strings.Join(values, ",")
+	}
+	if current, ok := variable["current"].(map[string]any); ok {
+		replace(current, "text")
+		replace(current, "value")
+	}
+	options, _ := variable["options"].([]any)
+	for _, raw := range options {
+		if option, ok := raw.(map[string]any); ok {
+			replace(option, "text")
+			replace(option, "value")
+		}
+	}
+	return matches
+}
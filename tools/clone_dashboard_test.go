@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// stagingDashboard returns a deployed dashboard scoped to env="staging"
+func stagingDashboard() map[string]any {
+	return map[string]any{
+		"id": 7.0, "uid": "checkout-staging", "title": "Checkout (staging)", "version": 12.0,
+		"panels": []any{
+			map[string]any{"id": 1.0, "type": "timeseries", "targets": []any{
+				map[string]any{"refId": "A", "expr": `sum(rate(http_requests_total{env="staging", job="checkout"}[5m]))`},
+			}},
+			map[string]any{"id": 2.0, "type": "row", "collapsed": true, "panels": []any{
+				map[string]any{"id": 3.0, "type": "stat", "targets": []any{
+					map[string]any{"refId": "A", "expr": `up{env=~"staging|qa"}`},
+				}},
+			}},
+		},
+		"templating": map[string]any{"list": []any{
+			map[string]any{"name": "instance", "type": "query", "definition": `label_values(up{env="staging"}, instance)`,
+				"query": map[string]any{"query": `label_values(up{env="staging"}, instance)`}},
+			map[string]any{"name": "env", "type": "constant", "query": "staging",
+				"current": map[string]any{"text": "staging", "value": "staging"}},
+		}},
+	}
+}
+
+func runCloneDashboard(t *testing.T, args map[string]any) (CloneDashboardResponse, grafana.Dashboard, error) {
+	t.Helper()
+
+	var deployed grafana.Dashboard
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: stagingDashboard(), FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = dashboard
+			return &grafana.DashboardResponse{ID: 8, UID: "checkout-prod", Version: 1}, nil
+		},
+	}
+	tool := &CloneDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	args["source_uid"] = "checkout-staging"
+	result, err := tool.CloneDashboardHandler(context.Background(), args)
+	if err != nil {
+		return CloneDashboardResponse{}, deployed, err
+	}
+
+	var response CloneDashboardResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed, nil
+}
+
+func TestCloneDashboardHandler(t *testing.T) {
+	t.Run("rewrites labels across queries and variables", func(t *testing.T) {
+		response, deployed, err := runCloneDashboard(t, map[string]any{
+			"uid":        "checkout-prod",
+			"title":      "Checkout (prod)",
+			"folder_uid": "production",
+			"rewrites": []any{
+				map[string]any{"label": "env", "from": "staging", "to": "prod"},
+				map[string]any{"label": "region", "from": "eu", "to": "us"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		dashboard := deployed.Dashboard
+		if dashboard["uid"] != "checkout-prod" || dashboard["title"] != "Checkout (prod)" || dashboard["id"] != nil || dashboard["version"] != nil {
+			t.Errorf("unexpected identity of the copy: %v", dashboard)
+		}
+		if deployed.FolderUID != "production" || deployed.Overwrite {
+			t.Errorf("expected a new dashboard in the production folder, got %+v", deployed)
+		}
+
+		encoded := mustMarshal(dashboard)
+		if strings.Contains(encoded, "staging") {
+			t.Errorf("expected every staging reference to be rewritten, got %s", encoded)
+		}
+		for _, want := range []string{`env=\"prod\", job=\"checkout\"`, `env=~\"prod|qa\"`, `label_values(up{env=\"prod\"}, instance)`} {
+			if !strings.Contains(encoded, want) {
+				t.Errorf("expected %s in the copy, got %s", want, encoded)
+			}
+		}
+
+		if response.SourceUID != "checkout-staging" || response.Rewrites[0].Matches != 7 || response.Rewrites[1].Matches != 0 {
+			t.Errorf("unexpected rewrite usage %+v", response.Rewrites)
+		}
+		if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "region") {
+			t.Errorf("expected a warning for the unused rule, got %v", response.Warnings)
+		}
+	})
+
+	t.Run("defaults keep the folder and derive the title", func(t *testing.T) {
+		_, deployed, err := runCloneDashboard(t, map[string]any{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if deployed.Dashboard["title"] != "Checkout (staging) (copy)" || deployed.Dashboard["uid"] != nil || deployed.FolderUID != "platform" {
+			t.Errorf("unexpected copy %+v", deployed)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			args    map[string]any
+			wantErr string
+		}{
+			{map[string]any{"uid": "checkout-staging"}, "uid must differ"},
+			{map[string]any{"rewrites": []any{map[string]any{"from": "staging", "to": "prod"}}}, "rewrites[0].label is required"},
+			{map[string]any{"rewrites": []any{map[string]any{"label": "env", "from": "prod", "to": "prod"}}}, "must differ"},
+		} {
+			_, _, err := runCloneDashboard(t, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
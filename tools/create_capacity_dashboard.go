@@ -0,0 +1,275 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// Forecast defaults: the trend of the last 6h extrapolated 4h ahead
+const (
+	defaultForecastRange = "6h"
+	defaultForecastHours = 4
+)
+
+// CreateCapacityDashboardTool struct holds the tool with services
+type CreateCapacityDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewCreateCapacityDashboardTool creates a new create_capacity_dashboard tool
+func NewCreateCapacityDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, promql promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CreateCapacityDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		promql:        promql,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"create_capacity_dashboard",
+		"Builds a capacity forecasting dashboard for the saturating resources found in Prometheus (filesystems, persistent volumes, host and container memory, connection pools): utilization with a predict_linear forecast and days-until-full stat panels, optionally deploying it",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the dashboard (default \"Capacity forecast\")",
+					"type":        "string",
+				},
+				"deploy": map[string]any{
+					"description": "Whether to deploy the dashboard to Grafana (requires GRAFANA_DEPLOY_ENABLED=true)",
+					"type":        "boolean",
+				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Platform/Capacity\"; missing folders are created",
+					"type":        "string",
+				},
+				"forecast_hours": map[string]any{
+					"description": "How many hours ahead to forecast (default 4)",
+					"type":        "integer",
+					"minimum":     1,
+					"maximum":     720,
+				},
+				"forecast_range": map[string]any{
+					"description": "Range of history the linear trend is fitted over (default 6h)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to discover the saturation metrics from",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.CreateCapacityDashboardHandler,
+	)
+}
+
+// CapacityResource is a discovered saturation resource with its forecast queries
+type CapacityResource struct {
+	promql.SaturationResource
+	Queries promql.CapacityQueries `json:"queries"`
+}
+
+// CreateCapacityDashboardResponse represents the generated capacity dashboard
+type CreateCapacityDashboardResponse struct {
+	*deploy.Result
+	Resources []CapacityResource `json:"resources"`
+	Dashboard map[string]any     `json:"dashboard"`
+	Summary   string             `json:"summary"`
+}
+
+// CreateCapacityDashboardHandler handles the create_capacity_dashboard tool execution
+func (t *CreateCapacityDashboardTool) CreateCapacityDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "create_capacity_dashboard")
+	defer span.End()
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+	forecastRange := getStringOrDefault(args, "forecast_range", defaultForecastRange)
+	if d, err := time.ParseDuration(forecastRange); err != nil || d < time.Hour {
+		return "", fmt.Errorf("forecast_range must be a duration of at least 1h, got %q", forecastRange)
+	}
+	forecastHours, ok := toInt(args["forecast_hours"])
+	if !ok || forecastHours <= 0 {
+		forecastHours = defaultForecastHours
+	}
+
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
+	}
+
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+	saturations := promql.DetectSaturationResources(metricNames)
+	if len(saturations) == 0 {
+		return "", fmt.Errorf("no saturation metrics found in %s: capacity dashboards need node_exporter filesystem or memory metrics, kubelet volume stats, cAdvisor memory limits or connection pool metrics", prometheusURL)
+	}
+
+	response := CreateCapacityDashboardResponse{}
+	var panels []map[string]any
+	var groups []string
+	for _, saturation := range saturations {
+		resource := CapacityResource{SaturationResource: saturation, Queries: saturation.Queries(forecastRange, forecastHours*3600)}
+		response.Resources = append(response.Resources, resource)
+		panels = append(panels,
+			daysUntilFullPanel(resource),
+			utilizationForecastPanel(resource, forecastRange, forecastHours))
+		groups = append(groups, resource.Name, resource.Name)
+	}
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", "Capacity forecast"))
+	tags := []any{"capacity"}
+	dashboard := dashboardModel(title, groupPanelsInRows(panels, groups), map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	if variables := environmentVariables(t.environment, nil); len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+	response.Dashboard = dashboard
+	response.Summary = summarizeDashboard(dashboard, defaults.Language)
+
+	t.logger.Info("generated capacity dashboard",
+		zap.String("prometheus_url", prometheusURL),
+		zap.Int("resources", len(saturations)))
+
+	if shouldDeploy {
+		folderPath, _ := args["folder"].(string)
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard,
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    "Capacity dashboard created via grafana-agent",
+			Overwrite:  true,
+			Provenance: templateProvenance(ctx, dashboard, "capacity"),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// resourceLegend labels a resource's series by its grouping labels, e.g.
+// "{{instance}} {{mountpoint}}"
+func resourceLegend(resource CapacityResource) string {
+	parts := make([]string, len(resource.GroupBy))
+	for i, label := range resource.GroupBy {
+		parts[i] = "{{" + label + "}}"
+	}
+	return strings.Join(parts, " ")
+}
+
+// daysUntilFullPanel shows the resources closest to running out
+func daysUntilFullPanel(resource CapacityResource) map[string]any {
+	panel := map[string]any{
+		"title": resource.Name + " days until full",
+		"type":  "stat",
+		"description": fmt.Sprintf("Days until %s is exhausted at its current growth, for the 5 resources closest to it; resources that are not growing are not shown.",
+			strings.ToLower(resource.Name)),
+		"targets": []any{map[string]any{
+			"refId":        "A",
+			"expr":         fmt.Sprintf("bottomk(5, %s)", resource.Queries.DaysUntilFull),
+			"legendFormat": resourceLegend(resource),
+			"instant":      true,
+		}},
+		"options": map[string]any{
+			"reduceOptions": map[string]any{"calcs": []any{"lastNotNull"}, "fields": "", "values": false},
+			"colorMode":     "background",
+			"graphMode":     "none",
+			"textMode":      "value_and_name",
+		},
+		"fieldConfig": map[string]any{"defaults": map[string]any{}, "overrides": []any{}},
+	}
+	defaults := fieldConfigDefaults(panel)
+	defaults["unit"] = "d"
+	defaults["decimals"] = 1
+	defaults["noValue"] = "Not filling up"
+	defaults["thresholds"] = suggestedThresholds(promql.DaysUntilFullThresholds())
+	return panel
+}
+
+// utilizationForecastPanel plots the utilization of a resource next to the
+// utilization forecast at the horizon
+func utilizationForecastPanel(resource CapacityResource, forecastRange string, forecastHours int) map[string]any {
+	legend := resourceLegend(resource)
+	panel := map[string]any{
+		"title": resource.Name + " utilization forecast",
+		"type":  "timeseries",
+		"description": fmt.Sprintf("Used fraction of %s, and the fraction predicted %dh ahead by a linear fit over the last %s (dashed).",
+			strings.ToLower(resource.Name), forecastHours, forecastRange),
+		"targets": []any{
+			map[string]any{"refId": "A", "expr": resource.Queries.Utilization, "legendFormat": legend},
+			map[string]any{"refId": "B", "expr": resource.Queries.Forecast, "legendFormat": fmt.Sprintf("%s in %dh", legend, forecastHours)},
+		},
+		"fieldConfig": extractFieldConfig(map[string]any{}),
+	}
+	defaults := fieldConfigDefaults(panel)
+	defaults["unit"] = "percentunit"
+	defaults["min"] = 0
+	defaults["max"] = 1
+	defaults["thresholds"] = suggestedThresholds(promql.UtilizationThresholds())
+	defaults["custom"].(map[string]any)["thresholdsStyle"] = map[string]any{"mode": "dashed"}
+	panel["fieldConfig"].(map[string]any)["overrides"] = []any{map[string]any{
+		"matcher": map[string]any{"id": "byFrameRefID", "options": "B"},
+		"properties": []any{map[string]any{
+			"id":    "custom.lineStyle",
+			"value": map[string]any{"fill": "dash", "dash": []any{10, 10}},
+		}},
+	}}
+	return panel
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func newCapacityDashboardTool(grafanaSvc grafana.Grafana, metricNames []string, err error) *CreateCapacityDashboardTool {
+	fake := &promqlfakes.FakePromQL{}
+	fake.GetLabelValuesReturns(metricNames, err)
+	return &CreateCapacityDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    grafanaSvc,
+		promql:        fake,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+		environment:   &config.EnvironmentConfig{},
+	}
+}
+
+func TestCreateCapacityDashboardHandler(t *testing.T) {
+	t.Run("forecasts the discovered resources", func(t *testing.T) {
+		tool := newCapacityDashboardTool(nil, []string{
+			"node_filesystem_avail_bytes", "node_filesystem_size_bytes",
+			"kubelet_volume_stats_used_bytes", "kubelet_volume_stats_capacity_bytes",
+		}, nil)
+
+		result, err := tool.CreateCapacityDashboardHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"forecast_hours": 24.0,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateCapacityDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if len(response.Resources) != 2 {
+			t.Fatalf("Expected 2 resources, got %+v", response.Resources)
+		}
+		if !strings.Contains(response.Resources[0].Queries.Forecast, ", 86400)") {
+			t.Errorf("Expected a 24h forecast, got %s", response.Resources[0].Queries.Forecast)
+		}
+		if response.Dashboard["title"] != "Capacity forecast" {
+			t.Errorf("Expected the default title, got %v", response.Dashboard["title"])
+		}
+
+		panels, _ := response.Dashboard["panels"].([]any)
+		if len(panels) != 6 {
+			t.Fatalf("Expected a row with 2 panels per resource, got %d panels", len(panels))
+		}
+		stat := panels[1].(map[string]any)
+		if stat["type"] != "stat" || stat["title"] != "Filesystem days until full" {
+			t.Errorf("Expected the filesystem days until full stat, got %v %v", stat["type"], stat["title"])
+		}
+		target := stat["targets"].([]any)[0].(map[string]any)
+		if expr, _ := target["expr"].(string); !strings.HasPrefix(expr, "bottomk(5, ") || target["legendFormat"] != "{{instance}} {{mountpoint}}" {
+			t.Errorf("Unexpected days until full target %v", target)
+		}
+		forecast := panels[2].(map[string]any)
+		if targets, _ := forecast["targets"].([]any); forecast["type"] != "timeseries" || len(targets) != 2 {
+			t.Errorf("Expected a timeseries with the utilization and forecast, got %v", forecast)
+		}
+		if unit := forecast["fieldConfig"].(map[string]any)["defaults"].(map[string]any)["unit"]; unit != "percentunit" {
+			t.Errorf("Expected percentunit, got %v", unit)
+		}
+		if response.Result != nil {
+			t.Errorf("Expected no deployment, got %+v", response.Result)
+		}
+	})
+
+	t.Run("deploys the dashboard", func(t *testing.T) {
+		var deployed *grafana.Dashboard
+		mock := &mockGrafanaService{
+			createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+				deployed = &dashboard
+				return &grafana.DashboardResponse{ID: 1, UID: "capacity", Version: 1}, nil
+			},
+		}
+		tool := newCapacityDashboardTool(mock, []string{"hikaricp_connections_active", "hikaricp_connections_max"}, nil)
+
+		result, err := tool.CreateCapacityDashboardHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"deploy":         true,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if deployed == nil {
+			t.Fatal("Expected the dashboard to be deployed")
+		}
+		if !strings.Contains(result, `"status": "deployed"`) {
+			t.Errorf("Expected the deploy result in the response, got %s", result)
+		}
+	})
+
+	t.Run("no saturation metrics", func(t *testing.T) {
+		tool := newCapacityDashboardTool(nil, []string{"up", "http_requests_total"}, nil)
+		_, err := tool.CreateCapacityDashboardHandler(context.Background(), map[string]any{"prometheus_url": "http://prometheus.test:9090"})
+		if err == nil || !strings.Contains(err.Error(), "no saturation metrics") {
+			t.Errorf("Expected a no saturation metrics error, got %v", err)
+		}
+	})
+
+	t.Run("metric listing fails", func(t *testing.T) {
+		tool := newCapacityDashboardTool(nil, nil, errors.New("connection refused"))
+		_, err := tool.CreateCapacityDashboardHandler(context.Background(), map[string]any{"prometheus_url": "http://prometheus.test:9090"})
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("Expected the listing error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// defaultCostLabels are the target labels series are attributed to by default
+var defaultCostLabels = []string{"job", "namespace"}
+
+// costTopK is the number of label values the attribution panels show
+const costTopK = 10
+
+// CreateCostDashboardTool struct holds the tool with services
+type CreateCostDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewCreateCostDashboardTool creates a new create_cost_dashboard tool
+func NewCreateCostDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, promql promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CreateCostDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		promql:        promql,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"create_cost_dashboard",
+		"Builds a metrics cost attribution dashboard showing active series, samples per second, cardinality growth and churn per job and namespace from the scrape metrics, with the top metrics and labels of the TSDB stats, to find which teams drive Grafana Cloud metrics billing; optionally deploys it",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the dashboard (default \"Metrics cost attribution\")",
+					"type":        "string",
+				},
+				"deploy": map[string]any{
+					"description": "Whether to deploy the dashboard to Grafana (requires GRAFANA_DEPLOY_ENABLED=true)",
+					"type":        "boolean",
+				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Platform/FinOps\"; missing folders are created",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"group_by": map[string]any{
+					"description": "Target labels to attribute series to, one row each (default job and namespace); labels the targets do not have are skipped",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL whose ingestion to attribute",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.CreateCostDashboardHandler,
+	)
+}
+
+// CostAttribution is a label series are attributed to, with its queries
+type CostAttribution struct {
+	Label   string             `json:"label"`
+	Queries promql.CostQueries `json:"queries"`
+}
+
+// CreateCostDashboardResponse represents the generated cost dashboard
+type CreateCostDashboardResponse struct {
+	*deploy.Result
+	SeriesMetric string            `json:"series_metric"`
+	Attributions []CostAttribution `json:"attributions"`
+	TSDB         *promql.TSDBStats `json:"tsdb,omitempty"`
+	Warnings     []string          `json:"warnings,omitempty"`
+	Dashboard    map[string]any    `json:"dashboard"`
+	Summary      string            `json:"summary"`
+}
+
+// CreateCostDashboardHandler handles the create_cost_dashboard tool execution
+func (t *CreateCostDashboardTool) CreateCostDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "create_cost_dashboard")
+	defer span.End()
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+	labels := schemaStrings(args["group_by"])
+	if len(labels) == 0 {
+		labels = defaultCostLabels
+	}
+
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
+	}
+
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+	seriesMetric := promql.ScrapeSeriesMetric(metricNames)
+	if seriesMetric == "" {
+		return "", fmt.Errorf("no scrape metrics found in %s: cost attribution needs scrape_samples_scraped, which Prometheus and Grafana Alloy record for every target", prometheusURL)
+	}
+
+	response := CreateCostDashboardResponse{SeriesMetric: seriesMetric}
+	for _, label := range labels {
+		values, err := t.promql.GetLabelValues(ctx, prometheusURL, label, []string{seriesMetric})
+		if err != nil {
+			return "", fmt.Errorf("failed to list %s values: %w", label, err)
+		}
+		if len(values) == 0 {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("no target has a %s label; series are not attributed by it", label))
+			continue
+		}
+		response.Attributions = append(response.Attributions, CostAttribution{Label: label, Queries: promql.AttributionQueries(seriesMetric, label)})
+	}
+
+	response.TSDB, err = t.promql.GetTSDBStats(ctx, prometheusURL)
+	if err != nil {
+		t.logger.Debug("tsdb stats unavailable", zap.String("prometheus_url", prometheusURL), zap.Error(err))
+		response.Warnings = append(response.Warnings, fmt.Sprintf("TSDB stats unavailable, the top metrics and labels are not reported: %v", err))
+	}
+
+	panels, groups := costOverviewPanels(promql.AttributionQueries(seriesMetric, ""))
+	for _, attribution := range response.Attributions {
+		attributionPanels := costAttributionPanels(attribution)
+		panels = append(panels, attributionPanels...)
+		for range attributionPanels {
+			groups = append(groups, "By "+attribution.Label)
+		}
+	}
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", "Metrics cost attribution"))
+	tags := []any{"cost", "cardinality"}
+	dashboard := dashboardModel(title, groupPanelsInRows(panels, groups), map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	if variables := environmentVariables(t.environment, nil); len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+	response.Dashboard = dashboard
+	response.Summary = summarizeDashboard(dashboard, defaults.Language)
+
+	t.logger.Info("generated cost dashboard",
+		zap.String("prometheus_url", prometheusURL),
+		zap.Int("attributions", len(response.Attributions)))
+
+	if shouldDeploy {
+		folderPath, _ := args["folder"].(string)
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard,
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    "Cost dashboard created via grafana-agent",
+			Overwrite:  true,
+			Provenance: templateProvenance(ctx, dashboard, "cost"),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// costOverviewPanels are the totals at the top of the dashboard, outside
+// any row
+func costOverviewPanels(total promql.CostQueries) ([]map[string]any, []string) {
+	panels := []map[string]any{
+		starterPanel("Active series", "stat", total.ActiveSeries, "Series sent by all scrape targets, what active series plans bill.", "short"),
+		starterPanel("Samples per second", "stat", total.SamplesPerSecond, "Samples ingested per second from all scrape targets.", "cps"),
+		starterPanel("Series growth (1d)", "stat", total.SeriesGrowth, "Change in active series over the last day.", "short"),
+		starterPanel("New series per hour", "stat", total.SeriesChurn, "Series created in the last hour; high churn inflates active series.", "short"),
+	}
+	return panels, make([]string, len(panels))
+}
+
+// costAttributionPanels break the ingestion down by the values of a label,
+// showing the top values
+func costAttributionPanels(attribution CostAttribution) []map[string]any {
+	label, queries := attribution.Label, attribution.Queries
+	top := func(expr string) string { return fmt.Sprintf("topk(%d, %s)", costTopK, expr) }
+	panels := []map[string]any{
+		starterPanel("Active series by "+label, "timeseries", top(queries.ActiveSeries),
+			fmt.Sprintf("Series sent per %s, top %d.", label, costTopK), "short"),
+		starterPanel("Samples per second by "+label, "timeseries", top(queries.SamplesPerSecond),
+			fmt.Sprintf("Samples ingested per second per %s, top %d.", label, costTopK), "cps"),
+		starterPanel("Series growth by "+label+" (1d)", "bargauge", top(queries.SeriesGrowth),
+			fmt.Sprintf("Change in active series over the last day per %s, for the %d growing the most.", label, costTopK), "short"),
+		starterPanel("New series per hour by "+label, "timeseries", top(queries.SeriesChurn),
+			fmt.Sprintf("Series created in the last hour per %s, top %d.", label, costTopK), "short"),
+	}
+	for _, panel := range panels {
+		target := panel["targets"].([]any)[0].(map[string]any)
+		target["legendFormat"] = "{{" + label + "}}"
+		if panel["type"] == "bargauge" {
+			target["instant"] = true
+			panel["options"] = map[string]any{
+				"displayMode":   "gradient",
+				"orientation":   "horizontal",
+				"reduceOptions": map[string]any{"calcs": []any{"lastNotNull"}, "fields": "", "values": false},
+			}
+		}
+	}
+	return panels
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestCreateCostDashboardHandler(t *testing.T) {
+	newTool := func(fake *promqlfakes.FakePromQL) *CreateCostDashboardTool {
+		return &CreateCostDashboardTool{
+			logger:        zap.NewNop(),
+			promql:        fake,
+			grafanaConfig: &config.GrafanaConfig{},
+			environment:   &config.EnvironmentConfig{},
+		}
+	}
+	scrapeMetrics := func(fake *promqlfakes.FakePromQL) {
+		fake.GetLabelValuesCalls(func(_ context.Context, _ string, label string, _ []string) ([]string, error) {
+			switch label {
+			case "__name__":
+				return []string{"up", "scrape_samples_scraped", "scrape_samples_post_metric_relabeling", "scrape_series_added"}, nil
+			case "job":
+				return []string{"api", "node"}, nil
+			default:
+				return []string{}, nil
+			}
+		})
+	}
+
+	t.Run("attributes series to the target labels", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		scrapeMetrics(fake)
+		fake.GetTSDBStatsReturns(&promql.TSDBStats{HeadSeries: 5120, TopMetrics: []promql.SeriesCount{{Name: "http_request_duration_seconds_bucket", Value: 2400}}}, nil)
+
+		result, err := newTool(fake).CreateCostDashboardHandler(context.Background(), map[string]any{"prometheus_url": "http://prometheus.test:9090"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateCostDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if response.SeriesMetric != "scrape_samples_post_metric_relabeling" {
+			t.Errorf("Expected the post relabeling count, got %s", response.SeriesMetric)
+		}
+		if len(response.Attributions) != 1 || response.Attributions[0].Label != "job" {
+			t.Fatalf("Expected an attribution by job only, got %+v", response.Attributions)
+		}
+		if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "namespace") {
+			t.Errorf("Expected a warning about the missing namespace label, got %v", response.Warnings)
+		}
+		if response.TSDB == nil || response.TSDB.HeadSeries != 5120 {
+			t.Errorf("Expected the tsdb stats, got %+v", response.TSDB)
+		}
+
+		panels, _ := response.Dashboard["panels"].([]any)
+		if len(panels) != 9 {
+			t.Fatalf("Expected 4 overview panels, a row and 4 job panels, got %d panels", len(panels))
+		}
+		if row := panels[4].(map[string]any); row["type"] != "row" || row["title"] != "By job" {
+			t.Errorf("Expected the by job row, got %v", row)
+		}
+		growth := panels[7].(map[string]any)
+		target := growth["targets"].([]any)[0].(map[string]any)
+		if growth["type"] != "bargauge" || target["legendFormat"] != "{{job}}" ||
+			!strings.HasPrefix(target["expr"].(string), "topk(10, sum by (job) (scrape_samples_post_metric_relabeling) - ") {
+			t.Errorf("Unexpected growth panel %v", growth)
+		}
+	})
+
+	t.Run("tsdb stats unavailable", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		scrapeMetrics(fake)
+		fake.GetTSDBStatsReturns(nil, errors.New("tsdb request failed with status 404"))
+
+		result, err := newTool(fake).CreateCostDashboardHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://mimir.test/prometheus",
+			"group_by":       []any{"job"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateCostDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.TSDB != nil || len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "TSDB stats unavailable") {
+			t.Errorf("Expected a TSDB stats warning, got %+v", response.Warnings)
+		}
+	})
+
+	t.Run("no scrape metrics", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"http_requests_total"}, nil)
+
+		_, err := newTool(fake).CreateCostDashboardHandler(context.Background(), map[string]any{"prometheus_url": "http://prometheus.test:9090"})
+		if err == nil || !strings.Contains(err.Error(), "no scrape metrics") {
+			t.Errorf("Expected a no scrape metrics error, got %v", err)
+		}
+	})
+}
@@ -4,31 +4,45 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"slices"
+	"strings"
 
 	zap "go.uber.org/zap"
 
 	server "github.com/inference-gateway/adk/server"
 
 	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
 	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	i18n "github.com/inference-gateway/grafana-agent/internal/i18n"
+	naming "github.com/inference-gateway/grafana-agent/internal/naming"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	schema "github.com/inference-gateway/grafana-agent/internal/schema"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
 )
 
 // CreateDashboardTool struct holds the tool with services
 type CreateDashboardTool struct {
-	logger     *zap.Logger
-	grafanaSvc grafana.Grafana
-	config     *config.GrafanaConfig
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	config        *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
 }
 
 // NewCreateDashboardTool creates a new create_dashboard tool
-func NewCreateDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+func NewCreateDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
 	tool := &CreateDashboardTool{
-		logger:     logger,
-		grafanaSvc: grafanaSvc,
-		config:     grafanaConfig,
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		config:        grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
 	}
-	return server.NewBasicTool(
+	return newValidatedTool(
 		"create_dashboard",
 		"Creates a Grafana dashboard with specified panels, queries, and configurations",
 		map[string]any{
@@ -42,6 +56,10 @@ func NewCreateDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, graf
 					"description": "Description of what the dashboard monitors or displays",
 					"type":        "string",
 				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Platform/Payments\"; missing folders are created",
+					"type":        "string",
+				},
 				"grafana_url": map[string]any{
 					"description": "Grafana server URL (overrides default configuration if provided)",
 					"type":        "string",
@@ -50,30 +68,55 @@ func NewCreateDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, graf
 					"description": "Whether to deploy the dashboard to Grafana (requires grafana_url and GRAFANA_DEPLOY_ENABLED=true)",
 					"type":        "boolean",
 				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
 				"panels": map[string]any{
 					"description": "Array of panel configurations (title, type, queries, etc.)",
-					"items":       map[string]any{"type": "object"},
-					"type":        "array",
+					"items": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"transform": transformSchema},
+					},
+					"type": "array",
 				},
 				"refresh_interval": map[string]any{
 					"description": "Auto-refresh interval (e.g., \"5s\", \"1m\", \"5m\")",
 					"type":        "string",
 				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); resolves the URL and a stack token (mutually exclusive with grafana_url)",
+					"type":        "string",
+				},
 				"tags": map[string]any{
 					"description": "Tags to categorize the dashboard",
 					"items":       map[string]any{"type": "string"},
 					"type":        "array",
 				},
+				"timezone": map[string]any{
+					"description": "Dashboard timezone (\"browser\", \"utc\" or an IANA name); defaults to GRAFANA_DEFAULT_TIMEZONE",
+					"type":        "string",
+				},
 				"time_range": map[string]any{
 					"description": "Default time range for the dashboard (from, to)",
 					"properties":  map[string]any{"from": map[string]any{"type": "string"}, "to": map[string]any{"type": "string"}},
 					"type":        "object",
 				},
+				"trends_variant": map[string]any{
+					"description": "Also emit a trends variant over 30 days, with rate() turned into daily increase() and gauges into daily avg_over_time(), linked to and from the real-time dashboard (default false)",
+					"type":        "boolean",
+				},
 				"variables": map[string]any{
 					"description": "Dashboard template variables for dynamic queries",
 					"items":       map[string]any{"type": "object"},
 					"type":        "array",
 				},
+				"week_start": map[string]any{
+					"description": "First day of the week in time pickers; defaults to GRAFANA_DEFAULT_WEEK_START",
+					"enum":        []string{"monday", "saturday", "sunday"},
+					"type":        "string",
+				},
 			},
 			"required": []string{"dashboard_title", "panels"},
 		},
@@ -86,124 +129,108 @@ func (t *CreateDashboardTool) CreateDashboardHandler(ctx context.Context, args m
 	span := startToolSpan(ctx, "create_dashboard")
 	defer span.End()
 
-	dashboardTitle, ok := args["dashboard_title"].(string)
-	if !ok || dashboardTitle == "" {
+	title, ok := args["dashboard_title"].(string)
+	if !ok || title == "" {
 		return "", fmt.Errorf("dashboard_title is required and must be a string")
 	}
+	title = dashboardTitle(t.config, title)
 
 	panels, ok := args["panels"].([]any)
 	if !ok || len(panels) == 0 {
 		return "", fmt.Errorf("panels are required")
 	}
-
-	deploy, deployRequested := args["deploy"].(bool)
-	if deployRequested && deploy {
-		if t.config != nil && !t.config.DeployEnabled {
-			log.Printf("WARNING: Grafana deployment attempted but GRAFANA_DEPLOY_ENABLED=false")
-			return "", fmt.Errorf("grafana deployment is disabled - set GRAFANA_DEPLOY_ENABLED=true to enable dashboard deployments")
-		}
-
-		var grafanaURL string
-		if urlParam, ok := args["grafana_url"].(string); ok && urlParam != "" {
-			grafanaURL = urlParam
-		} else if t.config != nil && t.config.URL != "" {
-			grafanaURL = t.config.URL
-		}
-
-		if grafanaURL == "" {
-			return "", fmt.Errorf("deployment requested but no grafana_url provided")
-		}
+	if err := expandPanelTransforms(panels); err != nil {
+		return "", err
 	}
 
-	var grafanaURL string
-	if urlParam, ok := args["grafana_url"].(string); ok && urlParam != "" {
-		grafanaURL = urlParam
-	} else if t.config != nil && t.config.URL != "" {
-		grafanaURL = t.config.URL
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	folderPath, _ := args["folder"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.config, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
 	}
 
-	if grafanaURL != "" {
-		log.Printf("INFO: Using Grafana URL: %s", grafanaURL)
-	}
-	if t.config != nil && t.config.APIKey != "" {
-		log.Printf("INFO: Grafana API key configured")
-	}
+	defaults := dashboardDefaults(t.config)
 
 	dashboard := map[string]any{
-		"dashboard": map[string]any{
-			"title":                dashboardTitle,
-			"tags":                 extractTags(args),
-			"timezone":             "browser",
-			"panels":               processPanels(panels),
-			"time":                 extractTimeRange(args),
-			"refresh":              extractRefreshInterval(args),
-			"schemaVersion":        36,
-			"version":              0,
-			"editable":             true,
-			"fiscalYearStartMonth": 0,
-			"graphTooltip":         0,
-			"links":                []any{},
-			"liveNow":              false,
-		},
+		"dashboard": dashboardModel(title, panels, args, defaults),
 		"folderUid": "",
 		"message":   "",
 		"overwrite": false,
 	}
+	if err := enforceTags(t.config, dashboard["dashboard"].(map[string]any)); err != nil {
+		return "", err
+	}
 
 	if description, ok := args["description"].(string); ok && description != "" {
 		dashboard["dashboard"].(map[string]any)["description"] = description
 	}
 
-	if variables, ok := args["variables"].([]any); ok && len(variables) > 0 {
+	variables, _ := args["variables"].([]any)
+	templateVariables := processVariables(variables)
+	templateVariables = append(templateVariables, environmentVariables(t.environment, templateVariables)...)
+	if len(templateVariables) > 0 {
 		dashboard["dashboard"].(map[string]any)["templating"] = map[string]any{
-			"list": processVariables(variables),
+			"list": templateVariables,
 		}
 	}
+	scopePanelsToEnvironment(dashboard["dashboard"].(map[string]any), environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard["dashboard"].(map[string]any), t.environment); err != nil {
+		return "", err
+	}
 
-	if deployRequested && deploy {
-		var grafanaURL string
-		var apiKey string
-
-		if urlParam, ok := args["grafana_url"].(string); ok && urlParam != "" {
-			grafanaURL = urlParam
-		} else if t.config != nil && t.config.URL != "" {
-			grafanaURL = t.config.URL
-		}
-
-		if t.config != nil && t.config.APIKey != "" {
-			apiKey = t.config.APIKey
-		}
-
-		if apiKey == "" {
-			return "", fmt.Errorf("deployment requested but no API key configured - set GRAFANA_API_KEY")
+	var trends map[string]any
+	if trendsVariant, _ := args["trends_variant"].(bool); trendsVariant {
+		realtime := dashboard["dashboard"].(map[string]any)
+		var err error
+		if trends, err = trendsDashboard(realtime, defaults.Language); err != nil {
+			return "", err
 		}
+		trends["title"] = dashboardTitle(t.config, trends["title"].(string))
+		dashboardVariants(realtime, trends, defaults.Language)
+	}
 
-		grafanaDashboard := grafana.Dashboard{
-			Dashboard: dashboard["dashboard"].(map[string]any),
-			FolderUID: "",
-			Message:   "Dashboard created via grafana-agent",
-			Overwrite: true,
+	if shouldDeploy {
+		type deployment struct {
+			*deploy.Result
+			Summary       string         `json:"summary"`
+			DashboardJSON map[string]any `json:"dashboard_json"`
+			Trends        *deployment    `json:"trends,omitempty"`
 		}
 
-		resp, err := t.grafanaSvc.CreateDashboard(ctx, grafanaDashboard, grafanaURL, apiKey)
+		result, err := deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard["dashboard"].(map[string]any),
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    "Dashboard created via grafana-agent",
+			Overwrite:  true,
+			Provenance: dashboardProvenance(ctx, dashboard["dashboard"].(map[string]any)),
+		})
 		if err != nil {
-			return "", fmt.Errorf("failed to deploy dashboard to Grafana: %w", err)
+			return "", err
 		}
 
-		t.logger.Info("Dashboard deployed successfully",
-			zap.String("grafana_url", grafanaURL),
-			zap.String("dashboard_uid", resp.UID),
-			zap.Int("dashboard_id", resp.ID))
-
-		deploymentInfo := map[string]any{
-			"status":      "deployed",
-			"grafana_url": grafanaURL,
-			"dashboard": map[string]any{
-				"id":  resp.ID,
-				"uid": resp.UID,
-				"url": resp.URL,
-			},
-			"dashboard_json": dashboard,
+		deploymentInfo := deployment{Result: result, Summary: summarizeDashboard(dashboard["dashboard"].(map[string]any), defaults.Language), DashboardJSON: dashboard}
+
+		if trends != nil {
+			trendsResult, err := deployer.Deploy(ctx, deploy.Request{
+				Dashboard:  trends,
+				GrafanaURL: grafanaURL,
+				Stack:      stack,
+				FolderPath: folderPath,
+				Message:    "Trends dashboard created via grafana-agent",
+				Overwrite:  true,
+				Provenance: templateProvenance(ctx, trends, "trends"),
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to deploy the trends dashboard: %w", err)
+			}
+			deploymentInfo.Trends = &deployment{Result: trendsResult, Summary: summarizeDashboard(trends, defaults.Language), DashboardJSON: map[string]any{"dashboard": trends}}
 		}
 
 		jsonBytes, err := json.MarshalIndent(deploymentInfo, "", "  ")
@@ -214,6 +241,11 @@ func (t *CreateDashboardTool) CreateDashboardHandler(ctx context.Context, args m
 		return string(jsonBytes), nil
 	}
 
+	dashboard["summary"] = summarizeDashboard(dashboard["dashboard"].(map[string]any), defaults.Language)
+	if trends != nil {
+		dashboard["trends"] = map[string]any{"dashboard": trends, "summary": summarizeDashboard(trends, defaults.Language)}
+	}
+
 	jsonBytes, err := json.MarshalIndent(dashboard, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal dashboard JSON: %w", err)
@@ -222,54 +254,194 @@ func (t *CreateDashboardTool) CreateDashboardHandler(ctx context.Context, args m
 	return string(jsonBytes), nil
 }
 
-func extractTags(args map[string]any) []string {
+// dashboardModel builds the dashboard JSON model from panel definitions,
+// with the preferences given in args falling back to defaults
+func dashboardModel(title string, panels []any, args map[string]any, defaults config.GrafanaDefaultsConfig) map[string]any {
+	return map[string]any{
+		"title":                title,
+		"tags":                 extractTags(args, defaults),
+		"timezone":             getStringOrDefault(args, "timezone", defaults.Timezone),
+		"weekStart":            getStringOrDefault(args, "week_start", defaults.WeekStart),
+		"panels":               processPanels(panels, defaults.Language),
+		"time":                 extractTimeRange(args, defaults),
+		"refresh":              extractRefreshInterval(args, defaults),
+		"schemaVersion":        schema.GeneratedVersion,
+		"version":              0,
+		"editable":             true,
+		"fiscalYearStartMonth": 0,
+		"graphTooltip":         0,
+		"links":                []any{},
+		"liveNow":              false,
+	}
+}
+
+// builtinDashboardDefaults are used for any preference left unset in
+// GRAFANA_DEFAULT_*
+var builtinDashboardDefaults = config.GrafanaDefaultsConfig{
+	Refresh:  "5s",
+	TimeFrom: "now-6h",
+	TimeTo:   "now",
+	Timezone: "browser",
+	Language: i18n.DefaultLanguage,
+}
+
+// dashboardDefaults resolves the organization-level preferences every
+// generated dashboard inherits unless the tool arguments override them
+func dashboardDefaults(cfg *config.GrafanaConfig) config.GrafanaDefaultsConfig {
+	defaults := builtinDashboardDefaults
+	if cfg == nil {
+		return defaults
+	}
+
+	configured := cfg.Defaults
+	if configured.Refresh != "" {
+		defaults.Refresh = configured.Refresh
+	}
+	if configured.TimeFrom != "" {
+		defaults.TimeFrom = configured.TimeFrom
+	}
+	if configured.TimeTo != "" {
+		defaults.TimeTo = configured.TimeTo
+	}
+	if configured.Timezone != "" {
+		defaults.Timezone = configured.Timezone
+	}
+	if configured.Language != "" {
+		defaults.Language = configured.Language
+	}
+	defaults.WeekStart = configured.WeekStart
+	defaults.Tags = configured.Tags
+	return defaults
+}
+
+// dashboardTitle makes a generated title follow the naming convention of
+// GRAFANA_NAMING_*; deployed titles that do not are rejected
+func dashboardTitle(cfg *config.GrafanaConfig, title string) string {
+	if cfg == nil {
+		return title
+	}
+	return naming.NewRules(&cfg.Naming).Apply(title)
+}
+
+// enforceTags makes the tags a call asked for follow the tag taxonomy of
+// GRAFANA_TAGS_*: they are normalized and undeclared ones dropped, or
+// rejected with GRAFANA_TAGS_MODE=reject
+func enforceTags(cfg *config.GrafanaConfig, dashboard map[string]any) error {
+	if cfg == nil {
+		return nil
+	}
+	tags, _, err := taxonomy.New(&cfg.Tags).Enforce(schemaStrings(dashboard["tags"]))
+	if err != nil {
+		return err
+	}
+	dashboard["tags"] = tags
+	return nil
+}
+
+// correctTags makes the tags a builder's dashboard inherits, such as
+// GRAFANA_DEFAULT_TAGS, follow the tag taxonomy; undeclared ones are dropped
+// whatever GRAFANA_TAGS_MODE is. The builder's own tags are kept as they
+// are, since the dashboard category and the service links rely on them.
+func correctTags(cfg *config.GrafanaConfig, dashboard map[string]any, own []any) {
+	if cfg == nil {
+		return
+	}
+	builderTags := schemaStrings(own)
+	var tags, inherited []string
+	for _, tag := range schemaStrings(dashboard["tags"]) {
+		if slices.Contains(builderTags, tag) {
+			tags = append(tags, tag)
+		} else {
+			inherited = append(inherited, tag)
+		}
+	}
+	corrected, _ := taxonomy.New(&cfg.Tags).Correct(inherited)
+	for _, tag := range corrected {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	dashboard["tags"] = tags
+}
+
+// serviceTagPrefix is "service:" when the tag taxonomy declares service
+// tags, "" when dashboards are tagged with the bare job
+func serviceTagPrefix(cfg *config.GrafanaConfig) string {
+	if cfg != nil && taxonomy.New(&cfg.Tags).Declares(taxonomy.KeyService) {
+		return taxonomy.KeyService + ":"
+	}
+	return ""
+}
+
+// serviceTag is the tag of the dashboards built for a service (job), which
+// the service and fleet overviews link to
+func serviceTag(cfg *config.GrafanaConfig, job string) string {
+	if serviceTagPrefix(cfg) != "" {
+		return taxonomy.Tag(taxonomy.KeyService, job)
+	}
+	return job
+}
+
+// extractTags extracts the requested tags followed by any default tags not
+// already present
+func extractTags(args map[string]any, defaults config.GrafanaDefaultsConfig) []string {
 	tags := []string{}
+	seen := map[string]bool{}
+	add := func(tag string) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			return
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
 	if tagsRaw, ok := args["tags"].([]any); ok {
 		for _, tag := range tagsRaw {
 			if tagStr, ok := tag.(string); ok {
-				tags = append(tags, tagStr)
+				add(tagStr)
 			}
 		}
 	}
+	for _, tag := range defaults.Tags {
+		add(tag)
+	}
 	return tags
 }
 
 // extractTimeRange extracts time range or returns defaults
-func extractTimeRange(args map[string]any) map[string]string {
-	defaultTimeRange := map[string]string{
-		"from": "now-6h",
-		"to":   "now",
+func extractTimeRange(args map[string]any, defaults config.GrafanaDefaultsConfig) map[string]string {
+	result := map[string]string{
+		"from": defaults.TimeFrom,
+		"to":   defaults.TimeTo,
 	}
 
 	if timeRange, ok := args["time_range"].(map[string]any); ok {
-		result := make(map[string]string)
 		if from, ok := timeRange["from"].(string); ok && from != "" {
 			result["from"] = from
-		} else {
-			result["from"] = defaultTimeRange["from"]
 		}
 		if to, ok := timeRange["to"].(string); ok && to != "" {
 			result["to"] = to
-		} else {
-			result["to"] = defaultTimeRange["to"]
 		}
-		return result
 	}
 
-	return defaultTimeRange
+	return result
 }
 
 // extractRefreshInterval extracts refresh interval or returns default
-func extractRefreshInterval(args map[string]any) string {
+func extractRefreshInterval(args map[string]any, defaults config.GrafanaDefaultsConfig) string {
 	if refresh, ok := args["refresh_interval"].(string); ok && refresh != "" {
 		return refresh
 	}
-	return "5s"
+	return defaults.Refresh
 }
 
-// processPanels converts panel definitions to Grafana panel format
-func processPanels(panels []any) []any {
+// processPanels converts panel definitions to Grafana panel format, with
+// default titles and descriptions in language
+func processPanels(panels []any, language string) []any {
 	result := []any{}
+	printer := i18n.NewPrinter(language)
+	describer := promql.NewDescriber(language)
 
 	for i, panelRaw := range panels {
 		panelMap, ok := panelRaw.(map[string]any)
@@ -277,17 +449,52 @@ func processPanels(panels []any) []any {
 			continue
 		}
 
+		// Rows only title the panels below them
+		if panelMap["type"] == "row" {
+			collapsed, _ := panelMap["collapsed"].(bool)
+			result = append(result, map[string]any{
+				"id":        i + 1,
+				"type":      "row",
+				"title":     getStringOrDefault(panelMap, "title", ""),
+				"gridPos":   extractGridPos(panelMap, i),
+				"collapsed": collapsed,
+				"panels":    []any{},
+			})
+			continue
+		}
+
 		panel := map[string]any{
 			"id":          i + 1,
 			"type":        getStringOrDefault(panelMap, "type", "timeseries"),
-			"title":       getStringOrDefault(panelMap, "title", fmt.Sprintf("Panel %d", i+1)),
+			"title":       getStringOrDefault(panelMap, "title", printer.Sprintf("Panel %d", i+1)),
 			"gridPos":     extractGridPos(panelMap, i),
 			"targets":     extractTargets(panelMap),
 			"options":     extractOptions(panelMap),
 			"fieldConfig": extractFieldConfig(panelMap),
 		}
 
-		if description, ok := panelMap["description"].(string); ok && description != "" {
+		if transformations, ok := panelMap["transformations"].([]any); ok && len(transformations) > 0 {
+			panel["transformations"] = transformations
+		}
+		// The minimum interval keeps $__interval and $__rate_interval at or
+		// above the resolution of downsampled data
+		if interval, ok := panelMap["interval"].(string); ok && interval != "" {
+			panel["interval"] = interval
+		}
+		switch panel["type"] {
+		case "nodeGraph":
+			shapeNodeGraph(panel)
+		case "state-timeline", "status-history":
+			shapeStatePanel(panel, panelMap)
+		case "gauge":
+			shapeGaugePanel(panel)
+		}
+
+		description, _ := panelMap["description"].(string)
+		if description == "" {
+			description = describePanelQueries(panel, describer)
+		}
+		if description != "" {
 			panel["description"] = description
 		}
 
@@ -297,6 +504,140 @@ func processPanels(panels []any) []any {
 	return result
 }
 
+// Node graph query refIds: the edges query returns one row per edge with
+// id, source and target labels, the nodes query one row per node with id
+// and title labels (see promql.NodeGraphEdges and promql.NodeGraphNodes)
+const (
+	nodeGraphEdges = "edges"
+	nodeGraphNodes = "nodes"
+)
+
+// shapeNodeGraph turns the edges and nodes queries of a nodeGraph panel
+// into the data frames the panel reads: instant tables whose value becomes
+// the mainstat field, without the Time field
+func shapeNodeGraph(panel map[string]any) {
+	for _, raw := range panel["targets"].([]any) {
+		target, ok := raw.(map[string]any)
+		if !ok || (target["refId"] != nodeGraphEdges && target["refId"] != nodeGraphNodes) {
+			continue
+		}
+		target["instant"], target["range"], target["format"] = true, false, "table"
+	}
+	if _, ok := panel["transformations"]; !ok {
+		panel["transformations"] = []any{
+			map[string]any{"id": "renameByRegex", "options": map[string]any{"regex": "Value.*", "renamePattern": "mainstat"}},
+			organizeTransformation([]string{"Time"}, nil, nil),
+		}
+	}
+}
+
+// shapeStatePanel gives a state timeline or status history panel the field
+// defaults and options of its type where its definition leaves them out,
+// and maps the values of binary and enum queries to their states
+func shapeStatePanel(panel, definition map[string]any) {
+	if _, ok := definition["fieldConfig"].(map[string]any); !ok {
+		panel["fieldConfig"] = map[string]any{"defaults": map[string]any{}, "overrides": []any{}}
+	}
+	defaults := fieldConfigDefaults(panel)
+	if _, ok := defaults["color"]; !ok {
+		defaults["color"] = map[string]any{"mode": "thresholds"}
+	}
+	if _, ok := defaults["custom"]; !ok {
+		defaults["custom"] = map[string]any{"fillOpacity": 70, "lineWidth": 0}
+	}
+	if _, ok := definition["options"].(map[string]any); !ok {
+		options := map[string]any{
+			"showValue": "auto",
+			"rowHeight": 0.9,
+			"legend":    map[string]any{"displayMode": "list", "placement": "bottom", "showLegend": true},
+		}
+		if panel["type"] == "state-timeline" {
+			options["mergeValues"] = true
+			options["alignValue"] = "left"
+		}
+		panel["options"] = options
+	}
+
+	if _, ok := defaults["mappings"]; ok {
+		return
+	}
+	for _, raw := range panel["targets"].([]any) {
+		target, _ := raw.(map[string]any)
+		expr, _ := target["expr"].(string)
+		if states := promql.StateMappings(expr); len(states) > 0 {
+			defaults["mappings"] = promql.ValueMappings(states)
+			return
+		}
+	}
+}
+
+// gaugeCapacity is the refId of a gauge query returning the capacity the
+// gauge's value uses a part of, which sets the gauge's max
+const gaugeCapacity = "max"
+
+// shapeGaugePanel bounds a gauge without its own min and max: 0–100 for
+// percent and 0–1 for percentunit, or from 0 to the result of its max query
+// (see promql.CapacityQuery), which a config from query results
+// transformation turns into the max of the other queries
+func shapeGaugePanel(panel map[string]any) {
+	defaults := fieldConfigDefaults(panel)
+	_, hasMin := defaults["min"]
+	_, hasMax := defaults["max"]
+	if hasMin || hasMax {
+		return
+	}
+
+	unit, _ := defaults["unit"].(string)
+	if min, max := promql.GaugeRange(unit); min != nil {
+		defaults["min"], defaults["max"] = *min, *max
+		return
+	}
+	for _, raw := range panel["targets"].([]any) {
+		target, ok := raw.(map[string]any)
+		if !ok || target["refId"] != gaugeCapacity {
+			continue
+		}
+		defaults["min"] = 0
+		target["legendFormat"] = "Max"
+		if _, ok := panel["transformations"]; !ok {
+			panel["transformations"] = []any{map[string]any{
+				"id": "configFromData",
+				"options": map[string]any{
+					"configRefId": gaugeCapacity,
+					"mappings":    []any{map[string]any{"fieldName": "Max", "handlerKey": "max", "reducerId": "lastNotNull"}},
+				},
+			}}
+		}
+		return
+	}
+}
+
+// describePanelQueries synthesizes the description of a panel without one
+// from what its queries compute and its unit
+func describePanelQueries(panel map[string]any, describer promql.Describer) string {
+	targets, _ := panel["targets"].([]any)
+	var explanations []string
+	firstExpr := ""
+	for _, raw := range targets {
+		target, _ := raw.(map[string]any)
+		expr, _ := target["expr"].(string)
+		if explanation := describer.ExplainQuery(expr); explanation != "" {
+			explanations = append(explanations, explanation)
+			if firstExpr == "" {
+				firstExpr = expr
+			}
+		}
+	}
+	if len(explanations) == 0 {
+		return ""
+	}
+
+	fieldConfig, _ := panel["fieldConfig"].(map[string]any)
+	defaults, _ := fieldConfig["defaults"].(map[string]any)
+	unit, _ := defaults["unit"].(string)
+	return describer.PanelDescription(strings.Join(explanations, ". "), "", describer.DescribeUnit(unit, firstExpr))
+}
+
 // extractGridPos extracts grid position or calculates default
 func extractGridPos(panel map[string]any, index int) map[string]any {
 	if gridPos, ok := panel["gridPos"].(map[string]any); ok {
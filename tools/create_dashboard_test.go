@@ -3,17 +3,39 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	zap "go.uber.org/zap"
 
 	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
 	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
 )
 
 // mockGrafanaService is a mock implementation of the Grafana interface for testing
 type mockGrafanaService struct {
 	createDashboardFunc func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error)
+	getDashboardFunc    func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error)
+	folders             []grafana.Folder
+	correlations        []grafana.Correlation
+	publicDashboards    map[string]*grafana.PublicDashboard
+	teams               []grafana.Team
+	homeDashboards      map[int]string
+	datasources         []grafana.Datasource
+	datasourceHealth    map[string]*grafana.DatasourceHealth
+	queryDatasourceFunc func(ctx context.Context, datasource grafana.Datasource, query map[string]any) (int, error)
+	dashboards          []grafana.DashboardHit
+	alertRules          []grafana.AlertRule
+	silences            []grafana.Silence
+	expiredSilences     []string
+	contactPoints       []grafana.ContactPoint
+	testedAlert         grafana.TestAlert
+	orgs                []grafana.Org
+	deleted             []string
+	grafanaVersion      string
 }
 
 func (m *mockGrafanaService) CreateDashboard(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
@@ -27,18 +49,209 @@ func (m *mockGrafanaService) CreateDashboard(ctx context.Context, dashboard graf
 	}, nil
 }
 
-func (m *mockGrafanaService) UpdateDashboard(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+func (m *mockGrafanaService) UpdateDashboard(ctx context.Context, dashboard grafana.Dashboard, expectedVersion int, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+	if expectedVersion > 0 {
+		dashboard.Dashboard["version"] = expectedVersion
+	}
 	return m.CreateDashboard(ctx, dashboard, grafanaURL, apiKey)
 }
 
 func (m *mockGrafanaService) GetDashboard(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+	if m.getDashboardFunc != nil {
+		return m.getDashboardFunc(ctx, uid, grafanaURL, apiKey)
+	}
 	return nil, nil
 }
 
 func (m *mockGrafanaService) DeleteDashboard(ctx context.Context, uid, grafanaURL, apiKey string) error {
+	m.deleted = append(m.deleted, uid)
+	return nil
+}
+
+func (m *mockGrafanaService) SearchDashboards(ctx context.Context, query, grafanaURL, apiKey string) ([]grafana.DashboardHit, error) {
+	var hits []grafana.DashboardHit
+	for _, hit := range m.dashboards {
+		if strings.Contains(strings.ToLower(hit.Title), strings.ToLower(query)) {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, nil
+}
+
+func (m *mockGrafanaService) ListAlertRules(ctx context.Context, grafanaURL, apiKey string) ([]grafana.AlertRule, error) {
+	return m.alertRules, nil
+}
+
+func (m *mockGrafanaService) CreateSilence(ctx context.Context, silence grafana.Silence, grafanaURL, apiKey string) (string, error) {
+	m.silences = append(m.silences, silence)
+	return fmt.Sprintf("silence-%d", len(m.silences)), nil
+}
+
+func (m *mockGrafanaService) DeleteSilence(ctx context.Context, id, grafanaURL, apiKey string) error {
+	m.expiredSilences = append(m.expiredSilences, id)
+	return nil
+}
+
+func (m *mockGrafanaService) ListContactPoints(ctx context.Context, name, grafanaURL, apiKey string) ([]grafana.ContactPoint, error) {
+	return m.contactPoints, nil
+}
+
+func (m *mockGrafanaService) GetCurrentUser(ctx context.Context, grafanaURL, apiKey string) (*grafana.CurrentUser, error) {
+	return &grafana.CurrentUser{ID: 1, Login: "admin", OrgID: 1, Role: grafana.RoleAdmin}, nil
+}
+
+func (m *mockGrafanaService) ListOrgs(ctx context.Context, grafanaURL, apiKey string) ([]grafana.Org, error) {
+	return m.orgs, nil
+}
+
+func (m *mockGrafanaService) TestContactPoint(ctx context.Context, name string, integrations []grafana.ContactPoint, alert grafana.TestAlert, grafanaURL, apiKey string) (*grafana.ContactPointTest, error) {
+	m.testedAlert = alert
+	test := &grafana.ContactPointTest{NotifiedAt: time.Date(2026, 1, 10, 22, 0, 0, 0, time.UTC)}
+	for _, integration := range integrations {
+		result := grafana.IntegrationTest{UID: integration.UID, Name: name, Status: "ok"}
+		if integration.Settings["url"] == "invalid" {
+			result.Status, result.Error = "failed", "invalid webhook URL"
+		}
+		test.Integrations = append(test.Integrations, result)
+	}
+	return test, nil
+}
+
+func (m *mockGrafanaService) GetDashboardVersion(ctx context.Context, uid string, version int, grafanaURL, apiKey string) (*grafana.DashboardVersion, error) {
+	return nil, fmt.Errorf("dashboard %s version %d not found", uid, version)
+}
+
+func (m *mockGrafanaService) ListFolders(ctx context.Context, parentUID, grafanaURL, apiKey string) ([]grafana.Folder, error) {
+	var folders []grafana.Folder
+	for _, folder := range m.folders {
+		if folder.ParentUID == parentUID {
+			folders = append(folders, folder)
+		}
+	}
+	return folders, nil
+}
+
+func (m *mockGrafanaService) GetFolder(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Folder, error) {
+	for _, folder := range m.folders {
+		if folder.UID == uid {
+			return &folder, nil
+		}
+	}
+	return nil, fmt.Errorf("grafana returned status 404")
+}
+
+func (m *mockGrafanaService) CreateFolder(ctx context.Context, title, parentUID, grafanaURL, apiKey string) (*grafana.Folder, error) {
+	folder := grafana.Folder{UID: fmt.Sprintf("folder-%d", len(m.folders)+1), Title: title, ParentUID: parentUID}
+	m.folders = append(m.folders, folder)
+	return &folder, nil
+}
+
+func (m *mockGrafanaService) ListCorrelations(ctx context.Context, sourceUID, grafanaURL, apiKey string) ([]grafana.Correlation, error) {
+	var correlations []grafana.Correlation
+	for _, correlation := range m.correlations {
+		if correlation.SourceUID == sourceUID {
+			correlations = append(correlations, correlation)
+		}
+	}
+	return correlations, nil
+}
+
+func (m *mockGrafanaService) CreateCorrelation(ctx context.Context, correlation grafana.Correlation, grafanaURL, apiKey string) (*grafana.Correlation, error) {
+	correlation.UID = fmt.Sprintf("correlation-%d", len(m.correlations)+1)
+	m.correlations = append(m.correlations, correlation)
+	return &correlation, nil
+}
+
+func (m *mockGrafanaService) DeleteCorrelation(ctx context.Context, sourceUID, correlationUID, grafanaURL, apiKey string) error {
+	for i, correlation := range m.correlations {
+		if correlation.SourceUID == sourceUID && correlation.UID == correlationUID {
+			m.correlations = append(m.correlations[:i], m.correlations[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("grafana returned status 404")
+}
+
+func (m *mockGrafanaService) GetPublicDashboard(ctx context.Context, dashboardUID, grafanaURL, apiKey string) (*grafana.PublicDashboard, error) {
+	return m.publicDashboards[dashboardUID], nil
+}
+
+func (m *mockGrafanaService) ListPublicDashboards(ctx context.Context, grafanaURL, apiKey string) ([]grafana.PublicDashboard, error) {
+	var publicDashboards []grafana.PublicDashboard
+	for _, publicDashboard := range m.publicDashboards {
+		publicDashboards = append(publicDashboards, *publicDashboard)
+	}
+	return publicDashboards, nil
+}
+
+func (m *mockGrafanaService) CreatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard grafana.PublicDashboard, grafanaURL, apiKey string) (*grafana.PublicDashboard, error) {
+	if m.publicDashboards == nil {
+		m.publicDashboards = map[string]*grafana.PublicDashboard{}
+	}
+	publicDashboard.UID = "public-" + dashboardUID
+	publicDashboard.DashboardUID = dashboardUID
+	publicDashboard.AccessToken = "token-" + dashboardUID
+	m.publicDashboards[dashboardUID] = &publicDashboard
+	return &publicDashboard, nil
+}
+
+func (m *mockGrafanaService) UpdatePublicDashboard(ctx context.Context, dashboardUID string, publicDashboard grafana.PublicDashboard, grafanaURL, apiKey string) (*grafana.PublicDashboard, error) {
+	m.publicDashboards[dashboardUID] = &publicDashboard
+	return &publicDashboard, nil
+}
+
+func (m *mockGrafanaService) GetPreferences(ctx context.Context, teamID int, grafanaURL, apiKey string) (*grafana.Preferences, error) {
+	return &grafana.Preferences{HomeDashboardUID: m.homeDashboards[teamID]}, nil
+}
+
+func (m *mockGrafanaService) SetHomeDashboard(ctx context.Context, teamID int, dashboardUID, grafanaURL, apiKey string) error {
+	if m.homeDashboards == nil {
+		m.homeDashboards = map[int]string{}
+	}
+	m.homeDashboards[teamID] = dashboardUID
 	return nil
 }
 
+func (m *mockGrafanaService) FindTeam(ctx context.Context, name, grafanaURL, apiKey string) (*grafana.Team, error) {
+	for i := range m.teams {
+		if m.teams[i].Name == name {
+			return &m.teams[i], nil
+		}
+	}
+	return nil, fmt.Errorf("team %q not found", name)
+}
+
+func (m *mockGrafanaService) ListDatasources(ctx context.Context, grafanaURL, apiKey string) ([]grafana.Datasource, error) {
+	return m.datasources, nil
+}
+
+func (m *mockGrafanaService) GetDatasource(ctx context.Context, uidOrName, grafanaURL, apiKey string) (*grafana.Datasource, error) {
+	for i := range m.datasources {
+		if m.datasources[i].UID == uidOrName || m.datasources[i].Name == uidOrName {
+			return &m.datasources[i], nil
+		}
+	}
+	return nil, fmt.Errorf("datasource %q not found", uidOrName)
+}
+
+func (m *mockGrafanaService) CheckDatasourceHealth(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.DatasourceHealth, error) {
+	if health, ok := m.datasourceHealth[uid]; ok {
+		return health, nil
+	}
+	return &grafana.DatasourceHealth{Status: grafana.HealthOK, Message: "Successfully queried the Prometheus API."}, nil
+}
+
+func (m *mockGrafanaService) QueryDatasource(ctx context.Context, datasource grafana.Datasource, query map[string]any, grafanaURL, apiKey string) (int, error) {
+	if m.queryDatasourceFunc != nil {
+		return m.queryDatasourceFunc(ctx, datasource, query)
+	}
+	return 1, nil
+}
+
+func (m *mockGrafanaService) GetHealth(ctx context.Context, grafanaURL, apiKey string) (*grafana.Health, error) {
+	return &grafana.Health{Version: m.grafanaVersion}, nil
+}
+
 func TestNewCreateDashboardTool(t *testing.T) {
 	logger := zap.NewNop()
 	mockGrafana := &mockGrafanaService{}
@@ -48,7 +261,7 @@ func TestNewCreateDashboardTool(t *testing.T) {
 		APIKey:        "test-key",
 	}
 
-	tool := NewCreateDashboardTool(logger, mockGrafana, cfg)
+	tool := NewCreateDashboardTool(logger, mockGrafana, nil, cfg, &config.EnvironmentConfig{}, deploy.Options{})
 
 	if tool == nil {
 		t.Error("Expected non-nil tool")
@@ -105,6 +318,234 @@ func TestCreateDashboardHandler_BasicPanels(t *testing.T) {
 	}
 }
 
+func TestProcessPanelsDescription(t *testing.T) {
+	panels := processPanels([]any{
+		map[string]any{
+			"title":       "Requests",
+			"targets":     []any{map[string]any{"refId": "A", "expr": "sum by (job) (rate(http_requests_total[$__rate_interval]))"}},
+			"fieldConfig": map[string]any{"defaults": map[string]any{"unit": "reqps"}},
+		},
+		map[string]any{
+			"title":       "Documented",
+			"description": "Written by hand",
+			"targets":     []any{map[string]any{"refId": "A", "expr": "up"}},
+		},
+		map[string]any{"title": "Text"},
+	}, "en")
+
+	expected := "Per-second rate of http_requests_total over the dashboard rate interval, summed by job. Unit: requests per second."
+	if got := panels[0].(map[string]any)["description"]; got != expected {
+		t.Errorf("Expected description %q, got %v", expected, got)
+	}
+	if got := panels[1].(map[string]any)["description"]; got != "Written by hand" {
+		t.Errorf("Expected the given description to be kept, got %v", got)
+	}
+	if _, ok := panels[2].(map[string]any)["description"]; ok {
+		t.Error("Expected no description for a panel without queries")
+	}
+}
+
+func TestProcessPanelsInterval(t *testing.T) {
+	panels := processPanels([]any{
+		map[string]any{"title": "Requests", "interval": "5m"},
+		map[string]any{"title": "Errors"},
+	}, "en")
+
+	if got := panels[0].(map[string]any)["interval"]; got != "5m" {
+		t.Errorf("Expected the 5m minimum interval, got %v", got)
+	}
+	if _, ok := panels[1].(map[string]any)["interval"]; ok {
+		t.Error("Expected no interval when none is given")
+	}
+}
+
+func TestProcessPanelsNodeGraph(t *testing.T) {
+	panels := processPanels([]any{
+		map[string]any{
+			"title": "Topology",
+			"type":  "nodeGraph",
+			"targets": []any{
+				map[string]any{"refId": "edges", "expr": `label_join(calls, "id", "->", "source", "target")`},
+				map[string]any{"refId": "nodes", "expr": `label_replace(received, "title", "$1", "id", "(.*)")`},
+			},
+		},
+		map[string]any{
+			"title":           "Shaped by hand",
+			"type":            "nodeGraph",
+			"targets":         []any{map[string]any{"refId": "edges", "expr": "calls"}},
+			"transformations": []any{map[string]any{"id": "merge", "options": map[string]any{}}},
+		},
+	}, "en")
+
+	panel := panels[0].(map[string]any)
+	for _, raw := range panel["targets"].([]any) {
+		target := raw.(map[string]any)
+		if target["instant"] != true || target["format"] != "table" {
+			t.Errorf("Expected the %s query as an instant table, got %v", target["refId"], target)
+		}
+	}
+	transformations := panel["transformations"].([]any)
+	if len(transformations) != 2 || transformations[0].(map[string]any)["id"] != "renameByRegex" {
+		t.Errorf("Expected the value renamed to mainstat, got %v", transformations)
+	}
+	if got := panels[1].(map[string]any)["transformations"].([]any); len(got) != 1 {
+		t.Errorf("Expected the given transformations to be kept, got %v", got)
+	}
+}
+
+func TestProcessPanelsStateTimeline(t *testing.T) {
+	panels := processPanels([]any{
+		map[string]any{
+			"title":   "Targets",
+			"type":    "state-timeline",
+			"targets": []any{map[string]any{"refId": "A", "expr": "min by (instance) (up)"}},
+		},
+		map[string]any{
+			"title":       "Checks",
+			"type":        "status-history",
+			"targets":     []any{map[string]any{"refId": "A", "expr": "probe_success"}},
+			"fieldConfig": map[string]any{"defaults": map[string]any{"mappings": []any{}}, "overrides": []any{}},
+		},
+	}, "en")
+
+	timeline := panels[0].(map[string]any)
+	defaults := timeline["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+	if _, ok := defaults["custom"].(map[string]any)["drawStyle"]; ok {
+		t.Errorf("Expected state timeline field defaults, got %v", defaults["custom"])
+	}
+	mappings := defaults["mappings"].([]any)
+	options := mappings[0].(map[string]any)["options"].(map[string]any)
+	if down := options["0"].(map[string]any); down["text"] != "Down" || down["color"] != "red" {
+		t.Errorf("Expected 0 mapped to a red Down, got %v", down)
+	}
+	if timeline["options"].(map[string]any)["mergeValues"] != true {
+		t.Errorf("Expected state timeline options, got %v", timeline["options"])
+	}
+
+	history := panels[1].(map[string]any)
+	if got := fieldConfigDefaults(history)["mappings"].([]any); len(got) != 0 {
+		t.Errorf("Expected the given mappings to be kept, got %v", got)
+	}
+}
+
+func TestProcessPanelsGaugeRange(t *testing.T) {
+	gauge := func(unit string, targets ...any) any {
+		return map[string]any{
+			"type":        "gauge",
+			"targets":     targets,
+			"fieldConfig": map[string]any{"defaults": map[string]any{"unit": unit}, "overrides": []any{}},
+		}
+	}
+	panels := processPanels([]any{
+		gauge("percent", map[string]any{"refId": "A", "expr": "cpu_usage_percent"}),
+		gauge("bytes", map[string]any{"refId": "A", "expr": "jvm_memory_used_bytes"}, map[string]any{"refId": "max", "expr": "jvm_memory_max_bytes"}),
+		gauge("bytes", map[string]any{"refId": "A", "expr": "jvm_memory_used_bytes"}),
+	}, "en")
+
+	bounds := func(panel any) (any, any) {
+		defaults := fieldConfigDefaults(panel.(map[string]any))
+		return defaults["min"], defaults["max"]
+	}
+	if min, max := bounds(panels[0]); min != 0.0 || max != 100.0 {
+		t.Errorf("Expected a percent gauge from 0 to 100, got %v to %v", min, max)
+	}
+	if min, max := bounds(panels[1]); min != 0 || max != nil {
+		t.Errorf("Expected a gauge from 0 to its max query, got %v to %v", min, max)
+	}
+	if _, ok := panels[1].(map[string]any)["transformations"]; !ok {
+		t.Error("Expected the max set from the max query")
+	}
+	if min, max := bounds(panels[2]); min != nil || max != nil {
+		t.Errorf("Expected no bounds without a capacity, got %v to %v", min, max)
+	}
+}
+
+func TestCreateDashboardHandler_Summary(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: &mockGrafanaService{},
+		config:     &config.GrafanaConfig{},
+	}
+
+	args := map[string]any{
+		"dashboard_title": "Checkout",
+		"tags":            []any{"payments"},
+		"panels": []any{
+			map[string]any{
+				"title":   "Request rate",
+				"targets": []any{map[string]any{"refId": "A", "expr": "sum(rate(http_requests_total[5m]))"}},
+			},
+			map[string]any{"title": "Errors", "type": "stat"},
+			map[string]any{"title": "Latency"},
+		},
+		"variables": []any{
+			map[string]any{"name": "job", "query": "label_values(up, job)"},
+		},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	expected := `Dashboard "Checkout": 3 panels, 1 variables, time now-6h to now, refresh 5s
+Tags: payments
+Layout: 2 rows (2/1 panels per row), 16 grid units tall
+Panels:
+  1. Request rate [timeseries] at (0,0) 12x8
+     A: sum(rate(http_requests_total[5m]))
+  2. Errors [stat] at (12,0) 12x8
+     A: (no query)
+  3. Latency [timeseries] at (0,8) 12x8
+     A: (no query)
+Variables:
+  - $job (query): label_values(up, job)`
+	if response["summary"] != expected {
+		t.Errorf("Unexpected summary:\n%v", response["summary"])
+	}
+}
+
+func TestCreateDashboardHandler_Language(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: &mockGrafanaService{},
+		config:     &config.GrafanaConfig{Defaults: config.GrafanaDefaultsConfig{Language: "de-DE"}},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+		"dashboard_title": "Checkout",
+		"panels": []any{
+			map[string]any{"targets": []any{map[string]any{"refId": "A", "expr": "sum(rate(http_requests_total[5m]))"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	panel := response["dashboard"].(map[string]any)["panels"].([]any)[0].(map[string]any)
+	if panel["title"] != "Panel 1" {
+		t.Errorf("Expected a localized default title, got %v", panel["title"])
+	}
+	expected := "Rate pro Sekunde von http_requests_total über 5m, summiert über alle Serien. Einheit: pro Sekunde."
+	if panel["description"] != expected {
+		t.Errorf("Expected description %q, got %v", expected, panel["description"])
+	}
+	summary, _ := response["summary"].(string)
+	if !strings.HasPrefix(summary, `Dashboard "Checkout": 1 Panels, 0 Variablen, Zeitraum now-6h bis now`) || !strings.Contains(summary, "\nPanels:\n") {
+		t.Errorf("Unexpected summary:\n%v", summary)
+	}
+}
+
 func TestCreateDashboardHandler_MissingTitle(t *testing.T) {
 	logger := zap.NewNop()
 	mockGrafana := &mockGrafanaService{}
@@ -196,6 +637,259 @@ func TestCreateDashboardHandler_DeploymentDisabled(t *testing.T) {
 	}
 }
 
+func TestCreateDashboardHandler_OrgDefaults(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: &mockGrafanaService{},
+		config: &config.GrafanaConfig{
+			Defaults: config.GrafanaDefaultsConfig{
+				Refresh:   "1m",
+				Tags:      []string{"agent-generated", "payments"},
+				TimeFrom:  "now-24h",
+				Timezone:  "utc",
+				WeekStart: "monday",
+			},
+		},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+		"dashboard_title": "Checkout",
+		"panels":          []any{map[string]any{"title": "Requests"}},
+		"tags":            []any{"payments", "checkout"},
+		"timezone":        "Europe/Berlin",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	dashboard := response["dashboard"].(map[string]any)
+
+	if dashboard["timezone"] != "Europe/Berlin" {
+		t.Errorf("Expected argument to override the default timezone, got %v", dashboard["timezone"])
+	}
+	if dashboard["weekStart"] != "monday" || dashboard["refresh"] != "1m" {
+		t.Errorf("Expected configured weekStart and refresh, got %v / %v", dashboard["weekStart"], dashboard["refresh"])
+	}
+	timeRange := dashboard["time"].(map[string]any)
+	if timeRange["from"] != "now-24h" || timeRange["to"] != "now" {
+		t.Errorf("Expected configured time range with built-in end, got %v", timeRange)
+	}
+	tags, _ := dashboard["tags"].([]any)
+	if len(tags) != 3 || tags[0] != "payments" || tags[1] != "checkout" || tags[2] != "agent-generated" {
+		t.Errorf("Expected requested tags followed by missing default tags, got %v", tags)
+	}
+}
+
+func TestCreateDashboardHandler_Environment(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:      zap.NewNop(),
+		grafanaSvc:  &mockGrafanaService{},
+		config:      &config.GrafanaConfig{},
+		environment: &config.EnvironmentConfig{Labels: map[string]string{"cluster": "prod-eu", "env": "prod"}},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+		"dashboard_title": "Checkout",
+		"panels": []any{
+			map[string]any{
+				"title":   "Requests",
+				"targets": []any{map[string]any{"refId": "A", "expr": `sum(rate(http_requests_total{env="staging"}[5m]))`}},
+			},
+		},
+		"variables": []any{map[string]any{"name": "env", "type": "custom", "query": "prod,staging"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	dashboard := response["dashboard"].(map[string]any)
+
+	list := dashboard["templating"].(map[string]any)["list"].([]any)
+	if len(list) != 2 {
+		t.Fatalf("Expected the user variable plus one constant, got %v", list)
+	}
+	constant := list[1].(map[string]any)
+	if constant["name"] != "cluster" || constant["type"] != "constant" || constant["query"] != "prod-eu" || constant["hide"] != float64(2) {
+		t.Errorf("Unexpected environment variable %v", constant)
+	}
+
+	expr := dashboard["panels"].([]any)[0].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]
+	if expr != `sum(rate(http_requests_total{env="staging", cluster="$cluster"}[5m]))` {
+		t.Errorf("Expected target scoped to the cluster variable only, got %v", expr)
+	}
+}
+
+func TestCreateDashboardHandler_ScopeLabel(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:      zap.NewNop(),
+		grafanaSvc:  &mockGrafanaService{},
+		config:      &config.GrafanaConfig{},
+		environment: &config.EnvironmentConfig{ScopeLabel: "tenant"},
+	}
+
+	t.Run("scopes panels and variables", func(t *testing.T) {
+		result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+			"dashboard_title": "Checkout",
+			"panels": []any{
+				map[string]any{
+					"title":   "Requests",
+					"targets": []any{map[string]any{"refId": "A", "expr": `sum(rate(http_requests_total[5m])) / sum(rate(http_requests_total{code=~"5.."}[5m]))`}},
+				},
+			},
+			"variables": []any{map[string]any{"name": "instance", "type": "query", "query": `label_values(up{job="api"}, instance)`}},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		var response map[string]any
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Expected valid JSON result, got error: %v", err)
+		}
+		dashboard := response["dashboard"].(map[string]any)
+
+		list := dashboard["templating"].(map[string]any)["list"].([]any)
+		if len(list) != 2 {
+			t.Fatalf("Expected the user variable plus the scope variable, got %v", list)
+		}
+		instance := list[0].(map[string]any)
+		if instance["query"] != `label_values(up{job="api", tenant="$tenant"}, instance)` {
+			t.Errorf("Expected the variable query scoped to the tenant, got %v", instance["query"])
+		}
+		scope := list[1].(map[string]any)
+		if scope["name"] != "tenant" || scope["type"] != "query" || scope["query"] != "label_values(tenant)" || scope["includeAll"] != false {
+			t.Errorf("Unexpected scope variable %v", scope)
+		}
+
+		expr := dashboard["panels"].([]any)[0].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]
+		if expr != `sum(rate(http_requests_total{tenant="$tenant"}[5m])) / sum(rate(http_requests_total{code=~"5..", tenant="$tenant"}[5m]))` {
+			t.Errorf("Expected every selector scoped to the tenant, got %v", expr)
+		}
+	})
+
+	t.Run("rejects queries matching another tenant", func(t *testing.T) {
+		_, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+			"dashboard_title": "Checkout",
+			"panels": []any{
+				map[string]any{
+					"title":   "Requests",
+					"targets": []any{map[string]any{"refId": "A", "expr": `sum(rate(http_requests_total{tenant=~".+"}[5m]))`}},
+				},
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), `panel "Requests" query A`) {
+			t.Fatalf("Expected the unscoped query to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestCreateDashboardHandler_TrendsVariant(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: &mockGrafanaService{},
+		config:     &config.GrafanaConfig{},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+		"dashboard_title": "Checkout API",
+		"trends_variant":  true,
+		"panels": []any{
+			map[string]any{
+				"title":       "Requests",
+				"targets":     []any{map[string]any{"refId": "A", "expr": "sum by (job) (rate(http_requests_total[$__rate_interval]))"}},
+				"fieldConfig": map[string]any{"defaults": map[string]any{"unit": "reqps"}},
+			},
+			map[string]any{
+				"title":   "Queue depth",
+				"targets": []any{map[string]any{"refId": "A", "expr": "max(queue_depth)"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	realtime := response["dashboard"].(map[string]any)
+	trends := response["trends"].(map[string]any)["dashboard"].(map[string]any)
+
+	if realtime["uid"] != "checkout-api-realtime" || trends["uid"] != "checkout-api-trends" {
+		t.Errorf("Expected UIDs derived from the title, got %v and %v", realtime["uid"], trends["uid"])
+	}
+	if link := realtime["links"].([]any)[0].(map[string]any); link["url"] != "/d/checkout-api-trends" {
+		t.Errorf("Expected a link to the trends dashboard, got %v", link)
+	}
+	if link := trends["links"].([]any)[0].(map[string]any); link["url"] != "/d/checkout-api-realtime" || link["keepTime"] != false {
+		t.Errorf("Expected a link back to the real-time dashboard, got %v", link)
+	}
+	if trends["title"] != "Checkout API (trends)" || trends["time"].(map[string]any)["from"] != "now-30d" {
+		t.Errorf("Expected a 30 day trends dashboard, got %v %v", trends["title"], trends["time"])
+	}
+
+	panels := trends["panels"].([]any)
+	requests := panels[0].(map[string]any)
+	if expr := requests["targets"].([]any)[0].(map[string]any)["expr"]; expr != "sum by (job) (increase(http_requests_total[1d]))" {
+		t.Errorf("Expected daily increase, got %v", expr)
+	}
+	if unit := requests["fieldConfig"].(map[string]any)["defaults"].(map[string]any)["unit"]; unit != "short" || requests["interval"] != "1d" {
+		t.Errorf("Expected daily counts at a 1d interval, got unit %v interval %v", unit, requests["interval"])
+	}
+	if description, _ := requests["description"].(string); !strings.HasPrefix(description, "Increase of http_requests_total") {
+		t.Errorf("Expected the description redone for the trends query, got %q", description)
+	}
+	if expr := panels[1].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]; expr != "max(avg_over_time(queue_depth[1d]))" {
+		t.Errorf("Expected the daily average of the gauge, got %v", expr)
+	}
+
+	realtimeExpr := realtime["panels"].([]any)[0].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]
+	if realtimeExpr != "sum by (job) (rate(http_requests_total[$__rate_interval]))" {
+		t.Errorf("Expected the real-time query unchanged, got %v", realtimeExpr)
+	}
+}
+
+func TestCreateDashboardHandler_NamingConvention(t *testing.T) {
+	tool := &CreateDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: &mockGrafanaService{},
+		config: &config.GrafanaConfig{Naming: config.GrafanaNamingConfig{
+			Prefix:         "[Payments]",
+			TitleCase:      true,
+			ForbiddenWords: []string{"tmp"},
+		}},
+	}
+
+	result, err := tool.CreateDashboardHandler(context.Background(), map[string]any{
+		"dashboard_title": "tmp checkout latency by region",
+		"trends_variant":  true,
+		"panels":          []any{map[string]any{"title": "Requests"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if title := response["dashboard"].(map[string]any)["title"]; title != "[Payments] Checkout Latency by Region" {
+		t.Errorf("Expected the naming convention applied, got %v", title)
+	}
+	if title := response["trends"].(map[string]any)["dashboard"].(map[string]any)["title"]; title != "[Payments] Checkout Latency by Region (Trends)" {
+		t.Errorf("Expected the naming convention applied to the trends variant, got %v", title)
+	}
+}
+
 func TestExtractTags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -225,7 +919,7 @@ func TestExtractTags(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractTags(tt.args)
+			result := extractTags(tt.args, builtinDashboardDefaults)
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("Expected %d tags, got %d", len(tt.expected), len(result))
@@ -283,7 +977,7 @@ func TestExtractTimeRange(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractTimeRange(tt.args)
+			result := extractTimeRange(tt.args, builtinDashboardDefaults)
 
 			if result["from"] != tt.expected["from"] {
 				t.Errorf("Expected from = %s, got %s", tt.expected["from"], result["from"])
@@ -325,7 +1019,7 @@ func TestExtractRefreshInterval(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractRefreshInterval(tt.args)
+			result := extractRefreshInterval(tt.args, builtinDashboardDefaults)
 			if result != tt.expected {
 				t.Errorf("Expected %s, got %s", tt.expected, result)
 			}
@@ -0,0 +1,334 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// CreateFleetOverviewTool struct holds the tool with services
+type CreateFleetOverviewTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewCreateFleetOverviewTool creates a new create_fleet_overview tool
+func NewCreateFleetOverviewTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, promqlSvc promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CreateFleetOverviewTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		promql:        promqlSvc,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"create_fleet_overview",
+		"Builds a fleet dashboard with one table row per service (job) showing availability, error ratio, P95 latency and a CPU sparkline, linking each service to its dashboards, optionally deployed",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the dashboard (default \"Fleet overview\")",
+					"type":        "string",
+				},
+				"deploy": map[string]any{
+					"description": "Whether to deploy the dashboard to Grafana (requires GRAFANA_DEPLOY_ENABLED=true)",
+					"type":        "boolean",
+				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Services\"; missing folders are created",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"jobs": map[string]any{
+					"description": "Jobs to list (default every job with up series)",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to list the jobs and metrics from",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.CreateFleetOverviewHandler,
+	)
+}
+
+// CreateFleetOverviewResponse represents the fleet dashboard
+type CreateFleetOverviewResponse struct {
+	*deploy.Result
+	Jobs      []string             `json:"jobs"`
+	Columns   []promql.FleetColumn `json:"columns"`
+	Dashboard map[string]any       `json:"dashboard"`
+	Summary   string               `json:"summary"`
+}
+
+// CreateFleetOverviewHandler handles the create_fleet_overview tool execution
+func (t *CreateFleetOverviewTool) CreateFleetOverviewHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "create_fleet_overview")
+	defer span.End()
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+	var requested []string
+	if raw, ok := args["jobs"].([]any); ok {
+		for _, v := range raw {
+			if job, ok := v.(string); ok && job != "" {
+				requested = append(requested, job)
+			}
+		}
+	}
+
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
+	}
+
+	jobs, err := t.promql.GetLabelValues(ctx, prometheusURL, "job", []string{"up"})
+	if err != nil {
+		return "", fmt.Errorf("failed to list jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return "", fmt.Errorf("no jobs found in the up series of %s", prometheusURL)
+	}
+	var jobMatcher []promql.LabelMatcher
+	var metricMatchers []string
+	if len(requested) > 0 {
+		var unknown, quoted []string
+		for _, job := range requested {
+			if !slices.Contains(jobs, job) {
+				unknown = append(unknown, job)
+			}
+			quoted = append(quoted, regexp.QuoteMeta(job))
+		}
+		if len(unknown) > 0 {
+			return "", fmt.Errorf("unknown jobs %s; Prometheus has %s", strings.Join(unknown, ", "), strings.Join(jobs, ", "))
+		}
+		jobs = requested
+		jobMatcher = []promql.LabelMatcher{{Name: "job", Op: "=~", Value: strings.Join(quoted, "|")}}
+		metricMatchers = []string{promql.InjectMatchers("{}", jobMatcher)}
+	}
+
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", metricMatchers)
+	if err != nil {
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+	columns := promql.FleetColumns(metricNames)
+	for i := range columns {
+		columns[i].Expr = promql.InjectMatchers(columns[i].Expr, jobMatcher)
+	}
+
+	panels := append(fleetStatPanels(columns), fleetTablePanel(columns, serviceTagPrefix(t.grafanaConfig)))
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", "Fleet overview"))
+	tags := []any{"fleet-overview"}
+	dashboard := dashboardModel(title, panels, map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	if variables := environmentVariables(t.environment, nil); len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+
+	response := CreateFleetOverviewResponse{
+		Jobs:      jobs,
+		Columns:   columns,
+		Dashboard: dashboard,
+		Summary:   summarizeDashboard(dashboard, defaults.Language),
+	}
+
+	t.logger.Info("generated fleet overview",
+		zap.Int("jobs", len(jobs)),
+		zap.Int("columns", len(columns)))
+
+	if shouldDeploy {
+		folderPath, _ := args["folder"].(string)
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard,
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    "Fleet overview created via grafana-agent",
+			Overwrite:  true,
+			Provenance: templateProvenance(ctx, dashboard, "fleet_overview"),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// fleetStatPanels counts the services, those with targets down and, when
+// the fleet exposes request metrics, those failing over 1% of requests
+func fleetStatPanels(columns []promql.FleetColumn) []any {
+	stats := []map[string]any{
+		fleetStatPanel("Services", "Jobs with up series.", "count(count by (job) (up))", nil),
+		fleetStatPanel("Services with targets down", "Jobs with at least one target down now.", "count(avg by (job) (up) < 1) or vector(0)", countThresholds()),
+	}
+	for _, column := range columns {
+		if column.Name == "Error ratio" {
+			stats = append(stats, fleetStatPanel("Services over 1% errors", "Jobs failing more than 1% of their requests over the last 5 minutes.", fmt.Sprintf("count((%s) > 0.01) or vector(0)", column.Expr), countThresholds()))
+		}
+	}
+
+	panels := make([]any, len(stats))
+	width := 24 / len(stats)
+	for i, stat := range stats {
+		stat["gridPos"] = map[string]any{"x": i * width, "y": 0, "w": width, "h": 4}
+		panels[i] = stat
+	}
+	return panels
+}
+
+// countThresholds turn a count of failing services red from one
+func countThresholds() []promql.Threshold {
+	one := 1.0
+	return []promql.Threshold{{Value: nil, Color: "green"}, {Value: &one, Color: "red"}}
+}
+
+// fleetStatPanel builds an instant stat panel, coloured by its thresholds
+func fleetStatPanel(title, description, expr string, thresholds []promql.Threshold) map[string]any {
+	panel := map[string]any{
+		"title":       title,
+		"type":        "stat",
+		"description": description,
+		"targets":     []any{map[string]any{"refId": "A", "expr": expr, "instant": true}},
+		"options": map[string]any{
+			"reduceOptions": map[string]any{"calcs": []any{"lastNotNull"}, "fields": "", "values": false},
+			"colorMode":     "background",
+		},
+	}
+	if len(thresholds) > 0 {
+		fieldConfigDefaults(panel)["thresholds"] = suggestedThresholds(thresholds)
+	}
+	return panel
+}
+
+// fleetTablePanel joins the columns into one table row per job. The
+// instant queries return one table frame each and the sparkline queries a
+// series per job, which the timeSeriesTable transformation turns into a
+// trend cell; merge joins the frames on job and organize names the columns.
+// The job cell links to the dashboards tagged with the job, after
+// tagPrefix (see serviceTagPrefix).
+func fleetTablePanel(columns []promql.FleetColumn, tagPrefix string) map[string]any {
+	targets := make([]any, len(columns))
+	rename := map[string]string{}
+	order := []string{"job"}
+	overrides := []any{map[string]any{
+		"matcher": map[string]any{"id": "byName", "options": "job"},
+		"properties": []any{
+			map[string]any{"id": "displayName", "value": "Service"},
+			map[string]any{"id": "links", "value": []any{map[string]any{
+				"title": "Dashboards of ${__value.raw}",
+				"url":   "/dashboards?tag=" + tagPrefix + "${__value.raw}",
+			}}},
+		},
+	}}
+	sparklines := false
+	for i, column := range columns {
+		refID := string(rune('A' + i))
+		target := map[string]any{"refId": refID, "expr": column.Expr}
+		field := "Value #" + refID
+		if column.Sparkline {
+			target["range"], target["format"], target["legendFormat"] = true, "time_series", "{{job}}"
+			field = "Trend #" + refID
+			sparklines = true
+		} else {
+			target["instant"], target["format"] = true, "table"
+		}
+		targets[i] = target
+		rename[field] = column.Name
+		order = append(order, field)
+
+		properties := []any{
+			map[string]any{"id": "unit", "value": column.Unit},
+			map[string]any{"id": "description", "value": column.Description},
+		}
+		switch {
+		case column.Sparkline:
+			properties = append(properties, map[string]any{"id": "custom.cellOptions", "value": map[string]any{"type": "sparkline"}})
+		case len(column.Thresholds) > 0:
+			properties = append(properties,
+				map[string]any{"id": "thresholds", "value": suggestedThresholds(column.Thresholds)},
+				map[string]any{"id": "custom.cellOptions", "value": map[string]any{"type": "color-background", "mode": "basic"}})
+		}
+		overrides = append(overrides, map[string]any{
+			"matcher":    map[string]any{"id": "byName", "options": column.Name},
+			"properties": properties,
+		})
+	}
+
+	var transformations []any
+	if sparklines {
+		transformations = append(transformations, map[string]any{"id": "timeSeriesTable", "options": map[string]any{}})
+	}
+	transformations = append(transformations, mergeTransformation(), organizeTransformation([]string{"Time"}, rename, order))
+
+	return map[string]any{
+		"title":           "Services",
+		"type":            "table",
+		"description":     "One row per service, least available first. Availability covers the time range; errors and latency the last 5 minutes.",
+		"gridPos":         map[string]any{"x": 0, "y": 4, "w": 24, "h": 16},
+		"targets":         targets,
+		"transformations": transformations,
+		"options": map[string]any{
+			"showHeader": true,
+			"sortBy":     []any{map[string]any{"displayName": "Availability", "desc": false}},
+		},
+		"fieldConfig": map[string]any{
+			"defaults":  map[string]any{"custom": map[string]any{"align": "auto", "cellOptions": map[string]any{"type": "auto"}}},
+			"overrides": overrides,
+		},
+	}
+}
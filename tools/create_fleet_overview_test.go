@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestCreateFleetOverviewHandler(t *testing.T) {
+	newTool := func(fake *promqlfakes.FakePromQL) *CreateFleetOverviewTool {
+		return &CreateFleetOverviewTool{
+			logger:        zap.NewNop(),
+			promql:        fake,
+			grafanaConfig: &config.GrafanaConfig{},
+			environment:   &config.EnvironmentConfig{},
+		}
+	}
+	newFake := func() *promqlfakes.FakePromQL {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesCalls(func(_ context.Context, _ string, label string, _ []string) ([]string, error) {
+			if label == "job" {
+				return []string{"cart", "checkout", "payments"}, nil
+			}
+			return []string{"up", "http_requests_total", "http_request_duration_seconds_bucket", "process_cpu_seconds_total"}, nil
+		})
+		return fake
+	}
+
+	t.Run("table of the selected jobs", func(t *testing.T) {
+		fake := newFake()
+
+		result, err := newTool(fake).CreateFleetOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"jobs":           []any{"checkout", "payments"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateFleetOverviewResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if _, _, _, matchers := fake.GetLabelValuesArgsForCall(1); len(matchers) != 1 || matchers[0] != `{job=~"checkout|payments"}` {
+			t.Errorf("Expected the metrics to be listed for the jobs, got %v", matchers)
+		}
+		if strings.Join(response.Jobs, ",") != "checkout,payments" {
+			t.Errorf("Expected the selected jobs, got %v", response.Jobs)
+		}
+
+		var table map[string]any
+		var stats []string
+		for _, raw := range response.Dashboard["panels"].([]any) {
+			panel := raw.(map[string]any)
+			switch panel["type"] {
+			case "table":
+				table = panel
+			case "stat":
+				stats = append(stats, panel["title"].(string))
+			}
+		}
+		if len(stats) != 3 {
+			t.Errorf("Expected the services, targets down and errors stats, got %v", stats)
+		}
+		if table == nil {
+			t.Fatal("Expected the services table")
+		}
+
+		targets := table["targets"].([]any)
+		if len(targets) != 4 {
+			t.Fatalf("Expected one query per column, got %d", len(targets))
+		}
+		availability := targets[0].(map[string]any)
+		if availability["expr"] != `avg by (job) (avg_over_time(up{job=~"checkout|payments"}[$__range]))` || availability["format"] != "table" {
+			t.Errorf("Expected an instant availability table query scoped to the jobs, got %v", availability)
+		}
+		if cpu := targets[3].(map[string]any); cpu["range"] != true {
+			t.Errorf("Expected the CPU sparkline as a range query, got %v", cpu)
+		}
+
+		var ids []string
+		for _, raw := range table["transformations"].([]any) {
+			ids = append(ids, raw.(map[string]any)["id"].(string))
+		}
+		if strings.Join(ids, ",") != "timeSeriesTable,merge,organize" {
+			t.Errorf("Expected the sparkline, merge and organize transformations, got %v", ids)
+		}
+		organize := table["transformations"].([]any)[2].(map[string]any)["options"].(map[string]any)
+		if rename := organize["renameByName"].(map[string]any); rename["Value #B"] != "Error ratio" || rename["Trend #D"] != "CPU" {
+			t.Errorf("Expected the columns renamed, got %v", rename)
+		}
+
+		overrides := table["fieldConfig"].(map[string]any)["overrides"].([]any)
+		link := overrides[0].(map[string]any)["properties"].([]any)[1].(map[string]any)["value"].([]any)[0].(map[string]any)
+		if link["url"] != "/dashboards?tag=${__value.raw}" {
+			t.Errorf("Expected the service to link to its dashboards, got %v", link)
+		}
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		_, err := newTool(newFake()).CreateFleetOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"jobs":           []any{"chekout"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "unknown jobs chekout") {
+			t.Errorf("Expected an unknown job error, got %v", err)
+		}
+	})
+}
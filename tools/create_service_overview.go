@@ -0,0 +1,306 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// CreateServiceOverviewTool struct holds the tool with services
+type CreateServiceOverviewTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewCreateServiceOverviewTool creates a new create_service_overview tool
+func NewCreateServiceOverviewTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, promqlSvc promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CreateServiceOverviewTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		promql:        promqlSvc,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"create_service_overview",
+		"Builds a landing dashboard for a service, identified by its job, with request rate, errors and latency, runtime stats, calls to dependencies, upstream and downstream calls from Tempo's service graph, firing alerts and links to the service's detail dashboards, optionally deployed",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the dashboard (default \"<job> overview\")",
+					"type":        "string",
+				},
+				"deploy": map[string]any{
+					"description": "Whether to deploy the dashboard to Grafana (requires GRAFANA_DEPLOY_ENABLED=true)",
+					"type":        "boolean",
+				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Services/Checkout\"; missing folders are created",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"job": map[string]any{
+					"description": "Job label of the service",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to list the service's metrics from",
+					"type":        "string",
+				},
+				"service": map[string]any{
+					"description": "Name of the service in traces, for the service graph panels of Tempo's metrics generator (default the job without its namespace/ prefix)",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url", "job"},
+		},
+		tool.CreateServiceOverviewHandler,
+	)
+}
+
+// CreateServiceOverviewResponse represents a service's landing dashboard
+type CreateServiceOverviewResponse struct {
+	*deploy.Result
+	Job string `json:"job"`
+	// Service is the service's name in traces; Upstream and Downstream list
+	// the services the service graph shows calling it and called by it
+	Service    string   `json:"service"`
+	Upstream   []string `json:"upstream,omitempty"`
+	Downstream []string `json:"downstream,omitempty"`
+	// Templates names the built-in templates that fit the service's metrics,
+	// whose dashboards the overview links to once built for the job
+	Templates []string       `json:"templates,omitempty"`
+	Dashboard map[string]any `json:"dashboard"`
+	Summary   string         `json:"summary"`
+}
+
+// CreateServiceOverviewHandler handles the create_service_overview tool execution
+func (t *CreateServiceOverviewTool) CreateServiceOverviewHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "create_service_overview")
+	defer span.End()
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+	job, ok := args["job"].(string)
+	if !ok || job == "" {
+		return "", fmt.Errorf("job is required and must be a string")
+	}
+
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
+	}
+
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", []string{fmt.Sprintf("{job=%q}", job)})
+	if err != nil {
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+	if len(metricNames) == 0 {
+		return "", fmt.Errorf("no metrics found for job %s; discover_metrics lists the jobs Prometheus scrapes", job)
+	}
+	overviewPanels := promql.ServiceOverviewPanels(metricNames)
+	service := getStringOrDefault(args, "service", job[strings.LastIndex(job, "/")+1:])
+	graphMetrics, upstream, downstream, err := t.serviceGraph(ctx, prometheusURL, service)
+	if err != nil {
+		return "", err
+	}
+	graphPanels := promql.ServiceGraphPanels(graphMetrics, service, len(upstream) > 0, len(downstream) > 0)
+	if len(overviewPanels) == 0 && len(graphPanels) == 0 {
+		return "", fmt.Errorf("no request, runtime, dependency or service graph metrics found for job %s", job)
+	}
+
+	jobMatcher := []promql.LabelMatcher{{Name: "job", Op: "=", Value: job}}
+	var panels []map[string]any
+	var groups []string
+	for _, overviewPanel := range overviewPanels {
+		panels = append(panels, templateDashboardPanel(overviewPanel, jobMatcher))
+		groups = append(groups, overviewPanel.Group)
+	}
+	// The service graph metrics carry the service name, not the job
+	if len(graphPanels) > 0 {
+		panels = append(panels, serviceMapPanel(service, slices.Concat(upstream, downstream)))
+		groups = append(groups, promql.GroupServiceGraph)
+	}
+	for _, graphPanel := range graphPanels {
+		panels = append(panels, templateDashboardPanel(graphPanel, nil))
+		groups = append(groups, graphPanel.Group)
+	}
+	// The firing alerts join the request stats at the top
+	panels = append(panels, serviceAlertListPanel(job))
+	groups = append(groups, "")
+
+	var templates []string
+	for _, template := range promql.SelectTemplates(metricNames) {
+		templates = append(templates, template.Name)
+	}
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", job+" overview"))
+	tags := []any{"service-overview", serviceTag(t.grafanaConfig, job)}
+	dashboard := dashboardModel(title, groupPanelsInRows(panels, groups), map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	dashboard["links"] = []any{serviceDashboardsLink(serviceTag(t.grafanaConfig, job))}
+	if variables := environmentVariables(t.environment, nil); len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+
+	response := CreateServiceOverviewResponse{
+		Job:        job,
+		Service:    service,
+		Upstream:   upstream,
+		Downstream: downstream,
+		Templates:  templates,
+		Dashboard:  dashboard,
+		Summary:    summarizeDashboard(dashboard, defaults.Language),
+	}
+
+	t.logger.Info("generated service overview",
+		zap.String("job", job),
+		zap.Int("panels", len(panels)),
+		zap.Strings("templates", templates))
+
+	if shouldDeploy {
+		folderPath, _ := args["folder"].(string)
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard,
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    fmt.Sprintf("Service overview of %s created via grafana-agent", job),
+			Overwrite:  true,
+			Provenance: templateProvenance(ctx, dashboard, "service_overview"),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// serviceGraph lists the service graph metrics Prometheus has and the
+// services calling the service and called by it
+func (t *CreateServiceOverviewTool) serviceGraph(ctx context.Context, prometheusURL, service string) (metricNames, upstream, downstream []string, err error) {
+	metricNames, err = t.promql.GetLabelValues(ctx, prometheusURL, "__name__", []string{`{__name__=~"traces_service_graph_request_.*"}`})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list service graph metrics: %w", err)
+	}
+	if !slices.Contains(metricNames, promql.ServiceGraphRequests) {
+		return nil, nil, nil, nil
+	}
+	upstream, err = t.promql.GetLabelValues(ctx, prometheusURL, "client", []string{fmt.Sprintf("%s{server=%q}", promql.ServiceGraphRequests, service)})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list upstream services: %w", err)
+	}
+	downstream, err = t.promql.GetLabelValues(ctx, prometheusURL, "server", []string{fmt.Sprintf("%s{client=%q}", promql.ServiceGraphRequests, service)})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list downstream services: %w", err)
+	}
+	return metricNames, upstream, downstream, nil
+}
+
+// serviceMapPanel draws the service with the services calling it and called
+// by it as a node graph
+func serviceMapPanel(service string, neighbours []string) map[string]any {
+	edges, nodes := promql.ServiceGraphTopology(service, neighbours)
+	return map[string]any{
+		"title":       "Service map",
+		"type":        "nodeGraph",
+		"description": fmt.Sprintf("Services calling %s and called by it, with the calls per second of each edge and the calls per second each service receives.", service),
+		"targets": []any{
+			map[string]any{"refId": nodeGraphEdges, "expr": edges},
+			map[string]any{"refId": nodeGraphNodes, "expr": nodes},
+		},
+		"options": map[string]any{
+			"nodes": map[string]any{"mainStatUnit": "reqps"},
+			"edges": map[string]any{"mainStatUnit": "reqps"},
+		},
+	}
+}
+
+// serviceAlertListPanel lists the firing and pending Grafana-managed and
+// data source alerts whose instances carry the service's job label
+func serviceAlertListPanel(job string) map[string]any {
+	return map[string]any{
+		"title":       "Firing alerts",
+		"type":        "alertlist",
+		"description": fmt.Sprintf("Firing and pending alerts labelled job=%q.", job),
+		"options": map[string]any{
+			"viewMode":                 "list",
+			"groupMode":                "default",
+			"maxItems":                 20,
+			"sortOrder":                1,
+			"dashboardAlerts":          false,
+			"alertInstanceLabelFilter": fmt.Sprintf("{job=%q}", job),
+			"stateFilter": map[string]any{
+				"firing":  true,
+				"pending": true,
+				"error":   true,
+				"noData":  false,
+				"normal":  false,
+			},
+		},
+	}
+}
+
+// serviceDashboardsLink links to the dashboards with the service tag of a
+// job, as create_template_dashboard tags the dashboards it builds for a job,
+// keeping the time range and variables
+func serviceDashboardsLink(tag string) map[string]any {
+	return map[string]any{
+		"title":       "Detail dashboards",
+		"type":        "dashboards",
+		"tags":        []any{tag},
+		"asDropdown":  true,
+		"includeVars": true,
+		"keepTime":    true,
+	}
+}
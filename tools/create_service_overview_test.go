@@ -0,0 +1,184 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestCreateServiceOverviewHandler(t *testing.T) {
+	newTool := func(fake *promqlfakes.FakePromQL) *CreateServiceOverviewTool {
+		return &CreateServiceOverviewTool{
+			logger:        zap.NewNop(),
+			promql:        fake,
+			grafanaConfig: &config.GrafanaConfig{},
+			environment:   &config.EnvironmentConfig{},
+		}
+	}
+
+	t.Run("landing dashboard of a job", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{
+			"http_requests_total", "http_request_duration_seconds_bucket",
+			"go_goroutines", "grpc_client_handled_total",
+		}, nil)
+
+		result, err := newTool(fake).CreateServiceOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "checkout",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateServiceOverviewResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if _, _, _, matchers := fake.GetLabelValuesArgsForCall(0); len(matchers) != 1 || matchers[0] != `{job="checkout"}` {
+			t.Errorf("Expected the metrics to be listed for the job, got %v", matchers)
+		}
+		if response.Dashboard["title"] != "checkout overview" {
+			t.Errorf("Unexpected title %v", response.Dashboard["title"])
+		}
+		if len(response.Templates) != 1 || response.Templates[0] != "go_runtime" {
+			t.Errorf("Expected the go_runtime template, got %v", response.Templates)
+		}
+
+		var titles, rows []string
+		var rate, alerts map[string]any
+		for _, raw := range response.Dashboard["panels"].([]any) {
+			panel := raw.(map[string]any)
+			title := panel["title"].(string)
+			if panel["type"] == "row" {
+				rows = append(rows, title)
+				continue
+			}
+			titles = append(titles, title)
+			switch title {
+			case "Request rate":
+				rate = panel
+			case "Firing alerts":
+				alerts = panel
+			}
+		}
+		if strings.Join(rows, ", ") != "Requests, Runtime, Dependencies" {
+			t.Errorf("Expected the requests, runtime and dependencies rows, got %v", rows)
+		}
+		if len(titles) < 4 || titles[3] != "Firing alerts" {
+			t.Errorf("Expected the firing alerts next to the request stats, got %v", titles)
+		}
+		if rate == nil || rate["targets"].([]any)[0].(map[string]any)["expr"] != `sum(rate(http_requests_total{job="checkout"}[5m]))` {
+			t.Errorf("Expected the request rate scoped to the job, got %v", rate)
+		}
+		if alerts["options"].(map[string]any)["alertInstanceLabelFilter"] != `{job="checkout"}` {
+			t.Errorf("Expected the alerts filtered by job, got %v", alerts["options"])
+		}
+
+		links := response.Dashboard["links"].([]any)
+		if len(links) != 1 || links[0].(map[string]any)["tags"].([]any)[0] != "checkout" {
+			t.Errorf("Expected a link to the job's dashboards, got %v", links)
+		}
+	})
+
+	t.Run("service graph panels select the service by name", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesCalls(func(_ context.Context, _ string, label string, matchers []string) ([]string, error) {
+			switch {
+			case label == "client":
+				return []string{"frontend"}, nil
+			case label == "server":
+				return nil, nil
+			case strings.Contains(matchers[0], "traces_service_graph"):
+				return []string{"traces_service_graph_request_total", "traces_service_graph_request_failed_total"}, nil
+			}
+			return []string{"http_requests_total"}, nil
+		})
+
+		result, err := newTool(fake).CreateServiceOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "shop/checkout",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateServiceOverviewResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if response.Service != "checkout" || len(response.Upstream) != 1 || len(response.Downstream) != 0 {
+			t.Errorf("Expected checkout called by frontend only, got %s %v %v", response.Service, response.Upstream, response.Downstream)
+		}
+		var upstream, serviceMap map[string]any
+		for _, raw := range response.Dashboard["panels"].([]any) {
+			panel := raw.(map[string]any)
+			if panel["type"] == "nodeGraph" {
+				serviceMap = panel
+			}
+			if panel["title"] == "Calls to downstream services" {
+				t.Error("Expected no downstream panels without downstream calls")
+			}
+			if panel["title"] == "Calls from upstream services" {
+				upstream = panel
+			}
+		}
+		if upstream == nil {
+			t.Fatal("Expected the upstream calls panel")
+		}
+		if serviceMap == nil || len(serviceMap["transformations"].([]any)) == 0 {
+			t.Errorf("Expected a shaped service map, got %v", serviceMap)
+		}
+		if expr := upstream["targets"].([]any)[0].(map[string]any)["expr"]; expr != `sum by (client) (rate(traces_service_graph_request_total{server="checkout"}[5m]))` {
+			t.Errorf("Expected the upstream calls selected by service rather than job, got %v", expr)
+		}
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+
+		_, err := newTool(fake).CreateServiceOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "chekout",
+		})
+		if err == nil || !strings.Contains(err.Error(), "no metrics found for job chekout") {
+			t.Errorf("Expected a missing job error, got %v", err)
+		}
+	})
+
+	t.Run("keeps its own tags under a tag taxonomy", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"http_requests_total"}, nil)
+		tool := newTool(fake)
+		tool.grafanaConfig = &config.GrafanaConfig{
+			Defaults: config.GrafanaDefaultsConfig{Tags: []string{"Team=Payments", "legacy"}},
+			Tags:     config.GrafanaTagsConfig{Taxonomy: []string{"env", "team"}},
+		}
+
+		result, err := tool.CreateServiceOverviewHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "checkout",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateServiceOverviewResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if tags := schemaStrings(response.Dashboard["tags"]); strings.Join(tags, ",") != "service-overview,checkout,team:payments" {
+			t.Errorf("Expected the builder's tags kept and the default tags corrected, got %v", tags)
+		}
+		links := response.Dashboard["links"].([]any)
+		if links[0].(map[string]any)["tags"].([]any)[0] != "checkout" {
+			t.Errorf("Expected the link to match the kept job tag, got %v", links)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// CreateTemplateDashboardTool struct holds the tool with services
+type CreateTemplateDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	deployOptions deploy.Options
+}
+
+// NewCreateTemplateDashboardTool creates a new create_template_dashboard tool
+func NewCreateTemplateDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, promqlSvc promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, deployOptions deploy.Options) server.Tool {
+	tool := &CreateTemplateDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		promql:        promqlSvc,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		deployOptions: deployOptions,
+	}
+
+	var names, descriptions []string
+	for _, template := range promql.DashboardTemplates() {
+		names = append(names, template.Name)
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s)", template.Name, template.Description))
+	}
+	return newValidatedTool(
+		"create_template_dashboard",
+		"Builds a dashboard from a built-in template for a well-known metric family, keeping the panels whose metrics Prometheus has, optionally scoped to one job and deployed",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the dashboard (default the template's title, followed by the job when given)",
+					"type":        "string",
+				},
+				"deploy": map[string]any{
+					"description": "Whether to deploy the dashboard to Grafana (requires GRAFANA_DEPLOY_ENABLED=true)",
+					"type":        "boolean",
+				},
+				"folder": map[string]any{
+					"description": "Folder path to deploy into, such as \"Platform/Probes\"; missing folders are created",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"job": map[string]any{
+					"description": "Optional job to scope every query to, for a dashboard of a single service",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to check the template's metrics against",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+				"template": map[string]any{
+					"description": "Template to build, selected from the metrics found when omitted: " + strings.Join(descriptions, "; "),
+					"type":        "string",
+					"enum":        names,
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.CreateTemplateDashboardHandler,
+	)
+}
+
+// CreateTemplateDashboardResponse represents the dashboard built from a template
+type CreateTemplateDashboardResponse struct {
+	*deploy.Result
+	Template string `json:"template"`
+	// Breakdown is the label the panels break series down by
+	Breakdown string         `json:"breakdown"`
+	Dashboard map[string]any `json:"dashboard"`
+	Summary   string         `json:"summary"`
+}
+
+// CreateTemplateDashboardHandler handles the create_template_dashboard tool execution
+func (t *CreateTemplateDashboardTool) CreateTemplateDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "create_template_dashboard")
+	defer span.End()
+
+	name, _ := args["template"].(string)
+	template, ok := promql.FindDashboardTemplate(name)
+	if !ok && name != "" {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+	job, _ := args["job"].(string)
+
+	shouldDeploy, _ := args["deploy"].(bool)
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	if shouldDeploy {
+		if _, err := deployer.ResolveTarget(ctx, grafanaURL, stack); err != nil {
+			return "", err
+		}
+	}
+
+	var matchers []string
+	var jobMatcher []promql.LabelMatcher
+	if job != "" {
+		matchers = []string{fmt.Sprintf("{job=%q}", job)}
+		jobMatcher = []promql.LabelMatcher{{Name: "job", Op: "=", Value: job}}
+	}
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", matchers)
+	if err != nil {
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+	where := prometheusURL
+	if job != "" {
+		where = fmt.Sprintf("job %s", job)
+	}
+	if name == "" {
+		selected := promql.SelectTemplates(metricNames)
+		switch len(selected) {
+		case 0:
+			return "", fmt.Errorf("no built-in template applies to the metrics of %s", where)
+		case 1:
+			template = selected[0]
+		default:
+			names := make([]string, len(selected))
+			for i, candidate := range selected {
+				names[i] = candidate.Name
+			}
+			return "", fmt.Errorf("several templates apply to %s (%s); pass template, or job to narrow the metrics", where, strings.Join(names, ", "))
+		}
+	} else if !template.Applies(metricNames) {
+		return "", fmt.Errorf("the %s template does not apply: none of %s found for %s", template.Name, strings.Join(template.Signature, ", "), where)
+	}
+
+	breakdown, err := t.breakdownLabel(ctx, prometheusURL, template, jobMatcher)
+	if err != nil {
+		return "", err
+	}
+
+	var panels []map[string]any
+	var groups []string
+	for _, templatePanel := range template.Panels(metricNames, breakdown) {
+		panels = append(panels, templateDashboardPanel(templatePanel, jobMatcher))
+		groups = append(groups, templatePanel.Group)
+	}
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := template.Title
+	if job != "" {
+		title += " - " + job
+	}
+	title = dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", title))
+	tags := []any{template.Name}
+	if job != "" {
+		tags = append(tags, serviceTag(t.grafanaConfig, job))
+	}
+	dashboard := dashboardModel(title, groupPanelsInRows(panels, groups), map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	variables := environmentVariables(t.environment, nil)
+	variableMatchers := append(slices.Clone(jobMatcher), environmentMatchers(t.environment, true)...)
+	for _, variable := range template.Variables {
+		variables = append(variables, templateQueryVariable(variable, variableMatchers))
+	}
+	if len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+
+	response := CreateTemplateDashboardResponse{
+		Template:  template.Name,
+		Breakdown: breakdown,
+		Dashboard: dashboard,
+		Summary:   summarizeDashboard(dashboard, defaults.Language),
+	}
+
+	t.logger.Info("generated template dashboard",
+		zap.String("template", template.Name),
+		zap.String("job", job),
+		zap.Int("panels", len(panels)))
+
+	if shouldDeploy {
+		folderPath, _ := args["folder"].(string)
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:  dashboard,
+			GrafanaURL: grafanaURL,
+			Stack:      stack,
+			FolderPath: folderPath,
+			Message:    fmt.Sprintf("Dashboard created from the %s template via grafana-agent", template.Name),
+			Overwrite:  true,
+			Provenance: templateProvenance(ctx, dashboard, template.Name),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// breakdownLabel picks the first of the template's breakdown labels the
+// signature metrics carry, falling back to the last one; empty for
+// templates without a breakdown
+func (t *CreateTemplateDashboardTool) breakdownLabel(ctx context.Context, prometheusURL string, template promql.DashboardTemplate, jobMatcher []promql.LabelMatcher) (string, error) {
+	if len(template.Breakdown) == 0 {
+		return "", nil
+	}
+	selector := promql.InjectMatchers(fmt.Sprintf(`{__name__=~"%s"}`, strings.Join(template.Signature, "|")), jobMatcher)
+	for _, label := range template.Breakdown[:len(template.Breakdown)-1] {
+		values, err := t.promql.GetLabelValues(ctx, prometheusURL, label, []string{selector})
+		if err != nil {
+			return "", fmt.Errorf("failed to list %s values: %w", label, err)
+		}
+		if len(values) > 0 {
+			return label, nil
+		}
+	}
+	return template.Breakdown[len(template.Breakdown)-1], nil
+}
+
+// templateQueryVariable converts a template variable to a multi-value query
+// variable whose All option matches any value, refreshed with the time
+// range so it only lists values with recent series
+func templateQueryVariable(variable promql.TemplateVariable, matchers []promql.LabelMatcher) map[string]any {
+	query := variable.Query(matchers)
+	return map[string]any{
+		"name":       variable.Name,
+		"label":      variable.Label,
+		"type":       "query",
+		"query":      query,
+		"definition": query,
+		"refresh":    2,
+		"sort":       1,
+		"multi":      true,
+		"includeAll": true,
+		"allValue":   ".*",
+		"current":    map[string]any{"text": "All", "value": "$__all"},
+	}
+}
+
+// templateDashboardPanel converts a template panel to a dashboard panel,
+// scoping its queries with the job matcher
+func templateDashboardPanel(templatePanel promql.TemplatePanel, jobMatcher []promql.LabelMatcher) map[string]any {
+	targets := make([]any, len(templatePanel.Queries))
+	for i, query := range templatePanel.Queries {
+		target := map[string]any{
+			"refId": string(rune('A' + i)),
+			"expr":  promql.InjectMatchers(query.Expr, jobMatcher),
+		}
+		if query.Legend != "" {
+			target["legendFormat"] = query.Legend
+		}
+		if query.Instant {
+			target["instant"] = true
+		}
+		if templatePanel.Type == "table" {
+			target["format"] = "table"
+		}
+		targets[i] = target
+	}
+
+	panel := map[string]any{
+		"title":       templatePanel.Title,
+		"type":        templatePanel.Type,
+		"description": templatePanel.Description,
+		"targets":     targets,
+	}
+	switch templatePanel.Type {
+	case "timeseries":
+		panel["fieldConfig"] = extractFieldConfig(map[string]any{})
+	case "table":
+		// One row per series, with its labels as columns
+		panel["transformations"] = []any{organizeTransformation([]string{"Time", "__name__"}, nil, nil)}
+	}
+	defaults := fieldConfigDefaults(panel)
+	if templatePanel.Unit != "" {
+		defaults["unit"] = templatePanel.Unit
+	}
+	if templatePanel.Min != nil {
+		defaults["min"] = *templatePanel.Min
+	}
+	if templatePanel.Max != nil {
+		defaults["max"] = *templatePanel.Max
+	}
+	if len(templatePanel.Thresholds) > 0 {
+		defaults["thresholds"] = suggestedThresholds(templatePanel.Thresholds)
+		switch templatePanel.Type {
+		case "stat":
+			panel["options"] = map[string]any{
+				"reduceOptions": map[string]any{"calcs": []any{"lastNotNull"}, "fields": "", "values": false},
+				"colorMode":     "background",
+			}
+		case "timeseries":
+			defaults["custom"].(map[string]any)["thresholdsStyle"] = map[string]any{"mode": "dashed"}
+		}
+	}
+	return panel
+}
@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestCreateTemplateDashboardHandler(t *testing.T) {
+	newTool := func(fake *promqlfakes.FakePromQL) *CreateTemplateDashboardTool {
+		return &CreateTemplateDashboardTool{
+			logger:        zap.NewNop(),
+			promql:        fake,
+			grafanaConfig: &config.GrafanaConfig{},
+			environment:   &config.EnvironmentConfig{},
+		}
+	}
+
+	t.Run("synthetic monitoring scoped to a job", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesCalls(func(_ context.Context, _ string, label string, matchers []string) ([]string, error) {
+			switch label {
+			case "__name__":
+				return []string{"probe_success", "probe_duration_seconds", "probe_ssl_earliest_cert_expiry"}, nil
+			case "probe":
+				return []string{"Frankfurt", "Oregon"}, nil
+			}
+			return nil, nil
+		})
+
+		result, err := newTool(fake).CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"template":       "synthetic_monitoring",
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "checkout",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateTemplateDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if _, _, _, matchers := fake.GetLabelValuesArgsForCall(0); len(matchers) != 1 || matchers[0] != `{job="checkout"}` {
+			t.Errorf("Expected the metrics to be listed for the job, got %v", matchers)
+		}
+		if _, _, _, matchers := fake.GetLabelValuesArgsForCall(1); len(matchers) != 1 || matchers[0] != `{__name__=~"probe_success", job="checkout"}` {
+			t.Errorf("Expected the breakdown to be looked up on the job's signature metrics, got %v", matchers)
+		}
+		if response.Breakdown != "probe" {
+			t.Errorf("Expected the probe breakdown, got %s", response.Breakdown)
+		}
+		if response.Dashboard["title"] != "Synthetic monitoring - checkout" {
+			t.Errorf("Unexpected title %v", response.Dashboard["title"])
+		}
+
+		var expiry map[string]any
+		for _, raw := range response.Dashboard["panels"].([]any) {
+			panel := raw.(map[string]any)
+			if panel["title"] == "Days until certificate expiry" {
+				expiry = panel
+			}
+		}
+		if expiry == nil {
+			t.Fatal("Expected the certificate expiry panel")
+		}
+		target := expiry["targets"].([]any)[0].(map[string]any)
+		if target["expr"] != `min by (instance) ((probe_ssl_earliest_cert_expiry{job="checkout"} - time()) / 86400)` {
+			t.Errorf("Expected the expiry query scoped to the job, got %v", target["expr"])
+		}
+		defaults := expiry["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+		if defaults["unit"] != "d" || defaults["thresholds"] == nil {
+			t.Errorf("Expected days with thresholds, got %v", defaults)
+		}
+	})
+
+	t.Run("database ratios draw their thresholds", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"pg_up", "pg_stat_database_tup_fetched", "pg_stat_database_tup_returned"}, nil)
+
+		result, err := newTool(fake).CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"template":       "postgresql",
+			"prometheus_url": "http://prometheus.test:9090",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateTemplateDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if fake.GetLabelValuesCallCount() != 1 {
+			t.Errorf("Expected no breakdown lookup for a single breakdown label, got %d calls", fake.GetLabelValuesCallCount())
+		}
+		var ratio map[string]any
+		for _, raw := range response.Dashboard["panels"].([]any) {
+			panel := raw.(map[string]any)
+			if panel["title"] == "Rows fetched per row returned by datname" {
+				ratio = panel
+			}
+		}
+		if ratio == nil {
+			t.Fatalf("Expected the rows fetched per row returned panel, got %v", response.Dashboard["panels"])
+		}
+		defaults := ratio["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+		custom := defaults["custom"].(map[string]any)
+		if defaults["thresholds"] == nil || custom["thresholdsStyle"] == nil {
+			t.Errorf("Expected thresholds drawn on the timeseries, got %v", defaults)
+		}
+	})
+
+	t.Run("mesh dashboard chains namespace and workload variables", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"istio_requests_total", "istio_request_duration_milliseconds_bucket"}, nil)
+		tool := newTool(fake)
+		tool.environment = &config.EnvironmentConfig{Labels: map[string]string{"cluster": "prod-eu"}}
+
+		result, err := tool.CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"template":       "istio_mesh",
+			"prometheus_url": "http://prometheus.test:9090",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateTemplateDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		variables := response.Dashboard["templating"].(map[string]any)["list"].([]any)
+		var names []string
+		for _, raw := range variables {
+			names = append(names, raw.(map[string]any)["name"].(string))
+		}
+		if strings.Join(names, ",") != "cluster,namespace,workload" {
+			t.Fatalf("Expected the cluster constant before the chained variables, got %v", names)
+		}
+		workload := variables[2].(map[string]any)
+		if workload["query"] != `label_values(istio_requests_total{reporter="destination", destination_workload_namespace=~"$namespace", cluster="$cluster"}, destination_workload)` {
+			t.Errorf("Expected the workload variable to follow the namespace, got %v", workload["query"])
+		}
+		if workload["includeAll"] != true || workload["allValue"] != ".*" {
+			t.Errorf("Expected a multi-value variable with All, got %v", workload)
+		}
+
+		panel := response.Dashboard["panels"].([]any)[0].(map[string]any)
+		expr := panel["targets"].([]any)[0].(map[string]any)["expr"].(string)
+		if !strings.Contains(expr, `destination_workload=~"$workload"`) || !strings.Contains(expr, `cluster="$cluster"`) {
+			t.Errorf("Expected the panel filtered by the variables, got %s", expr)
+		}
+	})
+
+	t.Run("selects the template from the metrics", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"go_goroutines", "traefik_router_requests_total", "traefik_entrypoint_requests_total"}, nil)
+
+		result, err := newTool(fake).CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+			"job":            "traefik",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response CreateTemplateDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.Template != "traefik" || response.Dashboard["title"] != "Traefik - traefik" {
+			t.Errorf("Expected the traefik template over go_runtime, got %s (%v)", response.Template, response.Dashboard["title"])
+		}
+	})
+
+	t.Run("several templates apply", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"traefik_router_requests_total", "nginx_ingress_controller_requests"}, nil)
+
+		_, err := newTool(fake).CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://prometheus.test:9090",
+		})
+		if err == nil || !strings.Contains(err.Error(), "several templates apply") || !strings.Contains(err.Error(), "nginx_ingress, traefik") {
+			t.Errorf("Expected an ambiguous template error, got %v", err)
+		}
+	})
+
+	t.Run("template does not apply", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.GetLabelValuesReturns([]string{"http_requests_total"}, nil)
+
+		_, err := newTool(fake).CreateTemplateDashboardHandler(context.Background(), map[string]any{
+			"template":       "synthetic_monitoring",
+			"prometheus_url": "http://prometheus.test:9090",
+		})
+		if err == nil || !strings.Contains(err.Error(), "none of probe_success found") {
+			t.Errorf("Expected a template does not apply error, got %v", err)
+		}
+	})
+}
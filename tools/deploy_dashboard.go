@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	zap "go.uber.org/zap"
@@ -10,33 +11,50 @@ import (
 	server "github.com/inference-gateway/adk/server"
 
 	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
 	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
 )
 
 // DeployDashboardTool struct holds the tool with services
 type DeployDashboardTool struct {
 	logger        *zap.Logger
 	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
 	grafanaConfig *config.GrafanaConfig
+	alerting      *config.AlertingConfig
+	deployOptions deploy.Options
 }
 
 // NewDeployDashboardTool creates a new deploy_dashboard tool
-func NewDeployDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+func NewDeployDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, grafanaConfig *config.GrafanaConfig, alerting *config.AlertingConfig, deployOptions deploy.Options) server.Tool {
 	tool := &DeployDashboardTool{
 		logger:        logger,
 		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
 		grafanaConfig: grafanaConfig,
+		alerting:      alerting,
+		deployOptions: deployOptions,
 	}
-	return server.NewBasicTool(
+	return newValidatedTool(
 		"deploy_dashboard",
 		"Deploys a dashboard JSON to Grafana (Cloud or self-hosted)",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
+				"base_version": map[string]any{
+					"description": "Deployed version the dashboard JSON was derived from (the version returned by an earlier deploy); if the dashboard was edited since, the changes are merged three-way and conflicts reported instead of overwriting",
+					"type":        "integer",
+					"minimum":     1,
+				},
 				"dashboard_json": map[string]any{
 					"description": "The complete dashboard JSON object to deploy",
 					"type":        "object",
 				},
+				"folder": map[string]any{
+					"description": "Optional folder path such as \"Platform/Payments\"; missing folders are created (mutually exclusive with folder_uid)",
+					"type":        "string",
+				},
 				"folder_uid": map[string]any{
 					"description": "Optional folder UID where the dashboard should be deployed",
 					"type":        "string",
@@ -45,14 +63,31 @@ func NewDeployDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, graf
 					"description": "Grafana server URL (user provides in prompt or uses config default)",
 					"type":        "string",
 				},
+				"maintenance_labels": map[string]any{
+					"description": "Labels of the alerts to silence during the maintenance window (default: the alert rules linked to the dashboard)",
+					"type":        "object",
+				},
+				"maintenance_window": map[string]any{
+					"description": "Maintenance window the deploy is part of, as an RFC 3339 start and an end time or duration (e.g. \"2026-01-10T22:00:00Z/2h\"); overrides ALERTING_MAINTENANCE_WINDOW. Inside the window the affected alerts are silenced until the dashboard is saved",
+					"type":        "string",
+				},
 				"message": map[string]any{
 					"description": "Optional commit message describing the dashboard changes",
 					"type":        "string",
 				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
 				"overwrite": map[string]any{
 					"description": "Whether to overwrite an existing dashboard with the same UID (default true)",
 					"type":        "boolean",
 				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to deploy to, by slug or name (e.g. \"prod\"); resolves the URL and a stack token (mutually exclusive with grafana_url)",
+					"type":        "string",
+				},
 			},
 			"required": []string{"dashboard_json"},
 		},
@@ -65,9 +100,11 @@ func (t *DeployDashboardTool) DeployDashboardHandler(ctx context.Context, args m
 	span := startToolSpan(ctx, "deploy_dashboard")
 	defer span.End()
 
-	if t.grafanaConfig != nil && !t.grafanaConfig.DeployEnabled {
-		t.logger.Warn("Grafana deployment attempted but GRAFANA_DEPLOY_ENABLED=false")
-		return "", fmt.Errorf("grafana deployment is disabled - set GRAFANA_DEPLOY_ENABLED=true to enable dashboard deployments")
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	if _, err := deployer.ResolveTarget(ctx, grafanaURL, ""); errors.Is(err, deploy.ErrDeployDisabled) {
+		return "", err
 	}
 
 	dashboardJSON, ok := args["dashboard_json"].(map[string]any)
@@ -75,75 +112,36 @@ func (t *DeployDashboardTool) DeployDashboardHandler(ctx context.Context, args m
 		return "", fmt.Errorf("dashboard_json is required and must be a valid object")
 	}
 
-	var grafanaURL string
-	if urlParam, ok := args["grafana_url"].(string); ok && urlParam != "" {
-		grafanaURL = urlParam
-	} else if t.grafanaConfig != nil && t.grafanaConfig.URL != "" {
-		grafanaURL = t.grafanaConfig.URL
-	}
-
-	if grafanaURL == "" {
-		return "", fmt.Errorf("grafana_url must be provided either as a parameter or in configuration (GRAFANA_URL)")
-	}
-
-	var apiKey string
-	if t.grafanaConfig != nil && t.grafanaConfig.APIKey != "" {
-		apiKey = t.grafanaConfig.APIKey
-	}
-
-	if apiKey == "" {
-		return "", fmt.Errorf("grafana API key is required - set GRAFANA_API_KEY")
-	}
-
-	folderUID := ""
-	if uid, ok := args["folder_uid"].(string); ok {
-		folderUID = uid
-	}
+	folderUID, _ := args["folder_uid"].(string)
+	folderPath, _ := args["folder"].(string)
 
 	overwrite := true
 	if ow, ok := args["overwrite"].(bool); ok {
 		overwrite = ow
 	}
 
-	message := "Dashboard deployed via grafana-agent"
-	if msg, ok := args["message"].(string); ok && msg != "" {
-		message = msg
-	}
-
-	dashboard := grafana.Dashboard{
-		Dashboard: dashboardJSON,
-		FolderUID: folderUID,
-		Message:   message,
-		Overwrite: overwrite,
-	}
-
-	t.logger.Info("Deploying dashboard to Grafana",
-		zap.String("grafana_url", grafanaURL),
-		zap.String("folder_uid", folderUID),
-		zap.Bool("overwrite", overwrite))
-
-	resp, err := t.grafanaSvc.CreateDashboard(ctx, dashboard, grafanaURL, apiKey)
+	message, _ := args["message"].(string)
+	stack, _ := args["stack"].(string)
+	baseVersion, _ := toInt(args["base_version"])
+	maintenance, err := t.maintenance(args)
 	if err != nil {
-		return "", fmt.Errorf("failed to deploy dashboard to Grafana: %w", err)
+		return "", err
 	}
 
-	t.logger.Info("Dashboard deployed successfully",
-		zap.String("grafana_url", grafanaURL),
-		zap.String("dashboard_uid", resp.UID),
-		zap.Int("dashboard_id", resp.ID),
-		zap.String("dashboard_url", resp.URL))
-
-	result := map[string]any{
-		"status":      "deployed",
-		"grafana_url": grafanaURL,
-		"dashboard": map[string]any{
-			"id":      resp.ID,
-			"uid":     resp.UID,
-			"url":     resp.URL,
-			"version": resp.Version,
-			"slug":    resp.Slug,
-		},
-		"message": message,
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:   dashboardJSON,
+		GrafanaURL:  grafanaURL,
+		Stack:       stack,
+		FolderUID:   folderUID,
+		FolderPath:  folderPath,
+		Message:     message,
+		Overwrite:   overwrite,
+		BaseVersion: baseVersion,
+		Provenance:  dashboardProvenance(ctx, dashboardJSON),
+		Maintenance: maintenance,
+	})
+	if err != nil {
+		return "", err
 	}
 
 	jsonBytes, err := json.MarshalIndent(result, "", "  ")
@@ -153,3 +151,27 @@ func (t *DeployDashboardTool) DeployDashboardHandler(ctx context.Context, args m
 
 	return string(jsonBytes), nil
 }
+
+// maintenance resolves the maintenance window of a deploy from the arguments,
+// falling back to ALERTING_MAINTENANCE_WINDOW; nil means no window
+func (t *DeployDashboardTool) maintenance(args map[string]any) (*deploy.Maintenance, error) {
+	window, _ := args["maintenance_window"].(string)
+	if window == "" && t.alerting != nil {
+		window = t.alerting.MaintenanceWindow
+	}
+	if window == "" {
+		return nil, nil
+	}
+
+	maintenance, err := deploy.ParseMaintenanceWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	if labels, ok := args["maintenance_labels"].(map[string]any); ok {
+		maintenance.Labels = make(map[string]string, len(labels))
+		for name, value := range labels {
+			maintenance.Labels[name] = fmt.Sprint(value)
+		}
+	}
+	return maintenance, nil
+}
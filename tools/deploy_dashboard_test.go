@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	zap "go.uber.org/zap"
 
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
 	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
 	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
 )
 
@@ -21,7 +27,7 @@ func TestNewDeployDashboardTool(t *testing.T) {
 		APIKey:        "test-key",
 	}
 
-	tool := NewDeployDashboardTool(logger, mockGrafana, cfg)
+	tool := NewDeployDashboardTool(logger, mockGrafana, nil, cfg, &config.AlertingConfig{}, deploy.Options{})
 
 	if tool == nil {
 		t.Error("Expected non-nil tool")
@@ -291,11 +297,68 @@ func TestDeployDashboardHandler_WithFolderUID(t *testing.T) {
 	}
 }
 
+func TestDeployDashboardHandler_WithFolderPath(t *testing.T) {
+	var deployedFolder string
+	mockGrafana := &mockGrafanaService{
+		folders: []grafana.Folder{{UID: "platform-uid", Title: "Platform"}},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployedFolder = dashboard.FolderUID
+			return &grafana.DashboardResponse{ID: 1, UID: "test-uid"}, nil
+		},
+	}
+
+	tool := &DeployDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mockGrafana,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-api-key"},
+	}
+
+	result, err := tool.DeployDashboardHandler(context.Background(), map[string]any{
+		"dashboard_json": map[string]any{"title": "Test Dashboard"},
+		"folder":         "platform/Payments",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if deployedFolder != "folder-2" || response["folder_uid"] != "folder-2" {
+		t.Errorf("Expected dashboard in the created Payments folder, got %s / %v", deployedFolder, response["folder_uid"])
+	}
+	created, _ := response["created_folders"].([]any)
+	if len(created) != 1 || created[0] != "platform/Payments" {
+		t.Errorf("Expected only platform/Payments to be created, got %v", response["created_folders"])
+	}
+	if mockGrafana.folders[1].ParentUID != "platform-uid" {
+		t.Errorf("Expected Payments to be nested under Platform, got parent %q", mockGrafana.folders[1].ParentUID)
+	}
+}
+
+func TestDeployDashboardHandler_FolderConflict(t *testing.T) {
+	tool := &DeployDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    &mockGrafanaService{},
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-api-key"},
+	}
+
+	_, err := tool.DeployDashboardHandler(context.Background(), map[string]any{
+		"dashboard_json": map[string]any{"title": "Test Dashboard"},
+		"folder":         "Platform",
+		"folder_uid":     "abc",
+	})
+	if !errors.Is(err, deploy.ErrFolderConflict) {
+		t.Errorf("Expected folder conflict error, got %v", err)
+	}
+}
+
 func TestDeployDashboardHandler_WithCustomMessage(t *testing.T) {
 	logger := zap.NewNop()
 	mockGrafana := &mockGrafanaService{
 		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
-			if dashboard.Message != "Custom deployment message" {
+			if !strings.HasPrefix(dashboard.Message, "Custom deployment message\n") {
 				t.Errorf("Expected message 'Custom deployment message', got %s", dashboard.Message)
 			}
 			return &grafana.DashboardResponse{
@@ -330,6 +393,59 @@ func TestDeployDashboardHandler_WithCustomMessage(t *testing.T) {
 	}
 }
 
+func TestDeployDashboardHandler_Provenance(t *testing.T) {
+	var message string
+	mockGrafana := &mockGrafanaService{
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			message = dashboard.Message
+			return &grafana.DashboardResponse{ID: 1, UID: "checkout"}, nil
+		},
+	}
+	tool := &DeployDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mockGrafana,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-api-key"},
+		deployOptions: deploy.Options{AgentVersion: "grafana-agent 1.4.0"},
+	}
+
+	prompt := "Add   an error-rate panel\nto the checkout dashboard"
+	ctx := context.WithValue(context.Background(), server.TaskContextKey, &types.Task{
+		ID: "task-42",
+		History: []types.Message{
+			{Role: types.RoleUser, Parts: []types.Part{{Text: &prompt}}},
+		},
+	})
+
+	result, err := tool.DeployDashboardHandler(ctx, map[string]any{
+		"dashboard_json": map[string]any{
+			"title": "Checkout",
+			"panels": []any{
+				map[string]any{"targets": []any{
+					map[string]any{"expr": `sum(rate(http_requests_total{status=~"5.."}[5m])) / sum(rate(http_requests_total[5m]))`},
+					map[string]any{"expr": "up"},
+				}},
+			},
+		},
+		"message": "Add error rate",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := "Add error rate\n\nPrompt: Add an error-rate panel to the checkout dashboard\nMetrics: http_requests_total, up\nAgent: grafana-agent 1.4.0\nRequest: task-42"
+	if message != want {
+		t.Errorf("unexpected version message:\n%s", message)
+	}
+
+	var response deploy.Result
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if response.Message != "Add error rate" || response.Provenance == nil || response.Provenance.RequestID != "task-42" {
+		t.Errorf("unexpected result %+v", response)
+	}
+}
+
 func TestDeployDashboardHandler_WithOverwriteFalse(t *testing.T) {
 	logger := zap.NewNop()
 	mockGrafana := &mockGrafanaService{
@@ -404,3 +520,41 @@ func TestDeployDashboardHandler_DeploymentError(t *testing.T) {
 		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
 	}
 }
+
+func TestDeployDashboardHandler_MaintenanceWindow(t *testing.T) {
+	mockGrafana := &mockGrafanaService{}
+	now := time.Now().UTC()
+	tool := &DeployDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mockGrafana,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-api-key"},
+		alerting:      &config.AlertingConfig{MaintenanceWindow: now.Add(-time.Hour).Format(time.RFC3339) + "/2h"},
+	}
+
+	result, err := tool.DeployDashboardHandler(context.Background(), map[string]any{
+		"dashboard_json":     map[string]any{"title": "Checkout"},
+		"maintenance_labels": map[string]any{"service": "checkout"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response deploy.Result
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse result: %v", err)
+	}
+	if len(mockGrafana.silences) != 1 || mockGrafana.silences[0].Matchers[0].Name != "service" {
+		t.Errorf("Expected a silence for service=checkout, got %+v", mockGrafana.silences)
+	}
+	if response.Silence == nil || !response.Silence.Expired || len(mockGrafana.expiredSilences) != 1 {
+		t.Errorf("Expected the silence to be expired after the deploy, got %+v", response.Silence)
+	}
+
+	_, err = tool.DeployDashboardHandler(context.Background(), map[string]any{
+		"dashboard_json":     map[string]any{"title": "Checkout"},
+		"maintenance_window": "tonight",
+	})
+	if !errors.Is(err, deploy.ErrInvalidMaintenanceWindow) {
+		t.Errorf("Expected ErrInvalidMaintenanceWindow, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// DetectExportersTool struct holds the tool with services
+type DetectExportersTool struct {
+	logger *zap.Logger
+	promql promql.PromQL
+}
+
+// NewDetectExportersTool creates a new detect_exporters tool
+func NewDetectExportersTool(logger *zap.Logger, promql promql.PromQL) server.Tool {
+	tool := &DetectExportersTool{
+		logger: logger,
+		promql: promql,
+	}
+	return newValidatedTool(
+		"detect_exporters",
+		"Identifies well-known exporters (node_exporter, windows_exporter, cadvisor, blackbox, postgres_exporter, mysqld_exporter, redis_exporter, istio, ingress-nginx, traefik, haproxy, kafka_exporter, rabbitmq, nats_exporter, dcgm_exporter) and the dashboard templates that apply to each",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to inspect",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.DetectExportersHandler,
+	)
+}
+
+// DetectExportersResponse represents the detected exporters
+type DetectExportersResponse struct {
+	PrometheusURL string                    `json:"prometheus_url"`
+	TotalMetrics  int                       `json:"total_metrics"`
+	Jobs          []string                  `json:"jobs"`
+	Exporters     []promql.DetectedExporter `json:"exporters"`
+}
+
+// DetectExportersHandler handles the detect_exporters tool execution
+func (t *DetectExportersTool) DetectExportersHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "detect_exporters")
+	defer span.End()
+
+	t.logger.Info("detecting exporters")
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+
+	metricNames, err := t.promql.GetLabelValues(ctx, prometheusURL, "__name__", nil)
+	if err != nil {
+		t.logger.Error("failed to list metric names",
+			zap.String("prometheus_url", prometheusURL),
+			zap.Error(err))
+		return "", fmt.Errorf("failed to list metrics: %w", err)
+	}
+
+	upJobs, err := t.promql.GetLabelValues(ctx, prometheusURL, "job", []string{"up"})
+	if err != nil {
+		t.logger.Warn("failed to list up jobs, detecting from metric names only",
+			zap.String("prometheus_url", prometheusURL),
+			zap.Error(err))
+		upJobs = nil
+	}
+
+	exporters := promql.DetectExporters(metricNames, upJobs)
+
+	// Attribute exporters to the jobs actually exporting their signature metrics
+	for i := range exporters {
+		if len(exporters[i].SignatureMetrics) == 0 {
+			continue
+		}
+		selector := fmt.Sprintf(`{__name__=~"%s"}`, strings.Join(exporters[i].SignatureMetrics, "|"))
+		jobs, err := t.promql.GetLabelValues(ctx, prometheusURL, "job", []string{selector})
+		if err != nil {
+			t.logger.Warn("failed to resolve exporter jobs",
+				zap.String("exporter", exporters[i].Exporter),
+				zap.Error(err))
+			continue
+		}
+		exporters[i].Jobs = mergeSorted(exporters[i].Jobs, jobs)
+	}
+
+	if exporters == nil {
+		exporters = []promql.DetectedExporter{}
+	}
+	if upJobs == nil {
+		upJobs = []string{}
+	}
+
+	response := DetectExportersResponse{
+		PrometheusURL: prometheusURL,
+		TotalMetrics:  len(metricNames),
+		Jobs:          upJobs,
+		Exporters:     exporters,
+	}
+
+	t.logger.Info("detected exporters",
+		zap.String("prometheus_url", prometheusURL),
+		zap.Int("count", len(exporters)))
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonData), nil
+}
+
+// mergeSorted returns the sorted union of two string lists
+func mergeSorted(a, b []string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, list := range [][]string{a, b} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				merged = append(merged, v)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestNewDetectExportersTool(t *testing.T) {
+	tool := NewDetectExportersTool(zap.NewNop(), &promqlfakes.FakePromQL{})
+
+	if tool == nil {
+		t.Error("Expected non-nil tool")
+	}
+}
+
+func TestDetectExportersHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          map[string]any
+		setupMock     func(*promqlfakes.FakePromQL)
+		wantErr       bool
+		expectedError string
+		validateFunc  func(t *testing.T, response DetectExportersResponse)
+	}{
+		{
+			name: "detects exporters and their jobs",
+			args: map[string]any{"prometheus_url": "http://prometheus.test:9090"},
+			setupMock: func(fake *promqlfakes.FakePromQL) {
+				fake.GetLabelValuesCalls(func(_ context.Context, _ string, label string, matchers []string) ([]string, error) {
+					switch {
+					case label == "__name__":
+						return []string{"node_cpu_seconds_total", "pg_up", "up"}, nil
+					case len(matchers) == 1 && matchers[0] == "up":
+						return []string{"hosts", "postgres"}, nil
+					case len(matchers) == 1 && matchers[0] == `{__name__=~"node_cpu_seconds_total"}`:
+						return []string{"hosts"}, nil
+					default:
+						return []string{}, nil
+					}
+				})
+			},
+			validateFunc: func(t *testing.T, response DetectExportersResponse) {
+				if len(response.Exporters) != 2 {
+					t.Fatalf("Expected 2 exporters, got %+v", response.Exporters)
+				}
+				for _, e := range response.Exporters {
+					switch e.Exporter {
+					case "node_exporter":
+						if len(e.Jobs) != 1 || e.Jobs[0] != "hosts" {
+							t.Errorf("Expected node_exporter job hosts, got %v", e.Jobs)
+						}
+					case "postgres_exporter":
+						if len(e.Jobs) != 1 || e.Jobs[0] != "postgres" {
+							t.Errorf("Expected postgres_exporter job postgres, got %v", e.Jobs)
+						}
+					default:
+						t.Errorf("Unexpected exporter %s", e.Exporter)
+					}
+				}
+				if response.TotalMetrics != 3 {
+					t.Errorf("Expected 3 total metrics, got %d", response.TotalMetrics)
+				}
+			},
+		},
+		{
+			name:          "missing prometheus_url",
+			args:          map[string]any{},
+			setupMock:     func(fake *promqlfakes.FakePromQL) {},
+			wantErr:       true,
+			expectedError: "prometheus_url is required and must be a string",
+		},
+		{
+			name: "metric listing fails",
+			args: map[string]any{"prometheus_url": "http://prometheus.test:9090"},
+			setupMock: func(fake *promqlfakes.FakePromQL) {
+				fake.GetLabelValuesReturns(nil, errors.New("connection refused"))
+			},
+			wantErr:       true,
+			expectedError: "failed to list metrics: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakePromQL := &promqlfakes.FakePromQL{}
+			tt.setupMock(fakePromQL)
+
+			tool := &DetectExportersTool{logger: zap.NewNop(), promql: fakePromQL}
+
+			result, err := tool.DetectExportersHandler(context.Background(), tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				} else if tt.expectedError != "" && err.Error() != tt.expectedError {
+					t.Errorf("Expected error '%s', got '%s'", tt.expectedError, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var response DetectExportersResponse
+			if err := json.Unmarshal([]byte(result), &response); err != nil {
+				t.Fatalf("Expected valid JSON result, got error: %v", err)
+			}
+			tt.validateFunc(t, response)
+		})
+	}
+}
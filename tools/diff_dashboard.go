@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// DiffDashboardTool struct holds the tool with services
+type DiffDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewDiffDashboardTool creates a new diff_dashboard tool
+func NewDiffDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &DiffDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"diff_dashboard",
+		"Compares a dashboard JSON with the version deployed in Grafana and lists the semantic changes, ignoring volatile fields",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_json": map[string]any{
+					"description": "The dashboard JSON to compare with the deployed version",
+					"type":        "object",
+				},
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard (defaults to the uid of dashboard_json)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+			},
+			"required": []string{"dashboard_json"},
+		},
+		tool.DiffDashboardHandler,
+	)
+}
+
+// DiffDashboardResponse lists the changes from the deployed dashboard to the given one
+type DiffDashboardResponse struct {
+	DashboardUID string        `json:"dashboard_uid"`
+	GrafanaURL   string        `json:"grafana_url"`
+	Identical    bool          `json:"identical"`
+	Changes      []diff.Change `json:"changes"`
+	Total        int           `json:"total"`
+}
+
+// DiffDashboardHandler handles the diff_dashboard tool execution
+func (t *DiffDashboardTool) DiffDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "diff_dashboard")
+	defer span.End()
+
+	dashboardJSON, ok := args["dashboard_json"].(map[string]any)
+	if !ok || len(dashboardJSON) == 0 {
+		return "", fmt.Errorf("dashboard_json is required and must be a valid object")
+	}
+
+	dashboardUID, _ := args["dashboard_uid"].(string)
+	if strings.TrimSpace(dashboardUID) == "" {
+		dashboardUID, _ = dashboardJSON["uid"].(string)
+	}
+	if strings.TrimSpace(dashboardUID) == "" {
+		return "", fmt.Errorf("dashboard_uid is required when dashboard_json has no uid")
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	deployed, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	var current map[string]any
+	if deployed != nil {
+		current = deployed.Dashboard
+	}
+
+	changes := diff.Dashboards(current, dashboardJSON)
+	if changes == nil {
+		changes = []diff.Change{}
+	}
+
+	jsonBytes, err := json.MarshalIndent(DiffDashboardResponse{
+		DashboardUID: dashboardUID,
+		GrafanaURL:   target.GrafanaURL,
+		Identical:    len(changes) == 0,
+		Changes:      changes,
+		Total:        len(changes),
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
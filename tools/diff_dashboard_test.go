@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func TestDiffDashboardHandler(t *testing.T) {
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: map[string]any{
+				"id": 12.0, "uid": uid, "version": 4.0, "title": "Checkout",
+			}}, nil
+		},
+	}
+	tool := &DiffDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	tests := []struct {
+		name      string
+		dashboard map[string]any
+		identical bool
+		total     int
+	}{
+		{name: "volatile fields are ignored", dashboard: map[string]any{"uid": "checkout", "title": "Checkout", "version": 1}, identical: true},
+		{name: "semantic change", dashboard: map[string]any{"uid": "checkout", "title": "Checkout v2"}, total: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.DiffDashboardHandler(context.Background(), map[string]any{"dashboard_json": tt.dashboard})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var response DiffDashboardResponse
+			if err := json.Unmarshal([]byte(result), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Identical != tt.identical || response.Total != tt.total {
+				t.Errorf("unexpected diff %+v", response)
+			}
+		})
+	}
+
+	if _, err := tool.DiffDashboardHandler(context.Background(), map[string]any{"dashboard_json": map[string]any{"title": "x"}}); err == nil {
+		t.Error("expected an error without a dashboard uid")
+	}
+}
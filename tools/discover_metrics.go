@@ -3,7 +3,12 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
 
 	zap "go.uber.org/zap"
 
@@ -12,6 +17,12 @@ import (
 	promql "github.com/inference-gateway/grafana-agent/internal/promql"
 )
 
+// Pagination defaults keep discovery responses small enough for LLM context windows
+const (
+	defaultDiscoverLimit = 100
+	maxDiscoverLimit     = 1000
+)
+
 // DiscoverMetricsTool struct holds the tool with services
 type DiscoverMetricsTool struct {
 	logger *zap.Logger
@@ -24,12 +35,24 @@ func NewDiscoverMetricsTool(logger *zap.Logger, promql promql.PromQL) server.Too
 		logger: logger,
 		promql: promql,
 	}
-	return server.NewBasicTool(
+	return newValidatedTool(
 		"discover_metrics",
-		"Discovers available metrics from a Prometheus endpoint with optional filtering",
+		"Discovers available metrics from a Prometheus endpoint, or from a service's /metrics endpoint before it is scraped, with optional filtering",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
+				"group_by_prefix": map[string]any{
+					"description": "Cluster the matching metrics by naming prefix (http_, go_, process_, ...) with per-group counts",
+					"type":        "boolean",
+				},
+				"limit": map[string]any{
+					"description": "Maximum number of metrics to return (default 100, max 1000)",
+					"type":        "integer",
+				},
+				"metrics_url": map[string]any{
+					"description": "A service or exporter /metrics URL to discover metrics from directly, when no Prometheus server scrapes it yet (instead of prometheus_url)",
+					"type":        "string",
+				},
 				"metric_type": map[string]any{
 					"description": "Optional metric type filter (counter, gauge, histogram, summary)",
 					"enum":        []string{"counter", "gauge", "histogram", "summary"},
@@ -39,12 +62,28 @@ func NewDiscoverMetricsTool(logger *zap.Logger, promql promql.PromQL) server.Too
 					"description": "Optional regex pattern to filter metrics by name",
 					"type":        "string",
 				},
+				"substring_match": map[string]any{
+					"description": "Treat name_pattern as a literal substring instead of a regex",
+					"type":        "boolean",
+				},
+				"offset": map[string]any{
+					"description": "Number of metrics to skip before returning results (default 0)",
+					"type":        "integer",
+				},
 				"prometheus_url": map[string]any{
-					"description": "Prometheus server URL to discover metrics from",
+					"description": "Prometheus server URL to discover metrics from (or set metrics_url)",
+					"type":        "string",
+				},
+				"selector": map[string]any{
+					"description": "Optional series selector scoping discovery, e.g. {job=\"checkout\"} or {namespace=\"prod\"}",
+					"type":        "string",
+				},
+				"sort_by": map[string]any{
+					"description": "Sort order: name (default), type, or series_count (descending)",
+					"enum":        []string{"name", "type", "series_count"},
 					"type":        "string",
 				},
 			},
-			"required": []string{"prometheus_url"},
 		},
 		tool.DiscoverMetricsHandler,
 	)
@@ -52,16 +91,45 @@ func NewDiscoverMetricsTool(logger *zap.Logger, promql promql.PromQL) server.Too
 
 // DiscoverMetricsResponse represents the response from metric discovery
 type DiscoverMetricsResponse struct {
-	PrometheusURL string              `json:"prometheus_url"`
+	PrometheusURL string              `json:"prometheus_url,omitempty"`
+	MetricsURL    string              `json:"metrics_url,omitempty"`
 	TotalMetrics  int                 `json:"total_metrics"`
 	Metrics       []promql.MetricInfo `json:"metrics"`
 	Filters       FilterInfo          `json:"filters,omitempty"`
+	Pagination    PaginationInfo      `json:"pagination"`
+	Groups        []MetricGroup       `json:"groups,omitempty"`
+	// Templates names the built-in dashboard templates create_template_dashboard
+	// selects for the metrics found
+	Templates []string `json:"templates,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// maxGroupExamples caps the example metric names listed per prefix group
+const maxGroupExamples = 5
+
+// MetricGroup clusters the metrics sharing a subsystem prefix
+type MetricGroup struct {
+	Prefix   string         `json:"prefix"`
+	Count    int            `json:"count"`
+	Types    map[string]int `json:"types"`
+	Examples []string       `json:"examples"`
+}
+
+// PaginationInfo describes which slice of the matching metrics was returned
+type PaginationInfo struct {
+	SortBy     string `json:"sort_by"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	Returned   int    `json:"returned"`
+	Truncated  bool   `json:"truncated"`
+	NextOffset int    `json:"next_offset,omitempty"`
 }
 
 // FilterInfo contains information about applied filters
 type FilterInfo struct {
 	NamePattern string `json:"name_pattern,omitempty"`
 	MetricType  string `json:"metric_type,omitempty"`
+	Selector    string `json:"selector,omitempty"`
 }
 
 // DiscoverMetricsHandler handles the discover_metrics tool execution
@@ -71,9 +139,10 @@ func (t *DiscoverMetricsTool) DiscoverMetricsHandler(ctx context.Context, args m
 
 	t.logger.Info("discovering metrics")
 
-	prometheusURL, ok := args["prometheus_url"].(string)
-	if !ok || prometheusURL == "" {
-		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	prometheusURL, _ := args["prometheus_url"].(string)
+	metricsURL, _ := args["metrics_url"].(string)
+	if (prometheusURL == "") == (metricsURL == "") {
+		return "", fmt.Errorf("exactly one of prometheus_url or metrics_url is required")
 	}
 
 	namePattern := ""
@@ -81,6 +150,15 @@ func (t *DiscoverMetricsTool) DiscoverMetricsHandler(ctx context.Context, args m
 		namePattern = pattern
 	}
 
+	substringMatch, _ := args["substring_match"].(bool)
+	pattern, err := compileNamePattern(namePattern, substringMatch)
+	if err != nil {
+		t.logger.Warn("invalid name pattern",
+			zap.String("name_pattern", namePattern),
+			zap.Error(err))
+		return "", err
+	}
+
 	metricTypeStr := ""
 	var metricType promql.MetricType
 	if mt, ok := args["metric_type"].(string); ok {
@@ -99,34 +177,119 @@ func (t *DiscoverMetricsTool) DiscoverMetricsHandler(ctx context.Context, args m
 		}
 	}
 
+	selector := ""
+	if sel, ok := args["selector"].(string); ok {
+		selector = strings.TrimSpace(sel)
+	}
+
+	limit := defaultDiscoverLimit
+	if l, ok := args["limit"].(float64); ok {
+		if l < 1 || l > maxDiscoverLimit {
+			return "", fmt.Errorf("limit must be between 1 and %d", maxDiscoverLimit)
+		}
+		limit = int(l)
+	}
+
+	offset := 0
+	if o, ok := args["offset"].(float64); ok {
+		if o < 0 {
+			return "", fmt.Errorf("offset must not be negative")
+		}
+		offset = int(o)
+	}
+
+	sortBy := "name"
+	if sb, ok := args["sort_by"].(string); ok && sb != "" {
+		switch sb {
+		case "name", "type", "series_count":
+			sortBy = sb
+		default:
+			return "", fmt.Errorf("unsupported sort_by %q (expected name, type or series_count)", sb)
+		}
+	}
+
+	if metricsURL != "" && selector != "" {
+		return "", fmt.Errorf("selector needs prometheus_url: series scraped from metrics_url carry no target labels yet")
+	}
+
 	t.logger.Debug("discovering metrics with filters",
 		zap.String("prometheus_url", prometheusURL),
+		zap.String("metrics_url", metricsURL),
 		zap.String("name_pattern", namePattern),
-		zap.String("metric_type", metricTypeStr))
+		zap.String("metric_type", metricTypeStr),
+		zap.String("selector", selector))
 
-	metrics, err := t.promql.DiscoverMetrics(ctx, prometheusURL, namePattern, metricType)
-	if err != nil {
-		t.logger.Error("failed to discover metrics",
-			zap.String("prometheus_url", prometheusURL),
-			zap.Error(err))
-		return "", fmt.Errorf("failed to discover metrics: %w", err)
+	var metrics []promql.MetricInfo
+	if metricsURL != "" {
+		// The exposition carries the types and series directly, so the
+		// same typing heuristics apply without a Prometheus server
+		families, err := t.promql.ScrapeTarget(ctx, metricsURL)
+		if err != nil {
+			t.logger.Error("failed to scrape metrics endpoint",
+				zap.String("metrics_url", metricsURL),
+				zap.Error(err))
+			return "", fmt.Errorf("failed to discover metrics: %w", err)
+		}
+		metrics = promql.DiscoverScrapedMetrics(families, pattern, metricType)
+	} else {
+		metrics, err = t.promql.DiscoverMetrics(ctx, prometheusURL, pattern, metricType, selector)
+		if err != nil {
+			t.logger.Error("failed to discover metrics",
+				zap.String("prometheus_url", prometheusURL),
+				zap.Error(err))
+			return "", fmt.Errorf("failed to discover metrics: %w", err)
+		}
 	}
 
 	response := DiscoverMetricsResponse{
 		PrometheusURL: prometheusURL,
+		MetricsURL:    metricsURL,
 		TotalMetrics:  len(metrics),
-		Metrics:       metrics,
 	}
 
-	if namePattern != "" || metricTypeStr != "" {
+	// Scraped metrics already carry their series count
+	if sortBy == "series_count" && metricsURL == "" {
+		counts, err := t.promql.GetSeriesCounts(ctx, prometheusURL)
+		if err != nil {
+			t.logger.Warn("failed to count series, sorting by name",
+				zap.String("prometheus_url", prometheusURL),
+				zap.Error(err))
+			response.Warnings = append(response.Warnings, fmt.Sprintf("series counts unavailable, sorted by name instead: %v", err))
+			sortBy = "name"
+		} else {
+			for i := range metrics {
+				metrics[i].SeriesCount = counts[metrics[i].Name]
+			}
+		}
+	}
+	sortMetrics(metrics, sortBy)
+
+	if groupByPrefix, ok := args["group_by_prefix"].(bool); ok && groupByPrefix {
+		response.Groups = groupMetricsByPrefix(metrics)
+	}
+
+	names := make([]string, len(metrics))
+	for i, metric := range metrics {
+		names[i] = metric.Name
+	}
+	for _, template := range promql.SelectTemplates(names) {
+		response.Templates = append(response.Templates, template.Name)
+	}
+
+	response.Metrics, response.Pagination = paginateMetrics(metrics, offset, limit)
+	response.Pagination.SortBy = sortBy
+
+	if namePattern != "" || metricTypeStr != "" || selector != "" {
 		response.Filters = FilterInfo{
 			NamePattern: namePattern,
 			MetricType:  metricTypeStr,
+			Selector:    selector,
 		}
 	}
 
 	t.logger.Info("discovered metrics",
 		zap.String("prometheus_url", prometheusURL),
+		zap.String("metrics_url", metricsURL),
 		zap.Int("total", len(metrics)))
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
@@ -136,3 +299,159 @@ func (t *DiscoverMetricsTool) DiscoverMetricsHandler(ctx context.Context, args m
 
 	return string(jsonData), nil
 }
+
+// PatternError describes an invalid name_pattern regex and where it breaks
+type PatternError struct {
+	Pattern  string `json:"pattern"`
+	Position int    `json:"position"`
+	Reason   string `json:"reason"`
+}
+
+// Error implements the error interface
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid name_pattern %q at position %d: %s (escape regex metacharacters or set substring_match to match literally)",
+		e.Pattern, e.Position, e.Reason)
+}
+
+// compileNamePattern compiles the name filter up front so an invalid regex is
+// reported before any request reaches Prometheus. Substring matching quotes
+// the pattern so it is matched literally.
+func compileNamePattern(pattern string, substring bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if substring {
+		return regexp.Compile(regexp.QuoteMeta(pattern))
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err == nil {
+		return re, nil
+	}
+
+	patternErr := &PatternError{Pattern: pattern, Reason: err.Error()}
+	var syntaxErr *syntax.Error
+	if errors.As(err, &syntaxErr) {
+		patternErr.Reason = string(syntaxErr.Code)
+		patternErr.Position = patternErrorPosition(pattern, syntaxErr)
+	}
+	return nil, patternErr
+}
+
+// patternErrorPosition locates the byte offset a regex syntax error refers to
+func patternErrorPosition(pattern string, syntaxErr *syntax.Error) int {
+	switch syntaxErr.Code {
+	case syntax.ErrMissingParen, syntax.ErrUnexpectedParen:
+		var open []int
+		inClass := false
+		for i := 0; i < len(pattern); i++ {
+			switch c := pattern[i]; {
+			case c == '\\':
+				i++
+			case inClass:
+				inClass = c != ']'
+			case c == '[':
+				inClass = true
+			case c == '(':
+				open = append(open, i)
+			case c == ')':
+				if len(open) == 0 {
+					return i
+				}
+				open = open[:len(open)-1]
+			}
+		}
+		if len(open) > 0 {
+			return open[len(open)-1]
+		}
+	}
+
+	if syntaxErr.Expr != pattern {
+		if idx := strings.Index(pattern, syntaxErr.Expr); idx >= 0 {
+			return idx
+		}
+	}
+	return 0
+}
+
+// sortMetrics orders metrics by name, by type then name, or by descending series count
+func sortMetrics(metrics []promql.MetricInfo, sortBy string) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		switch sortBy {
+		case "type":
+			if metrics[i].Type != metrics[j].Type {
+				return metrics[i].Type < metrics[j].Type
+			}
+		case "series_count":
+			if metrics[i].SeriesCount != metrics[j].SeriesCount {
+				return metrics[i].SeriesCount > metrics[j].SeriesCount
+			}
+		}
+		return metrics[i].Name < metrics[j].Name
+	})
+}
+
+// paginateMetrics returns the requested page of metrics along with truncation metadata
+func paginateMetrics(metrics []promql.MetricInfo, offset, limit int) ([]promql.MetricInfo, PaginationInfo) {
+	info := PaginationInfo{Offset: offset, Limit: limit}
+
+	start := min(offset, len(metrics))
+	end := min(start+limit, len(metrics))
+	page := metrics[start:end]
+
+	info.Returned = len(page)
+	if end < len(metrics) {
+		info.Truncated = true
+		info.NextOffset = end
+	}
+	if page == nil {
+		page = []promql.MetricInfo{}
+	}
+
+	return page, info
+}
+
+// metricPrefix returns the subsystem prefix of a metric name, e.g. "http_"
+// for http_requests_total; names without an underscore form their own group
+func metricPrefix(name string) string {
+	if idx := strings.IndexAny(name, "_:"); idx > 0 {
+		return name[:idx+1]
+	}
+	return name
+}
+
+// groupMetricsByPrefix clusters metrics by naming prefix, largest groups first
+func groupMetricsByPrefix(metrics []promql.MetricInfo) []MetricGroup {
+	index := map[string]int{}
+	var groups []MetricGroup
+
+	for _, m := range metrics {
+		prefix := metricPrefix(m.Name)
+		i, ok := index[prefix]
+		if !ok {
+			i = len(groups)
+			index[prefix] = i
+			groups = append(groups, MetricGroup{Prefix: prefix, Types: map[string]int{}, Examples: []string{}})
+		}
+
+		g := &groups[i]
+		g.Count++
+		mType := string(m.Type)
+		if mType == "" {
+			mType = string(promql.MetricTypeUnknown)
+		}
+		g.Types[mType]++
+		if len(g.Examples) < maxGroupExamples {
+			g.Examples = append(g.Examples, m.Name)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Prefix < groups[j].Prefix
+	})
+
+	return groups
+}
@@ -8,6 +8,7 @@ import (
 
 	zap "go.uber.org/zap"
 
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
 	promql "github.com/inference-gateway/grafana-agent/internal/promql"
 	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
 )
@@ -173,7 +174,7 @@ func TestDiscoverMetricsHandler(t *testing.T) {
 			},
 			setupMock:     func(fake *promqlfakes.FakePromQL) {},
 			wantErr:       true,
-			expectedError: "prometheus_url is required and must be a string",
+			expectedError: "exactly one of prometheus_url or metrics_url is required",
 		},
 		{
 			name: "empty prometheus_url",
@@ -182,7 +183,7 @@ func TestDiscoverMetricsHandler(t *testing.T) {
 			},
 			setupMock:     func(fake *promqlfakes.FakePromQL) {},
 			wantErr:       true,
-			expectedError: "prometheus_url is required and must be a string",
+			expectedError: "exactly one of prometheus_url or metrics_url is required",
 		},
 		{
 			name: "prometheus connection error",
@@ -308,3 +309,331 @@ func TestDiscoverMetricsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscoverMetricsHandler_Pagination(t *testing.T) {
+	metrics := []promql.MetricInfo{
+		{Name: "up", Type: promql.MetricTypeGauge},
+		{Name: "http_requests_total", Type: promql.MetricTypeCounter},
+		{Name: "go_goroutines", Type: promql.MetricTypeGauge},
+	}
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		seriesErr     error
+		expectedNames []string
+		expected      PaginationInfo
+		wantWarning   bool
+		wantErr       bool
+	}{
+		{
+			name:          "sorted by name with limit",
+			args:          map[string]any{"limit": float64(2)},
+			expectedNames: []string{"go_goroutines", "http_requests_total"},
+			expected:      PaginationInfo{SortBy: "name", Limit: 2, Returned: 2, Truncated: true, NextOffset: 2},
+		},
+		{
+			name:          "second page",
+			args:          map[string]any{"limit": float64(2), "offset": float64(2)},
+			expectedNames: []string{"up"},
+			expected:      PaginationInfo{SortBy: "name", Offset: 2, Limit: 2, Returned: 1},
+		},
+		{
+			name:          "sorted by type",
+			args:          map[string]any{"sort_by": "type"},
+			expectedNames: []string{"http_requests_total", "go_goroutines", "up"},
+			expected:      PaginationInfo{SortBy: "type", Limit: 100, Returned: 3},
+		},
+		{
+			name:          "sorted by series count",
+			args:          map[string]any{"sort_by": "series_count"},
+			expectedNames: []string{"http_requests_total", "up", "go_goroutines"},
+			expected:      PaginationInfo{SortBy: "series_count", Limit: 100, Returned: 3},
+		},
+		{
+			name:          "series count unavailable falls back to name",
+			args:          map[string]any{"sort_by": "series_count"},
+			seriesErr:     errors.New("query timed out"),
+			expectedNames: []string{"go_goroutines", "http_requests_total", "up"},
+			expected:      PaginationInfo{SortBy: "name", Limit: 100, Returned: 3},
+			wantWarning:   true,
+		},
+		{
+			name:     "offset beyond results",
+			args:     map[string]any{"offset": float64(10)},
+			wantErr:  false,
+			expected: PaginationInfo{SortBy: "name", Offset: 10, Limit: 100},
+		},
+		{
+			name:    "invalid limit",
+			args:    map[string]any{"limit": float64(0)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid sort_by",
+			args:    map[string]any{"sort_by": "size"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakePromQL := &promqlfakes.FakePromQL{}
+			fakePromQL.DiscoverMetricsReturns(append([]promql.MetricInfo(nil), metrics...), nil)
+			fakePromQL.GetSeriesCountsReturns(map[string]int{"up": 12, "http_requests_total": 40, "go_goroutines": 3}, tt.seriesErr)
+
+			tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+			args := map[string]any{"prometheus_url": "http://prometheus.test:9090"}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+
+			result, err := tool.DiscoverMetricsHandler(context.Background(), args)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			var response DiscoverMetricsResponse
+			if err := json.Unmarshal([]byte(result), &response); err != nil {
+				t.Fatalf("Expected valid JSON result, got error: %v", err)
+			}
+
+			if response.TotalMetrics != len(metrics) {
+				t.Errorf("Expected total_metrics %d, got %d", len(metrics), response.TotalMetrics)
+			}
+			if response.Pagination != tt.expected {
+				t.Errorf("Expected pagination %+v, got %+v", tt.expected, response.Pagination)
+			}
+			if len(response.Metrics) != len(tt.expectedNames) {
+				t.Fatalf("Expected %d metrics, got %d", len(tt.expectedNames), len(response.Metrics))
+			}
+			for i, name := range tt.expectedNames {
+				if response.Metrics[i].Name != name {
+					t.Errorf("Expected metric %d to be %s, got %s", i, name, response.Metrics[i].Name)
+				}
+			}
+			if tt.wantWarning != (len(response.Warnings) > 0) {
+				t.Errorf("Unexpected warnings %v", response.Warnings)
+			}
+		})
+	}
+}
+
+func TestDiscoverMetricsHandler_Selector(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.DiscoverMetricsReturns([]promql.MetricInfo{{Name: "http_requests_total", Type: promql.MetricTypeCounter}}, nil)
+
+	tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"selector":       ` {job="checkout"} `,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	_, _, _, _, selector := fakePromQL.DiscoverMetricsArgsForCall(0)
+	if selector != `{job="checkout"}` {
+		t.Errorf("Expected selector to be forwarded, got %q", selector)
+	}
+
+	var response DiscoverMetricsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if response.Filters.Selector != `{job="checkout"}` {
+		t.Errorf("Expected selector in filters, got %q", response.Filters.Selector)
+	}
+}
+
+func TestDiscoverMetricsHandler_GroupByPrefix(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.DiscoverMetricsReturns([]promql.MetricInfo{
+		{Name: "http_requests_total", Type: promql.MetricTypeCounter},
+		{Name: "http_request_duration_seconds", Type: promql.MetricTypeHistogram},
+		{Name: "go_goroutines", Type: promql.MetricTypeGauge},
+		{Name: "go_threads", Type: promql.MetricTypeGauge},
+		{Name: "pg_up", Type: promql.MetricTypeGauge},
+		{Name: "up", Type: promql.MetricTypeGauge},
+	}, nil)
+
+	tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"prometheus_url":  "http://prometheus.test:9090",
+		"group_by_prefix": true,
+		"limit":           float64(1),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response DiscoverMetricsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if len(response.Groups) != 4 {
+		t.Fatalf("Expected 4 groups computed over all metrics, got %+v", response.Groups)
+	}
+	expected := []struct {
+		prefix string
+		count  int
+	}{{"go_", 2}, {"http_", 2}, {"pg_", 1}, {"up", 1}}
+	for i, e := range expected {
+		if response.Groups[i].Prefix != e.prefix || response.Groups[i].Count != e.count {
+			t.Errorf("Group %d: expected %s (%d), got %s (%d)", i, e.prefix, e.count, response.Groups[i].Prefix, response.Groups[i].Count)
+		}
+	}
+	if response.Groups[1].Types["counter"] != 1 || response.Groups[1].Types["histogram"] != 1 {
+		t.Errorf("Expected per-type counts for http_, got %v", response.Groups[1].Types)
+	}
+	if len(response.Metrics) != 1 {
+		t.Errorf("Expected pagination to still apply to metrics, got %d", len(response.Metrics))
+	}
+}
+
+func TestDiscoverMetricsHandler_Templates(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.DiscoverMetricsReturns([]promql.MetricInfo{
+		{Name: "go_goroutines", Type: promql.MetricTypeGauge},
+		{Name: "nginx_ingress_controller_requests", Type: promql.MetricTypeCounter},
+		{Name: "nginx_ingress_controller_request_duration_seconds", Type: promql.MetricTypeHistogram},
+	}, nil)
+
+	tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"limit":          float64(1),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response DiscoverMetricsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if len(response.Templates) != 1 || response.Templates[0] != "nginx_ingress" {
+		t.Errorf("Expected the nginx_ingress template selected over all metrics, got %v", response.Templates)
+	}
+}
+
+func TestCompileNamePattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		substring bool
+		wantErr   bool
+		position  int
+		reason    string
+		matches   string
+	}{
+		{name: "empty pattern", pattern: ""},
+		{name: "valid regex", pattern: "^http_.*", matches: "http_requests_total"},
+		{name: "unclosed group", pattern: "http_(req", wantErr: true, position: 5, reason: "missing closing )"},
+		{name: "stray closing paren", pattern: "http)_req", wantErr: true, position: 4, reason: "unexpected )"},
+		{name: "bad repetition", pattern: "http_*+x", wantErr: true, position: 5, reason: "invalid nested repetition operator"},
+		{name: "leading star", pattern: "*requests", wantErr: true, position: 0, reason: "missing argument to repetition operator"},
+		{name: "substring match quotes metacharacters", pattern: "requests(", substring: true, matches: "http_requests(total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := compileNamePattern(tt.pattern, tt.substring)
+			if tt.wantErr {
+				var patternErr *PatternError
+				if !errors.As(err, &patternErr) {
+					t.Fatalf("Expected PatternError, got %v", err)
+				}
+				if patternErr.Position != tt.position || patternErr.Reason != tt.reason {
+					t.Errorf("Expected %q at %d, got %q at %d", tt.reason, tt.position, patternErr.Reason, patternErr.Position)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.pattern == "" {
+				if re != nil {
+					t.Error("Expected nil regex for empty pattern")
+				}
+				return
+			}
+			if !re.MatchString(tt.matches) {
+				t.Errorf("Expected %q to match %q", tt.pattern, tt.matches)
+			}
+		})
+	}
+}
+
+func TestDiscoverMetricsHandler_InvalidPattern(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	_, err := tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"name_pattern":   "http_(req",
+	})
+
+	var patternErr *PatternError
+	if !errors.As(err, &patternErr) {
+		t.Fatalf("Expected PatternError, got %v", err)
+	}
+	if fakePromQL.DiscoverMetricsCallCount() != 0 {
+		t.Error("Expected Prometheus not to be queried with an invalid pattern")
+	}
+}
+
+func TestDiscoverMetricsHandler_MetricsURL(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.ScrapeTargetReturns([]exposition.Family{
+		{Name: "orders", Type: "counter", Samples: []exposition.Sample{
+			{Name: "orders_total", Labels: map[string]string{"status": "ok"}},
+			{Name: "orders_total", Labels: map[string]string{"status": "failed"}},
+		}},
+		{Name: "queue_depth", Type: "gauge", Samples: []exposition.Sample{{Name: "queue_depth"}}},
+		{Name: "cache_hits_total", Type: "unknown", Samples: []exposition.Sample{{Name: "cache_hits_total"}}},
+	}, nil)
+	tool := &DiscoverMetricsTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"metrics_url": "http://checkout:8080/metrics",
+		"metric_type": "counter",
+		"sort_by":     "series_count",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var response DiscoverMetricsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if fakePromQL.DiscoverMetricsCallCount() != 0 || fakePromQL.GetSeriesCountsCallCount() != 0 {
+		t.Error("Expected no Prometheus server to be queried")
+	}
+	if response.MetricsURL != "http://checkout:8080/metrics" || response.PrometheusURL != "" {
+		t.Errorf("Unexpected source %q / %q", response.MetricsURL, response.PrometheusURL)
+	}
+	if len(response.Metrics) != 2 || response.Metrics[0].Name != "orders_total" || response.Metrics[0].SeriesCount != 2 || response.Metrics[1].Name != "cache_hits_total" {
+		t.Errorf("Expected the counters typed from the exposition and by name, got %+v", response.Metrics)
+	}
+
+	_, err = tool.DiscoverMetricsHandler(context.Background(), map[string]any{
+		"metrics_url": "http://checkout:8080/metrics",
+		"selector":    `{job="checkout"}`,
+	})
+	if err == nil {
+		t.Error("Expected selector to be rejected with metrics_url")
+	}
+}
@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// labelNamePattern matches valid Prometheus label names
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// environmentLabels returns the configured environment labels (cluster,
+// region, env, ...) sorted by name, skipping invalid label names
+func environmentLabels(env *config.EnvironmentConfig) []string {
+	if env == nil {
+		return nil
+	}
+	names := make([]string, 0, len(env.Labels))
+	for name := range env.Labels {
+		if labelNamePattern.MatchString(name) && !isReservedLabel(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scopeLabel returns the configured scope label (tenant, team, ...) every
+// dashboard query must match on, or "" when none is configured, the name is
+// invalid or an environment label already fixes its value
+func scopeLabel(env *config.EnvironmentConfig) string {
+	if env == nil || !labelNamePattern.MatchString(env.ScopeLabel) || isReservedLabel(env.ScopeLabel) {
+		return ""
+	}
+	if _, ok := env.Labels[env.ScopeLabel]; ok {
+		return ""
+	}
+	return env.ScopeLabel
+}
+
+// environmentMatchers turns the environment labels into equality matchers.
+// With asVariables the values reference the dashboard constant variables
+// ($cluster) instead of the literal values, for queries placed on dashboards,
+// and the scope label is matched against its variable ($tenant). Without a
+// dashboard the scope label has no value and is left out.
+func environmentMatchers(env *config.EnvironmentConfig, asVariables bool) []promql.LabelMatcher {
+	names := environmentLabels(env)
+	matchers := make([]promql.LabelMatcher, 0, len(names)+1)
+	for _, name := range names {
+		value := env.Labels[name]
+		if asVariables {
+			value = "$" + name
+		}
+		matchers = append(matchers, promql.LabelMatcher{Name: name, Op: "=", Value: value})
+	}
+	if label := scopeLabel(env); label != "" && asVariables {
+		matchers = append(matchers, promql.LabelMatcher{Name: label, Op: "=", Value: "$" + label})
+	}
+	return matchers
+}
+
+// environmentVariables returns hidden constant template variables holding
+// the environment label values, preceded by a query variable selecting the
+// scope label's value, skipping names the dashboard already defines
+func environmentVariables(env *config.EnvironmentConfig, existing []any) []any {
+	defined := map[string]bool{}
+	for _, raw := range existing {
+		if variable, ok := raw.(map[string]any); ok {
+			defined[getStringOrDefault(variable, "name", "")] = true
+		}
+	}
+
+	var variables []any
+	if label := scopeLabel(env); label != "" && !defined[label] {
+		variables = append(variables, scopeVariable(label))
+	}
+	for _, name := range environmentLabels(env) {
+		if defined[name] {
+			continue
+		}
+		value := env.Labels[name]
+		variables = append(variables, map[string]any{
+			"name":    name,
+			"type":    "constant",
+			"label":   name,
+			"query":   value,
+			"hide":    2,
+			"current": map[string]any{"text": value, "value": value},
+		})
+	}
+	return variables
+}
+
+// scopeVariable is the query variable selecting the scope label's value. It
+// takes a single value without an All option, so no panel shows several
+// tenants at once.
+func scopeVariable(label string) map[string]any {
+	query := fmt.Sprintf("label_values(%s)", label)
+	return map[string]any{
+		"name":       label,
+		"label":      label,
+		"type":       "query",
+		"query":      query,
+		"definition": query,
+		"refresh":    1,
+		"sort":       1,
+		"multi":      false,
+		"includeAll": false,
+	}
+}
+
+// isReservedLabel reports whether a label is internal to Prometheus
+func isReservedLabel(name string) bool {
+	return strings.HasPrefix(name, "__")
+}
+
+// scopePanelsToEnvironment adds environment matchers to every Prometheus
+// target of a dashboard model that does not already constrain the label
+func scopePanelsToEnvironment(dashboard map[string]any, matchers []promql.LabelMatcher) {
+	if len(matchers) == 0 {
+		return
+	}
+	for _, panel := range flattenPanels(dashboard) {
+		targets, _ := panel["targets"].([]any)
+		for _, targetRaw := range targets {
+			target, ok := targetRaw.(map[string]any)
+			if !ok {
+				continue
+			}
+			if expr, ok := target["expr"].(string); ok && strings.TrimSpace(expr) != "" {
+				target["expr"] = promql.InjectMatchers(expr, matchers)
+			}
+		}
+	}
+}
+
+// enforceScope scopes the query variables of a dashboard model to the scope
+// label and fails when any panel or variable query still selects series
+// without matching the scope variable, e.g. because it matches the label
+// against another value. Without a scope label every query passes.
+func enforceScope(dashboard map[string]any, env *config.EnvironmentConfig) error {
+	label := scopeLabel(env)
+	if label == "" {
+		return nil
+	}
+	matchers := []promql.LabelMatcher{{Name: label, Op: "=", Value: "$" + label}}
+
+	var unscoped []string
+	for _, panel := range flattenPanels(dashboard) {
+		targets, _ := panel["targets"].([]any)
+		for _, targetRaw := range targets {
+			target, ok := targetRaw.(map[string]any)
+			if !ok {
+				continue
+			}
+			expr, _ := target["expr"].(string)
+			if !isScoped(expr, label) {
+				unscoped = append(unscoped, fmt.Sprintf("panel %q query %s", getStringOrDefault(panel, "title", ""), getStringOrDefault(target, "refId", "")))
+			}
+		}
+	}
+
+	templating, _ := dashboard["templating"].(map[string]any)
+	variables, _ := templating["list"].([]any)
+	for _, raw := range variables {
+		variable, ok := raw.(map[string]any)
+		if !ok || variable["type"] != "query" || variable["name"] == label {
+			continue
+		}
+		query, ok := variable["query"].(string)
+		if !ok {
+			continue
+		}
+		prefix, expr, suffix := variableQuerySeries(query)
+		if expr == "" {
+			continue
+		}
+		expr = promql.InjectMatchers(expr, matchers)
+		variable["query"] = prefix + expr + suffix
+		variable["definition"] = variable["query"]
+		if !isScoped(expr, label) {
+			unscoped = append(unscoped, fmt.Sprintf("variable %s", getStringOrDefault(variable, "name", "")))
+		}
+	}
+
+	if len(unscoped) > 0 {
+		return fmt.Errorf("queries must match %s=\"$%s\", which these do not: %s", label, label, strings.Join(unscoped, ", "))
+	}
+	return nil
+}
+
+// isScoped reports whether every selector of a query matches the label
+// against its dashboard variable
+func isScoped(expr, label string) bool {
+	for _, selector := range promql.ParseSelectors(expr) {
+		scoped := false
+		for _, matcher := range selector.Matchers {
+			if matcher.Name == label && matcher.Op == "=" && (matcher.Value == "$"+label || matcher.Value == "${"+label+"}") {
+				scoped = true
+			}
+		}
+		if !scoped {
+			return false
+		}
+	}
+	return true
+}
+
+// variableQuerySeries splits the query of a Prometheus query variable around
+// the expression selecting its series, e.g. up{job="api"} in
+// label_values(up{job="api"}, instance). Queries without series, such as
+// label_values(instance), return no expression.
+func variableQuerySeries(query string) (prefix, expr, suffix string) {
+	query = strings.TrimSpace(query)
+	if !strings.HasSuffix(query, ")") {
+		return "", "", ""
+	}
+	switch {
+	case strings.HasPrefix(query, "query_result("):
+		return "query_result(", query[len("query_result(") : len(query)-1], ")"
+	case strings.HasPrefix(query, "label_values("):
+		args := query[len("label_values(") : len(query)-1]
+		comma := strings.LastIndex(args, ",")
+		if comma < 0 {
+			return "", "", ""
+		}
+		return "label_values(", args[:comma], args[comma:] + ")"
+	}
+	return "", "", ""
+}
@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	authz "github.com/inference-gateway/grafana-agent/internal/authz"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	naming "github.com/inference-gateway/grafana-agent/internal/naming"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+// Tool error codes
+const (
+	ErrCodeInvalidArgument     = "invalid_argument"
+	ErrCodeMissingConfig       = "missing_config"
+	ErrCodeDeployDisabled      = "deploy_disabled"
+	ErrCodeNotFound            = "not_found"
+	ErrCodePermissionDenied    = "permission_denied"
+	ErrCodeUpstreamUnavailable = "upstream_unavailable"
+	ErrCodeUpstreamError       = "upstream_error"
+	ErrCodeTimeout             = "timeout"
+	ErrCodeCanceled            = "canceled"
+	ErrCodeInternal            = "internal"
+)
+
+// ToolError is the structured error a tool reports to the calling LLM: a
+// stable code, whether retrying the same call can succeed, and a hint on how
+// to remediate (fix arguments, ask the user for configuration, give up)
+type ToolError struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Retryable   bool   `json:"retryable"`
+	Remediation string `json:"remediation,omitempty"`
+	Details     any    `json:"details,omitempty"`
+	err         error
+}
+
+// Error implements the error interface
+func (e *ToolError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error, if any
+func (e *ToolError) Unwrap() error {
+	return e.err
+}
+
+// newToolError creates a non-retryable tool error
+func newToolError(code, message, remediation string) *ToolError {
+	return &ToolError{Code: code, Message: message, Remediation: remediation}
+}
+
+// ErrorEnvelope wraps a ToolError as the JSON document returned by a failed
+// tool call, e.g. {"error":{"code":"missing_config",...}}
+type ErrorEnvelope struct {
+	Err *ToolError `json:"error"`
+}
+
+// Error renders the envelope as JSON so it reaches the LLM verbatim
+func (e *ErrorEnvelope) Error() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e.Err.Message
+	}
+	return string(data)
+}
+
+// Unwrap returns the wrapped ToolError
+func (e *ErrorEnvelope) Unwrap() error {
+	return e.Err
+}
+
+// statusCodePattern extracts HTTP status codes from upstream error messages
+var statusCodePattern = regexp.MustCompile(`status (\d{3})`)
+
+// toToolError classifies any handler error into a ToolError
+func toToolError(err error) *ToolError {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return toolErr
+	}
+
+	var argErr *ArgumentValidationError
+	if errors.As(err, &argErr) {
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     "arguments do not match the tool schema",
+			Remediation: "Correct the listed arguments and call the tool again",
+			Details:     argErr.Errors,
+			err:         err,
+		}
+	}
+
+	var patternErr *PatternError
+	if errors.As(err, &patternErr) {
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Fix the regex at the reported position or set substring_match",
+			Details:     patternErr,
+			err:         err,
+		}
+	}
+
+	var lintErr *naming.LintError
+	if errors.As(err, &lintErr) {
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Rename the dashboard to the suggested title, or ask the user for a title that follows the naming convention, and deploy again",
+			Details:     lintErr,
+			err:         err,
+		}
+	}
+
+	var tagErr *taxonomy.TagError
+	if errors.As(err, &tagErr) {
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Retag with key:value tags whose key is one of the allowed keys, or drop the listed tags, and call the tool again",
+			Details:     tagErr,
+			err:         err,
+		}
+	}
+
+	var openErr *circuit.OpenError
+	if errors.As(err, &openErr) {
+		return &ToolError{
+			Code:        ErrCodeUpstreamUnavailable,
+			Message:     err.Error(),
+			Retryable:   true,
+			Remediation: "The backend is failing repeatedly; tell the user it is unavailable and retry after retry_at",
+			Details:     openErr,
+			err:         err,
+		}
+	}
+
+	switch {
+	case errors.Is(err, authz.ErrForbidden):
+		return &ToolError{
+			Code:        ErrCodePermissionDenied,
+			Message:     err.Error(),
+			Remediation: "Do not retry; tell the user their role does not allow this, or offer an allowed folder",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrDeployDisabled):
+		return &ToolError{
+			Code:        ErrCodeDeployDisabled,
+			Message:     err.Error(),
+			Remediation: "Return the dashboard JSON to the user instead, or ask an operator to enable deployments",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrPublicDashboardsDisabled):
+		return &ToolError{
+			Code:        ErrCodeDeployDisabled,
+			Message:     err.Error(),
+			Remediation: "Do not retry; ask an operator to opt in to publishing dashboards publicly",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrMissingURL):
+		return &ToolError{
+			Code:        ErrCodeMissingConfig,
+			Message:     err.Error(),
+			Remediation: "Ask the user for the Grafana URL and pass it as grafana_url",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrFolderConflict):
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Pass either folder (a path) or folder_uid, not both",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrStackConflict):
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Pass either stack or grafana_url, not both",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrMergeWithoutUID):
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Keep the uid of the deployed dashboard in dashboard_json, or omit base_version to overwrite",
+			err:         err,
+		}
+	case errors.Is(err, grafanacloud.ErrNotConfigured):
+		return &ToolError{
+			Code:        ErrCodeMissingConfig,
+			Message:     err.Error(),
+			Remediation: "Ask an operator to configure GRAFANA_CLOUD_ORG and GRAFANA_CLOUD_TOKEN, or deploy with grafana_url instead",
+			err:         err,
+		}
+	case errors.Is(err, grafanacloud.ErrStackNotFound):
+		return &ToolError{
+			Code:        ErrCodeNotFound,
+			Message:     err.Error(),
+			Remediation: "Call list_grafana_stacks and pick one of the returned stacks",
+			err:         err,
+		}
+	case errors.Is(err, grafanacloud.ErrAmbiguousStack):
+		return &ToolError{
+			Code:        ErrCodeInvalidArgument,
+			Message:     err.Error(),
+			Remediation: "Ask the user which of the listed stacks they mean and pass its slug",
+			err:         err,
+		}
+	case errors.Is(err, deploy.ErrMissingAPIKey):
+		return &ToolError{
+			Code:        ErrCodeMissingConfig,
+			Message:     err.Error(),
+			Remediation: "Ask an operator to configure GRAFANA_API_KEY; it cannot be passed as an argument",
+			err:         err,
+		}
+	}
+
+	classified := &ToolError{Code: ErrCodeInternal, Message: err.Error(), err: err}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		classified.Code = ErrCodeTimeout
+		classified.Retryable = true
+		classified.Remediation = "Retry, or narrow the request (shorter time range, fewer metrics)"
+	case errors.Is(err, context.Canceled):
+		classified.Code = ErrCodeCanceled
+	case errors.As(err, &netErr):
+		classified.Code = ErrCodeUpstreamUnavailable
+		classified.Retryable = true
+		classified.Remediation = "Check that the server URL is correct and reachable, then retry"
+	default:
+		classifyMessage(classified)
+	}
+
+	return classified
+}
+
+// classifyMessage derives a code from the message of an unstructured error
+func classifyMessage(e *ToolError) {
+	msg := strings.ToLower(e.Message)
+
+	if m := statusCodePattern.FindStringSubmatch(msg); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		switch {
+		case status == 401 || status == 403:
+			e.Code = ErrCodePermissionDenied
+			e.Remediation = "Ask the user for credentials with the required permissions"
+		case status == 404:
+			e.Code = ErrCodeNotFound
+			e.Remediation = "Verify the identifier or URL exists"
+		case status == 429 || status >= 500:
+			e.Code = ErrCodeUpstreamError
+			e.Retryable = true
+			e.Remediation = "The server is overloaded or failing; retry after a short delay"
+		default:
+			e.Code = ErrCodeUpstreamError
+		}
+		return
+	}
+
+	switch {
+	case strings.Contains(msg, "not found"):
+		e.Code = ErrCodeNotFound
+		e.Remediation = "Verify the identifier or URL exists"
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "no such host"):
+		e.Code = ErrCodeUpstreamUnavailable
+		e.Retryable = true
+		e.Remediation = "Check that the server URL is correct and reachable, then retry"
+	case strings.Contains(msg, "is required") || strings.Contains(msg, "must be") ||
+		strings.Contains(msg, "cannot be empty") || strings.Contains(msg, "unsupported") ||
+		strings.Contains(msg, "invalid"):
+		e.Code = ErrCodeInvalidArgument
+		e.Remediation = "Correct the arguments and call the tool again"
+	}
+}
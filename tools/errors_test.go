@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	circuit "github.com/inference-gateway/grafana-agent/internal/circuit"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	naming "github.com/inference-gateway/grafana-agent/internal/naming"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+func TestToToolError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		retryable bool
+	}{
+		{name: "structured error passes through", err: newToolError(ErrCodeMissingConfig, "no key", "ask"), code: ErrCodeMissingConfig},
+		{name: "argument validation", err: &ArgumentValidationError{Tool: "x", Errors: []ArgumentError{{Field: "a", Code: ArgErrorRequired}}}, code: ErrCodeInvalidArgument},
+		{name: "pattern error", err: &PatternError{Pattern: "(", Reason: "missing closing )"}, code: ErrCodeInvalidArgument},
+		{name: "deadline", err: fmt.Errorf("failed to query: %w", context.DeadlineExceeded), code: ErrCodeTimeout, retryable: true},
+		{name: "canceled", err: context.Canceled, code: ErrCodeCanceled},
+		{name: "network error", err: fmt.Errorf("failed to query Prometheus: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), code: ErrCodeUpstreamUnavailable, retryable: true},
+		{name: "naming convention", err: &naming.LintError{Title: "tmp", Violations: []naming.Violation{{Rule: naming.RuleForbiddenWord}}}, code: ErrCodeInvalidArgument},
+		{name: "tag taxonomy", err: &taxonomy.TagError{Tags: []string{"owner:alice"}, Allowed: []string{"team"}}, code: ErrCodeInvalidArgument},
+		{name: "open circuit", err: fmt.Errorf("failed to query Prometheus: %w", &circuit.OpenError{Backend: "http://prometheus:9090", Failures: 5}), code: ErrCodeUpstreamUnavailable, retryable: true},
+		{name: "server error status", err: errors.New("grafana returned status 503"), code: ErrCodeUpstreamError, retryable: true},
+		{name: "unauthorized status", err: errors.New("grafana returned status 401"), code: ErrCodePermissionDenied},
+		{name: "not found", err: errors.New("failed to get dashboard: dashboard not found"), code: ErrCodeNotFound},
+		{name: "missing argument", err: errors.New("prometheus_url is required and must be a string"), code: ErrCodeInvalidArgument},
+		{name: "unknown", err: errors.New("failed to marshal response"), code: ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolErr := toToolError(tt.err)
+			if toolErr.Code != tt.code {
+				t.Errorf("Code = %s, want %s", toolErr.Code, tt.code)
+			}
+			if toolErr.Retryable != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", toolErr.Retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestNewValidatedTool_ErrorEnvelope(t *testing.T) {
+	tool := NewDeployDashboardTool(zap.NewNop(), &mockGrafanaService{}, nil, &config.GrafanaConfig{DeployEnabled: true}, &config.AlertingConfig{}, deploy.Options{})
+
+	_, err := tool.Execute(context.Background(), map[string]any{
+		"dashboard_json": map[string]any{"title": "Test"},
+	})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	var envelope struct {
+		Error ToolError `json:"error"`
+	}
+	if jsonErr := json.Unmarshal([]byte(err.Error()), &envelope); jsonErr != nil {
+		t.Fatalf("Expected JSON error envelope, got %q", err.Error())
+	}
+	if envelope.Error.Code != ErrCodeMissingConfig || envelope.Error.Remediation == "" || envelope.Error.Retryable {
+		t.Errorf("Unexpected envelope %+v", envelope.Error)
+	}
+
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != ErrCodeMissingConfig {
+		t.Errorf("Expected envelope to unwrap to a ToolError, got %v", err)
+	}
+}
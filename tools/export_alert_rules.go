@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	rulefile "github.com/inference-gateway/grafana-agent/internal/rulefile"
+)
+
+// defaultRuleGroup names the group of exported agent-generated rules
+const defaultRuleGroup = "grafana-agent"
+
+// ExportAlertRulesTool struct holds the tool with services
+type ExportAlertRulesTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewExportAlertRulesTool creates a new export_alert_rules tool
+func NewExportAlertRulesTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &ExportAlertRulesTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"export_alert_rules",
+		"Exports Grafana-managed alert rules, or alert rules generated by the agent, as a Prometheus rule file (groups: YAML) for Prometheus, Thanos Ruler or Mimir",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"folder_uid": map[string]any{
+					"description": "Only export Grafana-managed rules of this folder",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"group": map[string]any{
+					"description": "For source grafana, only export this rule group; for source generated, the name of the exported group (default \"grafana-agent\")",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"rules": map[string]any{
+					"description": "Alert rules to export for source generated, as returned in alert_rules by generate_promql_queries",
+					"type":        "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"name":        map[string]any{"type": "string"},
+							"expr":        map[string]any{"type": "string"},
+							"for":         map[string]any{"type": "string"},
+							"severity":    map[string]any{"type": "string"},
+							"description": map[string]any{"type": "string"},
+							"labels":      map[string]any{"type": "object"},
+						},
+						"required": []string{"name", "expr"},
+					},
+				},
+				"source": map[string]any{
+					"description": "Rules to export: grafana for the Grafana-managed rules (default) or generated for the rules passed in rules",
+					"type":        "string",
+					"enum":        []string{"grafana", "generated"},
+				},
+			},
+		},
+		tool.ExportAlertRulesHandler,
+	)
+}
+
+// ExportAlertRulesResponse represents an exported rule file
+type ExportAlertRulesResponse struct {
+	Source string `json:"source"`
+	Groups int    `json:"groups"`
+	Rules  int    `json:"rules"`
+	// YAML is the rule file, ready to be loaded by Prometheus
+	YAML string `json:"yaml"`
+	// Skipped lists Grafana rules without a Prometheus equivalent
+	Skipped []rulefile.Skipped `json:"skipped,omitempty"`
+}
+
+// ExportAlertRulesHandler handles the export_alert_rules tool execution
+func (t *ExportAlertRulesTool) ExportAlertRulesHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "export_alert_rules")
+	defer span.End()
+
+	source := getStringOrDefault(args, "source", "grafana")
+	group, _ := args["group"].(string)
+
+	var (
+		file    rulefile.File
+		skipped []rulefile.Skipped
+		err     error
+	)
+	switch source {
+	case "generated":
+		file, err = generatedRuleFile(args["rules"], group)
+	case "grafana":
+		file, skipped, err = t.grafanaRuleFile(ctx, args, group)
+	default:
+		return "", fmt.Errorf("source must be one of grafana or generated")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	data, err := rulefile.Marshal(file)
+	if err != nil {
+		return "", err
+	}
+
+	response := ExportAlertRulesResponse{Source: source, Groups: len(file.Groups), YAML: string(data), Skipped: skipped}
+	for _, g := range file.Groups {
+		response.Rules += len(g.Rules)
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// generatedRuleFile builds a single-group rule file from agent-generated
+// alert rule candidates
+func generatedRuleFile(raw any, group string) (rulefile.File, error) {
+	items, _ := raw.([]any)
+	if len(items) == 0 {
+		return rulefile.File{}, fmt.Errorf("rules is required for source generated")
+	}
+
+	candidates := make([]promql.AlertRuleCandidate, 0, len(items))
+	for i, item := range items {
+		var candidate promql.AlertRuleCandidate
+		if err := json.Unmarshal([]byte(mustMarshal(item)), &candidate); err != nil {
+			return rulefile.File{}, fmt.Errorf("rules[%d] is invalid: %w", i, err)
+		}
+		if strings.TrimSpace(candidate.Name) == "" || strings.TrimSpace(candidate.Expr) == "" {
+			return rulefile.File{}, fmt.Errorf("rules[%d] must have a name and an expr", i)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if group == "" {
+		group = defaultRuleGroup
+	}
+	return rulefile.File{Groups: []rulefile.Group{rulefile.FromCandidates(group, candidates)}}, nil
+}
+
+// grafanaRuleFile converts the Grafana-managed rules, optionally filtered by
+// folder and group
+func (t *ExportAlertRulesTool) grafanaRuleFile(ctx context.Context, args map[string]any, group string) (rulefile.File, []rulefile.Skipped, error) {
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return rulefile.File{}, nil, err
+	}
+
+	rules, err := t.grafanaSvc.ListAlertRules(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return rulefile.File{}, nil, err
+	}
+	datasources, err := t.grafanaSvc.ListDatasources(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return rulefile.File{}, nil, err
+	}
+	prometheus := map[string]bool{}
+	for _, datasource := range datasources {
+		// Mimir and managed Prometheus services use Prometheus-compatible plugins
+		prometheus[datasource.UID] = strings.Contains(datasource.Type, "prometheus")
+	}
+
+	folderUID, _ := args["folder_uid"].(string)
+	selected := make([]grafana.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if (folderUID == "" || rule.FolderUID == folderUID) && (group == "" || rule.RuleGroup == group) {
+			selected = append(selected, rule)
+		}
+	}
+	if len(selected) == 0 {
+		return rulefile.File{}, nil, fmt.Errorf("no alert rules found matching the folder and group filters")
+	}
+
+	file, skipped := rulefile.FromGrafana(selected, func(uid string) bool { return prometheus[uid] })
+	return file, skipped, nil
+}
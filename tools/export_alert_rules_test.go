@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func runExportAlertRules(t *testing.T, mock *mockGrafanaService, args map[string]any) (ExportAlertRulesResponse, error) {
+	t.Helper()
+
+	tool := &ExportAlertRulesTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+	result, err := tool.ExportAlertRulesHandler(context.Background(), args)
+	if err != nil {
+		return ExportAlertRulesResponse{}, err
+	}
+
+	var response ExportAlertRulesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, nil
+}
+
+func TestExportAlertRulesHandler(t *testing.T) {
+	t.Run("grafana-managed rules", func(t *testing.T) {
+		mock := &mockGrafanaService{
+			datasources: []grafana.Datasource{{UID: "prom", Type: "prometheus"}, {UID: "loki", Type: "loki"}},
+			alertRules: []grafana.AlertRule{
+				{UID: "down", Title: "TargetDown", FolderUID: "platform", RuleGroup: "availability", Condition: "A", For: "5m",
+					Data: []grafana.AlertQuery{{RefID: "A", DatasourceUID: "prom", Model: map[string]any{"expr": "up == 0"}}}},
+				{UID: "logs", Title: "LogErrors", FolderUID: "platform", RuleGroup: "availability", Condition: "A",
+					Data: []grafana.AlertQuery{{RefID: "A", DatasourceUID: "loki", Model: map[string]any{"expr": `count_over_time({app="x"} |= "error" [5m])`}}}},
+				{UID: "other", Title: "Other", FolderUID: "infra", RuleGroup: "nodes", Condition: "A",
+					Data: []grafana.AlertQuery{{RefID: "A", DatasourceUID: "prom", Model: map[string]any{"expr": "node_load1 > 4"}}}},
+			},
+		}
+
+		response, err := runExportAlertRules(t, mock, map[string]any{"folder_uid": "platform"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.Groups != 1 || response.Rules != 1 || len(response.Skipped) != 1 || response.Skipped[0].UID != "logs" {
+			t.Errorf("unexpected export %+v", response)
+		}
+		if !strings.Contains(response.YAML, "- name: availability") || !strings.Contains(response.YAML, "expr: up == 0") {
+			t.Errorf("unexpected rule file:\n%s", response.YAML)
+		}
+
+		if _, err := runExportAlertRules(t, mock, map[string]any{"group": "missing"}); err == nil || !strings.Contains(err.Error(), "no alert rules found") {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+
+	t.Run("generated rules", func(t *testing.T) {
+		response, err := runExportAlertRules(t, &mockGrafanaService{}, map[string]any{
+			"source": "generated",
+			"group":  "checkout",
+			"rules": []any{
+				map[string]any{"name": "HttpRequestsTotalAbsent", "expr": "absent(http_requests_total)", "for": "5m", "severity": "critical", "labels": map[string]any{"team": "payments"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, want := range []string{"- name: checkout", "alert: HttpRequestsTotalAbsent", "for: 5m", "severity: critical", "team: payments"} {
+			if !strings.Contains(response.YAML, want) {
+				t.Errorf("expected %q in the rule file:\n%s", want, response.YAML)
+			}
+		}
+
+		if _, err := runExportAlertRules(t, &mockGrafanaService{}, map[string]any{"source": "generated", "rules": []any{map[string]any{"name": "x"}}}); err == nil || !strings.Contains(err.Error(), "must have a name and an expr") {
+			t.Errorf("expected a validation error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// seriesSuffixes are the series a histogram or summary metric is exposed as
+var seriesSuffixes = []string{"_bucket", "_sum", "_count"}
+
+// FindQueryUsageTool struct holds the tool with services
+type FindQueryUsageTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewFindQueryUsageTool creates a new find_query_usage tool
+func NewFindQueryUsageTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &FindQueryUsageTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"find_query_usage",
+		"Scans all dashboards for panel, variable and annotation queries that use a metric or contain a PromQL fragment and reports where they are, e.g. before renaming or deleting a metric",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_query": map[string]any{
+					"description": "Optional dashboard title filter; only matching dashboards are scanned",
+					"type":        "string",
+				},
+				"fragment": map[string]any{
+					"description": "PromQL fragment to look for, e.g. `job=\"checkout\"` or `rate(http_requests_total`; whitespace differences are ignored",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"metric": map[string]any{
+					"description": "Metric name to look for; its _bucket, _sum and _count series match too",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+			},
+		},
+		tool.FindQueryUsageHandler,
+	)
+}
+
+// QueryUsage is a query that matched the search
+type QueryUsage struct {
+	DashboardUID   string `json:"dashboard_uid"`
+	DashboardTitle string `json:"dashboard_title"`
+	Folder         string `json:"folder,omitempty"`
+	URL            string `json:"url,omitempty"`
+	// Kind is "panel", "variable" or "annotation"
+	Kind       string `json:"kind"`
+	PanelID    int    `json:"panel_id,omitempty"`
+	PanelTitle string `json:"panel_title,omitempty"`
+	RefID      string `json:"ref_id,omitempty"`
+	// Name is the name of the variable or annotation
+	Name  string `json:"name,omitempty"`
+	Query string `json:"query"`
+}
+
+// FindQueryUsageResponse represents the outcome of a usage search
+type FindQueryUsageResponse struct {
+	DashboardsScanned int          `json:"dashboards_scanned"`
+	DashboardsMatched int          `json:"dashboards_matched"`
+	Usages            []QueryUsage `json:"usages"`
+	// Skipped lists dashboards that could not be read
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// FindQueryUsageHandler handles the find_query_usage tool execution
+func (t *FindQueryUsageTool) FindQueryUsageHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "find_query_usage")
+	defer span.End()
+
+	metric, _ := args["metric"].(string)
+	fragment, _ := args["fragment"].(string)
+	metric, fragment = strings.TrimSpace(metric), normalizeWhitespace(fragment)
+	if metric == "" && fragment == "" {
+		return "", fmt.Errorf("metric or fragment is required")
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	dashboardQuery, _ := args["dashboard_query"].(string)
+	hits, err := t.grafanaSvc.SearchDashboards(ctx, dashboardQuery, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	matches := func(query string) bool {
+		if fragment != "" && !strings.Contains(normalizeWhitespace(query), fragment) {
+			return false
+		}
+		return metric == "" || usesMetric(query, metric)
+	}
+
+	response := FindQueryUsageResponse{Usages: []QueryUsage{}}
+	for _, hit := range hits {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		dashboard, err := t.grafanaSvc.GetDashboard(ctx, hit.UID, target.GrafanaURL, target.APIKey)
+		if err != nil || dashboard == nil {
+			t.logger.Warn("skipping dashboard", zap.String("uid", hit.UID), zap.Error(err))
+			response.Skipped = append(response.Skipped, hit.UID)
+			continue
+		}
+		response.DashboardsScanned++
+
+		usages := dashboardQueryUsages(dashboard.Dashboard, matches)
+		if len(usages) == 0 {
+			continue
+		}
+		response.DashboardsMatched++
+		for _, usage := range usages {
+			usage.DashboardUID = hit.UID
+			usage.DashboardTitle = hit.Title
+			usage.Folder = hit.FolderTitle
+			usage.URL = hit.URL
+			response.Usages = append(response.Usages, usage)
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// dashboardQueryUsages returns the target, variable and annotation queries of
+// a dashboard model accepted by matches
+func dashboardQueryUsages(dashboard map[string]any, matches func(string) bool) []QueryUsage {
+	var usages []QueryUsage
+
+	for _, panel := range flattenPanels(dashboard) {
+		panelID, _ := toInt(panel["id"])
+		targets, _ := panel["targets"].([]any)
+		for _, raw := range targets {
+			target, _ := raw.(map[string]any)
+			if expr, ok := target["expr"].(string); ok && matches(expr) {
+				usages = append(usages, QueryUsage{
+					Kind:       "panel",
+					PanelID:    panelID,
+					PanelTitle: getStringOrDefault(panel, "title", ""),
+					RefID:      getStringOrDefault(target, "refId", ""),
+					Query:      expr,
+				})
+			}
+		}
+	}
+
+	templating, _ := dashboard["templating"].(map[string]any)
+	variables, _ := templating["list"].([]any)
+	for _, raw := range variables {
+		variable, _ := raw.(map[string]any)
+		if variable["type"] != "query" {
+			continue
+		}
+		query, _ := variable["query"].(string)
+		if object, ok := variable["query"].(map[string]any); ok {
+			query, _ = object["query"].(string)
+		}
+		if query != "" && matches(query) {
+			usages = append(usages, QueryUsage{Kind: "variable", Name: getStringOrDefault(variable, "name", ""), Query: query})
+		}
+	}
+
+	annotations, _ := dashboard["annotations"].(map[string]any)
+	annotationList, _ := annotations["list"].([]any)
+	for _, raw := range annotationList {
+		annotation, _ := raw.(map[string]any)
+		if expr, ok := annotation["expr"].(string); ok && matches(expr) {
+			usages = append(usages, QueryUsage{Kind: "annotation", Name: getStringOrDefault(annotation, "name", ""), Query: expr})
+		}
+	}
+
+	return usages
+}
+
+// usesMetric reports whether a query selects metric or one of its
+// histogram/summary series
+func usesMetric(query, metric string) bool {
+	for _, name := range promql.MetricNames(query) {
+		if name == metric {
+			return true
+		}
+		for _, suffix := range seriesSuffixes {
+			if name == metric+suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeWhitespace collapses runs of whitespace into single spaces
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
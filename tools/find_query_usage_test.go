@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func runFindQueryUsage(t *testing.T, args map[string]any) (FindQueryUsageResponse, error) {
+	t.Helper()
+
+	dashboards := map[string]map[string]any{
+		"checkout": panelDashboard(),
+		"latency": {
+			"title": "Latency",
+			"panels": []any{
+				map[string]any{"id": 1.0, "title": "p99", "targets": []any{
+					map[string]any{"refId": "A", "expr": "histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))"},
+				}},
+			},
+			"templating": map[string]any{"list": []any{
+				map[string]any{"name": "route", "type": "query", "query": map[string]any{"query": "label_values(http_requests_total, route)"}},
+			}},
+			"annotations": map[string]any{"list": []any{
+				map[string]any{"name": "Deploys", "expr": "changes(http_requests_total{job=\"checkout\"}[1m]) > 0"},
+			}},
+		},
+	}
+	mock := &mockGrafanaService{
+		dashboards: []grafana.DashboardHit{
+			{UID: "checkout", Title: "Checkout", FolderTitle: "Platform", URL: "/d/checkout"},
+			{UID: "latency", Title: "Latency"},
+			{UID: "gone", Title: "Gone"},
+		},
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			if dashboard, ok := dashboards[uid]; ok {
+				return &grafana.Dashboard{Dashboard: dashboard}, nil
+			}
+			return nil, errors.New("dashboard not found")
+		},
+	}
+	tool := &FindQueryUsageTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	result, err := tool.FindQueryUsageHandler(context.Background(), args)
+	if err != nil {
+		return FindQueryUsageResponse{}, err
+	}
+
+	var response FindQueryUsageResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, nil
+}
+
+func TestFindQueryUsageHandler(t *testing.T) {
+	t.Run("metric across panels, variables and annotations", func(t *testing.T) {
+		response, err := runFindQueryUsage(t, map[string]any{"metric": "http_requests_total"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if response.DashboardsScanned != 2 || response.DashboardsMatched != 2 || len(response.Skipped) != 1 || response.Skipped[0] != "gone" {
+			t.Errorf("unexpected scan summary %+v", response)
+		}
+
+		var kinds []string
+		for _, usage := range response.Usages {
+			kinds = append(kinds, usage.DashboardUID+"/"+usage.Kind)
+		}
+		if strings.Join(kinds, ",") != "checkout/panel,latency/variable,latency/annotation" {
+			t.Errorf("unexpected usages %v", kinds)
+		}
+
+		first := response.Usages[0]
+		if first.PanelID != 1 || first.PanelTitle != "Request Rate" || first.RefID != "A" || first.Folder != "Platform" {
+			t.Errorf("unexpected panel location %+v", first)
+		}
+	})
+
+	t.Run("histogram series match their base name", func(t *testing.T) {
+		response, err := runFindQueryUsage(t, map[string]any{"metric": "http_request_duration_seconds"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(response.Usages) != 1 || response.Usages[0].PanelTitle != "p99" {
+			t.Errorf("unexpected usages %+v", response.Usages)
+		}
+	})
+
+	t.Run("fragment ignores whitespace", func(t *testing.T) {
+		response, err := runFindQueryUsage(t, map[string]any{"fragment": "sum  by (le)\n(rate("})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(response.Usages) != 1 || response.Usages[0].DashboardUID != "latency" {
+			t.Errorf("unexpected usages %+v", response.Usages)
+		}
+	})
+
+	t.Run("metric or fragment is required", func(t *testing.T) {
+		if _, err := runFindQueryUsage(t, map[string]any{}); err == nil || !strings.Contains(err.Error(), "is required") {
+			t.Errorf("expected a required argument error, got %v", err)
+		}
+	})
+}
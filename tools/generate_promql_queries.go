@@ -4,41 +4,88 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	zap "go.uber.org/zap"
 
 	server "github.com/inference-gateway/adk/server"
 
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
 	promql "github.com/inference-gateway/grafana-agent/internal/promql"
 )
 
+const (
+	// alertPreviewWindow is how far back alert rules are replayed
+	alertPreviewWindow = 24 * time.Hour
+	// alertPreviewStep is the evaluation interval of the replay
+	alertPreviewStep = time.Minute
+)
+
 // GeneratePromqlQueriesTool struct holds the tool with services
 type GeneratePromqlQueriesTool struct {
-	logger *zap.Logger
-	promql promql.PromQL
+	logger        *zap.Logger
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+	alerting      *config.AlertingConfig
 }
 
 // NewGeneratePromqlQueriesTool creates a new generate_promql_queries tool
-func NewGeneratePromqlQueriesTool(logger *zap.Logger, promql promql.PromQL) server.Tool {
+func NewGeneratePromqlQueriesTool(logger *zap.Logger, promql promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig, alerting *config.AlertingConfig) server.Tool {
 	tool := &GeneratePromqlQueriesTool{
-		logger: logger,
-		promql: promql,
+		logger:        logger,
+		promql:        promql,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+		alerting:      alerting,
 	}
-	return server.NewBasicTool(
+	return newValidatedTool(
 		"generate_promql_queries",
 		"Generates PromQL query suggestions for given metric names by querying Prometheus metadata",
 		map[string]any{
 			"type": "object",
 			"properties": map[string]any{
+				"apdex_satisfied_seconds": map[string]any{
+					"description": "Apdex satisfied threshold in seconds for latency histograms (default 0.3)",
+					"type":        "number",
+				},
+				"apdex_tolerating_seconds": map[string]any{
+					"description": "Apdex tolerating threshold in seconds (default 4x the satisfied threshold)",
+					"type":        "number",
+				},
+				"intents": map[string]any{
+					"description": "Optional extra suggestion families: trend (subquery smoothing), week_over_week (offset 1w comparisons) and anomaly (z-scores, linear forecasts and smoothing for capacity and saturation panels)",
+					"items":       map[string]any{"enum": []string{"trend", "week_over_week", "anomaly"}, "type": "string"},
+					"type":        "array",
+				},
 				"metric_names": map[string]any{
 					"description": "Array of metric names to generate queries for",
 					"items":       map[string]any{"type": "string"},
 					"type":        "array",
 				},
+				"preview_alerts": map[string]any{
+					"description": "Replay the alert rule candidates over the past 24h and report when they would have fired (default true)",
+					"type":        "boolean",
+				},
 				"prometheus_url": map[string]any{
 					"description": "Prometheus server URL for querying metric metadata",
 					"type":        "string",
 				},
+				"sample_results": map[string]any{
+					"description": "Run each suggestion as an instant query and report how many series it returns with a few sample values, to pick the one returning a usable number of series (default false)",
+					"type":        "boolean",
+				},
+				"tags": map[string]any{
+					"description": "Tags of the dashboard the queries are for; key:value tags such as team:payments, service:checkout or severity:critical become alert rule labels",
+					"items":       map[string]any{"type": "string"},
+					"type":        "array",
+				},
+				"time_range": map[string]any{
+					"description": "Time range of the dashboard the queries are for (from, to); defaults to the configured dashboard time range. Over 30 days and more on a Thanos querier with downsampling, windows and panel intervals are aligned to the downsampled resolution",
+					"properties":  map[string]any{"from": map[string]any{"type": "string"}, "to": map[string]any{"type": "string"}},
+					"type":        "object",
+				},
 			},
 			"required": []string{"prometheus_url", "metric_names"},
 		},
@@ -48,18 +95,41 @@ func NewGeneratePromqlQueriesTool(logger *zap.Logger, promql promql.PromQL) serv
 
 // QueryGenerationResult represents the result for a single metric
 type QueryGenerationResult struct {
-	MetricName  string                   `json:"metric_name"`
-	MetricType  string                   `json:"metric_type"`
-	MetricHelp  string                   `json:"metric_help"`
-	Labels      []string                 `json:"labels,omitempty"`
+	MetricName string   `json:"metric_name"`
+	MetricType string   `json:"metric_type"`
+	MetricHelp string   `json:"metric_help"`
+	Labels     []string `json:"labels,omitempty"`
+	// Convention is the OpenTelemetry semantic convention the metric
+	// follows; its suggestions then come first and carry units and the
+	// panel group
+	Convention *promql.Convention `json:"convention,omitempty"`
+	// Temporality is "delta" for OpenTelemetry delta counters, whose
+	// suggestions sum the samples instead of taking rate()
+	Temporality string                   `json:"temporality,omitempty"`
 	Suggestions []promql.QuerySuggestion `json:"suggestions"`
-	Error       string                   `json:"error,omitempty"`
+	// AlertRules are alert rule candidates (absent series, counter resets)
+	AlertRules []promql.AlertRuleCandidate `json:"alert_rules,omitempty"`
+	// Stale is set when the metric has no samples in the dashboard time
+	// range, so panels built on it would render empty
+	Stale bool   `json:"stale,omitempty"`
+	Error string `json:"error,omitempty"`
 }
 
 // GeneratePromqlQueriesResponse represents the overall response
 type GeneratePromqlQueriesResponse struct {
 	PrometheusURL string                  `json:"prometheus_url"`
 	Results       []QueryGenerationResult `json:"results"`
+	// Server is the detected server type and version; the suggestions only
+	// use the optional query features it supports
+	Server *promql.ServerInfo `json:"server,omitempty"`
+	// Environment lists the configured environment labels the queries are
+	// scoped to; suggestions reference them as dashboard variables ($cluster)
+	Environment map[string]string `json:"environment,omitempty"`
+	// Partial is set when the call was cancelled or timed out before every
+	// metric was processed; SkippedMetrics lists the ones left out
+	Partial        bool     `json:"partial,omitempty"`
+	SkippedMetrics []string `json:"skipped_metrics,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
 }
 
 // GeneratePromqlQueriesHandler handles the generate_promql_queries tool execution
@@ -95,12 +165,74 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 		}
 	}
 
+	var opts promql.GenerateOptions
+	if intentsRaw, ok := args["intents"].([]any); ok {
+		for _, intentRaw := range intentsRaw {
+			intent, ok := intentRaw.(string)
+			if !ok {
+				continue
+			}
+			switch promql.QueryIntent(intent) {
+			case promql.QueryIntentTrend, promql.QueryIntentWeekOverWeek, promql.QueryIntentAnomaly:
+				opts.Intents = append(opts.Intents, promql.QueryIntent(intent))
+			default:
+				return "", fmt.Errorf("unsupported intent %q (expected trend, week_over_week or anomaly)", intent)
+			}
+		}
+	}
+
+	if satisfied, ok := args["apdex_satisfied_seconds"].(float64); ok {
+		if satisfied <= 0 {
+			return "", fmt.Errorf("apdex_satisfied_seconds must be positive")
+		}
+		opts.ApdexSatisfied = satisfied
+	}
+	if tolerating, ok := args["apdex_tolerating_seconds"].(float64); ok {
+		if tolerating <= opts.ApdexSatisfied {
+			return "", fmt.Errorf("apdex_tolerating_seconds must be greater than the satisfied threshold")
+		}
+		opts.ApdexTolerating = tolerating
+	}
+
+	tags := schemaStrings(args["tags"])
+	previewAlerts := true
+	if preview, ok := args["preview_alerts"].(bool); ok {
+		previewAlerts = preview
+	}
+	sampleResults, _ := args["sample_results"].(bool)
+
+	timeRange := extractTimeRange(args, dashboardDefaults(t.grafanaConfig))
+	from, to := timeRange["from"], timeRange["to"]
+	start, end, err := grafana.ParseTimeRange(from, to, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("invalid time_range: %w", err)
+	}
+	opts.Range = end.Sub(start)
+
 	response := GeneratePromqlQueriesResponse{
 		PrometheusURL: prometheusURL,
 		Results:       make([]QueryGenerationResult, 0, len(metricNames)),
 	}
+	if server := detectServer(ctx, t.logger, t.promql, prometheusURL); server != nil {
+		response.Server = server
+		opts.Features = &server.Features
+		if promql.DownsamplingResolution(opts.Range, opts.Features) > 0 {
+			response.Warnings = append(response.Warnings, downsamplingWarning(from, to))
+		}
+	}
+	if names := environmentLabels(t.environment); len(names) > 0 {
+		response.Environment = make(map[string]string, len(names))
+		for _, name := range names {
+			response.Environment[name] = t.environment.Labels[name]
+		}
+	}
+
+	for i, metricName := range metricNames {
+		if ctx.Err() != nil {
+			response.SkippedMetrics = metricNames[i:]
+			break
+		}
 
-	for _, metricName := range metricNames {
 		t.logger.Debug("processing metric", zap.String("metric", metricName))
 
 		result := QueryGenerationResult{
@@ -108,6 +240,10 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 		}
 
 		metricInfo, err := t.promql.GetMetricMetadata(ctx, prometheusURL, metricName)
+		if err != nil && ctx.Err() != nil {
+			response.SkippedMetrics = metricNames[i:]
+			break
+		}
 		if err != nil {
 			t.logger.Warn("failed to get metric metadata",
 				zap.String("metric", metricName),
@@ -120,8 +256,12 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 		result.MetricType = string(metricInfo.Type)
 		result.MetricHelp = metricInfo.Help
 		result.Labels = metricInfo.Labels
+		result.Temporality = metricInfo.Temporality
+		if convention, ok := promql.MatchConvention(metricInfo.Name); ok {
+			result.Convention = &convention
+		}
 
-		suggestions := t.promql.GenerateQueries(metricInfo)
+		suggestions := t.promql.GenerateQueries(metricInfo, opts)
 		if len(suggestions) == 0 {
 			t.logger.Warn("no suggestions generated",
 				zap.String("metric", metricName))
@@ -131,6 +271,15 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 		}
 
 		result.Suggestions = suggestions
+		result.AlertRules = t.promql.GenerateAlertRules(metricInfo)
+		applyAlertLabels(result.AlertRules, tags, t.alerting)
+		t.scopeToEnvironment(&result)
+		if sampleResults {
+			t.sampleSuggestions(ctx, prometheusURL, result.Suggestions)
+		}
+		if previewAlerts {
+			t.previewAlertRules(ctx, prometheusURL, result.AlertRules)
+		}
 		response.Results = append(response.Results, result)
 
 		t.logger.Info("generated queries for metric",
@@ -138,6 +287,20 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 			zap.Int("suggestion_count", len(suggestions)))
 	}
 
+	t.flagStaleMetrics(ctx, prometheusURL, &response, from, to, start, end)
+
+	if len(response.SkippedMetrics) > 0 {
+		if len(response.Results) == 0 {
+			return "", fmt.Errorf("query generation stopped before any metric was processed: %w", ctx.Err())
+		}
+		t.logger.Warn("returning partial query suggestions",
+			zap.Int("processed", len(response.Results)),
+			zap.Int("skipped", len(response.SkippedMetrics)),
+			zap.Error(ctx.Err()))
+		response.Partial = true
+		response.Warnings = append(response.Warnings, partialWarning(ctx.Err(), len(response.Results), len(metricNames), "metrics"))
+	}
+
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal response: %w", err)
@@ -145,3 +308,139 @@ func (t *GeneratePromqlQueriesTool) GeneratePromqlQueriesHandler(ctx context.Con
 
 	return string(jsonData), nil
 }
+
+// flagStaleMetrics marks the metrics that have no samples in the dashboard
+// time range; their metadata can outlive the series by a while
+func (t *GeneratePromqlQueriesTool) flagStaleMetrics(ctx context.Context, prometheusURL string, response *GeneratePromqlQueriesResponse, from, to string, start, end time.Time) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	var metrics []string
+	for _, result := range response.Results {
+		if result.Error == "" {
+			metrics = append(metrics, result.MetricName)
+		}
+	}
+
+	stale := staleMetrics(ctx, t.logger, t.promql, prometheusURL, metrics, start, end)
+	if len(stale) == 0 {
+		return
+	}
+
+	isStale := make(map[string]bool, len(stale))
+	for _, metric := range stale {
+		isStale[metric] = true
+		response.Warnings = append(response.Warnings, staleMetricWarning(metric, from, to))
+	}
+	for i := range response.Results {
+		if isStale[response.Results[i].MetricName] {
+			response.Results[i].Stale = true
+		}
+	}
+}
+
+// scopeToEnvironment adds the environment label matchers to the generated
+// queries: the runnable query and alert rules use the literal values, the
+// dashboard query references the constant variables ($cluster) and the
+// scope variable ($tenant)
+func (t *GeneratePromqlQueriesTool) scopeToEnvironment(result *QueryGenerationResult) {
+	literalMatchers := environmentMatchers(t.environment, false)
+	variableMatchers := environmentMatchers(t.environment, true)
+	if len(variableMatchers) == 0 {
+		return
+	}
+
+	for i := range result.Suggestions {
+		suggestion := &result.Suggestions[i]
+		dashboardQuery := suggestion.DashboardQuery
+		if dashboardQuery == "" {
+			dashboardQuery = suggestion.Query
+		}
+		suggestion.DashboardQuery = promql.InjectMatchers(dashboardQuery, variableMatchers)
+		suggestion.Query = promql.InjectMatchers(suggestion.Query, literalMatchers)
+	}
+
+	for i := range result.AlertRules {
+		result.AlertRules[i].Expr = promql.InjectMatchers(result.AlertRules[i].Expr, literalMatchers)
+	}
+}
+
+// previewAlertRules replays each alert rule over the past day so users can
+// spot noisy rules before creating them. A failed replay is reported on the
+// rule rather than failing the generation.
+func (t *GeneratePromqlQueriesTool) previewAlertRules(ctx context.Context, prometheusURL string, rules []promql.AlertRuleCandidate) {
+	end := time.Now().UTC().Truncate(alertPreviewStep)
+	for i := range rules {
+		rule := &rules[i]
+		var forDuration time.Duration
+		if rule.For != "" {
+			parsed, err := time.ParseDuration(rule.For)
+			if err != nil {
+				rule.Preview = failedAlertPreview(fmt.Sprintf("invalid for duration %q", rule.For))
+				continue
+			}
+			forDuration = parsed
+		}
+
+		series, err := t.promql.QueryRange(ctx, prometheusURL, rule.Expr, end.Add(-alertPreviewWindow), end, alertPreviewStep)
+		if err != nil {
+			t.logger.Warn("failed to preview alert rule",
+				zap.String("alert", rule.Name),
+				zap.Error(err))
+			rule.Preview = failedAlertPreview(fmt.Sprintf("failed to evaluate the rule: %v", err))
+			continue
+		}
+
+		preview := promql.EvaluateAlertPreview(series, forDuration, alertPreviewStep, alertPreviewWindow, end)
+		rule.Preview = &preview
+	}
+}
+
+// sampleSuggestions runs each suggestion as an instant query and records
+// what it returns. A failed query is reported on the suggestion rather than
+// failing the generation.
+func (t *GeneratePromqlQueriesTool) sampleSuggestions(ctx context.Context, prometheusURL string, suggestions []promql.QuerySuggestion) {
+	now := time.Now().UTC()
+	for i := range suggestions {
+		suggestion := &suggestions[i]
+		series, err := t.promql.Query(ctx, prometheusURL, suggestion.Query, now)
+		if err != nil {
+			t.logger.Warn("failed to sample query result",
+				zap.String("query", suggestion.Query),
+				zap.Error(err))
+			suggestion.Sample = &promql.ResultSample{Error: fmt.Sprintf("failed to run the query: %v", err)}
+			continue
+		}
+		sample := promql.SampleResult(series)
+		suggestion.Sample = &sample
+	}
+}
+
+// downsamplingWarning explains why the suggestions of a long time range
+// carry a panel interval and wider windows
+func downsamplingWarning(from, to string) string {
+	return fmt.Sprintf("the server answers %s to %s from downsampled data: suggestions carry its resolution as their panel interval, and rate() windows shorter than four samples were widened since they would return gaps or nothing",
+		from, to)
+}
+
+// failedAlertPreview reports why an alert rule could not be replayed
+func failedAlertPreview(reason string) *promql.AlertPreview {
+	preview := promql.EvaluateAlertPreview(nil, 0, alertPreviewStep, alertPreviewWindow, time.Time{})
+	preview.Error = reason
+	return &preview
+}
+
+// detectServer identifies the server behind prometheusURL so generated queries
+// can use its optional features. Detection is best effort: when it fails the
+// queries stick to what every Prometheus-compatible server evaluates.
+func detectServer(ctx context.Context, logger *zap.Logger, promqlSvc promql.PromQL, prometheusURL string) *promql.ServerInfo {
+	server, err := promqlSvc.DetectServer(ctx, prometheusURL)
+	if err != nil {
+		logger.Warn("failed to detect server features",
+			zap.String("prometheus_url", prometheusURL),
+			zap.Error(err))
+		return nil
+	}
+	return server
+}
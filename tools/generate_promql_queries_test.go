@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	zap "go.uber.org/zap"
 
+	config "github.com/inference-gateway/grafana-agent/config"
 	promql "github.com/inference-gateway/grafana-agent/internal/promql"
 	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
 )
@@ -16,7 +20,7 @@ func TestNewGeneratePromqlQueriesTool(t *testing.T) {
 	logger := zap.NewNop()
 	fakePromQL := &promqlfakes.FakePromQL{}
 
-	tool := NewGeneratePromqlQueriesTool(logger, fakePromQL)
+	tool := NewGeneratePromqlQueriesTool(logger, fakePromQL, &config.GrafanaConfig{}, &config.EnvironmentConfig{}, &config.AlertingConfig{})
 
 	if tool == nil {
 		t.Error("Expected non-nil tool")
@@ -259,3 +263,417 @@ func TestGeneratePromqlQueriesHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratePromqlQueriesHandler_Intents(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(http_requests_total[5m])"}})
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	_, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+		"intents":        []any{"trend", "week_over_week"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, opts := fakePromQL.GenerateQueriesArgsForCall(0)
+	if len(opts.Intents) != 2 || opts.Intents[0] != promql.QueryIntentTrend || opts.Intents[1] != promql.QueryIntentWeekOverWeek {
+		t.Errorf("Expected intents to be forwarded, got %+v", opts.Intents)
+	}
+
+	_, err = tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+		"intents":        []any{"yearly"},
+	})
+	if err == nil {
+		t.Error("Expected error for unsupported intent")
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_AlertRules(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(http_requests_total[5m])"}})
+	fakePromQL.GenerateAlertRulesReturns([]promql.AlertRuleCandidate{{Name: "HttpRequestsTotalAbsent", Expr: "absent(http_requests_total)"}})
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Results) != 1 || len(response.Results[0].AlertRules) != 1 {
+		t.Fatalf("Expected one alert rule candidate, got %+v", response.Results)
+	}
+	if response.Results[0].AlertRules[0].Expr != "absent(http_requests_total)" {
+		t.Errorf("Unexpected alert rule %+v", response.Results[0].AlertRules[0])
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_AlertPreview(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(http_requests_total[5m])"}})
+	fakePromQL.GenerateAlertRulesStub = func(*promql.MetricInfo) []promql.AlertRuleCandidate {
+		return []promql.AlertRuleCandidate{
+			{Name: "HttpRequestsTotalAbsent", Expr: "absent(http_requests_total)", For: "5m"},
+			{Name: "HttpRequestsTotalCounterReset", Expr: "resets(http_requests_total[15m]) > 0", For: "soon"},
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	var samples []promql.SamplePoint
+	for offset := 20 * time.Minute; offset >= 0; offset -= time.Minute {
+		samples = append(samples, promql.SamplePoint{Timestamp: now.Add(-offset), Value: 1})
+	}
+	fakePromQL.QueryRangeReturns([]promql.Series{{Labels: map[string]string{"job": "api"}, Samples: samples}}, nil)
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+	run := func(args map[string]any) []promql.AlertRuleCandidate {
+		t.Helper()
+		args["prometheus_url"] = "http://prometheus.test:9090"
+		args["metric_names"] = []any{"http_requests_total"}
+		result, err := tool.GeneratePromqlQueriesHandler(context.Background(), args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response GeneratePromqlQueriesResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response.Results[0].AlertRules
+	}
+
+	rules := run(map[string]any{})
+	preview := rules[0].Preview
+	if preview == nil || !preview.Fired || preview.TotalFirings != 1 || !preview.Firings[0].Ongoing {
+		t.Errorf("Expected an ongoing firing, got %+v", preview)
+	}
+	if _, _, query, _, _, step := fakePromQL.QueryRangeArgsForCall(0); query != "absent(http_requests_total)" || step != time.Minute {
+		t.Errorf("Unexpected preview query %q at step %s", query, step)
+	}
+	if rules[1].Preview == nil || rules[1].Preview.Error == "" {
+		t.Errorf("Expected an invalid for duration to be reported, got %+v", rules[1].Preview)
+	}
+	if fakePromQL.QueryRangeCallCount() != 1 {
+		t.Errorf("Expected only the valid rule to be replayed, got %d queries", fakePromQL.QueryRangeCallCount())
+	}
+
+	if rules := run(map[string]any{"preview_alerts": false}); rules[0].Preview != nil {
+		t.Errorf("Expected no preview when disabled, got %+v", rules[0].Preview)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_SampleResults(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesStub = func(metricInfo *promql.MetricInfo, opts promql.GenerateOptions) []promql.QuerySuggestion {
+		return []promql.QuerySuggestion{
+			{Query: "sum(rate(http_requests_total[5m]))"},
+			{Query: "sum by (path) (rate(http_requests_total[5m]))"},
+		}
+	}
+	fakePromQL.QueryStub = func(ctx context.Context, prometheusURL, query string, at time.Time) ([]promql.Series, error) {
+		if strings.Contains(query, "path") {
+			return nil, errors.New("query timed out")
+		}
+		return []promql.Series{{Labels: map[string]string{}, Samples: []promql.SamplePoint{{Value: 12.5}}}}, nil
+	}
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+	run := func(args map[string]any) []promql.QuerySuggestion {
+		args["prometheus_url"] = "http://prometheus.test:9090"
+		args["metric_names"] = []any{"http_requests_total"}
+		args["preview_alerts"] = false
+		result, err := tool.GeneratePromqlQueriesHandler(context.Background(), args)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		var response GeneratePromqlQueriesResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Expected valid JSON result, got error: %v", err)
+		}
+		return response.Results[0].Suggestions
+	}
+
+	suggestions := run(map[string]any{"sample_results": true})
+	if sample := suggestions[0].Sample; sample == nil || sample.Series != 1 || sample.Values[0].Value != "12.5" {
+		t.Errorf("Expected the sampled result, got %+v", sample)
+	}
+	if sample := suggestions[1].Sample; sample == nil || !strings.Contains(sample.Error, "query timed out") {
+		t.Errorf("Expected the failed query reported on the suggestion, got %+v", sample)
+	}
+
+	calls := fakePromQL.QueryCallCount()
+	if suggestions := run(map[string]any{}); suggestions[0].Sample != nil || fakePromQL.QueryCallCount() != calls {
+		t.Errorf("Expected no sampling by default, got %+v", suggestions[0].Sample)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_Environment(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{
+		Query:          "sum(rate(http_requests_total[5m]))",
+		DashboardQuery: "sum(rate(http_requests_total[$__rate_interval]))",
+	}})
+	fakePromQL.GenerateAlertRulesReturns([]promql.AlertRuleCandidate{{Name: "HttpRequestsTotalAbsent", Expr: "absent(http_requests_total)"}})
+
+	tool := &GeneratePromqlQueriesTool{
+		logger:      zap.NewNop(),
+		promql:      fakePromQL,
+		environment: &config.EnvironmentConfig{Labels: map[string]string{"region": "eu-west-1", "cluster": "prod-eu", "__bad": "x"}},
+	}
+
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if len(response.Environment) != 2 || response.Environment["cluster"] != "prod-eu" {
+		t.Errorf("Expected the valid environment labels, got %v", response.Environment)
+	}
+	suggestion := response.Results[0].Suggestions[0]
+	if suggestion.Query != `sum(rate(http_requests_total{cluster="prod-eu", region="eu-west-1"}[5m]))` {
+		t.Errorf("Expected runnable query scoped to literal environment values, got %s", suggestion.Query)
+	}
+	if suggestion.DashboardQuery != `sum(rate(http_requests_total{cluster="$cluster", region="$region"}[$__rate_interval]))` {
+		t.Errorf("Expected dashboard query scoped to environment variables, got %s", suggestion.DashboardQuery)
+	}
+	if got := response.Results[0].AlertRules[0].Expr; got != `absent(http_requests_total{cluster="prod-eu", region="eu-west-1"})` {
+		t.Errorf("Expected alert rule scoped to literal environment values, got %s", got)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_AlertRoutingLabels(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(http_requests_total[5m])"}})
+	fakePromQL.GenerateAlertRulesReturns([]promql.AlertRuleCandidate{
+		{Name: "HttpRequestsTotalAbsent", Expr: "absent(http_requests_total)", Severity: "critical"},
+		{Name: "HttpRequestsTotalCounterReset", Expr: "resets(http_requests_total[15m]) > 0", Severity: "info"},
+	})
+
+	tool := &GeneratePromqlQueriesTool{
+		logger: zap.NewNop(),
+		promql: fakePromQL,
+		alerting: &config.AlertingConfig{
+			Labels:      map[string]string{"team": "platform", "owner": "sre"},
+			RoutingTags: []string{"team", "service", "severity"},
+		},
+	}
+
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+		"tags":           []any{"payments", "team:payments", "Service = checkout", "region:eu"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	rules := response.Results[0].AlertRules
+	expected := map[string]string{"team": "payments", "service": "checkout", "owner": "sre", "severity": "critical"}
+	if !reflect.DeepEqual(rules[0].Labels, expected) {
+		t.Errorf("Labels = %v, want %v", rules[0].Labels, expected)
+	}
+	if rules[1].Labels["severity"] != "info" || rules[1].Severity != "info" {
+		t.Errorf("Expected rule severity to be kept, got %v", rules[1].Labels)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_StaleMetrics(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataStub = func(ctx context.Context, prometheusURL, metricName string) (*promql.MetricInfo, error) {
+		return &promql.MetricInfo{Name: metricName, Type: promql.MetricTypeHistogram}, nil
+	}
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "histogram_quantile(0.95, rate(x_bucket[5m]))"}})
+	fakePromQL.SeriesReturns([]map[string]string{{"__name__": "rpc_duration_seconds_bucket", "le": "0.1"}}, nil)
+
+	tool := &GeneratePromqlQueriesTool{
+		logger:        zap.NewNop(),
+		promql:        fakePromQL,
+		grafanaConfig: &config.GrafanaConfig{Defaults: config.GrafanaDefaultsConfig{TimeFrom: "now-1h", TimeTo: "now"}},
+	}
+
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"rpc_duration_seconds", "old_duration_seconds"},
+		"preview_alerts": false,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if response.Results[0].Stale || !response.Results[1].Stale {
+		t.Errorf("expected only old_duration_seconds to be stale, got %+v", response.Results)
+	}
+	if len(response.Warnings) != 1 || !strings.Contains(response.Warnings[0], "between now-1h and now") {
+		t.Errorf("expected a stale metric warning for the configured range, got %v", response.Warnings)
+	}
+	if _, _, _, start, end := fakePromQL.SeriesArgsForCall(0); end.Sub(start) != time.Hour {
+		t.Errorf("expected the configured 1h range, got %s", end.Sub(start))
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_OpenTelemetry(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataStub = func(ctx context.Context, prometheusURL, metricName string) (*promql.MetricInfo, error) {
+		if metricName == "checkout_orders" {
+			return &promql.MetricInfo{Name: metricName, Type: promql.MetricTypeCounter, Temporality: promql.TemporalityDelta}, nil
+		}
+		return &promql.MetricInfo{Name: metricName, Type: promql.MetricTypeHistogram}, nil
+	}
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "sum_over_time(checkout_orders[5m])"}})
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"checkout_orders", "http_server_request_duration_seconds"},
+		"preview_alerts": false,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if response.Results[0].Temporality != promql.TemporalityDelta || response.Results[0].Convention != nil {
+		t.Errorf("expected a delta counter without convention, got %+v", response.Results[0])
+	}
+	if convention := response.Results[1].Convention; convention == nil || convention.Name != "http.server.request.duration" || convention.Unit != "s" {
+		t.Errorf("expected the HTTP server duration convention, got %+v", convention)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_ServerFeatures(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.DetectServerReturns(&promql.ServerInfo{
+		Type:     promql.ServerTypeMimir,
+		Version:  "2.12.0",
+		Features: promql.ServerFeatures{NativeHistograms: true, Exemplars: true, AtModifier: true},
+	}, nil)
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(http_requests_total[5m])"}})
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://mimir.test/prometheus",
+		"metric_names":   []any{"http_requests_total"},
+		"preview_alerts": false,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if response.Server == nil || response.Server.Type != promql.ServerTypeMimir {
+		t.Errorf("expected the detected server in the response, got %+v", response.Server)
+	}
+	if _, opts := fakePromQL.GenerateQueriesArgsForCall(0); opts.Features == nil || !opts.Features.NativeHistograms {
+		t.Errorf("expected the server features to be forwarded, got %+v", opts.Features)
+	}
+
+	fakePromQL.DetectServerReturns(nil, errors.New("buildinfo request failed with status 404"))
+	if _, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"http_requests_total"},
+		"preview_alerts": false,
+	}); err != nil {
+		t.Fatalf("detection failures should not fail generation, got: %v", err)
+	}
+	if _, opts := fakePromQL.GenerateQueriesArgsForCall(1); opts.Features != nil {
+		t.Errorf("expected no features without detection, got %+v", opts.Features)
+	}
+}
+
+func TestTagLabels(t *testing.T) {
+	labels := tagLabels([]string{"severity:critical", "team=", "bad-key:x", "team:a", "team:b"}, []string{"team", "severity", "bad-key"})
+	expected := map[string]string{"severity": "critical", "team": "b"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("tagLabels() = %v, want %v", labels, expected)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_Downsampling(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataReturns(&promql.MetricInfo{Name: "http_requests_total", Type: promql.MetricTypeCounter}, nil)
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "sum(rate(http_requests_total[5m]))"}})
+	fakePromQL.DetectServerReturns(&promql.ServerInfo{Type: promql.ServerTypeThanos, Version: "0.35.1", Features: promql.ServerFeatures{Downsampling: true}}, nil)
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+	run := func(timeRange map[string]any) (GeneratePromqlQueriesResponse, error) {
+		result, err := tool.GeneratePromqlQueriesHandler(context.Background(), map[string]any{
+			"prometheus_url": "http://thanos-query.test:9090",
+			"metric_names":   []any{"http_requests_total"},
+			"preview_alerts": false,
+			"time_range":     timeRange,
+		})
+		var response GeneratePromqlQueriesResponse
+		if err == nil {
+			err = json.Unmarshal([]byte(result), &response)
+		}
+		return response, err
+	}
+
+	response, err := run(map[string]any{"from": "now-30d", "to": "now"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, opts := fakePromQL.GenerateQueriesArgsForCall(0); opts.Range != 30*24*time.Hour {
+		t.Errorf("Expected the 30d range passed to query generation, got %s", opts.Range)
+	}
+	if len(response.Warnings) == 0 || !strings.Contains(response.Warnings[0], "downsampled data") {
+		t.Errorf("Expected a downsampling warning, got %v", response.Warnings)
+	}
+
+	response, err = run(map[string]any{"from": "now-6h"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(response.Warnings) != 1 || strings.Contains(response.Warnings[0], "downsampled data") {
+		t.Errorf("Expected only the stale metric warning for a short range, got %v", response.Warnings)
+	}
+
+	if _, err := run(map[string]any{"from": "yesterday"}); err == nil || !strings.Contains(err.Error(), "invalid time_range") {
+		t.Errorf("Expected an invalid time_range error, got %v", err)
+	}
+}
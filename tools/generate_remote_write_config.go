@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	remotewrite "github.com/inference-gateway/grafana-agent/internal/remotewrite"
+)
+
+// defaultSeriesLimit is the active series count above which a metric is
+// dropped from the shipped metrics
+const defaultSeriesLimit = 10000
+
+// GenerateRemoteWriteConfigTool struct holds the tool with services
+type GenerateRemoteWriteConfigTool struct {
+	logger       *zap.Logger
+	promql       promql.PromQL
+	grafanacloud grafanacloud.GrafanaCloud
+}
+
+// NewGenerateRemoteWriteConfigTool creates a new generate_remote_write_config tool
+func NewGenerateRemoteWriteConfigTool(logger *zap.Logger, promql promql.PromQL, grafanacloud grafanacloud.GrafanaCloud) server.Tool {
+	tool := &GenerateRemoteWriteConfigTool{
+		logger:       logger,
+		promql:       promql,
+		grafanacloud: grafanacloud,
+	}
+	return newValidatedTool(
+		"generate_remote_write_config",
+		"Generates Prometheus and Grafana Alloy remote_write configuration for shipping metrics to Grafana Cloud, with relabel rules that keep the chosen metrics and drop the high-cardinality ones found in Prometheus",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"metric_names": map[string]any{
+					"description": "Metrics to ship, e.g. the ones discovered for a dashboard; all metrics are shipped when omitted",
+					"items":       map[string]any{"type": "string"},
+					"type":        "array",
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL the series counts are read from",
+					"type":        "string",
+				},
+				"remote_write_url": map[string]any{
+					"description": "remote_write endpoint, e.g. https://prometheus-prod-01-eu-west-0.grafana.net/api/prom/push (resolved from stack when omitted)",
+					"type":        "string",
+				},
+				"series_limit": map[string]any{
+					"description": "Active series count above which a metric is dropped (default 10000)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack slug or name (e.g. \"prod\") whose metrics endpoint and instance ID to use",
+					"type":        "string",
+				},
+				"username": map[string]any{
+					"description": "Basic auth username, the Grafana Cloud Prometheus instance ID (resolved from stack when omitted)",
+					"type":        "string",
+				},
+			},
+			"required": []string{"prometheus_url"},
+		},
+		tool.GenerateRemoteWriteConfigHandler,
+	)
+}
+
+// DroppedMetric is a metric left out of the shipped metrics
+type DroppedMetric struct {
+	Metric string `json:"metric"`
+	Series int    `json:"series"`
+}
+
+// GenerateRemoteWriteConfigResponse represents the generated configuration
+type GenerateRemoteWriteConfigResponse struct {
+	RemoteWriteURL string `json:"remote_write_url"`
+	Username       string `json:"username,omitempty"`
+	// Metrics is the number of metrics shipped, 0 when all are
+	Metrics int             `json:"metrics"`
+	Dropped []DroppedMetric `json:"dropped,omitempty"`
+	// Rules are the write relabel rules in both snippets
+	Rules []remotewrite.Rule `json:"rules,omitempty"`
+	// Prometheus is the remote_write section for prometheus.yml; the token
+	// is read from remotewrite.PasswordFile
+	Prometheus string `json:"prometheus"`
+	// Alloy is the prometheus.remote_write component; the token is read
+	// from the GRAFANA_CLOUD_TOKEN environment variable
+	Alloy string `json:"alloy"`
+}
+
+// GenerateRemoteWriteConfigHandler handles the generate_remote_write_config tool execution
+func (t *GenerateRemoteWriteConfigTool) GenerateRemoteWriteConfigHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "generate_remote_write_config")
+	defer span.End()
+
+	prometheusURL, _ := args["prometheus_url"].(string)
+	remoteWriteURL, _ := args["remote_write_url"].(string)
+	username, _ := args["username"].(string)
+	if stack, _ := args["stack"].(string); strings.TrimSpace(stack) != "" {
+		resolved, err := t.grafanacloud.ResolveStack(ctx, stack)
+		if err != nil {
+			return "", err
+		}
+		if remoteWriteURL == "" {
+			if resolved.PrometheusURL == "" {
+				return "", fmt.Errorf("grafana cloud stack %s has no hosted metrics instance", resolved.Slug)
+			}
+			remoteWriteURL = strings.TrimRight(resolved.PrometheusURL, "/") + "/api/prom/push"
+		}
+		if username == "" && resolved.PrometheusUserID != 0 {
+			username = strconv.Itoa(resolved.PrometheusUserID)
+		}
+	}
+	if remoteWriteURL == "" {
+		return "", fmt.Errorf("remote_write_url or stack is required")
+	}
+
+	seriesLimit, ok := toInt(args["series_limit"])
+	if !ok || seriesLimit <= 0 {
+		seriesLimit = defaultSeriesLimit
+	}
+	metricNames := schemaStrings(args["metric_names"])
+
+	counts, err := t.promql.GetSeriesCounts(ctx, prometheusURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to count series: %w", err)
+	}
+
+	response := GenerateRemoteWriteConfigResponse{
+		RemoteWriteURL: remoteWriteURL,
+		Username:       username,
+		Metrics:        len(metricNames),
+		Dropped:        highCardinalityMetrics(counts, metricNames, seriesLimit),
+	}
+	if len(metricNames) > 0 {
+		response.Rules = append(response.Rules, remotewrite.MetricRule(remotewrite.ActionKeep, metricNames))
+	}
+	if len(response.Dropped) > 0 {
+		dropped := make([]string, 0, len(response.Dropped))
+		for _, metric := range response.Dropped {
+			dropped = append(dropped, metric.Metric)
+		}
+		response.Rules = append(response.Rules, remotewrite.MetricRule(remotewrite.ActionDrop, dropped))
+	}
+
+	cfg := remotewrite.Config{URL: remoteWriteURL, Username: username, Rules: response.Rules}
+	data, err := remotewrite.Prometheus(cfg)
+	if err != nil {
+		return "", err
+	}
+	response.Prometheus = string(data)
+	response.Alloy = remotewrite.Alloy(cfg)
+
+	t.logger.Info("generated remote_write config",
+		zap.Int("metrics", len(metricNames)),
+		zap.Int("dropped", len(response.Dropped)))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// highCardinalityMetrics returns the metrics with more active series than the
+// limit, highest first. Histogram and summary metrics count the series of all
+// their suffixes. Without metricNames every metric is considered.
+func highCardinalityMetrics(counts map[string]int, metricNames []string, limit int) []DroppedMetric {
+	var dropped []DroppedMetric
+	if len(metricNames) == 0 {
+		for metric, series := range counts {
+			if series > limit {
+				dropped = append(dropped, DroppedMetric{Metric: metric, Series: series})
+			}
+		}
+	}
+	for _, metric := range metricNames {
+		series := counts[metric]
+		for _, suffix := range seriesSuffixes {
+			series += counts[metric+suffix]
+		}
+		if series > limit {
+			dropped = append(dropped, DroppedMetric{Metric: metric, Series: series})
+		}
+	}
+
+	sort.Slice(dropped, func(i, j int) bool {
+		if dropped[i].Series != dropped[j].Series {
+			return dropped[i].Series > dropped[j].Series
+		}
+		return dropped[i].Metric < dropped[j].Metric
+	})
+	return dropped
+}
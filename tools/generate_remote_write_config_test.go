@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+	remotewrite "github.com/inference-gateway/grafana-agent/internal/remotewrite"
+)
+
+func TestGenerateRemoteWriteConfigHandler(t *testing.T) {
+	fake := &promqlfakes.FakePromQL{}
+	fake.GetSeriesCountsReturns(map[string]int{
+		"up":                                    12,
+		"http_requests_total":                   800,
+		"http_request_duration_seconds_bucket":  24000,
+		"http_request_duration_seconds_count":   2000,
+		"container_network_receive_bytes_total": 50000,
+	}, nil)
+	cloud := &mockGrafanaCloudService{stacks: []grafanacloud.Stack{
+		{Slug: "acmeprod", PrometheusURL: "https://prometheus-prod-01-eu-west-0.grafana.net", PrometheusUserID: 123456},
+		{Slug: "acmelab"},
+	}}
+	tool := &GenerateRemoteWriteConfigTool{logger: zap.NewNop(), promql: fake, grafanacloud: cloud}
+
+	run := func(t *testing.T, args map[string]any) GenerateRemoteWriteConfigResponse {
+		t.Helper()
+		args["prometheus_url"] = "http://prometheus.test:9090"
+		result, err := tool.GenerateRemoteWriteConfigHandler(context.Background(), args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response GenerateRemoteWriteConfigResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response
+	}
+
+	t.Run("ships the chosen metrics to a stack", func(t *testing.T) {
+		response := run(t, map[string]any{
+			"stack":        "acmeprod",
+			"metric_names": []any{"up", "http_requests_total", "http_request_duration_seconds"},
+		})
+
+		if response.RemoteWriteURL != "https://prometheus-prod-01-eu-west-0.grafana.net/api/prom/push" || response.Username != "123456" {
+			t.Errorf("expected the stack's metrics endpoint, got %s as %s", response.RemoteWriteURL, response.Username)
+		}
+		if len(response.Dropped) != 1 || response.Dropped[0].Metric != "http_request_duration_seconds" || response.Dropped[0].Series != 26000 {
+			t.Errorf("expected the histogram to be dropped, got %+v", response.Dropped)
+		}
+		if len(response.Rules) != 2 || response.Rules[0].Action != remotewrite.ActionKeep || response.Rules[1].Action != remotewrite.ActionDrop {
+			t.Fatalf("expected a keep and a drop rule, got %+v", response.Rules)
+		}
+		if !strings.Contains(response.Prometheus, "username: \"123456\"") || !strings.Contains(response.Alloy, "write_relabel_config {") {
+			t.Errorf("unexpected snippets:\n%s\n%s", response.Prometheus, response.Alloy)
+		}
+	})
+
+	t.Run("drops high-cardinality metrics when shipping everything", func(t *testing.T) {
+		response := run(t, map[string]any{"remote_write_url": "http://mimir:9009/api/v1/push", "series_limit": 10000.0})
+
+		if len(response.Dropped) != 2 || response.Dropped[0].Metric != "container_network_receive_bytes_total" {
+			t.Errorf("expected the metrics over the limit, highest first, got %+v", response.Dropped)
+		}
+		if len(response.Rules) != 1 || response.Rules[0].Action != remotewrite.ActionDrop {
+			t.Errorf("expected a single drop rule, got %+v", response.Rules)
+		}
+		if strings.Contains(response.Prometheus, "basic_auth") {
+			t.Errorf("expected no basic auth without a username, got %s", response.Prometheus)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			args    map[string]any
+			wantErr string
+		}{
+			{map[string]any{}, "remote_write_url or stack is required"},
+			{map[string]any{"stack": "acmelab"}, "no hosted metrics instance"},
+			{map[string]any{"stack": "missing"}, "not found"},
+		} {
+			tt.args["prometheus_url"] = "http://prometheus.test:9090"
+			_, err := tool.GenerateRemoteWriteConfigHandler(context.Background(), tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	scrapeconfig "github.com/inference-gateway/grafana-agent/internal/scrapeconfig"
+)
+
+// defaultStarterPanels is the number of panels of a starter dashboard,
+// including the up panel
+const defaultStarterPanels = 12
+
+// GenerateScrapeConfigTool struct holds the tool with services
+type GenerateScrapeConfigTool struct {
+	logger        *zap.Logger
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+	environment   *config.EnvironmentConfig
+}
+
+// NewGenerateScrapeConfigTool creates a new generate_scrape_config tool
+func NewGenerateScrapeConfigTool(logger *zap.Logger, promql promql.PromQL, grafanaConfig *config.GrafanaConfig, environment *config.EnvironmentConfig) server.Tool {
+	tool := &GenerateScrapeConfigTool{
+		logger:        logger,
+		promql:        promql,
+		grafanaConfig: grafanaConfig,
+		environment:   environment,
+	}
+	return newValidatedTool(
+		"generate_scrape_config",
+		"Onboards a new service from its /metrics URL: scrapes and parses the exposition directly (text format or OpenMetrics), infers the job name, and returns a Prometheus scrape_config, the equivalent Grafana Alloy component and a starter dashboard for its metrics",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_title": map[string]any{
+					"description": "Title of the starter dashboard (default \"<job> overview\")",
+					"type":        "string",
+				},
+				"job_name": map[string]any{
+					"description": "Job name to use instead of the inferred one",
+					"type":        "string",
+				},
+				"max_panels": map[string]any{
+					"description": "Maximum number of panels on the starter dashboard (default 12)",
+					"type":        "integer",
+					"minimum":     1,
+					"maximum":     48,
+				},
+				"metrics_url": map[string]any{
+					"description": "The service's metrics endpoint, e.g. http://checkout:8080/metrics",
+					"type":        "string",
+				},
+				"scrape_interval": map[string]any{
+					"description": "Scrape interval (default 30s)",
+					"type":        "string",
+				},
+			},
+			"required": []string{"metrics_url"},
+		},
+		tool.GenerateScrapeConfigHandler,
+	)
+}
+
+// GenerateScrapeConfigResponse represents the onboarding configuration of a service
+type GenerateScrapeConfigResponse struct {
+	ScrapeConfig scrapeconfig.Config `json:"scrape_config"`
+	// JobSource tells where the job name came from: argument, target_info,
+	// exporter, hostname, metric_prefix or default
+	JobSource string `json:"job_source"`
+	Metrics   int    `json:"metrics"`
+	// Prometheus is the scrape_configs section for prometheus.yml
+	Prometheus string `json:"prometheus"`
+	// Alloy is the prometheus.scrape component, forwarding to the
+	// component generated by generate_remote_write_config
+	Alloy     string         `json:"alloy"`
+	Dashboard map[string]any `json:"dashboard"`
+	Summary   string         `json:"summary"`
+	// Uncharted lists the service's metrics left off the starter dashboard
+	Uncharted []string `json:"uncharted,omitempty"`
+}
+
+// GenerateScrapeConfigHandler handles the generate_scrape_config tool execution
+func (t *GenerateScrapeConfigTool) GenerateScrapeConfigHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "generate_scrape_config")
+	defer span.End()
+
+	metricsURL, _ := args["metrics_url"].(string)
+	scrapeConfig, err := scrapeconfig.FromURL(strings.TrimSpace(metricsURL))
+	if err != nil {
+		return "", err
+	}
+	if interval, _ := args["scrape_interval"].(string); interval != "" {
+		if _, err := time.ParseDuration(interval); err != nil {
+			return "", fmt.Errorf("scrape_interval %q is not a valid duration", interval)
+		}
+		scrapeConfig.ScrapeInterval = interval
+	}
+
+	families, err := t.promql.ScrapeTarget(ctx, metricsURL)
+	if err != nil {
+		return "", err
+	}
+	if len(families) == 0 {
+		return "", fmt.Errorf("%s exposes no metrics", metricsURL)
+	}
+
+	response := GenerateScrapeConfigResponse{JobSource: scrapeconfig.JobSourceArgument, Metrics: len(families)}
+	scrapeConfig.JobName, _ = args["job_name"].(string)
+	if scrapeConfig.JobName == "" {
+		scrapeConfig.JobName, response.JobSource = scrapeconfig.InferJobName(scrapeConfig, families)
+	}
+	if names := environmentLabels(t.environment); len(names) > 0 {
+		scrapeConfig.Labels = make(map[string]string, len(names))
+		for _, name := range names {
+			scrapeConfig.Labels[name] = t.environment.Labels[name]
+		}
+	}
+	response.ScrapeConfig = scrapeConfig
+
+	data, err := scrapeconfig.Prometheus(scrapeConfig)
+	if err != nil {
+		return "", err
+	}
+	response.Prometheus = string(data)
+	response.Alloy = scrapeconfig.Alloy(scrapeConfig)
+
+	maxPanels, ok := toInt(args["max_panels"])
+	if !ok || maxPanels <= 0 {
+		maxPanels = defaultStarterPanels
+	}
+	panels := []map[string]any{starterPanel("Up", "stat", fmt.Sprintf(`up{job=%q}`, scrapeConfig.JobName), "", "")}
+	groups := []string{""}
+	jobMatcher := []promql.LabelMatcher{{Name: "job", Op: "=", Value: scrapeConfig.JobName}}
+	for _, family := range families {
+		info := promql.MetricInfoFromFamily(family)
+		if promql.IsRuntimeMetric(info.Name) || family.Type == "info" {
+			continue
+		}
+		if len(panels) >= maxPanels {
+			response.Uncharted = append(response.Uncharted, info.Name)
+			continue
+		}
+
+		suggestion := t.promql.GetBestQuery(t.promql.GenerateQueries(&info, promql.GenerateOptions{}))
+		query := suggestion.DashboardQuery
+		if query == "" {
+			query = suggestion.Query
+		}
+		if query == "" {
+			continue
+		}
+		panels = append(panels, starterPanel(defaultPanelTitle(info.Name, suggestion), suggestion.VisualizationType,
+			promql.InjectMatchers(query, jobMatcher), suggestion.PanelDescription, suggestion.Unit))
+		groups = append(groups, suggestion.Group)
+	}
+
+	defaults := dashboardDefaults(t.grafanaConfig)
+	title := dashboardTitle(t.grafanaConfig, getStringOrDefault(args, "dashboard_title", scrapeConfig.JobName+" overview"))
+	tags := []any{serviceTag(t.grafanaConfig, scrapeConfig.JobName)}
+	dashboard := dashboardModel(title, groupPanelsInRows(panels, groups), map[string]any{"tags": tags}, defaults)
+	correctTags(t.grafanaConfig, dashboard, tags)
+	if variables := environmentVariables(t.environment, nil); len(variables) > 0 {
+		dashboard["templating"] = map[string]any{"list": variables}
+	}
+	scopePanelsToEnvironment(dashboard, environmentMatchers(t.environment, true))
+	if err := enforceScope(dashboard, t.environment); err != nil {
+		return "", err
+	}
+	response.Dashboard = dashboard
+	response.Summary = summarizeDashboard(dashboard, defaults.Language)
+
+	t.logger.Info("generated scrape config",
+		zap.String("job", scrapeConfig.JobName),
+		zap.String("job_source", response.JobSource),
+		zap.Int("metrics", len(families)),
+		zap.Int("panels", len(panels)))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// starterPanel is a single-query panel definition for dashboardModel
+func starterPanel(title, panelType, expr, description, unit string) map[string]any {
+	if panelType == "" {
+		panelType = "timeseries"
+	}
+	panel := map[string]any{
+		"title":   title,
+		"type":    panelType,
+		"targets": []any{map[string]any{"refId": "A", "expr": expr}},
+	}
+	if description != "" {
+		panel["description"] = description
+	}
+	if unit != "" {
+		panel["fieldConfig"] = extractFieldConfig(map[string]any{})
+		fieldConfigDefaults(panel)["unit"] = unit
+	}
+	return panel
+}
+
+// groupPanelsInRows lays panels out two per line, the ungrouped ones first
+// and then one row per group (e.g. "HTTP server" for the OpenTelemetry
+// HTTP metrics), in the order the groups first appear; groups[i] is the
+// group of panels[i]
+func groupPanelsInRows(panels []map[string]any, groups []string) []any {
+	order := []string{""}
+	members := map[string][]map[string]any{}
+	for i, panel := range panels {
+		if _, seen := members[groups[i]]; !seen && groups[i] != "" {
+			order = append(order, groups[i])
+		}
+		members[groups[i]] = append(members[groups[i]], panel)
+	}
+
+	var result []any
+	y := 0
+	for _, group := range order {
+		if len(members[group]) == 0 {
+			continue
+		}
+		if group != "" {
+			result = append(result, map[string]any{
+				"type":    "row",
+				"title":   group,
+				"gridPos": map[string]any{"x": 0, "y": y, "w": 24, "h": 1},
+			})
+			y++
+		}
+		for i, panel := range members[group] {
+			panel["gridPos"] = map[string]any{"x": (i % 2) * 12, "y": y + (i/2)*8, "w": 12, "h": 8}
+			result = append(result, panel)
+		}
+		y += (len(members[group]) + 1) / 2 * 8
+	}
+	return result
+}
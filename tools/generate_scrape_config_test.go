@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	exposition "github.com/inference-gateway/grafana-agent/internal/exposition"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+	scrapeconfig "github.com/inference-gateway/grafana-agent/internal/scrapeconfig"
+)
+
+func TestGenerateScrapeConfigHandler(t *testing.T) {
+	fake := &promqlfakes.FakePromQL{}
+	fake.ScrapeTargetReturns([]exposition.Family{
+		{Name: "go_goroutines", Type: "gauge", Samples: []exposition.Sample{{Name: "go_goroutines", Value: 12}}},
+		{Name: "checkout_orders", Type: "counter", Samples: []exposition.Sample{{Name: "checkout_orders_total", Value: 10}}},
+		{Name: "checkout_cart_size", Type: "gauge", Samples: []exposition.Sample{{Name: "checkout_cart_size", Value: 3}}},
+	}, nil)
+	fake.GetBestQueryReturns(promql.QuerySuggestion{
+		Query:             "sum(rate(checkout_orders_total[5m]))",
+		DashboardQuery:    "sum(rate(checkout_orders_total[$__rate_interval]))",
+		VisualizationType: "timeseries",
+	})
+	tool := &GenerateScrapeConfigTool{
+		logger:      zap.NewNop(),
+		promql:      fake,
+		environment: &config.EnvironmentConfig{Labels: map[string]string{"cluster": "prod"}},
+	}
+
+	run := func(t *testing.T, args map[string]any) GenerateScrapeConfigResponse {
+		t.Helper()
+		result, err := tool.GenerateScrapeConfigHandler(context.Background(), args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response GenerateScrapeConfigResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response
+	}
+
+	t.Run("onboards the service end to end", func(t *testing.T) {
+		response := run(t, map[string]any{"metrics_url": "http://10.0.0.7:8080/metrics"})
+
+		if response.ScrapeConfig.JobName != "checkout" || response.JobSource != scrapeconfig.JobSourceMetricPrefix {
+			t.Errorf("expected the job to be named after the metric prefix, got %s from %s", response.ScrapeConfig.JobName, response.JobSource)
+		}
+		if response.ScrapeConfig.Labels["cluster"] != "prod" {
+			t.Errorf("expected the environment labels on the target, got %+v", response.ScrapeConfig.Labels)
+		}
+		if !strings.Contains(response.Prometheus, "job_name: checkout") || !strings.Contains(response.Alloy, `prometheus.scrape "checkout"`) {
+			t.Errorf("unexpected configurations:\n%s\n%s", response.Prometheus, response.Alloy)
+		}
+		if fake.GenerateQueriesCallCount() != 2 {
+			t.Errorf("expected the runtime metrics to be left out, got %d generated", fake.GenerateQueriesCallCount())
+		}
+
+		panels, _ := response.Dashboard["panels"].([]any)
+		if len(panels) != 3 {
+			t.Fatalf("expected the up panel and one panel per metric, got %d", len(panels))
+		}
+		expr := panels[1].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"].(string)
+		if !strings.Contains(expr, `job="checkout"`) || !strings.Contains(expr, `cluster="$cluster"`) {
+			t.Errorf("expected the query scoped to the job and environment, got %s", expr)
+		}
+	})
+
+	t.Run("caps the starter dashboard", func(t *testing.T) {
+		response := run(t, map[string]any{
+			"metrics_url": "http://checkout.payments.svc:8080/metrics",
+			"job_name":    "checkout-api",
+			"max_panels":  2,
+		})
+
+		if response.ScrapeConfig.JobName != "checkout-api" || response.JobSource != scrapeconfig.JobSourceArgument {
+			t.Errorf("expected the given job name, got %s from %s", response.ScrapeConfig.JobName, response.JobSource)
+		}
+		if len(response.Uncharted) != 1 || response.Uncharted[0] != "checkout_cart_size" {
+			t.Errorf("expected the last metric to be uncharted, got %v", response.Uncharted)
+		}
+	})
+
+	t.Run("rejects an invalid scrape interval", func(t *testing.T) {
+		_, err := tool.GenerateScrapeConfigHandler(context.Background(), map[string]any{
+			"metrics_url":     "http://checkout:8080/metrics",
+			"scrape_interval": "often",
+		})
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestGroupPanelsInRows(t *testing.T) {
+	panels := []map[string]any{{"title": "Up"}, {"title": "Latency"}, {"title": "Queue"}, {"title": "Errors"}}
+	result := groupPanelsInRows(panels, []string{"", promql.GroupHTTPServer, "", promql.GroupHTTPServer})
+
+	var titles []string
+	for _, raw := range result {
+		panel := raw.(map[string]any)
+		titles = append(titles, panel["title"].(string))
+	}
+	if strings.Join(titles, ",") != "Up,Queue,HTTP server,Latency,Errors" {
+		t.Errorf("expected the ungrouped panels first and one row per group, got %v", titles)
+	}
+
+	row := result[2].(map[string]any)
+	if row["type"] != "row" || row["gridPos"].(map[string]any)["y"] != 8 {
+		t.Errorf("expected a row below the ungrouped panels, got %+v", row)
+	}
+	if pos := result[4].(map[string]any)["gridPos"].(map[string]any); pos["x"] != 12 || pos["y"] != 9 {
+		t.Errorf("expected the second grouped panel beside the first, got %+v", pos)
+	}
+}
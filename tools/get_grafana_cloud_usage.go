@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// GetGrafanaCloudUsageTool struct holds the tool with services
+type GetGrafanaCloudUsageTool struct {
+	logger       *zap.Logger
+	grafanacloud grafanacloud.GrafanaCloud
+	promql       promql.PromQL
+}
+
+// NewGetGrafanaCloudUsageTool creates a new get_grafana_cloud_usage tool
+func NewGetGrafanaCloudUsageTool(logger *zap.Logger, grafanacloud grafanacloud.GrafanaCloud, promql promql.PromQL) server.Tool {
+	tool := &GetGrafanaCloudUsageTool{
+		logger:       logger,
+		grafanacloud: grafanacloud,
+		promql:       promql,
+	}
+	return newValidatedTool(
+		"get_grafana_cloud_usage",
+		"Reports the Grafana Cloud org's current active series, log ingestion and active users against its plan, per stack where available, and warns when planned series or a recording rule set would push usage over the plan limits",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"planned_series": map[string]any{
+					"description": "Active series a planned change adds, such as new scrape targets or a bulk deployment",
+					"type":        "integer",
+					"minimum":     0,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL to estimate the output series of recording_rules on",
+					"type":        "string",
+				},
+				"recording_rules": map[string]any{
+					"description": "PromQL expressions of planned recording rules; each adds the series it returns (requires prometheus_url)",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+			},
+		},
+		tool.GetGrafanaCloudUsageHandler,
+	)
+}
+
+// RecordingRuleEstimate is the number of series a planned recording rule
+// would write
+type RecordingRuleEstimate struct {
+	Expr   string `json:"expr"`
+	Series int    `json:"series"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StackUsage is the active series of a stack's hosted metrics instance
+type StackUsage struct {
+	Slug         string `json:"slug"`
+	ActiveSeries int    `json:"active_series"`
+}
+
+// GetGrafanaCloudUsageResponse represents the org's usage and the projected
+// effect of the planned changes
+type GetGrafanaCloudUsageResponse struct {
+	*grafanacloud.Usage
+	Stacks         []StackUsage            `json:"stacks,omitempty"`
+	RecordingRules []RecordingRuleEstimate `json:"recording_rules,omitempty"`
+	// PlannedSeries is planned_series plus the recording rule estimates
+	PlannedSeries int      `json:"planned_series,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// GetGrafanaCloudUsageHandler handles the get_grafana_cloud_usage tool execution
+func (t *GetGrafanaCloudUsageTool) GetGrafanaCloudUsageHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "get_grafana_cloud_usage")
+	defer span.End()
+
+	plannedSeries, _ := toInt(args["planned_series"])
+	rules := schemaStrings(args["recording_rules"])
+	prometheusURL, _ := args["prometheus_url"].(string)
+	if len(rules) > 0 && prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required to estimate recording_rules")
+	}
+
+	usage, err := t.grafanacloud.Usage(ctx)
+	if err != nil {
+		return "", err
+	}
+	response := GetGrafanaCloudUsageResponse{Usage: usage}
+
+	stacks, err := t.grafanacloud.ListStacks(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, stack := range stacks {
+		if stack.ActiveSeries > 0 {
+			response.Stacks = append(response.Stacks, StackUsage{Slug: stack.Slug, ActiveSeries: stack.ActiveSeries})
+		}
+	}
+	sort.Slice(response.Stacks, func(i, j int) bool { return response.Stacks[i].ActiveSeries > response.Stacks[j].ActiveSeries })
+
+	response.PlannedSeries = plannedSeries
+	for _, expr := range rules {
+		estimate := t.estimateRecordingRule(ctx, prometheusURL, expr)
+		response.RecordingRules = append(response.RecordingRules, estimate)
+		response.PlannedSeries += estimate.Series
+		if estimate.Error != "" {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("could not estimate the series of %s: %s", expr, estimate.Error))
+		}
+	}
+	response.Warnings = append(response.Warnings, usage.Warnings(response.PlannedSeries)...)
+
+	t.logger.Info("got grafana cloud usage",
+		zap.String("org", usage.Org),
+		zap.Float64("active_series", usage.ActiveSeries.Current),
+		zap.Int("planned_series", response.PlannedSeries))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// estimateRecordingRule counts the series a recording rule would write by
+// evaluating count() of its expression now
+func (t *GetGrafanaCloudUsageTool) estimateRecordingRule(ctx context.Context, prometheusURL, expr string) RecordingRuleEstimate {
+	estimate := RecordingRuleEstimate{Expr: expr}
+	now := time.Now()
+	series, err := t.promql.QueryRange(ctx, prometheusURL, fmt.Sprintf("count(%s)", expr), now, now, time.Minute)
+	if err != nil {
+		estimate.Error = err.Error()
+		return estimate
+	}
+	if len(series) > 0 && len(series[0].Samples) > 0 {
+		estimate.Series = int(series[0].Samples[len(series[0].Samples)-1].Value)
+	}
+	return estimate
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestGetGrafanaCloudUsageHandler(t *testing.T) {
+	cloud := &mockGrafanaCloudService{
+		stacks: []grafanacloud.Stack{
+			{Slug: "acmestaging", ActiveSeries: 1500},
+			{Slug: "acmeprod", ActiveSeries: 7000},
+			{Slug: "acmedev"},
+		},
+		usage: &grafanacloud.Usage{
+			Org:          "acme",
+			ActiveSeries: grafanacloud.Quota{Current: 8500, Included: 10000},
+			LogsGB:       grafanacloud.Quota{Current: 12.5, Included: 50},
+		},
+	}
+
+	t.Run("reports usage per stack", func(t *testing.T) {
+		tool := &GetGrafanaCloudUsageTool{logger: zap.NewNop(), grafanacloud: cloud, promql: &promqlfakes.FakePromQL{}}
+		result, err := tool.GetGrafanaCloudUsageHandler(context.Background(), map[string]any{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response GetGrafanaCloudUsageResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if response.Usage == nil || response.ActiveSeries.Current != 8500 {
+			t.Errorf("Expected the org usage, got %+v", response.Usage)
+		}
+		if len(response.Stacks) != 2 || response.Stacks[0].Slug != "acmeprod" {
+			t.Errorf("Expected the stacks with series, largest first, got %+v", response.Stacks)
+		}
+		if len(response.Warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", response.Warnings)
+		}
+	})
+
+	t.Run("warns when recording rules exceed the plan", func(t *testing.T) {
+		fake := &promqlfakes.FakePromQL{}
+		fake.QueryRangeCalls(func(_ context.Context, _ string, query string, _, _ time.Time, _ time.Duration) ([]promql.Series, error) {
+			if strings.Contains(query, "pod") {
+				return nil, errors.New("query timed out")
+			}
+			return []promql.Series{{Samples: []promql.SamplePoint{{Value: 900}}}}, nil
+		})
+		tool := &GetGrafanaCloudUsageTool{logger: zap.NewNop(), grafanacloud: cloud, promql: fake}
+
+		result, err := tool.GetGrafanaCloudUsageHandler(context.Background(), map[string]any{
+			"prometheus_url":  "http://prometheus.test:9090",
+			"planned_series":  100.0,
+			"recording_rules": []any{"sum by (job, le) (rate(http_request_duration_seconds_bucket[5m]))", "sum by (pod) (rate(http_requests_total[5m]))"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response GetGrafanaCloudUsageResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+
+		if _, _, query, _, _, _ := fake.QueryRangeArgsForCall(0); !strings.HasPrefix(query, "count(sum by (job, le)") {
+			t.Errorf("Expected the rule's series to be counted, got %s", query)
+		}
+		if response.PlannedSeries != 1000 {
+			t.Errorf("Expected 1000 planned series, got %d", response.PlannedSeries)
+		}
+		if len(response.Warnings) != 2 || !strings.Contains(response.Warnings[0], "query timed out") ||
+			!strings.Contains(response.Warnings[1], "adding 1000 active series") {
+			t.Errorf("Unexpected warnings %v", response.Warnings)
+		}
+	})
+
+	t.Run("recording rules need a prometheus_url", func(t *testing.T) {
+		tool := &GetGrafanaCloudUsageTool{logger: zap.NewNop(), grafanacloud: cloud, promql: &promqlfakes.FakePromQL{}}
+		_, err := tool.GetGrafanaCloudUsageHandler(context.Background(), map[string]any{"recording_rules": []any{"up"}})
+		if err == nil || !strings.Contains(err.Error(), "prometheus_url is required") {
+			t.Errorf("Expected a prometheus_url error, got %v", err)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		tool := &GetGrafanaCloudUsageTool{logger: zap.NewNop(), grafanacloud: &mockGrafanaCloudService{err: grafanacloud.ErrNotConfigured}}
+		_, err := tool.GetGrafanaCloudUsageHandler(context.Background(), map[string]any{})
+		if !errors.Is(err, grafanacloud.ErrNotConfigured) {
+			t.Errorf("Expected ErrNotConfigured, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,533 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+)
+
+// maxImportSize caps the size of an imported dashboard document
+const maxImportSize = 10 << 20
+
+// importFetchTimeout bounds the download of a dashboard document
+const importFetchTimeout = 30 * time.Second
+
+// grafanaComDashboardPattern matches grafana.com dashboard pages such as
+// https://grafana.com/grafana/dashboards/1860-node-exporter-full/
+var grafanaComDashboardPattern = regexp.MustCompile(`^https?://grafana\.com/grafana/dashboards/(\d+)`)
+
+// datasourceInputPattern matches export placeholders such as ${DS_PROMETHEUS}
+var datasourceInputPattern = regexp.MustCompile(`\$\{(DS_[A-Za-z0-9_]+)\}`)
+
+// ImportDashboardTool struct holds the tool with services
+type ImportDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	cloudSvc      grafanacloud.GrafanaCloud
+	grafanaConfig *config.GrafanaConfig
+	client        *http.Client
+	deployOptions deploy.Options
+}
+
+// NewImportDashboardTool creates a new import_dashboard tool. Documents are
+// downloaded through transport; nil uses http.DefaultTransport.
+func NewImportDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, cloudSvc grafanacloud.GrafanaCloud, grafanaConfig *config.GrafanaConfig, transport http.RoundTripper, deployOptions deploy.Options) server.Tool {
+	tool := &ImportDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		cloudSvc:      cloudSvc,
+		grafanaConfig: grafanaConfig,
+		client:        &http.Client{Timeout: importFetchTimeout, Transport: transport},
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"import_dashboard",
+		"Imports a dashboard from raw JSON, a file or an HTTP(S) URL (e.g. a grafana.com dashboard), validates it and remaps its datasources before deploying it to Grafana",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"datasource_map": map[string]any{
+					"description": "Datasources to substitute, keyed by export input (\"DS_PROMETHEUS\") or the UID or name used in the dashboard, valued by the UID or name of a datasource in the target Grafana; unmapped export inputs use the default (or only) datasource of their type",
+					"type":        "object",
+				},
+				"folder": map[string]any{
+					"description": "Optional folder path such as \"Platform/Payments\"; missing folders are created (mutually exclusive with folder_uid)",
+					"type":        "string",
+				},
+				"folder_uid": map[string]any{
+					"description": "Optional folder UID where the dashboard should be imported",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the imported version",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"overwrite": map[string]any{
+					"description": "Whether to overwrite an existing dashboard with the same UID or title (default false)",
+					"type":        "boolean",
+				},
+				"source": map[string]any{
+					"description": "Dashboard to import: a raw JSON string, a file path under GRAFANA_IMPORT_DIR, or an HTTP(S) URL",
+					"type":        "string",
+				},
+				"stack": map[string]any{
+					"description": "Grafana Cloud stack to import into, by slug or name (e.g. \"prod\"); mutually exclusive with grafana_url",
+					"type":        "string",
+				},
+			},
+			"required": []string{"source"},
+		},
+		tool.ImportDashboardHandler,
+	)
+}
+
+// DatasourceMapping records how a datasource reference of an imported
+// dashboard was resolved
+type DatasourceMapping struct {
+	// From is the export input ("DS_PROMETHEUS") or the original UID or name
+	From string `json:"from"`
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Automatic is true when the datasource was picked by type rather than mapped
+	Automatic bool `json:"automatic,omitempty"`
+}
+
+// ImportDashboardResponse represents the outcome of an import
+type ImportDashboardResponse struct {
+	*deploy.Result
+	Source      string              `json:"source"`
+	Datasources []DatasourceMapping `json:"datasources"`
+	Warnings    []string            `json:"warnings,omitempty"`
+}
+
+// ImportDashboardHandler handles the import_dashboard tool execution
+func (t *ImportDashboardTool) ImportDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "import_dashboard")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	stack, _ := args["stack"].(string)
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, t.cloudSvc, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, stack)
+	if err != nil {
+		return "", err
+	}
+
+	source, _ := args["source"].(string)
+	data, kind, err := t.load(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	dashboard, inputs, warnings, err := parseImportedDashboard(data)
+	if err != nil {
+		return "", err
+	}
+
+	datasourceMap := map[string]string{}
+	if raw, ok := args["datasource_map"].(map[string]any); ok {
+		for from, to := range raw {
+			uidOrName, ok := to.(string)
+			if !ok || strings.TrimSpace(uidOrName) == "" {
+				return "", fmt.Errorf("datasource_map values must be datasource UIDs or names, got %v for %q", to, from)
+			}
+			datasourceMap[from] = uidOrName
+		}
+	}
+
+	mappings, err := t.remapDatasources(ctx, target, dashboard, inputs, datasourceMap)
+	if err != nil {
+		return "", err
+	}
+
+	folderUID, _ := args["folder_uid"].(string)
+	folderPath, _ := args["folder"].(string)
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = "Dashboard imported via grafana-agent from " + kind
+	}
+	overwrite, _ := args["overwrite"].(bool)
+
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:  dashboard,
+		Target:     target,
+		FolderUID:  folderUID,
+		FolderPath: folderPath,
+		Message:    message,
+		Overwrite:  overwrite,
+		Provenance: dashboardProvenance(ctx, dashboard),
+		// exports from older Grafana releases are converted to what the
+		// target renders rather than left for it to migrate on load
+		MigrateSchema: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jsonBytes, err := json.MarshalIndent(ImportDashboardResponse{
+		Result:      result,
+		Source:      kind,
+		Datasources: mappings,
+		Warnings:    warnings,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal import result: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// load reads the dashboard document and reports the kind of source it came
+// from: "json", "file" or "url"
+func (t *ImportDashboardTool) load(ctx context.Context, source string) ([]byte, string, error) {
+	source = strings.TrimSpace(source)
+	switch {
+	case source == "":
+		return nil, "", fmt.Errorf("source is required")
+	case strings.HasPrefix(source, "{"):
+		return []byte(source), "json", nil
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		data, err := t.fetch(ctx, source)
+		return data, "url", err
+	default:
+		data, err := t.readFile(source)
+		return data, "file", err
+	}
+}
+
+// fetch downloads a dashboard document. grafana.com dashboard pages are
+// resolved to the download of their latest revision.
+func (t *ImportDashboardTool) fetch(ctx context.Context, url string) ([]byte, error) {
+	if m := grafanaComDashboardPattern.FindStringSubmatch(url); m != nil {
+		url = fmt.Sprintf("https://grafana.com/api/dashboards/%s/revisions/latest/download", m[1])
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dashboard: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s returned status %d", url, resp.StatusCode)
+	}
+	return readLimited(resp.Body)
+}
+
+// readFile reads a dashboard file. File imports are confined to
+// GRAFANA_IMPORT_DIR so a prompt cannot read arbitrary files on the host.
+func (t *ImportDashboardTool) readFile(path string) ([]byte, error) {
+	if t.grafanaConfig == nil || t.grafanaConfig.ImportDir == "" {
+		return nil, fmt.Errorf("file imports are disabled - set GRAFANA_IMPORT_DIR to allow importing dashboards from files")
+	}
+
+	root, err := filepath.Abs(t.grafanaConfig.ImportDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRAFANA_IMPORT_DIR: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+	if rel, err := filepath.Rel(root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("file %s must be inside GRAFANA_IMPORT_DIR", path)
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("file %s not found", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dashboard file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return readLimited(f)
+}
+
+// readLimited reads at most maxImportSize bytes
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard: %w", err)
+	}
+	if len(data) > maxImportSize {
+		return nil, fmt.Errorf("dashboard must be at most %d bytes", maxImportSize)
+	}
+	return data, nil
+}
+
+// parseImportedDashboard decodes a dashboard document and validates its
+// shape. Both the bare model and the {"dashboard": ..., "meta": ...} API
+// envelope are accepted. The export metadata (__inputs, __requires,
+// __elements) is stripped and the datasource inputs are returned, and the
+// numeric id is dropped so the dashboard is created rather than matched
+// against an unrelated one in the target.
+func parseImportedDashboard(data []byte) (map[string]any, map[string]string, []string, error) {
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, nil, nil, fmt.Errorf("dashboard must be a JSON object: %w", err)
+	}
+
+	dashboard := document
+	if inner, ok := document["dashboard"].(map[string]any); ok {
+		dashboard = inner
+	}
+
+	var problems, warnings []string
+	if title, _ := dashboard["title"].(string); strings.TrimSpace(title) == "" {
+		problems = append(problems, "title must be a non-empty string")
+	}
+	if raw, ok := dashboard["panels"]; ok {
+		panels, isList := raw.([]any)
+		if !isList {
+			problems = append(problems, "panels must be an array")
+		}
+		for i, panelRaw := range panels {
+			panel, ok := panelRaw.(map[string]any)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("panels[%d] must be an object", i))
+				continue
+			}
+			if panelType, _ := panel["type"].(string); panelType == "" {
+				problems = append(problems, fmt.Sprintf("panels[%d].type must be a non-empty string", i))
+			}
+		}
+	} else if _, hasRows := dashboard["rows"]; !hasRows {
+		warnings = append(warnings, "dashboard has no panels")
+	}
+	if raw, ok := dashboard["templating"]; ok {
+		templating, isObject := raw.(map[string]any)
+		if _, isList := templating["list"].([]any); !isObject || (templating["list"] != nil && !isList) {
+			problems = append(problems, "templating.list must be an array")
+		}
+	}
+	if raw, ok := dashboard["schemaVersion"]; ok {
+		if _, isNumber := raw.(float64); !isNumber {
+			problems = append(problems, "schemaVersion must be a number")
+		}
+	}
+	if len(problems) > 0 {
+		return nil, nil, nil, fmt.Errorf("dashboard is invalid: %s", strings.Join(problems, "; "))
+	}
+
+	// grafana.com downloads keep the export metadata next to the model
+	inputs := datasourceInputs(document)
+	for name, pluginID := range datasourceInputs(dashboard) {
+		inputs[name] = pluginID
+	}
+	if elements, _ := dashboard["__elements"].(map[string]any); len(elements) > 0 {
+		warnings = append(warnings, "dashboard uses library panels, which must already exist in the target Grafana")
+	}
+	for _, key := range []string{"__inputs", "__requires", "__elements"} {
+		delete(dashboard, key)
+	}
+	dashboard["id"] = nil
+
+	return dashboard, inputs, warnings, nil
+}
+
+// remapDatasources resolves the export inputs (${DS_...}) of the dashboard
+// and the datasources named in datasourceMap to datasources of the target
+// Grafana and rewrites every reference. Datasources left pointing at a UID
+// the target does not have are reported as errors.
+func (t *ImportDashboardTool) remapDatasources(ctx context.Context, target *deploy.Target, dashboard map[string]any, inputs, datasourceMap map[string]string) ([]DatasourceMapping, error) {
+	available, err := t.grafanaSvc.ListDatasources(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	froms := make([]string, 0, len(inputs)+len(datasourceMap))
+	for from := range inputs {
+		froms = append(froms, from)
+	}
+	for from := range datasourceMap {
+		if _, ok := inputs[from]; !ok {
+			froms = append(froms, from)
+		}
+	}
+	sort.Strings(froms)
+
+	resolved := map[string]*grafana.Datasource{}
+	mappings := []DatasourceMapping{}
+	for _, from := range froms {
+		mapping := DatasourceMapping{From: from}
+		if uidOrName, ok := datasourceMap[from]; ok {
+			datasource, err := t.grafanaSvc.GetDatasource(ctx, uidOrName, target.GrafanaURL, target.APIKey)
+			if err != nil {
+				return nil, err
+			}
+			resolved[from] = datasource
+		} else {
+			datasource, err := pickDatasource(available, from, inputs[from])
+			if err != nil {
+				return nil, err
+			}
+			resolved[from] = datasource
+			mapping.Automatic = true
+		}
+		mapping.UID, mapping.Name, mapping.Type = resolved[from].UID, resolved[from].Name, resolved[from].Type
+		mappings = append(mappings, mapping)
+	}
+
+	for key, value := range dashboard {
+		dashboard[key] = rewriteDatasources(value, resolved)
+	}
+
+	if leftover := datasourceInputPattern.FindString(mustMarshal(dashboard)); leftover != "" {
+		return nil, fmt.Errorf("datasource input %s is not declared in __inputs - map it with datasource_map", leftover)
+	}
+	for _, uid := range dashboardDatasourceUIDs(dashboard) {
+		if !hasDatasource(available, uid) {
+			return nil, fmt.Errorf("datasource %q not found in %s - map it with datasource_map", uid, target.GrafanaURL)
+		}
+	}
+	return mappings, nil
+}
+
+// hasDatasource reports whether a datasource with the UID or name exists
+func hasDatasource(available []grafana.Datasource, uidOrName string) bool {
+	for _, datasource := range available {
+		if datasource.UID == uidOrName || datasource.Name == uidOrName {
+			return true
+		}
+	}
+	return false
+}
+
+// datasourceInputs returns the datasource inputs of an export, keyed by
+// name and valued by plugin type
+func datasourceInputs(dashboard map[string]any) map[string]string {
+	inputs := map[string]string{}
+	raw, _ := dashboard["__inputs"].([]any)
+	for _, inputRaw := range raw {
+		input, _ := inputRaw.(map[string]any)
+		if input["type"] != "datasource" {
+			continue
+		}
+		name, _ := input["name"].(string)
+		pluginID, _ := input["pluginId"].(string)
+		if name != "" {
+			inputs[name] = pluginID
+		}
+	}
+	return inputs
+}
+
+// pickDatasource chooses the datasource for an unmapped export input: the
+// default datasource of the input's type, or the only one of that type
+func pickDatasource(available []grafana.Datasource, input, pluginID string) (*grafana.Datasource, error) {
+	var candidates []grafana.Datasource
+	for _, datasource := range available {
+		if datasource.Type == pluginID {
+			if datasource.IsDefault {
+				return &datasource, nil
+			}
+			candidates = append(candidates, datasource)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no %s datasource found for input %s - map it with datasource_map", pluginID, input)
+	case 1:
+		return &candidates[0], nil
+	default:
+		names := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			names = append(names, candidate.Name)
+		}
+		return nil, fmt.Errorf("input %s is ambiguous between %s datasources %s - map it with datasource_map", input, pluginID, strings.Join(names, ", "))
+	}
+}
+
+// rewriteDatasources replaces datasource references throughout a dashboard
+// value. "datasource" fields naming a resolved input, UID or name, as an
+// object or a plain string, become {"type", "uid"} objects of the resolved
+// datasource; ${DS_...} placeholders inside other strings (e.g. variable
+// queries) are substituted with its UID.
+func rewriteDatasources(value any, resolved map[string]*grafana.Datasource) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if key == "datasource" {
+				if datasource := resolved[datasourceRef(item)]; datasource != nil {
+					v[key] = map[string]any{"type": datasource.Type, "uid": datasource.UID}
+					continue
+				}
+			}
+			v[key] = rewriteDatasources(item, resolved)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = rewriteDatasources(item, resolved)
+		}
+		return v
+	case string:
+		return datasourceInputPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			if datasource := resolved[datasourceRef(placeholder)]; datasource != nil {
+				return datasource.UID
+			}
+			return placeholder
+		})
+	}
+	return value
+}
+
+// datasourceRef returns the key a datasource reference is resolved by: the
+// input name of a ${DS_...} placeholder, or the UID or name it holds
+func datasourceRef(ref any) string {
+	var s string
+	switch v := ref.(type) {
+	case string:
+		s = v
+	case map[string]any:
+		s, _ = v["uid"].(string)
+	}
+	if m := datasourceInputPattern.FindStringSubmatch(s); m != nil && m[0] == s {
+		return m[1]
+	}
+	return s
+}
+
+// mustMarshal renders a decoded JSON value, which cannot fail to marshal
+func mustMarshal(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// exportedDashboard is a grafana.com style export with a datasource input
+const exportedDashboard = `{
+  "__inputs": [{"name": "DS_PROMETHEUS", "type": "datasource", "pluginId": "prometheus"}],
+  "__requires": [{"type": "datasource", "id": "prometheus"}],
+  "id": 12,
+  "uid": "node",
+  "title": "Node Exporter",
+  "panels": [
+    {"type": "timeseries", "datasource": {"type": "prometheus", "uid": "${DS_PROMETHEUS}"},
+     "targets": [{"expr": "node_load1", "datasource": {"type": "prometheus", "uid": "${DS_PROMETHEUS}"}}]}
+  ],
+  "templating": {"list": [
+    {"name": "job", "type": "query", "datasource": "${DS_PROMETHEUS}", "query": "label_values(node_uname_info, job)"}
+  ]}
+}`
+
+func newImportDashboardTool(mock *mockGrafanaService, importDir string) *ImportDashboardTool {
+	return &ImportDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			DeployEnabled: true,
+			URL:           "http://grafana.test",
+			APIKey:        "test-key",
+			ImportDir:     importDir,
+		},
+		client: &http.Client{},
+	}
+}
+
+func TestImportDashboardHandler(t *testing.T) {
+	datasources := []grafana.Datasource{
+		{UID: "prom-a", Name: "Prometheus A", Type: "prometheus", IsDefault: true},
+		{UID: "prom-b", Name: "Prometheus B", Type: "prometheus"},
+	}
+
+	run := func(t *testing.T, tool *ImportDashboardTool, args map[string]any) (ImportDashboardResponse, map[string]any) {
+		t.Helper()
+		var deployed map[string]any
+		tool.grafanaSvc.(*mockGrafanaService).createDashboardFunc = func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = dashboard.Dashboard
+			if dashboard.Overwrite {
+				t.Errorf("imports must not overwrite by default")
+			}
+			return &grafana.DashboardResponse{ID: 1, UID: "node", Version: 1}, nil
+		}
+
+		result, err := tool.ImportDashboardHandler(context.Background(), args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response ImportDashboardResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response, deployed
+	}
+
+	t.Run("raw JSON with an export input uses the default datasource", func(t *testing.T) {
+		tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources}, "")
+		response, deployed := run(t, tool, map[string]any{"source": exportedDashboard})
+
+		if response.Source != "json" || len(response.Datasources) != 1 || response.Datasources[0].UID != "prom-a" || !response.Datasources[0].Automatic {
+			t.Errorf("unexpected mappings %+v", response.Datasources)
+		}
+		if deployed["id"] != nil || deployed["__inputs"] != nil || deployed["__requires"] != nil {
+			t.Errorf("export metadata and id should be stripped, got %v", deployed)
+		}
+		if encoded := mustMarshal(deployed); strings.Contains(encoded, "DS_PROMETHEUS") || strings.Count(encoded, `"uid":"prom-a"`) != 3 {
+			t.Errorf("expected every reference to point at prom-a, got %s", encoded)
+		}
+	})
+
+	t.Run("datasource_map picks the datasource", func(t *testing.T) {
+		tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources}, "")
+		response, deployed := run(t, tool, map[string]any{
+			"source":         exportedDashboard,
+			"datasource_map": map[string]any{"DS_PROMETHEUS": "Prometheus B"},
+		})
+
+		if response.Datasources[0].UID != "prom-b" || response.Datasources[0].Automatic {
+			t.Errorf("unexpected mappings %+v", response.Datasources)
+		}
+		panel := deployed["panels"].([]any)[0].(map[string]any)
+		if panel["datasource"].(map[string]any)["uid"] != "prom-b" {
+			t.Errorf("expected the panel to use prom-b, got %v", panel["datasource"])
+		}
+	})
+
+	t.Run("file under the import directory", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "node.json"), []byte(`{"dashboard": {"title": "Node", "panels": [{"type": "text"}]}, "meta": {}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources}, dir)
+		response, deployed := run(t, tool, map[string]any{"source": "node.json"})
+		if response.Source != "file" || deployed["title"] != "Node" {
+			t.Errorf("expected the enveloped dashboard to be imported, got %v", deployed)
+		}
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(exportedDashboard))
+		}))
+		defer server.Close()
+
+		tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources}, "")
+		response, deployed := run(t, tool, map[string]any{"source": server.URL + "/node.json"})
+		if response.Source != "url" || deployed["title"] != "Node Exporter" {
+			t.Errorf("unexpected import %+v", response)
+		}
+	})
+
+	t.Run("legacy dashboard is migrated for the target Grafana", func(t *testing.T) {
+		tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources, grafanaVersion: "11.2.0"}, "")
+		response, deployed := run(t, tool, map[string]any{
+			"source": `{"title": "Legacy", "schemaVersion": 14, "rows": [{"height": "250px", "panels": [{"id": 1, "type": "graph", "span": 12, "title": "Requests"}]}]}`,
+		})
+
+		if len(response.Warnings) != 0 {
+			t.Errorf("expected no warnings for a migrated rows layout, got %v", response.Warnings)
+		}
+		if response.Schema == nil || response.Schema.SupportedVersion != 39 || response.Schema.DashboardVersion != 14 || len(response.Schema.Migrations) != 2 {
+			t.Fatalf("unexpected schema report %+v", response.Schema)
+		}
+		panels, _ := deployed["panels"].([]any)
+		if deployed["rows"] != nil || len(panels) != 1 || panels[0].(map[string]any)["type"] != "timeseries" {
+			t.Errorf("expected the row's graph panel migrated to a timeseries panel, got %v", deployed)
+		}
+	})
+}
+
+func TestImportDashboardHandler_Rejects(t *testing.T) {
+	datasources := []grafana.Datasource{
+		{UID: "prom-a", Name: "Prometheus A", Type: "prometheus"},
+		{UID: "prom-b", Name: "Prometheus B", Type: "prometheus"},
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		importDir string
+		wantErr   string
+	}{
+		{name: "invalid schema", source: `{"title": "", "panels": {}}`, wantErr: "title must be a non-empty string; panels must be an array"},
+		{name: "ambiguous datasource", source: exportedDashboard, wantErr: "ambiguous"},
+		{name: "unknown datasource", source: `{"title": "x", "panels": [{"type": "stat", "datasource": {"uid": "gone"}}]}`, wantErr: `datasource "gone" not found`},
+		{name: "files disabled", source: "node.json", wantErr: "GRAFANA_IMPORT_DIR"},
+		{name: "file outside the import directory", source: "../etc/passwd", importDir: "/srv/dashboards", wantErr: "must be inside GRAFANA_IMPORT_DIR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newImportDashboardTool(&mockGrafanaService{datasources: datasources}, tt.importDir)
+			_, err := tool.ImportDashboardHandler(context.Background(), map[string]any{"source": tt.source})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("deployments disabled", func(t *testing.T) {
+		tool := newImportDashboardTool(&mockGrafanaService{}, "")
+		tool.grafanaConfig.DeployEnabled = false
+		_, err := tool.ImportDashboardHandler(context.Background(), map[string]any{"source": exportedDashboard})
+		if err != deploy.ErrDeployDisabled {
+			t.Errorf("expected ErrDeployDisabled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// ListGrafanaOrgsTool struct holds the tool with services
+type ListGrafanaOrgsTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewListGrafanaOrgsTool creates a new list_grafana_orgs tool
+func NewListGrafanaOrgsTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &ListGrafanaOrgsTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"list_grafana_orgs",
+		"Lists the Grafana organizations the configured credentials can access, with the role in each, so other tools can be pointed at one with org_id",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+			},
+		},
+		tool.ListGrafanaOrgsHandler,
+	)
+}
+
+// ListGrafanaOrgsResponse represents the organizations of a Grafana instance
+type ListGrafanaOrgsResponse struct {
+	GrafanaURL string        `json:"grafana_url"`
+	Orgs       []grafana.Org `json:"orgs"`
+	// CurrentOrgID is the organization tools work in without org_id
+	CurrentOrgID int `json:"current_org_id,omitempty"`
+	Total        int `json:"total"`
+}
+
+// ListGrafanaOrgsHandler handles the list_grafana_orgs tool execution
+func (t *ListGrafanaOrgsTool) ListGrafanaOrgsHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "list_grafana_orgs")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := t.grafanaSvc.GetCurrentUser(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+	orgs, err := t.grafanaSvc.ListOrgs(ctx, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].OrgID < orgs[j].OrgID
+	})
+
+	t.logger.Debug("listed grafana organizations", zap.Int("count", len(orgs)))
+
+	jsonBytes, err := json.MarshalIndent(ListGrafanaOrgsResponse{
+		GrafanaURL:   target.GrafanaURL,
+		Orgs:         orgs,
+		CurrentOrgID: user.OrgID,
+		Total:        len(orgs),
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal organizations: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func TestListGrafanaOrgsHandler(t *testing.T) {
+	mock := &mockGrafanaService{orgs: []grafana.Org{
+		{OrgID: 3, Name: "Payments", Role: grafana.RoleEditor},
+		{OrgID: 1, Name: "Main Org.", Role: grafana.RoleAdmin},
+	}}
+	tool := &ListGrafanaOrgsTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	result, err := tool.ListGrafanaOrgsHandler(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response ListGrafanaOrgsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Total != 2 || response.Orgs[0].OrgID != 1 || response.Orgs[1].Name != "Payments" {
+		t.Errorf("Expected organizations sorted by ID, got %+v", response.Orgs)
+	}
+	if response.CurrentOrgID != 1 || response.GrafanaURL != "http://grafana.test" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	if _, err := (&ListGrafanaOrgsTool{logger: zap.NewNop(), grafanaSvc: mock}).ListGrafanaOrgsHandler(context.Background(), map[string]any{}); err == nil {
+		t.Error("Expected an error without a Grafana URL")
+	}
+}
+
+func TestOrgIDArgument(t *testing.T) {
+	var orgID int
+	tool := newValidatedTool("probe", "probe", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"org_id": map[string]any{"type": "integer", "minimum": 1},
+		},
+	}, func(ctx context.Context, args map[string]any) (string, error) {
+		orgID = grafana.OrgIDFromContext(ctx)
+		return "ok", nil
+	})
+
+	if _, err := tool.Execute(context.Background(), map[string]any{"org_id": float64(4)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if orgID != 4 {
+		t.Errorf("Expected requests to run in org 4, got %d", orgID)
+	}
+
+	if _, err := tool.Execute(context.Background(), map[string]any{"org_id": float64(0)}); err == nil {
+		t.Error("Expected org_id 0 to be rejected")
+	}
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+)
+
+// ListGrafanaStacksTool struct holds the tool with services
+type ListGrafanaStacksTool struct {
+	logger       *zap.Logger
+	grafanacloud grafanacloud.GrafanaCloud
+}
+
+// NewListGrafanaStacksTool creates a new list_grafana_stacks tool
+func NewListGrafanaStacksTool(logger *zap.Logger, grafanacloud grafanacloud.GrafanaCloud) server.Tool {
+	tool := &ListGrafanaStacksTool{
+		logger:       logger,
+		grafanacloud: grafanacloud,
+	}
+	return newValidatedTool(
+		"list_grafana_stacks",
+		"Lists the Grafana Cloud stacks of the configured org with their URLs and status",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"description": "Optional stack slug or name to resolve to a single stack (e.g. \"prod\")",
+					"type":        "string",
+				},
+			},
+		},
+		tool.ListGrafanaStacksHandler,
+	)
+}
+
+// ListGrafanaStacksResponse represents the stacks of the Grafana Cloud org
+type ListGrafanaStacksResponse struct {
+	Stacks []grafanacloud.Stack `json:"stacks"`
+	Total  int                  `json:"total"`
+}
+
+// ListGrafanaStacksHandler handles the list_grafana_stacks tool execution
+func (t *ListGrafanaStacksTool) ListGrafanaStacksHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "list_grafana_stacks")
+	defer span.End()
+
+	var stacks []grafanacloud.Stack
+	if name, _ := args["name"].(string); strings.TrimSpace(name) != "" {
+		stack, err := t.grafanacloud.ResolveStack(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		stacks = []grafanacloud.Stack{*stack}
+	} else {
+		var err error
+		stacks, err = t.grafanacloud.ListStacks(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sort.Slice(stacks, func(i, j int) bool {
+		return stacks[i].Slug < stacks[j].Slug
+	})
+
+	t.logger.Debug("listed grafana cloud stacks", zap.Int("count", len(stacks)))
+
+	jsonBytes, err := json.MarshalIndent(ListGrafanaStacksResponse{Stacks: stacks, Total: len(stacks)}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stacks: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
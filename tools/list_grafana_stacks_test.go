@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	grafanacloud "github.com/inference-gateway/grafana-agent/internal/grafanacloud"
+)
+
+// mockGrafanaCloudService is a mock implementation of grafanacloud.GrafanaCloud
+type mockGrafanaCloudService struct {
+	stacks []grafanacloud.Stack
+	usage  *grafanacloud.Usage
+	err    error
+}
+
+func (m *mockGrafanaCloudService) ListStacks(ctx context.Context) ([]grafanacloud.Stack, error) {
+	return m.stacks, m.err
+}
+
+func (m *mockGrafanaCloudService) ResolveStack(ctx context.Context, query string) (*grafanacloud.Stack, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for i := range m.stacks {
+		if m.stacks[i].Slug == query {
+			return &m.stacks[i], nil
+		}
+	}
+	return nil, grafanacloud.ErrStackNotFound
+}
+
+func (m *mockGrafanaCloudService) StackCredentials(ctx context.Context, query string) (*grafanacloud.StackCredentials, error) {
+	stack, err := m.ResolveStack(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &grafanacloud.StackCredentials{Stack: *stack, Token: "stack-token"}, nil
+}
+
+func (m *mockGrafanaCloudService) Usage(ctx context.Context) (*grafanacloud.Usage, error) {
+	return m.usage, m.err
+}
+
+func TestListGrafanaStacksHandler(t *testing.T) {
+	cloud := &mockGrafanaCloudService{stacks: []grafanacloud.Stack{
+		{Slug: "acmestaging", URL: "https://acmestaging.grafana.net"},
+		{Slug: "acmeprod", URL: "https://acmeprod.grafana.net"},
+	}}
+	tool := &ListGrafanaStacksTool{logger: zap.NewNop(), grafanacloud: cloud}
+
+	t.Run("lists all stacks sorted by slug", func(t *testing.T) {
+		result, err := tool.ListGrafanaStacksHandler(context.Background(), map[string]any{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var response ListGrafanaStacksResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.Total != 2 || response.Stacks[0].Slug != "acmeprod" {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	t.Run("resolves a single stack", func(t *testing.T) {
+		result, err := tool.ListGrafanaStacksHandler(context.Background(), map[string]any{"name": "acmestaging"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var response ListGrafanaStacksResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if response.Total != 1 || response.Stacks[0].URL != "https://acmestaging.grafana.net" {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+}
+
+func TestListGrafanaStacksNotConfigured(t *testing.T) {
+	tool := NewListGrafanaStacksTool(zap.NewNop(), &mockGrafanaCloudService{err: grafanacloud.ErrNotConfigured})
+
+	_, err := tool.Execute(context.Background(), map[string]any{})
+
+	var envelope *ErrorEnvelope
+	if !errors.As(err, &envelope) {
+		t.Fatalf("Expected ErrorEnvelope, got %v", err)
+	}
+	if envelope.Err.Code != ErrCodeMissingConfig {
+		t.Errorf("Expected missing_config, got %s", envelope.Err.Code)
+	}
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+)
+
+// ErrInventoryDisabled is returned by the inventory tools without a backend
+var ErrInventoryDisabled = errors.New("the dashboard inventory is disabled - set INVENTORY_BACKEND and INVENTORY_DSN to record the dashboards the agent deploys")
+
+// Drift statuses of a managed dashboard
+const (
+	managedInSync    = "in_sync"
+	managedDrifted   = "drifted"
+	managedMissing   = "missing"
+	managedUnchecked = "unchecked"
+)
+
+// ListManagedDashboardsTool struct holds the tool with services
+type ListManagedDashboardsTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	inventory     inventory.Store
+}
+
+// NewListManagedDashboardsTool creates a new list_managed_dashboards tool
+func NewListManagedDashboardsTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, store inventory.Store) server.Tool {
+	tool := &ListManagedDashboardsTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		inventory:     store,
+	}
+	return newValidatedTool(
+		"list_managed_dashboards",
+		"Lists the dashboards the agent has deployed, with the prompt and template each came from, and reports which were edited in Grafana since (drifted) or deleted there (missing)",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"check_drift": map[string]any{
+					"description": "Compare each dashboard of the Grafana instance with the deployed content (default true)",
+					"type":        "boolean",
+				},
+				"forget_missing": map[string]any{
+					"description": "Remove the dashboards deleted from Grafana from the inventory (default false)",
+					"type":        "boolean",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL whose dashboards to list (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Only list the dashboards deployed to this Grafana organization (default all organizations, see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"template": map[string]any{
+					"description": "Only list the dashboards generated from this template or builder, e.g. service_overview",
+					"type":        "string",
+				},
+			},
+		},
+		tool.ListManagedDashboardsHandler,
+	)
+}
+
+// ManagedDashboard is a recorded dashboard with its state in Grafana
+type ManagedDashboard struct {
+	inventory.Dashboard
+	// Status is in_sync, drifted or missing once compared with Grafana,
+	// unchecked otherwise
+	Status string `json:"status"`
+	// DeployedVersion is the version in Grafana of a drifted dashboard
+	DeployedVersion int    `json:"deployed_version,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// ListManagedDashboardsResponse lists the managed dashboards of a Grafana instance
+type ListManagedDashboardsResponse struct {
+	GrafanaURL string             `json:"grafana_url"`
+	Dashboards []ManagedDashboard `json:"dashboards"`
+	Drifted    int                `json:"drifted"`
+	Missing    int                `json:"missing"`
+	// Forgotten lists the UIDs of the missing dashboards removed from the
+	// inventory
+	Forgotten []string `json:"forgotten,omitempty"`
+	Total     int      `json:"total"`
+}
+
+// ListManagedDashboardsHandler handles the list_managed_dashboards tool execution
+func (t *ListManagedDashboardsTool) ListManagedDashboardsHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "list_managed_dashboards")
+	defer span.End()
+
+	if t.inventory == nil {
+		return "", ErrInventoryDisabled
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+	checkDrift := true
+	if check, ok := args["check_drift"].(bool); ok {
+		checkDrift = check
+	}
+	forgetMissing, _ := args["forget_missing"].(bool)
+	template, _ := args["template"].(string)
+
+	// Without org_id the dashboards of every organization are listed
+	records, err := t.inventory.List(ctx, inventory.Filter{
+		GrafanaURL: target.GrafanaURL,
+		OrgID:      grafana.OrgIDFromContext(ctx),
+		Template:   template,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	response := ListManagedDashboardsResponse{
+		GrafanaURL: target.GrafanaURL,
+		Dashboards: make([]ManagedDashboard, 0, len(records)),
+	}
+	for _, record := range records {
+		managed := ManagedDashboard{Dashboard: record, Status: managedUnchecked}
+		if checkDrift {
+			t.checkDrift(ctx, target, &managed)
+		}
+		switch managed.Status {
+		case managedDrifted:
+			response.Drifted++
+		case managedMissing:
+			response.Missing++
+			if forgetMissing {
+				if err := t.inventory.Forget(ctx, record.GrafanaURL, record.OrgID, record.UID); err != nil {
+					return "", err
+				}
+				response.Forgotten = append(response.Forgotten, record.UID)
+			}
+		}
+		response.Dashboards = append(response.Dashboards, managed)
+	}
+	response.Total = len(response.Dashboards)
+
+	t.logger.Debug("listed managed dashboards",
+		zap.Int("count", response.Total),
+		zap.Int("drifted", response.Drifted),
+		zap.Int("missing", response.Missing))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal managed dashboards: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// checkDrift compares a managed dashboard with its current content in the
+// Grafana organization it was deployed to. A dashboard that cannot be
+// fetched stays unchecked.
+func (t *ListManagedDashboardsTool) checkDrift(ctx context.Context, target *deploy.Target, managed *ManagedDashboard) {
+	if managed.OrgID > 0 {
+		ctx = grafana.WithOrgID(ctx, managed.OrgID)
+	}
+	current, err := t.grafanaSvc.GetDashboard(ctx, managed.UID, target.GrafanaURL, target.APIKey)
+	if errors.Is(err, grafana.ErrDashboardNotFound) {
+		managed.Status = managedMissing
+		return
+	}
+	if err != nil {
+		managed.Error = err.Error()
+		return
+	}
+	hash, err := inventory.Hash(current.Dashboard)
+	if err != nil {
+		managed.Error = err.Error()
+		return
+	}
+
+	managed.Status = managedInSync
+	if hash != managed.Hash {
+		managed.Status = managedDrifted
+		managed.DeployedVersion, _ = toInt(current.Dashboard["version"])
+	}
+}
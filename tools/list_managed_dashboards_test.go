@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	inventory "github.com/inference-gateway/grafana-agent/internal/inventory"
+	inventoryfakes "github.com/inference-gateway/grafana-agent/internal/inventory/inventoryfakes"
+)
+
+func TestListManagedDashboardsHandler(t *testing.T) {
+	deployed := map[string]any{"id": 12, "uid": "same", "version": 2, "title": "Checkout", "panels": []any{}}
+	hash, err := inventory.Hash(deployed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store := &inventoryfakes.FakeStore{}
+	store.ListReturns([]inventory.Dashboard{
+		{UID: "same", Title: "Checkout", GrafanaURL: "http://grafana.test", OrgID: 2, Template: "service_overview", Hash: hash, Version: 3},
+		{UID: "edited", Title: "Checkout", GrafanaURL: "http://grafana.test", OrgID: 1, Hash: hash, Version: 1},
+		{UID: "gone", Title: "Checkout", GrafanaURL: "http://grafana.test", OrgID: 1, Hash: hash, Version: 1},
+	}, nil)
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			// Each dashboard is only found in the organization it was deployed to
+			switch orgID := grafana.OrgIDFromContext(ctx); {
+			case uid == "same" && orgID == 2:
+				return &grafana.Dashboard{Dashboard: map[string]any{"id": 12, "uid": "same", "version": 3, "title": "Checkout", "panels": []any{}}}, nil
+			case uid == "edited" && orgID == 1:
+				return &grafana.Dashboard{Dashboard: map[string]any{"uid": "edited", "version": 4, "title": "Checkout (edited)", "panels": []any{}}}, nil
+			}
+			return nil, grafana.ErrDashboardNotFound
+		},
+	}
+	tool := &ListManagedDashboardsTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+		inventory:     store,
+	}
+
+	result, err := tool.ListManagedDashboardsHandler(context.Background(), map[string]any{
+		"forget_missing": true,
+		"template":       "service_overview",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response ListManagedDashboardsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if _, filter := store.ListArgsForCall(0); filter.GrafanaURL != "http://grafana.test" || filter.OrgID != 0 || filter.Template != "service_overview" {
+		t.Errorf("Unexpected filter %+v", filter)
+	}
+	if response.Total != 3 || response.Drifted != 1 || response.Missing != 1 {
+		t.Fatalf("Unexpected counts %+v", response)
+	}
+	statuses := []string{managedInSync, managedDrifted, managedMissing}
+	for i, dashboard := range response.Dashboards {
+		if dashboard.Status != statuses[i] {
+			t.Errorf("Expected %s to be %s, got %s", dashboard.UID, statuses[i], dashboard.Status)
+		}
+	}
+	if response.Dashboards[1].DeployedVersion != 4 {
+		t.Errorf("Expected the drifted dashboard's version, got %d", response.Dashboards[1].DeployedVersion)
+	}
+	if store.ForgetCallCount() != 1 || len(response.Forgotten) != 1 || response.Forgotten[0] != "gone" {
+		t.Errorf("Expected only the missing dashboard forgotten, got %v", response.Forgotten)
+	}
+	if _, grafanaURL, orgID, uid := store.ForgetArgsForCall(0); grafanaURL != "http://grafana.test" || orgID != 1 || uid != "gone" {
+		t.Errorf("Unexpected forgotten dashboard %s/%d/%s", grafanaURL, orgID, uid)
+	}
+
+	result, err = tool.ListManagedDashboardsHandler(grafana.WithOrgID(context.Background(), 2), map[string]any{"check_drift": false})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response = ListManagedDashboardsResponse{}
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Dashboards[0].Status != managedUnchecked || response.Missing != 0 {
+		t.Errorf("Expected unchecked dashboards without check_drift, got %+v", response)
+	}
+	if _, filter := store.ListArgsForCall(1); filter.OrgID != 2 {
+		t.Errorf("Expected the listing filtered by org_id, got %+v", filter)
+	}
+
+	disabled := &ListManagedDashboardsTool{logger: zap.NewNop(), grafanaSvc: mock, grafanaConfig: tool.grafanaConfig}
+	if _, err := disabled.ListManagedDashboardsHandler(context.Background(), map[string]any{}); !errors.Is(err, ErrInventoryDisabled) {
+		t.Errorf("Expected ErrInventoryDisabled, got %v", err)
+	}
+}
+
+func TestListManagedDashboardsHandler_AfterDeploy(t *testing.T) {
+	// Grafana stores the dashboard with the ids, grid positions and schema
+	// version it assigns on save
+	var stored map[string]any
+	mock := &mockGrafanaService{
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			data, _ := json.Marshal(dashboard.Dashboard)
+			_ = json.Unmarshal(data, &stored)
+			stored["id"], stored["version"], stored["schemaVersion"] = 42, 1, 39
+			for i, panel := range stored["panels"].([]any) {
+				panel.(map[string]any)["id"] = i + 1
+				panel.(map[string]any)["gridPos"] = map[string]any{"h": 8, "w": 12, "x": 0, "y": i * 8}
+			}
+			return &grafana.DashboardResponse{ID: 42, UID: "checkout", Version: 1}, nil
+		},
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: stored}, nil
+		},
+	}
+	store := &inventoryfakes.FakeStore{}
+	grafanaConfig := &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"}
+	deployTool := &DeployDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deploy.Options{Inventory: store},
+	}
+	if _, err := deployTool.DeployDashboardHandler(context.Background(), map[string]any{
+		"dashboard_json": map[string]any{
+			"uid":    "checkout",
+			"title":  "Checkout",
+			"panels": []any{map[string]any{"type": "stat", "title": "Requests"}},
+		},
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.RecordCallCount() != 1 {
+		t.Fatalf("Expected the deployed dashboard recorded, got %d records", store.RecordCallCount())
+	}
+	_, record := store.RecordArgsForCall(0)
+	store.ListReturns([]inventory.Dashboard{record}, nil)
+
+	tool := &ListManagedDashboardsTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: grafanaConfig,
+		inventory:     store,
+	}
+	status := func() string {
+		t.Helper()
+		result, err := tool.ListManagedDashboardsHandler(context.Background(), map[string]any{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var response ListManagedDashboardsResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response.Dashboards[0].Status
+	}
+
+	if got := status(); got != managedInSync {
+		t.Errorf("Expected a freshly deployed dashboard in sync, got %s", got)
+	}
+	stored["version"], stored["title"] = 2, "Checkout (edited)"
+	if got := status(); got != managedDrifted {
+		t.Errorf("Expected an edited dashboard to drift, got %s", got)
+	}
+}
@@ -0,0 +1,351 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// Correlation target types
+const (
+	correlationTargetLogs   = "logs"
+	correlationTargetTraces = "traces"
+)
+
+// ManageCorrelationsTool struct holds the tool with services
+type ManageCorrelationsTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewManageCorrelationsTool creates a new manage_correlations tool
+func NewManageCorrelationsTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &ManageCorrelationsTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"manage_correlations",
+		"Lists, creates or deletes Grafana datasource correlations linking metrics to related logs or traces",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{
+					"description": "Operation to perform: list, create or delete",
+					"type":        "string",
+					"enum":        []string{"list", "create", "delete"},
+				},
+				"correlation_uid": map[string]any{
+					"description": "UID of the correlation to delete (delete only)",
+					"type":        "string",
+				},
+				"dashboard_uid": map[string]any{
+					"description": "Deployed dashboard whose panel datasources are used as correlation sources",
+					"type":        "string",
+				},
+				"field": map[string]any{
+					"description": "Result field the link is attached to and interpolated into the target query (default \"job\" for logs, \"traceID\" for traces)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"label": map[string]any{
+					"description": "Link label shown in panels (default \"Related logs\" or \"Related traces\")",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"source_datasource_uids": map[string]any{
+					"description": "Source datasource UIDs, in addition to those of dashboard_uid",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"target_datasource_uid": map[string]any{
+					"description": "UID of the Loki or Tempo datasource to pivot to (create only)",
+					"type":        "string",
+				},
+				"target_type": map[string]any{
+					"description": "Kind of target datasource: logs (Loki) or traces (Tempo), default logs",
+					"type":        "string",
+					"enum":        []string{correlationTargetLogs, correlationTargetTraces},
+				},
+			},
+			"required": []string{"action"},
+		},
+		tool.ManageCorrelationsHandler,
+	)
+}
+
+// ManageCorrelationsResponse represents the outcome of a correlations action
+type ManageCorrelationsResponse struct {
+	Action       string                `json:"action"`
+	GrafanaURL   string                `json:"grafana_url"`
+	SourceUIDs   []string              `json:"source_uids,omitempty"`
+	Correlations []grafana.Correlation `json:"correlations"`
+	// Skipped lists sources that already had an identical correlation
+	Skipped []string `json:"skipped,omitempty"`
+	Message string   `json:"message"`
+}
+
+// ManageCorrelationsHandler handles the manage_correlations tool execution
+func (t *ManageCorrelationsTool) ManageCorrelationsHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "manage_correlations")
+	defer span.End()
+
+	action, _ := args["action"].(string)
+	grafanaURL, _ := args["grafana_url"].(string)
+
+	var (
+		target *deploy.Target
+		err    error
+	)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	switch action {
+	case "list":
+		target, err = deployer.ResolveReadTarget(grafanaURL)
+	case "create", "delete":
+		// Correlations change how every panel of the source datasource behaves,
+		// so writes are gated like deployments
+		target, err = deployer.ResolveTarget(ctx, grafanaURL, "")
+	default:
+		return "", fmt.Errorf("action must be one of list, create or delete")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sourceUIDs, err := t.sourceUIDs(ctx, target, args)
+	if err != nil {
+		return "", err
+	}
+	if len(sourceUIDs) == 0 {
+		return "", fmt.Errorf("dashboard_uid or source_datasource_uids is required")
+	}
+
+	response := ManageCorrelationsResponse{
+		Action:       action,
+		GrafanaURL:   target.GrafanaURL,
+		SourceUIDs:   sourceUIDs,
+		Correlations: []grafana.Correlation{},
+	}
+
+	switch action {
+	case "list":
+		for _, sourceUID := range sourceUIDs {
+			correlations, err := t.grafanaSvc.ListCorrelations(ctx, sourceUID, target.GrafanaURL, target.APIKey)
+			if err != nil {
+				return "", err
+			}
+			response.Correlations = append(response.Correlations, correlations...)
+		}
+		response.Message = fmt.Sprintf("Found %d correlations for %d datasources", len(response.Correlations), len(sourceUIDs))
+	case "create":
+		if err := t.create(ctx, target, sourceUIDs, args, &response); err != nil {
+			return "", err
+		}
+	case "delete":
+		correlationUID, _ := args["correlation_uid"].(string)
+		if correlationUID == "" || len(sourceUIDs) != 1 {
+			return "", fmt.Errorf("delete requires correlation_uid and exactly one source datasource")
+		}
+		if err := t.grafanaSvc.DeleteCorrelation(ctx, sourceUIDs[0], correlationUID, target.GrafanaURL, target.APIKey); err != nil {
+			return "", err
+		}
+		response.Message = fmt.Sprintf("Deleted correlation %s", correlationUID)
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// create adds a correlation from every source to the target datasource,
+// skipping sources that already have one with the same target and label
+func (t *ManageCorrelationsTool) create(ctx context.Context, target *deploy.Target, sourceUIDs []string, args map[string]any, response *ManageCorrelationsResponse) error {
+	targetUID, _ := args["target_datasource_uid"].(string)
+	if targetUID == "" {
+		return fmt.Errorf("target_datasource_uid is required to create correlations")
+	}
+
+	targetType, _ := args["target_type"].(string)
+	field, _ := args["field"].(string)
+	label, _ := args["label"].(string)
+
+	targetType = targetTypeOrDefault(targetType)
+	correlationCfg, err := correlationConfig(targetType, field)
+	if err != nil {
+		return err
+	}
+	if label == "" {
+		label = "Related " + targetType
+	}
+
+	for _, sourceUID := range sourceUIDs {
+		if sourceUID == targetUID {
+			continue
+		}
+
+		existing, err := t.grafanaSvc.ListCorrelations(ctx, sourceUID, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return err
+		}
+		if hasCorrelation(existing, targetUID, label) {
+			response.Skipped = append(response.Skipped, sourceUID)
+			continue
+		}
+
+		created, err := t.grafanaSvc.CreateCorrelation(ctx, grafana.Correlation{
+			SourceUID:   sourceUID,
+			TargetUID:   targetUID,
+			Label:       label,
+			Description: fmt.Sprintf("Created by grafana-agent: pivot from %s to %s", correlationCfg.Field, targetType),
+			Type:        "query",
+			Config:      correlationCfg,
+		}, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return err
+		}
+		response.Correlations = append(response.Correlations, *created)
+	}
+
+	response.Message = fmt.Sprintf("Created %d correlations to %s, %d already existed", len(response.Correlations), targetUID, len(response.Skipped))
+	return nil
+}
+
+// targetTypeOrDefault defaults the target type to logs
+func targetTypeOrDefault(targetType string) string {
+	if targetType == "" {
+		return correlationTargetLogs
+	}
+	return targetType
+}
+
+// correlationConfig builds the target query for the kind of target datasource
+func correlationConfig(targetType, field string) (grafana.CorrelationConfig, error) {
+	switch targetTypeOrDefault(targetType) {
+	case correlationTargetLogs:
+		if field == "" {
+			field = "job"
+		}
+		return grafana.CorrelationConfig{
+			Type:   "query",
+			Field:  field,
+			Target: map[string]any{"expr": fmt.Sprintf("{%s=\"${%s}\"}", field, field)},
+		}, nil
+	case correlationTargetTraces:
+		if field == "" {
+			field = "traceID"
+		}
+		return grafana.CorrelationConfig{
+			Type:   "query",
+			Field:  field,
+			Target: map[string]any{"queryType": "traceql", "query": fmt.Sprintf("${%s}", field)},
+		}, nil
+	default:
+		return grafana.CorrelationConfig{}, fmt.Errorf("target_type must be %s or %s", correlationTargetLogs, correlationTargetTraces)
+	}
+}
+
+// hasCorrelation reports whether a correlation to targetUID with label exists
+func hasCorrelation(correlations []grafana.Correlation, targetUID, label string) bool {
+	for _, c := range correlations {
+		if c.TargetUID == targetUID && c.Label == label {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceUIDs merges the explicit source datasources with those used by the
+// panels of dashboard_uid
+func (t *ManageCorrelationsTool) sourceUIDs(ctx context.Context, target *deploy.Target, args map[string]any) ([]string, error) {
+	seen := map[string]bool{}
+	var uids []string
+	add := func(uid string) {
+		uid = strings.TrimSpace(uid)
+		if uid == "" || seen[uid] {
+			return
+		}
+		seen[uid] = true
+		uids = append(uids, uid)
+	}
+
+	if raw, ok := args["source_datasource_uids"].([]any); ok {
+		for _, v := range raw {
+			if uid, ok := v.(string); ok {
+				add(uid)
+			}
+		}
+	}
+
+	if dashboardUID, _ := args["dashboard_uid"].(string); dashboardUID != "" {
+		dashboard, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch dashboard: %w", err)
+		}
+		if dashboard != nil {
+			for _, uid := range dashboardDatasourceUIDs(dashboard.Dashboard) {
+				add(uid)
+			}
+		}
+	}
+
+	return uids, nil
+}
+
+// dashboardDatasourceUIDs returns the sorted concrete datasource UIDs used by
+// the panels and targets of a dashboard. Template variables and the built-in
+// mixed, dashboard and grafana datasources are skipped.
+func dashboardDatasourceUIDs(dashboard map[string]any) []string {
+	seen := map[string]bool{}
+	collect := func(ds any) {
+		var uid string
+		switch v := ds.(type) {
+		case map[string]any:
+			uid, _ = v["uid"].(string)
+		case string:
+			uid = v
+		}
+		if uid == "" || strings.HasPrefix(uid, "$") || strings.HasPrefix(uid, "-- ") || uid == "grafana" {
+			return
+		}
+		seen[uid] = true
+	}
+
+	for _, panel := range flattenPanels(dashboard) {
+		collect(panel["datasource"])
+		targets, _ := panel["targets"].([]any)
+		for _, raw := range targets {
+			if target, ok := raw.(map[string]any); ok {
+				collect(target["datasource"])
+			}
+		}
+	}
+
+	uids := make([]string, 0, len(seen))
+	for uid := range seen {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func correlationDashboard() *grafana.Dashboard {
+	return &grafana.Dashboard{Dashboard: map[string]any{
+		"panels": []any{
+			map[string]any{
+				"type":       "timeseries",
+				"datasource": map[string]any{"type": "prometheus", "uid": "prom"},
+				"targets": []any{
+					map[string]any{"datasource": map[string]any{"uid": "mimir"}, "expr": "up"},
+				},
+			},
+			map[string]any{
+				"type":       "row",
+				"datasource": "-- Mixed --",
+				"panels": []any{
+					map[string]any{"type": "stat", "datasource": map[string]any{"uid": "${datasource}"}},
+				},
+			},
+		},
+	}}
+}
+
+func newCorrelationsTool(mock *mockGrafanaService, deployEnabled bool) *ManageCorrelationsTool {
+	return &ManageCorrelationsTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			DeployEnabled: deployEnabled,
+			URL:           "http://grafana.test",
+			APIKey:        "test-key",
+		},
+	}
+}
+
+func TestDashboardDatasourceUIDs(t *testing.T) {
+	uids := dashboardDatasourceUIDs(correlationDashboard().Dashboard)
+	if !reflect.DeepEqual(uids, []string{"mimir", "prom"}) {
+		t.Errorf("expected concrete datasources only, got %v", uids)
+	}
+}
+
+func TestManageCorrelationsCreate(t *testing.T) {
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return correlationDashboard(), nil
+		},
+		correlations: []grafana.Correlation{{UID: "existing", SourceUID: "mimir", TargetUID: "loki", Label: "Related logs"}},
+	}
+	tool := newCorrelationsTool(mock, true)
+
+	result, err := tool.ManageCorrelationsHandler(context.Background(), map[string]any{
+		"action":                "create",
+		"dashboard_uid":         "checkout",
+		"target_datasource_uid": "loki",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response ManageCorrelationsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Correlations) != 1 || response.Correlations[0].SourceUID != "prom" {
+		t.Fatalf("expected one correlation from prom, got %+v", response.Correlations)
+	}
+	if !reflect.DeepEqual(response.Skipped, []string{"mimir"}) {
+		t.Errorf("existing correlation should be skipped, got %v", response.Skipped)
+	}
+
+	created := response.Correlations[0]
+	if created.Config.Field != "job" || created.Config.Target["expr"] != `{job="${job}"}` {
+		t.Errorf("unexpected logs correlation config %+v", created.Config)
+	}
+}
+
+func TestManageCorrelationsTraces(t *testing.T) {
+	correlationCfg, err := correlationConfig("traces", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if correlationCfg.Field != "traceID" || correlationCfg.Target["query"] != "${traceID}" {
+		t.Errorf("unexpected traces correlation config %+v", correlationCfg)
+	}
+
+	if _, err := correlationConfig("profiles", ""); err == nil {
+		t.Error("expected an error for an unknown target type")
+	}
+}
+
+func TestManageCorrelationsListAndDelete(t *testing.T) {
+	mock := &mockGrafanaService{
+		correlations: []grafana.Correlation{{UID: "c1", SourceUID: "prom", TargetUID: "loki", Label: "Related logs"}},
+	}
+
+	t.Run("list works with deployments disabled", func(t *testing.T) {
+		tool := newCorrelationsTool(mock, false)
+		result, err := tool.ManageCorrelationsHandler(context.Background(), map[string]any{
+			"action":                 "list",
+			"source_datasource_uids": []any{"prom"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var response ManageCorrelationsResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if len(response.Correlations) != 1 {
+			t.Errorf("expected one correlation, got %+v", response.Correlations)
+		}
+	})
+
+	t.Run("delete requires deployments", func(t *testing.T) {
+		tool := newCorrelationsTool(mock, false)
+		_, err := tool.ManageCorrelationsHandler(context.Background(), map[string]any{
+			"action":                 "delete",
+			"source_datasource_uids": []any{"prom"},
+			"correlation_uid":        "c1",
+		})
+		if !errors.Is(err, deploy.ErrDeployDisabled) {
+			t.Errorf("expected ErrDeployDisabled, got %v", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		tool := newCorrelationsTool(mock, true)
+		_, err := tool.ManageCorrelationsHandler(context.Background(), map[string]any{
+			"action":                 "delete",
+			"source_datasource_uids": []any{"prom"},
+			"correlation_uid":        "c1",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(mock.correlations) != 0 {
+			t.Errorf("expected the correlation to be deleted, got %+v", mock.correlations)
+		}
+	})
+}
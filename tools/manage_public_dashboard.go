@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// ManagePublicDashboardTool struct holds the tool with services
+type ManagePublicDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewManagePublicDashboardTool creates a new manage_public_dashboard tool
+func NewManagePublicDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &ManagePublicDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"manage_public_dashboard",
+		"Lists public dashboards, or publishes, enables or disables the public share of a dashboard (e.g. a status page)",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{
+					"description": "Operation to perform: list, create, enable or disable",
+					"type":        "string",
+					"enum":        []string{"list", "create", "enable", "disable"},
+				},
+				"annotations_enabled": map[string]any{
+					"description": "Show annotations on the public dashboard (create only, default false)",
+					"type":        "boolean",
+				},
+				"dashboard_uid": map[string]any{
+					"description": "UID of the dashboard to publish (required except for list)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"time_selection_enabled": map[string]any{
+					"description": "Let viewers change the time range (create only, default false)",
+					"type":        "boolean",
+				},
+			},
+			"required": []string{"action"},
+		},
+		tool.ManagePublicDashboardHandler,
+	)
+}
+
+// PublicDashboardInfo describes a public dashboard and where it is served
+type PublicDashboardInfo struct {
+	grafana.PublicDashboard
+	PublicURL string `json:"public_url,omitempty"`
+}
+
+// ManagePublicDashboardResponse represents the outcome of a public dashboard action
+type ManagePublicDashboardResponse struct {
+	Action           string                `json:"action"`
+	GrafanaURL       string                `json:"grafana_url"`
+	PublicDashboards []PublicDashboardInfo `json:"public_dashboards"`
+	Message          string                `json:"message"`
+}
+
+// ManagePublicDashboardHandler handles the manage_public_dashboard tool execution
+func (t *ManagePublicDashboardTool) ManagePublicDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "manage_public_dashboard")
+	defer span.End()
+
+	action, _ := args["action"].(string)
+	grafanaURL, _ := args["grafana_url"].(string)
+	dashboardUID, _ := args["dashboard_uid"].(string)
+
+	var (
+		target *deploy.Target
+		err    error
+	)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	switch action {
+	case "list":
+		target, err = deployer.ResolveReadTarget(grafanaURL)
+	case "create", "enable", "disable":
+		// Publishing exposes dashboard data without authentication, so it needs
+		// its own opt-in on top of GRAFANA_DEPLOY_ENABLED
+		if t.grafanaConfig == nil || !t.grafanaConfig.PublicDashboardsEnabled {
+			t.logger.Warn("public dashboard change attempted but GRAFANA_PUBLIC_DASHBOARDS_ENABLED=false")
+			return "", deploy.ErrPublicDashboardsDisabled
+		}
+		if strings.TrimSpace(dashboardUID) == "" {
+			return "", fmt.Errorf("dashboard_uid is required to %s a public dashboard", action)
+		}
+		target, err = deployer.ResolveTarget(ctx, grafanaURL, "")
+	default:
+		return "", fmt.Errorf("action must be one of list, create, enable or disable")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	response := ManagePublicDashboardResponse{
+		Action:           action,
+		GrafanaURL:       target.GrafanaURL,
+		PublicDashboards: []PublicDashboardInfo{},
+	}
+
+	switch action {
+	case "list":
+		publicDashboards, err := t.grafanaSvc.ListPublicDashboards(ctx, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return "", err
+		}
+		for _, publicDashboard := range publicDashboards {
+			response.PublicDashboards = append(response.PublicDashboards, publicDashboardInfo(target.GrafanaURL, publicDashboard))
+		}
+		response.Message = fmt.Sprintf("Found %d public dashboards", len(publicDashboards))
+	default:
+		publicDashboard, message, err := t.apply(ctx, target, action, dashboardUID, args)
+		if err != nil {
+			return "", err
+		}
+		response.PublicDashboards = append(response.PublicDashboards, publicDashboardInfo(target.GrafanaURL, *publicDashboard))
+		response.Message = message
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// apply creates, enables or disables the public share of a dashboard. Creating
+// a dashboard that is already shared enables the existing share instead.
+func (t *ManagePublicDashboardTool) apply(ctx context.Context, target *deploy.Target, action, dashboardUID string, args map[string]any) (*grafana.PublicDashboard, string, error) {
+	existing, err := t.grafanaSvc.GetPublicDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if existing == nil {
+		if action != "create" {
+			return nil, "", fmt.Errorf("public dashboard for %s not found - use action create first", dashboardUID)
+		}
+
+		timeSelection, _ := args["time_selection_enabled"].(bool)
+		annotations, _ := args["annotations_enabled"].(bool)
+		created, err := t.grafanaSvc.CreatePublicDashboard(ctx, dashboardUID, grafana.PublicDashboard{
+			IsEnabled:            true,
+			TimeSelectionEnabled: timeSelection,
+			AnnotationsEnabled:   annotations,
+			Share:                "public",
+		}, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			return nil, "", err
+		}
+		return created, fmt.Sprintf("Published dashboard %s", dashboardUID), nil
+	}
+
+	enabled := action != "disable"
+	if existing.IsEnabled == enabled {
+		return existing, fmt.Sprintf("Public dashboard for %s is already %s", dashboardUID, enabledState(enabled)), nil
+	}
+
+	existing.IsEnabled = enabled
+	updated, err := t.grafanaSvc.UpdatePublicDashboard(ctx, dashboardUID, *existing, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, fmt.Sprintf("Public dashboard for %s is now %s", dashboardUID, enabledState(enabled)), nil
+}
+
+// enabledState renders an enabled flag for messages
+func enabledState(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// publicDashboardInfo adds the public URL to a public dashboard
+func publicDashboardInfo(grafanaURL string, publicDashboard grafana.PublicDashboard) PublicDashboardInfo {
+	info := PublicDashboardInfo{PublicDashboard: publicDashboard}
+	if publicDashboard.AccessToken != "" {
+		info.PublicURL = fmt.Sprintf("%s/public-dashboards/%s", strings.TrimRight(grafanaURL, "/"), publicDashboard.AccessToken)
+	}
+	return info
+}
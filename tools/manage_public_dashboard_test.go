@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+)
+
+func newPublicDashboardTool(mock *mockGrafanaService, publicEnabled bool) *ManagePublicDashboardTool {
+	return &ManagePublicDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			DeployEnabled:           true,
+			PublicDashboardsEnabled: publicEnabled,
+			URL:                     "http://grafana.test",
+			APIKey:                  "test-key",
+		},
+	}
+}
+
+func runPublicDashboard(t *testing.T, tool *ManagePublicDashboardTool, args map[string]any) ManagePublicDashboardResponse {
+	t.Helper()
+
+	result, err := tool.ManagePublicDashboardHandler(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response ManagePublicDashboardResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response
+}
+
+func TestManagePublicDashboardRequiresOptIn(t *testing.T) {
+	tool := newPublicDashboardTool(&mockGrafanaService{}, false)
+
+	_, err := tool.ManagePublicDashboardHandler(context.Background(), map[string]any{
+		"action":        "create",
+		"dashboard_uid": "status",
+	})
+	if !errors.Is(err, deploy.ErrPublicDashboardsDisabled) {
+		t.Fatalf("expected ErrPublicDashboardsDisabled, got %v", err)
+	}
+	if toToolError(err).Code != ErrCodeDeployDisabled {
+		t.Errorf("expected deploy_disabled, got %s", toToolError(err).Code)
+	}
+
+	response := runPublicDashboard(t, tool, map[string]any{"action": "list"})
+	if len(response.PublicDashboards) != 0 {
+		t.Errorf("expected no public dashboards, got %+v", response.PublicDashboards)
+	}
+}
+
+func TestManagePublicDashboardLifecycle(t *testing.T) {
+	mock := &mockGrafanaService{}
+	tool := newPublicDashboardTool(mock, true)
+
+	_, err := tool.ManagePublicDashboardHandler(context.Background(), map[string]any{
+		"action":        "enable",
+		"dashboard_uid": "status",
+	})
+	if err == nil || toToolError(err).Code != ErrCodeNotFound {
+		t.Fatalf("expected not_found before creation, got %v", err)
+	}
+
+	response := runPublicDashboard(t, tool, map[string]any{
+		"action":                 "create",
+		"dashboard_uid":          "status",
+		"time_selection_enabled": true,
+	})
+	created := response.PublicDashboards[0]
+	if !created.IsEnabled || !created.TimeSelectionEnabled || created.Share != "public" {
+		t.Errorf("unexpected public dashboard %+v", created)
+	}
+	if created.PublicURL != "http://grafana.test/public-dashboards/token-status" {
+		t.Errorf("unexpected public URL %q", created.PublicURL)
+	}
+
+	response = runPublicDashboard(t, tool, map[string]any{"action": "disable", "dashboard_uid": "status"})
+	if response.PublicDashboards[0].IsEnabled || mock.publicDashboards["status"].IsEnabled {
+		t.Errorf("expected the public dashboard to be disabled")
+	}
+
+	// Creating again re-enables the existing share rather than duplicating it
+	response = runPublicDashboard(t, tool, map[string]any{"action": "create", "dashboard_uid": "status"})
+	if !response.PublicDashboards[0].IsEnabled || response.PublicDashboards[0].UID != "public-status" {
+		t.Errorf("expected the existing public dashboard to be enabled, got %+v", response.PublicDashboards[0])
+	}
+
+	response = runPublicDashboard(t, tool, map[string]any{"action": "list"})
+	if len(response.PublicDashboards) != 1 {
+		t.Errorf("expected one public dashboard, got %+v", response.PublicDashboards)
+	}
+}
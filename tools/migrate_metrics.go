@@ -0,0 +1,252 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// metricNamePattern matches valid Prometheus metric names
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// MigrateMetricsTool struct holds the tool with services
+type MigrateMetricsTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	deployOptions deploy.Options
+}
+
+// NewMigrateMetricsTool creates a new migrate_metrics tool
+func NewMigrateMetricsTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &MigrateMetricsTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"migrate_metrics",
+		"Renames deprecated metrics in every dashboard that queries them, given an old to new metric mapping; previews the changes as diffs by default and deploys all affected dashboards when dry_run is false",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_query": map[string]any{
+					"description": "Optional dashboard title filter; only matching dashboards are migrated",
+					"type":        "string",
+				},
+				"dry_run": map[string]any{
+					"description": "Only report the changes each dashboard would get, without deploying (default true)",
+					"type":        "boolean",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the migrated dashboard versions",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"renames": map[string]any{
+					"description": "Metric renames keyed by the old metric name, valued by the new one, e.g. {\"http_requests_total\": \"http_server_requests_total\"}; the _bucket, _sum and _count series of a renamed metric are renamed too",
+					"type":        "object",
+				},
+			},
+			"required": []string{"renames"},
+		},
+		tool.MigrateMetricsHandler,
+	)
+}
+
+// MetricMigration is the migration of a single dashboard
+type MetricMigration struct {
+	DashboardUID   string `json:"dashboard_uid"`
+	DashboardTitle string `json:"dashboard_title"`
+	Folder         string `json:"folder,omitempty"`
+	// Selectors is the number of metric selectors renamed
+	Selectors int           `json:"selectors"`
+	Changes   []diff.Change `json:"changes"`
+	// Result is the deployment outcome, absent on dry runs
+	Result *deploy.Result `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// MigrateMetricsResponse represents the outcome of a metric migration
+type MigrateMetricsResponse struct {
+	DryRun            bool              `json:"dry_run"`
+	Renames           map[string]string `json:"renames"`
+	DashboardsScanned int               `json:"dashboards_scanned"`
+	Dashboards        []MetricMigration `json:"dashboards"`
+	// Failed counts the dashboards whose deployment failed or conflicted
+	Failed int `json:"failed,omitempty"`
+	// Skipped lists dashboards that could not be read
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// MigrateMetricsHandler handles the migrate_metrics tool execution
+func (t *MigrateMetricsTool) MigrateMetricsHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "migrate_metrics")
+	defer span.End()
+
+	renames, err := parseMetricRenames(args["renames"])
+	if err != nil {
+		return "", err
+	}
+
+	dryRun := true
+	if value, ok := args["dry_run"].(bool); ok {
+		dryRun = value
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	var target *deploy.Target
+	if dryRun {
+		target, err = deployer.ResolveReadTarget(grafanaURL)
+	} else {
+		target, err = deployer.ResolveTarget(ctx, grafanaURL, "")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	dashboardQuery, _ := args["dashboard_query"].(string)
+	hits, err := t.grafanaSvc.SearchDashboards(ctx, dashboardQuery, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = "Renamed metrics via grafana-agent: " + describeRenames(renames)
+	}
+
+	expanded := expandMetricRenames(renames)
+	response := MigrateMetricsResponse{DryRun: dryRun, Renames: renames, Dashboards: []MetricMigration{}}
+	for _, hit := range hits {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		current, err := t.grafanaSvc.GetDashboard(ctx, hit.UID, target.GrafanaURL, target.APIKey)
+		if err != nil || current == nil {
+			t.logger.Warn("skipping dashboard", zap.String("uid", hit.UID), zap.Error(err))
+			response.Skipped = append(response.Skipped, hit.UID)
+			continue
+		}
+		response.DashboardsScanned++
+
+		dashboard := current.Dashboard
+		before := diff.Canonicalize(dashboard)
+		selectors := 0
+		rewriteDashboardQueries(dashboard, func(expr string) string {
+			expr, n := promql.RenameMetrics(expr, expanded)
+			selectors += n
+			return expr
+		})
+		if selectors == 0 {
+			continue
+		}
+
+		migration := MetricMigration{
+			DashboardUID:   hit.UID,
+			DashboardTitle: hit.Title,
+			Folder:         hit.FolderTitle,
+			Selectors:      selectors,
+			Changes:        diff.Dashboards(before, dashboard),
+		}
+		if !dryRun {
+			baseVersion, _ := toInt(dashboard["version"])
+			result, err := deployer.Deploy(ctx, deploy.Request{
+				Dashboard:   dashboard,
+				GrafanaURL:  target.GrafanaURL,
+				FolderUID:   current.FolderUID,
+				Message:     message,
+				Overwrite:   baseVersion == 0,
+				BaseVersion: baseVersion,
+				Provenance:  dashboardProvenance(ctx, dashboard),
+			})
+			switch {
+			case err != nil:
+				migration.Error = err.Error()
+				response.Failed++
+			case result.Conflict != nil:
+				response.Failed++
+			}
+			migration.Result = result
+		}
+		response.Dashboards = append(response.Dashboards, migration)
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// parseMetricRenames validates the renames argument
+func parseMetricRenames(raw any) (map[string]string, error) {
+	object, _ := raw.(map[string]any)
+	if len(object) == 0 {
+		return nil, fmt.Errorf("renames must map at least one old metric name to a new one")
+	}
+
+	renames := make(map[string]string, len(object))
+	for from, value := range object {
+		to, _ := value.(string)
+		switch {
+		case !metricNamePattern.MatchString(from):
+			return nil, fmt.Errorf("invalid metric name %q", from)
+		case !metricNamePattern.MatchString(to):
+			return nil, fmt.Errorf("invalid new metric name %v for %s", value, from)
+		case from == to:
+			return nil, fmt.Errorf("the new name of %s must differ from the old one", from)
+		}
+		renames[from] = to
+	}
+	return renames, nil
+}
+
+// expandMetricRenames adds the histogram and summary series of each renamed
+// metric, unless they are renamed explicitly
+func expandMetricRenames(renames map[string]string) map[string]string {
+	expanded := make(map[string]string, len(renames)*(len(seriesSuffixes)+1))
+	for from, to := range renames {
+		expanded[from] = to
+		for _, suffix := range seriesSuffixes {
+			if _, explicit := renames[from+suffix]; !explicit {
+				expanded[from+suffix] = to + suffix
+			}
+		}
+	}
+	return expanded
+}
+
+// describeRenames lists the renames in a stable order, e.g. "a -> b, c -> d"
+func describeRenames(renames map[string]string) string {
+	parts := make([]string, 0, len(renames))
+	for from, to := range renames {
+		parts = append(parts, from+" -> "+to)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func runMigrateMetrics(t *testing.T, args map[string]any) (MigrateMetricsResponse, map[string]grafana.Dashboard, error) {
+	t.Helper()
+
+	dashboards := map[string]map[string]any{
+		"checkout": panelDashboard(),
+		"latency": {
+			"uid": "latency", "title": "Latency", "version": 5.0,
+			"panels": []any{
+				map[string]any{"id": 1.0, "title": "p99", "targets": []any{
+					map[string]any{"refId": "A", "expr": "histogram_quantile(0.99, sum by (le) (rate(http_request_duration_seconds_bucket[5m])))"},
+				}},
+			},
+		},
+		"nodes": {"title": "Nodes", "panels": []any{
+			map[string]any{"id": 1.0, "targets": []any{map[string]any{"refId": "A", "expr": "node_load1"}}},
+		}},
+	}
+	deployed := map[string]grafana.Dashboard{}
+	mock := &mockGrafanaService{
+		dashboards: []grafana.DashboardHit{{UID: "checkout", Title: "Checkout"}, {UID: "latency", Title: "Latency"}, {UID: "nodes", Title: "Nodes"}},
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: dashboards[uid], FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed[dashboard.Dashboard["title"].(string)] = dashboard
+			return &grafana.DashboardResponse{ID: 1, UID: "uid", Version: 6}, nil
+		},
+	}
+	tool := &MigrateMetricsTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	result, err := tool.MigrateMetricsHandler(context.Background(), args)
+	if err != nil {
+		return MigrateMetricsResponse{}, deployed, err
+	}
+
+	var response MigrateMetricsResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed, nil
+}
+
+func TestMigrateMetricsHandler(t *testing.T) {
+	renames := map[string]any{
+		"http_requests_total":           "http_server_requests_total",
+		"http_request_duration_seconds": "http_server_request_duration_seconds",
+	}
+
+	t.Run("dry run previews diffs", func(t *testing.T) {
+		response, deployed, err := runMigrateMetrics(t, map[string]any{"renames": renames})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !response.DryRun || len(deployed) != 0 {
+			t.Errorf("a dry run must not deploy, deployed %v", deployed)
+		}
+		if response.DashboardsScanned != 3 || len(response.Dashboards) != 2 {
+			t.Fatalf("expected two affected dashboards, got %+v", response)
+		}
+
+		latency := response.Dashboards[1]
+		if latency.DashboardUID != "latency" || latency.Selectors != 1 || len(latency.Changes) != 1 {
+			t.Fatalf("unexpected migration %+v", latency)
+		}
+		if change := latency.Changes[0]; change.Path != "panels[0].targets[0].expr" || !strings.Contains(change.New.(string), "http_server_request_duration_seconds_bucket") {
+			t.Errorf("unexpected change %+v", change)
+		}
+	})
+
+	t.Run("apply deploys every affected dashboard", func(t *testing.T) {
+		response, deployed, err := runMigrateMetrics(t, map[string]any{"renames": renames, "dry_run": false})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(deployed) != 2 || response.Failed != 0 || response.Dashboards[0].Result == nil {
+			t.Fatalf("expected two deployments, got %+v", response)
+		}
+
+		checkout := deployed["Checkout"]
+		expr := checkout.Dashboard["panels"].([]any)[0].(map[string]any)["targets"].([]any)[0].(map[string]any)["expr"]
+		if expr != "rate(http_server_requests_total[5m])" || checkout.FolderUID != "platform" || checkout.Dashboard["version"] != 3 {
+			t.Errorf("unexpected deployment %+v", checkout)
+		}
+		if !strings.Contains(checkout.Message, "http_requests_total -> http_server_requests_total") {
+			t.Errorf("expected the renames in the version message, got %q", checkout.Message)
+		}
+	})
+
+	t.Run("invalid renames", func(t *testing.T) {
+		for _, tt := range []struct {
+			renames any
+			wantErr string
+		}{
+			{nil, "at least one"},
+			{map[string]any{"http-requests": "x"}, "invalid metric name"},
+			{map[string]any{"up": "up"}, "must differ"},
+		} {
+			_, _, err := runMigrateMetrics(t, map[string]any{"renames": tt.renames})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
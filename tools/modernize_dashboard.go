@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	schema "github.com/inference-gateway/grafana-agent/internal/schema"
+)
+
+// ModernizeDashboardTool struct holds the tool with services
+type ModernizeDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	deployOptions deploy.Options
+}
+
+// NewModernizeDashboardTool creates a new modernize_dashboard tool
+func NewModernizeDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &ModernizeDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"modernize_dashboard",
+		"Rewrites the deprecated angular panels of a deployed dashboard (graph, singlestat, old table) to timeseries, stat or gauge and table panels, mapping their options, ahead of an upgrade to Grafana 11",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard",
+					"type":        "string",
+				},
+				"dry_run": map[string]any{
+					"description": "Only report the conversions and the resulting changes, without deploying (default false)",
+					"type":        "boolean",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the new dashboard version",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+			},
+			"required": []string{"dashboard_uid"},
+		},
+		tool.ModernizeDashboardHandler,
+	)
+}
+
+// ModernizeDashboardResponse represents the outcome of a dashboard
+// modernization
+type ModernizeDashboardResponse struct {
+	DryRun         bool   `json:"dry_run"`
+	DashboardUID   string `json:"dashboard_uid"`
+	DashboardTitle string `json:"dashboard_title"`
+	// GrafanaVersion is the version of the Grafana the panels were converted for
+	GrafanaVersion string `json:"grafana_version,omitempty"`
+	// Conversions lists the panels converted, e.g. "panels[2]: graph -> timeseries"
+	Conversions []string `json:"conversions"`
+	// Warnings lists the options that could not be mapped and the panels
+	// the target still cannot render
+	Warnings []string `json:"warnings,omitempty"`
+	// Changes are the resulting dashboard changes, reported on dry runs
+	Changes []diff.Change `json:"changes,omitempty"`
+	// Result is the deployment outcome, absent on dry runs and when nothing
+	// was converted
+	Result *deploy.Result `json:"result,omitempty"`
+}
+
+// ModernizeDashboardHandler handles the modernize_dashboard tool execution
+func (t *ModernizeDashboardTool) ModernizeDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "modernize_dashboard")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	dashboardUID, _ := args["dashboard_uid"].(string)
+	dryRun, _ := args["dry_run"].(bool)
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	var target *deploy.Target
+	var err error
+	if dryRun {
+		target, err = deployer.ResolveReadTarget(grafanaURL)
+	} else {
+		target, err = deployer.ResolveTarget(ctx, grafanaURL, "")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	current, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard %s: %w", dashboardUID, err)
+	}
+
+	// without a version the panels are converted as for a current Grafana
+	var grafanaVersion string
+	if health, err := t.grafanaSvc.GetHealth(ctx, target.GrafanaURL, target.APIKey); err != nil {
+		t.logger.Debug("could not detect the grafana version", zap.String("grafana_url", target.GrafanaURL), zap.Error(err))
+	} else {
+		grafanaVersion = health.Version
+	}
+
+	dashboard := current.Dashboard
+	before := diff.Canonicalize(dashboard)
+	report := schema.Migrate(dashboard, grafanaVersion, schema.ForGrafana(grafanaVersion))
+
+	response := ModernizeDashboardResponse{
+		DryRun:         dryRun,
+		DashboardUID:   dashboardUID,
+		DashboardTitle: getStringOrDefault(dashboard, "title", dashboardUID),
+		GrafanaVersion: grafanaVersion,
+		Conversions:    report.Migrations,
+		Warnings:       report.Warnings,
+	}
+	if response.Conversions == nil {
+		response.Conversions = []string{}
+	}
+
+	switch {
+	case len(report.Migrations) == 0:
+	case dryRun:
+		response.Changes = diff.Dashboards(before, dashboard)
+	default:
+		message, _ := args["message"].(string)
+		if message == "" {
+			message = fmt.Sprintf("Converted %d deprecated panels via grafana-agent", len(report.Migrations))
+		}
+		baseVersion, _ := toInt(dashboard["version"])
+		response.Result, err = deployer.Deploy(ctx, deploy.Request{
+			Dashboard:   dashboard,
+			GrafanaURL:  target.GrafanaURL,
+			FolderUID:   current.FolderUID,
+			Message:     message,
+			Overwrite:   baseVersion == 0,
+			BaseVersion: baseVersion,
+			Provenance:  dashboardProvenance(ctx, dashboard),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func runModernizeDashboard(t *testing.T, dashboard map[string]any, args map[string]any) (ModernizeDashboardResponse, *grafana.Dashboard) {
+	t.Helper()
+
+	var deployed *grafana.Dashboard
+	mock := &mockGrafanaService{
+		grafanaVersion: "11.1.0",
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: dashboard, FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = &dashboard
+			return &grafana.DashboardResponse{ID: 1, UID: "legacy", Version: 4}, nil
+		},
+	}
+	tool := &ModernizeDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	result, err := tool.ModernizeDashboardHandler(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var response ModernizeDashboardResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed
+}
+
+func legacyDashboard() map[string]any {
+	return map[string]any{
+		"uid": "legacy", "title": "Legacy", "version": 3.0, "schemaVersion": 27.0,
+		"panels": []any{
+			map[string]any{"id": 1.0, "type": "graph", "title": "Requests", "yaxes": []any{map[string]any{"format": "reqps"}}},
+			map[string]any{"id": 2.0, "type": "singlestat", "title": "Uptime", "format": "s", "valueName": "current"},
+			map[string]any{"id": 3.0, "type": "table-old", "title": "Pods", "styles": []any{}},
+			map[string]any{"id": 4.0, "type": "grafana-worldmap-panel", "title": "Regions"},
+			map[string]any{"id": 5.0, "type": "stat", "title": "Errors"},
+		},
+	}
+}
+
+func TestModernizeDashboardHandler(t *testing.T) {
+	t.Run("converts and deploys the deprecated panels", func(t *testing.T) {
+		response, deployed := runModernizeDashboard(t, legacyDashboard(), map[string]any{"dashboard_uid": "legacy"})
+
+		want := []string{"panels[0]: graph -> timeseries", "panels[1]: singlestat -> stat", "panels[2]: table-old -> table"}
+		if len(response.Conversions) != len(want) {
+			t.Fatalf("expected conversions %v, got %v", want, response.Conversions)
+		}
+		for i := range want {
+			if response.Conversions[i] != want[i] {
+				t.Errorf("expected conversion %q, got %q", want[i], response.Conversions[i])
+			}
+		}
+		if len(response.Warnings) != 1 || response.GrafanaVersion != "11.1.0" {
+			t.Errorf("expected the worldmap panel reported, got %+v", response)
+		}
+		if deployed == nil || response.Result == nil || response.Result.Dashboard.Version != 4 {
+			t.Fatalf("expected the dashboard deployed, got %+v", response.Result)
+		}
+		if deployed.FolderUID != "platform" || !strings.HasPrefix(deployed.Message, "Converted 3 deprecated panels via grafana-agent") {
+			t.Errorf("unexpected deployment %+v", deployed)
+		}
+		panels := deployed.Dashboard["panels"].([]any)
+		for i, panelType := range []string{"timeseries", "stat", "table", "grafana-worldmap-panel", "stat"} {
+			if got := panels[i].(map[string]any)["type"]; got != panelType {
+				t.Errorf("panel %d: expected %s, got %v", i, panelType, got)
+			}
+		}
+	})
+
+	t.Run("dry run reports the changes", func(t *testing.T) {
+		response, deployed := runModernizeDashboard(t, legacyDashboard(), map[string]any{"dashboard_uid": "legacy", "dry_run": true})
+
+		if deployed != nil || response.Result != nil {
+			t.Errorf("a dry run must not deploy, deployed %v", deployed)
+		}
+		if !response.DryRun || len(response.Conversions) != 3 || len(response.Changes) == 0 {
+			t.Errorf("expected the conversions and changes previewed, got %+v", response)
+		}
+	})
+
+	t.Run("modern dashboard is left alone", func(t *testing.T) {
+		dashboard := map[string]any{"uid": "modern", "title": "Modern", "panels": []any{map[string]any{"id": 1.0, "type": "timeseries"}}}
+		response, deployed := runModernizeDashboard(t, dashboard, map[string]any{"dashboard_uid": "modern"})
+
+		if deployed != nil || response.Result != nil || len(response.Conversions) != 0 {
+			t.Errorf("expected nothing converted or deployed, got %+v", response)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// maxPromptSummary caps the prompt excerpt recorded in version messages
+const maxPromptSummary = 120
+
+// dashboardProvenance describes where a dashboard save comes from: the
+// latest user message and ID of the A2A task the tool runs in, and the
+// metrics the dashboard queries
+func dashboardProvenance(ctx context.Context, dashboard map[string]any) *deploy.Provenance {
+	provenance := &deploy.Provenance{Metrics: dashboardMetrics(dashboard)}
+	if task, ok := ctx.Value(server.TaskContextKey).(*types.Task); ok && task != nil {
+		provenance.RequestID = task.ID
+		provenance.Prompt = summarizePrompt(latestUserText(task.History))
+	}
+	return provenance
+}
+
+// templateProvenance is the provenance of a dashboard generated from a
+// template or builder, which the inventory records
+func templateProvenance(ctx context.Context, dashboard map[string]any, template string) *deploy.Provenance {
+	provenance := dashboardProvenance(ctx, dashboard)
+	provenance.Template = template
+	return provenance
+}
+
+// latestUserText returns the text of the most recent user message
+func latestUserText(history []types.Message) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role != types.RoleUser {
+			continue
+		}
+		var parts []string
+		for _, part := range history[i].Parts {
+			if part.Text != nil {
+				parts = append(parts, *part.Text)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, " ")
+		}
+	}
+	return ""
+}
+
+// summarizePrompt collapses whitespace and truncates a prompt to
+// maxPromptSummary runes
+func summarizePrompt(prompt string) string {
+	summary := []rune(strings.Join(strings.Fields(prompt), " "))
+	if len(summary) <= maxPromptSummary {
+		return string(summary)
+	}
+	return strings.TrimSpace(string(summary[:maxPromptSummary-1])) + "…"
+}
+
+// dashboardMetrics returns the sorted metric names queried by the panels
+func dashboardMetrics(dashboard map[string]any) []string {
+	seen := map[string]bool{}
+	for _, panel := range flattenPanels(dashboard) {
+		targets, _ := panel["targets"].([]any)
+		for _, raw := range targets {
+			target, _ := raw.(map[string]any)
+			expr, _ := target["expr"].(string)
+			for _, name := range promql.MetricNames(expr) {
+				seen[name] = true
+			}
+		}
+	}
+
+	metrics := make([]string, 0, len(seen))
+	for name := range seen {
+		metrics = append(metrics, name)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+// RetagDashboardTool struct holds the tool with services
+type RetagDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	deployOptions deploy.Options
+}
+
+// NewRetagDashboardTool creates a new retag_dashboard tool
+func NewRetagDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &RetagDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"retag_dashboard",
+		"Adds, removes or replaces the tags of a deployed dashboard, normalizing them to the configured tag taxonomy and dropping or rejecting undeclared tags",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"add_tags": map[string]any{
+					"description": "Tags to add, such as team:payments",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the new dashboard version",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"remove_tags": map[string]any{
+					"description": "Tags to remove",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"tags": map[string]any{
+					"description": "Tags replacing all the dashboard's tags; add_tags and remove_tags apply on top",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+			},
+			"required": []string{"dashboard_uid"},
+		},
+		tool.RetagDashboardHandler,
+	)
+}
+
+// RetagDashboardResponse represents the outcome of a retag
+type RetagDashboardResponse struct {
+	*deploy.Result
+	Tags    []string `json:"tags"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Dropped lists the undeclared tags the tag taxonomy dropped
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+// RetagDashboardHandler handles the retag_dashboard tool execution
+func (t *RetagDashboardTool) RetagDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "retag_dashboard")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	dashboardUID, _ := args["dashboard_uid"].(string)
+	replace, replacing := args["tags"]
+	add := schemaStrings(args["add_tags"])
+	remove := schemaStrings(args["remove_tags"])
+	if !replacing && len(add) == 0 && len(remove) == 0 {
+		return "", fmt.Errorf("at least one of tags, add_tags or remove_tags is required")
+	}
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	current, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard %s: %w", dashboardUID, err)
+	}
+
+	previous := schemaStrings(current.Dashboard["tags"])
+	tags := slices.Clone(previous)
+	if replacing {
+		tags = schemaStrings(replace)
+	}
+	// Tags are compared normalized, so "Team=Payments" removes or
+	// duplicates "team:payments"; the first of duplicates is kept
+	merged := []string{}
+	for _, tag := range append(tags, add...) {
+		sameTag := func(other string) bool { return taxonomy.Normalize(other) == taxonomy.Normalize(tag) }
+		if !slices.ContainsFunc(remove, sameTag) && !slices.ContainsFunc(merged, sameTag) {
+			merged = append(merged, tag)
+		}
+	}
+	tags = merged
+
+	// Retagged dashboards follow the taxonomy, including the tags they had
+	tags, dropped, err := taxonomy.New(&t.grafanaConfig.Tags).Enforce(tags)
+	if err != nil {
+		return "", err
+	}
+	current.Dashboard["tags"] = tags
+
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = "Retagged via grafana-agent"
+	}
+
+	baseVersion, _ := toInt(current.Dashboard["version"])
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:   current.Dashboard,
+		GrafanaURL:  target.GrafanaURL,
+		FolderUID:   current.FolderUID,
+		Message:     message,
+		Overwrite:   baseVersion == 0,
+		BaseVersion: baseVersion,
+		Provenance:  dashboardProvenance(ctx, current.Dashboard),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	response := RetagDashboardResponse{Result: result, Tags: tags, Dropped: dropped}
+	for _, tag := range tags {
+		if !slices.Contains(previous, tag) {
+			response.Added = append(response.Added, tag)
+		}
+	}
+	for _, tag := range previous {
+		if !slices.Contains(tags, tag) {
+			response.Removed = append(response.Removed, tag)
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
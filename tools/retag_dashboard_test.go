@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	taxonomy "github.com/inference-gateway/grafana-agent/internal/taxonomy"
+)
+
+func runRetagDashboard(t *testing.T, tags config.GrafanaTagsConfig, args map[string]any) (RetagDashboardResponse, grafana.Dashboard, error) {
+	t.Helper()
+
+	var deployed grafana.Dashboard
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			dashboard := panelDashboard()
+			dashboard["tags"] = []any{"team:payments", "legacy"}
+			return &grafana.Dashboard{Dashboard: dashboard, FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = dashboard
+			return &grafana.DashboardResponse{ID: 1, UID: "checkout", Version: 4}, nil
+		},
+	}
+	tool := &RetagDashboardTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key", Tags: tags},
+	}
+
+	args["dashboard_uid"] = "checkout"
+	result, err := tool.RetagDashboardHandler(context.Background(), args)
+	if err != nil {
+		return RetagDashboardResponse{}, deployed, err
+	}
+
+	var response RetagDashboardResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed, nil
+}
+
+func TestRetagDashboardHandler(t *testing.T) {
+	taxonomyConfig := config.GrafanaTagsConfig{Taxonomy: []string{"env", "team", "service", "tier"}, Mode: taxonomy.ModeCorrect}
+
+	t.Run("adds and removes tags without a taxonomy", func(t *testing.T) {
+		response, deployed, err := runRetagDashboard(t, config.GrafanaTagsConfig{}, map[string]any{
+			"add_tags":    []any{"Checkout"},
+			"remove_tags": []any{"Legacy"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := []string{"team:payments", "Checkout"}; !slices.Equal(response.Tags, want) || !slices.Equal(schemaStrings(deployed.Dashboard["tags"]), want) {
+			t.Errorf("Expected tags %v, got %v", want, response.Tags)
+		}
+		if !slices.Equal(response.Added, []string{"Checkout"}) || !slices.Equal(response.Removed, []string{"legacy"}) {
+			t.Errorf("Unexpected changes %+v", response)
+		}
+		if deployed.FolderUID != "platform" || !strings.HasPrefix(deployed.Message, "Retagged via grafana-agent") {
+			t.Errorf("Unexpected deployment %+v", deployed)
+		}
+	})
+
+	t.Run("does not duplicate tags", func(t *testing.T) {
+		response, deployed, err := runRetagDashboard(t, config.GrafanaTagsConfig{}, map[string]any{
+			"add_tags": []any{"legacy", "Team=Payments", "checkout", "checkout"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := []string{"team:payments", "legacy", "checkout"}; !slices.Equal(schemaStrings(deployed.Dashboard["tags"]), want) {
+			t.Errorf("Expected tags %v, got %v", want, deployed.Dashboard["tags"])
+		}
+		if !slices.Equal(response.Added, []string{"checkout"}) {
+			t.Errorf("Expected only checkout added, got %v", response.Added)
+		}
+	})
+
+	t.Run("normalizes and drops undeclared tags", func(t *testing.T) {
+		response, _, err := runRetagDashboard(t, taxonomyConfig, map[string]any{
+			"add_tags": []any{"Env=Prod", "Tier: 1"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := []string{"team:payments", "env:prod", "tier:1"}; !slices.Equal(response.Tags, want) {
+			t.Errorf("Expected tags %v, got %v", want, response.Tags)
+		}
+		if !slices.Equal(response.Dropped, []string{"legacy"}) {
+			t.Errorf("Expected the legacy tag dropped, got %v", response.Dropped)
+		}
+	})
+
+	t.Run("replaces tags", func(t *testing.T) {
+		response, _, err := runRetagDashboard(t, taxonomyConfig, map[string]any{
+			"tags": []any{"service:checkout"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !slices.Equal(response.Tags, []string{"service:checkout"}) || len(response.Removed) != 2 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+
+	t.Run("rejects undeclared tags", func(t *testing.T) {
+		reject := taxonomyConfig
+		reject.Mode = taxonomy.ModeReject
+		_, deployed, err := runRetagDashboard(t, reject, map[string]any{
+			"add_tags":    []any{"owner:alice"},
+			"remove_tags": []any{"legacy"},
+		})
+		var tagErr *taxonomy.TagError
+		if !errors.As(err, &tagErr) || !slices.Equal(tagErr.Tags, []string{"owner:alice"}) {
+			t.Fatalf("Expected a TagError for owner:alice, got %v", err)
+		}
+		if deployed.Dashboard != nil {
+			t.Error("Expected nothing deployed")
+		}
+	})
+
+	t.Run("requires a change", func(t *testing.T) {
+		if _, _, err := runRetagDashboard(t, taxonomyConfig, map[string]any{}); err == nil {
+			t.Error("Expected an error without tags, add_tags or remove_tags")
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
+	session "github.com/inference-gateway/grafana-agent/internal/session"
+)
+
+// sessionTargetArguments select the Grafana instance and organization a
+// call works in
+var sessionTargetArguments = []string{"grafana_url", "stack", "org_id"}
+
+// sessionSourceArguments select where metrics are read from: a Prometheus
+// server or a single /metrics endpoint
+var sessionSourceArguments = []string{"prometheus_url", "metrics_url"}
+
+// sessionFolderArguments select the folder a dashboard is saved in, by path
+// or by UID
+var sessionFolderArguments = []string{"folder", "folder_uid"}
+
+// sessionArgumentGroups are recalled even where a tool does not require
+// them. The arguments of a group are alternatives, remembered and recalled
+// together, so a call naming a stack is not sent to a remembered
+// grafana_url as well, nor a call giving a folder_uid to a remembered
+// folder.
+var sessionArgumentGroups = [][]string{sessionTargetArguments, sessionSourceArguments, sessionFolderArguments}
+
+// sessionRequiredArguments are recalled only where a tool requires them,
+// since omitting them elsewhere means "all" or "none"
+var sessionRequiredArguments = []string{"dashboard_uid", "datasource", "job", "metric_names"}
+
+// sessionTool fills the arguments a call omits with the values the
+// conversation used before, and remembers the values of each successful
+// call, so "add a latency panel too" reaches the same Prometheus, Grafana
+// and dashboard as the request before it
+type sessionTool struct {
+	server.Tool
+	memory *session.Memory
+}
+
+// WithSessionMemory wraps a tool so it shares the conversation's arguments
+// with the other tools. A nil memory leaves the tool unchanged.
+func WithSessionMemory(tool server.Tool, memory *session.Memory) server.Tool {
+	if memory == nil {
+		return tool
+	}
+	return &sessionTool{Tool: tool, memory: memory}
+}
+
+// Execute runs the wrapped tool with the recalled arguments and remembers
+// the arguments and the deployed dashboard of a successful call
+func (t *sessionTool) Execute(ctx context.Context, args map[string]any) (string, error) {
+	contextID := conversationID(ctx)
+	if contextID == "" {
+		return t.Tool.Execute(ctx, args)
+	}
+
+	args = recallArguments(t.GetParameters(), args, t.memory.Recall(contextID))
+	result, err := t.Tool.Execute(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	t.memory.Remember(contextID, rememberedArguments(args, result))
+	return result, nil
+}
+
+// conversationID returns the A2A context the tool call belongs to
+func conversationID(ctx context.Context) string {
+	task, ok := ctx.Value(server.TaskContextKey).(*types.Task)
+	if !ok || task == nil {
+		return ""
+	}
+	return task.ContextID
+}
+
+// recallArguments returns the arguments with the omitted ones the tool
+// declares filled in from the remembered values
+func recallArguments(schema map[string]any, args, remembered map[string]any) map[string]any {
+	if len(remembered) == 0 {
+		return args
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	required := schemaStrings(schema["required"])
+	recalled := make(map[string]any, len(args))
+	for name, value := range args {
+		recalled[name] = value
+	}
+	recall := func(name string) {
+		if _, declared := properties[name]; !declared {
+			return
+		}
+		if _, given := args[name]; given {
+			return
+		}
+		if value, ok := remembered[name]; ok {
+			recalled[name] = value
+		}
+	}
+
+	for _, group := range sessionArgumentGroups {
+		groupGiven := slices.ContainsFunc(group, func(name string) bool {
+			_, given := args[name]
+			return given
+		})
+		if !groupGiven {
+			for _, name := range group {
+				recall(name)
+			}
+		}
+	}
+	for _, name := range sessionRequiredArguments {
+		if slices.Contains(required, name) {
+			recall(name)
+		}
+	}
+	return recalled
+}
+
+// rememberedArguments returns the values a successful call settles for the
+// conversation: its arguments, replacing a whole group when it names one
+// of its arguments, and the UID of the dashboard it deployed
+func rememberedArguments(args map[string]any, result string) map[string]any {
+	values := map[string]any{}
+	for _, group := range sessionArgumentGroups {
+		if slices.ContainsFunc(group, func(name string) bool { return args[name] != nil }) {
+			for _, name := range group {
+				values[name] = args[name]
+			}
+		}
+	}
+	for _, name := range sessionRequiredArguments {
+		if value, ok := args[name]; ok && value != nil {
+			values[name] = value
+		}
+	}
+
+	var response struct {
+		Dashboard struct {
+			UID string `json:"uid"`
+			URL string `json:"url"`
+		} `json:"dashboard"`
+	}
+	if json.Unmarshal([]byte(result), &response) == nil && response.Dashboard.UID != "" && response.Dashboard.URL != "" {
+		values["dashboard_uid"] = response.Dashboard.UID
+	}
+	return values
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	server "github.com/inference-gateway/adk/server"
+	types "github.com/inference-gateway/adk/types"
+
+	session "github.com/inference-gateway/grafana-agent/internal/session"
+)
+
+func TestWithSessionMemory(t *testing.T) {
+	memory := session.NewMemory(time.Hour, 10)
+	conversation := func(id string) context.Context {
+		return context.WithValue(context.Background(), server.TaskContextKey, &types.Task{ID: "task-" + id, ContextID: id})
+	}
+
+	createDashboard := WithSessionMemory(newValidatedTool("create_dashboard", "probe", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"folder":      map[string]any{"type": "string"},
+			"grafana_url": map[string]any{"type": "string"},
+			"stack":       map[string]any{"type": "string"},
+			"job":         map[string]any{"type": "string"},
+		},
+	}, func(ctx context.Context, args map[string]any) (string, error) {
+		return `{"status": "created", "dashboard": {"uid": "checkout", "url": "/d/checkout"}}`, nil
+	}), memory)
+
+	var received map[string]any
+	addPanel := WithSessionMemory(newValidatedTool("add_panel", "probe", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"dashboard_uid":  map[string]any{"type": "string"},
+			"grafana_url":    map[string]any{"type": "string"},
+			"stack":          map[string]any{"type": "string"},
+			"prometheus_url": map[string]any{"type": "string"},
+			"job":            map[string]any{"type": "string"},
+		},
+		"required": []string{"dashboard_uid"},
+	}, func(ctx context.Context, args map[string]any) (string, error) {
+		received = args
+		if args["dashboard_uid"] == "broken" {
+			return "", errors.New("dashboard not found")
+		}
+		return `{"panel": {"id": 2}}`, nil
+	}), memory)
+
+	if _, err := createDashboard.Execute(conversation("a"), map[string]any{"grafana_url": "https://grafana.test", "folder": "Payments", "job": "checkout"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("recalls the conversation's arguments", func(t *testing.T) {
+		if _, err := addPanel.Execute(conversation("a"), map[string]any{}); err != nil {
+			t.Fatalf("Expected the required dashboard_uid to be recalled, got %v", err)
+		}
+		if received["dashboard_uid"] != "checkout" || received["grafana_url"] != "https://grafana.test" {
+			t.Errorf("Expected the deployed dashboard and its Grafana, got %v", received)
+		}
+		if _, ok := received["job"]; ok {
+			t.Errorf("Expected the optional job not to be recalled, got %v", received)
+		}
+	})
+
+	t.Run("keeps the arguments given", func(t *testing.T) {
+		if _, err := addPanel.Execute(conversation("a"), map[string]any{"dashboard_uid": "other", "stack": "prod"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if received["dashboard_uid"] != "other" || received["stack"] != "prod" {
+			t.Errorf("Expected the given arguments, got %v", received)
+		}
+		if _, ok := received["grafana_url"]; ok {
+			t.Errorf("Expected no remembered grafana_url alongside the given stack, got %v", received)
+		}
+
+		if _, err := addPanel.Execute(conversation("a"), map[string]any{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if received["stack"] != "prod" || received["grafana_url"] != nil || received["dashboard_uid"] != "other" {
+			t.Errorf("Expected the stack to replace the remembered grafana_url, got %v", received)
+		}
+	})
+
+	t.Run("does not remember failed calls", func(t *testing.T) {
+		if _, err := addPanel.Execute(conversation("a"), map[string]any{"dashboard_uid": "broken"}); err == nil {
+			t.Fatal("Expected the tool error")
+		}
+		if _, err := addPanel.Execute(conversation("a"), map[string]any{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if received["dashboard_uid"] != "other" {
+			t.Errorf("Expected the last successful dashboard, got %v", received["dashboard_uid"])
+		}
+	})
+
+	t.Run("recalls alternative arguments as a group", func(t *testing.T) {
+		var discovered map[string]any
+		discoverMetrics := WithSessionMemory(newValidatedTool("discover_metrics", "probe", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"metrics_url":    map[string]any{"type": "string"},
+				"prometheus_url": map[string]any{"type": "string"},
+			},
+		}, func(ctx context.Context, args map[string]any) (string, error) {
+			discovered = args
+			return `{"metrics": []}`, nil
+		}), memory)
+
+		if _, err := discoverMetrics.Execute(conversation("c"), map[string]any{"prometheus_url": "http://prometheus.test"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := discoverMetrics.Execute(conversation("c"), map[string]any{"metrics_url": "http://checkout:8080/metrics"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := discovered["prometheus_url"]; ok {
+			t.Errorf("Expected no remembered prometheus_url alongside the given metrics_url, got %v", discovered)
+		}
+		if _, err := discoverMetrics.Execute(conversation("c"), map[string]any{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if discovered["metrics_url"] != "http://checkout:8080/metrics" || discovered["prometheus_url"] != nil {
+			t.Errorf("Expected the metrics_url to replace the remembered prometheus_url, got %v", discovered)
+		}
+
+		var deployed map[string]any
+		deployDashboard := WithSessionMemory(newValidatedTool("deploy_dashboard", "probe", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"folder":     map[string]any{"type": "string"},
+				"folder_uid": map[string]any{"type": "string"},
+			},
+		}, func(ctx context.Context, args map[string]any) (string, error) {
+			deployed = args
+			return `{"status": "deployed"}`, nil
+		}), memory)
+
+		if _, err := deployDashboard.Execute(conversation("a"), map[string]any{"folder_uid": "payments"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := deployed["folder"]; ok {
+			t.Errorf("Expected no remembered folder alongside the given folder_uid, got %v", deployed)
+		}
+		if _, err := deployDashboard.Execute(conversation("a"), map[string]any{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if deployed["folder_uid"] != "payments" || deployed["folder"] != nil {
+			t.Errorf("Expected the folder_uid to replace the remembered folder, got %v", deployed)
+		}
+	})
+
+	t.Run("keeps conversations apart", func(t *testing.T) {
+		_, err := addPanel.Execute(conversation("b"), map[string]any{})
+		var envelope *ErrorEnvelope
+		if !errors.As(err, &envelope) {
+			t.Fatalf("Expected the missing dashboard_uid to fail validation, got %v", err)
+		}
+		if _, err := addPanel.Execute(context.Background(), map[string]any{}); err == nil {
+			t.Error("Expected calls outside a conversation to recall nothing")
+		}
+	})
+}
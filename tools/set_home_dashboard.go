@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// SetHomeDashboardTool struct holds the tool with services
+type SetHomeDashboardTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewSetHomeDashboardTool creates a new set_home_dashboard tool
+func NewSetHomeDashboardTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &SetHomeDashboardTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"set_home_dashboard",
+		"Sets a dashboard as the home dashboard of the Grafana org or of a team",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard to use as home dashboard",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"team": map[string]any{
+					"description": "Optional team name or ID; the org home dashboard is set when omitted",
+					"type":        "string",
+				},
+			},
+			"required": []string{"dashboard_uid"},
+		},
+		tool.SetHomeDashboardHandler,
+	)
+}
+
+// SetHomeDashboardResponse represents the outcome of setting a home dashboard
+type SetHomeDashboardResponse struct {
+	Status       string        `json:"status"`
+	GrafanaURL   string        `json:"grafana_url"`
+	Scope        string        `json:"scope"`
+	Team         *grafana.Team `json:"team,omitempty"`
+	DashboardUID string        `json:"dashboard_uid"`
+	// PreviousDashboardUID allows reverting the change
+	PreviousDashboardUID string `json:"previous_dashboard_uid,omitempty"`
+	Message              string `json:"message"`
+}
+
+// SetHomeDashboardHandler handles the set_home_dashboard tool execution
+func (t *SetHomeDashboardTool) SetHomeDashboardHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "set_home_dashboard")
+	defer span.End()
+
+	dashboardUID, _ := args["dashboard_uid"].(string)
+	if strings.TrimSpace(dashboardUID) == "" {
+		return "", fmt.Errorf("dashboard_uid is required and must be a string")
+	}
+	grafanaURL, _ := args["grafana_url"].(string)
+	teamArg, _ := args["team"].(string)
+
+	// Home dashboards change what every member of the org or team sees first,
+	// so they are gated like deployments
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Fail early on typos instead of pointing the home dashboard at nothing
+	if _, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey); err != nil {
+		return "", err
+	}
+
+	response := SetHomeDashboardResponse{
+		Status:       "success",
+		GrafanaURL:   target.GrafanaURL,
+		Scope:        "org",
+		DashboardUID: dashboardUID,
+	}
+
+	var teamID int
+	if teamArg = strings.TrimSpace(teamArg); teamArg != "" {
+		team, err := t.resolveTeam(ctx, target, teamArg)
+		if err != nil {
+			return "", err
+		}
+		teamID = team.ID
+		response.Scope = "team"
+		response.Team = team
+	}
+
+	previous, err := t.grafanaSvc.GetPreferences(ctx, teamID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+	response.PreviousDashboardUID = previous.HomeDashboardUID
+
+	if previous.HomeDashboardUID == dashboardUID {
+		response.Message = fmt.Sprintf("Dashboard %s is already the %s home dashboard", dashboardUID, response.Scope)
+	} else {
+		if err := t.grafanaSvc.SetHomeDashboard(ctx, teamID, dashboardUID, target.GrafanaURL, target.APIKey); err != nil {
+			return "", err
+		}
+		response.Message = fmt.Sprintf("Dashboard %s is now the %s home dashboard", dashboardUID, response.Scope)
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// resolveTeam accepts a numeric team ID or a team name
+func (t *SetHomeDashboardTool) resolveTeam(ctx context.Context, target *deploy.Target, team string) (*grafana.Team, error) {
+	if id, err := strconv.Atoi(team); err == nil && id > 0 {
+		return &grafana.Team{ID: id}, nil
+	}
+	return t.grafanaSvc.FindTeam(ctx, team, target.GrafanaURL, target.APIKey)
+}
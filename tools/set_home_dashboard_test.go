@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func newHomeDashboardTool(mock *mockGrafanaService, deployEnabled bool) *SetHomeDashboardTool {
+	return &SetHomeDashboardTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			DeployEnabled: deployEnabled,
+			URL:           "http://grafana.test",
+			APIKey:        "test-key",
+		},
+	}
+}
+
+func TestSetHomeDashboardHandler(t *testing.T) {
+	mock := &mockGrafanaService{
+		teams:          []grafana.Team{{ID: 7, Name: "Payments"}},
+		homeDashboards: map[int]string{0: "old-home"},
+	}
+	tool := newHomeDashboardTool(mock, true)
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		expectedTeamID   int
+		expectedScope    string
+		expectedPrevious string
+	}{
+		{name: "org home dashboard", args: map[string]any{"dashboard_uid": "overview"}, expectedScope: "org", expectedPrevious: "old-home"},
+		{name: "team by name", args: map[string]any{"dashboard_uid": "overview", "team": "Payments"}, expectedTeamID: 7, expectedScope: "team"},
+		{name: "team by id", args: map[string]any{"dashboard_uid": "payments", "team": "7"}, expectedTeamID: 7, expectedScope: "team", expectedPrevious: "overview"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tool.SetHomeDashboardHandler(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var response SetHomeDashboardResponse
+			if err := json.Unmarshal([]byte(result), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Scope != tt.expectedScope || response.PreviousDashboardUID != tt.expectedPrevious {
+				t.Errorf("unexpected response %+v", response)
+			}
+			if mock.homeDashboards[tt.expectedTeamID] != tt.args["dashboard_uid"] {
+				t.Errorf("expected home dashboard %v for team %d, got %q", tt.args["dashboard_uid"], tt.expectedTeamID, mock.homeDashboards[tt.expectedTeamID])
+			}
+		})
+	}
+}
+
+func TestSetHomeDashboardErrors(t *testing.T) {
+	t.Run("deployments disabled", func(t *testing.T) {
+		tool := newHomeDashboardTool(&mockGrafanaService{}, false)
+		_, err := tool.SetHomeDashboardHandler(context.Background(), map[string]any{"dashboard_uid": "overview"})
+		if !errors.Is(err, deploy.ErrDeployDisabled) {
+			t.Errorf("expected ErrDeployDisabled, got %v", err)
+		}
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		mock := &mockGrafanaService{}
+		tool := newHomeDashboardTool(mock, true)
+		_, err := tool.SetHomeDashboardHandler(context.Background(), map[string]any{"dashboard_uid": "overview", "team": "SRE"})
+		if err == nil || toToolError(err).Code != ErrCodeNotFound {
+			t.Errorf("expected not_found, got %v", err)
+		}
+		if len(mock.homeDashboards) != 0 {
+			t.Errorf("no preferences should change, got %v", mock.homeDashboards)
+		}
+	})
+
+	t.Run("unknown dashboard", func(t *testing.T) {
+		mock := &mockGrafanaService{
+			getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+				return nil, errors.New("dashboard not found")
+			},
+		}
+		tool := newHomeDashboardTool(mock, true)
+		_, err := tool.SetHomeDashboardHandler(context.Background(), map[string]any{"dashboard_uid": "missing"})
+		if err == nil || toToolError(err).Code != ErrCodeNotFound {
+			t.Errorf("expected not_found, got %v", err)
+		}
+	})
+}
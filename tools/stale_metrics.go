@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// dashboardTimeRange resolves the time range a dashboard opens with: its own
+// time setting when it has a valid one, otherwise the configured default
+func dashboardTimeRange(dashboard map[string]any, cfg *config.GrafanaConfig, now time.Time) (string, string, time.Time, time.Time) {
+	if timeRange, ok := dashboard["time"].(map[string]any); ok {
+		from, _ := timeRange["from"].(string)
+		to, _ := timeRange["to"].(string)
+		if start, end, err := grafana.ParseTimeRange(from, to, now); err == nil {
+			return from, to, start, end
+		}
+	}
+
+	defaults := dashboardDefaults(cfg)
+	start, end, err := grafana.ParseTimeRange(defaults.TimeFrom, defaults.TimeTo, now)
+	if err != nil {
+		defaults = builtinDashboardDefaults
+		start, end, _ = grafana.ParseTimeRange(defaults.TimeFrom, defaults.TimeTo, now)
+	}
+	return defaults.TimeFrom, defaults.TimeTo, start, end
+}
+
+// staleMetrics returns the metrics that have no series with samples between
+// start and end, i.e. panels built on them would render empty. Histogram and
+// summary metrics count as present when any of their suffixed series are.
+// The check is best effort: when the series lookup fails nothing is flagged.
+func staleMetrics(ctx context.Context, logger *zap.Logger, promqlSvc promql.PromQL, prometheusURL string, metrics []string, start, end time.Time) []string {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	suffixes := strings.Join(seriesSuffixes, "|")
+	matchers := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		matchers = append(matchers, fmt.Sprintf(`{__name__=~"%s(%s)?"}`, regexp.QuoteMeta(metric), suffixes))
+	}
+
+	series, err := promqlSvc.Series(ctx, prometheusURL, matchers, start, end)
+	if err != nil {
+		logger.Warn("failed to check metrics for recent samples",
+			zap.Strings("metrics", metrics),
+			zap.Error(err))
+		return nil
+	}
+
+	present := make(map[string]bool, len(series))
+	for _, labels := range series {
+		present[labels["__name__"]] = true
+	}
+
+	var stale []string
+	for _, metric := range metrics {
+		found := present[metric]
+		for _, suffix := range seriesSuffixes {
+			found = found || present[metric+suffix]
+		}
+		if !found {
+			stale = append(stale, metric)
+		}
+	}
+	return stale
+}
+
+// staleMetricWarning explains why a panel on a stale metric would be empty
+func staleMetricWarning(metric, from, to string) string {
+	return fmt.Sprintf("metric %s has no samples between %s and %s; panels using it will be empty until it is scraped again", metric, from, to)
+}
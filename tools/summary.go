@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	i18n "github.com/inference-gateway/grafana-agent/internal/i18n"
+)
+
+// summarizeDashboard renders a compact, human-readable outline of a
+// dashboard model (panels with their queries, variables and layout) so
+// users reviewing in chat do not have to read the full JSON; its headings
+// are written in language
+func summarizeDashboard(dashboard map[string]any, language string) string {
+	var b strings.Builder
+	p := i18n.NewPrinter(language)
+
+	panels := flattenPanels(dashboard)
+	variables := dashboardVariables(dashboard)
+
+	b.WriteString(p.Sprintf("Dashboard %q: %d panels, %d variables", getStringOrDefault(dashboard, "title", p.T("Untitled")), len(panels), len(variables)))
+	if timeRange, ok := dashboard["time"].(map[string]string); ok {
+		b.WriteString(p.Sprintf(", time %v to %v", timeRange["from"], timeRange["to"]))
+	} else if timeRange, ok := dashboard["time"].(map[string]any); ok {
+		b.WriteString(p.Sprintf(", time %v to %v", timeRange["from"], timeRange["to"]))
+	}
+	if refresh := getStringOrDefault(dashboard, "refresh", ""); refresh != "" {
+		b.WriteString(p.Sprintf(", refresh %s", refresh))
+	}
+	b.WriteString("\n")
+
+	if tags := schemaStrings(dashboard["tags"]); len(tags) > 0 {
+		b.WriteString(p.Sprintf("Tags: %s", strings.Join(tags, ", ")) + "\n")
+	}
+
+	if len(panels) > 0 {
+		b.WriteString(p.Sprintf("Layout: %s", summarizeLayout(panels, p)) + "\n")
+		b.WriteString(p.T("Panels:") + "\n")
+		for i, panel := range panels {
+			fmt.Fprintf(&b, "  %d. %s [%s]", i+1, getStringOrDefault(panel, "title", p.T("Untitled")), getStringOrDefault(panel, "type", "timeseries"))
+			if x, y, w, h, ok := panelGridPos(panel); ok {
+				fmt.Fprintf(&b, " at (%d,%d) %dx%d", x, y, w, h)
+			}
+			b.WriteString("\n")
+
+			targets, _ := panel["targets"].([]any)
+			for _, targetRaw := range targets {
+				target, ok := targetRaw.(map[string]any)
+				if !ok {
+					continue
+				}
+				expr := strings.TrimSpace(getStringOrDefault(target, "expr", ""))
+				if expr == "" {
+					expr = p.T("(no query)")
+				}
+				fmt.Fprintf(&b, "     %s: %s\n", getStringOrDefault(target, "refId", "-"), expr)
+			}
+		}
+	}
+
+	if len(variables) > 0 {
+		b.WriteString(p.T("Variables:") + "\n")
+		for _, variable := range variables {
+			fmt.Fprintf(&b, "  - $%s (%s)", getStringOrDefault(variable, "name", "var"), getStringOrDefault(variable, "type", "query"))
+			if query, ok := variable["query"].(string); ok && query != "" {
+				fmt.Fprintf(&b, ": %s", query)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// summarizeLayout describes the grid occupied by the panels
+func summarizeLayout(panels []map[string]any, p i18n.Printer) string {
+	rows := map[int]int{}
+	bottom := 0
+	for _, panel := range panels {
+		_, y, _, h, ok := panelGridPos(panel)
+		if !ok {
+			continue
+		}
+		rows[y]++
+		if y+h > bottom {
+			bottom = y + h
+		}
+	}
+	if len(rows) == 0 {
+		return p.T("automatic")
+	}
+
+	ys := make([]int, 0, len(rows))
+	for y := range rows {
+		ys = append(ys, y)
+	}
+	sort.Ints(ys)
+
+	perRow := make([]string, len(ys))
+	for i, y := range ys {
+		perRow[i] = fmt.Sprintf("%d", rows[y])
+	}
+	return p.Sprintf("%d rows (%s panels per row), %d grid units tall", len(ys), strings.Join(perRow, "/"), bottom)
+}
+
+// panelGridPos reads a panel's grid position
+func panelGridPos(panel map[string]any) (x, y, w, h int, ok bool) {
+	gridPos, ok := panel["gridPos"].(map[string]any)
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	x, _ = toInt(gridPos["x"])
+	y, _ = toInt(gridPos["y"])
+	w, _ = toInt(gridPos["w"])
+	h, _ = toInt(gridPos["h"])
+	return x, y, w, h, true
+}
+
+// dashboardVariables returns the template variables of a dashboard model
+func dashboardVariables(dashboard map[string]any) []map[string]any {
+	templating, _ := dashboard["templating"].(map[string]any)
+	list, _ := templating["list"].([]any)
+
+	variables := make([]map[string]any, 0, len(list))
+	for _, raw := range list {
+		if variable, ok := raw.(map[string]any); ok {
+			variables = append(variables, variable)
+		}
+	}
+	return variables
+}
@@ -0,0 +1,386 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	diff "github.com/inference-gateway/grafana-agent/internal/diff"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	plan "github.com/inference-gateway/grafana-agent/internal/plan"
+)
+
+// planTTL is how long a plan can be applied after it was made
+const planTTL = time.Hour
+
+// SyncDashboardsTool struct holds the tool with services
+type SyncDashboardsTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	plans         *plan.Store
+	deployOptions deploy.Options
+}
+
+// NewSyncDashboardsTool creates a new sync_dashboards tool
+func NewSyncDashboardsTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &SyncDashboardsTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		plans:         plan.NewStore(planTTL),
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"sync_dashboards",
+		"Brings a set of dashboards in Grafana in line with the given dashboard JSON in two steps; plan computes the creates, updates and deletes against the current state and summarizes them like terraform plan; apply makes exactly the planned changes",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboards": map[string]any{
+					"description": "Desired dashboard JSON models, each with a uid (required for plan)",
+					"type":        "array",
+					"items":       map[string]any{"type": "object"},
+				},
+				"folder": map[string]any{
+					"description": "Optional folder path for the dashboards that are created, e.g. \"Platform/Payments\"; updated dashboards stay in their folder",
+					"type":        "string",
+				},
+				"folder_uid": map[string]any{
+					"description": "Optional folder UID for the dashboards that are created (mutually exclusive with folder)",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the dashboard versions saved on apply",
+					"type":        "string",
+				},
+				"mode": map[string]any{
+					"description": "plan (default) to compute and summarize the changes without making them, or apply to make the changes of plan_id",
+					"type":        "string",
+					"enum":        []string{"plan", "apply"},
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"plan_id": map[string]any{
+					"description": "ID of the plan to apply, as returned by mode plan (required for apply)",
+					"type":        "string",
+				},
+				"prune_tag": map[string]any{
+					"description": "Optional tag marking the dashboards this set manages; tagged dashboards missing from dashboards are deleted",
+					"type":        "string",
+				},
+			},
+		},
+		tool.SyncDashboardsHandler,
+	)
+}
+
+// SyncDashboardsPlanResponse represents a computed plan
+type SyncDashboardsPlanResponse struct {
+	PlanID     string        `json:"plan_id"`
+	GrafanaURL string        `json:"grafana_url"`
+	Summary    string        `json:"summary"`
+	Counts     plan.Summary  `json:"counts"`
+	Changes    []plan.Change `json:"changes"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+}
+
+// SyncDashboardsApplyResponse represents the outcome of applying a plan
+type SyncDashboardsApplyResponse struct {
+	PlanID     string          `json:"plan_id"`
+	GrafanaURL string          `json:"grafana_url"`
+	Summary    string          `json:"summary"`
+	Results    []AppliedChange `json:"results"`
+	Applied    int             `json:"applied"`
+	// Failed counts the changes that errored, conflicted or were stale
+	Failed int `json:"failed,omitempty"`
+}
+
+// AppliedChange is the outcome of a single planned change
+type AppliedChange struct {
+	Action string `json:"action"`
+	UID    string `json:"uid"`
+	Title  string `json:"title"`
+	// Status is applied, conflict, stale (changed since the plan) or failed
+	Status string         `json:"status"`
+	Result *deploy.Result `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// SyncDashboardsHandler handles the sync_dashboards tool execution
+func (t *SyncDashboardsTool) SyncDashboardsHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "sync_dashboards")
+	defer span.End()
+
+	var (
+		response any
+		err      error
+	)
+	switch mode := getStringOrDefault(args, "mode", "plan"); mode {
+	case "plan":
+		response, err = t.plan(ctx, args)
+	case "apply":
+		response, err = t.apply(ctx, args)
+	default:
+		return "", fmt.Errorf("mode must be one of plan or apply")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// plan computes the changes that bring Grafana in line with the dashboards
+func (t *SyncDashboardsTool) plan(ctx context.Context, args map[string]any) (*SyncDashboardsPlanResponse, error) {
+	items, _ := args["dashboards"].([]any)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("dashboards is required for plan and must list at least one dashboard")
+	}
+	folderUID, _ := args["folder_uid"].(string)
+	folderPath, _ := args["folder"].(string)
+	if strings.TrimSpace(folderUID) != "" && strings.TrimSpace(folderPath) != "" {
+		return nil, deploy.ErrFolderConflict
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &plan.Plan{GrafanaURL: target.GrafanaURL, OrgID: grafana.OrgIDFromContext(ctx), Changes: []plan.Change{}}
+	desired := map[string]bool{}
+	for i, item := range items {
+		dashboard, _ := item.(map[string]any)
+		uid, _ := dashboard["uid"].(string)
+		if strings.TrimSpace(uid) == "" {
+			return nil, fmt.Errorf("dashboards[%d] must have a uid", i)
+		}
+		if desired[uid] {
+			return nil, fmt.Errorf("dashboards[%d] has the uid %s of an earlier dashboard; uids must be unique", i, uid)
+		}
+		desired[uid] = true
+		title, _ := dashboard["title"].(string)
+
+		current, err := t.grafanaSvc.GetDashboard(ctx, uid, target.GrafanaURL, target.APIKey)
+		if err != nil && !errors.Is(err, grafana.ErrDashboardNotFound) {
+			return nil, err
+		}
+		if current == nil {
+			folder := folderPath
+			if folder == "" {
+				folder = folderUID
+			}
+			p.Changes = append(p.Changes, plan.Change{
+				Action:     plan.Create,
+				UID:        uid,
+				Title:      title,
+				Folder:     folder,
+				Dashboard:  dashboard,
+				FolderUID:  folderUID,
+				FolderPath: folderPath,
+			})
+			continue
+		}
+
+		changes := diff.Dashboards(current.Dashboard, dashboard)
+		if len(changes) == 0 {
+			p.Unchanged++
+			continue
+		}
+		version, _ := toInt(current.Dashboard["version"])
+		p.Changes = append(p.Changes, plan.Change{
+			Action:    plan.Update,
+			UID:       uid,
+			Title:     title,
+			Version:   version,
+			Changes:   changes,
+			Dashboard: dashboard,
+			FolderUID: current.FolderUID,
+		})
+	}
+
+	if pruneTag, _ := args["prune_tag"].(string); strings.TrimSpace(pruneTag) != "" {
+		deletes, err := t.planDeletes(ctx, target, strings.TrimSpace(pruneTag), desired)
+		if err != nil {
+			return nil, err
+		}
+		p.Changes = append(p.Changes, deletes...)
+	}
+
+	id, err := t.plans.Put(p)
+	if err != nil {
+		return nil, err
+	}
+	summary := p.Summary()
+
+	t.logger.Info("planned dashboard sync",
+		zap.String("plan_id", id),
+		zap.Int("create", summary.Create),
+		zap.Int("update", summary.Update),
+		zap.Int("delete", summary.Delete))
+
+	return &SyncDashboardsPlanResponse{
+		PlanID:     id,
+		GrafanaURL: target.GrafanaURL,
+		Summary:    summary.String(),
+		Counts:     summary,
+		Changes:    p.Changes,
+		ExpiresAt:  p.CreatedAt.Add(planTTL),
+	}, nil
+}
+
+// planDeletes plans the deletion of the dashboards carrying the prune tag
+// that are not among the desired dashboards
+func (t *SyncDashboardsTool) planDeletes(ctx context.Context, target *deploy.Target, pruneTag string, desired map[string]bool) ([]plan.Change, error) {
+	hits, err := t.grafanaSvc.SearchDashboards(ctx, "", target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var deletes []plan.Change
+	for _, hit := range hits {
+		if desired[hit.UID] || !slices.Contains(hit.Tags, pruneTag) {
+			continue
+		}
+		current, err := t.grafanaSvc.GetDashboard(ctx, hit.UID, target.GrafanaURL, target.APIKey)
+		if errors.Is(err, grafana.ErrDashboardNotFound) || (err == nil && current == nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		version, _ := toInt(current.Dashboard["version"])
+		deletes = append(deletes, plan.Change{
+			Action:    plan.Delete,
+			UID:       hit.UID,
+			Title:     hit.Title,
+			Folder:    hit.FolderTitle,
+			Version:   version,
+			FolderUID: current.FolderUID,
+		})
+	}
+	return deletes, nil
+}
+
+// apply makes the changes of a plan, skipping the dashboards that changed
+// since it was made
+func (t *SyncDashboardsTool) apply(ctx context.Context, args map[string]any) (*SyncDashboardsApplyResponse, error) {
+	planID, _ := args["plan_id"].(string)
+	if strings.TrimSpace(planID) == "" {
+		return nil, fmt.Errorf("plan_id is required for apply; run mode plan first")
+	}
+	p, err := t.plans.Take(strings.TrimSpace(planID))
+	if err != nil {
+		return nil, err
+	}
+	if p.OrgID > 0 {
+		ctx = grafana.WithOrgID(ctx, p.OrgID)
+	}
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, p.GrafanaURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = "Synced via grafana-agent (plan " + p.ID + ")"
+	}
+
+	response := &SyncDashboardsApplyResponse{PlanID: p.ID, GrafanaURL: target.GrafanaURL, Results: []AppliedChange{}}
+	for _, change := range p.Changes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := t.applyChange(ctx, deployer, target, change, message)
+		if result.Status == "applied" {
+			response.Applied++
+		} else {
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Summary = fmt.Sprintf("Apply complete: %d of %d changes applied, %d failed.", response.Applied, len(p.Changes), response.Failed)
+
+	t.logger.Info("applied dashboard sync plan",
+		zap.String("plan_id", p.ID),
+		zap.Int("applied", response.Applied),
+		zap.Int("failed", response.Failed))
+
+	return response, nil
+}
+
+// applyChange makes a single planned change
+func (t *SyncDashboardsTool) applyChange(ctx context.Context, deployer *deploy.Deployer, target *deploy.Target, change plan.Change, message string) AppliedChange {
+	applied := AppliedChange{Action: change.Action, UID: change.UID, Title: change.Title}
+	fail := func(status string, err error) AppliedChange {
+		applied.Status, applied.Error = status, err.Error()
+		return applied
+	}
+
+	if change.Action != plan.Create {
+		current, err := t.grafanaSvc.GetDashboard(ctx, change.UID, target.GrafanaURL, target.APIKey)
+		if err != nil && !errors.Is(err, grafana.ErrDashboardNotFound) {
+			return fail("failed", err)
+		}
+		if current == nil {
+			return fail("stale", fmt.Errorf("dashboard %s was deleted since the plan was made", change.UID))
+		}
+		if version, _ := toInt(current.Dashboard["version"]); version != change.Version {
+			return fail("stale", fmt.Errorf("dashboard %s changed since the plan was made (version %d, planned against %d); plan again", change.UID, version, change.Version))
+		}
+	}
+
+	if change.Action == plan.Delete {
+		if err := deployer.Delete(ctx, target, change.UID, change.FolderUID); err != nil {
+			return fail("failed", err)
+		}
+		applied.Status = "applied"
+		return applied
+	}
+
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:   change.Dashboard,
+		GrafanaURL:  target.GrafanaURL,
+		FolderUID:   change.FolderUID,
+		FolderPath:  change.FolderPath,
+		Message:     message,
+		BaseVersion: change.Version,
+		Provenance:  dashboardProvenance(ctx, change.Dashboard),
+	})
+	if err != nil {
+		return fail("failed", err)
+	}
+	applied.Result = result
+	applied.Status = "applied"
+	if result.Conflict != nil {
+		applied.Status = "conflict"
+	}
+	return applied
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	plan "github.com/inference-gateway/grafana-agent/internal/plan"
+)
+
+func newSyncDashboardsTool(mock *mockGrafanaService) *SyncDashboardsTool {
+	return &SyncDashboardsTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			URL:           "http://grafana.test",
+			APIKey:        "test-key",
+			DeployEnabled: true,
+		},
+		plans: plan.NewStore(planTTL),
+	}
+}
+
+func runSyncDashboards[T any](t *testing.T, tool *SyncDashboardsTool, args map[string]any) T {
+	t.Helper()
+
+	result, err := tool.SyncDashboardsHandler(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response T
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response
+}
+
+func TestSyncDashboardsHandler(t *testing.T) {
+	deployed := map[string]map[string]any{
+		"checkout": {"uid": "checkout", "title": "Checkout", "version": float64(4), "refresh": "1m"},
+		"payments": {"uid": "payments", "title": "Payments", "version": float64(2)},
+		"legacy":   {"uid": "legacy", "title": "Legacy", "version": float64(9)},
+	}
+	var saved []grafana.Dashboard
+	mock := &mockGrafanaService{
+		dashboards: []grafana.DashboardHit{
+			{UID: "checkout", Title: "Checkout", Tags: []string{"managed"}},
+			{UID: "payments", Title: "Payments", Tags: []string{"managed"}},
+			{UID: "legacy", Title: "Legacy", Tags: []string{"managed"}},
+			{UID: "adhoc", Title: "Ad hoc"},
+		},
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			if dashboard, ok := deployed[uid]; ok {
+				return &grafana.Dashboard{Dashboard: dashboard, FolderUID: "platform"}, nil
+			}
+			return nil, grafana.ErrDashboardNotFound
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			saved = append(saved, dashboard)
+			return &grafana.DashboardResponse{UID: dashboard.Dashboard["uid"].(string), Version: 1}, nil
+		},
+	}
+	tool := newSyncDashboardsTool(mock)
+
+	planned := runSyncDashboards[SyncDashboardsPlanResponse](t, tool, map[string]any{
+		"dashboards": []any{
+			map[string]any{"uid": "checkout", "title": "Checkout", "refresh": "30s"},
+			map[string]any{"uid": "payments", "title": "Payments"},
+			map[string]any{"uid": "orders", "title": "Orders"},
+		},
+		"folder":    "Platform",
+		"prune_tag": "managed",
+	})
+	if planned.Summary != "Plan: 1 to add, 1 to change, 1 to destroy." {
+		t.Errorf("Unexpected summary %q", planned.Summary)
+	}
+	if planned.Counts.Unchanged != 1 || len(planned.Changes) != 3 {
+		t.Fatalf("Unexpected plan %+v", planned)
+	}
+	if update := planned.Changes[0]; update.Action != plan.Update || update.Version != 4 || len(update.Changes) != 1 {
+		t.Errorf("Unexpected update %+v", update)
+	}
+	if len(saved) != 0 || len(mock.deleted) != 0 {
+		t.Fatal("Expected plan to change nothing")
+	}
+
+	// Someone edits the checkout dashboard between plan and apply
+	deployed["checkout"]["version"] = float64(5)
+
+	applied := runSyncDashboards[SyncDashboardsApplyResponse](t, tool, map[string]any{"mode": "apply", "plan_id": planned.PlanID})
+	if applied.Applied != 2 || applied.Failed != 1 {
+		t.Fatalf("Unexpected apply %+v", applied)
+	}
+	if applied.Results[0].Status != "stale" || applied.Results[1].Status != "applied" || applied.Results[2].Status != "applied" {
+		t.Errorf("Unexpected results %+v", applied.Results)
+	}
+	if len(saved) != 1 || saved[0].Dashboard["uid"] != "orders" || saved[0].Overwrite {
+		t.Errorf("Expected only orders to be created, got %+v", saved)
+	}
+	if len(mock.deleted) != 1 || mock.deleted[0] != "legacy" {
+		t.Errorf("Expected legacy to be deleted, got %v", mock.deleted)
+	}
+
+	_, err := tool.SyncDashboardsHandler(context.Background(), map[string]any{"mode": "apply", "plan_id": planned.PlanID})
+	if !errors.Is(err, plan.ErrNotFound) {
+		t.Errorf("Expected an applied plan to be gone, got %v", err)
+	}
+}
+
+func TestSyncDashboardsHandler_Errors(t *testing.T) {
+	tool := newSyncDashboardsTool(&mockGrafanaService{})
+
+	for name, args := range map[string]map[string]any{
+		"no dashboards": {},
+		"missing uid":   {"dashboards": []any{map[string]any{"title": "No UID"}}},
+		"duplicate uid": {"dashboards": []any{map[string]any{"uid": "a"}, map[string]any{"uid": "a"}}},
+		"folder clash":  {"dashboards": []any{map[string]any{"uid": "a"}}, "folder": "A", "folder_uid": "b"},
+		"apply no plan": {"mode": "apply"},
+		"unknown plan":  {"mode": "apply", "plan_id": "missing"},
+		"unknown mode":  {"mode": "destroy"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tool.SyncDashboardsHandler(context.Background(), args); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// defaultTestSummary is the summary of the test alert when none is given
+const defaultTestSummary = "Test notification sent by grafana-agent to verify the contact point"
+
+// TestContactPointTool struct holds the tool with services
+type TestContactPointTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewTestContactPointTool creates a new test_contact_point tool
+func NewTestContactPointTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &TestContactPointTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"test_contact_point",
+		"Sends a test notification through every integration (Slack, PagerDuty, email, ...) of a Grafana contact point and reports which ones delivered it, to verify alerting wiring right after it is configured",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"labels": map[string]any{
+					"description": "Extra labels of the test alert, e.g. to show which team or service the test is for",
+					"type":        "object",
+				},
+				"name": map[string]any{
+					"description": "Name of the contact point to test",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"summary": map[string]any{
+					"description": "Summary annotation of the test alert (defaults to a note that grafana-agent sent it)",
+					"type":        "string",
+				},
+			},
+			"required": []string{"name"},
+		},
+		tool.TestContactPointHandler,
+	)
+}
+
+// IntegrationResult is the test outcome of one contact point integration
+type IntegrationResult struct {
+	UID    string `json:"uid"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TestContactPointResponse reports a contact point test
+type TestContactPointResponse struct {
+	ContactPoint string              `json:"contact_point"`
+	NotifiedAt   time.Time           `json:"notified_at"`
+	Succeeded    int                 `json:"succeeded"`
+	Failed       int                 `json:"failed"`
+	Integrations []IntegrationResult `json:"integrations"`
+}
+
+// TestContactPointHandler handles the test_contact_point tool execution
+func (t *TestContactPointTool) TestContactPointHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "test_contact_point")
+	defer span.End()
+
+	name, _ := args["name"].(string)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	integrations, err := t.grafanaSvc.ListContactPoints(ctx, name, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+	// Older Grafana versions ignore the name filter
+	var matching []grafana.ContactPoint
+	for _, integration := range integrations {
+		if integration.Name == name {
+			matching = append(matching, integration)
+		}
+	}
+	if len(matching) == 0 {
+		return "", fmt.Errorf("contact point %q not found", name)
+	}
+
+	alert := grafana.TestAlert{
+		Labels:      map[string]string{"alertname": "TestAlert", "instance": "grafana-agent"},
+		Annotations: map[string]string{"summary": getStringOrDefault(args, "summary", defaultTestSummary)},
+	}
+	if labels, ok := args["labels"].(map[string]any); ok {
+		for key, value := range labels {
+			alert.Labels[key] = fmt.Sprint(value)
+		}
+	}
+
+	test, err := t.grafanaSvc.TestContactPoint(ctx, name, matching, alert, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	types := make(map[string]string, len(matching))
+	for _, integration := range matching {
+		types[integration.UID] = integration.Type
+	}
+	response := TestContactPointResponse{ContactPoint: name, NotifiedAt: test.NotifiedAt, Integrations: make([]IntegrationResult, 0, len(test.Integrations))}
+	for _, integration := range test.Integrations {
+		if integration.Status == "ok" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+		response.Integrations = append(response.Integrations, IntegrationResult{
+			UID:    integration.UID,
+			Type:   types[integration.UID],
+			Status: integration.Status,
+			Error:  integration.Error,
+		})
+	}
+
+	t.logger.Info("tested contact point",
+		zap.String("contact_point", name),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func runTestContactPoint(t *testing.T, mock *mockGrafanaService, args map[string]any) (TestContactPointResponse, error) {
+	t.Helper()
+
+	tool := &TestContactPointTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+	result, err := tool.TestContactPointHandler(context.Background(), args)
+	if err != nil {
+		return TestContactPointResponse{}, err
+	}
+
+	var response TestContactPointResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, nil
+}
+
+func TestTestContactPointHandler(t *testing.T) {
+	mock := &mockGrafanaService{contactPoints: []grafana.ContactPoint{
+		{UID: "slack-1", Name: "oncall", Type: "slack", Settings: map[string]any{"url": "[REDACTED]"}},
+		{UID: "pd-1", Name: "oncall", Type: "pagerduty", Settings: map[string]any{"url": "invalid"}},
+		{UID: "email-1", Name: "reports", Type: "email"},
+	}}
+
+	response, err := runTestContactPoint(t, mock, map[string]any{"name": "oncall", "labels": map[string]any{"team": "payments"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Succeeded != 1 || response.Failed != 1 || len(response.Integrations) != 2 {
+		t.Fatalf("unexpected test result %+v", response)
+	}
+	if failed := response.Integrations[1]; failed.Type != "pagerduty" || failed.Error != "invalid webhook URL" {
+		t.Errorf("unexpected failed integration %+v", failed)
+	}
+	if mock.testedAlert.Labels["team"] != "payments" || mock.testedAlert.Annotations["summary"] != defaultTestSummary {
+		t.Errorf("unexpected test alert %+v", mock.testedAlert)
+	}
+
+	if _, err := runTestContactPoint(t, mock, map[string]any{"name": "missing"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := runTestContactPoint(t, mock, map[string]any{"name": " "}); err == nil || !strings.Contains(err.Error(), "is required") {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
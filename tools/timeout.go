@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+)
+
+// timeoutTool bounds each execution of the wrapped tool with a deadline.
+// The deadline propagates through the handler's context into every
+// Grafana and Prometheus request it makes.
+type timeoutTool struct {
+	server.Tool
+	timeout time.Duration
+}
+
+// WithTimeout wraps a tool so each call is cancelled after the timeout
+// configured for it (TIMEOUTS_TOOLS), falling back to TIMEOUTS_DEFAULT. A
+// zero timeout leaves the tool unbounded.
+func WithTimeout(tool server.Tool, cfg *config.TimeoutsConfig) server.Tool {
+	timeout := toolTimeout(tool.GetName(), cfg)
+	if timeout <= 0 {
+		return tool
+	}
+	return &timeoutTool{Tool: tool, timeout: timeout}
+}
+
+// Execute runs the wrapped tool under the configured deadline
+func (t *timeoutTool) Execute(ctx context.Context, args map[string]any) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.Tool.Execute(ctx, args)
+}
+
+// toolTimeout resolves the timeout for a tool from its override or the default
+func toolTimeout(name string, cfg *config.TimeoutsConfig) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	if timeout, ok := cfg.Tools[name]; ok {
+		return timeout
+	}
+	return cfg.Default
+}
+
+// partialWarning describes why a multi-step tool returned partial results
+func partialWarning(err error, done, total int, noun string) string {
+	reason := "was cancelled"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = "timed out"
+	}
+	return fmt.Sprintf("request %s after %d of %d %s; results are partial", reason, done, total, noun)
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestToolTimeout(t *testing.T) {
+	cfg := &config.TimeoutsConfig{
+		Default: time.Minute,
+		Tools:   map[string]time.Duration{"verify_dashboard_data": 3 * time.Minute, "create_dashboard": 0},
+	}
+
+	if got := toolTimeout("discover_metrics", cfg); got != time.Minute {
+		t.Errorf("Expected default timeout, got %v", got)
+	}
+	if got := toolTimeout("verify_dashboard_data", cfg); got != 3*time.Minute {
+		t.Errorf("Expected override timeout, got %v", got)
+	}
+	if got := toolTimeout("create_dashboard", cfg); got != 0 {
+		t.Errorf("Expected explicit zero to disable the timeout, got %v", got)
+	}
+	if got := toolTimeout("discover_metrics", nil); got != 0 {
+		t.Errorf("Expected no timeout without config, got %v", got)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	tool := server.NewBasicTool("probe", "probe", map[string]any{"type": "object"}, func(ctx context.Context, args map[string]any) (string, error) {
+		deadline, hasDeadline = ctx.Deadline()
+		return "ok", nil
+	})
+
+	wrapped := WithTimeout(tool, &config.TimeoutsConfig{Default: time.Minute})
+	if wrapped.GetName() != "probe" {
+		t.Errorf("Expected wrapped tool to keep its name, got %s", wrapped.GetName())
+	}
+	if _, err := wrapped.Execute(context.Background(), map[string]any{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !hasDeadline || time.Until(deadline) > time.Minute {
+		t.Errorf("Expected a deadline within one minute, got %v (set: %v)", deadline, hasDeadline)
+	}
+
+	if unbounded := WithTimeout(tool, &config.TimeoutsConfig{}); unbounded != tool {
+		t.Error("Expected a zero timeout to return the tool unchanged")
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_PartialOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.GetMetricMetadataStub = func(ctx context.Context, url, name string) (*promql.MetricInfo, error) {
+		if name == "second_total" {
+			cancel()
+			return nil, ctx.Err()
+		}
+		return &promql.MetricInfo{Name: name, Type: promql.MetricTypeCounter}, nil
+	}
+	fakePromQL.GenerateQueriesReturns([]promql.QuerySuggestion{{Query: "rate(first_total[5m])"}})
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: fakePromQL}
+	result, err := tool.GeneratePromqlQueriesHandler(ctx, map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"first_total", "second_total", "third_total"},
+	})
+	if err != nil {
+		t.Fatalf("Expected partial result, got error: %v", err)
+	}
+
+	var response GeneratePromqlQueriesResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+	if !response.Partial || len(response.Results) != 1 || len(response.SkippedMetrics) != 2 {
+		t.Errorf("Expected 1 result and 2 skipped metrics, got %+v", response)
+	}
+	if len(response.Warnings) != 1 || response.Warnings[0] != "request was cancelled after 1 of 3 metrics; results are partial" {
+		t.Errorf("Unexpected warnings %v", response.Warnings)
+	}
+}
+
+func TestGeneratePromqlQueriesHandler_TimeoutBeforeAnyMetric(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tool := &GeneratePromqlQueriesTool{logger: zap.NewNop(), promql: &promqlfakes.FakePromQL{}}
+	_, err := tool.GeneratePromqlQueriesHandler(ctx, map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"metric_names":   []any{"first_total"},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if code := toToolError(err).Code; code != ErrCodeTimeout {
+		t.Errorf("Expected timeout code, got %s", code)
+	}
+}
@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"fmt"
+)
+
+// Transformation steps a panel can describe in its transform argument
+const (
+	transformOrganize  = "organize"
+	transformMerge     = "merge"
+	transformGroupBy   = "group_by"
+	transformCalculate = "calculate"
+	transformFilter    = "filter"
+)
+
+// transformReducers are the Grafana reducers group_by aggregations and
+// calculate rows may use
+var transformReducers = []string{"last", "lastNotNull", "first", "min", "max", "mean", "sum", "count", "distinctCount"}
+
+// filterMatchers map the comparisons of filter steps to Grafana's value
+// matchers
+var filterMatchers = map[string]string{
+	">":        "greater",
+	">=":       "greaterOrEqual",
+	"<":        "lower",
+	"<=":       "lowerOrEqual",
+	"==":       "equal",
+	"!=":       "notEqual",
+	"null":     "isNull",
+	"not_null": "isNotNull",
+	"regex":    "regex",
+}
+
+// transformSchema describes the transform steps of a panel definition, in
+// the order Grafana applies them
+var transformSchema = map[string]any{
+	"description": "Transformations applied in order to the panel's query results, each one of organize (exclude, rename and order fields), merge (join the results of several queries into one table), group_by (group rows by fields and aggregate the others), calculate (a field from two others with an operator, or reducing each row's fields) and filter (keep or drop rows by the value of a field)",
+	"type":        "array",
+	"items": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type": map[string]any{
+				"description": "Kind of step",
+				"type":        "string",
+				"enum":        []string{transformOrganize, transformMerge, transformGroupBy, transformCalculate, transformFilter},
+			},
+			"exclude": map[string]any{
+				"description": "Fields to hide (organize)",
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+			},
+			"rename": map[string]any{
+				"description": "New names keyed by field name (organize)",
+				"type":        "object",
+			},
+			"order": map[string]any{
+				"description": "Fields to show first, in order (organize)",
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+			},
+			"group": map[string]any{
+				"description": "Fields whose values form the groups (group_by)",
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+			},
+			"aggregate": map[string]any{
+				"description": "Fields to aggregate within each group, with their reducers (group_by)",
+				"type":        "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"field":    map[string]any{"type": "string"},
+						"reducers": map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": transformReducers}},
+					},
+					"required": []string{"field", "reducers"},
+				},
+			},
+			"alias": map[string]any{
+				"description": "Name of the new field (calculate)",
+				"type":        "string",
+			},
+			"left": map[string]any{
+				"description": "Field or number left of the operator (calculate)",
+				"type":        "string",
+			},
+			"operator": map[string]any{
+				"description": "Operator between left and right (calculate)",
+				"type":        "string",
+				"enum":        []string{"+", "-", "*", "/"},
+			},
+			"right": map[string]any{
+				"description": "Field or number right of the operator (calculate)",
+				"type":        "string",
+			},
+			"reducer": map[string]any{
+				"description": "Reducer over each row's fields, instead of an operator (calculate)",
+				"type":        "string",
+				"enum":        transformReducers,
+			},
+			"fields": map[string]any{
+				"description": "Fields the reducer reads, by default all numeric fields (calculate)",
+				"type":        "array",
+				"items":       map[string]any{"type": "string"},
+			},
+			"field": map[string]any{
+				"description": "Field whose value is compared (filter)",
+				"type":        "string",
+			},
+			"op": map[string]any{
+				"description": "Comparison of the field's value (filter)",
+				"type":        "string",
+				"enum":        []string{">", ">=", "<", "<=", "==", "!=", "null", "not_null", "regex"},
+			},
+			"value": map[string]any{
+				"description": "Number, or pattern for regex, to compare with (filter)",
+			},
+			"exclude_matches": map[string]any{
+				"description": "Drop the matching rows instead of keeping them (filter)",
+				"type":        "boolean",
+			},
+		},
+		"required": []string{"type"},
+	},
+}
+
+// organizeTransformation hides the excluded fields, renames fields and puts
+// the ordered fields first
+func organizeTransformation(exclude []string, rename map[string]string, order []string) map[string]any {
+	excludeByName := map[string]any{}
+	for _, name := range exclude {
+		excludeByName[name] = true
+	}
+	renameByName := map[string]any{}
+	for from, to := range rename {
+		renameByName[from] = to
+	}
+	indexByName := map[string]any{}
+	for i, name := range order {
+		indexByName[name] = i
+	}
+	return map[string]any{"id": "organize", "options": map[string]any{
+		"excludeByName": excludeByName,
+		"renameByName":  renameByName,
+		"indexByName":   indexByName,
+	}}
+}
+
+// mergeTransformation joins the frames of several queries into one table,
+// matching rows on their common fields
+func mergeTransformation() map[string]any {
+	return map[string]any{"id": "merge", "options": map[string]any{}}
+}
+
+// groupByTransformation groups rows by the group fields and reduces the
+// aggregated fields within each group
+func groupByTransformation(group []string, aggregate map[string][]string) map[string]any {
+	fields := map[string]any{}
+	for _, name := range group {
+		fields[name] = map[string]any{"operation": "groupby", "aggregations": []any{}}
+	}
+	for name, reducers := range aggregate {
+		aggregations := make([]any, len(reducers))
+		for i, reducer := range reducers {
+			aggregations[i] = reducer
+		}
+		fields[name] = map[string]any{"operation": "aggregate", "aggregations": aggregations}
+	}
+	return map[string]any{"id": "groupBy", "options": map[string]any{"fields": fields}}
+}
+
+// binaryCalculation adds the alias field computed from left and right,
+// each a field name or a number
+func binaryCalculation(alias, left, operator, right string) map[string]any {
+	return map[string]any{"id": "calculateField", "options": map[string]any{
+		"mode":   "binary",
+		"alias":  alias,
+		"binary": map[string]any{"left": left, "operator": operator, "right": right},
+	}}
+}
+
+// rowCalculation adds the alias field reducing each row's fields, all
+// numeric ones when fields is empty
+func rowCalculation(alias, reducer string, fields []string) map[string]any {
+	reduce := map[string]any{"reducer": reducer}
+	if len(fields) > 0 {
+		reduce["include"] = fields
+	}
+	return map[string]any{"id": "calculateField", "options": map[string]any{
+		"mode":   "reduceRow",
+		"alias":  alias,
+		"reduce": reduce,
+	}}
+}
+
+// filterByValueTransformation keeps the rows whose field matches, or drops
+// them when exclude is set
+func filterByValueTransformation(field, op string, value any, exclude bool) map[string]any {
+	options := map[string]any{}
+	if value != nil {
+		options["value"] = value
+	}
+	mode := "include"
+	if exclude {
+		mode = "exclude"
+	}
+	return map[string]any{"id": "filterByValue", "options": map[string]any{
+		"type":  mode,
+		"match": "any",
+		"filters": []any{map[string]any{
+			"fieldName": field,
+			"config":    map[string]any{"id": filterMatchers[op], "options": options},
+		}},
+	}}
+}
+
+// buildTransformations converts a panel's transform steps, already checked
+// against transformSchema, to Grafana transformations
+func buildTransformations(steps []any) ([]any, error) {
+	transformations := make([]any, 0, len(steps))
+	for i, raw := range steps {
+		step, _ := raw.(map[string]any)
+		var transformation map[string]any
+		switch step["type"] {
+		case transformOrganize:
+			renames, _ := step["rename"].(map[string]any)
+			rename := map[string]string{}
+			for from, to := range renames {
+				name, ok := to.(string)
+				if !ok {
+					return nil, fmt.Errorf("transform step %d: rename of %s must be a string", i, from)
+				}
+				rename[from] = name
+			}
+			transformation = organizeTransformation(schemaStrings(step["exclude"]), rename, schemaStrings(step["order"]))
+		case transformMerge:
+			transformation = mergeTransformation()
+		case transformGroupBy:
+			group := schemaStrings(step["group"])
+			if len(group) == 0 {
+				return nil, fmt.Errorf("transform step %d: group_by needs the fields to group by", i)
+			}
+			aggregations, _ := step["aggregate"].([]any)
+			aggregate := map[string][]string{}
+			for _, rawAggregation := range aggregations {
+				aggregation, _ := rawAggregation.(map[string]any)
+				field, _ := aggregation["field"].(string)
+				aggregate[field] = schemaStrings(aggregation["reducers"])
+			}
+			transformation = groupByTransformation(group, aggregate)
+		case transformCalculate:
+			alias, _ := step["alias"].(string)
+			left, _ := step["left"].(string)
+			operator, _ := step["operator"].(string)
+			right, _ := step["right"].(string)
+			reducer, _ := step["reducer"].(string)
+			switch {
+			case operator != "" && left != "" && right != "":
+				transformation = binaryCalculation(alias, left, operator, right)
+			case reducer != "":
+				transformation = rowCalculation(alias, reducer, schemaStrings(step["fields"]))
+			default:
+				return nil, fmt.Errorf("transform step %d: calculate needs left, operator and right, or a reducer", i)
+			}
+		case transformFilter:
+			field, _ := step["field"].(string)
+			op, _ := step["op"].(string)
+			if field == "" || op == "" {
+				return nil, fmt.Errorf("transform step %d: filter needs a field and an op", i)
+			}
+			exclude, _ := step["exclude_matches"].(bool)
+			transformation = filterByValueTransformation(field, op, step["value"], exclude)
+		}
+		transformations = append(transformations, transformation)
+	}
+	return transformations, nil
+}
+
+// expandPanelTransforms appends the transformations each panel's transform
+// steps describe to the panel's own transformations
+func expandPanelTransforms(panels []any) error {
+	for i, raw := range panels {
+		panel, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		steps, _ := panel["transform"].([]any)
+		if len(steps) == 0 {
+			continue
+		}
+		transformations, err := buildTransformations(steps)
+		if err != nil {
+			return fmt.Errorf("panel %d: %w", i, err)
+		}
+		existing, _ := panel["transformations"].([]any)
+		panel["transformations"] = append(existing, transformations...)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuildTransformations(t *testing.T) {
+	transformations, err := buildTransformations([]any{
+		map[string]any{"type": "merge"},
+		map[string]any{"type": "group_by", "group": []any{"job"}, "aggregate": []any{map[string]any{"field": "Value", "reducers": []any{"mean", "max"}}}},
+		map[string]any{"type": "calculate", "alias": "Error ratio", "left": "Value #A", "operator": "/", "right": "Value #B"},
+		map[string]any{"type": "calculate", "alias": "Total", "reducer": "sum"},
+		map[string]any{"type": "filter", "field": "Error ratio", "op": ">", "value": 0.01},
+		map[string]any{"type": "organize", "exclude": []any{"Time"}, "rename": map[string]any{"job": "Service"}, "order": []any{"job"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, transformation := range transformations {
+		ids = append(ids, transformation.(map[string]any)["id"].(string))
+	}
+	if got := strings.Join(ids, ","); got != "merge,groupBy,calculateField,calculateField,filterByValue,organize" {
+		t.Errorf("unexpected transformations %s", got)
+	}
+
+	fields := transformations[1].(map[string]any)["options"].(map[string]any)["fields"].(map[string]any)
+	if fields["job"].(map[string]any)["operation"] != "groupby" || len(fields["Value"].(map[string]any)["aggregations"].([]any)) != 2 {
+		t.Errorf("unexpected group by fields %v", fields)
+	}
+	binary := transformations[2].(map[string]any)["options"].(map[string]any)
+	if binary["mode"] != "binary" || binary["binary"].(map[string]any)["right"] != "Value #B" {
+		t.Errorf("unexpected binary calculation %v", binary)
+	}
+	if mode := transformations[3].(map[string]any)["options"].(map[string]any)["mode"]; mode != "reduceRow" {
+		t.Errorf("expected a row reduction, got %v", mode)
+	}
+	filter := transformations[4].(map[string]any)["options"].(map[string]any)["filters"].([]any)[0].(map[string]any)
+	if config := filter["config"].(map[string]any); config["id"] != "greater" || config["options"].(map[string]any)["value"] != 0.01 {
+		t.Errorf("unexpected filter %v", filter)
+	}
+	organize := transformations[5].(map[string]any)["options"].(map[string]any)
+	if organize["renameByName"].(map[string]any)["job"] != "Service" || organize["indexByName"].(map[string]any)["job"] != 0 {
+		t.Errorf("unexpected organize options %v", organize)
+	}
+
+	for _, step := range []map[string]any{
+		{"type": "calculate", "alias": "Ratio", "left": "A"},
+		{"type": "filter", "op": ">"},
+		{"type": "group_by"},
+	} {
+		if _, err := buildTransformations([]any{step}); err == nil {
+			t.Errorf("expected an error for %v", step)
+		}
+	}
+}
+
+func TestExpandPanelTransforms(t *testing.T) {
+	panel := map[string]any{
+		"type":            "table",
+		"transformations": []any{map[string]any{"id": "timeSeriesTable", "options": map[string]any{}}},
+		"transform":       []any{map[string]any{"type": "merge"}},
+	}
+	if err := expandPanelTransforms([]any{panel}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transformations := panel["transformations"].([]any); len(transformations) != 2 || transformations[1].(map[string]any)["id"] != "merge" {
+		t.Errorf("expected the steps after the raw transformations, got %v", transformations)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"panels": map[string]any{"type": "array", "items": map[string]any{"type": "object", "properties": map[string]any{"transform": transformSchema}}},
+		},
+	}
+	err := validateArgs("create_dashboard", schema, map[string]any{"panels": []any{map[string]any{
+		"transform": []any{map[string]any{"type": "filter", "field": "Value", "op": "above"}},
+	}}})
+	var validationErr *ArgumentValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "panels[0].transform[0].op" {
+		t.Errorf("expected the unknown comparison to be rejected, got %v", err)
+	}
+}
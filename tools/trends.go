@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	i18n "github.com/inference-gateway/grafana-agent/internal/i18n"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// trendsTimeFrom is the time range a trends dashboard opens with
+const trendsTimeFrom = "now-30d"
+
+// maxDashboardUIDLength is the longest UID Grafana accepts
+const maxDashboardUIDLength = 40
+
+// perDayUnits maps per-second units to the unit of the daily counts their
+// trends panels show once rate() became increase()
+var perDayUnits = map[string]string{
+	"reqps":  "short",
+	"rps":    "short",
+	"wps":    "short",
+	"iops":   "short",
+	"ops":    "short",
+	"cps":    "short",
+	"Bps":    "decbytes",
+	"binBps": "bytes",
+	"bps":    "decbits",
+	"binbps": "bits",
+}
+
+// dashboardVariants gives a real-time dashboard and its trends variant
+// UIDs derived from the title, so redeploying overwrites the same pair, and
+// links each to the other keeping the variables but not the time range
+func dashboardVariants(realtime, trends map[string]any, language string) {
+	printer := i18n.NewPrinter(language)
+	title, _ := realtime["title"].(string)
+	realtime["uid"] = variantUID(title, "realtime")
+	trends["uid"] = variantUID(title, "trends")
+
+	link := func(title string, target map[string]any) map[string]any {
+		return map[string]any{
+			"title":       title,
+			"type":        "link",
+			"url":         fmt.Sprintf("/d/%s", target["uid"]),
+			"icon":        "dashboard",
+			"includeVars": true,
+			"keepTime":    false,
+		}
+	}
+	realtimeLinks, _ := realtime["links"].([]any)
+	realtime["links"] = append(realtimeLinks, link(printer.Sprintf("Trends over 30 days"), trends))
+	trendsLinks, _ := trends["links"].([]any)
+	trends["links"] = append(trendsLinks, link(printer.Sprintf("Real-time"), realtime))
+}
+
+// variantUID derives the UID of a dashboard variant from the dashboard
+// title, within Grafana's UID length limit
+func variantUID(title, variant string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && slug.Len() > 0 {
+			slug.WriteByte('-')
+			dash = true
+		}
+	}
+	base := strings.TrimSuffix(slug.String(), "-")
+	if limit := maxDashboardUIDLength - len(variant) - 1; len(base) > limit {
+		base = strings.TrimSuffix(base[:limit], "-")
+	}
+	if base == "" {
+		return variant
+	}
+	return base + "-" + variant
+}
+
+// trendsDashboard derives the trends variant of a real-time dashboard model:
+// 30 days without auto refresh, every query rewritten to daily increase()
+// and avg_over_time() (see promql.TrendsQuery) with one point per day, per
+// second units turned into daily counts and generated panel descriptions
+// redone for the new queries
+func trendsDashboard(realtime map[string]any, language string) (map[string]any, error) {
+	data, err := json.Marshal(realtime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy dashboard: %w", err)
+	}
+	var trends map[string]any
+	if err := json.Unmarshal(data, &trends); err != nil {
+		return nil, fmt.Errorf("failed to copy dashboard: %w", err)
+	}
+
+	printer := i18n.NewPrinter(language)
+	describer := promql.NewDescriber(language)
+	title, _ := realtime["title"].(string)
+	trends["title"] = printer.Sprintf("%s (trends)", title)
+	trends["time"] = map[string]any{"from": trendsTimeFrom, "to": "now"}
+	trends["refresh"] = ""
+
+	for _, panel := range flattenPanels(trends) {
+		targets, _ := panel["targets"].([]any)
+		if len(targets) == 0 || panel["type"] == "row" {
+			continue
+		}
+		generated := panel["description"] == describePanelQueries(panel, describer)
+
+		perDay := false
+		for _, raw := range targets {
+			target, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			if expr, ok := target["expr"].(string); ok && strings.TrimSpace(expr) != "" {
+				query, rated := promql.TrendsQuery(expr)
+				target["expr"] = query
+				perDay = perDay || rated
+			}
+		}
+		panel["interval"] = promql.TrendsWindow
+
+		fieldConfig, _ := panel["fieldConfig"].(map[string]any)
+		if defaults, ok := fieldConfig["defaults"].(map[string]any); ok && perDay {
+			unit, _ := defaults["unit"].(string)
+			if perDayUnit, ok := perDayUnits[unit]; ok {
+				defaults["unit"] = perDayUnit
+			}
+		}
+		if generated {
+			if description := describePanelQueries(panel, describer); description != "" {
+				panel["description"] = description
+			}
+		}
+	}
+	return trends, nil
+}
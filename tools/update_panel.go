@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// UpdatePanelTool struct holds the tool with services
+type UpdatePanelTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+	deployOptions deploy.Options
+}
+
+// NewUpdatePanelTool creates a new update_panel tool
+func NewUpdatePanelTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig, deployOptions deploy.Options) server.Tool {
+	tool := &UpdatePanelTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+		deployOptions: deployOptions,
+	}
+	return newValidatedTool(
+		"update_panel",
+		"Updates a single panel of a deployed dashboard (queries, thresholds, title, type, unit or description) without resubmitting the whole dashboard",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard",
+					"type":        "string",
+				},
+				"description": map[string]any{
+					"description": "New panel description",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"message": map[string]any{
+					"description": "Optional commit message for the new dashboard version",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"panel": map[string]any{
+					"description": "Panel to update, by title (case-insensitive) or numeric panel ID",
+					"type":        "string",
+				},
+				"queries": map[string]any{
+					"description": "New PromQL queries, replacing the panel's queries in order; existing targets keep their datasource and legend",
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+				},
+				"thresholds": map[string]any{
+					"description": "New absolute thresholds in ascending order; the first step is the base color and its value is ignored",
+					"type":        "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"color": map[string]any{"type": "string"},
+							"value": map[string]any{"type": "number"},
+						},
+						"required": []string{"color"},
+					},
+				},
+				"title": map[string]any{
+					"description": "New panel title",
+					"type":        "string",
+				},
+				"type": map[string]any{
+					"description": "New panel type (e.g. timeseries, stat, gauge, table)",
+					"type":        "string",
+				},
+				"unit": map[string]any{
+					"description": "New unit of the panel values (e.g. percent, s, bytes)",
+					"type":        "string",
+				},
+			},
+			"required": []string{"dashboard_uid", "panel"},
+		},
+		tool.UpdatePanelHandler,
+	)
+}
+
+// UpdatePanelResponse represents the outcome of a panel update
+type UpdatePanelResponse struct {
+	*deploy.Result
+	Panel map[string]any `json:"panel"`
+	// Changes describes each field that was changed
+	Changes []string `json:"changes"`
+}
+
+// UpdatePanelHandler handles the update_panel tool execution
+func (t *UpdatePanelTool) UpdatePanelHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "update_panel")
+	defer span.End()
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	dashboardUID, _ := args["dashboard_uid"].(string)
+	panelRef, _ := args["panel"].(string)
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, t.deployOptions)
+	target, err := deployer.ResolveTarget(ctx, grafanaURL, "")
+	if err != nil {
+		return "", err
+	}
+
+	current, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard %s: %w", dashboardUID, err)
+	}
+
+	panel, err := findPanel(current.Dashboard, panelRef)
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := applyPanelUpdate(panel, args)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return "", fmt.Errorf("at least one of title, type, description, unit, queries or thresholds is required")
+	}
+
+	message, _ := args["message"].(string)
+	if message == "" {
+		message = fmt.Sprintf("Updated panel %q via grafana-agent", getStringOrDefault(panel, "title", panelRef))
+	}
+
+	baseVersion, _ := toInt(current.Dashboard["version"])
+	result, err := deployer.Deploy(ctx, deploy.Request{
+		Dashboard:   current.Dashboard,
+		GrafanaURL:  target.GrafanaURL,
+		FolderUID:   current.FolderUID,
+		Message:     message,
+		Overwrite:   baseVersion == 0,
+		BaseVersion: baseVersion,
+		Provenance:  dashboardProvenance(ctx, current.Dashboard),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	jsonBytes, err := json.MarshalIndent(UpdatePanelResponse{
+		Result:  result,
+		Panel:   panel,
+		Changes: changes,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// findPanel returns the panel, including panels nested in collapsed rows,
+// whose numeric ID or case-insensitive title matches ref
+func findPanel(dashboard map[string]any, ref string) (map[string]any, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("panel is required")
+	}
+
+	panels := flattenPanels(dashboard)
+	if id, err := strconv.Atoi(ref); err == nil {
+		for _, panel := range panels {
+			if panelID, ok := toInt(panel["id"]); ok && panelID == id {
+				return panel, nil
+			}
+		}
+	}
+
+	var matches []map[string]any
+	for _, panel := range panels {
+		if title, _ := panel["title"].(string); strings.EqualFold(strings.TrimSpace(title), ref) {
+			matches = append(matches, panel)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("panel %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, panel := range matches {
+			ids = append(ids, fmt.Sprint(panel["id"]))
+		}
+		return nil, fmt.Errorf("panel title %q is ambiguous - pass one of the panel IDs %s", ref, strings.Join(ids, ", "))
+	}
+}
+
+// applyPanelUpdate applies the requested changes to panel in place and
+// describes each one
+func applyPanelUpdate(panel map[string]any, args map[string]any) ([]string, error) {
+	var changes []string
+
+	for _, field := range []string{"title", "type", "description"} {
+		value, ok := args[field].(string)
+		if !ok || value == "" || panel[field] == value {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %q -> %q", field, getStringOrDefault(panel, field, ""), value))
+		panel[field] = value
+	}
+
+	if unit, ok := args["unit"].(string); ok && unit != "" {
+		defaults := fieldConfigDefaults(panel)
+		if defaults["unit"] != unit {
+			changes = append(changes, fmt.Sprintf("unit: %q -> %q", getStringOrDefault(defaults, "unit", ""), unit))
+			defaults["unit"] = unit
+		}
+	}
+
+	if raw, ok := args["queries"].([]any); ok {
+		queries := make([]string, 0, len(raw))
+		for _, q := range raw {
+			if query, ok := q.(string); ok && strings.TrimSpace(query) != "" {
+				queries = append(queries, query)
+			}
+		}
+		if len(queries) == 0 {
+			return nil, fmt.Errorf("queries must contain at least one query")
+		}
+		panel["targets"] = replaceTargetQueries(panel, queries)
+		changes = append(changes, fmt.Sprintf("queries: %s", strings.Join(queries, "; ")))
+	}
+
+	if raw, ok := args["thresholds"].([]any); ok {
+		steps, err := thresholdSteps(raw)
+		if err != nil {
+			return nil, err
+		}
+		fieldConfigDefaults(panel)["thresholds"] = map[string]any{"mode": "absolute", "steps": steps}
+		changes = append(changes, fmt.Sprintf("thresholds: %d steps", len(steps)))
+	}
+
+	return changes, nil
+}
+
+// fieldConfigDefaults returns fieldConfig.defaults of a panel, creating it
+// when missing
+func fieldConfigDefaults(panel map[string]any) map[string]any {
+	fieldConfig, ok := panel["fieldConfig"].(map[string]any)
+	if !ok {
+		fieldConfig = map[string]any{"overrides": []any{}}
+		panel["fieldConfig"] = fieldConfig
+	}
+	defaults, ok := fieldConfig["defaults"].(map[string]any)
+	if !ok {
+		defaults = map[string]any{}
+		fieldConfig["defaults"] = defaults
+	}
+	return defaults
+}
+
+// replaceTargetQueries sets the expressions of a panel's targets in order.
+// Existing targets keep their other fields; extra queries get new targets
+// modelled on the first one, and surplus targets are dropped.
+func replaceTargetQueries(panel map[string]any, queries []string) []any {
+	existing, _ := panel["targets"].([]any)
+
+	targets := make([]any, 0, len(queries))
+	for i, query := range queries {
+		target := map[string]any{}
+		if i < len(existing) {
+			if t, ok := existing[i].(map[string]any); ok {
+				target = t
+			}
+		} else if len(existing) > 0 {
+			if first, ok := existing[0].(map[string]any); ok && first["datasource"] != nil {
+				target["datasource"] = first["datasource"]
+			}
+		}
+		if _, ok := target["refId"]; !ok {
+			target["refId"] = string(rune('A' + i%26))
+		}
+		target["expr"] = query
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// thresholdSteps converts threshold arguments into Grafana threshold steps;
+// the first step is the base step with a null value
+func thresholdSteps(raw []any) ([]any, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("thresholds must contain at least the base color")
+	}
+
+	steps := make([]any, 0, len(raw))
+	previous := 0.0
+	for i, stepRaw := range raw {
+		step, _ := stepRaw.(map[string]any)
+		color, _ := step["color"].(string)
+		if color == "" {
+			return nil, fmt.Errorf("thresholds[%d].color is required", i)
+		}
+		if i == 0 {
+			steps = append(steps, map[string]any{"color": color, "value": nil})
+			continue
+		}
+
+		value, ok := toFloat(step["value"])
+		if !ok {
+			return nil, fmt.Errorf("thresholds[%d].value is required", i)
+		}
+		if i > 1 && value <= previous {
+			return nil, fmt.Errorf("thresholds must be in ascending order, got %v after %v", value, previous)
+		}
+		previous = value
+		steps = append(steps, map[string]any{"color": color, "value": value})
+	}
+	return steps, nil
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// panelDashboard returns a deployed dashboard with a top-level panel and a
+// panel nested in a collapsed row
+func panelDashboard() map[string]any {
+	return map[string]any{
+		"uid":     "checkout",
+		"title":   "Checkout",
+		"version": 3.0,
+		"panels": []any{
+			map[string]any{
+				"id": 1.0, "type": "timeseries", "title": "Request Rate",
+				"targets": []any{
+					map[string]any{"refId": "A", "expr": "rate(http_requests_total[5m])", "legendFormat": "{{route}}", "datasource": map[string]any{"uid": "prom"}},
+				},
+			},
+			map[string]any{
+				"id": 2.0, "type": "row", "title": "Details", "collapsed": true,
+				"panels": []any{
+					map[string]any{"id": 3.0, "type": "stat", "title": "Error Rate"},
+				},
+			},
+		},
+	}
+}
+
+func runUpdatePanel(t *testing.T, args map[string]any) (UpdatePanelResponse, grafana.Dashboard, error) {
+	t.Helper()
+
+	var deployed grafana.Dashboard
+	mock := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: panelDashboard(), FolderUID: "platform"}, nil
+		},
+		createDashboardFunc: func(ctx context.Context, dashboard grafana.Dashboard, grafanaURL, apiKey string) (*grafana.DashboardResponse, error) {
+			deployed = dashboard
+			return &grafana.DashboardResponse{ID: 1, UID: "checkout", Version: 4}, nil
+		},
+	}
+	tool := &UpdatePanelTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mock,
+		grafanaConfig: &config.GrafanaConfig{DeployEnabled: true, URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	args["dashboard_uid"] = "checkout"
+	result, err := tool.UpdatePanelHandler(context.Background(), args)
+	if err != nil {
+		return UpdatePanelResponse{}, deployed, err
+	}
+
+	var response UpdatePanelResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response, deployed, nil
+}
+
+func TestUpdatePanelHandler(t *testing.T) {
+	t.Run("queries and title by title", func(t *testing.T) {
+		response, deployed, err := runUpdatePanel(t, map[string]any{
+			"panel":   "request rate",
+			"title":   "Requests per second",
+			"queries": []any{"sum(rate(http_requests_total[5m]))", "sum(rate(http_requests_total{status=~\"5..\"}[5m]))"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(response.Changes) != 2 || response.Panel["title"] != "Requests per second" {
+			t.Errorf("unexpected response %+v", response)
+		}
+
+		panel := deployed.Dashboard["panels"].([]any)[0].(map[string]any)
+		targets := panel["targets"].([]any)
+		first, second := targets[0].(map[string]any), targets[1].(map[string]any)
+		if first["expr"] != "sum(rate(http_requests_total[5m]))" || first["legendFormat"] != "{{route}}" {
+			t.Errorf("the first target should keep its legend, got %v", first)
+		}
+		if second["refId"] != "B" || second["datasource"] == nil {
+			t.Errorf("the added target should reuse the datasource, got %v", second)
+		}
+		if deployed.FolderUID != "platform" || deployed.Overwrite || deployed.Dashboard["version"] != 3 {
+			t.Errorf("expected a save against version 3 in the same folder, got %+v", deployed)
+		}
+	})
+
+	t.Run("thresholds and unit of a panel in a collapsed row by ID", func(t *testing.T) {
+		_, deployed, err := runUpdatePanel(t, map[string]any{
+			"panel":      "3",
+			"unit":       "percent",
+			"thresholds": []any{map[string]any{"color": "green"}, map[string]any{"color": "red", "value": 5.0}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		row := deployed.Dashboard["panels"].([]any)[1].(map[string]any)
+		defaults := row["panels"].([]any)[0].(map[string]any)["fieldConfig"].(map[string]any)["defaults"].(map[string]any)
+		steps := defaults["thresholds"].(map[string]any)["steps"].([]any)
+		if defaults["unit"] != "percent" || len(steps) != 2 || steps[0].(map[string]any)["value"] != nil || steps[1].(map[string]any)["value"] != 5.0 {
+			t.Errorf("unexpected field config %v", defaults)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			args    map[string]any
+			wantErr string
+		}{
+			{map[string]any{"panel": "Latency", "title": "x"}, `panel "Latency" not found`},
+			{map[string]any{"panel": "Error Rate"}, "at least one of"},
+			{map[string]any{"panel": "1", "thresholds": []any{map[string]any{"color": "green"}, map[string]any{"color": "red", "value": 9.0}, map[string]any{"color": "orange", "value": 5.0}}}, "ascending"},
+		} {
+			_, _, err := runUpdatePanel(t, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
+
+func TestFindPanelAmbiguousTitle(t *testing.T) {
+	dashboard := map[string]any{"panels": []any{
+		map[string]any{"id": 1.0, "title": "Latency"},
+		map[string]any{"id": 2.0, "title": "latency"},
+	}}
+	if _, err := findPanel(dashboard, "Latency"); err == nil || !strings.Contains(err.Error(), "1, 2") {
+		t.Errorf("expected an ambiguity error listing the IDs, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	zap "go.uber.org/zap"
 
@@ -24,7 +25,7 @@ func NewValidatePromqlQueryTool(logger *zap.Logger, promql promql.PromQL) server
 		logger: logger,
 		promql: promql,
 	}
-	return server.NewBasicTool(
+	return newValidatedTool(
 		"validate_promql_query",
 		"Validates a PromQL query against a Prometheus server",
 		map[string]any{
@@ -45,12 +46,16 @@ func NewValidatePromqlQueryTool(logger *zap.Logger, promql promql.PromQL) server
 	)
 }
 
+// maxValidatedFixes caps how many fix candidates are re-validated against Prometheus
+const maxValidatedFixes = 5
+
 // ValidateQueryResponse represents the validation result
 type ValidateQueryResponse struct {
-	PrometheusURL string `json:"prometheus_url"`
-	Query         string `json:"query"`
-	Valid         bool   `json:"valid"`
-	Error         string `json:"error,omitempty"`
+	PrometheusURL string            `json:"prometheus_url"`
+	Query         string            `json:"query"`
+	Valid         bool              `json:"valid"`
+	Error         string            `json:"error,omitempty"`
+	Fixes         []promql.QueryFix `json:"fixes,omitempty"`
 }
 
 // ValidatePromqlQueryHandler handles the validate_promql_query tool execution
@@ -87,6 +92,7 @@ func (t *ValidatePromqlQueryTool) ValidatePromqlQueryHandler(ctx context.Context
 			zap.Error(err))
 		response.Error = err.Error()
 		response.Valid = false
+		response.Fixes = t.rankFixes(ctx, prometheusURL, query)
 	} else {
 		t.logger.Info("query validation succeeded",
 			zap.String("query", query))
@@ -100,3 +106,31 @@ func (t *ValidatePromqlQueryTool) ValidatePromqlQueryHandler(ctx context.Context
 
 	return string(jsonData), nil
 }
+
+// rankFixes builds repair candidates for a failed query, re-validates the most
+// promising ones and orders the candidates that parse ahead of the rest
+func (t *ValidatePromqlQueryTool) rankFixes(ctx context.Context, prometheusURL, query string) []promql.QueryFix {
+	fixes := t.promql.SuggestQueryFixes(query)
+
+	for i := range fixes {
+		if i >= maxValidatedFixes {
+			break
+		}
+		valid := t.promql.ValidateQuery(ctx, prometheusURL, fixes[i].Query) == nil
+		fixes[i].Valid = &valid
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return isValidFix(fixes[i]) && !isValidFix(fixes[j])
+	})
+
+	t.logger.Debug("suggested query fixes",
+		zap.String("query", query),
+		zap.Int("fix_count", len(fixes)))
+
+	return fixes
+}
+
+func isValidFix(fix promql.QueryFix) bool {
+	return fix.Valid != nil && *fix.Valid
+}
@@ -8,6 +8,7 @@ import (
 
 	zap "go.uber.org/zap"
 
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
 	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
 )
 
@@ -242,3 +243,42 @@ func TestValidatePromqlQueryHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePromqlQueryHandler_RanksFixes(t *testing.T) {
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.ValidateQueryStub = func(ctx context.Context, url, query string) error {
+		if query == "rate(up[5m])" {
+			return nil
+		}
+		return errors.New("parse error")
+	}
+	fakePromQL.SuggestQueryFixesReturns([]promql.QueryFix{
+		{Query: "rate(up[5m]", Description: "still broken", Confidence: 0.9},
+		{Query: "rate(up[5m])", Description: "balanced", Confidence: 0.8},
+	})
+
+	tool := &ValidatePromqlQueryTool{logger: zap.NewNop(), promql: fakePromQL}
+
+	result, err := tool.ValidatePromqlQueryHandler(context.Background(), map[string]any{
+		"prometheus_url": "http://prometheus.test:9090",
+		"query":          "rate(up[5m]",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response ValidateQueryResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if response.Valid {
+		t.Error("Expected query to be invalid")
+	}
+	if len(response.Fixes) != 2 {
+		t.Fatalf("Expected 2 fixes, got %d", len(response.Fixes))
+	}
+	if response.Fixes[0].Query != "rate(up[5m])" || response.Fixes[0].Valid == nil || !*response.Fixes[0].Valid {
+		t.Errorf("Expected the validated fix to be ranked first, got %+v", response.Fixes[0])
+	}
+}
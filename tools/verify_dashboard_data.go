@@ -0,0 +1,573 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+)
+
+// VerifyDashboardDataTool struct holds the tool with services
+type VerifyDashboardDataTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	promql        promql.PromQL
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewVerifyDashboardDataTool creates a new verify_dashboard_data tool
+func NewVerifyDashboardDataTool(logger *zap.Logger, grafanaSvc grafana.Grafana, promql promql.PromQL, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &VerifyDashboardDataTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		promql:        promql,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"verify_dashboard_data",
+		"Runs every target of a deployed dashboard over its time range, reports panels with no data and suggests fixed queries",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"dashboard_uid": map[string]any{
+					"description": "UID of the deployed dashboard to verify",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (overrides default configuration if provided)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+				"prometheus_url": map[string]any{
+					"description": "Prometheus server URL the dashboard queries are executed against",
+					"type":        "string",
+				},
+			},
+			"required": []string{"dashboard_uid", "prometheus_url"},
+		},
+		tool.VerifyDashboardDataHandler,
+	)
+}
+
+// SuggestedFix represents a candidate repair for a query that returned no data
+type SuggestedFix struct {
+	Query    string `json:"query"`
+	Reason   string `json:"reason"`
+	Verified bool   `json:"verified"`
+}
+
+// TargetDataReport describes the outcome of executing a single panel target
+type TargetDataReport struct {
+	RefID        string         `json:"ref_id,omitempty"`
+	Expr         string         `json:"expr"`
+	ResolvedExpr string         `json:"resolved_expr,omitempty"`
+	SeriesCount  int            `json:"series_count"`
+	Error        string         `json:"error,omitempty"`
+	Suggestions  []SuggestedFix `json:"suggestions,omitempty"`
+	// Unverifiable says why the target was not run, such as variables the
+	// dashboard does not define
+	Unverifiable string `json:"unverifiable,omitempty"`
+}
+
+// PanelDataReport describes a panel whose targets returned no data
+type PanelDataReport struct {
+	PanelID int                `json:"panel_id"`
+	Title   string             `json:"title"`
+	Targets []TargetDataReport `json:"targets"`
+}
+
+// VerifyDashboardDataResponse represents the verification result
+type VerifyDashboardDataResponse struct {
+	DashboardUID    string            `json:"dashboard_uid"`
+	Title           string            `json:"title"`
+	TimeRange       map[string]string `json:"time_range"`
+	TotalPanels     int               `json:"total_panels"`
+	PanelsWithData  int               `json:"panels_with_data"`
+	EmptyPanels     []PanelDataReport `json:"empty_panels"`
+	EmptyPanelCount int               `json:"empty_panel_count"`
+	// UnverifiablePanels lists the panels none of whose targets could be run
+	UnverifiablePanels []PanelDataReport `json:"unverifiable_panels,omitempty"`
+	// Partial is set when the call was cancelled or timed out before every
+	// panel was verified; UnverifiedPanels counts the ones not checked
+	Partial          bool     `json:"partial,omitempty"`
+	UnverifiedPanels int      `json:"unverified_panels,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// VerifyDashboardDataHandler handles the verify_dashboard_data tool execution
+func (t *VerifyDashboardDataTool) VerifyDashboardDataHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "verify_dashboard_data")
+	defer span.End()
+
+	dashboardUID, ok := args["dashboard_uid"].(string)
+	if !ok || dashboardUID == "" {
+		return "", fmt.Errorf("dashboard_uid is required and must be a string")
+	}
+
+	prometheusURL, ok := args["prometheus_url"].(string)
+	if !ok || prometheusURL == "" {
+		return "", fmt.Errorf("prometheus_url is required and must be a string")
+	}
+
+	grafanaURL, _ := args["grafana_url"].(string)
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	t.logger.Info("verifying dashboard data",
+		zap.String("dashboard_uid", dashboardUID),
+		zap.String("grafana_url", target.GrafanaURL),
+		zap.String("prometheus_url", prometheusURL))
+
+	dashboard, err := t.grafanaSvc.GetDashboard(ctx, dashboardUID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dashboard: %w", err)
+	}
+
+	timeRange := map[string]string{"from": "now-6h", "to": "now"}
+	if tr, ok := dashboard.Dashboard["time"].(map[string]any); ok {
+		if from, ok := tr["from"].(string); ok && from != "" {
+			timeRange["from"] = from
+		}
+		if to, ok := tr["to"].(string); ok && to != "" {
+			timeRange["to"] = to
+		}
+	}
+
+	start, end, err := grafana.ParseTimeRange(timeRange["from"], timeRange["to"], time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve dashboard time range: %w", err)
+	}
+	step := queryStep(start, end)
+
+	verifier := &dashboardVerifier{
+		tool:          t,
+		prometheusURL: prometheusURL,
+		start:         start,
+		end:           end,
+		step:          step,
+		labelValues:   map[string][]string{},
+	}
+
+	response := VerifyDashboardDataResponse{
+		DashboardUID: dashboardUID,
+		Title:        getStringOrDefault(dashboard.Dashboard, "title", ""),
+		TimeRange:    timeRange,
+		EmptyPanels:  []PanelDataReport{},
+	}
+
+	panels := flattenPanels(dashboard.Dashboard)
+	for i, panel := range panels {
+		if ctx.Err() != nil {
+			response.UnverifiedPanels = len(panels) - i
+			break
+		}
+
+		targets, _ := panel["targets"].([]any)
+
+		var reports, unverifiable []TargetDataReport
+		hasData := false
+		for _, targetRaw := range targets {
+			target, ok := targetRaw.(map[string]any)
+			if !ok {
+				continue
+			}
+			expr, ok := target["expr"].(string)
+			if !ok || strings.TrimSpace(expr) == "" {
+				continue
+			}
+
+			report := verifier.verifyTarget(ctx, dashboard.Dashboard, getStringOrDefault(target, "refId", ""), expr)
+			if report.Unverifiable != "" {
+				unverifiable = append(unverifiable, report)
+				continue
+			}
+			if report.SeriesCount > 0 {
+				hasData = true
+			}
+			reports = append(reports, report)
+		}
+
+		// A target interrupted by the deadline has no data through no fault
+		// of its query, so the panel is reported as unverified instead
+		if ctx.Err() != nil {
+			response.UnverifiedPanels = len(panels) - i
+			break
+		}
+
+		panelID := 0
+		if id, ok := panel["id"].(float64); ok {
+			panelID = int(id)
+		} else if id, ok := panel["id"].(int); ok {
+			panelID = id
+		}
+
+		if len(reports) == 0 {
+			if len(unverifiable) > 0 {
+				response.UnverifiablePanels = append(response.UnverifiablePanels, PanelDataReport{
+					PanelID: panelID,
+					Title:   getStringOrDefault(panel, "title", ""),
+					Targets: unverifiable,
+				})
+			}
+			continue
+		}
+
+		response.TotalPanels++
+		if hasData {
+			response.PanelsWithData++
+			continue
+		}
+
+		response.EmptyPanels = append(response.EmptyPanels, PanelDataReport{
+			PanelID: panelID,
+			Title:   getStringOrDefault(panel, "title", ""),
+			Targets: append(reports, unverifiable...),
+		})
+	}
+	response.EmptyPanelCount = len(response.EmptyPanels)
+
+	if response.UnverifiedPanels > 0 {
+		verified := len(panels) - response.UnverifiedPanels
+		if verified == 0 {
+			return "", fmt.Errorf("dashboard verification stopped before any panel was checked: %w", ctx.Err())
+		}
+		t.logger.Warn("returning partial dashboard verification",
+			zap.Int("verified", verified),
+			zap.Int("unverified", response.UnverifiedPanels),
+			zap.Error(ctx.Err()))
+		response.Partial = true
+		response.Warnings = append(response.Warnings, partialWarning(ctx.Err(), verified, len(panels), "panels"))
+	}
+
+	t.logger.Info("verified dashboard data",
+		zap.String("dashboard_uid", dashboardUID),
+		zap.Int("total_panels", response.TotalPanels),
+		zap.Int("empty_panels", response.EmptyPanelCount))
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonData), nil
+}
+
+// dashboardVerifier executes dashboard targets and caches discovery data
+// while proposing repairs for the empty ones
+type dashboardVerifier struct {
+	tool          *VerifyDashboardDataTool
+	prometheusURL string
+	start, end    time.Time
+	step          time.Duration
+	labelValues   map[string][]string
+}
+
+// verifyTarget runs a single target and, when it returns no data, suggests fixes
+func (v *dashboardVerifier) verifyTarget(ctx context.Context, dashboard map[string]any, refID, expr string) TargetDataReport {
+	report := TargetDataReport{RefID: refID, Expr: expr}
+
+	resolved, undefined := resolveTemplateVariables(expr, dashboard, v.end.Sub(v.start))
+	if resolved != expr {
+		report.ResolvedExpr = resolved
+	}
+	if len(undefined) > 0 {
+		report.Unverifiable = "the query references variables the dashboard does not define: $" + strings.Join(undefined, ", $")
+		return report
+	}
+
+	series, err := v.tool.promql.QueryRange(ctx, v.prometheusURL, resolved, v.start, v.end, v.step)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	report.SeriesCount = len(series)
+	if report.SeriesCount > 0 {
+		return report
+	}
+
+	report.Suggestions = v.suggestFixes(ctx, resolved)
+	return report
+}
+
+// suggestFixes proposes repaired queries for label typos, unknown metric
+// names and over-restrictive job filters using the discovery APIs
+func (v *dashboardVerifier) suggestFixes(ctx context.Context, query string) []SuggestedFix {
+	var fixes []SuggestedFix
+	seen := map[string]bool{}
+
+	add := func(candidate, reason string) {
+		if candidate == query || seen[candidate] {
+			return
+		}
+		seen[candidate] = true
+		fixes = append(fixes, SuggestedFix{Query: candidate, Reason: reason})
+	}
+
+	metricNames := v.cachedLabelValues(ctx, "__name__", nil)
+
+	for _, sel := range promql.ParseSelectors(query) {
+		if sel.Metric != "" && len(metricNames) > 0 && !slices.Contains(metricNames, sel.Metric) {
+			for _, candidate := range promql.ClosestMatches(sel.Metric, metricNames, 3, 3) {
+				fixed := sel
+				fixed.Metric = candidate
+				add(replaceSelector(query, sel, fixed),
+					fmt.Sprintf("metric %q does not exist; did you mean %q?", sel.Metric, candidate))
+			}
+			continue
+		}
+
+		scope := []string{}
+		if sel.Metric != "" {
+			scope = append(scope, fmt.Sprintf("{__name__=%q}", sel.Metric))
+		}
+
+		for i, m := range sel.Matchers {
+			if m.Op != "=" || m.Name == "__name__" {
+				continue
+			}
+
+			values := v.cachedLabelValues(ctx, m.Name, scope)
+			if slices.Contains(values, m.Value) {
+				continue
+			}
+
+			if len(values) == 0 {
+				add(replaceSelector(query, sel, withoutMatcher(sel, i)),
+					fmt.Sprintf("no series carry the label %q; removed the %s filter", m.Name, m.Name))
+				continue
+			}
+
+			candidates := promql.ClosestMatches(m.Value, values, 3, 3)
+			for _, candidate := range candidates {
+				fixed := withMatcherValue(sel, i, candidate)
+				add(replaceSelector(query, sel, fixed),
+					fmt.Sprintf("label %s=%q has no series; did you mean %q?", m.Name, m.Value, candidate))
+			}
+
+			if len(candidates) == 0 && m.Name == "job" {
+				add(replaceSelector(query, sel, withoutMatcher(sel, i)),
+					fmt.Sprintf("job %q has no series; removed the job filter (known jobs: %s)", m.Value, strings.Join(firstN(values, 5), ", ")))
+			}
+		}
+	}
+
+	for i := range fixes {
+		series, err := v.tool.promql.QueryRange(ctx, v.prometheusURL, fixes[i].Query, v.start, v.end, v.step)
+		fixes[i].Verified = err == nil && len(series) > 0
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return fixes[i].Verified && !fixes[j].Verified
+	})
+
+	return fixes
+}
+
+// cachedLabelValues fetches label values once per verification run
+func (v *dashboardVerifier) cachedLabelValues(ctx context.Context, label string, matchers []string) []string {
+	key := label + "|" + strings.Join(matchers, ",")
+	if values, ok := v.labelValues[key]; ok {
+		return values
+	}
+
+	values, err := v.tool.promql.GetLabelValues(ctx, v.prometheusURL, label, matchers)
+	if err != nil {
+		v.tool.logger.Debug("failed to fetch label values",
+			zap.String("label", label),
+			zap.Error(err))
+		values = nil
+	}
+	v.labelValues[key] = values
+	return values
+}
+
+// replaceSelector splices a rewritten selector into the original query
+func replaceSelector(query string, original, replacement promql.Selector) string {
+	return query[:original.Start] + replacement.String() + query[original.End:]
+}
+
+// withoutMatcher returns a copy of the selector without the matcher at index i
+func withoutMatcher(sel promql.Selector, i int) promql.Selector {
+	matchers := make([]promql.LabelMatcher, 0, len(sel.Matchers)-1)
+	matchers = append(matchers, sel.Matchers[:i]...)
+	matchers = append(matchers, sel.Matchers[i+1:]...)
+	sel.Matchers = matchers
+	return sel
+}
+
+// withMatcherValue returns a copy of the selector with the matcher at index i set to value
+func withMatcherValue(sel promql.Selector, i int, value string) promql.Selector {
+	matchers := slices.Clone(sel.Matchers)
+	matchers[i].Value = value
+	sel.Matchers = matchers
+	return sel
+}
+
+// flattenPanels returns every panel of a dashboard, including those nested in collapsed rows
+func flattenPanels(dashboard map[string]any) []map[string]any {
+	var result []map[string]any
+
+	panels, _ := dashboard["panels"].([]any)
+	for _, panelRaw := range panels {
+		panel, ok := panelRaw.(map[string]any)
+		if !ok {
+			continue
+		}
+		result = append(result, panel)
+
+		if nested, ok := panel["panels"].([]any); ok {
+			for _, nestedRaw := range nested {
+				if nestedPanel, ok := nestedRaw.(map[string]any); ok {
+					result = append(result, nestedPanel)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// templateVariablePattern matches $var, ${var}, ${var:format} and [[var]] references
+var templateVariablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_]+)(?::[^}]*)?\}|\[\[([A-Za-z0-9_]+)\]\]|\$([A-Za-z0-9_]+)`)
+
+// equalityMatcherPattern matches the = and != label matchers of a query
+var equalityMatcherPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*\s*)(!?=)(\s*"[^"]*")`)
+
+// resolveTemplateVariables substitutes Grafana built-in and dashboard
+// variables so a target can be executed directly against Prometheus, and
+// returns the names of the variables the dashboard does not define. Equality
+// matchers on a variable that selects several values, or all of them, become
+// regex matchers so the ".*" or "a|b" substituted matches. References that
+// start with a digit, such as the "$1" capture references of label_replace,
+// are left alone.
+func resolveTemplateVariables(expr string, dashboard map[string]any, rangeDuration time.Duration) (string, []string) {
+	multiValued := map[string]bool{}
+	values := map[string]string{
+		"__rate_interval": "5m",
+		"__interval":      "1m",
+		"__interval_ms":   "60000",
+		"__range":         fmt.Sprintf("%ds", int(rangeDuration.Seconds())),
+		"__range_s":       fmt.Sprintf("%d", int(rangeDuration.Seconds())),
+		"__range_ms":      fmt.Sprintf("%d", rangeDuration.Milliseconds()),
+	}
+
+	if templating, ok := dashboard["templating"].(map[string]any); ok {
+		list, _ := templating["list"].([]any)
+		for _, varRaw := range list {
+			variable, ok := varRaw.(map[string]any)
+			if !ok {
+				continue
+			}
+			name := getStringOrDefault(variable, "name", "")
+			if name == "" {
+				continue
+			}
+			values[name], multiValued[name] = currentVariableValue(variable)
+		}
+	}
+
+	expr = equalityMatcherPattern.ReplaceAllStringFunc(expr, func(match string) string {
+		sub := equalityMatcherPattern.FindStringSubmatch(match)
+		for _, reference := range templateVariablePattern.FindAllStringSubmatch(sub[3], -1) {
+			if multiValued[reference[1]+reference[2]+reference[3]] {
+				operator := "=~"
+				if sub[2] == "!=" {
+					operator = "!~"
+				}
+				return sub[1] + operator + sub[3]
+			}
+		}
+		return match
+	})
+
+	var undefined []string
+	resolved := templateVariablePattern.ReplaceAllStringFunc(expr, func(match string) string {
+		sub := templateVariablePattern.FindStringSubmatch(match)
+		name := sub[1] + sub[2] + sub[3]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if name[0] < '0' || name[0] > '9' {
+			if !slices.Contains(undefined, name) {
+				undefined = append(undefined, name)
+			}
+		}
+		return match
+	})
+	return resolved, undefined
+}
+
+// currentVariableValue returns the selected value of a template variable as
+// a regex-friendly string, and whether it selects several values or all of
+// them
+func currentVariableValue(variable map[string]any) (string, bool) {
+	current, ok := variable["current"].(map[string]any)
+	if !ok {
+		if query, ok := variable["query"].(string); ok && variable["type"] == "constant" {
+			return query, false
+		}
+		return ".*", true
+	}
+
+	switch value := current["value"].(type) {
+	case string:
+		if value == "" || value == "$__all" {
+			return ".*", true
+		}
+		return value, false
+	case []any:
+		parts := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				if s == "$__all" {
+					return ".*", true
+				}
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 0 {
+			return ".*", true
+		}
+		return strings.Join(parts, "|"), len(parts) > 1
+	}
+
+	return ".*", true
+}
+
+// queryStep picks a range-query resolution that keeps results to a few hundred points
+func queryStep(start, end time.Time) time.Duration {
+	step := end.Sub(start) / 250
+	if step < 15*time.Second {
+		return 15 * time.Second
+	}
+	return step.Truncate(time.Second)
+}
+
+// firstN returns at most n items of a slice
+func firstN(values []string, n int) []string {
+	if len(values) <= n {
+		return values
+	}
+	return values[:n]
+}
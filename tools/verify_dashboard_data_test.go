@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+	promql "github.com/inference-gateway/grafana-agent/internal/promql"
+	promqlfakes "github.com/inference-gateway/grafana-agent/internal/promql/promqlfakes"
+)
+
+func TestNewVerifyDashboardDataTool(t *testing.T) {
+	tool := NewVerifyDashboardDataTool(zap.NewNop(), &mockGrafanaService{}, &promqlfakes.FakePromQL{}, &config.GrafanaConfig{})
+
+	if tool == nil {
+		t.Error("Expected non-nil tool")
+	}
+}
+
+func TestVerifyDashboardDataHandler_MissingArguments(t *testing.T) {
+	tool := &VerifyDashboardDataTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    &mockGrafanaService{},
+		promql:        &promqlfakes.FakePromQL{},
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	if _, err := tool.VerifyDashboardDataHandler(context.Background(), map[string]any{"prometheus_url": "http://prom"}); err == nil {
+		t.Error("Expected error for missing dashboard_uid")
+	}
+	if _, err := tool.VerifyDashboardDataHandler(context.Background(), map[string]any{"dashboard_uid": "abc"}); err == nil {
+		t.Error("Expected error for missing prometheus_url")
+	}
+
+	tool.grafanaConfig = &config.GrafanaConfig{URL: "http://grafana.test"}
+	_, err := tool.VerifyDashboardDataHandler(context.Background(), map[string]any{"dashboard_uid": "abc", "prometheus_url": "http://prom"})
+	if err == nil || !strings.Contains(err.Error(), "GRAFANA_API_KEY") {
+		t.Errorf("Expected API key error, got %v", err)
+	}
+}
+
+func TestVerifyDashboardDataHandler_ReportsEmptyPanels(t *testing.T) {
+	mockGrafana := &mockGrafanaService{
+		getDashboardFunc: func(ctx context.Context, uid, grafanaURL, apiKey string) (*grafana.Dashboard, error) {
+			return &grafana.Dashboard{Dashboard: map[string]any{
+				"title": "Checkout",
+				"time":  map[string]any{"from": "now-1h", "to": "now"},
+				"panels": []any{
+					map[string]any{
+						"id":      float64(1),
+						"title":   "Requests",
+						"targets": []any{map[string]any{"refId": "A", "expr": "sum(rate(http_requests_total[$__rate_interval]))"}},
+					},
+					map[string]any{
+						"id":      float64(2),
+						"title":   "Errors",
+						"targets": []any{map[string]any{"refId": "A", "expr": `sum(rate(http_requests_total{job="chekout"}[5m]))`}},
+					},
+					map[string]any{
+						"id":      float64(3),
+						"title":   "Latency",
+						"targets": []any{map[string]any{"refId": "A", "expr": "rate(http_request_duraton_seconds_sum[5m])"}},
+					},
+					map[string]any{"id": float64(4), "title": "Notes", "type": "text"},
+					map[string]any{
+						"id":      float64(5),
+						"title":   "Saturation",
+						"targets": []any{map[string]any{"refId": "A", "expr": `up{cluster="$cluster"}`}},
+					},
+				},
+			}}, nil
+		},
+	}
+
+	fakePromQL := &promqlfakes.FakePromQL{}
+	fakePromQL.QueryRangeStub = func(ctx context.Context, url, query string, start, end time.Time, step time.Duration) ([]promql.Series, error) {
+		switch query {
+		case "sum(rate(http_requests_total[5m]))",
+			`sum(rate(http_requests_total{job="checkout"}[5m]))`,
+			"rate(http_request_duration_seconds_sum[5m])":
+			return []promql.Series{{Labels: map[string]string{}}}, nil
+		}
+		return nil, nil
+	}
+	fakePromQL.GetLabelValuesStub = func(ctx context.Context, url, label string, matchers []string) ([]string, error) {
+		switch label {
+		case "__name__":
+			return []string{"http_requests_total", "http_request_duration_seconds_sum"}, nil
+		case "job":
+			return []string{"checkout", "payments"}, nil
+		}
+		return nil, nil
+	}
+
+	tool := &VerifyDashboardDataTool{
+		logger:        zap.NewNop(),
+		grafanaSvc:    mockGrafana,
+		promql:        fakePromQL,
+		grafanaConfig: &config.GrafanaConfig{URL: "http://grafana.test", APIKey: "test-key"},
+	}
+
+	result, err := tool.VerifyDashboardDataHandler(context.Background(), map[string]any{
+		"dashboard_uid":  "checkout",
+		"prometheus_url": "http://prometheus.test:9090",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response VerifyDashboardDataResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Expected valid JSON result, got error: %v", err)
+	}
+
+	if response.TotalPanels != 3 {
+		t.Errorf("Expected 3 panels with queries, got %d", response.TotalPanels)
+	}
+	if response.PanelsWithData != 1 {
+		t.Errorf("Expected 1 panel with data, got %d", response.PanelsWithData)
+	}
+	if response.EmptyPanelCount != 2 {
+		t.Fatalf("Expected 2 empty panels, got %d", response.EmptyPanelCount)
+	}
+
+	if len(response.UnverifiablePanels) != 1 || response.UnverifiablePanels[0].Title != "Saturation" ||
+		!strings.Contains(response.UnverifiablePanels[0].Targets[0].Unverifiable, "$cluster") {
+		t.Errorf("Expected the panel with an undefined variable reported unverifiable, got %+v", response.UnverifiablePanels)
+	}
+
+	errorsPanel := response.EmptyPanels[0]
+	if errorsPanel.Title != "Errors" || len(errorsPanel.Targets[0].Suggestions) == 0 {
+		t.Fatalf("Expected suggestions for the Errors panel, got %+v", errorsPanel)
+	}
+	fix := errorsPanel.Targets[0].Suggestions[0]
+	if fix.Query != `sum(rate(http_requests_total{job="checkout"}[5m]))` || !fix.Verified {
+		t.Errorf("Expected verified job typo fix, got %+v", fix)
+	}
+
+	latencyFix := response.EmptyPanels[1].Targets[0].Suggestions[0]
+	if latencyFix.Query != "rate(http_request_duration_seconds_sum[5m])" || !latencyFix.Verified {
+		t.Errorf("Expected verified metric name fix, got %+v", latencyFix)
+	}
+}
+
+func TestResolveTemplateVariables(t *testing.T) {
+	dashboard := map[string]any{
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{"name": "job", "current": map[string]any{"value": "api"}},
+				map[string]any{"name": "instance", "current": map[string]any{"value": []any{"a", "b"}}},
+				map[string]any{"name": "env", "current": map[string]any{"value": "$__all"}},
+				map[string]any{"name": "pod", "current": map[string]any{"value": []any{"pod-1"}}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		want      string
+		undefined []string
+	}{
+		{
+			name: "built-in and dashboard variables",
+			expr: `rate(x{job="$job", instance=~"${instance:regex}", env=~"[[env]]"}[$__rate_interval])`,
+			want: `rate(x{job="api", instance=~"a|b", env=~".*"}[5m])`,
+		},
+		{
+			name: "capture references are left alone",
+			expr: `label_replace(up{job="$job"}, "host", "$1", "instance", "(.*):.*")`,
+			want: `label_replace(up{job="api"}, "host", "$1", "instance", "(.*):.*")`,
+		},
+		{
+			name: "equality matchers on several values become regex matchers",
+			expr: `up{env="$env", instance != "${instance}", pod="$pod", job="$job"}`,
+			want: `up{env=~".*", instance !~ "a|b", pod="pod-1", job="api"}`,
+		},
+		{
+			name:      "undefined variables",
+			expr:      `up{cluster="$cluster", job="$job"}`,
+			want:      `up{cluster="$cluster", job="api"}`,
+			undefined: []string{"cluster"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, undefined := resolveTemplateVariables(tt.expr, dashboard, time.Hour)
+			if got != tt.want {
+				t.Errorf("resolveTemplateVariables() = %s, want %s", got, tt.want)
+			}
+			if strings.Join(undefined, ",") != strings.Join(tt.undefined, ",") {
+				t.Errorf("Expected undefined variables %v, got %v", tt.undefined, undefined)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	zap "go.uber.org/zap"
+
+	server "github.com/inference-gateway/adk/server"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	deploy "github.com/inference-gateway/grafana-agent/internal/deploy"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+// VerifyDatasourceTool struct holds the tool with services
+type VerifyDatasourceTool struct {
+	logger        *zap.Logger
+	grafanaSvc    grafana.Grafana
+	grafanaConfig *config.GrafanaConfig
+}
+
+// NewVerifyDatasourceTool creates a new verify_datasource tool
+func NewVerifyDatasourceTool(logger *zap.Logger, grafanaSvc grafana.Grafana, grafanaConfig *config.GrafanaConfig) server.Tool {
+	tool := &VerifyDatasourceTool{
+		logger:        logger,
+		grafanaSvc:    grafanaSvc,
+		grafanaConfig: grafanaConfig,
+	}
+	return newValidatedTool(
+		"verify_datasource",
+		"Checks that a Grafana datasource works by running its health check and a trivial query, and explains any misconfiguration",
+		map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"datasource": map[string]any{
+					"description": "UID or name of the datasource to verify",
+					"type":        "string",
+				},
+				"grafana_url": map[string]any{
+					"description": "Grafana server URL (user provides in prompt or uses config default)",
+					"type":        "string",
+				},
+				"org_id": map[string]any{
+					"description": "Optional Grafana organization ID to work in instead of the token's current organization (see list_grafana_orgs)",
+					"type":        "integer",
+					"minimum":     1,
+				},
+			},
+			"required": []string{"datasource"},
+		},
+		tool.VerifyDatasourceHandler,
+	)
+}
+
+// DatasourceQueryCheck reports the trivial query run against a datasource
+type DatasourceQueryCheck struct {
+	Query  map[string]any `json:"query,omitempty"`
+	Frames int            `json:"frames"`
+	Error  string         `json:"error,omitempty"`
+	// Skipped explains why no query was run
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// VerifyDatasourceResponse represents the outcome of a datasource verification
+type VerifyDatasourceResponse struct {
+	GrafanaURL string                    `json:"grafana_url"`
+	Datasource grafana.Datasource        `json:"datasource"`
+	Healthy    bool                      `json:"healthy"`
+	Health     *grafana.DatasourceHealth `json:"health"`
+	Query      DatasourceQueryCheck      `json:"query"`
+	// Problems explains each failed check in terms of the likely misconfiguration
+	Problems []string `json:"problems,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// VerifyDatasourceHandler handles the verify_datasource tool execution
+func (t *VerifyDatasourceTool) VerifyDatasourceHandler(ctx context.Context, args map[string]any) (string, error) {
+	span := startToolSpan(ctx, "verify_datasource")
+	defer span.End()
+
+	name, _ := args["datasource"].(string)
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("datasource is required and must be a string")
+	}
+	grafanaURL, _ := args["grafana_url"].(string)
+
+	deployer := deploy.NewDeployer(t.logger, t.grafanaSvc, nil, t.grafanaConfig, deploy.Options{})
+	target, err := deployer.ResolveReadTarget(grafanaURL)
+	if err != nil {
+		return "", err
+	}
+
+	datasource, err := t.grafanaSvc.GetDatasource(ctx, strings.TrimSpace(name), target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+
+	response := VerifyDatasourceResponse{
+		GrafanaURL: target.GrafanaURL,
+		Datasource: *datasource,
+	}
+
+	health, err := t.grafanaSvc.CheckDatasourceHealth(ctx, datasource.UID, target.GrafanaURL, target.APIKey)
+	if err != nil {
+		return "", err
+	}
+	response.Health = health
+	if health.Status == grafana.HealthError {
+		response.Problems = append(response.Problems, diagnoseDatasource(datasource, "health check", health.Message))
+	}
+
+	query := trivialQuery(datasource.Type)
+	if query == nil {
+		response.Query.Skipped = fmt.Sprintf("no trivial query is known for %s datasources", datasource.Type)
+	} else {
+		response.Query.Query = query
+		frames, err := t.grafanaSvc.QueryDatasource(ctx, *datasource, query, target.GrafanaURL, target.APIKey)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
+			response.Query.Error = err.Error()
+			response.Problems = append(response.Problems, diagnoseDatasource(datasource, "query", err.Error()))
+		}
+		response.Query.Frames = frames
+	}
+
+	response.Healthy = len(response.Problems) == 0
+	if response.Healthy {
+		response.Message = fmt.Sprintf("Datasource %s (%s) is working", datasource.Name, datasource.Type)
+	} else {
+		response.Message = fmt.Sprintf("Datasource %s (%s) is misconfigured: %s", datasource.Name, datasource.Type, strings.Join(response.Problems, "; "))
+	}
+
+	t.logger.Debug("verified datasource",
+		zap.String("uid", datasource.UID),
+		zap.String("type", datasource.Type),
+		zap.Bool("healthy", response.Healthy))
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(jsonBytes), nil
+}
+
+// trivialQuery returns a cheap query proving the datasource answers queries,
+// or nil for datasource types without one
+func trivialQuery(datasourceType string) map[string]any {
+	switch datasourceType {
+	case "prometheus":
+		return map[string]any{"expr": "vector(1)", "instant": true, "range": false}
+	case "loki":
+		return map[string]any{"expr": "vector(1)", "queryType": "instant"}
+	case "tempo":
+		return map[string]any{"queryType": "traceql", "query": "{}", "limit": 1}
+	}
+	return nil
+}
+
+// diagnoseDatasource turns a failed check into the most likely
+// misconfiguration of the datasource
+func diagnoseDatasource(datasource *grafana.Datasource, check, message string) string {
+	msg := strings.ToLower(message)
+
+	var cause string
+	switch {
+	case strings.Contains(msg, "connection refused"):
+		cause = fmt.Sprintf("Grafana cannot connect to %s - check the datasource URL and that the backend is running", datasource.URL)
+	case strings.Contains(msg, "no such host"):
+		cause = fmt.Sprintf("the host of %s does not resolve from Grafana - check the datasource URL", datasource.URL)
+	case strings.Contains(msg, "x509") || strings.Contains(msg, "certificate"):
+		cause = "TLS verification failed - configure the CA certificate of the backend or enable skip TLS verify"
+	case strings.Contains(msg, "401") || strings.Contains(msg, "403") || strings.Contains(msg, "unauthorized") ||
+		strings.Contains(msg, "forbidden") || strings.Contains(msg, "authentication"):
+		cause = "the backend rejected Grafana's credentials - check the datasource basic auth, token or custom headers"
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		cause = fmt.Sprintf("%s did not answer in time - check network access from Grafana and the datasource timeout", datasource.URL)
+	case strings.Contains(msg, "404") || strings.Contains(msg, "not found"):
+		cause = fmt.Sprintf("%s does not serve the %s API - check the URL path (e.g. a missing /prometheus or /api/prom suffix)", datasource.URL, datasource.Type)
+	default:
+		cause = message
+	}
+	return fmt.Sprintf("%s failed: %s", check, cause)
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	zap "go.uber.org/zap"
+
+	config "github.com/inference-gateway/grafana-agent/config"
+	grafana "github.com/inference-gateway/grafana-agent/internal/grafana"
+)
+
+func newVerifyDatasourceTool(mock *mockGrafanaService) *VerifyDatasourceTool {
+	return &VerifyDatasourceTool{
+		logger:     zap.NewNop(),
+		grafanaSvc: mock,
+		grafanaConfig: &config.GrafanaConfig{
+			URL:    "http://grafana.test",
+			APIKey: "test-key",
+		},
+	}
+}
+
+func runVerifyDatasource(t *testing.T, tool *VerifyDatasourceTool, name string) VerifyDatasourceResponse {
+	t.Helper()
+
+	result, err := tool.VerifyDatasourceHandler(context.Background(), map[string]any{"datasource": name})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var response VerifyDatasourceResponse
+	if err := json.Unmarshal([]byte(result), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response
+}
+
+func TestVerifyDatasourceHandler(t *testing.T) {
+	datasources := []grafana.Datasource{
+		{UID: "prom", Name: "Prometheus", Type: "prometheus", URL: "http://prometheus:9090"},
+		{UID: "broken", Name: "Broken", Type: "prometheus", URL: "http://prometheus:9999"},
+		{UID: "pg", Name: "Postgres", Type: "grafana-postgresql-datasource", URL: "postgres:5432"},
+	}
+
+	t.Run("healthy datasource by name", func(t *testing.T) {
+		var queried map[string]any
+		mock := &mockGrafanaService{
+			datasources: datasources,
+			queryDatasourceFunc: func(ctx context.Context, datasource grafana.Datasource, query map[string]any) (int, error) {
+				queried = query
+				return 1, nil
+			},
+		}
+
+		response := runVerifyDatasource(t, newVerifyDatasourceTool(mock), "Prometheus")
+		if !response.Healthy || len(response.Problems) != 0 {
+			t.Errorf("expected a healthy datasource, got %+v", response)
+		}
+		if queried["expr"] != "vector(1)" || response.Query.Frames != 1 {
+			t.Errorf("expected a trivial PromQL query, got %v", queried)
+		}
+	})
+
+	t.Run("misconfigured datasource is diagnosed", func(t *testing.T) {
+		mock := &mockGrafanaService{
+			datasources: datasources,
+			datasourceHealth: map[string]*grafana.DatasourceHealth{
+				"broken": {Status: grafana.HealthError, Message: `Post "http://prometheus:9999/api/v1/query": dial tcp 10.0.0.1:9999: connect: connection refused`},
+			},
+			queryDatasourceFunc: func(ctx context.Context, datasource grafana.Datasource, query map[string]any) (int, error) {
+				return 0, errors.New("datasource query failed: connect: connection refused")
+			},
+		}
+
+		response := runVerifyDatasource(t, newVerifyDatasourceTool(mock), "broken")
+		if response.Healthy {
+			t.Fatal("expected an unhealthy datasource")
+		}
+		if len(response.Problems) != 2 || !strings.Contains(response.Problems[0], "cannot connect to http://prometheus:9999") {
+			t.Errorf("expected a connection diagnosis, got %v", response.Problems)
+		}
+		if response.Query.Error == "" {
+			t.Error("expected the query error to be reported")
+		}
+	})
+
+	t.Run("unknown datasource types skip the query", func(t *testing.T) {
+		mock := &mockGrafanaService{datasources: datasources}
+
+		response := runVerifyDatasource(t, newVerifyDatasourceTool(mock), "pg")
+		if !response.Healthy || response.Query.Skipped == "" {
+			t.Errorf("expected a skipped query, got %+v", response.Query)
+		}
+	})
+
+	t.Run("missing datasource", func(t *testing.T) {
+		tool := newVerifyDatasourceTool(&mockGrafanaService{datasources: datasources})
+		_, err := tool.VerifyDatasourceHandler(context.Background(), map[string]any{"datasource": "loki"})
+		if err == nil || toToolError(err).Code != ErrCodeNotFound {
+			t.Errorf("expected not_found, got %v", err)
+		}
+	})
+}
+
+func TestDiagnoseDatasource(t *testing.T) {
+	datasource := &grafana.Datasource{Type: "prometheus", URL: "https://prom.example"}
+
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{message: "dial tcp: lookup prom.example: no such host", expected: "does not resolve"},
+		{message: "x509: certificate signed by unknown authority", expected: "TLS verification failed"},
+		{message: "401 Unauthorized", expected: "rejected Grafana's credentials"},
+		{message: "404 page not found", expected: "/prometheus"},
+		{message: "something odd", expected: "something odd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			diagnosis := diagnoseDatasource(datasource, "health check", tt.message)
+			if !strings.Contains(diagnosis, tt.expected) {
+				t.Errorf("expected %q in %q", tt.expected, diagnosis)
+			}
+		})
+	}
+}